@@ -203,6 +203,13 @@ func (a *App) renderPage(w http.ResponseWriter, r *http.Request, match *router.M
 		for i := len(layouts) - 1; i >= 0; i-- {
 			result = layouts[i](ctx, result)
 		}
+
+		// Populate <head> from the route's Meta function, if any.
+		if match.MetaHandler != nil && result != nil {
+			var head render.PageData
+			match.MetaHandler(ctx, match.Params).ApplyTo(&head)
+			result = render.ApplyHead(result, head)
+		}
 		return nil
 	})
 
@@ -271,9 +278,16 @@ func (a *App) renderPage(w http.ResponseWriter, r *http.Request, match *router.M
 		return
 	}
 
-	// Render to HTML
+	// Render to HTML. ErrorBoundary components without a fallback render
+	// the router's error page.
 	renderer := render.NewRenderer(render.RendererConfig{
 		Pretty: a.config.DevMode,
+		ErrorPage: func(err error) *vdom.VNode {
+			if a.router.ErrorPage() == nil {
+				return nil
+			}
+			return a.router.ErrorPage()(ctx, err)
+		},
 	})
 
 	html, err := renderer.RenderToString(result)
@@ -465,6 +479,21 @@ func (a *App) Page(path string, handler PageHandler, layouts ...LayoutHandler) {
 	}
 }
 
+// Meta registers the metadata handler for a page path.
+// The handler runs after the page's middleware; its result populates
+// <title>, meta and canonical link tags during SSR and is applied to the
+// document head during client-side navigation.
+//
+//	func ShowMeta(ctx vango.Ctx, p ShowParams) vango.PageMeta {
+//	    return vango.PageMeta{Title: "Project " + strconv.Itoa(p.ID)}
+//	}
+//
+//	app.Page("/projects/:id", projects.ShowPage)
+//	app.Meta("/projects/:id", projects.ShowMeta)
+func (a *App) Meta(path string, handler MetaHandler) {
+	a.router.Meta(path, wrapMetaHandler(handler))
+}
+
 // API registers an API handler for the given HTTP method and path.
 // API handlers return JSON responses.
 //
@@ -135,3 +135,48 @@ func TestAppPage_ErrorPageUsesLayoutsAndStatus(t *testing.T) {
 		t.Fatalf("layout order unexpected: root=%d inner=%d error=%d", rootIdx, innerIdx, errorIdx)
 	}
 }
+
+func TestAppMeta_RendersIntoLayoutHead(t *testing.T) {
+	type showParams struct {
+		ID string `param:"id"`
+	}
+
+	app := New(DefaultConfig())
+	app.Layout("/", func(ctx Ctx, children Slot) *VNode {
+		return vdom.Html(
+			vdom.Head(vdom.Title(vdom.Text("Layout Title"))),
+			vdom.Body(children),
+		)
+	})
+	app.Page("/projects/:id", func(ctx Ctx) *VNode {
+		return vdom.Text("project page")
+	})
+	app.Meta("/projects/:id", func(ctx Ctx, p showParams) PageMeta {
+		return PageMeta{
+			Title:       "Project " + p.ID,
+			Description: "Details for project " + p.ID,
+			Canonical:   "https://example.com/projects/" + p.ID,
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/projects/42", nil)
+	rr := httptest.NewRecorder()
+	app.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, "Layout Title") || !strings.Contains(body, ">Project 42</title>") {
+		t.Fatalf("expected page title to replace layout title, got %q", body)
+	}
+	if !strings.Contains(body, `content="Details for project 42"`) {
+		t.Fatalf("expected description meta tag, got %q", body)
+	}
+	if !strings.Contains(body, `href="https://example.com/projects/42"`) {
+		t.Fatalf("expected canonical link, got %q", body)
+	}
+	if headEnd := strings.Index(body, "</head>"); headEnd == -1 || strings.Index(body, "Details for project") > headEnd {
+		t.Fatalf("expected meta tags inside <head>, got %q", body)
+	}
+}
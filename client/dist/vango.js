@@ -3,21 +3,28 @@ var Vango = (() => {
   var __getOwnPropDesc = Object.getOwnPropertyDescriptor;
   var __getOwnPropNames = Object.getOwnPropertyNames;
   var __hasOwnProp = Object.prototype.hasOwnProperty;
-  var __name = (target, value) => __defProp(target, "name", { value, configurable: true });
+  var __name = (target, value) => __defProp(target, "name", {
+    value,
+    configurable: true
+  });
   var __export = (target, all) => {
-    for (var name in all)
-      __defProp(target, name, { get: all[name], enumerable: true });
+    for (var name in all) __defProp(target, name, {
+      get: all[name],
+      enumerable: true
+    });
   };
   var __copyProps = (to, from, except, desc) => {
     if (from && typeof from === "object" || typeof from === "function") {
-      for (let key of __getOwnPropNames(from))
-        if (!__hasOwnProp.call(to, key) && key !== except)
-          __defProp(to, key, { get: () => from[key], enumerable: !(desc = __getOwnPropDesc(from, key)) || desc.enumerable });
+      for (let key of __getOwnPropNames(from)) if (!__hasOwnProp.call(to, key) && key !== except) __defProp(to, key, {
+        get: () => from[key],
+        enumerable: !(desc = __getOwnPropDesc(from, key)) || desc.enumerable
+      });
     }
     return to;
   };
-  var __toCommonJS = (mod) => __copyProps(__defProp({}, "__esModule", { value: true }), mod);
-
+  var __toCommonJS = mod => __copyProps(__defProp({}, "__esModule", {
+    value: true
+  }), mod);
   // src/index.js
   var src_exports = {};
   __export(src_exports, {
@@ -27,8 +34,15 @@ var Vango = (() => {
     VangoClient: () => VangoClient,
     default: () => src_default
   });
-
   // src/utils.js
+  function hidToInt(hid) {
+    return parseInt(hid.slice(1), 10);
+  }
+  __name(hidToInt, "hidToInt");
+  function intToHid(n) {
+    return 'h' + n;
+  }
+  __name(intToHid, "intToHid");
   function concat(arrays) {
     let totalLength = 0;
     for (let i = 0; i < arrays.length; i++) {
@@ -43,101 +57,492 @@ var Vango = (() => {
     return result;
   }
   __name(concat, "concat");
-
+  function debounce(fn, delay) {
+    let timer = null;
+    return function (...args) {
+      if (timer) clearTimeout(timer);
+      timer = setTimeout(() => {
+        timer = null;
+        fn.apply(this, args);
+      }, delay);
+    };
+  }
+  __name(debounce, "debounce");
+  function throttle(fn, limit) {
+    let inThrottle = false;
+    return function (...args) {
+      if (!inThrottle) {
+        fn.apply(this, args);
+        inThrottle = true;
+        setTimeout(() => {
+          inThrottle = false;
+        }, limit);
+      }
+    };
+  }
+  __name(throttle, "throttle");
+  function formValueString(value) {
+    if (typeof File !== 'undefined' && value instanceof File) {
+      return value.name;
+    }
+    return String(value != null ? value : '');
+  }
+  __name(formValueString, "formValueString");
+  // src/inflate.js
+  const WINDOW_SIZE = 32768;
+  const LENGTH_BASE = [3, 4, 5, 6, 7, 8, 9, 10, 11, 13, 15, 17, 19, 23, 27, 31, 35, 43, 51, 59, 67, 83, 99, 115, 131, 163, 195, 227, 258];
+  const LENGTH_EXTRA = [0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 5, 5, 0];
+  const DIST_BASE = [1, 2, 3, 4, 5, 7, 9, 13, 17, 25, 33, 49, 65, 97, 129, 193, 257, 385, 513, 769, 1025, 1537, 2049, 3073, 4097, 6145, 8193, 12289, 16385, 24577];
+  const DIST_EXTRA = [0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 8, 8, 9, 9, 10, 10, 11, 11, 12, 12, 13, 13];
+  const CLEN_ORDER = [16, 17, 18, 0, 8, 7, 9, 6, 10, 5, 11, 4, 12, 3, 13, 2, 14, 1, 15];
+  function buildHuffman(lengths) {
+    const counts = new Uint16Array(16);
+    for (let i = 0; i < lengths.length; i++) {
+      counts[lengths[i]]++;
+    }
+    counts[0] = 0;
+    const offsets = new Uint16Array(16);
+    for (let len = 1; len < 16; len++) {
+      offsets[len] = offsets[len - 1] + counts[len - 1];
+    }
+    const symbols = new Uint16Array(lengths.length);
+    for (let i = 0; i < lengths.length; i++) {
+      if (lengths[i] !== 0) {
+        symbols[offsets[lengths[i]]++] = i;
+      }
+    }
+    return {
+      counts,
+      symbols
+    };
+  }
+  __name(buildHuffman, "buildHuffman");
+  let fixedTables = null;
+  function getFixedTables() {
+    if (!fixedTables) {
+      const lit = new Uint8Array(288);
+      lit.fill(8, 0, 144);
+      lit.fill(9, 144, 256);
+      lit.fill(7, 256, 280);
+      lit.fill(8, 280, 288);
+      const dist = new Uint8Array(30).fill(5);
+      fixedTables = {
+        lit: buildHuffman(lit),
+        dist: buildHuffman(dist)
+      };
+    }
+    return fixedTables;
+  }
+  __name(getFixedTables, "getFixedTables");
+  class Inflater {
+    constructor() {
+      this.history = new Uint8Array(0);
+    }
+    inflate(input, rawLength) {
+      this.input = input;
+      this.pos = 0;
+      this.bitBuf = 0;
+      this.bitCnt = 0;
+      const histLen = this.history.length;
+      this.out = new Uint8Array(histLen + rawLength);
+      this.out.set(this.history);
+      this.outPos = histLen;
+      while (this.pos < input.length) {
+        const final = this._bits(1);
+        const type = this._bits(2);
+        switch (type) {
+          case 0:
+            this._stored();
+            break;
+          case 1:
+            {
+              const fixed = getFixedTables();
+              this._codes(fixed.lit, fixed.dist);
+              break;
+            }
+          case 2:
+            this._dynamic();
+            break;
+          default:
+            throw new Error('Inflate: invalid block type');
+        }
+        if (final) {
+          break;
+        }
+      }
+      if (this.outPos !== this.out.length) {
+        throw new Error('Inflate: decompressed length mismatch');
+      }
+      const out = this.out;
+      this.history = out.slice(Math.max(0, out.length - WINDOW_SIZE));
+      this.input = null;
+      this.out = null;
+      return out.subarray(histLen);
+    }
+    _bits(n) {
+      let buf = this.bitBuf;
+      while (this.bitCnt < n) {
+        if (this.pos >= this.input.length) {
+          throw new Error('Inflate: unexpected end of input');
+        }
+        buf |= this.input[this.pos++] << this.bitCnt;
+        this.bitCnt += 8;
+      }
+      this.bitBuf = buf >>> n;
+      this.bitCnt -= n;
+      return buf & (1 << n) - 1;
+    }
+    _decode(table) {
+      let code = 0;
+      let first = 0;
+      let index = 0;
+      for (let len = 1; len < 16; len++) {
+        code |= this._bits(1);
+        const count = table.counts[len];
+        if (code - count < first) {
+          return table.symbols[index + (code - first)];
+        }
+        index += count;
+        first = first + count << 1;
+        code <<= 1;
+      }
+      throw new Error('Inflate: invalid Huffman code');
+    }
+    _put(byte) {
+      if (this.outPos >= this.out.length) {
+        throw new Error('Inflate: output exceeds declared length');
+      }
+      this.out[this.outPos++] = byte;
+    }
+    _stored() {
+      this.bitBuf = 0;
+      this.bitCnt = 0;
+      if (this.pos + 4 > this.input.length) {
+        throw new Error('Inflate: unexpected end of input');
+      }
+      const input = this.input;
+      const len = input[this.pos] | input[this.pos + 1] << 8;
+      const nlen = input[this.pos + 2] | input[this.pos + 3] << 8;
+      this.pos += 4;
+      if (len !== (~nlen & 0xFFFF)) {
+        throw new Error('Inflate: stored block length mismatch');
+      }
+      if (this.pos + len > input.length) {
+        throw new Error('Inflate: unexpected end of input');
+      }
+      if (this.outPos + len > this.out.length) {
+        throw new Error('Inflate: output exceeds declared length');
+      }
+      this.out.set(input.subarray(this.pos, this.pos + len), this.outPos);
+      this.pos += len;
+      this.outPos += len;
+    }
+    _dynamic() {
+      const nlen = this._bits(5) + 257;
+      const ndist = this._bits(5) + 1;
+      const ncode = this._bits(4) + 4;
+      if (nlen > 286 || ndist > 30) {
+        throw new Error('Inflate: invalid code counts');
+      }
+      const clens = new Uint8Array(19);
+      for (let i = 0; i < ncode; i++) {
+        clens[CLEN_ORDER[i]] = this._bits(3);
+      }
+      const clenTable = buildHuffman(clens);
+      const lengths = new Uint8Array(nlen + ndist);
+      let i = 0;
+      while (i < nlen + ndist) {
+        const sym = this._decode(clenTable);
+        if (sym < 16) {
+          lengths[i++] = sym;
+          continue;
+        }
+        let value = 0;
+        let repeat;
+        if (sym === 16) {
+          if (i === 0) {
+            throw new Error('Inflate: repeat with no previous length');
+          }
+          value = lengths[i - 1];
+          repeat = 3 + this._bits(2);
+        } else if (sym === 17) {
+          repeat = 3 + this._bits(3);
+        } else {
+          repeat = 11 + this._bits(7);
+        }
+        if (i + repeat > nlen + ndist) {
+          throw new Error('Inflate: too many code lengths');
+        }
+        lengths.fill(value, i, i + repeat);
+        i += repeat;
+      }
+      this._codes(buildHuffman(lengths.subarray(0, nlen)), buildHuffman(lengths.subarray(nlen)));
+    }
+    _codes(litTable, distTable) {
+      for (;;) {
+        const sym = this._decode(litTable);
+        if (sym < 256) {
+          this._put(sym);
+          continue;
+        }
+        if (sym === 256) {
+          return;
+        }
+        const li = sym - 257;
+        if (li >= LENGTH_BASE.length) {
+          throw new Error('Inflate: invalid length code');
+        }
+        const len = LENGTH_BASE[li] + this._bits(LENGTH_EXTRA[li]);
+        const di = this._decode(distTable);
+        if (di >= DIST_BASE.length) {
+          throw new Error('Inflate: invalid distance code');
+        }
+        const dist = DIST_BASE[di] + this._bits(DIST_EXTRA[di]);
+        if (dist > this.outPos) {
+          throw new Error('Inflate: distance too far back');
+        }
+        if (this.outPos + len > this.out.length) {
+          throw new Error('Inflate: output exceeds declared length');
+        }
+        const out = this.out;
+        let from = this.outPos - dist;
+        for (let k = 0; k < len; k++) {
+          out[this.outPos++] = out[from++];
+        }
+      }
+    }
+  }
+  __name(Inflater, "Inflater");
   // src/codec.js
-  var EventType = {
-    // Mouse events (0x01-0x07)
-    CLICK: 1,
-    DBLCLICK: 2,
-    MOUSEDOWN: 3,
-    MOUSEUP: 4,
-    MOUSEMOVE: 5,
-    MOUSEENTER: 6,
-    MOUSELEAVE: 7,
-    // Form events (0x10-0x14)
-    INPUT: 16,
-    CHANGE: 17,
-    SUBMIT: 18,
-    FOCUS: 19,
-    BLUR: 20,
-    // Keyboard events (0x20-0x22)
-    KEYDOWN: 32,
-    KEYUP: 33,
-    KEYPRESS: 34,
-    // Scroll/Resize events (0x30-0x31)
-    SCROLL: 48,
-    RESIZE: 49,
-    // Touch events (0x40-0x42)
-    TOUCHSTART: 64,
-    TOUCHMOVE: 65,
-    TOUCHEND: 66,
-    // Drag events (0x50-0x52)
-    DRAGSTART: 80,
-    DRAGEND: 81,
-    DROP: 82,
-    // Special events (0x60+)
-    HOOK: 96,
-    NAVIGATE: 112,
-    CUSTOM: 255
+  const EventType = {
+    CLICK: 0x01,
+    DBLCLICK: 0x02,
+    MOUSEDOWN: 0x03,
+    MOUSEUP: 0x04,
+    MOUSEMOVE: 0x05,
+    MOUSEENTER: 0x06,
+    MOUSELEAVE: 0x07,
+    CONTEXTMENU: 0x09,
+    INPUT: 0x10,
+    CHANGE: 0x11,
+    SUBMIT: 0x12,
+    FOCUS: 0x13,
+    BLUR: 0x14,
+    KEYDOWN: 0x20,
+    KEYUP: 0x21,
+    KEYPRESS: 0x22,
+    SCROLL: 0x30,
+    RESIZE: 0x31,
+    TOUCHSTART: 0x40,
+    TOUCHMOVE: 0x41,
+    TOUCHEND: 0x42,
+    DRAGSTART: 0x50,
+    DRAGEND: 0x51,
+    DROP: 0x52,
+    DRAGENTER: 0x5B,
+    DRAGOVER: 0x5C,
+    DRAGLEAVE: 0x5D,
+    DRAG: 0x5E,
+    HOOK: 0x60,
+    ISLAND: 0x61,
+    NAVIGATE: 0x70,
+    POINTERDOWN: 0x80,
+    POINTERUP: 0x81,
+    POINTERMOVE: 0x82,
+    POINTERENTER: 0x83,
+    POINTERLEAVE: 0x84,
+    POINTERCANCEL: 0x85,
+    COPY: 0x90,
+    CUT: 0x91,
+    PASTE: 0x92,
+    TOGGLE: 0x98,
+    PLAY: 0xA0,
+    PAUSE: 0xA1,
+    ENDED: 0xA2,
+    TIMEUPDATE: 0xA3,
+    SEEKING: 0xA4,
+    SEEKED: 0xA5,
+    VOLUMECHANGE: 0xA6,
+    RATECHANGE: 0xA7,
+    DURATIONCHANGE: 0xA8,
+    LOADEDMETADATA: 0xA9,
+    LOADEDDATA: 0xAA,
+    CANPLAY: 0xAB,
+    CANPLAYTHROUGH: 0xAC,
+    WAITING: 0xAD,
+    PLAYING: 0xAE,
+    VISIBILITYCHANGE: 0xB0,
+    ONLINE: 0xB1,
+    OFFLINE: 0xB2,
+    BEFOREUNLOAD: 0xB3,
+    CUSTOM: 0xFF
+  };
+  const PatchType = {
+    SET_TEXT: 0x01,
+    SET_ATTR: 0x02,
+    REMOVE_ATTR: 0x03,
+    INSERT_NODE: 0x04,
+    REMOVE_NODE: 0x05,
+    MOVE_NODE: 0x06,
+    REPLACE_NODE: 0x07,
+    SET_VALUE: 0x08,
+    SET_CHECKED: 0x09,
+    SET_SELECTED: 0x0A,
+    FOCUS: 0x0B,
+    BLUR: 0x0C,
+    SCROLL_TO: 0x0D,
+    ADD_CLASS: 0x10,
+    REMOVE_CLASS: 0x11,
+    TOGGLE_CLASS: 0x12,
+    SET_STYLE: 0x13,
+    REMOVE_STYLE: 0x14,
+    SET_DATA: 0x15,
+    DISPATCH: 0x20,
+    URL_PUSH: 0x30,
+    URL_REPLACE: 0x31,
+    NAV_PUSH: 0x32,
+    NAV_REPLACE: 0x33,
+    SET_TITLE: 0x34,
+    SET_META: 0x35,
+    SET_LINK: 0x36,
+    ISLAND_MESSAGE: 0x40
+  };
+  const KeyMod = {
+    CTRL: 0x01,
+    SHIFT: 0x02,
+    ALT: 0x04,
+    META: 0x08
+  };
+  const Codec = {
+    NONE: 0x00,
+    DEFLATE: 0x01
+  };
+  const SupportedCodecs = [Codec.DEFLATE];
+  const ProtocolVersion = {
+    major: 2,
+    minor: 2
+  };
+  const LegacyVersion = {
+    major: 2,
+    minor: 0
+  };
+  function versionAtLeast(version, major, minor) {
+    return version.major > major || version.major === major && version.minor >= minor;
+  }
+  __name(versionAtLeast, "versionAtLeast");
+  const FrameFlags = {
+    COMPRESSED: 0x01,
+    FINAL: 0x04,
+    FRAGMENT: 0x10
   };
-  var PatchType = {
-    SET_TEXT: 1,
-    SET_ATTR: 2,
-    REMOVE_ATTR: 3,
-    INSERT_NODE: 4,
-    REMOVE_NODE: 5,
-    MOVE_NODE: 6,
-    REPLACE_NODE: 7,
-    SET_VALUE: 8,
-    SET_CHECKED: 9,
-    SET_SELECTED: 10,
-    FOCUS: 11,
-    BLUR: 12,
-    SCROLL_TO: 13,
-    ADD_CLASS: 16,
-    REMOVE_CLASS: 17,
-    TOGGLE_CLASS: 18,
-    SET_STYLE: 19,
-    REMOVE_STYLE: 20,
-    SET_DATA: 21,
-    DISPATCH: 32,
-    // NOTE: EVAL (0x21) has been REMOVED for security. Server never sends it.
-    // URL operations (Phase 12: URLParam 2.0)
-    URL_PUSH: 48,
-    URL_REPLACE: 49,
-    // Navigation operations (full route navigation)
-    NAV_PUSH: 50,
-    NAV_REPLACE: 51
+  const ServerFlagCompression = 0x0001;
+  const ServerFlagBinaryBlobs = 0x0002;
+  const BlobOp = {
+    START: 0x01,
+    CHUNK: 0x02,
+    END: 0x03,
+    CANCEL: 0x04
   };
-  var KeyMod = {
-    CTRL: 1,
-    SHIFT: 2,
-    ALT: 4,
-    META: 8
+  const BlobStatus = {
+    PROGRESS: 0x00,
+    DONE: 0x01,
+    ERROR: 0x02
   };
-  var VNodeType = {
-    ELEMENT: 1,
-    TEXT: 2,
-    FRAGMENT: 3
+  const VNodeType = {
+    ELEMENT: 0x01,
+    TEXT: 0x02,
+    FRAGMENT: 0x03,
+    PORTAL: 0x06
   };
-  var HookValueType = {
-    NULL: 0,
-    BOOL: 1,
-    INT: 2,
-    FLOAT: 3,
-    STRING: 4,
-    ARRAY: 5,
-    OBJECT: 6
+  const HookValueType = {
+    NULL: 0x00,
+    BOOL: 0x01,
+    INT: 0x02,
+    FLOAT: 0x03,
+    STRING: 0x04,
+    ARRAY: 0x05,
+    OBJECT: 0x06
   };
-  var _BinaryCodec = class _BinaryCodec {
+  const MaxVarintLen = 10;
+  const DefaultMaxAllocation = 4 * 1024 * 1024;
+  const HardMaxAllocation = 16 * 1024 * 1024;
+  const MaxCollectionCount = 100000;
+  const MaxVNodeDepth = 256;
+  const MaxPatchDepth = 128;
+  const MaxHookDepth = 64;
+  const MaxFramePayload = 0xFFFF;
+  const DefaultMaxMessageSize = DefaultMaxAllocation;
+  class FrameReassembler {
+    constructor(maxSize = DefaultMaxMessageSize) {
+      this.maxSize = maxSize;
+      this.partial = new Map();
+      this.buffered = 0;
+    }
+    add(frameType, flags, payload) {
+      const partial = this.partial.get(frameType);
+      if (!(flags & FrameFlags.FRAGMENT)) {
+        if (partial) {
+          this._discard(frameType);
+          throw new Error('Protocol decode: unfragmented frame inside a fragmented message');
+        }
+        return {
+          flags,
+          payload
+        };
+      }
+      const messageFlags = flags & ~(FrameFlags.FRAGMENT | FrameFlags.FINAL);
+      let message = partial;
+      if (!message) {
+        message = {
+          flags: messageFlags,
+          chunks: [],
+          length: 0
+        };
+        this.partial.set(frameType, message);
+      } else if (message.flags !== messageFlags) {
+        this._discard(frameType);
+        throw new Error('Protocol decode: fragment flags do not match message');
+      }
+      if (this.buffered + payload.length > this.maxSize) {
+        this._discard(frameType);
+        throw new Error('Protocol decode: reassembled message too large');
+      }
+      message.chunks.push(payload);
+      message.length += payload.length;
+      this.buffered += payload.length;
+      if (!(flags & FrameFlags.FINAL)) {
+        return null;
+      }
+      this._discard(frameType);
+      const out = new Uint8Array(message.length);
+      let offset = 0;
+      for (const chunk of message.chunks) {
+        out.set(chunk, offset);
+        offset += chunk.length;
+      }
+      return {
+        flags: message.flags,
+        payload: out
+      };
+    }
+    reset() {
+      this.partial.clear();
+      this.buffered = 0;
+    }
+    _discard(frameType) {
+      const message = this.partial.get(frameType);
+      if (message) {
+        this.buffered -= message.length;
+        this.partial.delete(frameType);
+      }
+    }
+  }
+  __name(FrameReassembler, "FrameReassembler");
+  class BinaryCodec {
     constructor() {
       this.textEncoder = new TextEncoder();
       this.textDecoder = new TextDecoder();
     }
-    /**
-     * Encode event to binary buffer
-     * Format: [seq][type][hid][payload...]
-     */
     encodeEvent(seq, type, hid, data = null) {
       const parts = [];
       parts.push(this.encodeUvarint(seq));
@@ -146,7 +551,8 @@ var Vango = (() => {
       switch (type) {
         case EventType.INPUT:
         case EventType.CHANGE:
-          parts.push(this.encodeString((data == null ? void 0 : data.value) || ""));
+        case EventType.VISIBILITYCHANGE:
+          parts.push(this.encodeString((data == null ? void 0 : data.value) || ''));
           break;
         case EventType.SUBMIT:
           this.encodeFormData(parts, data);
@@ -159,8 +565,42 @@ var Vango = (() => {
         case EventType.MOUSEDOWN:
         case EventType.MOUSEUP:
         case EventType.MOUSEMOVE:
+        case EventType.CONTEXTMENU:
           this.encodeMouseEvent(parts, data);
           break;
+        case EventType.POINTERDOWN:
+        case EventType.POINTERUP:
+        case EventType.POINTERMOVE:
+        case EventType.POINTERENTER:
+        case EventType.POINTERLEAVE:
+        case EventType.POINTERCANCEL:
+          this.encodePointerEvent(parts, data);
+          break;
+        case EventType.PLAY:
+        case EventType.PAUSE:
+        case EventType.ENDED:
+        case EventType.TIMEUPDATE:
+        case EventType.SEEKING:
+        case EventType.SEEKED:
+        case EventType.VOLUMECHANGE:
+        case EventType.RATECHANGE:
+        case EventType.DURATIONCHANGE:
+        case EventType.LOADEDMETADATA:
+        case EventType.LOADEDDATA:
+        case EventType.CANPLAY:
+        case EventType.CANPLAYTHROUGH:
+        case EventType.WAITING:
+        case EventType.PLAYING:
+          this.encodeMediaEvent(parts, data);
+          break;
+        case EventType.COPY:
+        case EventType.CUT:
+        case EventType.PASTE:
+          this.encodeClipboardEvent(parts, data);
+          break;
+        case EventType.TOGGLE:
+          parts.push(new Uint8Array([(data == null ? void 0 : data.open) ? 1 : 0]));
+          break;
         case EventType.SCROLL:
           this.encodeScrollEvent(parts, data);
           break;
@@ -175,17 +615,24 @@ var Vango = (() => {
         case EventType.DRAGSTART:
         case EventType.DRAGEND:
         case EventType.DROP:
+        case EventType.DRAGENTER:
+        case EventType.DRAGOVER:
+        case EventType.DRAGLEAVE:
+        case EventType.DRAG:
           this.encodeDragEvent(parts, data);
           break;
         case EventType.HOOK:
           this.encodeHookEvent(parts, data);
           break;
+        case EventType.ISLAND:
+          this.encodeHookData(parts, (data == null ? void 0 : data.data) || {});
+          break;
         case EventType.NAVIGATE:
-          parts.push(this.encodeString((data == null ? void 0 : data.path) || ""));
+          parts.push(this.encodeString((data == null ? void 0 : data.path) || ''));
           parts.push(new Uint8Array([(data == null ? void 0 : data.replace) ? 1 : 0]));
           break;
         case EventType.CUSTOM:
-          parts.push(this.encodeString((data == null ? void 0 : data.name) || ""));
+          parts.push(this.encodeString((data == null ? void 0 : data.name) || ''));
           parts.push(this.encodeLenBytes((data == null ? void 0 : data.data) || new Uint8Array(0)));
           break;
         case EventType.CLICK:
@@ -199,291 +646,546 @@ var Vango = (() => {
       }
       return concat(parts);
     }
-    /**
-     * Decode patches from binary buffer
-     * Format: [seq][count][patch...]
-     */
     decodePatches(buffer) {
+      return this._decodePatchesWithDepth(buffer, 0);
+    }
+    _decodePatchesWithDepth(buffer, depth) {
+      if (depth > MaxPatchDepth) {
+        throw new Error('Protocol decode: patch depth exceeded');
+      }
       let offset = 0;
-      const { value: seq, bytesRead: seqBytes } = this.decodeUvarint(buffer, offset);
+      const {
+        value: seq,
+        bytesRead: seqBytes
+      } = this.decodeUvarint(buffer, offset);
       offset += seqBytes;
-      const { value: count, bytesRead: countBytes } = this.decodeUvarint(buffer, offset);
+      const {
+        value: count,
+        bytesRead: countBytes
+      } = this.decodeCollectionCount(buffer, offset);
       offset += countBytes;
       const patches = [];
       for (let i = 0; i < count; i++) {
-        const { patch, bytesRead } = this.decodePatch(buffer, offset);
+        const {
+          patch,
+          bytesRead
+        } = this.decodePatch(buffer, offset, depth + 1);
         patches.push(patch);
         offset += bytesRead;
       }
-      return { seq, patches };
+      return {
+        seq,
+        patches
+      };
     }
-    /**
-     * Decode single patch
-     */
-    decodePatch(buffer, offset) {
+    decodePatch(buffer, offset, depth) {
+      if (depth > MaxPatchDepth) {
+        throw new Error('Protocol decode: patch depth exceeded');
+      }
       const startOffset = offset;
       const patch = {};
+      this._ensureAvailable(buffer, offset, 1, 'patch type');
       patch.type = buffer[offset++];
-      const { value: hid, bytesRead: hidBytes } = this.decodeString(buffer, offset);
+      const {
+        value: hid,
+        bytesRead: hidBytes
+      } = this.decodeString(buffer, offset);
       patch.hid = hid;
       offset += hidBytes;
       switch (patch.type) {
         case PatchType.SET_TEXT:
-        case PatchType.SET_VALUE: {
-          const { value, bytesRead } = this.decodeString(buffer, offset);
-          patch.value = value;
-          offset += bytesRead;
-          break;
-        }
+        case PatchType.SET_VALUE:
+          {
+            const {
+              value,
+              bytesRead
+            } = this.decodeString(buffer, offset);
+            patch.value = value;
+            offset += bytesRead;
+            break;
+          }
         case PatchType.SET_ATTR:
         case PatchType.SET_STYLE:
-        case PatchType.SET_DATA: {
-          const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
-          offset += keyBytes;
-          const { value: val, bytesRead: valBytes } = this.decodeString(buffer, offset);
-          offset += valBytes;
-          patch.key = key;
-          patch.value = val;
-          break;
-        }
+        case PatchType.SET_DATA:
+          {
+            const {
+              value: key,
+              bytesRead: keyBytes
+            } = this.decodeString(buffer, offset);
+            offset += keyBytes;
+            const {
+              value: val,
+              bytesRead: valBytes
+            } = this.decodeString(buffer, offset);
+            offset += valBytes;
+            patch.key = key;
+            patch.value = val;
+            break;
+          }
         case PatchType.REMOVE_ATTR:
-        case PatchType.REMOVE_STYLE: {
-          const { value, bytesRead } = this.decodeString(buffer, offset);
-          patch.key = value;
-          offset += bytesRead;
-          break;
-        }
+        case PatchType.REMOVE_STYLE:
+          {
+            const {
+              value,
+              bytesRead
+            } = this.decodeString(buffer, offset);
+            patch.key = value;
+            offset += bytesRead;
+            break;
+          }
         case PatchType.ADD_CLASS:
         case PatchType.REMOVE_CLASS:
-        case PatchType.TOGGLE_CLASS: {
-          const { value, bytesRead } = this.decodeString(buffer, offset);
-          patch.className = value;
-          offset += bytesRead;
-          break;
-        }
-        case PatchType.INSERT_NODE: {
-          const { value: parentID, bytesRead: parentBytes } = this.decodeString(buffer, offset);
-          offset += parentBytes;
-          const { value: index, bytesRead: indexBytes } = this.decodeUvarint(buffer, offset);
-          offset += indexBytes;
-          const { vnode, bytesRead: vnodeBytes } = this.decodeVNode(buffer, offset);
-          offset += vnodeBytes;
-          patch.parentID = parentID;
-          patch.index = index;
-          patch.vnode = vnode;
-          break;
-        }
+        case PatchType.TOGGLE_CLASS:
+          {
+            const {
+              value,
+              bytesRead
+            } = this.decodeString(buffer, offset);
+            patch.className = value;
+            offset += bytesRead;
+            break;
+          }
+        case PatchType.INSERT_NODE:
+          {
+            const {
+              value: parentID,
+              bytesRead: parentBytes
+            } = this.decodeString(buffer, offset);
+            offset += parentBytes;
+            const {
+              value: index,
+              bytesRead: indexBytes
+            } = this.decodeUvarint(buffer, offset);
+            offset += indexBytes;
+            const {
+              vnode,
+              bytesRead: vnodeBytes
+            } = this.decodeVNode(buffer, offset, 0);
+            offset += vnodeBytes;
+            patch.parentID = parentID;
+            patch.index = index;
+            patch.vnode = vnode;
+            break;
+          }
         case PatchType.REMOVE_NODE:
           break;
-        case PatchType.MOVE_NODE: {
-          const { value: parentID, bytesRead: parentBytes } = this.decodeString(buffer, offset);
-          offset += parentBytes;
-          const { value: index, bytesRead: indexBytes } = this.decodeUvarint(buffer, offset);
-          offset += indexBytes;
-          patch.parentID = parentID;
-          patch.index = index;
-          break;
-        }
-        case PatchType.REPLACE_NODE: {
-          const { vnode, bytesRead: vnodeBytes } = this.decodeVNode(buffer, offset);
-          offset += vnodeBytes;
-          patch.vnode = vnode;
-          break;
-        }
+        case PatchType.MOVE_NODE:
+          {
+            const {
+              value: parentID,
+              bytesRead: parentBytes
+            } = this.decodeString(buffer, offset);
+            offset += parentBytes;
+            const {
+              value: index,
+              bytesRead: indexBytes
+            } = this.decodeUvarint(buffer, offset);
+            offset += indexBytes;
+            patch.parentID = parentID;
+            patch.index = index;
+            break;
+          }
+        case PatchType.REPLACE_NODE:
+          {
+            const {
+              vnode,
+              bytesRead: vnodeBytes
+            } = this.decodeVNode(buffer, offset, 0);
+            offset += vnodeBytes;
+            patch.vnode = vnode;
+            break;
+          }
         case PatchType.SET_CHECKED:
-        case PatchType.SET_SELECTED: {
-          patch.value = buffer[offset++] === 1;
-          break;
-        }
+        case PatchType.SET_SELECTED:
+          {
+            this._ensureAvailable(buffer, offset, 1, 'boolean');
+            patch.value = buffer[offset++] === 1;
+            break;
+          }
         case PatchType.FOCUS:
         case PatchType.BLUR:
           break;
-        case PatchType.SCROLL_TO: {
-          const { value: x, bytesRead: xBytes } = this.decodeSvarint(buffer, offset);
-          offset += xBytes;
-          const { value: y, bytesRead: yBytes } = this.decodeSvarint(buffer, offset);
-          offset += yBytes;
-          patch.x = x;
-          patch.y = y;
-          patch.behavior = buffer[offset++];
-          break;
-        }
-        case PatchType.DISPATCH: {
-          const { value: eventName, bytesRead: nameBytes } = this.decodeString(buffer, offset);
-          offset += nameBytes;
-          const { value: detail, bytesRead: detailBytes } = this.decodeString(buffer, offset);
-          offset += detailBytes;
-          patch.eventName = eventName;
-          patch.detail = detail;
-          break;
-        }
+        case PatchType.SCROLL_TO:
+          {
+            const {
+              value: x,
+              bytesRead: xBytes
+            } = this.decodeSvarint(buffer, offset);
+            offset += xBytes;
+            const {
+              value: y,
+              bytesRead: yBytes
+            } = this.decodeSvarint(buffer, offset);
+            offset += yBytes;
+            patch.x = x;
+            patch.y = y;
+            this._ensureAvailable(buffer, offset, 1, 'scroll behavior');
+            patch.behavior = buffer[offset++];
+            break;
+          }
+        case PatchType.DISPATCH:
+          {
+            const {
+              value: eventName,
+              bytesRead: nameBytes
+            } = this.decodeString(buffer, offset);
+            offset += nameBytes;
+            const {
+              value: detail,
+              bytesRead: detailBytes
+            } = this.decodeString(buffer, offset);
+            offset += detailBytes;
+            patch.eventName = eventName;
+            patch.detail = detail;
+            break;
+          }
         case PatchType.URL_PUSH:
-        case PatchType.URL_REPLACE: {
-          const { value: count, bytesRead: countBytes } = this.decodeUvarint(buffer, offset);
-          offset += countBytes;
-          patch.params = {};
-          for (let i = 0; i < count; i++) {
-            const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
-            offset += keyBytes;
-            const { value, bytesRead: valueBytes } = this.decodeString(buffer, offset);
-            offset += valueBytes;
-            patch.params[key] = value;
+        case PatchType.URL_REPLACE:
+          {
+            const {
+              value: count,
+              bytesRead: countBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += countBytes;
+            patch.params = {};
+            for (let i = 0; i < count; i++) {
+              const {
+                value: key,
+                bytesRead: keyBytes
+              } = this.decodeString(buffer, offset);
+              offset += keyBytes;
+              const {
+                value: value,
+                bytesRead: valueBytes
+              } = this.decodeString(buffer, offset);
+              offset += valueBytes;
+              patch.params[key] = value;
+            }
+            patch.op = patch.type;
+            break;
           }
-          patch.op = patch.type;
-          break;
-        }
         case PatchType.NAV_PUSH:
-        case PatchType.NAV_REPLACE: {
-          const { value: path, bytesRead: pathBytes } = this.decodeString(buffer, offset);
-          offset += pathBytes;
-          patch.path = path;
-          break;
-        }
+        case PatchType.NAV_REPLACE:
+          {
+            const {
+              value: path,
+              bytesRead: pathBytes
+            } = this.decodeString(buffer, offset);
+            offset += pathBytes;
+            patch.path = path;
+            break;
+          }
+        case PatchType.SET_TITLE:
+          {
+            const {
+              value: title,
+              bytesRead: titleBytes
+            } = this.decodeString(buffer, offset);
+            offset += titleBytes;
+            patch.value = title;
+            break;
+          }
+        case PatchType.SET_META:
+          {
+            const {
+              value: attr,
+              bytesRead: attrBytes
+            } = this.decodeString(buffer, offset);
+            offset += attrBytes;
+            const {
+              value: key,
+              bytesRead: keyBytes
+            } = this.decodeString(buffer, offset);
+            offset += keyBytes;
+            const {
+              value: content,
+              bytesRead: contentBytes
+            } = this.decodeString(buffer, offset);
+            offset += contentBytes;
+            patch.attr = attr;
+            patch.key = key;
+            patch.value = content;
+            break;
+          }
+        case PatchType.SET_LINK:
+          {
+            const {
+              value: rel,
+              bytesRead: relBytes
+            } = this.decodeString(buffer, offset);
+            offset += relBytes;
+            const {
+              value: href,
+              bytesRead: hrefBytes
+            } = this.decodeString(buffer, offset);
+            offset += hrefBytes;
+            patch.key = rel;
+            patch.value = href;
+            break;
+          }
+        case PatchType.ISLAND_MESSAGE:
+          {
+            const {
+              value: data,
+              bytesRead: dataBytes
+            } = this.decodeHookData(buffer, offset);
+            offset += dataBytes;
+            patch.data = data;
+            break;
+          }
         default:
-          break;
+          throw new Error(`Protocol decode: unknown patch type ${patch.type}`);
       }
-      return { patch, bytesRead: offset - startOffset };
+      return {
+        patch,
+        bytesRead: offset - startOffset
+      };
     }
-    /**
-     * Decode VNode from buffer
-     */
-    decodeVNode(buffer, offset) {
+    decodeVNode(buffer, offset, depth = 0) {
+      if (depth > MaxVNodeDepth) {
+        throw new Error('Protocol decode: VNode depth exceeded');
+      }
       const startOffset = offset;
       const vnode = {};
+      this._ensureAvailable(buffer, offset, 1, 'vnode type');
       const nodeType = buffer[offset++];
+      if (nodeType === 0xFF) {
+        return {
+          vnode: null,
+          bytesRead: offset - startOffset
+        };
+      }
       switch (nodeType) {
-        case VNodeType.ELEMENT: {
-          vnode.type = "element";
-          const { value: tag, bytesRead: tagBytes } = this.decodeString(buffer, offset);
-          vnode.tag = tag;
-          offset += tagBytes;
-          const { value: hid, bytesRead: hidBytes } = this.decodeString(buffer, offset);
-          vnode.hid = hid || null;
-          offset += hidBytes;
-          const { value: attrCount, bytesRead: attrCountBytes } = this.decodeUvarint(buffer, offset);
-          offset += attrCountBytes;
-          vnode.attrs = {};
-          for (let i = 0; i < attrCount; i++) {
-            const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
-            offset += keyBytes;
-            const { value: val, bytesRead: valBytes } = this.decodeString(buffer, offset);
-            offset += valBytes;
-            vnode.attrs[key] = val;
+        case VNodeType.ELEMENT:
+          {
+            vnode.type = 'element';
+            const {
+              value: tag,
+              bytesRead: tagBytes
+            } = this.decodeString(buffer, offset);
+            vnode.tag = tag;
+            offset += tagBytes;
+            const {
+              value: hid,
+              bytesRead: hidBytes
+            } = this.decodeString(buffer, offset);
+            vnode.hid = hid || null;
+            offset += hidBytes;
+            const {
+              value: attrCount,
+              bytesRead: attrCountBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += attrCountBytes;
+            vnode.attrs = {};
+            for (let i = 0; i < attrCount; i++) {
+              const {
+                value: key,
+                bytesRead: keyBytes
+              } = this.decodeString(buffer, offset);
+              offset += keyBytes;
+              const {
+                value: val,
+                bytesRead: valBytes
+              } = this.decodeString(buffer, offset);
+              offset += valBytes;
+              vnode.attrs[key] = val;
+            }
+            const {
+              value: childCount,
+              bytesRead: childCountBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += childCountBytes;
+            vnode.children = [];
+            for (let i = 0; i < childCount; i++) {
+              const {
+                vnode: child,
+                bytesRead: childBytes
+              } = this.decodeVNode(buffer, offset, depth + 1);
+              vnode.children.push(child);
+              offset += childBytes;
+            }
+            break;
           }
-          const { value: childCount, bytesRead: childCountBytes } = this.decodeUvarint(buffer, offset);
-          offset += childCountBytes;
-          vnode.children = [];
-          for (let i = 0; i < childCount; i++) {
-            const { vnode: child, bytesRead: childBytes } = this.decodeVNode(buffer, offset);
-            vnode.children.push(child);
-            offset += childBytes;
+        case VNodeType.TEXT:
+          {
+            vnode.type = 'text';
+            const {
+              value: text,
+              bytesRead: textBytes
+            } = this.decodeString(buffer, offset);
+            vnode.text = text;
+            offset += textBytes;
+            break;
           }
-          break;
-        }
-        case VNodeType.TEXT: {
-          vnode.type = "text";
-          const { value: text, bytesRead: textBytes } = this.decodeString(buffer, offset);
-          vnode.text = text;
-          offset += textBytes;
-          break;
-        }
-        case VNodeType.FRAGMENT: {
-          vnode.type = "fragment";
-          const { value: childCount, bytesRead: countBytes } = this.decodeUvarint(buffer, offset);
-          offset += countBytes;
-          vnode.children = [];
-          for (let i = 0; i < childCount; i++) {
-            const { vnode: child, bytesRead: childBytes } = this.decodeVNode(buffer, offset);
-            vnode.children.push(child);
-            offset += childBytes;
+        case VNodeType.FRAGMENT:
+          {
+            vnode.type = 'fragment';
+            const {
+              value: childCount,
+              bytesRead: countBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += countBytes;
+            vnode.children = [];
+            for (let i = 0; i < childCount; i++) {
+              const {
+                vnode: child,
+                bytesRead: childBytes
+              } = this.decodeVNode(buffer, offset, depth + 1);
+              vnode.children.push(child);
+              offset += childBytes;
+            }
+            break;
+          }
+        case VNodeType.PORTAL:
+          {
+            vnode.type = 'portal';
+            const {
+              value: target,
+              bytesRead: targetBytes
+            } = this.decodeString(buffer, offset);
+            vnode.target = target;
+            offset += targetBytes;
+            const {
+              value: hid,
+              bytesRead: hidBytes
+            } = this.decodeString(buffer, offset);
+            vnode.hid = hid || null;
+            offset += hidBytes;
+            const {
+              value: childCount,
+              bytesRead: countBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += countBytes;
+            vnode.children = [];
+            for (let i = 0; i < childCount; i++) {
+              const {
+                vnode: child,
+                bytesRead: childBytes
+              } = this.decodeVNode(buffer, offset, depth + 1);
+              vnode.children.push(child);
+              offset += childBytes;
+            }
+            break;
           }
-          break;
-        }
         default:
-          vnode.type = "unknown";
+          throw new Error(`Protocol decode: unknown vnode type ${nodeType}`);
       }
-      return { vnode, bytesRead: offset - startOffset };
+      return {
+        vnode,
+        bytesRead: offset - startOffset
+      };
     }
-    /**
-     * Encode string with length prefix (varint length + UTF-8 bytes)
-     */
     encodeString(str) {
       const bytes = this.textEncoder.encode(str);
       const length = this.encodeUvarint(bytes.length);
       return concat([length, bytes]);
     }
-    /**
-     * Decode string with length prefix
-     */
     decodeString(buffer, offset) {
-      const { value: length, bytesRead: lengthBytes } = this.decodeUvarint(buffer, offset);
-      const strBytes = buffer.slice(offset + lengthBytes, offset + lengthBytes + length);
+      const {
+        value: length,
+        bytesRead: lengthBytes
+      } = this.decodeUvarint(buffer, offset);
+      this._checkAllocation(length, 'string');
+      const start = offset + lengthBytes;
+      const end = start + length;
+      this._ensureAvailable(buffer, start, length, 'string bytes');
+      const strBytes = buffer.slice(start, end);
       const value = this.textDecoder.decode(strBytes);
-      return { value, bytesRead: lengthBytes + length };
+      return {
+        value,
+        bytesRead: lengthBytes + length
+      };
     }
-    /**
-     * Encode unsigned varint (protobuf-style)
-     */
     encodeUvarint(value) {
       const bytes = [];
-      while (value > 127) {
-        bytes.push(value & 127 | 128);
+      while (value > 0x7F) {
+        bytes.push(value & 0x7F | 0x80);
         value >>>= 7;
       }
-      bytes.push(value & 127);
+      bytes.push(value & 0x7F);
       return new Uint8Array(bytes);
     }
-    /**
-     * Decode unsigned varint
-     */
     decodeUvarint(buffer, offset) {
       let value = 0;
       let shift = 0;
       let bytesRead = 0;
       while (true) {
+        if (offset + bytesRead >= buffer.length) {
+          throw new Error('Protocol decode: buffer too short for varint');
+        }
+        if (bytesRead >= MaxVarintLen) {
+          throw new Error('Protocol decode: varint overflow');
+        }
+        if (shift > 53) {
+          throw new Error('Protocol decode: varint exceeds JS safe integer');
+        }
         const byte = buffer[offset + bytesRead];
         bytesRead++;
-        value |= (byte & 127) << shift;
-        if ((byte & 128) === 0) {
+        value += (byte & 0x7F) * Math.pow(2, shift);
+        if (!Number.isSafeInteger(value)) {
+          throw new Error('Protocol decode: varint exceeds JS safe integer');
+        }
+        if ((byte & 0x80) === 0) {
           break;
         }
         shift += 7;
       }
-      return { value, bytesRead };
+      return {
+        value,
+        bytesRead
+      };
     }
-    /**
-     * Encode signed varint using ZigZag encoding
-     */
     encodeSvarint(value) {
       const zigzag = value << 1 ^ value >> 31;
       return this.encodeUvarint(zigzag >>> 0);
     }
-    /**
-     * Decode signed varint using ZigZag encoding
-     */
     decodeSvarint(buffer, offset) {
-      const { value: zigzag, bytesRead } = this.decodeUvarint(buffer, offset);
-      const value = zigzag >>> 1 ^ -(zigzag & 1);
-      return { value, bytesRead };
+      const {
+        value: zigzag,
+        bytesRead
+      } = this.decodeUvarint(buffer, offset);
+      const value = zigzag % 2 === 0 ? zigzag / 2 : -((zigzag + 1) / 2);
+      return {
+        value,
+        bytesRead
+      };
     }
-    /**
-     * Encode length-prefixed bytes
-     */
     encodeLenBytes(bytes) {
       const length = this.encodeUvarint(bytes.length);
       return concat([length, bytes]);
     }
-    /**
-     * Decode length-prefixed bytes
-     */
     decodeLenBytes(buffer, offset) {
-      const { value: length, bytesRead: lengthBytes } = this.decodeUvarint(buffer, offset);
-      const bytes = buffer.slice(offset + lengthBytes, offset + lengthBytes + length);
-      return { value: bytes, bytesRead: lengthBytes + length };
+      const {
+        value: length,
+        bytesRead: lengthBytes
+      } = this.decodeUvarint(buffer, offset);
+      this._checkAllocation(length, 'bytes');
+      const start = offset + lengthBytes;
+      const end = start + length;
+      this._ensureAvailable(buffer, start, length, 'bytes');
+      const bytes = buffer.slice(start, end);
+      return {
+        value: bytes,
+        bytesRead: lengthBytes + length
+      };
+    }
+    decodeCollectionCount(buffer, offset) {
+      const {
+        value: count,
+        bytesRead
+      } = this.decodeUvarint(buffer, offset);
+      if (count > MaxCollectionCount) {
+        throw new Error('Protocol decode: collection count exceeds limit');
+      }
+      const remaining = buffer.length - (offset + bytesRead);
+      if (count > remaining) {
+        throw new Error('Protocol decode: collection count exceeds remaining buffer');
+      }
+      return {
+        value: count,
+        bytesRead
+      };
     }
-    /**
-     * Encode form data map
-     */
     encodeFormData(parts, formData) {
       if (!formData) {
         parts.push(this.encodeUvarint(0));
@@ -492,43 +1194,43 @@ var Vango = (() => {
       const entries = [];
       if (formData instanceof FormData) {
         for (const [key, value] of formData.entries()) {
-          entries.push({ key, value: String(value) });
+          entries.push({
+            key,
+            value: formValueString(value)
+          });
         }
-      } else if (typeof formData === "object") {
+      } else if (typeof formData === 'object') {
         for (const [key, value] of Object.entries(formData)) {
-          entries.push({ key, value: String(value) });
+          const values = Array.isArray(value) ? value : [value];
+          for (const v of values) {
+            entries.push({
+              key,
+              value: formValueString(v)
+            });
+          }
         }
       }
       parts.push(this.encodeUvarint(entries.length));
-      for (const { key, value } of entries) {
+      for (const {
+        key,
+        value
+      } of entries) {
         parts.push(this.encodeString(key));
         parts.push(this.encodeString(value));
       }
     }
-    /**
-     * Encode keyboard event
-     * Per spec section 3.9.3, lines 1091-1103
-     */
     encodeKeyboardEvent(parts, data) {
-      parts.push(this.encodeString((data == null ? void 0 : data.key) || ""));
-      parts.push(this.encodeString((data == null ? void 0 : data.code) || ""));
+      parts.push(this.encodeString((data == null ? void 0 : data.key) || ''));
+      parts.push(this.encodeString((data == null ? void 0 : data.code) || ''));
       let modifiers = 0;
-      if (data == null ? void 0 : data.ctrlKey)
-        modifiers |= KeyMod.CTRL;
-      if (data == null ? void 0 : data.shiftKey)
-        modifiers |= KeyMod.SHIFT;
-      if (data == null ? void 0 : data.altKey)
-        modifiers |= KeyMod.ALT;
-      if (data == null ? void 0 : data.metaKey)
-        modifiers |= KeyMod.META;
+      if (data == null ? void 0 : data.ctrlKey) modifiers |= KeyMod.CTRL;
+      if (data == null ? void 0 : data.shiftKey) modifiers |= KeyMod.SHIFT;
+      if (data == null ? void 0 : data.altKey) modifiers |= KeyMod.ALT;
+      if (data == null ? void 0 : data.metaKey) modifiers |= KeyMod.META;
       parts.push(new Uint8Array([modifiers]));
       parts.push(new Uint8Array([(data == null ? void 0 : data.repeat) ? 1 : 0]));
       parts.push(new Uint8Array([(data == null ? void 0 : data.location) || 0]));
     }
-    /**
-     * Encode mouse event
-     * Per spec section 3.9.3, lines 1059-1075
-     */
     encodeMouseEvent(parts, data) {
       parts.push(this.encodeSvarint((data == null ? void 0 : data.clientX) || 0));
       parts.push(this.encodeSvarint((data == null ? void 0 : data.clientY) || 0));
@@ -539,34 +1241,20 @@ var Vango = (() => {
       parts.push(new Uint8Array([(data == null ? void 0 : data.button) || 0]));
       parts.push(new Uint8Array([(data == null ? void 0 : data.buttons) || 0]));
       let modifiers = 0;
-      if (data == null ? void 0 : data.ctrlKey)
-        modifiers |= KeyMod.CTRL;
-      if (data == null ? void 0 : data.shiftKey)
-        modifiers |= KeyMod.SHIFT;
-      if (data == null ? void 0 : data.altKey)
-        modifiers |= KeyMod.ALT;
-      if (data == null ? void 0 : data.metaKey)
-        modifiers |= KeyMod.META;
+      if (data == null ? void 0 : data.ctrlKey) modifiers |= KeyMod.CTRL;
+      if (data == null ? void 0 : data.shiftKey) modifiers |= KeyMod.SHIFT;
+      if (data == null ? void 0 : data.altKey) modifiers |= KeyMod.ALT;
+      if (data == null ? void 0 : data.metaKey) modifiers |= KeyMod.META;
       parts.push(new Uint8Array([modifiers]));
     }
-    /**
-     * Encode scroll event
-     */
     encodeScrollEvent(parts, data) {
       parts.push(this.encodeSvarint((data == null ? void 0 : data.scrollTop) || 0));
       parts.push(this.encodeSvarint((data == null ? void 0 : data.scrollLeft) || 0));
     }
-    /**
-     * Encode resize event
-     */
     encodeResizeEvent(parts, data) {
       parts.push(this.encodeSvarint((data == null ? void 0 : data.width) || 0));
       parts.push(this.encodeSvarint((data == null ? void 0 : data.height) || 0));
     }
-    /**
-     * Encode touch event
-     * Per spec section 3.9.3, lines 1212-1225
-     */
     encodeTouchEvent(parts, data) {
       const touches = (data == null ? void 0 : data.touches) || [];
       parts.push(this.encodeUvarint(touches.length));
@@ -596,33 +1284,69 @@ var Vango = (() => {
         parts.push(this.encodeSvarint(touch.pageY || 0));
       }
     }
-    /**
-     * Encode drag event
-     */
+    encodePointerEvent(parts, data) {
+      this.encodeMouseEvent(parts, data);
+      parts.push(this.encodeSvarint((data == null ? void 0 : data.pointerId) || 0));
+      parts.push(this.encodeString((data == null ? void 0 : data.pointerType) || ''));
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.pressure) || 0));
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.width) || 0));
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.height) || 0));
+      parts.push(this.encodeSvarint((data == null ? void 0 : data.tiltX) || 0));
+      parts.push(this.encodeSvarint((data == null ? void 0 : data.tiltY) || 0));
+      parts.push(new Uint8Array([(data == null ? void 0 : data.isPrimary) ? 1 : 0]));
+    }
     encodeDragEvent(parts, data) {
       parts.push(this.encodeSvarint((data == null ? void 0 : data.clientX) || 0));
       parts.push(this.encodeSvarint((data == null ? void 0 : data.clientY) || 0));
+      parts.push(this.encodeSvarint((data == null ? void 0 : data.pageX) || 0));
+      parts.push(this.encodeSvarint((data == null ? void 0 : data.pageY) || 0));
       let modifiers = 0;
-      if (data == null ? void 0 : data.ctrlKey)
-        modifiers |= KeyMod.CTRL;
-      if (data == null ? void 0 : data.shiftKey)
-        modifiers |= KeyMod.SHIFT;
-      if (data == null ? void 0 : data.altKey)
-        modifiers |= KeyMod.ALT;
-      if (data == null ? void 0 : data.metaKey)
-        modifiers |= KeyMod.META;
+      if (data == null ? void 0 : data.ctrlKey) modifiers |= KeyMod.CTRL;
+      if (data == null ? void 0 : data.shiftKey) modifiers |= KeyMod.SHIFT;
+      if (data == null ? void 0 : data.altKey) modifiers |= KeyMod.ALT;
+      if (data == null ? void 0 : data.metaKey) modifiers |= KeyMod.META;
       parts.push(new Uint8Array([modifiers]));
+      this.encodeStringList(parts, (data == null ? void 0 : data.types) || []);
+      this.encodeFileList(parts, (data == null ? void 0 : data.files) || []);
+      const entries = Object.entries((data == null ? void 0 : data.data) || {});
+      parts.push(this.encodeUvarint(entries.length));
+      for (const [format, value] of entries) {
+        parts.push(this.encodeString(format));
+        parts.push(this.encodeString(value));
+      }
+    }
+    encodeMediaEvent(parts, data) {
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.currentTime) || 0));
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.duration) || 0));
+      parts.push(new Uint8Array([(data == null ? void 0 : data.paused) ? 1 : 0]));
+      parts.push(new Uint8Array([(data == null ? void 0 : data.ended) ? 1 : 0]));
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.volume) || 0));
+      parts.push(new Uint8Array([(data == null ? void 0 : data.muted) ? 1 : 0]));
+      parts.push(this.encodeFloat64((data == null ? void 0 : data.playbackRate) || 0));
+    }
+    encodeClipboardEvent(parts, data) {
+      parts.push(this.encodeString((data == null ? void 0 : data.text) || ''));
+      this.encodeStringList(parts, (data == null ? void 0 : data.types) || []);
+      this.encodeFileList(parts, (data == null ? void 0 : data.files) || []);
+    }
+    encodeStringList(parts, list) {
+      parts.push(this.encodeUvarint(list.length));
+      for (const s of list) {
+        parts.push(this.encodeString(s));
+      }
+    }
+    encodeFileList(parts, files) {
+      parts.push(this.encodeUvarint(files.length));
+      for (const file of files) {
+        parts.push(this.encodeString(file.name || ''));
+        parts.push(this.encodeString(file.type || ''));
+        parts.push(this.encodeUvarint(file.size || 0));
+      }
     }
-    /**
-     * Encode hook event
-     */
     encodeHookEvent(parts, data) {
-      parts.push(this.encodeString((data == null ? void 0 : data.name) || ""));
+      parts.push(this.encodeString((data == null ? void 0 : data.name) || ''));
       this.encodeHookData(parts, (data == null ? void 0 : data.data) || {});
     }
-    /**
-     * Encode hook data map
-     */
     encodeHookData(parts, data) {
       const entries = Object.entries(data);
       parts.push(this.encodeUvarint(entries.length));
@@ -631,21 +1355,18 @@ var Vango = (() => {
         this.encodeHookValue(parts, value);
       }
     }
-    /**
-     * Encode single hook value
-     */
     encodeHookValue(parts, value) {
-      if (value === null || value === void 0) {
+      if (value === null || value === undefined) {
         parts.push(new Uint8Array([HookValueType.NULL]));
-      } else if (typeof value === "boolean") {
+      } else if (typeof value === 'boolean') {
         parts.push(new Uint8Array([HookValueType.BOOL, value ? 1 : 0]));
-      } else if (typeof value === "number" && Number.isInteger(value)) {
+      } else if (typeof value === 'number' && Number.isInteger(value)) {
         parts.push(new Uint8Array([HookValueType.INT]));
         parts.push(this.encodeSvarint(value));
-      } else if (typeof value === "number") {
+      } else if (typeof value === 'number') {
         parts.push(new Uint8Array([HookValueType.FLOAT]));
         parts.push(this.encodeFloat64(value));
-      } else if (typeof value === "string") {
+      } else if (typeof value === 'string') {
         parts.push(new Uint8Array([HookValueType.STRING]));
         parts.push(this.encodeString(value));
       } else if (Array.isArray(value)) {
@@ -654,7 +1375,7 @@ var Vango = (() => {
         for (const item of value) {
           this.encodeHookValue(parts, item);
         }
-      } else if (typeof value === "object") {
+      } else if (typeof value === 'object') {
         parts.push(new Uint8Array([HookValueType.OBJECT]));
         const entries = Object.entries(value);
         parts.push(this.encodeUvarint(entries.length));
@@ -666,322 +1387,946 @@ var Vango = (() => {
         parts.push(new Uint8Array([HookValueType.NULL]));
       }
     }
-    /**
-     * Encode 64-bit float (little-endian)
-     */
+    decodeHookData(buffer, offset) {
+      const startOffset = offset;
+      const {
+        value: count,
+        bytesRead: countBytes
+      } = this.decodeCollectionCount(buffer, offset);
+      offset += countBytes;
+      const data = {};
+      for (let i = 0; i < count; i++) {
+        const {
+          value: key,
+          bytesRead: keyBytes
+        } = this.decodeString(buffer, offset);
+        offset += keyBytes;
+        const {
+          value,
+          bytesRead
+        } = this.decodeHookValue(buffer, offset, 0);
+        offset += bytesRead;
+        data[key] = value;
+      }
+      return {
+        value: data,
+        bytesRead: offset - startOffset
+      };
+    }
+    decodeHookValue(buffer, offset, depth) {
+      if (depth > MaxHookDepth) {
+        throw new Error('Protocol decode: hook value depth exceeded');
+      }
+      const startOffset = offset;
+      this._ensureAvailable(buffer, offset, 1, 'hook value type');
+      const type = buffer[offset++];
+      switch (type) {
+        case HookValueType.NULL:
+          return {
+            value: null,
+            bytesRead: 1
+          };
+        case HookValueType.BOOL:
+          this._ensureAvailable(buffer, offset, 1, 'hook bool');
+          return {
+            value: buffer[offset] !== 0,
+            bytesRead: 2
+          };
+        case HookValueType.INT:
+          {
+            const {
+              value,
+              bytesRead
+            } = this.decodeSvarint(buffer, offset);
+            return {
+              value,
+              bytesRead: 1 + bytesRead
+            };
+          }
+        case HookValueType.FLOAT:
+          {
+            this._ensureAvailable(buffer, offset, 8, 'hook float');
+            const view = new DataView(buffer.buffer, buffer.byteOffset + offset, 8);
+            return {
+              value: view.getFloat64(0, true),
+              bytesRead: 9
+            };
+          }
+        case HookValueType.STRING:
+          {
+            const {
+              value,
+              bytesRead
+            } = this.decodeString(buffer, offset);
+            return {
+              value,
+              bytesRead: 1 + bytesRead
+            };
+          }
+        case HookValueType.ARRAY:
+          {
+            const {
+              value: count,
+              bytesRead: countBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += countBytes;
+            const arr = [];
+            for (let i = 0; i < count; i++) {
+              const {
+                value,
+                bytesRead
+              } = this.decodeHookValue(buffer, offset, depth + 1);
+              offset += bytesRead;
+              arr.push(value);
+            }
+            return {
+              value: arr,
+              bytesRead: offset - startOffset
+            };
+          }
+        case HookValueType.OBJECT:
+          {
+            const {
+              value: count,
+              bytesRead: countBytes
+            } = this.decodeCollectionCount(buffer, offset);
+            offset += countBytes;
+            const obj = {};
+            for (let i = 0; i < count; i++) {
+              const {
+                value: key,
+                bytesRead: keyBytes
+              } = this.decodeString(buffer, offset);
+              offset += keyBytes;
+              const {
+                value,
+                bytesRead
+              } = this.decodeHookValue(buffer, offset, depth + 1);
+              offset += bytesRead;
+              obj[key] = value;
+            }
+            return {
+              value: obj,
+              bytesRead: offset - startOffset
+            };
+          }
+        default:
+          throw new Error(`Protocol decode: unknown hook value type ${type}`);
+      }
+    }
     encodeFloat64(value) {
       const buffer = new ArrayBuffer(8);
       new DataView(buffer).setFloat64(0, value, true);
       return new Uint8Array(buffer);
     }
-    /**
-     * Encode ClientHello for handshake (raw payload, no frame header)
-     * Format: [major:1][minor:1][csrf:string][sessionID:string][lastSeq:4][viewportW:2][viewportH:2][tzOffset:2]
-     */
     encodeClientHello(options = {}) {
       const parts = [];
-      parts.push(new Uint8Array([2, 0]));
-      parts.push(this.encodeString(options.csrf || ""));
-      parts.push(this.encodeString(options.sessionId || ""));
+      parts.push(new Uint8Array([ProtocolVersion.major, ProtocolVersion.minor]));
+      parts.push(this.encodeString(options.csrf || ''));
+      parts.push(this.encodeString(options.sessionId || ''));
       parts.push(this.encodeUint32(options.lastSeq || 0));
       parts.push(this.encodeUint16(options.viewportW || window.innerWidth));
       parts.push(this.encodeUint16(options.viewportH || window.innerHeight));
-      const tzOffset = (/* @__PURE__ */ new Date()).getTimezoneOffset();
+      const tzOffset = new Date().getTimezoneOffset();
       parts.push(this.encodeInt16(-tzOffset));
+      const codecs = options.codecs || [];
+      const prefValues = options.prefValues || [];
+      const hasEnv = !!options.locale || !!options.prefs || prefValues.length > 0;
+      if (codecs.length > 0 || hasEnv) {
+        parts.push(this.encodeUvarint(codecs.length));
+        parts.push(new Uint8Array(codecs));
+      }
+      if (hasEnv) {
+        parts.push(this.encodeString(options.locale || ''));
+        parts.push(new Uint8Array([options.prefs || 0]));
+      }
+      if (prefValues.length > 0) {
+        parts.push(this.encodePrefValues(prefValues));
+      }
       return concat(parts);
     }
-    /**
-     * Encode ClientHello wrapped in frame header for consistent framing.
-     * Format: [type:1][flags:1][len:2][payload...]
-     *
-     * Per spec: All protocol messages should use the same frame format.
-     */
-    encodeClientHelloFrame(options = {}) {
-      const payload = this.encodeClientHello(options);
-      const frame = new Uint8Array(4 + payload.length);
-      frame[0] = 0;
-      frame[1] = 0;
-      frame[2] = payload.length >> 8 & 255;
-      frame[3] = payload.length & 255;
-      frame.set(payload, 4);
-      return frame;
+    encodeClientEnv(env) {
+      return concat([new Uint8Array([0x41]), this.encodeUint16(env.viewportW), this.encodeUint16(env.viewportH), this.encodeInt16(env.tzOffset), this.encodeString(env.locale || ''), new Uint8Array([env.prefs || 0])]);
     }
-    /**
-     * Decode ServerHello from handshake response
-     * Frame format: [type:1][flags:1][len:2][payload...]
-     * ServerHello payload: [status:1][sessionID:string][nextSeq:4][serverTime:8][flags:2]
-     */
-    decodeServerHello(buffer) {
-      if (buffer.length < 5) {
-        return { error: "Buffer too short" };
-      }
-      const frameType = buffer[0];
-      if (frameType !== 0) {
-        return { error: `Unexpected frame type: ${frameType}` };
+    encodePrefSync(values) {
+      return concat([new Uint8Array([0x42]), this.encodePrefValues(values)]);
+    }
+    encodePrefValues(values) {
+      const parts = [this.encodeUvarint(values.length)];
+      for (const v of values) {
+        parts.push(this.encodeString(v.key));
+        parts.push(this.encodeString(v.value));
+        parts.push(this.encodeUint64(v.updatedAt));
       }
-      let offset = 4;
-      const status = buffer[offset++];
-      const { value: sessionId, bytesRead: sessionBytes } = this.decodeString(buffer, offset);
-      offset += sessionBytes;
+      return concat(parts);
+    }
+    decodePrefSync(buffer, offset = 0) {
+      const {
+        value: count,
+        bytesRead: countLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += countLen;
+      const values = [];
+      for (let i = 0; i < count; i++) {
+        const {
+          value: key,
+          bytesRead: keyLen
+        } = this.decodeString(buffer, offset);
+        offset += keyLen;
+        const {
+          value,
+          bytesRead: valueLen
+        } = this.decodeString(buffer, offset);
+        offset += valueLen;
+        const updatedAt = this.decodeUint64(buffer, offset);
+        offset += 8;
+        values.push({
+          key,
+          value,
+          updatedAt
+        });
+      }
+      return values;
+    }
+    decodeGlobalListen(buffer, offset = 0) {
+      const {
+        value: id,
+        bytesRead: idLen
+      } = this.decodeString(buffer, offset);
+      offset += idLen;
+      const target = buffer[offset++];
+      const {
+        value: event,
+        bytesRead: eventLen
+      } = this.decodeString(buffer, offset);
+      offset += eventLen;
+      const flags = buffer[offset++];
+      const {
+        value: debounce,
+        bytesRead: debounceLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += debounceLen;
+      const {
+        value: throttle,
+        bytesRead: throttleLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += throttleLen;
+      const {
+        value: count,
+        bytesRead: countLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += countLen;
+      const keys = [];
+      for (let i = 0; i < count; i++) {
+        const {
+          value: key,
+          bytesRead: keyLen
+        } = this.decodeString(buffer, offset);
+        offset += keyLen;
+        keys.push(key);
+      }
+      const keyMods = buffer[offset];
+      return {
+        id,
+        target,
+        event,
+        flags,
+        debounce,
+        throttle,
+        keys,
+        keyMods
+      };
+    }
+    decodeHookCall(buffer, offset = 0) {
+      const {
+        value: id,
+        bytesRead: idLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += idLen;
+      const {
+        value: hid,
+        bytesRead: hidLen
+      } = this.decodeString(buffer, offset);
+      offset += hidLen;
+      const {
+        value: method,
+        bytesRead: methodLen
+      } = this.decodeString(buffer, offset);
+      offset += methodLen;
+      const {
+        value: args
+      } = this.decodeHookData(buffer, offset);
+      return {
+        id,
+        hid,
+        method,
+        args
+      };
+    }
+    encodeHookResult(id, result, error = '') {
+      const parts = [new Uint8Array([0x46]), this.encodeUvarint(id), this.encodeString(error)];
+      this.encodeHookValue(parts, result);
+      return concat(parts);
+    }
+    encodeClientHelloFrame(options = {}) {
+      const payload = this.encodeClientHello(options);
+      const frame = new Uint8Array(4 + payload.length);
+      frame[0] = 0x00;
+      frame[1] = 0x00;
+      frame[2] = payload.length >> 8 & 0xFF;
+      frame[3] = payload.length & 0xFF;
+      frame.set(payload, 4);
+      return frame;
+    }
+    decodeServerHello(buffer) {
+      if (buffer.length < 5) {
+        throw new Error('Protocol decode: handshake frame too short');
+      }
+      const frameType = buffer[0];
+      if (frameType !== 0x00) {
+        throw new Error(`Protocol decode: unexpected frame type ${frameType}`);
+      }
+      const length = buffer[2] << 8 | buffer[3];
+      if (length > MaxFramePayload) {
+        throw new Error('Protocol decode: handshake payload exceeds max size');
+      }
+      if (buffer.length !== 4 + length) {
+        throw new Error('Protocol decode: handshake length mismatch');
+      }
+      let offset = 4;
+      this._ensureAvailable(buffer, offset, 1, 'handshake status');
+      const status = buffer[offset++];
+      const {
+        value: sessionId,
+        bytesRead: sessionBytes
+      } = this.decodeString(buffer, offset);
+      offset += sessionBytes;
       const nextSeq = this.decodeUint32(buffer, offset);
       offset += 4;
       const serverTime = this.decodeUint64(buffer, offset);
       offset += 8;
       const flags = this.decodeUint16(buffer, offset);
+      offset += 2;
+      let authReason;
+      let version = LegacyVersion;
+      let minVersion;
+      const readVersion = () => {
+        const v = {
+          major: buffer[offset],
+          minor: buffer[offset + 1]
+        };
+        offset += 2;
+        return v;
+      };
+      if (status === 0x00) {
+        if (offset + 2 <= buffer.length) {
+          version = readVersion();
+        }
+      } else if (status === 0x01 || status === 0x05) {
+        if (offset + 4 <= buffer.length) {
+          minVersion = readVersion();
+          version = readVersion();
+        }
+      } else if (offset < buffer.length) {
+        authReason = buffer[offset];
+      }
       return {
         status,
         sessionId,
         nextSeq,
         serverTime,
         flags,
+        codec: flags & ServerFlagCompression ? flags >> 8 & 0xFF : Codec.NONE,
+        authReason,
+        version,
+        minVersion,
         ok: status === 0
       };
     }
-    /**
-     * Encode uint16 big-endian (matches Go protocol)
-     */
+    encodeFrames(frameType, payload, flags = 0) {
+      if (payload.length <= MaxFramePayload) {
+        return [this._encodeFrame(frameType, flags, payload)];
+      }
+      const frames = [];
+      for (let offset = 0; offset < payload.length; offset += MaxFramePayload) {
+        const end = Math.min(offset + MaxFramePayload, payload.length);
+        let fragmentFlags = flags | FrameFlags.FRAGMENT;
+        if (end === payload.length) {
+          fragmentFlags |= FrameFlags.FINAL;
+        }
+        frames.push(this._encodeFrame(frameType, fragmentFlags, payload.subarray(offset, end)));
+      }
+      return frames;
+    }
+    _encodeFrame(frameType, flags, payload) {
+      const frame = new Uint8Array(4 + payload.length);
+      frame[0] = frameType;
+      frame[1] = flags;
+      frame[2] = payload.length >> 8 & 0xFF;
+      frame[3] = payload.length & 0xFF;
+      frame.set(payload, 4);
+      return frame;
+    }
+    createDecompressor(codec) {
+      switch (codec) {
+        case Codec.NONE:
+          return null;
+        case Codec.DEFLATE:
+          return new Inflater();
+        default:
+          throw new Error(`Protocol decode: unsupported codec ${codec}`);
+      }
+    }
+    decompressPayload(payload, decompressor, maxSize = DefaultMaxMessageSize) {
+      if (!decompressor) {
+        throw new Error('Protocol decode: compressed frame without a negotiated codec');
+      }
+      const {
+        value: rawLength,
+        bytesRead
+      } = this.decodeUvarint(payload, 0);
+      if (rawLength > maxSize) {
+        throw new Error('Protocol decode: decompressed payload exceeds max size');
+      }
+      return decompressor.inflate(payload.subarray(bytesRead), rawLength);
+    }
     encodeUint16(value) {
-      return new Uint8Array([value >> 8 & 255, value & 255]);
+      return new Uint8Array([value >> 8 & 0xFF, value & 0xFF]);
     }
-    /**
-     * Decode uint16 big-endian (matches Go protocol)
-     */
     decodeUint16(buffer, offset) {
+      this._ensureAvailable(buffer, offset, 2, 'uint16');
       return buffer[offset] << 8 | buffer[offset + 1];
     }
-    /**
-     * Encode int16 big-endian (matches Go protocol)
-     */
     encodeInt16(value) {
-      return this.encodeUint16(value & 65535);
+      return this.encodeUint16(value & 0xFFFF);
     }
-    /**
-     * Encode uint32 big-endian (matches Go protocol)
-     */
     encodeUint32(value) {
-      return new Uint8Array([
-        value >> 24 & 255,
-        value >> 16 & 255,
-        value >> 8 & 255,
-        value & 255
-      ]);
-    }
-    /**
-     * Decode uint32 big-endian (matches Go protocol)
-     */
+      return new Uint8Array([value >> 24 & 0xFF, value >> 16 & 0xFF, value >> 8 & 0xFF, value & 0xFF]);
+    }
+    encodeUint64(value) {
+      const high = Math.floor(value / 0x100000000);
+      return concat([this.encodeUint32(high), this.encodeUint32(value - high * 0x100000000)]);
+    }
     decodeUint32(buffer, offset) {
-      return buffer[offset] << 24 | buffer[offset + 1] << 16 | buffer[offset + 2] << 8 | buffer[offset + 3];
+      this._ensureAvailable(buffer, offset, 4, 'uint32');
+      return buffer[offset] * 0x1000000 + (buffer[offset + 1] << 16) + (buffer[offset + 2] << 8) + buffer[offset + 3];
     }
-    /**
-     * Decode uint64 big-endian (returns as Number, may lose precision for large values)
-     * Matches Go protocol encoding.
-     */
     decodeUint64(buffer, offset) {
       const high = this.decodeUint32(buffer, offset);
       const low = this.decodeUint32(buffer, offset + 4);
-      return high * 4294967296 + low;
-    }
-    /**
-     * Encode ACK payload
-     * Format: [lastSeq:varint][window:varint]
-     * Matches vango/pkg/protocol/ack.go
-     */
-    encodeAck(lastSeq, window2 = 100) {
-      return concat([
-        this.encodeUvarint(lastSeq),
-        this.encodeUvarint(window2)
-      ]);
-    }
-    /**
-     * Encode ResyncRequest control payload
-     * Format: [controlType:1][lastSeq:varint]
-     * Matches vango/pkg/protocol/control.go ControlResyncRequest (0x10)
-     */
+      const value = high * 0x100000000 + low;
+      if (!Number.isSafeInteger(value)) {
+        throw new Error('Protocol decode: uint64 exceeds JS safe integer');
+      }
+      return value;
+    }
+    _checkAllocation(length, context) {
+      if (length > HardMaxAllocation) {
+        throw new Error(`Protocol decode: ${context} exceeds hard cap`);
+      }
+      if (length > DefaultMaxAllocation) {
+        throw new Error(`Protocol decode: ${context} exceeds max allocation`);
+      }
+    }
+    _ensureAvailable(buffer, offset, needed, context) {
+      if (offset + needed > buffer.length) {
+        throw new Error(`Protocol decode: buffer too short for ${context}`);
+      }
+    }
+    encodeAck(lastSeq, window = 100) {
+      return concat([this.encodeUvarint(lastSeq), this.encodeUvarint(window)]);
+    }
+    encodeBlob(op, id, fields = {}) {
+      const parts = [new Uint8Array([op]), this.encodeUvarint(id)];
+      switch (op) {
+        case BlobOp.START:
+          parts.push(this.encodeString(fields.hid));
+          parts.push(this.encodeString(fields.filename || ''));
+          parts.push(this.encodeString(fields.type || ''));
+          parts.push(this.encodeUvarint(fields.size));
+          break;
+        case BlobOp.CHUNK:
+          parts.push(this.encodeUvarint(fields.offset));
+          parts.push(this.encodeLenBytes(fields.data));
+          break;
+      }
+      return concat(parts);
+    }
+    decodeBlobAck(buffer, offset = 0) {
+      const {
+        value: id,
+        bytesRead: idLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += idLen;
+      const {
+        value: received,
+        bytesRead: recvLen
+      } = this.decodeUvarint(buffer, offset);
+      offset += recvLen;
+      this._ensureAvailable(buffer, offset, 1, 'blob status');
+      const status = buffer[offset++];
+      const {
+        value: message
+      } = this.decodeString(buffer, offset);
+      return {
+        id,
+        received,
+        status,
+        message
+      };
+    }
     encodeResyncRequest(lastSeq) {
-      return concat([
-        new Uint8Array([16]),
-        // ControlResyncRequest
-        this.encodeUvarint(lastSeq)
-      ]);
+      return concat([new Uint8Array([0x10]), this.encodeUvarint(lastSeq)]);
+    }
+  }
+  __name(BinaryCodec, "BinaryCodec");
+  // src/fallback.js
+  function httpFallbackUrl(wsUrl) {
+    const url = new URL(wsUrl, location.href);
+    url.protocol = url.protocol === 'wss:' ? 'https:' : url.protocol === 'ws:' ? 'http:' : url.protocol;
+    url.pathname = url.pathname.replace(/\/_vango\/[^/]+$/, '/_vango/sse');
+    return url.toString();
+  }
+  __name(httpFallbackUrl, "httpFallbackUrl");
+  function decodeBase64(data) {
+    const binary = atob(data);
+    const bytes = new Uint8Array(binary.length);
+    for (let i = 0; i < binary.length; i++) {
+      bytes[i] = binary.charCodeAt(i);
+    }
+    return bytes.buffer;
+  }
+  __name(decodeBase64, "decodeBase64");
+  class HTTPFallbackSocket {
+    constructor(url) {
+      this.url = url;
+      this.readyState = HTTPFallbackSocket.CONNECTING;
+      this.binaryType = 'arraybuffer';
+      this.connId = null;
+      this.onopen = null;
+      this.onmessage = null;
+      this.onclose = null;
+      this.onerror = null;
+      this.pending = [];
+      this.inflight = false;
+      this.source = new EventSource(url);
+      this.source.addEventListener('conn', e => this._onConn(e));
+      this.source.addEventListener('close', () => this._finish(1000, '', true));
+      this.source.onmessage = e => this._onData(e);
+      this.source.onerror = e => {
+        var _fn;
+        if (this.readyState === HTTPFallbackSocket.CLOSED) return;
+        (_fn = this.onerror) == null ? void 0 : _fn.call(this, e);
+        this._finish(1006, '', false);
+      };
+    }
+    _onConn(e) {
+      var _fn2;
+      this.connId = e.data;
+      this.readyState = HTTPFallbackSocket.OPEN;
+      (_fn2 = this.onopen) == null ? void 0 : _fn2.call(this, {
+        type: 'open'
+      });
+    }
+    _onData(e) {
+      var _fn3;
+      if (this.readyState !== HTTPFallbackSocket.OPEN) return;
+      let data;
+      try {
+        data = decodeBase64(e.data);
+      } catch (_e) {
+        this._finish(1007, 'Invalid frame encoding', false);
+        return;
+      }
+      (_fn3 = this.onmessage) == null ? void 0 : _fn3.call(this, {
+        type: 'message',
+        data
+      });
+    }
+    send(buffer) {
+      if (this.readyState === HTTPFallbackSocket.CONNECTING) {
+        throw new Error('HTTPFallbackSocket is not open');
+      }
+      if (this.readyState !== HTTPFallbackSocket.OPEN) return;
+      this.pending.push(buffer instanceof ArrayBuffer ? new Uint8Array(buffer) : buffer);
+      if (this.pending.length === 1 && !this.inflight) {
+        queueMicrotask(() => this._flush());
+      }
+    }
+    _flush() {
+      if (this.inflight || this.pending.length === 0 || this.readyState !== HTTPFallbackSocket.OPEN) {
+        return;
+      }
+      const frames = this.pending;
+      this.pending = [];
+      let size = 0;
+      for (const f of frames) size += f.length;
+      const body = new Uint8Array(size);
+      let offset = 0;
+      for (const f of frames) {
+        body.set(f, offset);
+        offset += f.length;
+      }
+      this.inflight = true;
+      const url = this.url.replace(/\?.*$/, '') + '?conn=' + encodeURIComponent(this.connId);
+      fetch(url, {
+        method: 'POST',
+        body,
+        credentials: 'same-origin',
+        headers: {
+          'Content-Type': 'application/octet-stream'
+        }
+      }).then(resp => {
+        this.inflight = false;
+        if (!resp.ok) {
+          this._finish(1006, `POST failed: ${resp.status}`, false);
+          return;
+        }
+        this._flush();
+      }, () => {
+        this.inflight = false;
+        this._finish(1006, 'POST failed', false);
+      });
     }
+    close(code = 1000, reason = '') {
+      if (this.readyState === HTTPFallbackSocket.CLOSED) return;
+      this.source.close();
+      this.readyState = HTTPFallbackSocket.CLOSING;
+      setTimeout(() => this._finish(code, reason, true), 0);
+    }
+    _finish(code, reason, wasClean) {
+      var _fn4;
+      if (this.readyState === HTTPFallbackSocket.CLOSED) return;
+      this.readyState = HTTPFallbackSocket.CLOSED;
+      this.source.close();
+      this.pending = [];
+      (_fn4 = this.onclose) == null ? void 0 : _fn4.call(this, {
+        type: 'close',
+        code,
+        reason,
+        wasClean
+      });
+    }
+  }
+  __name(HTTPFallbackSocket, "HTTPFallbackSocket");
+  HTTPFallbackSocket.CLOSED = 3;
+  HTTPFallbackSocket.CLOSING = 2;
+  HTTPFallbackSocket.OPEN = 1;
+  HTTPFallbackSocket.CONNECTING = 0;
+  // src/env.js
+  const ClientPref = {
+    DARK: 0x01,
+    LIGHT: 0x02,
+    REDUCED_MOTION: 0x04
   };
-  __name(_BinaryCodec, "BinaryCodec");
-  var BinaryCodec = _BinaryCodec;
-
+  const MEDIA_QUERIES = ['(prefers-color-scheme: dark)', '(prefers-color-scheme: light)', '(prefers-reduced-motion: reduce)'];
+  function matches(query) {
+    return typeof window.matchMedia === 'function' && window.matchMedia(query).matches;
+  }
+  __name(matches, "matches");
+  function readClientEnv() {
+    let prefs = 0;
+    if (matches(MEDIA_QUERIES[0])) prefs |= ClientPref.DARK;else if (matches(MEDIA_QUERIES[1])) prefs |= ClientPref.LIGHT;
+    if (matches(MEDIA_QUERIES[2])) prefs |= ClientPref.REDUCED_MOTION;
+    return {
+      viewportW: Math.min(window.innerWidth, 0xFFFF),
+      viewportH: Math.min(window.innerHeight, 0xFFFF),
+      tzOffset: -new Date().getTimezoneOffset(),
+      locale: typeof navigator !== 'undefined' && navigator.language || '',
+      prefs
+    };
+  }
+  __name(readClientEnv, "readClientEnv");
+  function sameEnv(a, b) {
+    return !!a && !!b && a.viewportW === b.viewportW && a.viewportH === b.viewportH && a.tzOffset === b.tzOffset && a.locale === b.locale && a.prefs === b.prefs;
+  }
+  __name(sameEnv, "sameEnv");
+  class ClientEnvReporter {
+    constructor(client, options = {}) {
+      var _a;
+      this.client = client;
+      this.debounce = (_a = options.resizeDebounce) != null ? _a : 150;
+      this.last = null;
+      this.timer = null;
+      this.cleanups = [];
+    }
+    attach() {
+      const onResize = () => {
+        clearTimeout(this.timer);
+        this.timer = setTimeout(() => this.report(), this.debounce);
+      };
+      const onChange = () => this.report();
+      window.addEventListener('resize', onResize);
+      window.addEventListener('languagechange', onChange);
+      this.cleanups.push(() => {
+        window.removeEventListener('resize', onResize);
+        window.removeEventListener('languagechange', onChange);
+        clearTimeout(this.timer);
+      });
+      if (typeof window.matchMedia === 'function') {
+        for (const query of MEDIA_QUERIES) {
+          const mql = window.matchMedia(query);
+          if (mql && typeof mql.addEventListener === 'function') {
+            mql.addEventListener('change', onChange);
+            this.cleanups.push(() => mql.removeEventListener('change', onChange));
+          }
+        }
+      }
+    }
+    detach() {
+      for (const cleanup of this.cleanups) cleanup();
+      this.cleanups = [];
+    }
+    markReported(env) {
+      this.last = env;
+    }
+    report() {
+      if (!this.client.connected) return;
+      const env = readClientEnv();
+      if (sameEnv(env, this.last)) return;
+      this.last = env;
+      const payload = this.client.codec.encodeClientEnv(env);
+      for (const frame of this.client.codec.encodeFrames(0x03, payload)) {
+        this.client.wsManager.send(frame);
+      }
+      if (this.client.options.debug) {
+        console.log('[Vango] Reported client env', env);
+      }
+    }
+  }
+  __name(ClientEnvReporter, "ClientEnvReporter");
   // src/websocket.js
-  var _WebSocketManager = class _WebSocketManager {
+  const VersionReloadInterval = 30000;
+  function formatVersion(v) {
+    return `${v.major}.${v.minor}`;
+  }
+  __name(formatVersion, "formatVersion");
+  class WebSocketManager {
     constructor(client, options = {}) {
       this.client = client;
       this.options = {
         reconnect: options.reconnect !== false,
-        reconnectInterval: options.reconnectInterval || 1e3,
-        reconnectMaxInterval: options.reconnectMaxInterval || 3e4,
-        heartbeatInterval: options.heartbeatInterval || 3e4,
+        reconnectInterval: options.reconnectInterval || 1000,
+        reconnectMaxInterval: options.reconnectMaxInterval || 30000,
+        heartbeatInterval: options.heartbeatInterval || 30000,
         ...options
       };
       this.ws = null;
       this.connected = false;
       this.handshakeComplete = false;
       this.sessionId = null;
+      this.lastSeq = 0;
       this.reconnectAttempts = 0;
+      this.reconnectDisabled = false;
       this.heartbeatTimer = null;
       this.messageQueue = [];
+      this.useHTTPFallback = options.transport === 'sse';
+      this.fellBack = false;
+      this.opened = false;
+      const resume = this._loadResumeInfo();
+      if (resume) {
+        this.sessionId = resume.sessionId;
+        this.lastSeq = resume.lastSeq;
+      }
     }
-    /**
-     * Connect to WebSocket server
-     */
     connect(url) {
       if (this.ws) {
         this.ws.close();
       }
       this.url = url;
-      this.ws = new WebSocket(url);
-      this.ws.binaryType = "arraybuffer";
+      this.opened = false;
+      this.ws = this.useHTTPFallback ? new HTTPFallbackSocket(httpFallbackUrl(url)) : new WebSocket(url);
+      this.ws.binaryType = 'arraybuffer';
       this.ws.onopen = () => this._onOpen();
-      this.ws.onclose = (e) => this._onClose(e);
-      this.ws.onerror = (e) => this._onError(e);
-      this.ws.onmessage = (e) => this._onMessage(e);
+      this.ws.onclose = e => this._onClose(e);
+      this.ws.onerror = e => this._onError(e);
+      this.ws.onmessage = e => this._onMessage(e);
     }
-    /**
-     * Handle WebSocket open
-     */
     _onOpen() {
       if (this.client.options.debug) {
-        console.log("[Vango] WebSocket connected");
+        console.log('[Vango] WebSocket connected');
       }
+      this.opened = true;
       this.reconnectAttempts = 0;
       this._sendHandshake();
       this._startHeartbeat();
     }
-    /**
-     * Send binary ClientHello handshake wrapped in frame header.
-     * Per spec: All protocol messages use consistent framing.
-     */
     _sendHandshake() {
+      var _a, _a2;
+      const env = readClientEnv();
       const helloFrame = this.client.codec.encodeClientHelloFrame({
         csrf: this._getCSRFToken(),
-        sessionId: this.sessionId || "",
-        viewportW: window.innerWidth,
-        viewportH: window.innerHeight
+        sessionId: this.sessionId || '',
+        lastSeq: this.lastSeq || this.client.patchSeq || 0,
+        viewportW: env.viewportW,
+        viewportH: env.viewportH,
+        locale: env.locale,
+        prefs: env.prefs,
+        prefValues: ((_a = this.client.prefs) == null ? void 0 : _a.storedValues()) || [],
+        codecs: this.client.options.compression === false ? [] : SupportedCodecs
       });
       this.ws.send(helloFrame);
+      (_a2 = this.client.env) == null ? void 0 : _a2.markReported(env);
       if (this.client.options.debug) {
-        console.log("[Vango] Sent framed ClientHello");
+        console.log('[Vango] Sent framed ClientHello');
       }
     }
-    /**
-     * Get CSRF token from window global or cookie (Double Submit Cookie pattern)
-     */
     _getCSRFToken() {
       if (window.__VANGO_CSRF__) {
         return window.__VANGO_CSRF__;
       }
       const match = document.cookie.match(/(?:^|;\s*)__vango_csrf=([^;]*)/);
-      return match ? decodeURIComponent(match[1]) : "";
+      return match ? decodeURIComponent(match[1]) : '';
     }
-    /**
-     * Handle incoming message
-     */
     _onMessage(event) {
       if (!(event.data instanceof ArrayBuffer)) {
         if (this.client.options.debug) {
-          console.warn("[Vango] Received non-binary message:", event.data);
+          console.warn('[Vango] Received non-binary message:', event.data);
         }
         return;
       }
       const buffer = new Uint8Array(event.data);
       if (!this.handshakeComplete) {
-        const hello = this.client.codec.decodeServerHello(buffer);
-        if (hello.error) {
-          if (this.client.options.debug) {
-            console.error("[Vango] Handshake error:", hello.error);
-          }
-          this.client._onError(new Error(hello.error));
+        let hello;
+        try {
+          hello = this.client.codec.decodeServerHello(buffer);
+        } catch (err) {
+          this.client._handleProtocolError(err, 'handshake');
           return;
         }
         if (!hello.ok) {
           const errorMessages = {
-            1: "Version mismatch",
-            2: "Invalid CSRF token",
-            3: "Session expired",
-            4: "Server busy",
-            5: "Upgrade required",
-            6: "Invalid format",
-            7: "Not authorized",
-            8: "Internal error"
+            0x01: 'Version mismatch',
+            0x02: 'Invalid CSRF token',
+            0x03: 'Session expired',
+            0x04: 'Server busy',
+            0x05: 'Upgrade required',
+            0x06: 'Invalid format',
+            0x07: 'Not authorized',
+            0x08: 'Internal error',
+            0x09: 'Too many active sessions from this IP'
           };
           const msg = errorMessages[hello.status] || `Handshake failed: ${hello.status}`;
-          this.client._onError(new Error(msg));
+          const err = new Error(hello.minVersion ? `${msg}: client speaks ${formatVersion(ProtocolVersion)}, server ${formatVersion(hello.minVersion)}-${formatVersion(hello.version)}` : msg);
+          if (hello.authReason !== undefined) {
+            err.vangoAuthReason = hello.authReason;
+            if (this.client.options.debug) {
+              console.log('[Vango] Handshake auth reason:', hello.authReason);
+            }
+          }
+          this.client._onError(err);
+          if (hello.status === 0x01 || hello.status === 0x05) {
+            this._reloadForVersion(hello);
+          }
+          if (hello.status === 0x09) {
+            this.reconnectDisabled = true;
+            this.client.connection.setDisconnected('ip_limit');
+          }
+          if (hello.status === 0x03 || hello.status === 0x07) {
+            this._clearResumeInfo();
+            this.sessionId = null;
+            this.lastSeq = 0;
+            this.reconnectDisabled = true;
+            this.options.reconnect = false;
+            setTimeout(() => location.reload(), 0);
+          }
           this.ws.close();
           return;
         }
+        this.client.reassembler.reset();
+        try {
+          this.client.decompressor = this.client.codec.createDecompressor(hello.codec);
+        } catch (err) {
+          this.client._handleProtocolError(err, 'handshake');
+          return;
+        }
+        this.client.protocolVersion = hello.version;
+        if (this.client.uploads) {
+          this.client.uploads.enabled = (hello.flags & ServerFlagBinaryBlobs) !== 0;
+        }
         this.handshakeComplete = true;
         this.connected = true;
         this.sessionId = hello.sessionId;
+        this._persistResumeInfo(this.sessionId, this.lastSeq || this.client.patchSeq || 0);
         this.client._onConnected();
         this._flushQueue();
         if (this.client.options.debug) {
-          console.log("[Vango] Handshake complete, session:", this.sessionId);
+          console.log('[Vango] Handshake complete, session:', this.sessionId);
         }
         return;
       }
       this.client._handleBinaryMessage(buffer);
     }
-    /**
-     * Handle WebSocket close
-     *
-     * Per spec Section 9.6.3 (Connection Loss During Navigation):
-     * If WebSocket closes while awaiting navigation response,
-     * complete the navigation via location.assign(pendingPath).
-     */
+    _reloadForVersion(hello) {
+      this.reconnectDisabled = true;
+      this.options.reconnect = false;
+      this.client.connection.setDisconnected('version');
+      const key = '__vango_version_reload';
+      const now = Date.now();
+      if (this._storageAvailable()) {
+        try {
+          const last = Number(sessionStorage.getItem(key) || 0);
+          if (now - last < VersionReloadInterval) {
+            return;
+          }
+          sessionStorage.setItem(key, String(now));
+        } catch (_e) {}
+      }
+      this._clearResumeInfo();
+      setTimeout(() => location.reload(), 0);
+    }
     _onClose(event) {
-      var _a;
+      var _a3;
       const wasConnected = this.connected;
       this.connected = false;
       this.handshakeComplete = false;
       this._stopHeartbeat();
       if (this.client.options.debug) {
-        console.log("[Vango] WebSocket closed:", event.code, event.reason);
+        console.log('[Vango] WebSocket closed:', event.code, event.reason);
       }
-      const pendingPath = (_a = this.client.eventCapture) == null ? void 0 : _a.pendingNavPath;
+      const pendingPath = (_a3 = this.client.eventCapture) == null ? void 0 : _a3.pendingNavPath;
       if (wasConnected && pendingPath) {
-        console.log("[Vango] Connection lost during navigation, completing via location.assign:", pendingPath);
+        console.log('[Vango] Connection lost during navigation, completing via location.assign:', pendingPath);
         location.assign(pendingPath);
         return;
       }
       if (wasConnected) {
         this.client._onDisconnected();
       }
-      if (this.options.reconnect && !event.wasClean) {
+      if (!this.opened && !this.useHTTPFallback && this._httpFallbackAvailable()) {
+        if (this.client.options.debug) {
+          console.log('[Vango] WebSocket upgrade failed, falling back to SSE');
+        }
+        this.useHTTPFallback = true;
+        this.fellBack = true;
+        this.connect(this.url);
+        return;
+      }
+      if (!this.opened && this.fellBack) {
+        this.useHTTPFallback = false;
+        this.fellBack = false;
+      }
+      if (this.options.reconnect && !this.reconnectDisabled && !event.wasClean) {
         this._scheduleReconnect();
       }
     }
-    /**
-     * Handle WebSocket error
-     */
+    _httpFallbackAvailable() {
+      return this.client.options.httpFallback !== false && !this.reconnectDisabled && typeof EventSource !== 'undefined' && typeof fetch !== 'undefined';
+    }
     _onError(event) {
       if (this.client.options.debug) {
-        console.error("[Vango] WebSocket error:", event);
+        console.error('[Vango] WebSocket error:', event);
       }
-      this.client._onError(new Error("WebSocket error"));
+      this.client._onError(new Error('WebSocket error'));
     }
-    /**
-     * Schedule reconnection with exponential backoff
-     */
     _scheduleReconnect() {
-      const delay = Math.min(
-        this.options.reconnectInterval * Math.pow(2, this.reconnectAttempts),
-        this.options.reconnectMaxInterval
-      );
+      const delay = Math.min(this.options.reconnectInterval * Math.pow(2, this.reconnectAttempts), this.options.reconnectMaxInterval);
       this.reconnectAttempts++;
       if (this.client.options.debug) {
         console.log(`[Vango] Reconnecting in ${delay}ms (attempt ${this.reconnectAttempts})`);
       }
       setTimeout(() => this.connect(this.url), delay);
     }
-    /**
-     * Start heartbeat timer
-     */
     _startHeartbeat() {
       this.heartbeatTimer = setInterval(() => {
         if (this.connected) {
@@ -989,40 +2334,31 @@ var Vango = (() => {
         }
       }, this.options.heartbeatInterval);
     }
-    /**
-     * Stop heartbeat timer
-     */
     _stopHeartbeat() {
       if (this.heartbeatTimer) {
         clearInterval(this.heartbeatTimer);
         this.heartbeatTimer = null;
       }
     }
-    /**
-     * Send ping (control message)
-     */
     _sendPing() {
       if (this.ws && this.ws.readyState === WebSocket.OPEN) {
         const timestamp = Date.now();
         const payload = new Uint8Array(9);
-        payload[0] = 1;
+        payload[0] = 0x01;
         let ts = timestamp;
         for (let i = 0; i < 8; i++) {
-          payload[1 + i] = ts & 255;
+          payload[1 + i] = ts & 0xFF;
           ts = Math.floor(ts / 256);
         }
         const frame = new Uint8Array(4 + payload.length);
-        frame[0] = 3;
-        frame[1] = 0;
-        frame[2] = payload.length >> 8 & 255;
-        frame[3] = payload.length & 255;
+        frame[0] = 0x03;
+        frame[1] = 0x00;
+        frame[2] = payload.length >> 8 & 0xFF;
+        frame[3] = payload.length & 0xFF;
         frame.set(payload, 4);
         this.ws.send(frame);
       }
     }
-    /**
-     * Send binary message
-     */
     send(buffer) {
       if (this.connected && this.ws && this.ws.readyState === WebSocket.OPEN) {
         this.ws.send(buffer);
@@ -1032,64 +2368,140 @@ var Vango = (() => {
         return false;
       }
     }
-    /**
-     * Flush queued messages
-     */
     _flushQueue() {
       while (this.messageQueue.length > 0 && this.connected) {
         const buffer = this.messageQueue.shift();
         this.ws.send(buffer);
       }
     }
-    /**
-     * Close connection
-     */
     close() {
       this.options.reconnect = false;
       this._stopHeartbeat();
       if (this.ws) {
-        this.ws.close(1e3, "Client closing");
+        this.ws.close(1000, 'Client closing');
         this.ws = null;
       }
     }
-  };
-  __name(_WebSocketManager, "WebSocketManager");
-  var WebSocketManager = _WebSocketManager;
-
+    resetReconnect() {
+      this.reconnectDisabled = false;
+      this.reconnectAttempts = 0;
+    }
+    updateLastSeq(seq) {
+      const s = Number(seq);
+      if (!Number.isFinite(s) || s < 0) return;
+      this.lastSeq = s;
+      if (this.sessionId) {
+        this._persistResumeInfo(this.sessionId, this.lastSeq);
+      }
+    }
+    _storageAvailable() {
+      try {
+        return typeof sessionStorage !== 'undefined';
+      } catch (_e2) {
+        return false;
+      }
+    }
+    _loadResumeInfo() {
+      if (!this._storageAvailable()) return null;
+      try {
+        const sessionId = sessionStorage.getItem('__vango_session_id') || '';
+        if (!sessionId) return null;
+        const rawSeq = sessionStorage.getItem('__vango_last_seq') || '0';
+        const lastSeq = Number(rawSeq);
+        return {
+          sessionId,
+          lastSeq: Number.isFinite(lastSeq) && lastSeq >= 0 ? lastSeq : 0
+        };
+      } catch (_e3) {
+        return null;
+      }
+    }
+    _persistResumeInfo(sessionId, lastSeq) {
+      if (!this._storageAvailable()) return;
+      try {
+        sessionStorage.setItem('__vango_session_id', sessionId || '');
+        sessionStorage.setItem('__vango_last_seq', String(lastSeq || 0));
+      } catch (_e4) {}
+    }
+    _clearResumeInfo() {
+      if (!this._storageAvailable()) return;
+      try {
+        sessionStorage.removeItem('__vango_session_id');
+        sessionStorage.removeItem('__vango_last_seq');
+      } catch (_e5) {}
+    }
+    clearResumeInfo() {
+      this._clearResumeInfo();
+      this.sessionId = null;
+      this.lastSeq = 0;
+    }
+  }
+  __name(WebSocketManager, "WebSocketManager");
   // src/events.js
-  var _EventCapture = class _EventCapture {
+  const DEFAULT_THROTTLE = {
+    scroll: 100,
+    pointermove: 50,
+    drag: 50,
+    dragover: 50,
+    timeupdate: 250
+  };
+  const POINTER_EVENTS = ['pointerdown', 'pointerup', 'pointermove', 'pointerenter', 'pointerleave', 'pointercancel'];
+  const DRAG_EVENTS = ['dragstart', 'drag', 'dragenter', 'dragover', 'dragleave', 'dragend', 'drop'];
+  const MEDIA_EVENTS = ['play', 'pause', 'ended', 'timeupdate', 'seeking', 'seeked', 'volumechange', 'ratechange', 'durationchange', 'loadedmetadata', 'loadeddata', 'canplay', 'canplaythrough', 'waiting', 'playing'];
+  const CLIPBOARD_EVENTS = ['copy', 'cut', 'paste'];
+  const EVENTS_2_2 = new Set(['contextmenu', 'toggle', 'dragenter', 'dragover', 'dragleave', 'drag', ...POINTER_EVENTS, ...MEDIA_EVENTS, ...CLIPBOARD_EVENTS]);
+  function fileInfos(files) {
+    return Array.from(files || [], f => ({
+      name: f.name,
+      type: f.type,
+      size: f.size
+    }));
+  }
+  __name(fileInfos, "fileInfos");
+  class EventCapture {
     constructor(client) {
       this.client = client;
-      this.handlers = /* @__PURE__ */ new Map();
-      this.debounceTimers = /* @__PURE__ */ new Map();
-      this.scrollThrottled = /* @__PURE__ */ new Set();
-      this.prefetchedPaths = /* @__PURE__ */ new Set();
-    }
-    /**
-     * Attach event listeners to document
-     */
+      this.handlers = new Map();
+      this.debounceTimers = new Map();
+      this.throttled = new Set();
+      this.prefetchedPaths = new Set();
+    }
     attach() {
-      this._on("click", this._handleClick.bind(this));
-      this._on("dblclick", this._handleDblClick.bind(this));
-      this._on("input", this._handleInput.bind(this));
-      this._on("change", this._handleChange.bind(this));
-      this._on("submit", this._handleSubmit.bind(this));
-      this._on("focus", this._handleFocus.bind(this), true);
-      this._on("blur", this._handleBlur.bind(this), true);
-      this._on("keydown", this._handleKeyDown.bind(this));
-      this._on("keyup", this._handleKeyUp.bind(this));
-      this._on("mouseenter", this._handleMouseEnter.bind(this), true);
-      this._on("mouseleave", this._handleMouseLeave.bind(this), true);
-      this._on("scroll", this._handleScroll.bind(this), true);
-      this._on("click", this._handleLinkClick.bind(this));
-      window.addEventListener("popstate", this._handlePopState.bind(this));
-      this._on("mouseenter", this._handlePrefetch.bind(this), true);
-    }
-    /**
-     * Detach all event listeners
-     */
+      this._on('click', this._handleClick.bind(this));
+      this._on('dblclick', this._handleDblClick.bind(this));
+      this._on('input', this._handleInput.bind(this));
+      this._on('change', this._handleChange.bind(this));
+      this._on('submit', this._handleSubmit.bind(this));
+      this._on('focus', this._handleFocus.bind(this), true);
+      this._on('blur', this._handleBlur.bind(this), true);
+      this._on('keydown', this._handleKeyDown.bind(this));
+      this._on('keyup', this._handleKeyUp.bind(this));
+      this._on('mouseenter', this._handleMouseEnter.bind(this), true);
+      this._on('mouseleave', this._handleMouseLeave.bind(this), true);
+      this._on('contextmenu', this._handleContextMenu.bind(this));
+      for (const name of POINTER_EVENTS) {
+        this._on(name, this._handlePointer.bind(this), name === 'pointerenter' || name === 'pointerleave');
+      }
+      for (const name of DRAG_EVENTS) {
+        this._on(name, this._handleDrag.bind(this));
+      }
+      for (const name of MEDIA_EVENTS) {
+        this._on(name, this._handleMedia.bind(this), true);
+      }
+      this._on('toggle', this._handleToggle.bind(this), true);
+      for (const name of CLIPBOARD_EVENTS) {
+        this._on(name, this._handleClipboard.bind(this));
+      }
+      this._on('scroll', this._handleScroll.bind(this), true);
+      this._on('click', this._handleLinkClick.bind(this));
+      window.addEventListener('popstate', this._handlePopState.bind(this));
+      this._on('mouseenter', this._handlePrefetch.bind(this), true);
+    }
     detach() {
-      for (const [key, { handler, capture }] of this.handlers) {
+      for (const [key, {
+        handler,
+        capture
+      }] of this.handlers) {
         document.removeEventListener(key, handler, capture);
       }
       this.handlers.clear();
@@ -1098,64 +2510,47 @@ var Vango = (() => {
       }
       this.debounceTimers.clear();
     }
-    /**
-     * Helper to add event listener
-     */
     _on(eventType, handler, capture = false) {
-      document.addEventListener(eventType, handler, { capture, passive: false });
-      this.handlers.set(`${eventType}-${capture}`, { handler, capture });
+      document.addEventListener(eventType, handler, {
+        capture,
+        passive: false
+      });
+      this.handlers.set(`${eventType}-${capture}`, {
+        handler,
+        capture
+      });
     }
-    /**
-     * Find closest element with data-hid
-     */
     _findHidElement(target) {
       if (!target || !target.closest) {
         return null;
       }
-      return target.closest("[data-hid]");
+      return target.closest('[data-hid]');
     }
     _closest(target, selector) {
-      if (!target || typeof target.closest !== "function") {
+      if (!target || typeof target.closest !== 'function') {
         return null;
       }
       return target.closest(selector);
     }
-    /**
-     * Check if element has an event in its data-ve attribute.
-     * Parses data-ve="click,input,change" format per spec Section 5.2.
-     */
     _hasEvent(el, eventName) {
-      const ve = (el.dataset.ve || "").split(",").map((s) => s.trim());
+      const ve = (el.dataset.ve || '').split(',').map(s => s.trim());
       return ve.includes(eventName);
     }
-    /**
-     * Get modifier options for an event on an element.
-     * Reads data-pd-{event}, data-sp-{event}, data-self-{event}, etc.
-     * Per spec section 3.9.3, lines 1299-1365.
-     */
     _getModifiers(el, eventName) {
       return {
-        preventDefault: el.dataset[`pd${this._capitalize(eventName)}`] === "true",
-        stopPropagation: el.dataset[`sp${this._capitalize(eventName)}`] === "true",
-        self: el.dataset[`self${this._capitalize(eventName)}`] === "true",
-        once: el.dataset[`once${this._capitalize(eventName)}`] === "true",
-        passive: el.dataset[`passive${this._capitalize(eventName)}`] === "true",
-        capture: el.dataset[`capture${this._capitalize(eventName)}`] === "true",
-        debounce: parseInt(el.dataset[`debounce${this._capitalize(eventName)}`] || el.dataset.debounce || "0", 10),
-        throttle: parseInt(el.dataset[`throttle${this._capitalize(eventName)}`] || el.dataset.throttle || "0", 10)
+        preventDefault: el.dataset[`pd${this._capitalize(eventName)}`] === 'true',
+        stopPropagation: el.dataset[`sp${this._capitalize(eventName)}`] === 'true',
+        self: el.dataset[`self${this._capitalize(eventName)}`] === 'true',
+        once: el.dataset[`once${this._capitalize(eventName)}`] === 'true',
+        passive: el.dataset[`passive${this._capitalize(eventName)}`] === 'true',
+        capture: el.dataset[`capture${this._capitalize(eventName)}`] === 'true',
+        debounce: parseInt(el.dataset[`debounce${this._capitalize(eventName)}`] || el.dataset.debounce || '0', 10),
+        throttle: parseInt(el.dataset[`throttle${this._capitalize(eventName)}`] || el.dataset.throttle || '0', 10)
       };
     }
-    /**
-     * Capitalize first letter for dataset property access (click -> Click)
-     */
     _capitalize(str) {
       return str.charAt(0).toUpperCase() + str.slice(1);
     }
-    /**
-     * Apply modifiers to an event.
-     * Returns false if the event should not be processed.
-     * Returns the modifiers object for additional checks by handlers.
-     */
     _applyModifiers(event, el, eventName) {
       const mods = this._getModifiers(el, eventName);
       if (mods.self && event.target !== el) {
@@ -1164,7 +2559,7 @@ var Vango = (() => {
       if (mods.passive) {
         const originalPreventDefault = event.preventDefault.bind(event);
         event.preventDefault = () => {
-          console.warn("[Vango] preventDefault() called on passive handler - ignored");
+          console.warn('[Vango] preventDefault() called on passive handler - ignored');
         };
         queueMicrotask(() => {
           event.preventDefault = originalPreventDefault;
@@ -1176,226 +2571,245 @@ var Vango = (() => {
         event.stopPropagation();
       }
       if (mods.once) {
-        const ve = (el.dataset.ve || "").split(",").map((s) => s.trim());
-        const filtered = ve.filter((e) => e !== eventName);
+        const ve = (el.dataset.ve || '').split(',').map(s => s.trim());
+        const filtered = ve.filter(e => e !== eventName);
         if (filtered.length > 0) {
-          el.dataset.ve = filtered.join(",");
+          el.dataset.ve = filtered.join(',');
         } else {
           delete el.dataset.ve;
         }
       }
       return true;
     }
-    /**
-     * Get debounce delay for an event on an element.
-     */
     _getDebounce(el, eventName) {
       const mods = this._getModifiers(el, eventName);
-      if (eventName === "input" && mods.debounce === 0) {
+      if (eventName === 'input' && mods.debounce === 0) {
         return 100;
       }
       return mods.debounce;
     }
-    /**
-     * Get throttle delay for an event on an element.
-     */
     _getThrottle(el, eventName) {
       const mods = this._getModifiers(el, eventName);
-      if (eventName === "scroll" && mods.throttle === 0) {
-        return 100;
+      if (mods.throttle === 0 && DEFAULT_THROTTLE[eventName]) {
+        return DEFAULT_THROTTLE[eventName];
       }
       return mods.throttle;
     }
-    /**
-     * Find closest element with data-hid that has a specific event in data-ve.
-     * Parses data-ve="click,input,change" format per spec Section 5.2.
-     * This handles event bubbling through nested HID elements.
-     */
+    _isThrottled(el, eventName) {
+      const throttleMs = this._getThrottle(el, eventName);
+      if (throttleMs <= 0) {
+        return false;
+      }
+      const key = `${el.dataset.hid}:${eventName}`;
+      if (this.throttled.has(key)) {
+        return true;
+      }
+      this.throttled.add(key);
+      setTimeout(() => {
+        this.throttled.delete(key);
+      }, throttleMs);
+      return false;
+    }
+    _delegate(event, bubbles, payload) {
+      const name = event.type;
+      if (EVENTS_2_2.has(name) && !this._supports22()) {
+        return null;
+      }
+      const el = bubbles ? this._findHidElementWithEvent(event.target, name) : this._ownHidElement(event.target, name);
+      if (!el) return null;
+      if (!this._applyModifiers(event, el, name)) {
+        return null;
+      }
+      if (this._isThrottled(el, name)) {
+        return null;
+      }
+      this.client.sendEvent(EventType[name.toUpperCase()], el.dataset.hid, payload(el));
+      return el;
+    }
+    _supports22() {
+      const version = this.client.protocolVersion;
+      return !version || versionAtLeast(version, 2, 2);
+    }
+    _ownHidElement(target, eventName) {
+      if (!target || !target.dataset || !target.dataset.hid) {
+        return null;
+      }
+      return this._hasEvent(target, eventName) ? target : null;
+    }
     _findHidElementWithEvent(target, eventName) {
       if (!target || !target.closest) {
         return null;
       }
-      let el = target.closest("[data-hid]");
+      let el = target.closest('[data-hid]');
       while (el) {
         if (this._hasEvent(el, eventName)) {
           return el;
         }
         const parent = el.parentElement;
-        if (!parent)
-          break;
-        el = parent.closest("[data-hid]");
+        if (!parent) break;
+        el = parent.closest('[data-hid]');
       }
       return null;
     }
-    /**
-     * Handle click event
-     *
-     * Per spec Section 5.2 (Interception Decision Table):
-     * - MUST NOT intercept if defaultPrevented
-     * - MUST NOT intercept right/middle click (button !== 0)
-     * - MUST NOT intercept if modifier keys held
-     * - MUST NOT intercept if WebSocket not connected
-     * - For anchors: respect target, download, cross-origin
-     */
     _handleClick(event) {
-      if (event.defaultPrevented)
-        return;
-      if (event.button !== 0)
-        return;
-      if (event.ctrlKey || event.metaKey || event.shiftKey || event.altKey)
-        return;
-      if (!this.client.connected)
-        return;
-      const el = this._findHidElementWithEvent(event.target, "click");
-      if (!el)
-        return;
-      const anchor = this._closest(event.target, "a[href]");
+      if (event.defaultPrevented) return;
+      if (event.button !== 0) return;
+      if (event.ctrlKey || event.metaKey || event.shiftKey || event.altKey) return;
+      if (!this.client.connected) return;
+      const el = this._findHidElementWithEvent(event.target, 'click');
+      if (!el) return;
+      const anchor = this._closest(event.target, 'a[href]');
       if (anchor) {
-        const target = anchor.getAttribute("target");
-        if (target && target !== "_self")
-          return;
-        if (anchor.hasAttribute("download"))
-          return;
-        const href = anchor.getAttribute("href");
+        const target = anchor.getAttribute('target');
+        if (target && target !== '_self') return;
+        if (anchor.hasAttribute('download')) return;
+        const href = anchor.getAttribute('href');
         if (href) {
           try {
             const url = new URL(href, location.href);
-            if (url.origin !== location.origin)
-              return;
-          } catch (e) {
+            if (url.origin !== location.origin) return;
+          } catch (_e) {
             return;
           }
         }
       }
-      if (!this._applyModifiers(event, el, "click")) {
+      if (!this._applyModifiers(event, el, 'click')) {
         return;
       }
       event.preventDefault();
-      this.client.optimistic.applyOptimistic(el, "click");
+      this.client.optimistic.applyOptimistic(el, 'click');
       this.client.sendEvent(EventType.CLICK, el.dataset.hid);
     }
-    /**
-     * Handle double-click event
-     */
     _handleDblClick(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "dblclick"))
-        return;
-      if (!this._applyModifiers(event, el, "dblclick")) {
+      if (!el || !this._hasEvent(el, 'dblclick')) return;
+      if (!this._applyModifiers(event, el, 'dblclick')) {
         return;
       }
-      const mods = this._getModifiers(el, "dblclick");
+      const mods = this._getModifiers(el, 'dblclick');
       if (!mods.preventDefault && !mods.passive) {
         event.preventDefault();
       }
       this.client.sendEvent(EventType.DBLCLICK, el.dataset.hid);
     }
-    /**
-     * Handle input event (debounced)
-     */
     _handleInput(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "input"))
-        return;
-      if (!this._applyModifiers(event, el, "input")) {
+      if (!el || !this._hasEvent(el, 'input')) return;
+      if (!this._applyModifiers(event, el, 'input')) {
         return;
       }
       const hid = el.dataset.hid;
-      const debounceMs = this._getDebounce(el, "input");
+      const debounceMs = this._getDebounce(el, 'input');
       if (this.debounceTimers.has(hid)) {
         clearTimeout(this.debounceTimers.get(hid));
       }
       if (debounceMs === 0) {
-        this.client.sendEvent(EventType.INPUT, hid, { value: el.value });
+        this.client.sendEvent(EventType.INPUT, hid, {
+          value: el.value
+        });
         return;
       }
       const timer = setTimeout(() => {
         this.debounceTimers.delete(hid);
-        this.client.sendEvent(EventType.INPUT, hid, { value: el.value });
+        this.client.sendEvent(EventType.INPUT, hid, {
+          value: el.value
+        });
       }, debounceMs);
       this.debounceTimers.set(hid, timer);
     }
-    /**
-     * Handle change event
-     */
     _handleChange(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "change"))
-        return;
-      if (!this._applyModifiers(event, el, "change")) {
+      if (!el) return;
+      if (el.type === 'file' && this._hasEvent(el, 'upload')) {
+        this._uploadFiles(el, el.files);
+      }
+      if (!this._hasEvent(el, 'change')) return;
+      if (!this._applyModifiers(event, el, 'change')) {
         return;
       }
       let value;
-      if (el.type === "checkbox") {
-        value = el.checked ? "true" : "false";
-      } else if (el.type === "radio") {
-        value = el.checked ? el.value : "";
-      } else if (el.tagName === "SELECT" && el.multiple) {
-        value = Array.from(el.selectedOptions).map((o) => o.value).join(",");
+      if (el.type === 'checkbox') {
+        value = el.checked ? 'true' : 'false';
+      } else if (el.type === 'radio') {
+        value = el.checked ? el.value : '';
+      } else if (el.tagName === 'SELECT' && el.multiple) {
+        value = Array.from(el.selectedOptions).map(o => o.value).join(',');
       } else {
         value = el.value;
       }
-      this.client.optimistic.applyOptimistic(el, "change");
-      this.client.sendEvent(EventType.CHANGE, el.dataset.hid, { value });
+      this.client.optimistic.applyOptimistic(el, 'change');
+      this.client.sendEvent(EventType.CHANGE, el.dataset.hid, {
+        value
+      });
+    }
+    _handleUploadDragOver(event) {
+      if (!event.dataTransfer || !Array.from(event.dataTransfer.types || []).includes('Files')) return;
+      const el = this._findHidElementWithEvent(event.target, 'upload');
+      if (!el) return;
+      event.preventDefault();
+      event.dataTransfer.dropEffect = 'copy';
+    }
+    _handleUploadDrop(event) {
+      var _a;
+      const files = (_a = event.dataTransfer) == null ? void 0 : _a.files;
+      if (!files || files.length === 0) return;
+      const el = this._findHidElementWithEvent(event.target, 'upload');
+      if (!el) return;
+      event.preventDefault();
+      this._uploadFiles(el, files);
+    }
+    _uploadFiles(el, files) {
+      if (!files) return;
+      for (const file of Array.from(files)) {
+        if (!this.client.uploads.upload(el.dataset.hid, file)) {
+          if (this.client.options.debug) {
+            console.warn('[Vango] Upload not sent: blob uploads unavailable', file.name);
+          }
+        }
+      }
     }
-    /**
-     * Handle form submit
-     *
-     * Per spec Section 5.2 (Progressive Enhancement):
-     * When WebSocket is unavailable, forms MUST fall back to normal HTTP submission.
-     */
     _handleSubmit(event) {
-      const form = this._closest(event.target, "form[data-hid]");
-      if (!form || !this._hasEvent(form, "submit"))
-        return;
-      if (!this.client.connected)
-        return;
-      if (event.defaultPrevented)
-        return;
+      const form = this._closest(event.target, 'form[data-hid]');
+      if (!form || !this._hasEvent(form, 'submit')) return;
+      if (!this.client.connected) return;
+      if (event.defaultPrevented) return;
       event.preventDefault();
       const formData = new FormData(form);
       const fields = {};
       for (const [key, value] of formData.entries()) {
-        fields[key] = String(value);
+        const v = formValueString(value);
+        if (Object.prototype.hasOwnProperty.call(fields, key)) {
+          fields[key] = [].concat(fields[key], v);
+        } else {
+          fields[key] = v;
+        }
       }
       this.client.sendEvent(EventType.SUBMIT, form.dataset.hid, fields);
     }
-    /**
-     * Handle focus event
-     */
     _handleFocus(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "focus"))
-        return;
-      if (!this._applyModifiers(event, el, "focus")) {
+      if (!el || !this._hasEvent(el, 'focus')) return;
+      if (!this._applyModifiers(event, el, 'focus')) {
         return;
       }
       this.client.sendEvent(EventType.FOCUS, el.dataset.hid);
     }
-    /**
-     * Handle blur event
-     */
     _handleBlur(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "blur"))
-        return;
-      if (!this._applyModifiers(event, el, "blur")) {
+      if (!el || !this._hasEvent(el, 'blur')) return;
+      if (!this._applyModifiers(event, el, 'blur')) {
         return;
       }
       this.client.sendEvent(EventType.BLUR, el.dataset.hid);
     }
-    /**
-     * Handle keydown event
-     */
     _handleKeyDown(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "keydown"))
-        return;
+      if (!el || !this._hasEvent(el, 'keydown')) return;
       const keyFilter = el.dataset.keyFilter;
       if (keyFilter && !this._matchesKeyFilter(event, keyFilter)) {
         return;
       }
-      if (!this._applyModifiers(event, el, "keydown")) {
+      if (!this._applyModifiers(event, el, 'keydown')) {
         return;
       }
       this.client.sendEvent(EventType.KEYDOWN, el.dataset.hid, {
@@ -1409,14 +2823,10 @@ var Vango = (() => {
         location: event.location
       });
     }
-    /**
-     * Handle keyup event
-     */
     _handleKeyUp(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "keyup"))
-        return;
-      if (!this._applyModifiers(event, el, "keyup")) {
+      if (!el || !this._hasEvent(el, 'keyup')) return;
+      if (!this._applyModifiers(event, el, 'keyup')) {
         return;
       }
       this.client.sendEvent(EventType.KEYUP, el.dataset.hid, {
@@ -1430,108 +2840,139 @@ var Vango = (() => {
         location: event.location
       });
     }
-    /**
-     * Handle mouseenter event
-     */
     _handleMouseEnter(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "mouseenter"))
-        return;
-      if (!this._applyModifiers(event, el, "mouseenter")) {
+      if (!el || !this._hasEvent(el, 'mouseenter')) return;
+      if (!this._applyModifiers(event, el, 'mouseenter')) {
         return;
       }
       this.client.sendEvent(EventType.MOUSEENTER, el.dataset.hid);
     }
-    /**
-     * Handle mouseleave event
-     */
     _handleMouseLeave(event) {
       const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "mouseleave"))
-        return;
-      if (!this._applyModifiers(event, el, "mouseleave")) {
+      if (!el || !this._hasEvent(el, 'mouseleave')) return;
+      if (!this._applyModifiers(event, el, 'mouseleave')) {
         return;
       }
       this.client.sendEvent(EventType.MOUSELEAVE, el.dataset.hid);
     }
-    /**
-     * Handle scroll event (throttled)
-     */
-    _handleScroll(event) {
-      const el = this._findHidElement(event.target);
-      if (!el || !this._hasEvent(el, "scroll"))
-        return;
-      if (!this._applyModifiers(event, el, "scroll")) {
-        return;
+    _handleContextMenu(event) {
+      const el = this._delegate(event, true, () => event);
+      if (!el) return;
+      const mods = this._getModifiers(el, 'contextmenu');
+      if (!mods.preventDefault && !mods.passive) {
+        event.preventDefault();
       }
-      const hid = el.dataset.hid;
-      const throttleMs = this._getThrottle(el, "scroll");
-      if (throttleMs > 0 && this.scrollThrottled.has(hid)) {
-        return;
+    }
+    _handlePointer(event) {
+      const bubbles = event.type !== 'pointerenter' && event.type !== 'pointerleave';
+      this._delegate(event, bubbles, () => event);
+    }
+    _handleDrag(event) {
+      if (event.type === 'dragover') {
+        this._handleUploadDragOver(event);
+      } else if (event.type === 'drop') {
+        this._handleUploadDrop(event);
       }
-      if (throttleMs > 0) {
-        this.scrollThrottled.add(hid);
-        setTimeout(() => {
-          this.scrollThrottled.delete(hid);
-        }, throttleMs);
+      if ((event.type === 'dragenter' || event.type === 'dragover') && this._findHidElementWithEvent(event.target, 'drop')) {
+        event.preventDefault();
       }
-      this.client.sendEvent(EventType.SCROLL, hid, {
-        scrollTop: el.scrollTop,
-        scrollLeft: el.scrollLeft
-      });
-    }
-    /**
-     * Handle link click for client-side navigation.
-     *
-     * Per Navigation Contract Section 4.5 (Link Click Navigation):
-     * 1. Do NOT update history immediately
-     * 2. Send EventNavigate { path, replace }
-     * 3. Wait for server response with NAV_* + DOM patches
-     * 4. Apply patches (URL update + DOM update)
-     *
-     * Per Progressive Enhancement Contract Section 5.1:
-     * A link click is intercepted ONLY when ALL conditions are true:
-     * 1. Element has data-vango-link attribute (canonical marker)
-     * 2. WebSocket connection is healthy
-     * 3. Link is same-origin
-     * 4. No modifier keys pressed (ctrl, meta, shift, alt)
-     * 5. No target attribute (or target="_self")
-     * 6. No download attribute
-     *
-     * Note: data-link is supported for backwards compatibility but deprecated.
-     */
-    _handleLinkClick(event) {
-      const link = this._closest(event.target, "a[href]");
-      if (!link)
+      const el = this._delegate(event, true, () => this._dragPayload(event));
+      if (el && event.type === 'drop') {
+        event.preventDefault();
+      }
+    }
+    _dragPayload(event) {
+      const dt = event.dataTransfer;
+      const types = Array.from((dt == null ? void 0 : dt.types) || []);
+      const data = {};
+      let files = [];
+      if (event.type === 'drop' && dt) {
+        for (const type of types) {
+          if (type !== 'Files') {
+            data[type] = dt.getData(type);
+          }
+        }
+        files = fileInfos(dt.files);
+      }
+      return {
+        clientX: event.clientX,
+        clientY: event.clientY,
+        pageX: event.pageX,
+        pageY: event.pageY,
+        ctrlKey: event.ctrlKey,
+        shiftKey: event.shiftKey,
+        altKey: event.altKey,
+        metaKey: event.metaKey,
+        types,
+        files,
+        data
+      };
+    }
+    _handleMedia(event) {
+      this._delegate(event, false, el => el);
+    }
+    _handleClipboard(event) {
+      this._delegate(event, true, () => {
+        const cd = event.clipboardData;
+        const paste = event.type === 'paste';
+        return {
+          text: paste ? (cd == null ? void 0 : cd.getData('text/plain')) || '' : '',
+          types: Array.from((cd == null ? void 0 : cd.types) || []),
+          files: paste ? fileInfos(cd == null ? void 0 : cd.files) : []
+        };
+      });
+    }
+    _handleToggle(event) {
+      this._delegate(event, false, el => ({
+        open: event.newState ? event.newState === 'open' : Boolean(el.open)
+      }));
+    }
+    _handleScroll(event) {
+      const el = this._findHidElement(event.target);
+      if (!el || !this._hasEvent(el, 'scroll')) return;
+      if (!this._applyModifiers(event, el, 'scroll')) {
         return;
+      }
+      const hid = el.dataset.hid;
+      if (this._isThrottled(el, 'scroll')) {
+        return;
+      }
+      this.client.sendEvent(EventType.SCROLL, hid, {
+        scrollTop: el.scrollTop,
+        scrollLeft: el.scrollLeft
+      });
+    }
+    _handleLinkClick(event) {
+      const link = this._closest(event.target, 'a[href]');
+      if (!link) return;
       if (event.ctrlKey || event.metaKey || event.shiftKey || event.altKey) {
         return;
       }
-      if (link.hasAttribute("download")) {
+      if (link.hasAttribute('download')) {
         return;
       }
-      const target = link.getAttribute("target");
-      if (target && target !== "_self") {
+      const target = link.getAttribute('target');
+      if (target && target !== '_self') {
         return;
       }
-      const isVangoLink = link.hasAttribute("data-vango-link") || link.hasAttribute("data-link");
+      const isVangoLink = link.hasAttribute('data-vango-link') || link.hasAttribute('data-link');
       if (!isVangoLink) {
         return;
       }
-      const href = link.getAttribute("href");
-      if (!href)
-        return;
-      if (href.startsWith("http://") || href.startsWith("https://") || href.startsWith("//")) {
+      const href = link.getAttribute('href');
+      if (!href) return;
+      if (href.startsWith('http://') || href.startsWith('https://') || href.startsWith('//')) {
         try {
           const url = new URL(href, window.location.origin);
           if (url.origin !== window.location.origin) {
             return;
           }
-        } catch (e) {
+        } catch (_e2) {
           return;
         }
       }
-      if (link.hasAttribute("data-external")) {
+      if (link.hasAttribute('data-external')) {
         return;
       }
       if (!this.client.connected) {
@@ -1539,57 +2980,34 @@ var Vango = (() => {
       }
       event.preventDefault();
       this.pendingNavPath = href;
-      this.client.sendEvent(EventType.NAVIGATE, "nav", { path: href, replace: false });
-    }
-    /**
-     * Handle browser back/forward navigation (popstate).
-     *
-     * Per Navigation Contract Section 4.6:
-     * 1. Browser fires popstate event
-     * 2. Client sends EventNavigate { path, replace: true }
-     * 3. Server remounts and sends DOM patches
-     * 4. Server MAY omit NAV_REPLACE since URL already changed
-     *
-     * Note: popstate means the URL has already changed in the browser,
-     * so we use replace: true to avoid server sending NAV_* that would
-     * duplicate the history entry.
-     */
+      this.client.sendEvent(EventType.NAVIGATE, 'nav', {
+        path: href,
+        replace: false
+      });
+    }
     _handlePopState(event) {
       if (!this.client.connected) {
         return;
       }
       const path = location.pathname + location.search;
       this.pendingNavPath = path;
-      this.client.sendEvent(EventType.NAVIGATE, "nav", { path, replace: true });
-    }
-    /**
-     * Handle prefetch on hover for links with data-prefetch attribute.
-     *
-     * Per Prefetch Contract Section 8.1:
-     * Client sends EventType.CUSTOM (0xFF) with:
-     * - Name: "prefetch"
-     * - Data: JSON-encoded { "path": "/target/path" }
-     *
-     * Wire format:
-     * [0xFF (CUSTOM)][name: "prefetch" (varint-len string)][data: JSON bytes (varint-len)]
-     *
-     * The server can use this to preload route data before the user clicks,
-     * making navigation feel instant.
-     */
+      this.client.sendEvent(EventType.NAVIGATE, 'nav', {
+        path,
+        replace: true
+      });
+    }
     _handlePrefetch(event) {
-      const link = this._closest(event.target, "a[data-prefetch][href]");
-      if (!link)
-        return;
-      const href = link.getAttribute("href");
-      if (!href)
-        return;
-      if (href.startsWith("http://") || href.startsWith("https://") || href.startsWith("//")) {
+      const link = this._closest(event.target, 'a[data-prefetch][href]');
+      if (!link) return;
+      const href = link.getAttribute('href');
+      if (!href) return;
+      if (href.startsWith('http://') || href.startsWith('https://') || href.startsWith('//')) {
         try {
           const url = new URL(href, window.location.origin);
           if (url.origin !== window.location.origin) {
             return;
           }
-        } catch (e) {
+        } catch (_e3) {
           return;
         }
       }
@@ -1600,61 +3018,284 @@ var Vango = (() => {
         return;
       }
       this.prefetchedPaths.add(href);
-      const jsonData = JSON.stringify({ path: href });
+      const jsonData = JSON.stringify({
+        path: href
+      });
       const encoder = new TextEncoder();
       const dataBytes = encoder.encode(jsonData);
-      this.client.sendEvent(EventType.CUSTOM, "prefetch", { name: "prefetch", data: dataBytes });
+      this.client.sendEvent(EventType.CUSTOM, 'prefetch', {
+        name: 'prefetch',
+        data: dataBytes
+      });
       if (this.client.options.debug) {
-        console.log("[Vango] Prefetching:", href);
+        console.log('[Vango] Prefetching:', href);
       }
     }
-    /**
-     * Check if event matches key filter
-     * Format: "Enter" or "Ctrl+s" or "Meta+Enter"
-     */
     _matchesKeyFilter(event, filter) {
-      const parts = filter.split("+");
+      const parts = filter.split('+');
       const key = parts.pop().toLowerCase();
-      const modifiers = new Set(parts.map((m) => m.toLowerCase()));
+      const modifiers = new Set(parts.map(m => m.toLowerCase()));
       if (event.key.toLowerCase() !== key) {
         return false;
       }
-      const hasCtrl = modifiers.has("ctrl") || modifiers.has("control");
-      const hasShift = modifiers.has("shift");
-      const hasAlt = modifiers.has("alt");
-      const hasMeta = modifiers.has("meta") || modifiers.has("cmd");
-      if (hasCtrl !== event.ctrlKey)
-        return false;
-      if (hasShift !== event.shiftKey)
-        return false;
-      if (hasAlt !== event.altKey)
-        return false;
-      if (hasMeta !== event.metaKey)
-        return false;
+      const hasCtrl = modifiers.has('ctrl') || modifiers.has('control');
+      const hasShift = modifiers.has('shift');
+      const hasAlt = modifiers.has('alt');
+      const hasMeta = modifiers.has('meta') || modifiers.has('cmd');
+      if (hasCtrl !== event.ctrlKey) return false;
+      if (hasShift !== event.shiftKey) return false;
+      if (hasAlt !== event.altKey) return false;
+      if (hasMeta !== event.metaKey) return false;
       return true;
     }
-  };
-  __name(_EventCapture, "EventCapture");
-  var EventCapture = _EventCapture;
-
+  }
+  __name(EventCapture, "EventCapture");
+  // src/portals.js
+  const anchors = new WeakMap();
+  function portalTarget(selector) {
+    let target = null;
+    if (selector) {
+      try {
+        target = document.querySelector(selector);
+      } catch (e) {
+        console.warn('[Vango] Invalid portal target:', selector);
+      }
+    }
+    return target || document.body;
+  }
+  __name(portalTarget, "portalTarget");
+  function createPortalAnchor(wrapper) {
+    const anchor = document.createElement('template');
+    anchor.dataset.portalAnchor = wrapper.dataset.hid || '';
+    anchors.set(wrapper, anchor);
+    return anchor;
+  }
+  __name(createPortalAnchor, "createPortalAnchor");
+  function portalAnchor(wrapper) {
+    return anchors.get(wrapper) || null;
+  }
+  __name(portalAnchor, "portalAnchor");
+  function nestedPortals(el, getNode) {
+    const wrappers = [];
+    el.querySelectorAll('template[data-portal-anchor]').forEach(anchor => {
+      const wrapper = getNode(anchor.dataset.portalAnchor);
+      if (wrapper && anchors.get(wrapper) === anchor) {
+        wrappers.push(wrapper);
+      }
+    });
+    return wrappers;
+  }
+  __name(nestedPortals, "nestedPortals");
+  function mountPortals(root = document) {
+    root.querySelectorAll('vango-portal[data-portal-target]').forEach(wrapper => {
+      var _a;
+      if (!wrapper.dataset.hid || portalAnchor(wrapper)) return;
+      (_a = wrapper.parentNode) == null ? void 0 : _a.insertBefore(createPortalAnchor(wrapper), wrapper);
+      portalTarget(wrapper.dataset.portalTarget).appendChild(wrapper);
+    });
+  }
+  __name(mountPortals, "mountPortals");
+  // src/transitions.js
+  const running = new WeakMap();
+  const leaving = new WeakSet();
+  function transitionSource(el) {
+    if ((el == null ? void 0 : el.nodeType) !== 1) return null;
+    if (el.hasAttribute('data-transition')) return el;
+    const parent = el.parentElement;
+    if (parent == null ? void 0 : parent.hasAttribute('data-transition')) return parent;
+    return null;
+  }
+  __name(transitionSource, "transitionSource");
+  function transitionName(el) {
+    var _a;
+    return ((_a = transitionSource(el)) == null ? void 0 : _a.dataset.transition) || '';
+  }
+  __name(transitionName, "transitionName");
+  function isLeaving(el) {
+    return leaving.has(el);
+  }
+  __name(isLeaving, "isLeaving");
+  function liveChildren(parent) {
+    return Array.from(parent.children).filter(child => !leaving.has(child));
+  }
+  __name(liveChildren, "liveChildren");
+  function nextFrame(fn) {
+    if (typeof requestAnimationFrame === 'function') {
+      requestAnimationFrame(fn);
+    } else {
+      setTimeout(fn, 0);
+    }
+  }
+  __name(nextFrame, "nextFrame");
+  function parseTimes(value) {
+    return (value || '').split(',').map(part => {
+      const s = part.trim();
+      const n = parseFloat(s);
+      if (Number.isNaN(n)) return 0;
+      return s.endsWith('ms') ? n : n * 1000;
+    });
+  }
+  __name(parseTimes, "parseTimes");
+  function longest(durations, delays) {
+    const d = parseTimes(durations);
+    const w = parseTimes(delays);
+    return d.reduce((max, ms, i) => Math.max(max, ms + (w[i % w.length] || 0)), 0);
+  }
+  __name(longest, "longest");
+  function transitionTimeout(el) {
+    var _a2;
+    const explicit = (_a2 = transitionSource(el)) == null ? void 0 : _a2.dataset.transitionTimeout;
+    if (explicit !== undefined && explicit !== '') {
+      return Math.max(0, parseInt(explicit, 10) || 0);
+    }
+    if (typeof getComputedStyle !== 'function') return 0;
+    const style = getComputedStyle(el);
+    return Math.max(longest(style.transitionDuration, style.transitionDelay), longest(style.animationDuration, style.animationDelay));
+  }
+  __name(transitionTimeout, "transitionTimeout");
+  function whenDone(el, done) {
+    let finished = false;
+    let timer = null;
+    const finish = () => {
+      if (finished) return;
+      finished = true;
+      clearTimeout(timer);
+      el.removeEventListener('transitionend', onEnd);
+      el.removeEventListener('animationend', onEnd);
+      running.delete(el);
+      done();
+    };
+    const onEnd = event => {
+      if (event.target === el) finish();
+    };
+    el.addEventListener('transitionend', onEnd);
+    el.addEventListener('animationend', onEnd);
+    timer = setTimeout(finish, transitionTimeout(el));
+    running.set(el, finish);
+    return finish;
+  }
+  __name(whenDone, "whenDone");
+  function play(el, name, phase, done) {
+    var _fn;
+    (_fn = running.get(el)) == null ? void 0 : _fn();
+    const from = `${name}-${phase}-from`;
+    const active = `${name}-${phase}-active`;
+    const to = `${name}-${phase}-to`;
+    let finished = false;
+    el.classList.add(from, active);
+    whenDone(el, () => {
+      finished = true;
+      el.classList.remove(from, active, to);
+      done == null ? void 0 : done();
+    });
+    nextFrame(() => {
+      if (finished) return;
+      el.classList.remove(from);
+      el.classList.add(to);
+    });
+  }
+  __name(play, "play");
+  function animated(el) {
+    if ((el == null ? void 0 : el.nodeType) !== 1) return [];
+    if (el.hasAttribute('data-portal-target')) {
+      return Array.from(el.children).filter(child => child.hasAttribute('data-transition'));
+    }
+    return transitionName(el) ? [el] : [];
+  }
+  __name(animated, "animated");
+  function enter(el) {
+    for (const target of animated(el)) {
+      play(target, transitionName(target), 'enter');
+    }
+  }
+  __name(enter, "enter");
+  function leave(el, remove) {
+    const targets = animated(el);
+    if (targets.length === 0) return false;
+    leaving.add(el);
+    el.setAttribute('inert', '');
+    let pending = targets.length;
+    for (const target of targets) {
+      play(target, transitionName(target), 'leave', () => {
+        if (--pending === 0) {
+          leaving.delete(el);
+          remove();
+        }
+      });
+    }
+    return true;
+  }
+  __name(leave, "leave");
+  function recordPositions(parent, positions) {
+    var _fn2;
+    if (!positions || !(parent == null || (_fn2 = parent.hasAttribute) == null ? void 0 : _fn2.call(parent, 'data-transition')) || positions.has(parent)) return;
+    const rects = new Map();
+    for (const child of liveChildren(parent)) {
+      rects.set(child, child.getBoundingClientRect());
+    }
+    positions.set(parent, rects);
+  }
+  __name(recordPositions, "recordPositions");
+  function playMoves(positions) {
+    var _fn3;
+    const moved = [];
+    for (const [parent, rects] of positions) {
+      const name = parent.dataset.transition;
+      for (const child of liveChildren(parent)) {
+        const before = rects.get(child);
+        if (!before) continue;
+        const after = child.getBoundingClientRect();
+        const dx = before.left - after.left;
+        const dy = before.top - after.top;
+        if (dx || dy) {
+          moved.push({
+            child,
+            name,
+            dx,
+            dy
+          });
+        }
+      }
+    }
+    if (moved.length === 0) return;
+    for (const {
+      child,
+      dx,
+      dy
+    } of moved) {
+      (_fn3 = running.get(child)) == null ? void 0 : _fn3();
+      child.style.transform = `translate(${dx}px, ${dy}px)`;
+      child.style.transitionDuration = '0s';
+    }
+    void document.body.offsetHeight;
+    for (const {
+      child,
+      name
+    } of moved) {
+      const moveClass = `${name}-move`;
+      child.classList.add(moveClass);
+      child.style.transform = '';
+      child.style.transitionDuration = '';
+      whenDone(child, () => child.classList.remove(moveClass));
+    }
+  }
+  __name(playMoves, "playMoves");
   // src/patches.js
-  var _PatchApplier = class _PatchApplier {
+  class PatchApplier {
     constructor(client) {
       this.client = client;
     }
-    /**
-     * Apply array of patches
-     */
     apply(patches) {
-      for (const patch of patches) {
-        this.applyPatch(patch);
+      this._positions = new Map();
+      try {
+        for (const patch of patches) {
+          this.applyPatch(patch);
+        }
+        playMoves(this._positions);
+      } finally {
+        this._positions = null;
       }
     }
-    /**
-     * Check if a patch type requires a target DOM node.
-     * URL/NAV patches operate on browser history, not DOM elements.
-     * Per spec Section 9.6.1: These must not trigger self-heal.
-     */
     _requiresTargetNode(patchType) {
       switch (patchType) {
         case PatchType.URL_PUSH:
@@ -1662,35 +3303,34 @@ var Vango = (() => {
         case PatchType.NAV_PUSH:
         case PatchType.NAV_REPLACE:
           return false;
+        case PatchType.SET_TITLE:
+        case PatchType.SET_META:
+        case PatchType.SET_LINK:
+          return false;
+        case PatchType.ISLAND_MESSAGE:
+          return false;
         case PatchType.INSERT_NODE:
           return false;
         default:
           return true;
       }
     }
-    /**
-     * Trigger self-heal recovery per spec Section 9.6.1.
-     * If navigation in progress: location.assign(pendingPath)
-     * Else: location.reload()
-     */
     _triggerSelfHeal() {
       var _a;
       const pendingPath = (_a = this.client.eventCapture) == null ? void 0 : _a.pendingNavPath;
       if (pendingPath) {
-        console.log("[Vango] Self-heal: navigating to pending path:", pendingPath);
+        console.log('[Vango] Self-heal: navigating to pending path:', pendingPath);
         location.assign(pendingPath);
       } else {
-        console.log("[Vango] Self-heal: reloading page");
+        console.log('[Vango] Self-heal: reloading page');
         location.reload();
       }
     }
-    /**
-     * Apply single patch
-     */
     applyPatch(patch) {
+      var _a2;
       const el = this.client.getNode(patch.hid);
       if (!el && this._requiresTargetNode(patch.type)) {
-        console.error("[Vango] Patch target not found (HID:", patch.hid, "type:", patch.type, ")");
+        console.error('[Vango] Patch target not found (HID:', patch.hid, 'type:', patch.type, ')');
         this._triggerSelfHeal();
         return;
       }
@@ -1717,7 +3357,7 @@ var Vango = (() => {
           el.style[patch.key] = patch.value;
           break;
         case PatchType.REMOVE_STYLE:
-          el.style[patch.key] = "";
+          el.style[patch.key] = '';
           break;
         case PatchType.INSERT_NODE:
           this._insertNode(patch.parentID, patch.index, patch.vnode);
@@ -1750,7 +3390,7 @@ var Vango = (() => {
           el.scrollTo({
             left: patch.x,
             top: patch.y,
-            behavior: patch.behavior === 1 ? "smooth" : "instant"
+            behavior: patch.behavior === 1 ? 'smooth' : 'instant'
           });
           break;
         case PatchType.SET_DATA:
@@ -1769,49 +3409,55 @@ var Vango = (() => {
         case PatchType.NAV_REPLACE:
           this._applyNavPatch(patch);
           break;
+        case PatchType.SET_TITLE:
+          document.title = patch.value;
+          break;
+        case PatchType.SET_META:
+          this._setHeadTag('meta', patch.attr, patch.key, 'content', patch.value);
+          break;
+        case PatchType.SET_LINK:
+          this._setHeadTag('link', 'rel', patch.key, 'href', patch.value);
+          break;
+        case PatchType.ISLAND_MESSAGE:
+          (_a2 = this.client.islands) == null ? void 0 : _a2.deliver(patch.hid, patch.data || {});
+          break;
         default:
           if (this.client.options.debug) {
-            console.warn("[Vango] Unknown patch type:", patch.type);
+            console.warn('[Vango] Unknown patch type:', patch.type);
           }
       }
     }
-    /**
-     * Set text content
-     */
     _setText(el, text) {
       el.textContent = text;
     }
-    /**
-     * Set attribute with special cases
-     */
     _setAttr(el, key, value) {
-      if (key.length > 2 && key.substring(0, 2).toLowerCase() === "on") {
-        console.warn("[Vango] Blocked dangerous attribute:", key);
+      if (key.length > 2 && key.substring(0, 2).toLowerCase() === 'on') {
+        console.warn('[Vango] Blocked dangerous attribute:', key);
         return;
       }
       switch (key) {
-        case "class":
+        case 'class':
           el.className = value;
           break;
-        case "for":
+        case 'for':
           el.htmlFor = value;
           break;
-        case "value":
+        case 'value':
           this._setValue(el, value);
           break;
-        case "checked":
-          el.checked = value === "true" || value === "";
+        case 'checked':
+          el.checked = value === 'true' || value === '';
           break;
-        case "selected":
-          el.selected = value === "true" || value === "";
+        case 'selected':
+          el.selected = value === 'true' || value === '';
           break;
-        case "disabled":
-        case "readonly":
-        case "required":
-        case "multiple":
-        case "autofocus":
-          if (value === "true" || value === "") {
-            el.setAttribute(key, "");
+        case 'disabled':
+        case 'readonly':
+        case 'required':
+        case 'multiple':
+        case 'autofocus':
+          if (value === 'true' || value === '') {
+            el.setAttribute(key, '');
           } else {
             el.removeAttribute(key);
           }
@@ -1820,129 +3466,115 @@ var Vango = (() => {
           el.setAttribute(key, value);
       }
     }
-    /**
-     * Remove attribute
-     */
     _removeAttr(el, key) {
       switch (key) {
-        case "class":
-          el.className = "";
+        case 'class':
+          el.className = '';
           break;
-        case "for":
-          el.htmlFor = "";
+        case 'for':
+          el.htmlFor = '';
           break;
-        case "value":
-          el.value = "";
+        case 'value':
+          el.value = '';
           break;
-        case "checked":
+        case 'checked':
           el.checked = false;
           break;
-        case "selected":
+        case 'selected':
           el.selected = false;
           break;
         default:
           el.removeAttribute(key);
       }
     }
-    /**
-     * Set input value (preserving cursor position)
-     */
     _setValue(el, value) {
-      if (el.value === value)
-        return;
-      if (el.type === "text" || el.tagName === "TEXTAREA") {
+      if (el.value === value) return;
+      if (el.type === 'text' || el.tagName === 'TEXTAREA') {
         const start = el.selectionStart;
         const end = el.selectionEnd;
         el.value = value;
         if (document.activeElement === el) {
-          el.setSelectionRange(
-            Math.min(start, value.length),
-            Math.min(end, value.length)
-          );
+          el.setSelectionRange(Math.min(start, value.length), Math.min(end, value.length));
         }
       } else {
         el.value = value;
       }
     }
-    /**
-     * Insert node at index in parent
-     */
     _insertNode(parentHid, index, vnode) {
       const parentEl = this.client.getNode(parentHid);
       if (!parentEl) {
-        console.error("[Vango] INSERT_NODE parent not found (parentHID:", parentHid, ")");
+        console.error('[Vango] INSERT_NODE parent not found (parentHID:', parentHid, ')');
         this._triggerSelfHeal();
         return;
       }
+      recordPositions(parentEl, this._positions);
       const newEl = this._createNode(vnode);
-      if (index >= parentEl.children.length) {
-        parentEl.appendChild(newEl);
-      } else {
-        parentEl.insertBefore(newEl, parentEl.children[index]);
-      }
+      parentEl.insertBefore(newEl, this._childAt(parentEl, index));
+      enter(vnode.type === 'portal' ? this.client.getNode(vnode.hid) : newEl);
+    }
+    _childAt(parentEl, index) {
+      return liveChildren(parentEl)[index] || null;
     }
-    /**
-     * Remove node
-     */
     _removeNode(el, hid) {
+      var _a3;
+      this._cleanupNode(el, hid);
+      recordPositions(el.parentElement, this._positions);
+      (_a3 = portalAnchor(el)) == null ? void 0 : _a3.remove();
+      if (!leave(el, () => el.remove())) {
+        el.remove();
+      }
+    }
+    _cleanupNode(el, hid) {
       this.client.hooks.destroyForNode(el);
       this.client.unregisterNode(hid);
-      el.querySelectorAll("[data-hid]").forEach((child) => {
+      el.querySelectorAll('[data-hid]').forEach(child => {
         this.client.hooks.destroyForNode(child);
         this.client.unregisterNode(child.dataset.hid);
       });
-      el.remove();
+      for (const wrapper of nestedPortals(el, h => this.client.getNode(h))) {
+        this._removeNode(wrapper, wrapper.dataset.hid);
+      }
     }
-    /**
-     * Move node to new position
-     */
     _moveNode(el, parentHid, index) {
       const parentEl = this.client.getNode(parentHid);
       if (!parentEl) {
-        console.error("[Vango] MOVE_NODE parent not found (parentHID:", parentHid, ")");
+        console.error('[Vango] MOVE_NODE parent not found (parentHID:', parentHid, ')');
         this._triggerSelfHeal();
         return;
       }
-      if (index >= parentEl.children.length) {
-        parentEl.appendChild(el);
-      } else {
-        parentEl.insertBefore(el, parentEl.children[index]);
-      }
+      recordPositions(parentEl, this._positions);
+      const node = portalAnchor(el) || el;
+      parentEl.insertBefore(node, this._childAt(parentEl, index));
     }
-    /**
-     * Replace node
-     */
     _replaceNode(el, hid, vnode) {
-      this.client.hooks.destroyForNode(el);
-      this.client.unregisterNode(hid);
-      el.querySelectorAll("[data-hid]").forEach((child) => {
-        this.client.hooks.destroyForNode(child);
-        this.client.unregisterNode(child.dataset.hid);
-      });
+      this._cleanupNode(el, hid);
       const newEl = this._createNode(vnode);
-      el.replaceWith(newEl);
+      const anchor = portalAnchor(el);
+      if (anchor) {
+        anchor.replaceWith(newEl);
+        el.remove();
+      } else {
+        el.replaceWith(newEl);
+      }
+      enter(vnode.type === 'portal' ? this.client.getNode(vnode.hid) : newEl);
     }
-    /**
-     * Create DOM node from VNode
-     */
     _createNode(vnode) {
       switch (vnode.type) {
-        case "element":
+        case 'element':
           return this._createElement(vnode);
-        case "text":
+        case 'text':
           return document.createTextNode(vnode.text);
-        case "fragment":
+        case 'fragment':
           return this._createFragment(vnode);
+        case 'portal':
+          return this._createPortal(vnode);
         default:
           if (this.client.options.debug) {
-            console.warn("[Vango] Unknown vnode type:", vnode.type);
+            console.warn('[Vango] Unknown vnode type:', vnode.type);
           }
-          return document.createTextNode("");
+          return document.createTextNode('');
       }
     }
-    /**
-     * Create element from VNode
-     */
     _createElement(vnode) {
       const el = document.createElement(vnode.tag);
       for (const [key, value] of Object.entries(vnode.attrs || {})) {
@@ -1958,9 +3590,21 @@ var Vango = (() => {
       this.client.hooks.initializeForNode(el);
       return el;
     }
-    /**
-     * Create document fragment
-     */
+    _createPortal(vnode) {
+      const wrapper = document.createElement('vango-portal');
+      wrapper.dataset.portalTarget = vnode.target;
+      wrapper.style.display = 'contents';
+      if (vnode.hid) {
+        wrapper.dataset.hid = vnode.hid;
+        this.client.registerNode(vnode.hid, wrapper);
+      }
+      for (const child of vnode.children || []) {
+        wrapper.appendChild(this._createNode(child));
+      }
+      const anchor = createPortalAnchor(wrapper);
+      portalTarget(vnode.target).appendChild(wrapper);
+      return anchor;
+    }
     _createFragment(vnode) {
       const frag = document.createDocumentFragment();
       for (const child of vnode.children || []) {
@@ -1968,9 +3612,6 @@ var Vango = (() => {
       }
       return frag;
     }
-    /**
-     * Dispatch custom event
-     */
     _dispatchEvent(el, eventName, detail) {
       let parsedDetail = null;
       if (detail) {
@@ -1980,10 +3621,14 @@ var Vango = (() => {
           parsedDetail = detail;
         }
       }
-      if (eventName === "vango:navigate") {
+      if (eventName === 'vango:navigate') {
         this._handleNavigate(parsedDetail);
         return;
       }
+      if (eventName === 'vango:hash') {
+        this._handleHash(parsedDetail);
+        return;
+      }
       const target = el || document;
       const event = new CustomEvent(eventName, {
         detail: parsedDetail,
@@ -1992,91 +3637,127 @@ var Vango = (() => {
       });
       target.dispatchEvent(event);
     }
-    /**
-     * Handle server-initiated navigation via NAV_PUSH/NAV_REPLACE patches.
-     * This is the contract-compliant implementation that does NOT send
-     * EventNavigate back to the server (server already rendered the new page).
-     *
-     * Per spec Section 4.2 (Navigation Contract):
-     * 1. Receive NAV_* patch
-     * 2. Update history via pushState/replaceState with provided path
-     * 3. Apply subsequent DOM patches
-     * 4. Do NOT send EventNavigate back to server
-     */
+    _setHeadTag(tag, attr, key, valueAttr, value) {
+      const head = document.head;
+      if (!head || !attr || !key) return;
+      let el = null;
+      for (const candidate of head.querySelectorAll(tag)) {
+        if (candidate.getAttribute(attr) === key) {
+          el = candidate;
+          break;
+        }
+      }
+      if (!value) {
+        if (el) el.remove();
+        return;
+      }
+      if (!el) {
+        el = document.createElement(tag);
+        el.setAttribute(attr, key);
+        head.appendChild(el);
+      }
+      el.setAttribute(valueAttr, value);
+    }
     _applyNavPatch(patch) {
-      const { path, type } = patch;
-      if (!path || !path.startsWith("/")) {
-        console.error("[Vango] Invalid navigation path (must start with /):", path);
+      const {
+        path,
+        type
+      } = patch;
+      if (!path || !path.startsWith('/')) {
+        console.error('[Vango] Invalid navigation path (must start with /):', path);
         return;
       }
-      if (path.includes("://") || path.startsWith("//")) {
-        console.error("[Vango] Invalid navigation path (must be relative):", path);
+      if (path.includes('://') || path.startsWith('//')) {
+        console.error('[Vango] Invalid navigation path (must be relative):', path);
         return;
       }
       if (type === PatchType.NAV_REPLACE) {
-        history.replaceState(null, "", path);
+        history.replaceState(null, '', path);
       } else {
-        history.pushState(null, "", path);
+        history.pushState(null, '', path);
       }
       if (this.client.eventCapture) {
         this.client.eventCapture.pendingNavPath = null;
       }
-      window.scrollTo({ top: 0, behavior: "instant" });
+      window.scrollTo({
+        top: 0,
+        behavior: 'instant'
+      });
       if (this.client.options.debug) {
-        console.log("[Vango] Navigation applied:", path, type === PatchType.NAV_REPLACE ? "(replace)" : "(push)");
+        console.log('[Vango] Navigation applied:', path, type === PatchType.NAV_REPLACE ? '(replace)' : '(push)');
       }
     }
-    /**
-     * Handle server-initiated navigation via dispatch patch (DEPRECATED).
-     * This is the legacy mechanism using vango:navigate custom event.
-     * New code should use NAV_PUSH/NAV_REPLACE patches instead.
-     */
     _handleNavigate(data) {
       if (!data || !data.path) {
         if (this.client.options.debug) {
-          console.warn("[Vango] Invalid navigate data:", data);
+          console.warn('[Vango] Invalid navigate data:', data);
         }
         return;
       }
-      const { path, replace, scroll } = data;
+      const {
+        path,
+        replace,
+        scroll
+      } = data;
       if (replace) {
-        history.replaceState(null, "", path);
+        history.replaceState(null, '', path);
       } else {
-        history.pushState(null, "", path);
+        history.pushState(null, '', path);
       }
-      this.client.sendEvent(EventType.NAVIGATE, "nav", { path });
+      this.client.sendEvent(EventType.NAVIGATE, 'nav', {
+        path
+      });
       if (scroll !== false) {
-        window.scrollTo({ top: 0, behavior: "instant" });
+        window.scrollTo({
+          top: 0,
+          behavior: 'instant'
+        });
       }
       if (this.client.options.debug) {
-        console.log("[Vango] Navigated to:", path, { replace, scroll });
+        console.log('[Vango] Navigated to:', path, {
+          replace,
+          scroll
+        });
       }
     }
-    // NOTE: _evalCode method has been REMOVED for security.
-    // Executing arbitrary JS from server is an XSS/RCE risk.
-    // Use client-side hooks or custom events for safe JS interop.
-  };
-  __name(_PatchApplier, "PatchApplier");
-  var PatchApplier = _PatchApplier;
-
+    _handleHash(data) {
+      if (!data || typeof data.value !== 'string') {
+        if (this.client.options.debug) {
+          console.warn('[Vango] Invalid hash data:', data);
+        }
+        return;
+      }
+      const replace = !!data.replace;
+      const url = new URL(window.location);
+      if (data.value === '') {
+        url.hash = '';
+      } else if (data.value.startsWith('#')) {
+        url.hash = data.value;
+      } else {
+        url.hash = `#${data.value}`;
+      }
+      if (url.toString() === window.location.href) {
+        return;
+      }
+      if (replace) {
+        history.replaceState(null, '', url.toString());
+      } else {
+        history.pushState(null, '', url.toString());
+      }
+    }
+  }
+  __name(PatchApplier, "PatchApplier");
   // src/optimistic.js
-  var _OptimisticUpdates = class _OptimisticUpdates {
+  class OptimisticUpdates {
     constructor(client) {
       this.client = client;
-      this.pending = /* @__PURE__ */ new Map();
+      this.pending = new Map();
     }
-    /**
-     * Apply optimistic update based on element data attributes.
-     * Parses data-optimistic='{"class":"...","text":"...","attr":"...","value":"..."}' format
-     * per spec Section 5.2.
-     */
     applyOptimistic(el, eventType) {
       const hid = el.dataset.hid;
-      if (!hid)
-        return;
+      if (!hid) return;
       const optimisticData = el.dataset.optimistic;
-      if (!optimisticData)
-        return;
+      if (!optimisticData) return;
       try {
         const config = JSON.parse(optimisticData);
         if (config.class) {
@@ -2085,102 +3766,83 @@ var Vango = (() => {
         if (config.text) {
           this._applyTextOptimistic(el, hid, config.text);
         }
-        if (config.attr && config.value !== void 0) {
+        if (config.attr && config.value !== undefined) {
           this._applyAttrOptimistic(el, hid, config.attr, config.value);
         }
       } catch (e) {
-        console.warn("[Vango] Invalid optimistic config:", e);
+        console.warn('[Vango] Invalid optimistic config:', e);
       }
     }
-    /**
-     * Apply optimistic class toggle
-     * Format: "classname" or "classname:add" or "classname:remove" or "classname:toggle"
-     */
     _applyClassOptimistic(el, hid, classConfig) {
-      const [className, action = "toggle"] = classConfig.split(":");
+      const [className, action = 'toggle'] = classConfig.split(':');
       const original = el.classList.contains(className);
       switch (action) {
-        case "add":
+        case 'add':
           el.classList.add(className);
           break;
-        case "remove":
+        case 'remove':
           el.classList.remove(className);
           break;
-        case "toggle":
+        case 'toggle':
         default:
           el.classList.toggle(className);
       }
-      this._trackPending(hid, "class", className, original);
+      this._trackPending(hid, 'class', className, original);
     }
-    /**
-     * Apply optimistic text change
-     */
     _applyTextOptimistic(el, hid, text) {
       const original = el.textContent;
       el.textContent = text;
-      this._trackPending(hid, "text", null, original);
+      this._trackPending(hid, 'text', null, original);
     }
-    /**
-     * Apply optimistic attribute change
-     */
     _applyAttrOptimistic(el, hid, attr, value) {
-      if (attr.length > 2 && attr.substring(0, 2).toLowerCase() === "on") {
-        console.warn("[Vango] Blocked dangerous optimistic attribute:", attr);
+      if (attr.length > 2 && attr.substring(0, 2).toLowerCase() === 'on') {
+        console.warn('[Vango] Blocked dangerous optimistic attribute:', attr);
         return;
       }
       const original = el.getAttribute(attr);
-      if (value === "null" || value === "") {
+      if (value === 'null' || value === '') {
         el.removeAttribute(attr);
       } else {
         el.setAttribute(attr, value);
       }
-      this._trackPending(hid, "attr", attr, original);
+      this._trackPending(hid, 'attr', attr, original);
     }
-    /**
-     * Track pending optimistic update
-     */
     _trackPending(hid, type, key, original) {
       if (!this.pending.has(hid)) {
         this.pending.set(hid, []);
       }
-      this.pending.get(hid).push({ type, key, original });
+      this.pending.get(hid).push({
+        type,
+        key,
+        original
+      });
     }
-    /**
-     * Clear pending updates (called when server confirms)
-     */
     clearPending() {
       this.pending.clear();
     }
-    /**
-     * Revert all pending updates (called on error)
-     */
     revertAll() {
       for (const [hid, updates] of this.pending) {
         const el = this.client.getNode(hid);
-        if (!el)
-          continue;
+        if (!el) continue;
         for (const update of updates) {
           this._revertUpdate(el, update);
         }
       }
       this.pending.clear();
     }
-    /**
-     * Revert single update
-     */
     _revertUpdate(el, update) {
       switch (update.type) {
-        case "class":
+        case 'class':
           if (update.original) {
             el.classList.add(update.key);
           } else {
             el.classList.remove(update.key);
           }
           break;
-        case "text":
+        case 'text':
           el.textContent = update.original;
           break;
-        case "attr":
+        case 'attr':
           if (update.original === null) {
             el.removeAttribute(update.key);
           } else {
@@ -2189,20 +3851,18 @@ var Vango = (() => {
           break;
       }
     }
-  };
-  __name(_OptimisticUpdates, "OptimisticUpdates");
-  var OptimisticUpdates = _OptimisticUpdates;
-
+  }
+  __name(OptimisticUpdates, "OptimisticUpdates");
   // src/hooks/sortable.js
-  var _SortableHook = class _SortableHook {
+  class SortableHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
       this.pushEvent = pushEvent;
       this.animation = config.animation || 150;
       this.handle = config.handle || null;
-      this.ghostClass = config.ghostClass || "sortable-ghost";
-      this.dragClass = config.dragClass || "sortable-drag";
+      this.ghostClass = config.ghostClass || 'sortable-ghost';
+      this.dragClass = config.dragClass || 'sortable-drag';
       this.dragging = null;
       this.ghost = null;
       this.startIndex = -1;
@@ -2219,41 +3879,41 @@ var Vango = (() => {
       this._onMouseDown = this._handleMouseDown.bind(this);
       this._onMouseMove = this._handleMouseMove.bind(this);
       this._onMouseUp = this._handleMouseUp.bind(this);
-      this.el.addEventListener("mousedown", this._onMouseDown);
-      document.addEventListener("mousemove", this._onMouseMove);
-      document.addEventListener("mouseup", this._onMouseUp);
+      this.el.addEventListener('mousedown', this._onMouseDown);
+      document.addEventListener('mousemove', this._onMouseMove);
+      document.addEventListener('mouseup', this._onMouseUp);
       this._onTouchStart = this._handleTouchStart.bind(this);
       this._onTouchMove = this._handleTouchMove.bind(this);
       this._onTouchEnd = this._handleTouchEnd.bind(this);
-      this.el.addEventListener("touchstart", this._onTouchStart, { passive: false });
-      document.addEventListener("touchmove", this._onTouchMove, { passive: false });
-      document.addEventListener("touchend", this._onTouchEnd);
+      this.el.addEventListener('touchstart', this._onTouchStart, {
+        passive: false
+      });
+      document.addEventListener('touchmove', this._onTouchMove, {
+        passive: false
+      });
+      document.addEventListener('touchend', this._onTouchEnd);
     }
     _unbindEvents() {
-      this.el.removeEventListener("mousedown", this._onMouseDown);
-      document.removeEventListener("mousemove", this._onMouseMove);
-      document.removeEventListener("mouseup", this._onMouseUp);
-      this.el.removeEventListener("touchstart", this._onTouchStart);
-      document.removeEventListener("touchmove", this._onTouchMove);
-      document.removeEventListener("touchend", this._onTouchEnd);
+      this.el.removeEventListener('mousedown', this._onMouseDown);
+      document.removeEventListener('mousemove', this._onMouseMove);
+      document.removeEventListener('mouseup', this._onMouseUp);
+      this.el.removeEventListener('touchstart', this._onTouchStart);
+      document.removeEventListener('touchmove', this._onTouchMove);
+      document.removeEventListener('touchend', this._onTouchEnd);
     }
     _handleMouseDown(e) {
       var _a;
       const item = this._findItem(e.target);
-      if (!item)
-        return;
-      if (this.handle && (typeof ((_a = e.target) == null ? void 0 : _a.closest) !== "function" || !e.target.closest(this.handle)))
-        return;
+      if (!item) return;
+      if (this.handle && (typeof ((_a = e.target) == null ? void 0 : _a.closest) !== 'function' || !e.target.closest(this.handle))) return;
       e.preventDefault();
       this._startDrag(item, e.clientX, e.clientY);
     }
     _handleTouchStart(e) {
-      var _a;
+      var _a2;
       const item = this._findItem(e.target);
-      if (!item)
-        return;
-      if (this.handle && (typeof ((_a = e.target) == null ? void 0 : _a.closest) !== "function" || !e.target.closest(this.handle)))
-        return;
+      if (!item) return;
+      if (this.handle && (typeof ((_a2 = e.target) == null ? void 0 : _a2.closest) !== 'function' || !e.target.closest(this.handle))) return;
       e.preventDefault();
       const touch = e.touches[0];
       this._startDrag(item, touch.clientX, touch.clientY);
@@ -2278,28 +3938,26 @@ var Vango = (() => {
       this.ghostStartLeft = rect.left;
       this.ghost = item.cloneNode(true);
       this.ghost.classList.add(this.ghostClass);
-      this.ghost.style.position = "fixed";
-      this.ghost.style.zIndex = "9999";
+      this.ghost.style.position = 'fixed';
+      this.ghost.style.zIndex = '9999';
       this.ghost.style.width = `${item.offsetWidth}px`;
       this.ghost.style.height = `${item.offsetHeight}px`;
       this.ghost.style.left = `${this.ghostStartLeft}px`;
       this.ghost.style.top = `${this.ghostStartTop}px`;
-      this.ghost.style.pointerEvents = "none";
-      this.ghost.style.opacity = "0.8";
-      this.ghost.style.transition = "none";
+      this.ghost.style.pointerEvents = 'none';
+      this.ghost.style.opacity = '0.8';
+      this.ghost.style.transition = 'none';
       document.body.appendChild(this.ghost);
       item.classList.add(this.dragClass);
-      item.style.opacity = "0.4";
+      item.style.opacity = '0.4';
     }
     _handleMouseMove(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       e.preventDefault();
       this._updateDrag(e.clientX, e.clientY);
     }
     _handleTouchMove(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       e.preventDefault();
       const touch = e.touches[0];
       this._updateDrag(touch.clientX, touch.clientY);
@@ -2311,9 +3969,9 @@ var Vango = (() => {
         this.ghost.style.top = `${this.ghostStartTop + deltaY}px`;
         this.ghost.style.left = `${this.ghostStartLeft + deltaX}px`;
       }
-      this.ghost.style.display = "none";
+      this.ghost.style.display = 'none';
       const elUnderCursor = document.elementFromPoint(x, y);
-      this.ghost.style.display = "";
+      this.ghost.style.display = '';
       if (elUnderCursor) {
         const targetContainer = elUnderCursor.closest('[data-hook="Sortable"]');
         if (targetContainer && targetContainer !== this.activeContainer && targetContainer.dataset.hookConfig) {
@@ -2323,15 +3981,13 @@ var Vango = (() => {
               this.activeContainer = targetContainer;
               this.activeContainer.appendChild(this.dragging);
             }
-          } catch (e) {
-          }
+          } catch (e) {}
         }
       }
       const children = Array.from(this.activeContainer.children);
       const currentIndex = children.indexOf(this.dragging);
       for (let i = 0; i < children.length; i++) {
-        if (i === currentIndex)
-          continue;
+        if (i === currentIndex) continue;
         const child = children[i];
         const rect = child.getBoundingClientRect();
         const midpoint = rect.top + rect.height / 2;
@@ -2345,49 +4001,56 @@ var Vango = (() => {
       }
     }
     _handleMouseUp(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       this._endDrag();
     }
     _handleTouchEnd(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       this._endDrag();
     }
     _endDrag() {
       const endIndex = Array.from(this.activeContainer.children).indexOf(this.dragging);
-      const id = this.dragging.dataset.id || this.dragging.dataset.hid;
-      const targetContainerHid = this.activeContainer.dataset.hid;
+      const itemId = this.dragging.dataset.id || this.dragging.dataset.itemId || this.dragging.dataset.hid;
+      const fromContainerId = this.initialContainer.dataset.id || this.initialContainer.dataset.listId || this.initialContainer.dataset.containerId || this.initialContainer.dataset.hid;
+      const toContainerId = this.activeContainer.dataset.id || this.activeContainer.dataset.listId || this.activeContainer.dataset.containerId || this.activeContainer.dataset.hid;
+      const initialContainer = this.initialContainer;
+      const initialIndex = this.startIndex;
+      const draggedEl = this.dragging;
       this.dragging.classList.remove(this.dragClass);
-      this.dragging.style.opacity = "";
+      this.dragging.style.opacity = '';
       if (this.ghost) {
         this.ghost.remove();
       }
       if (this.activeContainer !== this.initialContainer || endIndex !== this.startIndex) {
-        this.pushEvent("reorder", {
-          id,
-          fromContainer: this.initialContainer.dataset.id || this.initialContainer.dataset.hid,
-          toContainer: this.activeContainer.dataset.id || this.activeContainer.dataset.hid,
+        const revertFn = () => {
+          if (!initialContainer || !draggedEl) return;
+          const children = Array.from(initialContainer.children);
+          const ref = children[initialIndex] || null;
+          initialContainer.insertBefore(draggedEl, ref);
+        };
+        this.pushEvent('reorder', {
+          id: itemId,
+          itemId: itemId,
+          fromContainerId,
+          toContainerId,
           fromIndex: this.startIndex,
           toIndex: endIndex
-        });
+        }, revertFn);
       }
       this.dragging = null;
       this.ghost = null;
       this.activeContainer = null;
       this.initialContainer = null;
     }
-  };
-  __name(_SortableHook, "SortableHook");
-  var SortableHook = _SortableHook;
-
+  }
+  __name(SortableHook, "SortableHook");
   // src/hooks/draggable.js
-  var _DraggableHook = class _DraggableHook {
+  class DraggableHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
       this.pushEvent = pushEvent;
-      this.axis = config.axis || "both";
+      this.axis = config.axis || 'both';
       this.handle = config.handle || null;
       this.bounds = config.bounds || null;
       this.dragging = false;
@@ -2400,7 +4063,7 @@ var Vango = (() => {
     updated(el, config, pushEvent) {
       this.config = config;
       this.pushEvent = pushEvent;
-      this.axis = config.axis || "both";
+      this.axis = config.axis || 'both';
       this.bounds = config.bounds || null;
     }
     destroyed(el) {
@@ -2412,29 +4075,33 @@ var Vango = (() => {
       this._onMouseUp = this._handleMouseUp.bind(this);
       const handleEl = this.handle ? this.el.querySelector(this.handle) : this.el;
       if (handleEl) {
-        handleEl.addEventListener("mousedown", this._onMouseDown);
+        handleEl.addEventListener('mousedown', this._onMouseDown);
       }
-      document.addEventListener("mousemove", this._onMouseMove);
-      document.addEventListener("mouseup", this._onMouseUp);
+      document.addEventListener('mousemove', this._onMouseMove);
+      document.addEventListener('mouseup', this._onMouseUp);
       this._onTouchStart = this._handleTouchStart.bind(this);
       this._onTouchMove = this._handleTouchMove.bind(this);
       this._onTouchEnd = this._handleTouchEnd.bind(this);
       if (handleEl) {
-        handleEl.addEventListener("touchstart", this._onTouchStart, { passive: false });
+        handleEl.addEventListener('touchstart', this._onTouchStart, {
+          passive: false
+        });
       }
-      document.addEventListener("touchmove", this._onTouchMove, { passive: false });
-      document.addEventListener("touchend", this._onTouchEnd);
+      document.addEventListener('touchmove', this._onTouchMove, {
+        passive: false
+      });
+      document.addEventListener('touchend', this._onTouchEnd);
     }
     _unbindEvents() {
       const handleEl = this.handle ? this.el.querySelector(this.handle) : this.el;
       if (handleEl) {
-        handleEl.removeEventListener("mousedown", this._onMouseDown);
-        handleEl.removeEventListener("touchstart", this._onTouchStart);
+        handleEl.removeEventListener('mousedown', this._onMouseDown);
+        handleEl.removeEventListener('touchstart', this._onTouchStart);
       }
-      document.removeEventListener("mousemove", this._onMouseMove);
-      document.removeEventListener("mouseup", this._onMouseUp);
-      document.removeEventListener("touchmove", this._onTouchMove);
-      document.removeEventListener("touchend", this._onTouchEnd);
+      document.removeEventListener('mousemove', this._onMouseMove);
+      document.removeEventListener('mouseup', this._onMouseUp);
+      document.removeEventListener('touchmove', this._onTouchMove);
+      document.removeEventListener('touchend', this._onTouchEnd);
     }
     _handleMouseDown(e) {
       e.preventDefault();
@@ -2449,17 +4116,15 @@ var Vango = (() => {
       this.dragging = true;
       this.startX = x - this.currentX;
       this.startY = y - this.currentY;
-      this.el.classList.add("dragging");
+      this.el.classList.add('dragging');
     }
     _handleMouseMove(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       e.preventDefault();
       this._updatePosition(e.clientX, e.clientY);
     }
     _handleTouchMove(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       e.preventDefault();
       const touch = e.touches[0];
       this._updatePosition(touch.clientX, touch.clientY);
@@ -2467,9 +4132,9 @@ var Vango = (() => {
     _updatePosition(x, y) {
       let newX = x - this.startX;
       let newY = y - this.startY;
-      if (this.axis === "x") {
+      if (this.axis === 'x') {
         newY = this.currentY;
-      } else if (this.axis === "y") {
+      } else if (this.axis === 'y') {
         newX = this.currentX;
       }
       if (this.bounds) {
@@ -2484,7 +4149,7 @@ var Vango = (() => {
     _getBounds() {
       const rect = this.el.getBoundingClientRect();
       let container;
-      if (this.bounds === "parent") {
+      if (this.bounds === 'parent') {
         container = this.el.parentElement.getBoundingClientRect();
       } else {
         container = {
@@ -2502,97 +4167,91 @@ var Vango = (() => {
       };
     }
     _handleMouseUp(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       this._endDrag();
     }
     _handleTouchEnd(e) {
-      if (!this.dragging)
-        return;
+      if (!this.dragging) return;
       this._endDrag();
     }
     _endDrag() {
       this.dragging = false;
-      this.el.classList.remove("dragging");
-      this.pushEvent("position", {
+      this.el.classList.remove('dragging');
+      this.pushEvent('position', {
         x: this.currentX,
         y: this.currentY
       });
     }
-  };
-  __name(_DraggableHook, "DraggableHook");
-  var DraggableHook = _DraggableHook;
-
+  }
+  __name(DraggableHook, "DraggableHook");
   // src/hooks/droppable.js
-  var _DroppableHook = class _DroppableHook {
+  class DroppableHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
       this.pushEvent = pushEvent;
-      this.hoverClass = config.hoverClass || "drag-over";
+      this.hoverClass = config.hoverClass || 'drag-over';
       this._bind();
     }
     updated(el, config, pushEvent) {
       this.config = config;
       this.pushEvent = pushEvent;
-      this.hoverClass = config.hoverClass || "drag-over";
+      this.hoverClass = config.hoverClass || 'drag-over';
     }
     destroyed() {
       this._unbind();
     }
     _bind() {
-      this._enter = (e) => {
+      this._enter = e => {
         e.preventDefault();
         this.el.classList.add(this.hoverClass);
       };
-      this._over = (e) => {
+      this._over = e => {
         e.preventDefault();
-        e.dataTransfer.dropEffect = "move";
+        e.dataTransfer.dropEffect = 'move';
       };
-      this._leave = (e) => {
-        if (!this.el.contains(e.relatedTarget))
-          this.el.classList.remove(this.hoverClass);
+      this._leave = e => {
+        if (!this.el.contains(e.relatedTarget)) this.el.classList.remove(this.hoverClass);
       };
-      this._drop = (e) => {
-        var _a, _b;
+      this._drop = e => {
+        var _a, _a2;
         e.preventDefault();
         this.el.classList.remove(this.hoverClass);
-        const data = { x: e.clientX, y: e.clientY };
+        const data = {
+          x: e.clientX,
+          y: e.clientY
+        };
         if (window.__vango_dragging__) {
           const d = window.__vango_dragging__;
           data.id = d.dataset.id || d.dataset.hid;
           window.__vango_dragging__ = null;
         }
         const files = (_a = e.dataTransfer) == null ? void 0 : _a.files;
-        if (files == null ? void 0 : files.length)
-          data.fileNames = Array.from(files).map((f) => f.name);
-        const text = (_b = e.dataTransfer) == null ? void 0 : _b.getData("text/plain");
-        if (text)
-          data.text = text;
-        this.pushEvent("drop", data);
+        if (files == null ? void 0 : files.length) data.fileNames = Array.from(files).map(f => f.name);
+        const text = (_a2 = e.dataTransfer) == null ? void 0 : _a2.getData('text/plain');
+        if (text) data.text = text;
+        this.pushEvent('drop', data);
       };
-      this.el.addEventListener("dragenter", this._enter);
-      this.el.addEventListener("dragover", this._over);
-      this.el.addEventListener("dragleave", this._leave);
-      this.el.addEventListener("drop", this._drop);
+      this.el.addEventListener('dragenter', this._enter);
+      this.el.addEventListener('dragover', this._over);
+      this.el.addEventListener('dragleave', this._leave);
+      this.el.addEventListener('drop', this._drop);
     }
     _unbind() {
-      this.el.removeEventListener("dragenter", this._enter);
-      this.el.removeEventListener("dragover", this._over);
-      this.el.removeEventListener("dragleave", this._leave);
-      this.el.removeEventListener("drop", this._drop);
+      this.el.removeEventListener('dragenter', this._enter);
+      this.el.removeEventListener('dragover', this._over);
+      this.el.removeEventListener('dragleave', this._leave);
+      this.el.removeEventListener('drop', this._drop);
     }
-  };
-  __name(_DroppableHook, "DroppableHook");
-  var DroppableHook = _DroppableHook;
-
+  }
+  __name(DroppableHook, "DroppableHook");
   // src/hooks/resizable.js
-  var _ResizableHook = class _ResizableHook {
+  class ResizableHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
       this.pushEvent = pushEvent;
-      this.handles = (config.handles || "se").split(",").map((h) => h.trim());
+      this.handles = (config.handles || 'se').split(',').map(h => h.trim());
       this.resizing = false;
       this._handleEls = [];
       this._createHandles();
@@ -2604,17 +4263,25 @@ var Vango = (() => {
     }
     destroyed() {
       this._unbind();
-      this._handleEls.forEach((h) => h.remove());
+      this._handleEls.forEach(h => h.remove());
     }
     _createHandles() {
-      if (getComputedStyle(this.el).position === "static") {
-        this.el.style.position = "relative";
-      }
-      const cursors = { n: "n", s: "s", e: "e", w: "w", ne: "ne", se: "se", sw: "sw", nw: "nw" };
+      if (getComputedStyle(this.el).position === 'static') {
+        this.el.style.position = 'relative';
+      }
+      const cursors = {
+        n: 'n',
+        s: 's',
+        e: 'e',
+        w: 'w',
+        ne: 'ne',
+        se: 'se',
+        sw: 'sw',
+        nw: 'nw'
+      };
       for (const h of this.handles) {
-        if (!cursors[h])
-          continue;
-        const el = document.createElement("div");
+        if (!cursors[h]) continue;
+        const el = document.createElement('div');
         el.dataset.handle = h;
         el.style.cssText = `position:absolute;z-index:10;cursor:${h}-resize;` + this._pos(h);
         this.el.appendChild(el);
@@ -2622,7 +4289,8 @@ var Vango = (() => {
       }
     }
     _pos(h) {
-      const s = 8, hs = 4;
+      const s = 8,
+        hs = 4;
       const m = {
         n: `top:-${hs}px;left:25%;width:50%;height:${s}px`,
         s: `bottom:-${hs}px;left:25%;width:50%;height:${s}px`,
@@ -2633,31 +4301,30 @@ var Vango = (() => {
         sw: `bottom:-${hs}px;left:-${hs}px;width:${s}px;height:${s}px`,
         nw: `top:-${hs}px;left:-${hs}px;width:${s}px;height:${s}px`
       };
-      return m[h] || "";
+      return m[h] || '';
     }
     _bind() {
-      this._onDown = (e) => {
+      this._onDown = e => {
         e.preventDefault();
         this._start(e.target.dataset.handle, e.clientX, e.clientY);
       };
-      this._onMove = (e) => {
+      this._onMove = e => {
         if (this.resizing) {
           e.preventDefault();
           this._update(e.clientX, e.clientY);
         }
       };
       this._onUp = () => {
-        if (this.resizing)
-          this._end();
+        if (this.resizing) this._end();
       };
-      this._handleEls.forEach((h) => h.addEventListener("mousedown", this._onDown));
-      document.addEventListener("mousemove", this._onMove);
-      document.addEventListener("mouseup", this._onUp);
+      this._handleEls.forEach(h => h.addEventListener('mousedown', this._onDown));
+      document.addEventListener('mousemove', this._onMove);
+      document.addEventListener('mouseup', this._onUp);
     }
     _unbind() {
-      this._handleEls.forEach((h) => h.removeEventListener("mousedown", this._onDown));
-      document.removeEventListener("mousemove", this._onMove);
-      document.removeEventListener("mouseup", this._onUp);
+      this._handleEls.forEach(h => h.removeEventListener('mousedown', this._onDown));
+      document.removeEventListener('mousemove', this._onMove);
+      document.removeEventListener('mouseup', this._onUp);
     }
     _start(handle, x, y) {
       this.resizing = true;
@@ -2669,45 +4336,44 @@ var Vango = (() => {
       this._sh = r.height;
     }
     _update(x, y) {
-      const dx = x - this._sx, dy = y - this._sy;
-      let w = this._sw, h = this._sh;
-      if (this._h.includes("e"))
-        w = this._sw + dx;
-      if (this._h.includes("w"))
-        w = this._sw - dx;
-      if (this._h.includes("s"))
-        h = this._sh + dy;
-      if (this._h.includes("n"))
-        h = this._sh - dy;
+      const dx = x - this._sx,
+        dy = y - this._sy;
+      let w = this._sw,
+        h = this._sh;
+      if (this._h.includes('e')) w = this._sw + dx;
+      if (this._h.includes('w')) w = this._sw - dx;
+      if (this._h.includes('s')) h = this._sh + dy;
+      if (this._h.includes('n')) h = this._sh - dy;
       const c = this.config;
       w = Math.max(c.minWidth || 0, Math.min(c.maxWidth || Infinity, w));
       h = Math.max(c.minHeight || 0, Math.min(c.maxHeight || Infinity, h));
-      this.el.style.width = w + "px";
-      this.el.style.height = h + "px";
+      this.el.style.width = w + 'px';
+      this.el.style.height = h + 'px';
     }
     _end() {
       this.resizing = false;
       const r = this.el.getBoundingClientRect();
-      this.pushEvent("resize", { width: Math.round(r.width), height: Math.round(r.height) });
+      this.pushEvent('resize', {
+        width: Math.round(r.width),
+        height: Math.round(r.height)
+      });
     }
-  };
-  __name(_ResizableHook, "ResizableHook");
-  var ResizableHook = _ResizableHook;
-
+  }
+  __name(ResizableHook, "ResizableHook");
   // src/hooks/tooltip.js
-  var _TooltipHook = class _TooltipHook {
+  class TooltipHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
       this.tooltip = null;
-      this.content = config.content || "";
-      this.placement = config.placement || "top";
+      this.content = config.content || '';
+      this.placement = config.placement || 'top';
       this.delay = config.delay || 200;
       this._bindEvents();
     }
     updated(el, config, pushEvent) {
-      this.content = config.content || "";
-      this.placement = config.placement || "top";
+      this.content = config.content || '';
+      this.placement = config.placement || 'top';
       if (this.tooltip) {
         this.tooltip.textContent = this.content;
       }
@@ -2719,12 +4385,12 @@ var Vango = (() => {
     _bindEvents() {
       this._onMouseEnter = this._handleMouseEnter.bind(this);
       this._onMouseLeave = this._handleMouseLeave.bind(this);
-      this.el.addEventListener("mouseenter", this._onMouseEnter);
-      this.el.addEventListener("mouseleave", this._onMouseLeave);
+      this.el.addEventListener('mouseenter', this._onMouseEnter);
+      this.el.addEventListener('mouseleave', this._onMouseLeave);
     }
     _unbindEvents() {
-      this.el.removeEventListener("mouseenter", this._onMouseEnter);
-      this.el.removeEventListener("mouseleave", this._onMouseLeave);
+      this.el.removeEventListener('mouseenter', this._onMouseEnter);
+      this.el.removeEventListener('mouseleave', this._onMouseLeave);
     }
     _handleMouseEnter() {
       this._showTimer = setTimeout(() => {
@@ -2736,10 +4402,9 @@ var Vango = (() => {
       this._hideTooltip();
     }
     _showTooltip() {
-      if (!this.content)
-        return;
-      this.tooltip = document.createElement("div");
-      this.tooltip.className = "vango-tooltip";
+      if (!this.content) return;
+      this.tooltip = document.createElement('div');
+      this.tooltip.className = 'vango-tooltip';
       this.tooltip.textContent = this.content;
       this.tooltip.style.cssText = `
             position: fixed;
@@ -2756,25 +4421,24 @@ var Vango = (() => {
       this._position();
     }
     _position() {
-      if (!this.tooltip)
-        return;
+      if (!this.tooltip) return;
       const rect = this.el.getBoundingClientRect();
       const tipRect = this.tooltip.getBoundingClientRect();
       let top, left;
       switch (this.placement) {
-        case "top":
+        case 'top':
           top = rect.top - tipRect.height - 8;
           left = rect.left + (rect.width - tipRect.width) / 2;
           break;
-        case "bottom":
+        case 'bottom':
           top = rect.bottom + 8;
           left = rect.left + (rect.width - tipRect.width) / 2;
           break;
-        case "left":
+        case 'left':
           top = rect.top + (rect.height - tipRect.height) / 2;
           left = rect.left - tipRect.width - 8;
           break;
-        case "right":
+        case 'right':
           top = rect.top + (rect.height - tipRect.height) / 2;
           left = rect.right + 8;
           break;
@@ -2791,12 +4455,10 @@ var Vango = (() => {
         this.tooltip = null;
       }
     }
-  };
-  __name(_TooltipHook, "TooltipHook");
-  var TooltipHook = _TooltipHook;
-
+  }
+  __name(TooltipHook, "TooltipHook");
   // src/hooks/dropdown.js
-  var _DropdownHook = class _DropdownHook {
+  class DropdownHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
@@ -2816,35 +4478,31 @@ var Vango = (() => {
       this._onClickOutside = this._handleClickOutside.bind(this);
       this._onKeyDown = this._handleKeyDown.bind(this);
       setTimeout(() => {
-        document.addEventListener("click", this._onClickOutside);
-        document.addEventListener("keydown", this._onKeyDown);
+        document.addEventListener('click', this._onClickOutside);
+        document.addEventListener('keydown', this._onKeyDown);
       }, 0);
     }
     _unbindEvents() {
-      document.removeEventListener("click", this._onClickOutside);
-      document.removeEventListener("keydown", this._onKeyDown);
+      document.removeEventListener('click', this._onClickOutside);
+      document.removeEventListener('keydown', this._onKeyDown);
     }
     _handleClickOutside(e) {
-      if (!this.closeOnClickOutside)
-        return;
+      if (!this.closeOnClickOutside) return;
       if (!this.el.contains(e.target)) {
-        this.pushEvent("close", {});
+        this.pushEvent('close', {});
       }
     }
     _handleKeyDown(e) {
-      if (!this.closeOnEscape)
-        return;
-      if (e.key === "Escape") {
+      if (!this.closeOnEscape) return;
+      if (e.key === 'Escape') {
         e.preventDefault();
-        this.pushEvent("close", {});
+        this.pushEvent('close', {});
       }
     }
-  };
-  __name(_DropdownHook, "DropdownHook");
-  var DropdownHook = _DropdownHook;
-
+  }
+  __name(DropdownHook, "DropdownHook");
   // src/hooks/collapsible.js
-  var _CollapsibleHook = class _CollapsibleHook {
+  class CollapsibleHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
@@ -2853,8 +4511,8 @@ var Vango = (() => {
       this.isOpen = !!config.open;
       this.animating = false;
       if (!this.isOpen) {
-        el.style.height = "0";
-        el.style.overflow = "hidden";
+        el.style.height = '0';
+        el.style.overflow = 'hidden';
       }
     }
     updated(el, config, pushEvent) {
@@ -2865,59 +4523,58 @@ var Vango = (() => {
         newOpen ? this._expand() : this._collapse();
       }
     }
-    destroyed() {
-    }
+    destroyed() {}
     _expand() {
-      if (this.animating || this.isOpen)
-        return;
+      if (this.animating || this.isOpen) return;
       this.animating = true;
       this.isOpen = true;
       const el = this.el;
-      el.style.height = "auto";
+      el.style.height = 'auto';
       const h = el.scrollHeight;
-      el.style.height = "0";
-      el.style.overflow = "hidden";
+      el.style.height = '0';
+      el.style.overflow = 'hidden';
       el.offsetHeight;
       el.style.transition = `height ${this.duration}ms ease`;
-      el.style.height = h + "px";
+      el.style.height = h + 'px';
       setTimeout(() => {
-        el.style.transition = "";
-        el.style.height = "auto";
-        el.style.overflow = "";
+        el.style.transition = '';
+        el.style.height = 'auto';
+        el.style.overflow = '';
         this.animating = false;
-        this.pushEvent("toggle", { open: true });
+        this.pushEvent('toggle', {
+          open: true
+        });
       }, this.duration);
     }
     _collapse() {
-      if (this.animating || !this.isOpen)
-        return;
+      if (this.animating || !this.isOpen) return;
       this.animating = true;
       this.isOpen = false;
       const el = this.el;
-      el.style.height = el.scrollHeight + "px";
-      el.style.overflow = "hidden";
+      el.style.height = el.scrollHeight + 'px';
+      el.style.overflow = 'hidden';
       el.offsetHeight;
       el.style.transition = `height ${this.duration}ms ease`;
-      el.style.height = "0";
+      el.style.height = '0';
       setTimeout(() => {
-        el.style.transition = "";
+        el.style.transition = '';
         this.animating = false;
-        this.pushEvent("toggle", { open: false });
+        this.pushEvent('toggle', {
+          open: false
+        });
       }, this.duration);
     }
-  };
-  __name(_CollapsibleHook, "CollapsibleHook");
-  var CollapsibleHook = _CollapsibleHook;
-
+  }
+  __name(CollapsibleHook, "CollapsibleHook");
   // src/hooks/focustrap.js
-  var _FocusTrapHook = class _FocusTrapHook {
+  class FocusTrapHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.pushEvent = pushEvent;
       this.active = config.active !== false;
       this.restoreFocusTo = document.activeElement;
       this._onKeyDown = this._handleKeyDown.bind(this);
-      this.el.addEventListener("keydown", this._onKeyDown);
+      this.el.addEventListener('keydown', this._onKeyDown);
       if (this.active) {
         this._focusFirst();
       }
@@ -2927,14 +4584,13 @@ var Vango = (() => {
       this.pushEvent = pushEvent;
     }
     destroyed(el) {
-      this.el.removeEventListener("keydown", this._onKeyDown);
-      if (this.restoreFocusTo && typeof this.restoreFocusTo.focus === "function") {
+      this.el.removeEventListener('keydown', this._onKeyDown);
+      if (this.restoreFocusTo && typeof this.restoreFocusTo.focus === 'function') {
         this.restoreFocusTo.focus();
       }
     }
     _handleKeyDown(e) {
-      if (!this.active || e.key !== "Tab")
-        return;
+      if (!this.active || e.key !== 'Tab') return;
       const focusable = this._getFocusableElements();
       if (focusable.length === 0) {
         e.preventDefault();
@@ -2959,34 +4615,30 @@ var Vango = (() => {
       if (focusable.length > 0) {
         focusable[0].focus();
       } else {
-        this.el.setAttribute("tabindex", "-1");
+        this.el.setAttribute('tabindex', '-1');
         this.el.focus();
       }
     }
     _getFocusableElements() {
-      return this.el.querySelectorAll(
-        'button:not([disabled]), [href], input:not([disabled]), select:not([disabled]), textarea:not([disabled]), [tabindex]:not([tabindex="-1"])'
-      );
+      return this.el.querySelectorAll('button:not([disabled]), ' + '[href], ' + 'input:not([disabled]), ' + 'select:not([disabled]), ' + 'textarea:not([disabled]), ' + '[tabindex]:not([tabindex="-1"])');
     }
-  };
-  __name(_FocusTrapHook, "FocusTrapHook");
-  var FocusTrapHook = _FocusTrapHook;
-
+  }
+  __name(FocusTrapHook, "FocusTrapHook");
   // src/hooks/portal.js
-  var _PortalHook = class _PortalHook {
+  class PortalHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.pushEvent = pushEvent;
-      this.target = config.target || "body";
-      this.placeholder = document.createComment("portal-placeholder");
+      this.target = config.target || 'body';
+      this.placeholder = document.createComment('portal-placeholder');
       if (el.parentNode) {
         el.parentNode.insertBefore(this.placeholder, el);
       }
-      let portalRoot = document.getElementById("vango-portal-root");
+      let portalRoot = document.getElementById('vango-portal-root');
       if (!portalRoot) {
-        portalRoot = document.createElement("div");
-        portalRoot.id = "vango-portal-root";
-        portalRoot.style.cssText = "position: relative; z-index: 9999;";
+        portalRoot = document.createElement('div');
+        portalRoot.id = 'vango-portal-root';
+        portalRoot.style.cssText = 'position: relative; z-index: 9999;';
         document.body.appendChild(portalRoot);
       }
       portalRoot.appendChild(el);
@@ -3000,25 +4652,22 @@ var Vango = (() => {
         this.placeholder.remove();
       }
     }
-  };
-  __name(_PortalHook, "PortalHook");
-  var PortalHook = _PortalHook;
+  }
+  __name(PortalHook, "PortalHook");
   function ensurePortalRoot() {
-    if (typeof document === "undefined")
-      return;
-    let portalRoot = document.getElementById("vango-portal-root");
+    if (typeof document === 'undefined') return;
+    let portalRoot = document.getElementById('vango-portal-root');
     if (!portalRoot) {
-      portalRoot = document.createElement("div");
-      portalRoot.id = "vango-portal-root";
-      portalRoot.style.cssText = "position: relative; z-index: 9999;";
+      portalRoot = document.createElement('div');
+      portalRoot.id = 'vango-portal-root';
+      portalRoot.style.cssText = 'position: relative; z-index: 9999;';
       document.body.appendChild(portalRoot);
     }
     return portalRoot;
   }
   __name(ensurePortalRoot, "ensurePortalRoot");
-
   // src/hooks/dialog.js
-  var _DialogHook = class _DialogHook {
+  class DialogHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
@@ -3026,41 +4675,41 @@ var Vango = (() => {
       this.previousFocus = document.activeElement;
       this._onKeyDown = this._handleKeyDown.bind(this);
       this._onClick = this._handleClick.bind(this);
-      document.addEventListener("keydown", this._onKeyDown);
+      document.addEventListener('keydown', this._onKeyDown);
       if (config.closeOnOutside !== false) {
         setTimeout(() => {
-          document.addEventListener("click", this._onClick);
+          document.addEventListener('click', this._onClick);
         }, 0);
       }
       this._focusFirst();
       this._originalOverflow = document.body.style.overflow;
-      document.body.style.overflow = "hidden";
+      document.body.style.overflow = 'hidden';
     }
     updated(el, config, pushEvent) {
       this.config = config;
       this.pushEvent = pushEvent;
     }
     destroyed(el) {
-      document.removeEventListener("keydown", this._onKeyDown);
-      document.removeEventListener("click", this._onClick);
-      document.body.style.overflow = this._originalOverflow || "";
-      if (this.previousFocus && typeof this.previousFocus.focus === "function") {
+      document.removeEventListener('keydown', this._onKeyDown);
+      document.removeEventListener('click', this._onClick);
+      document.body.style.overflow = this._originalOverflow || '';
+      if (this.previousFocus && typeof this.previousFocus.focus === 'function') {
         this.previousFocus.focus();
       }
     }
     _handleKeyDown(e) {
-      if (e.key === "Escape" && this.config.closeOnEscape !== false) {
+      if (e.key === 'Escape' && this.config.closeOnEscape !== false) {
         e.preventDefault();
-        this.pushEvent("close", {});
+        this.pushEvent('close', {});
         return;
       }
-      if (e.key === "Tab") {
+      if (e.key === 'Tab') {
         this._trapFocus(e);
       }
     }
     _handleClick(e) {
       if (!this.el.contains(e.target)) {
-        this.pushEvent("close", {});
+        this.pushEvent('close', {});
       }
     }
     _focusFirst() {
@@ -3075,7 +4724,7 @@ var Vango = (() => {
       if (focusable.length > 0) {
         focusable[0].focus();
       } else {
-        this.el.setAttribute("tabindex", "-1");
+        this.el.setAttribute('tabindex', '-1');
         this.el.focus();
       }
     }
@@ -3100,22 +4749,18 @@ var Vango = (() => {
       }
     }
     _getFocusableElements() {
-      return this.el.querySelectorAll(
-        'button:not([disabled]), [href], input:not([disabled]), select:not([disabled]), textarea:not([disabled]), [tabindex]:not([tabindex="-1"])'
-      );
+      return this.el.querySelectorAll('button:not([disabled]), ' + '[href], ' + 'input:not([disabled]), ' + 'select:not([disabled]), ' + 'textarea:not([disabled]), ' + '[tabindex]:not([tabindex="-1"])');
     }
-  };
-  __name(_DialogHook, "DialogHook");
-  var DialogHook = _DialogHook;
-
+  }
+  __name(DialogHook, "DialogHook");
   // src/hooks/popover.js
-  var _PopoverHook = class _PopoverHook {
+  class PopoverHook {
     mounted(el, config, pushEvent) {
       this.el = el;
       this.config = config;
       this.pushEvent = pushEvent;
-      this.trigger = el.querySelector("[data-popover-trigger]");
-      this.content = el.querySelector("[data-popover-content]");
+      this.trigger = el.querySelector('[data-popover-trigger]');
+      this.content = el.querySelector('[data-popover-content]');
       if (!this.content) {
         this.content = el;
       }
@@ -3123,14 +4768,14 @@ var Vango = (() => {
       this._onClick = this._handleClick.bind(this);
       this._onScroll = this._handleScroll.bind(this);
       this._onResize = this._handleResize.bind(this);
-      document.addEventListener("keydown", this._onKeyDown);
+      document.addEventListener('keydown', this._onKeyDown);
       if (config.closeOnOutside !== false) {
         setTimeout(() => {
-          document.addEventListener("click", this._onClick);
+          document.addEventListener('click', this._onClick);
         }, 0);
       }
-      window.addEventListener("scroll", this._onScroll, true);
-      window.addEventListener("resize", this._onResize);
+      window.addEventListener('scroll', this._onScroll, true);
+      window.addEventListener('resize', this._onResize);
       this._position();
     }
     updated(el, config, pushEvent) {
@@ -3139,20 +4784,20 @@ var Vango = (() => {
       this._position();
     }
     destroyed(el) {
-      document.removeEventListener("keydown", this._onKeyDown);
-      document.removeEventListener("click", this._onClick);
-      window.removeEventListener("scroll", this._onScroll, true);
-      window.removeEventListener("resize", this._onResize);
+      document.removeEventListener('keydown', this._onKeyDown);
+      document.removeEventListener('click', this._onClick);
+      window.removeEventListener('scroll', this._onScroll, true);
+      window.removeEventListener('resize', this._onResize);
     }
     _handleKeyDown(e) {
-      if (e.key === "Escape" && this.config.closeOnEscape !== false) {
+      if (e.key === 'Escape' && this.config.closeOnEscape !== false) {
         e.preventDefault();
-        this.pushEvent("close", {});
+        this.pushEvent('close', {});
       }
     }
     _handleClick(e) {
       if (!this.el.contains(e.target)) {
-        this.pushEvent("close", {});
+        this.pushEvent('close', {});
       }
     }
     _handleScroll() {
@@ -3162,209 +4807,399 @@ var Vango = (() => {
       this._position();
     }
     _position() {
-      if (!this.trigger || !this.content)
-        return;
+      if (!this.trigger || !this.content) return;
       const triggerRect = this.trigger.getBoundingClientRect();
       const contentRect = this.content.getBoundingClientRect();
       const offset = this.config.offset || 4;
-      let side = this.config.side || "bottom";
-      let align = this.config.align || "center";
+      let side = this.config.side || 'bottom';
+      let align = this.config.align || 'center';
       let top, left;
       switch (side) {
-        case "top":
+        case 'top':
           top = triggerRect.top - contentRect.height - offset;
           if (top < 0) {
             top = triggerRect.bottom + offset;
-            side = "bottom";
+            side = 'bottom';
           }
           break;
-        case "bottom":
+        case 'bottom':
           top = triggerRect.bottom + offset;
           if (top + contentRect.height > window.innerHeight) {
             top = triggerRect.top - contentRect.height - offset;
-            side = "top";
+            side = 'top';
           }
           break;
-        case "left":
+        case 'left':
           left = triggerRect.left - contentRect.width - offset;
           if (left < 0) {
             left = triggerRect.right + offset;
-            side = "right";
+            side = 'right';
           }
           break;
-        case "right":
+        case 'right':
           left = triggerRect.right + offset;
           if (left + contentRect.width > window.innerWidth) {
             left = triggerRect.left - contentRect.width - offset;
-            side = "left";
+            side = 'left';
           }
           break;
       }
-      if (side === "top" || side === "bottom") {
+      if (side === 'top' || side === 'bottom') {
         switch (align) {
-          case "start":
+          case 'start':
             left = triggerRect.left;
             break;
-          case "center":
+          case 'center':
             left = triggerRect.left + (triggerRect.width - contentRect.width) / 2;
             break;
-          case "end":
+          case 'end':
             left = triggerRect.right - contentRect.width;
             break;
         }
-        if (left < 0)
-          left = 0;
+        if (left < 0) left = 0;
         if (left + contentRect.width > window.innerWidth) {
           left = window.innerWidth - contentRect.width;
         }
       }
-      if (side === "left" || side === "right") {
+      if (side === 'left' || side === 'right') {
         switch (align) {
-          case "start":
+          case 'start':
             top = triggerRect.top;
             break;
-          case "center":
+          case 'center':
             top = triggerRect.top + (triggerRect.height - contentRect.height) / 2;
             break;
-          case "end":
+          case 'end':
             top = triggerRect.bottom - contentRect.height;
             break;
         }
-        if (top < 0)
-          top = 0;
+        if (top < 0) top = 0;
         if (top + contentRect.height > window.innerHeight) {
           top = window.innerHeight - contentRect.height;
         }
       }
-      this.content.style.position = "fixed";
+      this.content.style.position = 'fixed';
       this.content.style.top = `${top}px`;
       this.content.style.left = `${left}px`;
       this.content.dataset.side = side;
     }
+  }
+  __name(PopoverHook, "PopoverHook");
+  // src/hooks/theme_toggle.js
+  class ThemeToggleHook {
+    mounted(el, config = {}) {
+      const storageKey = config && config.storageKey ? String(config.storageKey) : 'theme';
+      const applyStoredTheme = () => {
+        let stored = '';
+        try {
+          stored = localStorage.getItem(storageKey) || '';
+        } catch (_e) {
+          stored = '';
+        }
+        const prefersDark = window.matchMedia && window.matchMedia('(prefers-color-scheme:dark)').matches;
+        const shouldBeDark = stored === 'dark' || !stored && prefersDark;
+        document.documentElement.classList.toggle('dark', shouldBeDark);
+      };
+      const toggle = () => {
+        const nextIsDark = !document.documentElement.classList.contains('dark');
+        document.documentElement.classList.toggle('dark', nextIsDark);
+        try {
+          localStorage.setItem(storageKey, nextIsDark ? 'dark' : 'light');
+        } catch (_e2) {}
+      };
+      applyStoredTheme();
+      this._onClick = e => {
+        e.preventDefault();
+        toggle();
+      };
+      el.addEventListener('click', this._onClick);
+    }
+    destroyed(el) {
+      if (this._onClick) {
+        el.removeEventListener('click', this._onClick);
+      }
+      this._onClick = null;
+    }
+  }
+  __name(ThemeToggleHook, "ThemeToggleHook");
+  // src/hooks/queries.js
+  function inputSelection(el) {
+    let start;
+    let end;
+    try {
+      start = el.selectionStart;
+      end = el.selectionEnd;
+    } catch (_e) {
+      return null;
+    }
+    if (start === null || start === undefined) return null;
+    return {
+      text: el.value.slice(start, end),
+      start,
+      end,
+      direction: el.selectionDirection || 'none'
+    };
+  }
+  __name(inputSelection, "inputSelection");
+  function rangeSelection(el) {
+    const none = {
+      text: '',
+      start: 0,
+      end: 0,
+      direction: 'none'
+    };
+    const sel = window.getSelection();
+    if (!sel || sel.rangeCount === 0) return none;
+    const range = sel.getRangeAt(0);
+    if (!range.intersectsNode(el)) return none;
+    const clipped = document.createRange();
+    clipped.selectNodeContents(el);
+    if (el.contains(range.startContainer)) {
+      clipped.setStart(range.startContainer, range.startOffset);
+    }
+    if (el.contains(range.endContainer)) {
+      clipped.setEnd(range.endContainer, range.endOffset);
+    }
+    const before = document.createRange();
+    before.selectNodeContents(el);
+    before.setEnd(clipped.startContainer, clipped.startOffset);
+    const text = clipped.toString();
+    const start = before.toString().length;
+    let direction = 'none';
+    if (!sel.isCollapsed) {
+      const forward = sel.anchorNode === range.startContainer && sel.anchorOffset === range.startOffset;
+      direction = forward ? 'forward' : 'backward';
+    }
+    return {
+      text,
+      start,
+      end: start + text.length,
+      direction
+    };
+  }
+  __name(rangeSelection, "rangeSelection");
+  const DOMQueries = {
+    'vango:rect': el => {
+      const r = el.getBoundingClientRect();
+      return {
+        x: r.x,
+        y: r.y,
+        width: r.width,
+        height: r.height,
+        top: r.top,
+        right: r.right,
+        bottom: r.bottom,
+        left: r.left
+      };
+    },
+    'vango:scroll': el => ({
+      top: el.scrollTop,
+      left: el.scrollLeft,
+      width: el.scrollWidth,
+      height: el.scrollHeight
+    }),
+    'vango:selection': el => {
+      if (el instanceof HTMLInputElement || el instanceof HTMLTextAreaElement) {
+        const sel = inputSelection(el);
+        if (sel) return sel;
+      }
+      return rangeSelection(el);
+    }
   };
-  __name(_PopoverHook, "PopoverHook");
-  var PopoverHook = _PopoverHook;
-
   // src/hooks/manager.js
-  var _HookManager = class _HookManager {
+  const lifecycleMethods = new Set(['constructor', 'mounted', 'updated', 'destroyed']);
+  class HookManager {
     constructor(client) {
       this.client = client;
-      this.instances = /* @__PURE__ */ new Map();
-      this.pendingReverts = /* @__PURE__ */ new Map();
+      this.instances = new Map();
+      this.instancesByHookID = new Map();
+      this.pendingReverts = new Map();
+      this._hookIdCounter = 0;
       this.hooks = {
-        "Sortable": SortableHook,
-        "Draggable": DraggableHook,
-        "Droppable": DroppableHook,
-        "Resizable": ResizableHook,
-        "Tooltip": TooltipHook,
-        "Dropdown": DropdownHook,
-        "Collapsible": CollapsibleHook,
-        // VangoUI helper hooks
-        "FocusTrap": FocusTrapHook,
-        "Portal": PortalHook,
-        "Dialog": DialogHook,
-        "Popover": PopoverHook
+        'Sortable': SortableHook,
+        'Draggable': DraggableHook,
+        'Droppable': DroppableHook,
+        'Resizable': ResizableHook,
+        'Tooltip': TooltipHook,
+        'Dropdown': DropdownHook,
+        'Collapsible': CollapsibleHook,
+        'FocusTrap': FocusTrapHook,
+        'Portal': PortalHook,
+        'Dialog': DialogHook,
+        'Popover': PopoverHook,
+        'ThemeToggle': ThemeToggleHook
       };
-      document.addEventListener("vango:hook-revert", (e) => {
+      document.addEventListener('vango:hook-revert', e => {
         var _a;
         const hid = (_a = e.detail) == null ? void 0 : _a.hid;
-        if (hid && this.pendingReverts.has(hid)) {
-          const revertFn = this.pendingReverts.get(hid);
-          if (typeof revertFn === "function") {
-            revertFn();
-          }
-          this.pendingReverts.delete(hid);
-        }
+        if (hid) this.revert(hid);
       });
     }
-    /**
-     * Register a custom hook
-     */
     register(name, hookClass) {
       this.hooks[name] = hookClass;
     }
-    /**
-     * Initialize hooks from current DOM
-     */
     initializeFromDOM() {
-      document.querySelectorAll("[data-hook]").forEach((el) => {
+      document.querySelectorAll('[data-hook]').forEach(el => {
         this.initializeForNode(el);
       });
     }
-    /**
-     * Update hooks after DOM changes
-     */
     updateFromDOM() {
-      document.querySelectorAll("[data-hook]").forEach((el) => {
+      document.querySelectorAll('[data-hook]').forEach(el => {
+        const hookName = el.dataset.hook;
+        const hookConfigRaw = el.dataset.hookConfig || '';
         const hid = el.dataset.hid;
-        if (hid && !this.instances.has(hid)) {
+        if (hid) {
+          const entry = this.instances.get(hid);
+          if (!entry) {
+            this.initializeForNode(el);
+            return;
+          }
+          if (entry.hookName !== hookName) {
+            this.destroyForNode(el);
+            this.initializeForNode(el);
+            return;
+          }
+          if (entry.hookConfigRaw !== hookConfigRaw) {
+            let config = {};
+            try {
+              if (hookConfigRaw) config = JSON.parse(hookConfigRaw);
+            } catch (e) {
+              if (this.client.options.debug) {
+                console.warn('[Vango] Invalid hook config:', e);
+              }
+            }
+            this.updateConfig(hid, config);
+            entry.hookConfigRaw = hookConfigRaw;
+          }
+          return;
+        }
+        const hookID = el.dataset.vangoHookId;
+        const key = hookID || '';
+        const entry = key ? this.instancesByHookID.get(key) : null;
+        if (!entry) {
           this.initializeForNode(el);
+          return;
         }
-      });
-    }
-    /**
-     * Initialize hook for a specific node
-     */
+        if (entry.hookName !== hookName) {
+          this.destroyForNode(el);
+          this.initializeForNode(el);
+          return;
+        }
+        if (entry.hookConfigRaw !== hookConfigRaw) {
+          let config = {};
+          try {
+            if (hookConfigRaw) config = JSON.parse(hookConfigRaw);
+          } catch (e) {
+            if (this.client.options.debug) {
+              console.warn('[Vango] Invalid hook config:', e);
+            }
+          }
+          if (entry.instance.updated) {
+            const pushEvent = (eventName, data = {}, revertFn = null) => {
+              if (typeof revertFn === 'function') {
+                this.pendingReverts.set(key, revertFn);
+              }
+            };
+            entry.instance.updated(entry.el, config, pushEvent);
+          }
+          entry.hookConfigRaw = hookConfigRaw;
+        }
+      });
+    }
     initializeForNode(el) {
       const hookName = el.dataset.hook;
-      if (!hookName)
-        return;
+      if (!hookName) return;
       const HookClass = this.hooks[hookName];
       if (!HookClass) {
         if (this.client.options.debug) {
-          console.warn("[Vango] Unknown hook:", hookName);
+          console.warn('[Vango] Unknown hook:', hookName);
         }
         return;
       }
       const hid = el.dataset.hid;
-      if (!hid) {
-        if (this.client.options.debug) {
-          console.warn("[Vango] Hook element must have data-hid");
+      let keyKind = 'hid';
+      let key = hid;
+      if (!key) {
+        keyKind = 'hookID';
+        key = el.dataset.vangoHookId;
+        if (!key) {
+          this._hookIdCounter++;
+          key = `hk${this._hookIdCounter}`;
+          el.dataset.vangoHookId = key;
+        }
+        if (this.instancesByHookID.has(key)) {
+          return;
+        }
+      } else {
+        if (this.instances.has(key)) {
+          return;
         }
-        return;
-      }
-      if (this.instances.has(hid)) {
-        return;
       }
       let config = {};
+      const hookConfigRaw = el.dataset.hookConfig || '';
       try {
-        if (el.dataset.hookConfig) {
-          config = JSON.parse(el.dataset.hookConfig);
+        if (hookConfigRaw) {
+          config = JSON.parse(hookConfigRaw);
         }
       } catch (e) {
         if (this.client.options.debug) {
-          console.warn("[Vango] Invalid hook config:", e);
+          console.warn('[Vango] Invalid hook config:', e);
         }
       }
-      const pushEvent = /* @__PURE__ */ __name((eventName, data = {}, revertFn = null) => {
-        if (typeof revertFn === "function") {
-          this.pendingReverts.set(hid, revertFn);
+      const pushEvent = (eventName, data = {}, revertFn = null) => {
+        if (keyKind !== 'hid') {
+          if (this.client.options.debug) {
+            console.warn('[Vango] Hook event ignored: hook element has no data-hid');
+          }
+          return;
+        }
+        if (typeof revertFn === 'function') {
+          this.pendingReverts.set(key, revertFn);
         }
-        this.client.sendHookEvent(hid, eventName, data);
-      }, "pushEvent");
+        this.client.sendHookEvent(key, eventName, data);
+      };
       const instance = new HookClass();
       instance.mounted(el, config, pushEvent);
-      this.instances.set(hid, { hook: HookClass, instance, el });
+      if (keyKind === 'hid') {
+        this.instances.set(key, {
+          hook: HookClass,
+          hookName,
+          hookConfigRaw,
+          instance,
+          el
+        });
+      } else {
+        this.instancesByHookID.set(key, {
+          hook: HookClass,
+          hookName,
+          hookConfigRaw,
+          instance,
+          el
+        });
+      }
     }
-    /**
-     * Destroy hook for a specific node
-     */
     destroyForNode(el) {
-      var _a;
-      const hid = (_a = el.dataset) == null ? void 0 : _a.hid;
-      if (!hid)
-        return;
-      const entry = this.instances.get(hid);
-      if (entry) {
-        if (entry.instance.destroyed) {
-          entry.instance.destroyed(entry.el);
+      var _a2, _a3;
+      const hid = (_a2 = el.dataset) == null ? void 0 : _a2.hid;
+      if (hid) {
+        const entry = this.instances.get(hid);
+        if (entry) {
+          if (entry.instance.destroyed) {
+            entry.instance.destroyed(entry.el);
+          }
+          this.instances.delete(hid);
         }
-        this.instances.delete(hid);
+        return;
       }
+      const hookID = (_a3 = el.dataset) == null ? void 0 : _a3.vangoHookId;
+      if (!hookID) return;
+      const entry = this.instancesByHookID.get(hookID);
+      if (!entry) return;
+      if (entry.instance.destroyed) {
+        entry.instance.destroyed(entry.el);
+      }
+      this.instancesByHookID.delete(hookID);
     }
-    /**
-     * Destroy all hooks
-     */
     destroyAll() {
       for (const [hid, entry] of this.instances) {
         if (entry.instance.destroyed) {
@@ -3372,67 +5207,254 @@ var Vango = (() => {
         }
       }
       this.instances.clear();
+      for (const [hookID, entry] of this.instancesByHookID) {
+        if (entry.instance.destroyed) {
+          entry.instance.destroyed(entry.el);
+        }
+      }
+      this.instancesByHookID.clear();
     }
-    /**
-     * Update hook config
-     */
     updateConfig(hid, config) {
       const entry = this.instances.get(hid);
       if (entry && entry.instance.updated) {
-        const pushEvent = /* @__PURE__ */ __name((eventName, data = {}, revertFn = null) => {
-          if (typeof revertFn === "function") {
+        const pushEvent = (eventName, data = {}, revertFn = null) => {
+          if (typeof revertFn === 'function') {
             this.pendingReverts.set(hid, revertFn);
           }
           this.client.sendHookEvent(hid, eventName, data);
-        }, "pushEvent");
+        };
         entry.instance.updated(entry.el, config, pushEvent);
       }
     }
-  };
-  __name(_HookManager, "HookManager");
-  var HookManager = _HookManager;
-
+    async call(hid, method, args = {}) {
+      const entry = this.instances.get(hid);
+      if (entry && !lifecycleMethods.has(method) && typeof entry.instance[method] === 'function') {
+        return await entry.instance[method](entry.el, args);
+      }
+      const query = DOMQueries[method];
+      if (query) {
+        const el = (entry == null ? void 0 : entry.el) || this.client.getNode(hid);
+        if (!el) throw new Error(`no element with HID ${hid}`);
+        return query(el, args);
+      }
+      if (!entry) throw new Error(`no hook on element ${hid}`);
+      throw new Error(`hook ${entry.hookName} has no method ${method}`);
+    }
+    revert(hid) {
+      if (!hid || !this.pendingReverts.has(hid)) return;
+      const revertFn = this.pendingReverts.get(hid);
+      if (typeof revertFn === 'function') {
+        revertFn();
+      }
+      this.pendingReverts.delete(hid);
+    }
+  }
+  __name(HookManager, "HookManager");
+  // src/islands.js
+  const MaxIslandDepth = 64;
+  class IslandManager {
+    constructor(client) {
+      this.client = client;
+      this.instances = new Map();
+      this.modules = new Map();
+      this.pending = new Map();
+      this.loadModule = client.options.islandLoader || (src => import(src));
+    }
+    register(src, module) {
+      this.modules.set(src, module);
+    }
+    initializeFromDOM() {
+      document.querySelectorAll('[data-island]').forEach(el => {
+        this.mount(el);
+      });
+    }
+    updateFromDOM() {
+      for (const [id, entry] of this.instances) {
+        if (!entry.el.isConnected) {
+          this.destroy(id);
+        }
+      }
+      document.querySelectorAll('[data-island]').forEach(el => {
+        const entry = this.instances.get(el.dataset.island);
+        if (!entry || entry.el !== el || entry.moduleSrc !== el.dataset.module) {
+          if (entry) this.destroy(el.dataset.island);
+          this.mount(el);
+          return;
+        }
+        const propsRaw = el.dataset.props || '';
+        if (entry.propsRaw !== propsRaw) {
+          entry.propsRaw = propsRaw;
+          if (entry.mounted && entry.instance && typeof entry.instance.update === 'function') {
+            entry.instance.update(this._parseProps(propsRaw));
+          }
+        }
+      });
+      this.pending.clear();
+    }
+    mount(el) {
+      const id = el.dataset.island;
+      const moduleSrc = el.dataset.module;
+      if (!id || !moduleSrc || this.instances.has(id)) return;
+      const entry = {
+        el,
+        moduleSrc,
+        propsRaw: el.dataset.props || '',
+        instance: null,
+        handlers: [],
+        pending: this.pending.get(id) || [],
+        mounted: false,
+        destroyed: false
+      };
+      this.pending.delete(id);
+      this.instances.set(id, entry);
+      const island = {
+        id,
+        send: (data = {}) => this.send(id, data),
+        onMessage: fn => {
+          entry.handlers.push(fn);
+          return () => {
+            entry.handlers = entry.handlers.filter(h => h !== fn);
+          };
+        }
+      };
+      const start = mod => {
+        var _a;
+        if (entry.destroyed) return;
+        const mountFn = (mod == null ? void 0 : mod.mount) || (mod == null || (_a = mod.default) == null ? void 0 : _a.mount) || (mod == null ? void 0 : mod.default);
+        if (typeof mountFn !== 'function') {
+          console.error('[Vango] Island module has no mount():', moduleSrc);
+          return;
+        }
+        entry.instance = mountFn(el, this._parseProps(entry.propsRaw), island) || null;
+        entry.mounted = true;
+        const queued = entry.pending;
+        entry.pending = [];
+        for (const data of queued) {
+          this._dispatch(entry, data);
+        }
+      };
+      const registered = this.modules.get(moduleSrc);
+      if (registered) {
+        start(registered);
+        return;
+      }
+      Promise.resolve().then(() => this.loadModule(moduleSrc)).then(start).catch(err => {
+        console.error('[Vango] Failed to load island module:', moduleSrc, err);
+      });
+    }
+    destroy(id) {
+      const entry = this.instances.get(id);
+      if (!entry) return;
+      entry.destroyed = true;
+      this.instances.delete(id);
+      const instance = entry.instance;
+      if (typeof instance === 'function') {
+        instance();
+      } else if (instance && typeof instance.destroy === 'function') {
+        instance.destroy();
+      }
+    }
+    destroyAll() {
+      for (const id of [...this.instances.keys()]) {
+        this.destroy(id);
+      }
+      this.pending.clear();
+    }
+    deliver(id, data) {
+      const entry = this.instances.get(id);
+      if (!entry) {
+        if (!this.pending.has(id)) this.pending.set(id, []);
+        this.pending.get(id).push(data);
+        return;
+      }
+      if (!entry.mounted) {
+        entry.pending.push(data);
+        return;
+      }
+      this._dispatch(entry, data);
+    }
+    send(id, data = {}) {
+      const depth = Math.max(0, ...Object.values(data || {}).map(v => this._depth(v, 0)));
+      if (depth > MaxIslandDepth) {
+        console.error('[Vango] Island message exceeds max depth, dropped:', id);
+        return false;
+      }
+      return this.client.sendIslandMessage(id, data);
+    }
+    _dispatch(entry, data) {
+      for (const fn of entry.handlers) {
+        try {
+          fn(data);
+        } catch (err) {
+          console.error('[Vango] Island message handler error:', err);
+        }
+      }
+    }
+    _parseProps(raw) {
+      if (!raw) return {};
+      try {
+        return JSON.parse(raw);
+      } catch (e) {
+        if (this.client.options.debug) {
+          console.warn('[Vango] Invalid island props:', e);
+        }
+        return {};
+      }
+    }
+    _depth(value, depth) {
+      if (depth > MaxIslandDepth) return depth;
+      let max = depth;
+      if (Array.isArray(value)) {
+        for (const item of value) {
+          max = Math.max(max, this._depth(item, depth + 1));
+        }
+      } else if (value !== null && typeof value === 'object') {
+        for (const item of Object.values(value)) {
+          max = Math.max(max, this._depth(item, depth + 1));
+        }
+      }
+      return max;
+    }
+  }
+  __name(IslandManager, "IslandManager");
   // src/connection.js
-  var ConnectionState = {
-    CONNECTING: "connecting",
-    CONNECTED: "connected",
-    RECONNECTING: "reconnecting",
-    DISCONNECTED: "disconnected"
+  const ConnectionState = {
+    CONNECTING: 'connecting',
+    CONNECTED: 'connected',
+    RECONNECTING: 'reconnecting',
+    DISCONNECTED: 'disconnected'
   };
-  var CSS_CLASSES = {
-    [ConnectionState.CONNECTING]: "vango-connecting",
-    [ConnectionState.CONNECTED]: "vango-connected",
-    [ConnectionState.RECONNECTING]: "vango-reconnecting",
-    [ConnectionState.DISCONNECTED]: "vango-disconnected"
+  const CSS_CLASSES = {
+    [ConnectionState.CONNECTING]: 'vango-connecting',
+    [ConnectionState.CONNECTED]: 'vango-connected',
+    [ConnectionState.RECONNECTING]: 'vango-reconnecting',
+    [ConnectionState.DISCONNECTED]: 'vango-disconnected'
   };
-  var _ConnectionManager = class _ConnectionManager {
+  class ConnectionManager {
     constructor(options = {}) {
       this.options = {
-        // Toast settings
         toastOnReconnect: options.toastOnReconnect || false,
-        toastMessage: options.toastMessage || "Connection restored",
-        toastDuration: options.toastDuration || 3e3,
-        // Reconnection settings
+        toastMessage: options.toastMessage || 'Connection restored',
+        toastDuration: options.toastDuration || 3000,
         maxRetries: options.maxRetries || 10,
-        baseDelay: options.baseDelay || 1e3,
-        maxDelay: options.maxDelay || 3e4,
-        // Debug mode
+        baseDelay: options.baseDelay || 1000,
+        maxDelay: options.maxDelay || 30000,
         debug: options.debug || false,
         ...options
       };
       this.state = ConnectionState.CONNECTING;
       this.retryCount = 0;
       this.previousState = null;
+      this.disconnectReason = null;
       this._updateClasses();
     }
-    /**
-     * Set connection state and update UI
-     */
     setState(newState) {
-      if (this.state === newState)
-        return;
+      if (this.state === newState) return;
       this.previousState = this.state;
       this.state = newState;
+      if (newState !== ConnectionState.DISCONNECTED) {
+        this.disconnectReason = null;
+      }
       this._updateClasses();
       this._dispatchEvent(newState, this.previousState);
       if (this.options.toastOnReconnect && newState === ConnectionState.CONNECTED && this.previousState !== ConnectionState.CONNECTED) {
@@ -3442,47 +5464,44 @@ var Vango = (() => {
         console.log(`[Vango] Connection state: ${this.previousState} -> ${newState}`);
       }
     }
-    /**
-     * Update CSS classes on document.documentElement (<html>)
-     */
     _updateClasses() {
       const root = document.documentElement;
-      Object.values(CSS_CLASSES).forEach((cls) => {
+      Object.values(CSS_CLASSES).forEach(cls => {
         root.classList.remove(cls);
       });
       const currentClass = CSS_CLASSES[this.state];
       if (currentClass) {
         root.classList.add(currentClass);
       }
+      if (this.state === ConnectionState.DISCONNECTED && this.disconnectReason) {
+        root.setAttribute('data-vango-disconnect-reason', this.disconnectReason);
+      } else {
+        root.removeAttribute('data-vango-disconnect-reason');
+      }
     }
-    /**
-     * Dispatch custom event for connection state changes
-     */
     _dispatchEvent(state, previousState) {
-      const event = new CustomEvent("vango:connection", {
-        detail: { state, previousState },
+      const event = new CustomEvent('vango:connection', {
+        detail: {
+          state,
+          previousState
+        },
         bubbles: true
       });
       document.dispatchEvent(event);
     }
-    /**
-     * Handle connection established
-     */
     onConnect() {
       this.retryCount = 0;
       this.setState(ConnectionState.CONNECTED);
     }
-    /**
-     * Handle connection lost
-     */
     onDisconnect() {
       if (this.state === ConnectionState.CONNECTED) {
         this.setState(ConnectionState.RECONNECTING);
       }
     }
-    /**
-     * Handle failed reconnection attempt
-     */
+    setDisconnected(reason) {
+      this.disconnectReason = reason || null;
+      this.setState(ConnectionState.DISCONNECTED);
+    }
     onReconnectFailed() {
       this.retryCount++;
       if (this.retryCount >= this.options.maxRetries) {
@@ -3491,76 +5510,55 @@ var Vango = (() => {
       }
       return true;
     }
-    /**
-     * Calculate next retry delay with exponential backoff
-     */
     getNextRetryDelay() {
-      const delay = Math.min(
-        this.options.baseDelay * Math.pow(2, this.retryCount),
-        this.options.maxDelay
-      );
+      const delay = Math.min(this.options.baseDelay * Math.pow(2, this.retryCount), this.options.maxDelay);
       return delay;
     }
-    /**
-     * Get current state
-     */
     getState() {
       return this.state;
     }
-    /**
-     * Check if currently connected
-     */
     isConnected() {
       return this.state === ConnectionState.CONNECTED;
     }
-    /**
-     * Check if disconnected (gave up reconnecting)
-     */
     isDisconnected() {
       return this.state === ConnectionState.DISCONNECTED;
     }
-    /**
-     * Reset state (e.g., for manual retry)
-     */
     reset() {
       this.retryCount = 0;
       this.setState(ConnectionState.CONNECTED);
     }
-    /**
-     * Show a toast notification
-     */
     showToast(message, duration = this.options.toastDuration) {
-      if (typeof window.__VANGO_TOAST__ === "function") {
+      if (typeof window.__VANGO_TOAST__ === 'function') {
         window.__VANGO_TOAST__(message, duration);
         return;
       }
-      const toast = document.createElement("div");
-      toast.className = "vango-toast";
+      const toast = document.createElement('div');
+      toast.className = 'vango-toast';
       toast.textContent = message;
-      toast.setAttribute("role", "alert");
-      toast.setAttribute("aria-live", "polite");
+      toast.setAttribute('role', 'alert');
+      toast.setAttribute('aria-live', 'polite');
       Object.assign(toast.style, {
-        position: "fixed",
-        bottom: "20px",
-        left: "50%",
-        transform: "translateX(-50%)",
-        padding: "12px 24px",
-        backgroundColor: "hsl(var(--primary, 220 13% 18%))",
-        color: "hsl(var(--primary-foreground, 0 0% 100%))",
-        borderRadius: "var(--radius, 6px)",
-        boxShadow: "0 4px 12px rgba(0, 0, 0, 0.15)",
-        zIndex: "10001",
-        opacity: "0",
-        transition: "opacity 0.3s ease",
-        fontFamily: "system-ui, -apple-system, sans-serif",
-        fontSize: "14px"
+        position: 'fixed',
+        bottom: '20px',
+        left: '50%',
+        transform: 'translateX(-50%)',
+        padding: '12px 24px',
+        backgroundColor: 'hsl(var(--primary, 220 13% 18%))',
+        color: 'hsl(var(--primary-foreground, 0 0% 100%))',
+        borderRadius: 'var(--radius, 6px)',
+        boxShadow: '0 4px 12px rgba(0, 0, 0, 0.15)',
+        zIndex: '10001',
+        opacity: '0',
+        transition: 'opacity 0.3s ease',
+        fontFamily: 'system-ui, -apple-system, sans-serif',
+        fontSize: '14px'
       });
       document.body.appendChild(toast);
       requestAnimationFrame(() => {
-        toast.style.opacity = "1";
+        toast.style.opacity = '1';
       });
       setTimeout(() => {
-        toast.style.opacity = "0";
+        toast.style.opacity = '0';
         setTimeout(() => {
           if (toast.parentNode) {
             toast.parentNode.removeChild(toast);
@@ -3568,15 +5566,14 @@ var Vango = (() => {
         }, 300);
       }, duration);
     }
-  };
-  __name(_ConnectionManager, "ConnectionManager");
-  var ConnectionManager = _ConnectionManager;
+  }
+  __name(ConnectionManager, "ConnectionManager");
   function injectDefaultStyles() {
-    if (document.getElementById("vango-connection-styles")) {
+    if (document.getElementById('vango-connection-styles')) {
       return;
     }
-    const style = document.createElement("style");
-    style.id = "vango-connection-styles";
+    const style = document.createElement('style');
+    style.id = 'vango-connection-styles';
     style.textContent = `
         /* Vango Connection State Styles */
 
@@ -3658,65 +5655,57 @@ var Vango = (() => {
     document.head.appendChild(style);
   }
   __name(injectDefaultStyles, "injectDefaultStyles");
-
   // src/url.js
-  var _URLManager = class _URLManager {
+  class URLManager {
     constructor(client, options = {}) {
       this.client = client;
       this.options = {
         debug: options.debug || false,
         ...options
       };
-      this.pending = /* @__PURE__ */ new Map();
+      this.pending = new Map();
     }
-    /**
-     * Apply a URL patch
-     * @param {Object} patch - The URL patch with op and params
-     */
     applyPatch(patch) {
-      const { op, params } = patch;
-      if (!params || typeof params !== "object") {
+      const {
+        op,
+        params
+      } = patch;
+      if (!params || typeof params !== 'object') {
         if (this.options.debug) {
-          console.warn("[Vango URL] Invalid params:", params);
+          console.warn('[Vango URL] Invalid params:', params);
         }
         return;
       }
       const url = new URL(window.location);
       for (const [key, value] of Object.entries(params)) {
-        if (value === "" || value === null || value === void 0) {
+        if (value === '' || value === null || value === undefined) {
           url.searchParams.delete(key);
         } else {
           url.searchParams.set(key, value);
         }
       }
-      const isPush = op === 48;
+      const isPush = op === 0x30;
       if (this.options.debug) {
-        console.log("[Vango URL]", isPush ? "Push" : "Replace", url.toString());
+        console.log('[Vango URL]', isPush ? 'Push' : 'Replace', url.toString());
       }
       if (isPush) {
-        history.pushState(null, "", url.toString());
+        history.pushState(null, '', url.toString());
       } else {
-        history.replaceState(null, "", url.toString());
+        history.replaceState(null, '', url.toString());
       }
       this._dispatchEvent(params, isPush);
     }
-    /**
-     * Dispatch custom event for URL changes
-     */
     _dispatchEvent(params, isPush) {
-      const event = new CustomEvent("vango:url", {
+      const event = new CustomEvent('vango:url', {
         detail: {
           params,
-          mode: isPush ? "push" : "replace",
+          mode: isPush ? 'push' : 'replace',
           url: window.location.href
         },
         bubbles: true
       });
       document.dispatchEvent(event);
     }
-    /**
-     * Get current query parameters as an object
-     */
     getParams() {
       const params = {};
       const searchParams = new URLSearchParams(window.location.search);
@@ -3725,127 +5714,112 @@ var Vango = (() => {
       }
       return params;
     }
-    /**
-     * Get a specific query parameter
-     */
     getParam(key) {
       const searchParams = new URLSearchParams(window.location.search);
       return searchParams.get(key);
     }
-    /**
-     * Check if a query parameter exists
-     */
     hasParam(key) {
       const searchParams = new URLSearchParams(window.location.search);
       return searchParams.has(key);
     }
-  };
-  __name(_URLManager, "URLManager");
-  var URLManager = _URLManager;
-
+  }
+  __name(URLManager, "URLManager");
   // src/prefs.js
-  var MergeStrategy = {
+  const MergeStrategy = {
     DB_WINS: 0,
-    // Server value wins
     LOCAL_WINS: 1,
-    // Local value wins
     PROMPT: 2,
-    // Ask user to choose
     LWW: 3
-    // Last-write-wins by timestamp
   };
-  var _PrefManager = class _PrefManager {
+  class PrefManager {
     constructor(client, options = {}) {
       this.client = client;
       this.options = {
-        channelName: "vango:prefs",
-        storagePrefix: "vango_pref_",
+        channelName: 'vango:prefs',
+        storagePrefix: 'vango_pref_',
         debug: options.debug || false,
         ...options
       };
-      this.prefs = /* @__PURE__ */ new Map();
+      this.prefs = new Map();
       this.channel = null;
-      if (typeof BroadcastChannel !== "undefined") {
+      if (typeof BroadcastChannel !== 'undefined') {
         this.channel = new BroadcastChannel(this.options.channelName);
-        this.channel.onmessage = (event) => this._handleBroadcast(event);
+        this.channel.onmessage = event => this._handleBroadcast(event);
       }
-      if (typeof window !== "undefined") {
-        window.addEventListener("storage", (event) => this._handleStorageEvent(event));
+      if (typeof window !== 'undefined') {
+        window.addEventListener('storage', event => this._handleStorageEvent(event));
       }
     }
-    /**
-     * Register a preference
-     * @param {string} key - Unique preference key
-     * @param {*} defaultValue - Default value
-     * @param {Object} options - Configuration options
-     * @returns {Pref} Preference instance
-     */
     register(key, defaultValue, options = {}) {
       const pref = new Pref(this, key, defaultValue, options);
       this.prefs.set(key, pref);
       pref._loadFromStorage();
       return pref;
     }
-    /**
-     * Get a registered preference
-     */
     get(key) {
       return this.prefs.get(key);
     }
-    /**
-     * Broadcast a preference change to other tabs
-     */
     broadcast(key, value, updatedAt) {
       if (this.channel) {
         this.channel.postMessage({
-          type: "update",
+          type: 'update',
           key,
           value,
           updatedAt: updatedAt.toISOString()
         });
       }
     }
-    /**
-     * Handle broadcast message from another tab
-     */
     _handleBroadcast(event) {
-      const { type, key, value, updatedAt } = event.data;
-      if (type === "update") {
+      const {
+        type,
+        key,
+        value,
+        updatedAt
+      } = event.data;
+      if (type === 'update') {
         const pref = this.prefs.get(key);
         if (pref) {
           pref._setFromRemote(value, new Date(updatedAt));
         }
+        this.sendToServer([{
+          key,
+          value,
+          updatedAt: new Date(updatedAt)
+        }]);
       }
       if (this.options.debug) {
-        console.log("[Vango Prefs] Broadcast received:", event.data);
+        console.log('[Vango Prefs] Broadcast received:', event.data);
       }
     }
-    /**
-     * Handle storage event (fallback for cross-tab sync)
-     */
     _handleStorageEvent(event) {
       if (!event.key || !event.key.startsWith(this.options.storagePrefix)) {
         return;
       }
       const prefKey = event.key.slice(this.options.storagePrefix.length);
       const pref = this.prefs.get(prefKey);
-      if (pref && event.newValue) {
+      if (event.newValue) {
         try {
           const data = JSON.parse(event.newValue);
-          pref._setFromRemote(data.value, new Date(data.updatedAt));
+          const updatedAt = new Date(data.updatedAt);
+          if (pref) {
+            pref._setFromRemote(data.value, updatedAt);
+          }
+          if (!this.channel) {
+            this.sendToServer([{
+              key: prefKey,
+              value: data.value,
+              updatedAt
+            }]);
+          }
         } catch (e) {
           if (this.options.debug) {
-            console.warn("[Vango Prefs] Failed to parse storage event:", e);
+            console.warn('[Vango Prefs] Failed to parse storage event:', e);
           }
         }
       }
     }
-    /**
-     * Save to LocalStorage
-     */
     saveToStorage(key, value, updatedAt) {
-      if (typeof localStorage === "undefined")
-        return;
+      if (typeof localStorage === 'undefined') return;
       const storageKey = this.options.storagePrefix + key;
       const data = {
         value,
@@ -3855,16 +5829,12 @@ var Vango = (() => {
         localStorage.setItem(storageKey, JSON.stringify(data));
       } catch (e) {
         if (this.options.debug) {
-          console.warn("[Vango Prefs] Failed to save to storage:", e);
+          console.warn('[Vango Prefs] Failed to save to storage:', e);
         }
       }
     }
-    /**
-     * Load from LocalStorage
-     */
     loadFromStorage(key) {
-      if (typeof localStorage === "undefined")
-        return null;
+      if (typeof localStorage === 'undefined') return null;
       const storageKey = this.options.storagePrefix + key;
       try {
         const data = localStorage.getItem(storageKey);
@@ -3877,27 +5847,88 @@ var Vango = (() => {
         }
       } catch (e) {
         if (this.options.debug) {
-          console.warn("[Vango Prefs] Failed to load from storage:", e);
+          console.warn('[Vango Prefs] Failed to load from storage:', e);
         }
       }
       return null;
     }
-    /**
-     * Remove from LocalStorage
-     */
     removeFromStorage(key) {
-      if (typeof localStorage === "undefined")
-        return;
+      if (typeof localStorage === 'undefined') return;
       const storageKey = this.options.storagePrefix + key;
       try {
         localStorage.removeItem(storageKey);
+      } catch (e) {}
+    }
+    storedValues() {
+      if (typeof localStorage === 'undefined') return [];
+      const values = [];
+      const prefix = this.options.storagePrefix;
+      try {
+        for (let i = 0; i < localStorage.length; i++) {
+          const storageKey = localStorage.key(i);
+          if (!storageKey || !storageKey.startsWith(prefix)) continue;
+          const key = storageKey.slice(prefix.length);
+          const stored = this.loadFromStorage(key);
+          const updatedAt = stored ? stored.updatedAt.getTime() : NaN;
+          if (!stored || stored.value === undefined || Number.isNaN(updatedAt)) continue;
+          values.push({
+            key,
+            value: JSON.stringify(stored.value),
+            updatedAt
+          });
+        }
       } catch (e) {
+        if (this.options.debug) {
+          console.warn('[Vango Prefs] Failed to list storage:', e);
+        }
+      }
+      return values;
+    }
+    sendToServer(values) {
+      const client = this.client;
+      if (!client || !client.connected || !client.codec || values.length === 0) {
+        return;
+      }
+      if (client.protocolVersion && !versionAtLeast(client.protocolVersion, 2, 2)) {
+        return;
+      }
+      const payload = client.codec.encodePrefSync(values.map(v => ({
+        key: v.key,
+        value: JSON.stringify(v.value),
+        updatedAt: v.updatedAt.getTime()
+      })));
+      for (const frame of client.codec.encodeFrames(0x03, payload)) {
+        client.wsManager.send(frame);
+      }
+    }
+    handleSync(values) {
+      for (const {
+        key,
+        value: raw,
+        updatedAt: ms
+      } of values) {
+        let value;
+        try {
+          value = JSON.parse(raw);
+        } catch (e) {
+          if (this.options.debug) {
+            console.warn('[Vango Prefs] Invalid value from server:', key, e);
+          }
+          continue;
+        }
+        const updatedAt = new Date(ms);
+        const pref = this.prefs.get(key);
+        if (pref) {
+          pref._setFromServer(value, updatedAt);
+        } else {
+          this.saveToStorage(key, value, updatedAt);
+        }
+        this.broadcast(key, value, updatedAt);
+      }
+      if (this.options.debug) {
+        console.log('[Vango Prefs] Sync received:', values);
       }
     }
-    /**
-     * Sync all preferences with server
-     * Called when user logs in
-     */
     async syncWithServer(serverPrefs) {
       for (const [key, serverData] of Object.entries(serverPrefs)) {
         const pref = this.prefs.get(key);
@@ -3906,9 +5937,6 @@ var Vango = (() => {
         }
       }
     }
-    /**
-     * Get all preferences as an object for server sync
-     */
     getAllForSync() {
       const result = {};
       for (const [key, pref] of this.prefs) {
@@ -3921,53 +5949,42 @@ var Vango = (() => {
       }
       return result;
     }
-    /**
-     * Cleanup
-     */
     destroy() {
       if (this.channel) {
         this.channel.close();
         this.channel = null;
       }
     }
-  };
-  __name(_PrefManager, "PrefManager");
-  var PrefManager = _PrefManager;
-  var _Pref = class _Pref {
+  }
+  __name(PrefManager, "PrefManager");
+  class Pref {
     constructor(manager, key, defaultValue, options = {}) {
       this.manager = manager;
       this.key = key;
       this.defaultValue = defaultValue;
       this.value = defaultValue;
-      this.updatedAt = /* @__PURE__ */ new Date();
+      this.updatedAt = new Date();
+      this.pendingConflict = null;
       this.options = {
         mergeStrategy: MergeStrategy.LWW,
         persistLocal: true,
         syncToServer: true,
         onConflict: null,
-        // Custom conflict handler
         onChange: null,
-        // Called when value changes
         ...options
       };
-      this.subscribers = /* @__PURE__ */ new Set();
+      this.subscribers = new Set();
     }
-    /**
-     * Get the current value
-     */
     get() {
       return this.value;
     }
-    /**
-     * Set a new value
-     */
     set(value) {
       if (this._isEqual(this.value, value)) {
         return;
       }
       const oldValue = this.value;
       this.value = value;
-      this.updatedAt = /* @__PURE__ */ new Date();
+      this.updatedAt = new Date();
       if (this.options.persistLocal) {
         this.manager.saveToStorage(this.key, value, this.updatedAt);
       }
@@ -3980,24 +5997,33 @@ var Vango = (() => {
         this.options.onChange(value, oldValue);
       }
     }
-    /**
-     * Reset to default value
-     */
     reset() {
       this.set(this.defaultValue);
     }
-    /**
-     * Subscribe to value changes
-     * @param {Function} callback - Called with (newValue, oldValue)
-     * @returns {Function} Unsubscribe function
-     */
+    conflict() {
+      return this.pendingConflict;
+    }
+    resolve(useRemote) {
+      const pending = this.pendingConflict;
+      if (!pending) return;
+      this.pendingConflict = null;
+      if (useRemote) {
+        this._setFromServer(pending.value, pending.updatedAt);
+      } else {
+        this.updatedAt = new Date();
+        if (this.options.persistLocal) {
+          this.manager.saveToStorage(this.key, this.value, this.updatedAt);
+        }
+        this.manager.broadcast(this.key, this.value, this.updatedAt);
+        if (this.options.syncToServer) {
+          this._syncToServer();
+        }
+      }
+    }
     subscribe(callback) {
       this.subscribers.add(callback);
       return () => this.subscribers.delete(callback);
     }
-    /**
-     * Load initial value from storage
-     */
     _loadFromStorage() {
       const stored = this.manager.loadFromStorage(this.key);
       if (stored) {
@@ -4005,9 +6031,6 @@ var Vango = (() => {
         this.updatedAt = stored.updatedAt;
       }
     }
-    /**
-     * Set value from remote source (another tab)
-     */
     _setFromRemote(value, remoteUpdatedAt) {
       const resolved = this._resolveConflict(this.value, value, this.updatedAt, remoteUpdatedAt);
       if (!this._isEqual(this.value, resolved)) {
@@ -4025,9 +6048,21 @@ var Vango = (() => {
         }
       }
     }
-    /**
-     * Merge with server value (called on login)
-     */
+    _setFromServer(value, serverUpdatedAt) {
+      this.pendingConflict = null;
+      this.updatedAt = serverUpdatedAt;
+      if (this.options.persistLocal) {
+        this.manager.saveToStorage(this.key, value, serverUpdatedAt);
+      }
+      if (!this._isEqual(this.value, value)) {
+        const oldValue = this.value;
+        this.value = value;
+        this._notifySubscribers(value, oldValue);
+        if (this.options.onChange) {
+          this.options.onChange(value, oldValue);
+        }
+      }
+    }
     _mergeWithServer(serverValue, serverUpdatedAt) {
       const resolved = this._resolveConflict(this.value, serverValue, this.updatedAt, serverUpdatedAt);
       if (!this._isEqual(this.value, resolved)) {
@@ -4045,9 +6080,6 @@ var Vango = (() => {
         }
       }
     }
-    /**
-     * Resolve conflict between local and remote values
-     */
     _resolveConflict(local, remote, localTime, remoteTime) {
       if (this.options.onConflict) {
         return this.options.onConflict(local, remote, localTime, remoteTime);
@@ -4060,129 +6092,615 @@ var Vango = (() => {
         case MergeStrategy.LWW:
           return remoteTime > localTime ? remote : local;
         case MergeStrategy.PROMPT:
-          return remoteTime > localTime ? remote : local;
+          if (!this._isEqual(local, remote)) {
+            this.pendingConflict = {
+              value: remote,
+              updatedAt: remoteTime
+            };
+          }
+          return local;
         default:
           return local;
       }
     }
-    /**
-     * Sync preference to server
-     */
     _syncToServer() {
-      if (!this.manager.client || !this.manager.client.connected) {
-        return;
-      }
-      this.manager.client.sendEvent(
-        16,
-        // EventType.PREF (custom event type for preferences)
-        "",
-        // No HID needed
-        {
-          key: this.key,
-          value: this.value,
-          updatedAt: this.updatedAt.toISOString()
-        }
-      );
-    }
-    /**
-     * Notify all subscribers of value change
-     */
+      this.manager.sendToServer([{
+        key: this.key,
+        value: this.value,
+        updatedAt: this.updatedAt
+      }]);
+    }
     _notifySubscribers(newValue, oldValue) {
       for (const callback of this.subscribers) {
         try {
           callback(newValue, oldValue);
         } catch (e) {
-          console.error("[Vango Prefs] Subscriber error:", e);
+          console.error('[Vango Prefs] Subscriber error:', e);
         }
       }
     }
-    /**
-     * Check if two values are equal
-     */
     _isEqual(a, b) {
-      if (a === b)
-        return true;
-      if (typeof a !== typeof b)
-        return false;
-      if (typeof a === "object" && a !== null && b !== null) {
+      if (a === b) return true;
+      if (typeof a !== typeof b) return false;
+      if (typeof a === 'object' && a !== null && b !== null) {
         return JSON.stringify(a) === JSON.stringify(b);
       }
       return false;
     }
+  }
+  __name(Pref, "Pref");
+  // src/global-events.js
+  const GlobalTarget = {
+    WINDOW: 0x01,
+    DOCUMENT: 0x02
   };
-  __name(_Pref, "Pref");
-  var Pref = _Pref;
-
+  const ListenFlags = {
+    PREVENT_DEFAULT: 0x01,
+    STOP_PROPAGATION: 0x02,
+    ONCE: 0x04,
+    PASSIVE: 0x08,
+    CAPTURE: 0x10
+  };
+  const ModCtrl = 0x01;
+  const ModShift = 0x02;
+  const ModAlt = 0x04;
+  const ModMeta = 0x08;
+  function keyModifiers(event) {
+    return (event.ctrlKey ? ModCtrl : 0) | (event.shiftKey ? ModShift : 0) | (event.altKey ? ModAlt : 0) | (event.metaKey ? ModMeta : 0);
+  }
+  __name(keyModifiers, "keyModifiers");
+  class GlobalEvents {
+    constructor(client, options = {}) {
+      this.client = client;
+      this.options = options;
+      this.subs = new Map();
+      this.listeners = new Map();
+    }
+    listen(sub) {
+      const type = EventType[sub.event.toUpperCase()];
+      if (type === undefined) {
+        console.warn('[Vango] Unsupported global event:', sub.event);
+        return;
+      }
+      this.unlisten(sub.id);
+      const capture = (sub.flags & ListenFlags.CAPTURE) !== 0;
+      const key = `${sub.target}:${sub.event}:${capture ? 1 : 0}`;
+      let entry = this.listeners.get(key);
+      if (!entry) {
+        const target = sub.target === GlobalTarget.DOCUMENT ? document : window;
+        entry = {
+          target,
+          event: sub.event,
+          capture,
+          ids: new Set()
+        };
+        entry.handler = event => {
+          for (const id of [...entry.ids]) {
+            const current = this.subs.get(id);
+            if (current) this._handle(current, event);
+          }
+        };
+        target.addEventListener(sub.event, entry.handler, {
+          capture
+        });
+        this.listeners.set(key, entry);
+      }
+      entry.ids.add(sub.id);
+      this.subs.set(sub.id, {
+        ...sub,
+        type,
+        key,
+        timer: null,
+        last: 0
+      });
+      if (this.options.debug) {
+        console.log('[Vango] Global listen:', sub.id, sub.event);
+      }
+    }
+    unlisten(id) {
+      const sub = this.subs.get(id);
+      if (!sub) return;
+      clearTimeout(sub.timer);
+      this.subs.delete(id);
+      const entry = this.listeners.get(sub.key);
+      if (!entry) return;
+      entry.ids.delete(id);
+      if (entry.ids.size === 0) {
+        entry.target.removeEventListener(entry.event, entry.handler, {
+          capture: entry.capture
+        });
+        this.listeners.delete(sub.key);
+      }
+    }
+    reset() {
+      for (const id of [...this.subs.keys()]) {
+        this.unlisten(id);
+      }
+    }
+    _handle(sub, event) {
+      if (sub.type === EventType.KEYDOWN || sub.type === EventType.KEYUP) {
+        if (sub.keys.length > 0 && !sub.keys.includes(event.key)) return;
+        if (sub.keyMods && keyModifiers(event) !== sub.keyMods) return;
+      }
+      if (sub.flags & ListenFlags.PREVENT_DEFAULT && !(sub.flags & ListenFlags.PASSIVE)) {
+        event.preventDefault();
+        if (event.type === 'beforeunload') {
+          event.returnValue = '';
+        }
+      }
+      if (sub.flags & ListenFlags.STOP_PROPAGATION) {
+        event.stopPropagation();
+      }
+      const payload = this._payload(sub.type, event);
+      const send = () => this.client.sendEvent(sub.type, sub.id, payload);
+      if (sub.flags & ListenFlags.ONCE) {
+        this.unlisten(sub.id);
+        send();
+        return;
+      }
+      if (sub.debounce > 0) {
+        clearTimeout(sub.timer);
+        sub.timer = setTimeout(send, sub.debounce);
+        return;
+      }
+      if (sub.throttle > 0) {
+        const now = Date.now();
+        if (now - sub.last < sub.throttle) return;
+        sub.last = now;
+      }
+      send();
+    }
+    _payload(type, event) {
+      switch (type) {
+        case EventType.RESIZE:
+          return {
+            width: window.innerWidth,
+            height: window.innerHeight
+          };
+        case EventType.SCROLL:
+          return {
+            scrollTop: Math.round(window.scrollY),
+            scrollLeft: Math.round(window.scrollX)
+          };
+        case EventType.KEYDOWN:
+        case EventType.KEYUP:
+          return {
+            key: event.key,
+            code: event.code,
+            ctrlKey: event.ctrlKey,
+            shiftKey: event.shiftKey,
+            altKey: event.altKey,
+            metaKey: event.metaKey,
+            repeat: event.repeat,
+            location: event.location
+          };
+        case EventType.VISIBILITYCHANGE:
+          return {
+            value: document.visibilityState
+          };
+        default:
+          return null;
+      }
+    }
+  }
+  __name(GlobalEvents, "GlobalEvents");
+  // src/uploads.js
+  const BlobChunkSize = 32 * 1024;
+  const BlobWindow = 8 * BlobChunkSize;
+  const FrameBlob = 0x06;
+  class BlobUploader {
+    constructor(client) {
+      this.client = client;
+      this.enabled = false;
+      this.nextId = 1;
+      this.uploads = new Map();
+    }
+    upload(hid, file) {
+      if (!this.enabled || !this.client.connected) {
+        return 0;
+      }
+      const id = this.nextId++;
+      const up = {
+        id,
+        hid,
+        file,
+        sent: 0,
+        acked: 0,
+        ended: false,
+        pumping: false
+      };
+      this.uploads.set(id, up);
+      this._send(this.client.codec.encodeBlob(BlobOp.START, id, {
+        hid,
+        filename: file.name,
+        type: file.type,
+        size: file.size
+      }));
+      this._dispatch(up, 'progress');
+      this._pump(up);
+      return id;
+    }
+    cancel(id) {
+      const up = this.uploads.get(id);
+      if (!up) return;
+      this.uploads.delete(id);
+      this._send(this.client.codec.encodeBlob(BlobOp.CANCEL, id));
+      this._dispatch(up, 'error', 'canceled');
+    }
+    handleAck(buffer) {
+      const {
+        id,
+        received,
+        status,
+        message
+      } = this.client.codec.decodeBlobAck(buffer);
+      const up = this.uploads.get(id);
+      if (!up) return;
+      if (received > up.acked) {
+        up.acked = received;
+      }
+      switch (status) {
+        case BlobStatus.PROGRESS:
+          this._dispatch(up, 'progress');
+          this._pump(up);
+          break;
+        case BlobStatus.DONE:
+          this.uploads.delete(id);
+          this._dispatch(up, 'done');
+          break;
+        case BlobStatus.ERROR:
+          this.uploads.delete(id);
+          this._dispatch(up, 'error', message);
+          break;
+      }
+    }
+    reset() {
+      const uploads = [...this.uploads.values()];
+      this.uploads.clear();
+      for (const up of uploads) {
+        this._dispatch(up, 'error', 'disconnected');
+      }
+    }
+    async _pump(up) {
+      if (up.pumping) return;
+      up.pumping = true;
+      try {
+        while (this.uploads.get(up.id) === up && up.sent < up.file.size) {
+          const end = Math.min(up.sent + BlobChunkSize, up.file.size);
+          if (end - up.acked > BlobWindow) {
+            return;
+          }
+          const data = new Uint8Array(await up.file.slice(up.sent, end).arrayBuffer());
+          if (this.uploads.get(up.id) !== up) {
+            return;
+          }
+          this._send(this.client.codec.encodeBlob(BlobOp.CHUNK, up.id, {
+            offset: up.sent,
+            data
+          }));
+          up.sent = end;
+        }
+        if (this.uploads.get(up.id) === up && !up.ended) {
+          up.ended = true;
+          this._send(this.client.codec.encodeBlob(BlobOp.END, up.id));
+        }
+      } catch (err) {
+        if (this.uploads.get(up.id) === up) {
+          this.cancel(up.id);
+        }
+        this.client._onError(err);
+      } finally {
+        up.pumping = false;
+      }
+    }
+    _send(payload) {
+      for (const frame of this.client.codec.encodeFrames(FrameBlob, payload)) {
+        this.client.wsManager.send(frame);
+      }
+    }
+    _dispatch(up, status, error) {
+      const el = this.client.nodeMap.get(up.hid);
+      if (!el) return;
+      el.dispatchEvent(new CustomEvent('vango:upload', {
+        detail: {
+          id: up.id,
+          name: up.file.name,
+          size: up.file.size,
+          loaded: up.acked,
+          status,
+          error: error || null
+        },
+        bubbles: true
+      }));
+    }
+  }
+  __name(BlobUploader, "BlobUploader");
+  // src/inspector.js
+  const TOGGLE_KEY = 'G';
+  const PANEL_ID = 'vango-inspector';
+  function buildInspectorTree(graph) {
+    const byId = new Map();
+    const children = new Map();
+    const deps = new Map();
+    const subs = new Map();
+    const roots = [];
+    for (const node of graph.nodes || []) {
+      byId.set(node.id, node);
+    }
+    for (const node of graph.nodes || []) {
+      if (node.owner && byId.has(node.owner)) {
+        if (!children.has(node.owner)) children.set(node.owner, []);
+        children.get(node.owner).push(node);
+      } else {
+        roots.push(node);
+      }
+    }
+    for (const edge of graph.edges || []) {
+      if (!deps.has(edge.to)) deps.set(edge.to, []);
+      deps.get(edge.to).push(edge.from);
+      if (!subs.has(edge.from)) subs.set(edge.from, []);
+      subs.get(edge.from).push(edge.to);
+    }
+    return {
+      byId,
+      children,
+      deps,
+      subs,
+      roots
+    };
+  }
+  __name(buildInspectorTree, "buildInspectorTree");
+  function describeNode(node) {
+    let text = `${node.kind} #${node.id}`;
+    if (node.label) text += ` ${node.label}`;
+    if (node.hid) text += ` [${node.hid}]`;
+    if (node.scope) text += ` (${node.scope})`;
+    if (node.persistKey) text += ` key=${node.persistKey}`;
+    if (node.value !== undefined && node.kind !== 'owner' && node.kind !== 'component') {
+      text += ` = ${node.value}`;
+    }
+    text += ` runs=${node.runs}`;
+    if (node.disposed) text += ' disposed';
+    return text;
+  }
+  __name(describeNode, "describeNode");
+  class GraphInspector {
+    constructor(client) {
+      this.client = client;
+      this.panel = null;
+      this.highlighted = null;
+      this._onKeyDown = e => {
+        if (e.altKey && e.shiftKey && e.key.toUpperCase() === TOGGLE_KEY) {
+          e.preventDefault();
+          this.toggle();
+        }
+      };
+    }
+    attach() {
+      document.addEventListener('keydown', this._onKeyDown);
+    }
+    detach() {
+      document.removeEventListener('keydown', this._onKeyDown);
+      this.close();
+    }
+    toggle() {
+      if (this.panel) {
+        this.close();
+      } else {
+        this.open();
+      }
+    }
+    async open() {
+      if (!this.panel) {
+        this.panel = document.createElement('div');
+        this.panel.id = PANEL_ID;
+        this.panel.style.cssText = 'position:fixed;right:0;top:0;bottom:0;width:420px;overflow:auto;' + 'z-index:2147483647;background:#1e1e1e;color:#ddd;font:12px/1.5 monospace;padding:8px;';
+        document.body.appendChild(this.panel);
+      }
+      await this.refresh();
+    }
+    close() {
+      this._highlight(null);
+      if (this.panel) {
+        this.panel.remove();
+        this.panel = null;
+      }
+    }
+    async refresh() {
+      const sessionId = this.client.wsManager.sessionId;
+      if (!sessionId) {
+        this._renderMessage('Not connected');
+        return;
+      }
+      try {
+        const res = await fetch(`/_vango/inspect?session=${encodeURIComponent(sessionId)}`, {
+          cache: 'no-store'
+        });
+        if (!res.ok) {
+          this._renderMessage(`Inspector unavailable (${res.status}); it requires DevMode`);
+          return;
+        }
+        this.render(await res.json());
+      } catch (err) {
+        this._renderMessage(`Inspector request failed: ${err.message}`);
+      }
+    }
+    render(graph) {
+      if (!this.panel) return;
+      const tree = buildInspectorTree(graph);
+      this.panel.textContent = '';
+      const header = document.createElement('div');
+      header.textContent = `${graph.nodes.length} nodes, ${graph.edges.length} edges `;
+      const refresh = document.createElement('button');
+      refresh.textContent = 'Refresh';
+      refresh.addEventListener('click', () => this.refresh());
+      header.appendChild(refresh);
+      this.panel.appendChild(header);
+      const list = document.createElement('ul');
+      list.style.cssText = 'margin:0;padding-left:12px;list-style:none;';
+      for (const node of tree.roots) {
+        list.appendChild(this._renderNode(node, tree));
+      }
+      this.panel.appendChild(list);
+    }
+    _renderNode(node, tree) {
+      const item = document.createElement('li');
+      const line = document.createElement('div');
+      line.textContent = describeNode(node);
+      line.dataset.nodeId = node.id;
+      const links = [];
+      const deps = tree.deps.get(node.id);
+      if (deps) links.push(`← ${deps.map(id => '#' + id).join(' ')}`);
+      const subs = tree.subs.get(node.id);
+      if (subs) links.push(`→ ${subs.map(id => '#' + id).join(' ')}`);
+      if (node.subscribers > (subs ? subs.length : 0)) {
+        links.push(`${node.subscribers} subscriber(s) total`);
+      }
+      if (links.length > 0) {
+        const detail = document.createElement('div');
+        detail.style.cssText = 'color:#888;padding-left:12px;';
+        detail.textContent = links.join('  ');
+        line.appendChild(detail);
+      }
+      if (node.hid) {
+        line.addEventListener('mouseenter', () => this._highlight(this.client.nodeMap.get(node.hid)));
+        line.addEventListener('mouseleave', () => this._highlight(null));
+      }
+      item.appendChild(line);
+      const children = tree.children.get(node.id);
+      if (children) {
+        const list = document.createElement('ul');
+        list.style.cssText = 'margin:0;padding-left:12px;list-style:none;';
+        for (const child of children) {
+          list.appendChild(this._renderNode(child, tree));
+        }
+        item.appendChild(list);
+      }
+      return item;
+    }
+    _renderMessage(message) {
+      if (!this.panel) return;
+      this.panel.textContent = message;
+    }
+    _highlight(el) {
+      if (this.highlighted) {
+        this.highlighted.style.outline = this.highlighted.__vangoOutline || '';
+        this.highlighted = null;
+      }
+      if (el) {
+        el.__vangoOutline = el.style.outline;
+        el.style.outline = '2px solid #e91e63';
+        this.highlighted = el;
+      }
+    }
+  }
+  __name(GraphInspector, "GraphInspector");
   // src/index.js
-  var FrameType = {
-    HANDSHAKE: 0,
-    EVENT: 1,
-    PATCHES: 2,
-    CONTROL: 3,
-    ACK: 4,
-    ERROR: 5
+  const FrameType = {
+    HANDSHAKE: 0x00,
+    EVENT: 0x01,
+    PATCHES: 0x02,
+    CONTROL: 0x03,
+    ACK: 0x04,
+    ERROR: 0x05,
+    BLOB: 0x06
+  };
+  const MaxFramePayload2 = 0xFFFF;
+  const ControlType = {
+    PING: 0x01,
+    PONG: 0x02,
+    RESYNC_REQUEST: 0x10,
+    RESYNC_PATCHES: 0x11,
+    RESYNC_FULL: 0x12,
+    HOOK_REVERT: 0x30,
+    AUTH_COMMAND: 0x31,
+    BLOB_ACK: 0x40,
+    CLIENT_ENV: 0x41,
+    PREF_SYNC: 0x42,
+    GLOBAL_LISTEN: 0x43,
+    GLOBAL_UNLISTEN: 0x44,
+    HOOK_CALL: 0x45,
+    HOOK_RESULT: 0x46,
+    CLOSE: 0x20
   };
-  var ControlType = {
-    PING: 1,
-    PONG: 2,
-    RESYNC_REQUEST: 16,
-    // Client -> Server: request missed patches
-    RESYNC_PATCHES: 17,
-    // Server -> Client: replay missed patches (not used with frame replay)
-    RESYNC_FULL: 18,
-    // Server -> Client: full HTML replacement
-    CLOSE: 32
+  const AuthAction = {
+    FORCE_RELOAD: 0x01,
+    HARD_NAVIGATE: 0x02,
+    BROADCAST: 0x03
   };
-  var _VangoClient = class _VangoClient {
+  class VangoClient {
     constructor(options = {}) {
       this.options = {
         wsUrl: options.wsUrl || this._defaultWsUrl(),
         reconnect: options.reconnect !== false,
-        reconnectInterval: options.reconnectInterval || 1e3,
-        reconnectMaxInterval: options.reconnectMaxInterval || 3e4,
-        heartbeatInterval: options.heartbeatInterval || 3e4,
+        reconnectInterval: options.reconnectInterval || 1000,
+        reconnectMaxInterval: options.reconnectMaxInterval || 30000,
+        heartbeatInterval: options.heartbeatInterval || 30000,
         debug: options.debug || false,
         ...options
       };
       this.codec = new BinaryCodec();
-      this.nodeMap = /* @__PURE__ */ new Map();
+      this.decompressor = null;
+      this.reassembler = new FrameReassembler(options.maxMessageSize);
+      this.nodeMap = new Map();
       this.connected = false;
       this.seq = 0;
       this.patchSeq = 0;
       this.expectedPatchSeq = 1;
       this.pendingResync = false;
+      this.protocolVersion = ProtocolVersion;
       this.wsManager = new WebSocketManager(this, this.options);
       this.patchApplier = new PatchApplier(this);
       this.eventCapture = new EventCapture(this);
       this.optimistic = new OptimisticUpdates(this);
       this.hooks = new HookManager(this);
+      this.islands = new IslandManager(this);
+      this.uploads = new BlobUploader(this);
+      this.env = new ClientEnvReporter(this, options);
       this.connection = new ConnectionManager({
         toastOnReconnect: options.toastOnReconnect || window.__VANGO_TOAST_ON_RECONNECT__,
-        toastMessage: options.toastMessage || "Connection restored",
+        toastMessage: options.toastMessage || 'Connection restored',
         maxRetries: options.maxRetries || 10,
-        baseDelay: options.reconnectInterval || 1e3,
-        maxDelay: options.reconnectMaxInterval || 3e4,
+        baseDelay: options.reconnectInterval || 1000,
+        maxDelay: options.reconnectMaxInterval || 30000,
         debug: options.debug
       });
-      this.urlManager = new URLManager(this, { debug: options.debug });
-      this.prefs = new PrefManager(this, { debug: options.debug });
-      this.onConnect = options.onConnect || (() => {
+      this.urlManager = new URLManager(this, {
+        debug: options.debug
+      });
+      this.prefs = new PrefManager(this, {
+        debug: options.debug
       });
-      this.onDisconnect = options.onDisconnect || (() => {
+      this.globalEvents = new GlobalEvents(this, {
+        debug: options.debug
       });
-      this.onError = options.onError || ((err) => console.error("[Vango]", err));
+      this.inspector = this.options.debug ? new GraphInspector(this) : null;
+      this._authChannel = 'vango:auth';
+      this._authBroadcast = null;
+      this._authStorageHandler = null;
+      this._authReloadScheduled = false;
+      this._authPollUrl = options.authPollUrl || '';
+      this._authPollInterval = options.authPollInterval || 30000;
+      this._authPollTimer = null;
+      this.onConnect = options.onConnect || (() => {});
+      this.onDisconnect = options.onDisconnect || (() => {});
+      this.onError = options.onError || (err => console.error('[Vango]', err));
       ensurePortalRoot();
       injectDefaultStyles();
       this._buildNodeMap();
+      this._initAuthBroadcast();
       this.wsManager.connect(this.options.wsUrl);
       this.eventCapture.attach();
+      this.env.attach();
       this.hooks.initializeFromDOM();
+      this.islands.initializeFromDOM();
+      if (this.inspector) {
+        this.inspector.attach();
+      }
     }
-    /**
-     * Build initial map of data-hid -> DOM node
-     */
     _buildNodeMap() {
-      document.querySelectorAll("[data-hid]").forEach((el) => {
+      mountPortals(document);
+      document.querySelectorAll('[data-hid]').forEach(el => {
         const hid = el.dataset.hid;
         this.nodeMap.set(hid, el);
       });
@@ -4190,213 +6708,444 @@ var Vango = (() => {
         console.log(`[Vango] Mapped ${this.nodeMap.size} nodes`);
       }
     }
-    /**
-     * Get default WebSocket URL based on current page
-     */
     _defaultWsUrl() {
-      const protocol = location.protocol === "https:" ? "wss:" : "ws:";
+      const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
       const path = encodeURIComponent(location.pathname + location.search);
       return `${protocol}//${location.host}/_vango/live?path=${path}`;
     }
-    /**
-     * Called when WebSocket connection established
-     */
     _onConnected() {
       this.connected = true;
+      this.globalEvents.reset();
       this.connection.onConnect();
       this.onConnect();
     }
-    /**
-     * Called when WebSocket disconnected
-     */
     _onDisconnected() {
       this.connected = false;
+      this.uploads.reset();
       this.connection.onDisconnect();
       this.onDisconnect();
     }
-    /**
-     * Called on WebSocket error
-     */
     _onError(err) {
       this.onError(err);
     }
-    /**
-     * Handle binary message from server
-     */
+    _handleProtocolError(err, context) {
+      const message = err instanceof Error ? err.message : String(err);
+      if (this.options.debug) {
+        console.error('[Vango] Protocol error:', context || 'decode failure', err);
+      } else {
+        console.error('[Vango] Protocol error:', context || 'decode failure', message);
+      }
+      const error = err instanceof Error ? err : new Error(message);
+      this._onError(error);
+      this.wsManager.close();
+      this._triggerSelfHeal();
+    }
+    _triggerSelfHeal() {
+      var _a;
+      const pendingPath = (_a = this.eventCapture) == null ? void 0 : _a.pendingNavPath;
+      if (pendingPath) {
+        console.log('[Vango] Self-heal: navigating to pending path:', pendingPath);
+        location.assign(pendingPath);
+      } else {
+        console.log('[Vango] Self-heal: reloading page');
+        location.reload();
+      }
+    }
+    retryConnect() {
+      this.wsManager.resetReconnect();
+      this.connection.setState(ConnectionState.CONNECTING);
+      this.wsManager.connect(this.options.wsUrl);
+    }
     _handleBinaryMessage(buffer) {
-      if (buffer.length < 4)
+      if (buffer.length < 4) {
+        this._handleProtocolError(new Error('Frame header too short'), 'frame header');
         return;
+      }
       const frameType = buffer[0];
       const length = buffer[2] << 8 | buffer[3];
-      const payload = buffer.slice(4, 4 + length);
-      switch (frameType) {
-        case FrameType.PATCHES:
-          this._handlePatches(payload);
-          break;
-        case FrameType.CONTROL:
-          this._handleControl(payload);
-          break;
-        case FrameType.ERROR:
-          this._handleServerError(payload);
-          break;
-        default:
-          if (this.options.debug) {
-            console.warn("[Vango] Unknown frame type:", frameType);
-          }
+      if (length > MaxFramePayload2) {
+        this._handleProtocolError(new Error('Frame payload exceeds max size'), 'frame header');
+        return;
+      }
+      if (buffer.length !== 4 + length) {
+        this._handleProtocolError(new Error('Frame length mismatch'), 'frame header');
+        return;
+      }
+      let payload = buffer.slice(4, 4 + length);
+      try {
+        const message = this.reassembler.add(frameType, buffer[1], payload);
+        if (!message) {
+          return;
+        }
+        payload = message.payload;
+        if (message.flags & FrameFlags.COMPRESSED) {
+          payload = this.codec.decompressPayload(payload, this.decompressor, this.reassembler.maxSize);
+        }
+        switch (frameType) {
+          case FrameType.PATCHES:
+            this._handlePatches(payload);
+            break;
+          case FrameType.CONTROL:
+            this._handleControl(payload);
+            break;
+          case FrameType.ERROR:
+            this._handleServerError(payload);
+            break;
+          default:
+            if (this.options.debug) {
+              console.warn('[Vango] Unknown frame type:', frameType);
+            }
+        }
+      } catch (err) {
+        this._handleProtocolError(err, `frame type ${frameType}`);
       }
     }
-    /**
-     * Handle patches frame
-     */
     _handlePatches(buffer) {
       if (this.options.debug) {
-        console.log("[Vango] Received patches buffer, length:", buffer.length);
+        console.log('[Vango] Received patches buffer, length:', buffer.length);
       }
-      const { seq, patches } = this.codec.decodePatches(buffer);
+      const {
+        seq,
+        patches
+      } = this.codec.decodePatches(buffer);
       if (this.options.debug) {
-        console.log("[Vango] Decoded", patches.length, "patches, seq:", seq);
+        console.log('[Vango] Decoded', patches.length, 'patches, seq:', seq);
       }
       if (seq < this.expectedPatchSeq) {
         if (this.options.debug) {
-          console.log("[Vango] Ignoring duplicate patch seq:", seq, "expected:", this.expectedPatchSeq);
+          console.log('[Vango] Ignoring duplicate patch seq:', seq, 'expected:', this.expectedPatchSeq);
         }
         return;
       }
       if (seq > this.expectedPatchSeq) {
-        console.warn(
-          "[Vango] Patch sequence gap detected",
-          "expected:",
-          this.expectedPatchSeq,
-          "received:",
-          seq
-        );
+        console.warn('[Vango] Patch sequence gap detected', 'expected:', this.expectedPatchSeq, 'received:', seq);
         this._requestResync(this.patchSeq);
         return;
       }
       if (this.options.debug) {
         for (const p of patches) {
-          console.log("[Vango] Patch:", p);
+          console.log('[Vango] Patch:', p);
         }
-        console.log("[Vango] Applying", patches.length, "patches (seq:", seq, ")");
+        console.log('[Vango] Applying', patches.length, 'patches (seq:', seq, ')');
       }
       this.optimistic.clearPending();
       this.patchApplier.apply(patches);
       this.hooks.updateFromDOM();
+      this.islands.updateFromDOM();
       this.patchSeq = seq;
       this.expectedPatchSeq = seq + 1;
       this.pendingResync = false;
+      this.wsManager.updateLastSeq(this.patchSeq);
       this._sendAck(seq);
     }
-    /**
-     * Handle control message
-     */
     _handleControl(buffer) {
-      if (buffer.length === 0)
-        return;
+      if (buffer.length === 0) {
+        throw new Error('Control frame empty');
+      }
       const controlType = buffer[0];
       switch (controlType) {
         case ControlType.PONG:
           if (this.options.debug) {
-            console.log("[Vango] Pong received");
+            console.log('[Vango] Pong received');
           }
           break;
         case ControlType.RESYNC_FULL:
           this._handleResyncFull(buffer.slice(1));
           break;
+        case ControlType.HOOK_REVERT:
+          {
+            const {
+              value: hid
+            } = this.codec.decodeString(buffer, 1);
+            this.hooks.revert(hid);
+            break;
+          }
+        case ControlType.AUTH_COMMAND:
+          this._handleAuthCommand(buffer.slice(1));
+          break;
+        case ControlType.BLOB_ACK:
+          this.uploads.handleAck(buffer.subarray(1));
+          break;
+        case ControlType.PREF_SYNC:
+          this.prefs.handleSync(this.codec.decodePrefSync(buffer, 1));
+          break;
+        case ControlType.GLOBAL_LISTEN:
+          this.globalEvents.listen(this.codec.decodeGlobalListen(buffer, 1));
+          break;
+        case ControlType.GLOBAL_UNLISTEN:
+          {
+            const {
+              value: id
+            } = this.codec.decodeString(buffer, 1);
+            this.globalEvents.unlisten(id);
+            break;
+          }
+        case ControlType.HOOK_CALL:
+          this._handleHookCall(this.codec.decodeHookCall(buffer, 1));
+          break;
         case ControlType.CLOSE:
           this.wsManager.close();
           break;
         default:
           if (this.options.debug) {
-            console.log("[Vango] Unknown control type:", controlType);
+            console.log('[Vango] Unknown control type:', controlType);
           }
       }
     }
-    /**
-     * Handle ResyncFull - replace body content with server-sent HTML
-     * Used during session resume to ensure client DOM matches server state
-     */
-    _handleResyncFull(buffer) {
-      if (buffer.length === 0)
+    async _handleHookCall(call) {
+      let result = null;
+      let error = '';
+      try {
+        result = await this.hooks.call(call.hid, call.method, call.args);
+      } catch (err) {
+        error = (err == null ? void 0 : err.message) || String(err);
+      }
+      if (!this.connected) return;
+      const payload = this.codec.encodeHookResult(call.id, result, error);
+      for (const frame of this.codec.encodeFrames(FrameType.CONTROL, payload)) {
+        this.wsManager.send(frame);
+      }
+      if (this.options.debug) {
+        console.log('[Vango] Hook call:', call.hid, call.method, error || result);
+      }
+    }
+    _handleAuthCommand(buffer) {
+      if (buffer.length < 2) {
+        return;
+      }
+      const action = buffer[0];
+      const reason = buffer[1];
+      let offset = 2;
+      const readOptionalString = () => {
+        if (offset >= buffer.length) {
+          return '';
+        }
+        const {
+          value,
+          bytesRead
+        } = this.codec.decodeString(buffer, offset);
+        offset += bytesRead;
+        return value;
+      };
+      const path = readOptionalString();
+      const channel = readOptionalString() || 'vango:auth';
+      const type = readOptionalString() || 'expired';
+      switch (action) {
+        case AuthAction.FORCE_RELOAD:
+          this._broadcastAuth(channel, type, reason);
+          this.wsManager.clearResumeInfo();
+          setTimeout(() => location.reload(), 0);
+          break;
+        case AuthAction.HARD_NAVIGATE:
+          {
+            this._broadcastAuth(channel, type, reason);
+            this.wsManager.clearResumeInfo();
+            const target = path || location.pathname;
+            setTimeout(() => location.assign(target), 0);
+            break;
+          }
+        case AuthAction.BROADCAST:
+          this._broadcastAuth(channel, type, reason);
+          break;
+        default:
+          if (this.options.debug) {
+            console.log('[Vango] Unknown auth action:', action);
+          }
+      }
+    }
+    _initAuthBroadcast() {
+      if (typeof BroadcastChannel !== 'undefined') {
+        this._authBroadcast = new BroadcastChannel(this._authChannel);
+        this._authBroadcast.onmessage = event => {
+          this._handleAuthBroadcast(event.data);
+        };
+        return;
+      }
+      if (!this._canUseLocalStorage()) {
+        this._startAuthPolling();
+        return;
+      }
+      if (typeof window === 'undefined' || !window.addEventListener) {
+        this._startAuthPolling();
+        return;
+      }
+      this._authStorageHandler = event => {
+        if (!event || !event.key || event.key !== `__vango_auth_${this._authChannel}`) {
+          return;
+        }
+        if (!event.newValue) {
+          return;
+        }
+        try {
+          const msg = JSON.parse(event.newValue);
+          this._handleAuthBroadcast(msg.payload);
+        } catch (_e) {}
+      };
+      window.addEventListener('storage', this._authStorageHandler);
+    }
+    _handleAuthBroadcast(payload) {
+      if (!payload || payload.type !== 'expired' && payload.type !== 'logout') {
+        return;
+      }
+      this._scheduleAuthReload(payload);
+    }
+    _scheduleAuthReload(payload) {
+      if (this._authReloadScheduled) {
         return;
-      const { value: html } = this.codec.decodeString(buffer, 0);
+      }
+      this._authReloadScheduled = true;
       if (this.options.debug) {
-        console.log("[Vango] ResyncFull received, replacing body content");
+        console.log('[Vango] Auth reload scheduled:', payload || {});
+      }
+      setTimeout(() => location.reload(), 0);
+    }
+    _startAuthPolling() {
+      if (!this._authPollUrl || this._authPollInterval <= 0) {
+        return;
+      }
+      if (this._authPollTimer) {
+        return;
+      }
+      const pollOnce = async () => {
+        try {
+          const res = await fetch(this._authPollUrl, {
+            method: 'GET',
+            credentials: 'same-origin',
+            cache: 'no-store',
+            headers: {
+              'X-Vango-Auth-Poll': '1'
+            }
+          });
+          if (res.status === 401) {
+            this._scheduleAuthReload({
+              type: 'expired',
+              reason: 'poll'
+            });
+          }
+        } catch (_e2) {}
+      };
+      pollOnce();
+      this._authPollTimer = setInterval(pollOnce, this._authPollInterval);
+    }
+    _stopAuthPolling() {
+      if (!this._authPollTimer) {
+        return;
+      }
+      clearInterval(this._authPollTimer);
+      this._authPollTimer = null;
+    }
+    _canUseLocalStorage() {
+      try {
+        if (typeof localStorage === 'undefined') {
+          return false;
+        }
+        const key = '__vango_auth_probe__';
+        localStorage.setItem(key, '1');
+        localStorage.removeItem(key);
+        return true;
+      } catch (_e3) {
+        return false;
       }
-      const template = document.createElement("template");
+    }
+    _broadcastAuth(channel, type, reason) {
+      const payload = {
+        type,
+        reason
+      };
+      if (typeof BroadcastChannel !== 'undefined') {
+        const bc = new BroadcastChannel(channel);
+        bc.postMessage(payload);
+        bc.close();
+        return;
+      }
+      try {
+        if (typeof localStorage === 'undefined') {
+          return;
+        }
+        const key = `__vango_auth_${channel}`;
+        const msg = JSON.stringify({
+          payload,
+          ts: Date.now()
+        });
+        localStorage.setItem(key, msg);
+        localStorage.removeItem(key);
+      } catch (_e4) {}
+    }
+    _handleResyncFull(buffer) {
+      if (buffer.length === 0) {
+        throw new Error('ResyncFull payload empty');
+      }
+      const {
+        value: html
+      } = this.codec.decodeString(buffer, 0);
+      if (this.options.debug) {
+        console.log('[Vango] ResyncFull received, replacing body content');
+      }
+      const template = document.createElement('template');
       template.innerHTML = html;
       this.nodeMap.clear();
       this.hooks.destroyAll();
-      document.body.innerHTML = "";
+      this.islands.destroyAll();
+      document.body.innerHTML = '';
       while (template.content.firstChild) {
         document.body.appendChild(template.content.firstChild);
       }
       this._buildNodeMap();
       this.hooks.initializeFromDOM();
+      this.islands.initializeFromDOM();
       this.patchSeq = 0;
       this.expectedPatchSeq = 1;
       this.pendingResync = false;
+      this.wsManager.updateLastSeq(0);
     }
-    /**
-     * Send ACK for received patches
-     * Format: [lastSeq:varint][window:varint]
-     */
     _sendAck(lastSeq) {
-      if (!this.connected)
-        return;
+      if (!this.connected) return;
       const payload = this.codec.encodeAck(lastSeq, 100);
       const frame = this._encodeFrame(FrameType.ACK, payload);
       this.wsManager.send(frame);
       if (this.options.debug) {
-        console.log("[Vango] Sent ACK for seq:", lastSeq);
+        console.log('[Vango] Sent ACK for seq:', lastSeq);
       }
     }
-    /**
-     * Request resync for missed patches (with debouncing)
-     * Format: [controlType:1][lastSeq:varint]
-     */
     _requestResync(lastSeq) {
       if (this.pendingResync) {
         if (this.options.debug) {
-          console.log("[Vango] Resync already pending, skipping");
+          console.log('[Vango] Resync already pending, skipping');
         }
         return;
       }
       this.pendingResync = true;
       if (this.options.debug) {
-        console.log("[Vango] Requesting resync from seq:", lastSeq);
+        console.log('[Vango] Requesting resync from seq:', lastSeq);
       }
       const payload = this.codec.encodeResyncRequest(lastSeq);
       const frame = this._encodeFrame(FrameType.CONTROL, payload);
       this.wsManager.send(frame);
     }
-    /**
-     * Handle server error
-     *
-     * Per spec Section 9.6.2 (error wire format):
-     * Wire format: [uint16:code][varint-string:message][bool:fatal]
-     * See vango/pkg/protocol/error.go for encoding.
-     */
     _handleServerError(buffer) {
-      if (buffer.length < 3)
-        return;
+      if (buffer.length < 3) {
+        throw new Error('Error frame too short');
+      }
       const code = buffer[0] << 8 | buffer[1];
-      const { value: message, bytesRead } = this.codec.decodeString(buffer, 2);
+      const {
+        value: message,
+        bytesRead
+      } = this.codec.decodeString(buffer, 2);
       const fatalOffset = 2 + bytesRead;
       const fatal = fatalOffset < buffer.length ? buffer[fatalOffset] === 1 : false;
       const errorMessages = {
-        0: "Unknown error",
-        1: "Invalid frame",
-        2: "Invalid event",
-        3: "Handler not found",
-        4: "Handler panic",
-        5: "Session expired",
-        6: "Rate limited",
-        256: "Server error",
-        257: "Not authorized",
-        258: "Not found",
-        259: "Validation failed",
-        260: "Route error"
+        0x0000: 'Unknown error',
+        0x0001: 'Invalid frame',
+        0x0002: 'Invalid event',
+        0x0003: 'Handler not found',
+        0x0004: 'Handler panic',
+        0x0005: 'Session expired',
+        0x0006: 'Rate limited',
+        0x0100: 'Server error',
+        0x0101: 'Not authorized',
+        0x0102: 'Not found',
+        0x0103: 'Validation failed',
+        0x0104: 'Route error'
       };
       const errorMessage = errorMessages[code] || message || `Unknown error: ${code}`;
       const error = new Error(errorMessage);
@@ -4408,107 +7157,112 @@ var Vango = (() => {
         this.wsManager.close();
       }
     }
-    /**
-     * Send event to server
-     */
     sendEvent(type, hid, data = null) {
       if (!this.connected) {
         if (this.options.debug) {
-          console.log("[Vango] Not connected, queueing event");
+          console.log('[Vango] Not connected, queueing event');
         }
       }
       this.seq++;
       const eventBuffer = this.codec.encodeEvent(this.seq, type, hid, data);
-      const frame = this._encodeFrame(FrameType.EVENT, eventBuffer);
-      this.wsManager.send(frame);
+      const frames = this.codec.encodeFrames(FrameType.EVENT, eventBuffer);
+      if (frames.length > 1 && !versionAtLeast(this.protocolVersion, 2, 1)) {
+        this.onError(new Error(`Event of ${eventBuffer.length} bytes is too large for protocol ${this.protocolVersion.major}.${this.protocolVersion.minor}`));
+        return;
+      }
+      for (const frame of frames) {
+        this.wsManager.send(frame);
+      }
       if (this.options.debug) {
-        console.log("[Vango] Sent event:", { type, hid, data, seq: this.seq });
+        console.log('[Vango] Sent event:', {
+          type,
+          hid,
+          data,
+          seq: this.seq
+        });
       }
     }
-    /**
-     * Encode a frame with proper header
-     * Format: [type:1][flags:1][length:2 big-endian][payload]
-     */
     _encodeFrame(frameType, payload) {
       const length = payload.length;
       const frame = new Uint8Array(4 + length);
       frame[0] = frameType;
       frame[1] = 0;
-      frame[2] = length >> 8 & 255;
-      frame[3] = length & 255;
+      frame[2] = length >> 8 & 0xFF;
+      frame[3] = length & 0xFF;
       frame.set(payload, 4);
       return frame;
     }
-    /**
-     * Send hook event to server
-     */
     sendHookEvent(hid, eventName, data = {}) {
-      this.sendEvent(EventType.HOOK, hid, { name: eventName, data });
+      this.sendEvent(EventType.HOOK, hid, {
+        name: eventName,
+        data
+      });
+    }
+    sendIslandMessage(id, data = {}) {
+      const size = this.codec.encodeEvent(this.seq + 1, EventType.ISLAND, id, {
+        data
+      }).length;
+      if (size > MaxFramePayload2) {
+        console.error('[Vango] Island message exceeds max frame size, dropped:', id);
+        return false;
+      }
+      this.sendEvent(EventType.ISLAND, id, {
+        data
+      });
+      return true;
+    }
+    registerIsland(src, module) {
+      this.islands.register(src, module);
     }
-    /**
-     * Get DOM node by hydration ID
-     */
     getNode(hid) {
       return this.nodeMap.get(hid);
     }
-    /**
-     * Register a node in the map
-     */
     registerNode(hid, node) {
       this.nodeMap.set(hid, node);
     }
-    /**
-     * Unregister a node from the map
-     */
     unregisterNode(hid) {
       this.nodeMap.delete(hid);
     }
-    /**
-     * Register a custom hook
-     */
     registerHook(name, hookClass) {
       this.hooks.register(name, hookClass);
     }
-    /**
-     * Register a preference
-     * @param {string} key - Unique preference key
-     * @param {*} defaultValue - Default value
-     * @param {Object} options - Configuration options
-     * @returns {Pref} Preference instance
-     */
     registerPref(key, defaultValue, options = {}) {
       return this.prefs.register(key, defaultValue, options);
     }
-    /**
-     * Disconnect and cleanup
-     */
     destroy() {
       this.eventCapture.detach();
       this.hooks.destroyAll();
+      this.islands.destroyAll();
       this.prefs.destroy();
+      this.globalEvents.reset();
+      if (this._authBroadcast) {
+        this._authBroadcast.close();
+        this._authBroadcast = null;
+      }
+      if (this._authStorageHandler && typeof window !== 'undefined') {
+        window.removeEventListener('storage', this._authStorageHandler);
+      }
+      this._stopAuthPolling();
       this.wsManager.close();
     }
-  };
-  __name(_VangoClient, "VangoClient");
-  var VangoClient = _VangoClient;
+  }
+  __name(VangoClient, "VangoClient");
   function init() {
     if (window.__vango__) {
       return;
     }
-    const script = document.currentScript || document.querySelector("script[data-vango]");
+    const script = document.currentScript || document.querySelector('script[data-vango]');
     const options = {};
     if (script) {
-      if (script.dataset.wsUrl)
-        options.wsUrl = script.dataset.wsUrl;
-      if (script.dataset.debug)
-        options.debug = script.dataset.debug === "true";
+      if (script.dataset.wsUrl) options.wsUrl = script.dataset.wsUrl;
+      if (script.dataset.debug) options.debug = script.dataset.debug === 'true';
     }
     window.__vango__ = new VangoClient(options);
   }
   __name(init, "init");
-  if (typeof document !== "undefined") {
-    if (document.readyState === "loading") {
-      document.addEventListener("DOMContentLoaded", init);
+  if (typeof document !== 'undefined') {
+    if (document.readyState === 'loading') {
+      document.addEventListener('DOMContentLoaded', init);
     } else {
       init();
     }
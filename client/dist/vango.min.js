@@ -1,4 +1,4 @@
-var Vango=(()=>{var G=Object.defineProperty;var re=Object.getOwnPropertyDescriptor;var ae=Object.getOwnPropertyNames;var he=Object.prototype.hasOwnProperty;var ce=(l,e)=>{for(var t in e)G(l,t,{get:e[t],enumerable:!0})},le=(l,e,t,s)=>{if(e&&typeof e=="object"||typeof e=="function")for(let n of ae(e))!he.call(l,n)&&n!==t&&G(l,n,{get:()=>e[n],enumerable:!(s=re(e,n))||s.enumerable});return l};var de=l=>le(G({},"__esModule",{value:!0}),l);var Ee={};ce(Ee,{ConnectionManager:()=>E,ConnectionState:()=>g,EventType:()=>d,VangoClient:()=>C,default:()=>ye});function y(l){let e=0;for(let n=0;n<l.length;n++)e+=l[n].length;let t=new Uint8Array(e),s=0;for(let n=0;n<l.length;n++)t.set(l[n],s),s+=l[n].length;return t}var d={CLICK:1,DBLCLICK:2,MOUSEDOWN:3,MOUSEUP:4,MOUSEMOVE:5,MOUSEENTER:6,MOUSELEAVE:7,INPUT:16,CHANGE:17,SUBMIT:18,FOCUS:19,BLUR:20,KEYDOWN:32,KEYUP:33,KEYPRESS:34,SCROLL:48,RESIZE:49,TOUCHSTART:64,TOUCHMOVE:65,TOUCHEND:66,DRAGSTART:80,DRAGEND:81,DROP:82,HOOK:96,NAVIGATE:112,CUSTOM:255},c={SET_TEXT:1,SET_ATTR:2,REMOVE_ATTR:3,INSERT_NODE:4,REMOVE_NODE:5,MOVE_NODE:6,REPLACE_NODE:7,SET_VALUE:8,SET_CHECKED:9,SET_SELECTED:10,FOCUS:11,BLUR:12,SCROLL_TO:13,ADD_CLASS:16,REMOVE_CLASS:17,TOGGLE_CLASS:18,SET_STYLE:19,REMOVE_STYLE:20,SET_DATA:21,DISPATCH:32,URL_PUSH:48,URL_REPLACE:49,NAV_PUSH:50,NAV_REPLACE:51},f={CTRL:1,SHIFT:2,ALT:4,META:8},J={ELEMENT:1,TEXT:2,FRAGMENT:3},m={NULL:0,BOOL:1,INT:2,FLOAT:3,STRING:4,ARRAY:5,OBJECT:6},ue=10,pe=4*1024*1024,ge=16*1024*1024,fe=1e5,_e=256,Z=128,ve=65535,k=class{constructor(){this.textEncoder=new TextEncoder,this.textDecoder=new TextDecoder}encodeEvent(e,t,s,n=null){let i=[];switch(i.push(this.encodeUvarint(e)),i.push(new Uint8Array([t])),i.push(this.encodeString(s)),t){case d.INPUT:case d.CHANGE:i.push(this.encodeString((n==null?void 0:n.value)||""));break;case d.SUBMIT:this.encodeFormData(i,n);break;case d.KEYDOWN:case d.KEYUP:case d.KEYPRESS:this.encodeKeyboardEvent(i,n);break;case d.MOUSEDOWN:case d.MOUSEUP:case d.MOUSEMOVE:this.encodeMouseEvent(i,n);break;case d.SCROLL:this.encodeScrollEvent(i,n);break;case d.RESIZE:this.encodeResizeEvent(i,n);break;case d.TOUCHSTART:case d.TOUCHMOVE:case d.TOUCHEND:this.encodeTouchEvent(i,n);break;case d.DRAGSTART:case d.DRAGEND:case d.DROP:this.encodeDragEvent(i,n);break;case d.HOOK:this.encodeHookEvent(i,n);break;case d.NAVIGATE:i.push(this.encodeString((n==null?void 0:n.path)||"")),i.push(new Uint8Array([n!=null&&n.replace?1:0]));break;case d.CUSTOM:i.push(this.encodeString((n==null?void 0:n.name)||"")),i.push(this.encodeLenBytes((n==null?void 0:n.data)||new Uint8Array(0)));break;case d.CLICK:case d.DBLCLICK:case d.FOCUS:case d.BLUR:case d.MOUSEENTER:case d.MOUSELEAVE:default:break}return y(i)}decodePatches(e){return this._decodePatchesWithDepth(e,0)}_decodePatchesWithDepth(e,t){if(t>Z)throw new Error("Protocol decode: patch depth exceeded");let s=0,{value:n,bytesRead:i}=this.decodeUvarint(e,s);s+=i;let{value:o,bytesRead:r}=this.decodeCollectionCount(e,s);s+=r;let a=[];for(let h=0;h<o;h++){let{patch:u,bytesRead:p}=this.decodePatch(e,s,t+1);a.push(u),s+=p}return{seq:n,patches:a}}decodePatch(e,t,s){if(s>Z)throw new Error("Protocol decode: patch depth exceeded");let n=t,i={};this._ensureAvailable(e,t,1,"patch type"),i.type=e[t++];let{value:o,bytesRead:r}=this.decodeString(e,t);switch(i.hid=o,t+=r,i.type){case c.SET_TEXT:case c.SET_VALUE:{let{value:a,bytesRead:h}=this.decodeString(e,t);i.value=a,t+=h;break}case c.SET_ATTR:case c.SET_STYLE:case c.SET_DATA:{let{value:a,bytesRead:h}=this.decodeString(e,t);t+=h;let{value:u,bytesRead:p}=this.decodeString(e,t);t+=p,i.key=a,i.value=u;break}case c.REMOVE_ATTR:case c.REMOVE_STYLE:{let{value:a,bytesRead:h}=this.decodeString(e,t);i.key=a,t+=h;break}case c.ADD_CLASS:case c.REMOVE_CLASS:case c.TOGGLE_CLASS:{let{value:a,bytesRead:h}=this.decodeString(e,t);i.className=a,t+=h;break}case c.INSERT_NODE:{let{value:a,bytesRead:h}=this.decodeString(e,t);t+=h;let{value:u,bytesRead:p}=this.decodeUvarint(e,t);t+=p;let{vnode:_,bytesRead:v}=this.decodeVNode(e,t,0);t+=v,i.parentID=a,i.index=u,i.vnode=_;break}case c.REMOVE_NODE:break;case c.MOVE_NODE:{let{value:a,bytesRead:h}=this.decodeString(e,t);t+=h;let{value:u,bytesRead:p}=this.decodeUvarint(e,t);t+=p,i.parentID=a,i.index=u;break}case c.REPLACE_NODE:{let{vnode:a,bytesRead:h}=this.decodeVNode(e,t,0);t+=h,i.vnode=a;break}case c.SET_CHECKED:case c.SET_SELECTED:{this._ensureAvailable(e,t,1,"boolean"),i.value=e[t++]===1;break}case c.FOCUS:case c.BLUR:break;case c.SCROLL_TO:{let{value:a,bytesRead:h}=this.decodeSvarint(e,t);t+=h;let{value:u,bytesRead:p}=this.decodeSvarint(e,t);t+=p,i.x=a,i.y=u,this._ensureAvailable(e,t,1,"scroll behavior"),i.behavior=e[t++];break}case c.DISPATCH:{let{value:a,bytesRead:h}=this.decodeString(e,t);t+=h;let{value:u,bytesRead:p}=this.decodeString(e,t);t+=p,i.eventName=a,i.detail=u;break}case c.URL_PUSH:case c.URL_REPLACE:{let{value:a,bytesRead:h}=this.decodeCollectionCount(e,t);t+=h,i.params={};for(let u=0;u<a;u++){let{value:p,bytesRead:_}=this.decodeString(e,t);t+=_;let{value:v,bytesRead:z}=this.decodeString(e,t);t+=z,i.params[p]=v}i.op=i.type;break}case c.NAV_PUSH:case c.NAV_REPLACE:{let{value:a,bytesRead:h}=this.decodeString(e,t);t+=h,i.path=a;break}default:throw new Error(`Protocol decode: unknown patch type ${i.type}`)}return{patch:i,bytesRead:t-n}}decodeVNode(e,t,s=0){if(s>_e)throw new Error("Protocol decode: VNode depth exceeded");let n=t,i={};this._ensureAvailable(e,t,1,"vnode type");let o=e[t++];if(o===255)return{vnode:null,bytesRead:t-n};switch(o){case J.ELEMENT:{i.type="element";let{value:r,bytesRead:a}=this.decodeString(e,t);i.tag=r,t+=a;let{value:h,bytesRead:u}=this.decodeString(e,t);i.hid=h||null,t+=u;let{value:p,bytesRead:_}=this.decodeCollectionCount(e,t);t+=_,i.attrs={};for(let b=0;b<p;b++){let{value:W,bytesRead:X}=this.decodeString(e,t);t+=X;let{value:ie,bytesRead:oe}=this.decodeString(e,t);t+=oe,i.attrs[W]=ie}let{value:v,bytesRead:z}=this.decodeCollectionCount(e,t);t+=z,i.children=[];for(let b=0;b<v;b++){let{vnode:W,bytesRead:X}=this.decodeVNode(e,t,s+1);i.children.push(W),t+=X}break}case J.TEXT:{i.type="text";let{value:r,bytesRead:a}=this.decodeString(e,t);i.text=r,t+=a;break}case J.FRAGMENT:{i.type="fragment";let{value:r,bytesRead:a}=this.decodeCollectionCount(e,t);t+=a,i.children=[];for(let h=0;h<r;h++){let{vnode:u,bytesRead:p}=this.decodeVNode(e,t,s+1);i.children.push(u),t+=p}break}default:throw new Error(`Protocol decode: unknown vnode type ${o}`)}return{vnode:i,bytesRead:t-n}}encodeString(e){let t=this.textEncoder.encode(e),s=this.encodeUvarint(t.length);return y([s,t])}decodeString(e,t){let{value:s,bytesRead:n}=this.decodeUvarint(e,t);this._checkAllocation(s,"string");let i=t+n,o=i+s;this._ensureAvailable(e,i,s,"string bytes");let r=e.slice(i,o);return{value:this.textDecoder.decode(r),bytesRead:n+s}}encodeUvarint(e){let t=[];for(;e>127;)t.push(e&127|128),e>>>=7;return t.push(e&127),new Uint8Array(t)}decodeUvarint(e,t){let s=0,n=0,i=0;for(;;){if(t+i>=e.length)throw new Error("Protocol decode: buffer too short for varint");if(i>=ue)throw new Error("Protocol decode: varint overflow");if(n>53)throw new Error("Protocol decode: varint exceeds JS safe integer");let o=e[t+i];if(i++,s+=(o&127)*Math.pow(2,n),!Number.isSafeInteger(s))throw new Error("Protocol decode: varint exceeds JS safe integer");if(!(o&128))break;n+=7}return{value:s,bytesRead:i}}encodeSvarint(e){let t=e<<1^e>>31;return this.encodeUvarint(t>>>0)}decodeSvarint(e,t){let{value:s,bytesRead:n}=this.decodeUvarint(e,t);return{value:s%2===0?s/2:-((s+1)/2),bytesRead:n}}encodeLenBytes(e){let t=this.encodeUvarint(e.length);return y([t,e])}decodeLenBytes(e,t){let{value:s,bytesRead:n}=this.decodeUvarint(e,t);this._checkAllocation(s,"bytes");let i=t+n,o=i+s;return this._ensureAvailable(e,i,s,"bytes"),{value:e.slice(i,o),bytesRead:n+s}}decodeCollectionCount(e,t){let{value:s,bytesRead:n}=this.decodeUvarint(e,t);if(s>fe)throw new Error("Protocol decode: collection count exceeds limit");let i=e.length-(t+n);if(s>i)throw new Error("Protocol decode: collection count exceeds remaining buffer");return{value:s,bytesRead:n}}encodeFormData(e,t){if(!t){e.push(this.encodeUvarint(0));return}let s=[];if(t instanceof FormData)for(let[n,i]of t.entries())s.push({key:n,value:String(i)});else if(typeof t=="object")for(let[n,i]of Object.entries(t))s.push({key:n,value:String(i)});e.push(this.encodeUvarint(s.length));for(let{key:n,value:i}of s)e.push(this.encodeString(n)),e.push(this.encodeString(i))}encodeKeyboardEvent(e,t){e.push(this.encodeString((t==null?void 0:t.key)||"")),e.push(this.encodeString((t==null?void 0:t.code)||""));let s=0;t!=null&&t.ctrlKey&&(s|=f.CTRL),t!=null&&t.shiftKey&&(s|=f.SHIFT),t!=null&&t.altKey&&(s|=f.ALT),t!=null&&t.metaKey&&(s|=f.META),e.push(new Uint8Array([s])),e.push(new Uint8Array([t!=null&&t.repeat?1:0])),e.push(new Uint8Array([(t==null?void 0:t.location)||0]))}encodeMouseEvent(e,t){e.push(this.encodeSvarint((t==null?void 0:t.clientX)||0)),e.push(this.encodeSvarint((t==null?void 0:t.clientY)||0)),e.push(this.encodeSvarint((t==null?void 0:t.pageX)||0)),e.push(this.encodeSvarint((t==null?void 0:t.pageY)||0)),e.push(this.encodeSvarint((t==null?void 0:t.offsetX)||0)),e.push(this.encodeSvarint((t==null?void 0:t.offsetY)||0)),e.push(new Uint8Array([(t==null?void 0:t.button)||0])),e.push(new Uint8Array([(t==null?void 0:t.buttons)||0]));let s=0;t!=null&&t.ctrlKey&&(s|=f.CTRL),t!=null&&t.shiftKey&&(s|=f.SHIFT),t!=null&&t.altKey&&(s|=f.ALT),t!=null&&t.metaKey&&(s|=f.META),e.push(new Uint8Array([s]))}encodeScrollEvent(e,t){e.push(this.encodeSvarint((t==null?void 0:t.scrollTop)||0)),e.push(this.encodeSvarint((t==null?void 0:t.scrollLeft)||0))}encodeResizeEvent(e,t){e.push(this.encodeSvarint((t==null?void 0:t.width)||0)),e.push(this.encodeSvarint((t==null?void 0:t.height)||0))}encodeTouchEvent(e,t){let s=(t==null?void 0:t.touches)||[];e.push(this.encodeUvarint(s.length));for(let o of s)e.push(this.encodeSvarint(o.identifier||o.id||0)),e.push(this.encodeSvarint(o.clientX||0)),e.push(this.encodeSvarint(o.clientY||0)),e.push(this.encodeSvarint(o.pageX||0)),e.push(this.encodeSvarint(o.pageY||0));let n=(t==null?void 0:t.targetTouches)||[];e.push(this.encodeUvarint(n.length));for(let o of n)e.push(this.encodeSvarint(o.identifier||o.id||0)),e.push(this.encodeSvarint(o.clientX||0)),e.push(this.encodeSvarint(o.clientY||0)),e.push(this.encodeSvarint(o.pageX||0)),e.push(this.encodeSvarint(o.pageY||0));let i=(t==null?void 0:t.changedTouches)||[];e.push(this.encodeUvarint(i.length));for(let o of i)e.push(this.encodeSvarint(o.identifier||o.id||0)),e.push(this.encodeSvarint(o.clientX||0)),e.push(this.encodeSvarint(o.clientY||0)),e.push(this.encodeSvarint(o.pageX||0)),e.push(this.encodeSvarint(o.pageY||0))}encodeDragEvent(e,t){e.push(this.encodeSvarint((t==null?void 0:t.clientX)||0)),e.push(this.encodeSvarint((t==null?void 0:t.clientY)||0));let s=0;t!=null&&t.ctrlKey&&(s|=f.CTRL),t!=null&&t.shiftKey&&(s|=f.SHIFT),t!=null&&t.altKey&&(s|=f.ALT),t!=null&&t.metaKey&&(s|=f.META),e.push(new Uint8Array([s]))}encodeHookEvent(e,t){e.push(this.encodeString((t==null?void 0:t.name)||"")),this.encodeHookData(e,(t==null?void 0:t.data)||{})}encodeHookData(e,t){let s=Object.entries(t);e.push(this.encodeUvarint(s.length));for(let[n,i]of s)e.push(this.encodeString(n)),this.encodeHookValue(e,i)}encodeHookValue(e,t){if(t==null)e.push(new Uint8Array([m.NULL]));else if(typeof t=="boolean")e.push(new Uint8Array([m.BOOL,t?1:0]));else if(typeof t=="number"&&Number.isInteger(t))e.push(new Uint8Array([m.INT])),e.push(this.encodeSvarint(t));else if(typeof t=="number")e.push(new Uint8Array([m.FLOAT])),e.push(this.encodeFloat64(t));else if(typeof t=="string")e.push(new Uint8Array([m.STRING])),e.push(this.encodeString(t));else if(Array.isArray(t)){e.push(new Uint8Array([m.ARRAY])),e.push(this.encodeUvarint(t.length));for(let s of t)this.encodeHookValue(e,s)}else if(typeof t=="object"){e.push(new Uint8Array([m.OBJECT]));let s=Object.entries(t);e.push(this.encodeUvarint(s.length));for(let[n,i]of s)e.push(this.encodeString(n)),this.encodeHookValue(e,i)}else e.push(new Uint8Array([m.NULL]))}encodeFloat64(e){let t=new ArrayBuffer(8);return new DataView(t).setFloat64(0,e,!0),new Uint8Array(t)}encodeClientHello(e={}){let t=[];t.push(new Uint8Array([2,0])),t.push(this.encodeString(e.csrf||"")),t.push(this.encodeString(e.sessionId||"")),t.push(this.encodeUint32(e.lastSeq||0)),t.push(this.encodeUint16(e.viewportW||window.innerWidth)),t.push(this.encodeUint16(e.viewportH||window.innerHeight));let s=new Date().getTimezoneOffset();return t.push(this.encodeInt16(-s)),y(t)}encodeClientHelloFrame(e={}){let t=this.encodeClientHello(e),s=new Uint8Array(4+t.length);return s[0]=0,s[1]=0,s[2]=t.length>>8&255,s[3]=t.length&255,s.set(t,4),s}decodeServerHello(e){if(e.length<5)throw new Error("Protocol decode: handshake frame too short");let t=e[0];if(t!==0)throw new Error(`Protocol decode: unexpected frame type ${t}`);let s=e[2]<<8|e[3];if(s>ve)throw new Error("Protocol decode: handshake payload exceeds max size");if(e.length!==4+s)throw new Error("Protocol decode: handshake length mismatch");let n=4;this._ensureAvailable(e,n,1,"handshake status");let i=e[n++],{value:o,bytesRead:r}=this.decodeString(e,n);n+=r;let a=this.decodeUint32(e,n);n+=4;let h=this.decodeUint64(e,n);n+=8;let u=this.decodeUint16(e,n);return{status:i,sessionId:o,nextSeq:a,serverTime:h,flags:u,ok:i===0}}encodeUint16(e){return new Uint8Array([e>>8&255,e&255])}decodeUint16(e,t){return this._ensureAvailable(e,t,2,"uint16"),e[t]<<8|e[t+1]}encodeInt16(e){return this.encodeUint16(e&65535)}encodeUint32(e){return new Uint8Array([e>>24&255,e>>16&255,e>>8&255,e&255])}decodeUint32(e,t){return this._ensureAvailable(e,t,4,"uint32"),e[t]*16777216+(e[t+1]<<16)+(e[t+2]<<8)+e[t+3]}decodeUint64(e,t){let s=this.decodeUint32(e,t),n=this.decodeUint32(e,t+4),i=s*4294967296+n;if(!Number.isSafeInteger(i))throw new Error("Protocol decode: uint64 exceeds JS safe integer");return i}_checkAllocation(e,t){if(e>ge)throw new Error(`Protocol decode: ${t} exceeds hard cap`);if(e>pe)throw new Error(`Protocol decode: ${t} exceeds max allocation`)}_ensureAvailable(e,t,s,n){if(t+s>e.length)throw new Error(`Protocol decode: buffer too short for ${n}`)}encodeAck(e,t=100){return y([this.encodeUvarint(e),this.encodeUvarint(t)])}encodeResyncRequest(e){return y([new Uint8Array([16]),this.encodeUvarint(e)])}};var T=class{constructor(e,t={}){this.client=e,this.options={reconnect:t.reconnect!==!1,reconnectInterval:t.reconnectInterval||1e3,reconnectMaxInterval:t.reconnectMaxInterval||3e4,heartbeatInterval:t.heartbeatInterval||3e4,...t},this.ws=null,this.connected=!1,this.handshakeComplete=!1,this.sessionId=null,this.lastSeq=0,this.reconnectAttempts=0,this.reconnectDisabled=!1,this.heartbeatTimer=null,this.messageQueue=[];let s=this._loadResumeInfo();s&&(this.sessionId=s.sessionId,this.lastSeq=s.lastSeq)}connect(e){this.ws&&this.ws.close(),this.url=e,this.ws=new WebSocket(e),this.ws.binaryType="arraybuffer",this.ws.onopen=()=>this._onOpen(),this.ws.onclose=t=>this._onClose(t),this.ws.onerror=t=>this._onError(t),this.ws.onmessage=t=>this._onMessage(t)}_onOpen(){this.client.options.debug&&console.log("[Vango] WebSocket connected"),this.reconnectAttempts=0,this._sendHandshake(),this._startHeartbeat()}_sendHandshake(){let e=this.client.codec.encodeClientHelloFrame({csrf:this._getCSRFToken(),sessionId:this.sessionId||"",lastSeq:this.lastSeq||this.client.patchSeq||0,viewportW:window.innerWidth,viewportH:window.innerHeight});this.ws.send(e),this.client.options.debug&&console.log("[Vango] Sent framed ClientHello")}_getCSRFToken(){if(window.__VANGO_CSRF__)return window.__VANGO_CSRF__;let e=document.cookie.match(/(?:^|;\s*)__vango_csrf=([^;]*)/);return e?decodeURIComponent(e[1]):""}_onMessage(e){if(!(e.data instanceof ArrayBuffer)){this.client.options.debug&&console.warn("[Vango] Received non-binary message:",e.data);return}let t=new Uint8Array(e.data);if(!this.handshakeComplete){let s;try{s=this.client.codec.decodeServerHello(t)}catch(n){this.client._handleProtocolError(n,"handshake");return}if(!s.ok){let i={1:"Version mismatch",2:"Invalid CSRF token",3:"Session expired",4:"Server busy",5:"Upgrade required",6:"Invalid format",7:"Not authorized",8:"Internal error",9:"Too many active sessions from this IP"}[s.status]||`Handshake failed: ${s.status}`;this.client._onError(new Error(i)),s.status===9&&(this.reconnectDisabled=!0,this.client.connection.setDisconnected("ip_limit")),(s.status===3||s.status===7)&&(this._clearResumeInfo(),this.sessionId=null,this.lastSeq=0,this.reconnectDisabled=!0,this.options.reconnect=!1,setTimeout(()=>location.reload(),0)),this.ws.close();return}this.handshakeComplete=!0,this.connected=!0,this.sessionId=s.sessionId,this._persistResumeInfo(this.sessionId,this.lastSeq||this.client.patchSeq||0),this.client._onConnected(),this._flushQueue(),this.client.options.debug&&console.log("[Vango] Handshake complete, session:",this.sessionId);return}this.client._handleBinaryMessage(t)}_onClose(e){var n;let t=this.connected;this.connected=!1,this.handshakeComplete=!1,this._stopHeartbeat(),this.client.options.debug&&console.log("[Vango] WebSocket closed:",e.code,e.reason);let s=(n=this.client.eventCapture)==null?void 0:n.pendingNavPath;if(t&&s){console.log("[Vango] Connection lost during navigation, completing via location.assign:",s),location.assign(s);return}t&&this.client._onDisconnected(),this.options.reconnect&&!this.reconnectDisabled&&!e.wasClean&&this._scheduleReconnect()}_onError(e){this.client.options.debug&&console.error("[Vango] WebSocket error:",e),this.client._onError(new Error("WebSocket error"))}_scheduleReconnect(){let e=Math.min(this.options.reconnectInterval*Math.pow(2,this.reconnectAttempts),this.options.reconnectMaxInterval);this.reconnectAttempts++,this.client.options.debug&&console.log(`[Vango] Reconnecting in ${e}ms (attempt ${this.reconnectAttempts})`),setTimeout(()=>this.connect(this.url),e)}_startHeartbeat(){this.heartbeatTimer=setInterval(()=>{this.connected&&this._sendPing()},this.options.heartbeatInterval)}_stopHeartbeat(){this.heartbeatTimer&&(clearInterval(this.heartbeatTimer),this.heartbeatTimer=null)}_sendPing(){if(this.ws&&this.ws.readyState===WebSocket.OPEN){let e=Date.now(),t=new Uint8Array(9);t[0]=1;let s=e;for(let i=0;i<8;i++)t[1+i]=s&255,s=Math.floor(s/256);let n=new Uint8Array(4+t.length);n[0]=3,n[1]=0,n[2]=t.length>>8&255,n[3]=t.length&255,n.set(t,4),this.ws.send(n)}}send(e){return this.connected&&this.ws&&this.ws.readyState===WebSocket.OPEN?(this.ws.send(e),!0):(this.messageQueue.push(e),!1)}_flushQueue(){for(;this.messageQueue.length>0&&this.connected;){let e=this.messageQueue.shift();this.ws.send(e)}}close(){this.options.reconnect=!1,this._stopHeartbeat(),this.ws&&(this.ws.close(1e3,"Client closing"),this.ws=null)}resetReconnect(){this.reconnectDisabled=!1,this.reconnectAttempts=0}updateLastSeq(e){let t=Number(e);!Number.isFinite(t)||t<0||(this.lastSeq=t,this.sessionId&&this._persistResumeInfo(this.sessionId,this.lastSeq))}_storageAvailable(){try{return typeof sessionStorage!="undefined"}catch(e){return!1}}_loadResumeInfo(){if(!this._storageAvailable())return null;try{let e=sessionStorage.getItem("__vango_session_id")||"";if(!e)return null;let t=sessionStorage.getItem("__vango_last_seq")||"0",s=Number(t);return{sessionId:e,lastSeq:Number.isFinite(s)&&s>=0?s:0}}catch(e){return null}}_persistResumeInfo(e,t){if(this._storageAvailable())try{sessionStorage.setItem("__vango_session_id",e||""),sessionStorage.setItem("__vango_last_seq",String(t||0))}catch(s){}}_clearResumeInfo(){if(this._storageAvailable())try{sessionStorage.removeItem("__vango_session_id"),sessionStorage.removeItem("__vango_last_seq")}catch(e){}}};var A=class{constructor(e){this.client=e,this.handlers=new Map,this.debounceTimers=new Map,this.scrollThrottled=new Set,this.prefetchedPaths=new Set}attach(){this._on("click",this._handleClick.bind(this)),this._on("dblclick",this._handleDblClick.bind(this)),this._on("input",this._handleInput.bind(this)),this._on("change",this._handleChange.bind(this)),this._on("submit",this._handleSubmit.bind(this)),this._on("focus",this._handleFocus.bind(this),!0),this._on("blur",this._handleBlur.bind(this),!0),this._on("keydown",this._handleKeyDown.bind(this)),this._on("keyup",this._handleKeyUp.bind(this)),this._on("mouseenter",this._handleMouseEnter.bind(this),!0),this._on("mouseleave",this._handleMouseLeave.bind(this),!0),this._on("scroll",this._handleScroll.bind(this),!0),this._on("click",this._handleLinkClick.bind(this)),window.addEventListener("popstate",this._handlePopState.bind(this)),this._on("mouseenter",this._handlePrefetch.bind(this),!0)}detach(){for(let[e,{handler:t,capture:s}]of this.handlers)document.removeEventListener(e,t,s);this.handlers.clear();for(let e of this.debounceTimers.values())clearTimeout(e);this.debounceTimers.clear()}_on(e,t,s=!1){document.addEventListener(e,t,{capture:s,passive:!1}),this.handlers.set(`${e}-${s}`,{handler:t,capture:s})}_findHidElement(e){return!e||!e.closest?null:e.closest("[data-hid]")}_closest(e,t){return!e||typeof e.closest!="function"?null:e.closest(t)}_hasEvent(e,t){return(e.dataset.ve||"").split(",").map(n=>n.trim()).includes(t)}_getModifiers(e,t){return{preventDefault:e.dataset[`pd${this._capitalize(t)}`]==="true",stopPropagation:e.dataset[`sp${this._capitalize(t)}`]==="true",self:e.dataset[`self${this._capitalize(t)}`]==="true",once:e.dataset[`once${this._capitalize(t)}`]==="true",passive:e.dataset[`passive${this._capitalize(t)}`]==="true",capture:e.dataset[`capture${this._capitalize(t)}`]==="true",debounce:parseInt(e.dataset[`debounce${this._capitalize(t)}`]||e.dataset.debounce||"0",10),throttle:parseInt(e.dataset[`throttle${this._capitalize(t)}`]||e.dataset.throttle||"0",10)}}_capitalize(e){return e.charAt(0).toUpperCase()+e.slice(1)}_applyModifiers(e,t,s){let n=this._getModifiers(t,s);if(n.self&&e.target!==t)return!1;if(n.passive){let i=e.preventDefault.bind(e);e.preventDefault=()=>{console.warn("[Vango] preventDefault() called on passive handler - ignored")},queueMicrotask(()=>{e.preventDefault=i})}else n.preventDefault&&e.preventDefault();if(n.stopPropagation&&e.stopPropagation(),n.once){let o=(t.dataset.ve||"").split(",").map(r=>r.trim()).filter(r=>r!==s);o.length>0?t.dataset.ve=o.join(","):delete t.dataset.ve}return!0}_getDebounce(e,t){let s=this._getModifiers(e,t);return t==="input"&&s.debounce===0?100:s.debounce}_getThrottle(e,t){let s=this._getModifiers(e,t);return t==="scroll"&&s.throttle===0?100:s.throttle}_findHidElementWithEvent(e,t){if(!e||!e.closest)return null;let s=e.closest("[data-hid]");for(;s;){if(this._hasEvent(s,t))return s;let n=s.parentElement;if(!n)break;s=n.closest("[data-hid]")}return null}_handleClick(e){if(e.defaultPrevented||e.button!==0||e.ctrlKey||e.metaKey||e.shiftKey||e.altKey||!this.client.connected)return;let t=this._findHidElementWithEvent(e.target,"click");if(!t)return;let s=this._closest(e.target,"a[href]");if(s){let n=s.getAttribute("target");if(n&&n!=="_self"||s.hasAttribute("download"))return;let i=s.getAttribute("href");if(i)try{if(new URL(i,location.href).origin!==location.origin)return}catch(o){return}}this._applyModifiers(e,t,"click")&&(e.preventDefault(),this.client.optimistic.applyOptimistic(t,"click"),this.client.sendEvent(d.CLICK,t.dataset.hid))}_handleDblClick(e){let t=this._findHidElement(e.target);if(!t||!this._hasEvent(t,"dblclick")||!this._applyModifiers(e,t,"dblclick"))return;let s=this._getModifiers(t,"dblclick");!s.preventDefault&&!s.passive&&e.preventDefault(),this.client.sendEvent(d.DBLCLICK,t.dataset.hid)}_handleInput(e){let t=this._findHidElement(e.target);if(!t||!this._hasEvent(t,"input")||!this._applyModifiers(e,t,"input"))return;let s=t.dataset.hid,n=this._getDebounce(t,"input");if(this.debounceTimers.has(s)&&clearTimeout(this.debounceTimers.get(s)),n===0){this.client.sendEvent(d.INPUT,s,{value:t.value});return}let i=setTimeout(()=>{this.debounceTimers.delete(s),this.client.sendEvent(d.INPUT,s,{value:t.value})},n);this.debounceTimers.set(s,i)}_handleChange(e){let t=this._findHidElement(e.target);if(!t||!this._hasEvent(t,"change")||!this._applyModifiers(e,t,"change"))return;let s;t.type==="checkbox"?s=t.checked?"true":"false":t.type==="radio"?s=t.checked?t.value:"":t.tagName==="SELECT"&&t.multiple?s=Array.from(t.selectedOptions).map(n=>n.value).join(","):s=t.value,this.client.optimistic.applyOptimistic(t,"change"),this.client.sendEvent(d.CHANGE,t.dataset.hid,{value:s})}_handleSubmit(e){let t=this._closest(e.target,"form[data-hid]");if(!t||!this._hasEvent(t,"submit")||!this.client.connected||e.defaultPrevented)return;e.preventDefault();let s=new FormData(t),n={};for(let[i,o]of s.entries())n[i]=String(o);this.client.sendEvent(d.SUBMIT,t.dataset.hid,n)}_handleFocus(e){let t=this._findHidElement(e.target);!t||!this._hasEvent(t,"focus")||this._applyModifiers(e,t,"focus")&&this.client.sendEvent(d.FOCUS,t.dataset.hid)}_handleBlur(e){let t=this._findHidElement(e.target);!t||!this._hasEvent(t,"blur")||this._applyModifiers(e,t,"blur")&&this.client.sendEvent(d.BLUR,t.dataset.hid)}_handleKeyDown(e){let t=this._findHidElement(e.target);if(!t||!this._hasEvent(t,"keydown"))return;let s=t.dataset.keyFilter;s&&!this._matchesKeyFilter(e,s)||this._applyModifiers(e,t,"keydown")&&this.client.sendEvent(d.KEYDOWN,t.dataset.hid,{key:e.key,code:e.code,ctrlKey:e.ctrlKey,shiftKey:e.shiftKey,altKey:e.altKey,metaKey:e.metaKey,repeat:e.repeat,location:e.location})}_handleKeyUp(e){let t=this._findHidElement(e.target);!t||!this._hasEvent(t,"keyup")||this._applyModifiers(e,t,"keyup")&&this.client.sendEvent(d.KEYUP,t.dataset.hid,{key:e.key,code:e.code,ctrlKey:e.ctrlKey,shiftKey:e.shiftKey,altKey:e.altKey,metaKey:e.metaKey,repeat:e.repeat,location:e.location})}_handleMouseEnter(e){let t=this._findHidElement(e.target);!t||!this._hasEvent(t,"mouseenter")||this._applyModifiers(e,t,"mouseenter")&&this.client.sendEvent(d.MOUSEENTER,t.dataset.hid)}_handleMouseLeave(e){let t=this._findHidElement(e.target);!t||!this._hasEvent(t,"mouseleave")||this._applyModifiers(e,t,"mouseleave")&&this.client.sendEvent(d.MOUSELEAVE,t.dataset.hid)}_handleScroll(e){let t=this._findHidElement(e.target);if(!t||!this._hasEvent(t,"scroll")||!this._applyModifiers(e,t,"scroll"))return;let s=t.dataset.hid,n=this._getThrottle(t,"scroll");n>0&&this.scrollThrottled.has(s)||(n>0&&(this.scrollThrottled.add(s),setTimeout(()=>{this.scrollThrottled.delete(s)},n)),this.client.sendEvent(d.SCROLL,s,{scrollTop:t.scrollTop,scrollLeft:t.scrollLeft}))}_handleLinkClick(e){let t=this._closest(e.target,"a[href]");if(!t||e.ctrlKey||e.metaKey||e.shiftKey||e.altKey||t.hasAttribute("download"))return;let s=t.getAttribute("target");if(s&&s!=="_self"||!(t.hasAttribute("data-vango-link")||t.hasAttribute("data-link")))return;let i=t.getAttribute("href");if(i){if(i.startsWith("http://")||i.startsWith("https://")||i.startsWith("//"))try{if(new URL(i,window.location.origin).origin!==window.location.origin)return}catch(o){return}t.hasAttribute("data-external")||this.client.connected&&(e.preventDefault(),this.pendingNavPath=i,this.client.sendEvent(d.NAVIGATE,"nav",{path:i,replace:!1}))}}_handlePopState(e){if(!this.client.connected)return;let t=location.pathname+location.search;this.pendingNavPath=t,this.client.sendEvent(d.NAVIGATE,"nav",{path:t,replace:!0})}_handlePrefetch(e){let t=this._closest(e.target,"a[data-prefetch][href]");if(!t)return;let s=t.getAttribute("href");if(!s)return;if(s.startsWith("http://")||s.startsWith("https://")||s.startsWith("//"))try{if(new URL(s,window.location.origin).origin!==window.location.origin)return}catch(r){return}if(this.prefetchedPaths.has(s)||!this.client.connected)return;this.prefetchedPaths.add(s);let n=JSON.stringify({path:s}),o=new TextEncoder().encode(n);this.client.sendEvent(d.CUSTOM,"prefetch",{name:"prefetch",data:o}),this.client.options.debug&&console.log("[Vango] Prefetching:",s)}_matchesKeyFilter(e,t){let s=t.split("+"),n=s.pop().toLowerCase(),i=new Set(s.map(u=>u.toLowerCase()));if(e.key.toLowerCase()!==n)return!1;let o=i.has("ctrl")||i.has("control"),r=i.has("shift"),a=i.has("alt"),h=i.has("meta")||i.has("cmd");return!(o!==e.ctrlKey||r!==e.shiftKey||a!==e.altKey||h!==e.metaKey)}};var R=class{constructor(e){this.client=e}apply(e){for(let t of e)this.applyPatch(t)}_requiresTargetNode(e){switch(e){case c.URL_PUSH:case c.URL_REPLACE:case c.NAV_PUSH:case c.NAV_REPLACE:return!1;case c.INSERT_NODE:return!1;default:return!0}}_triggerSelfHeal(){var t;let e=(t=this.client.eventCapture)==null?void 0:t.pendingNavPath;e?(console.log("[Vango] Self-heal: navigating to pending path:",e),location.assign(e)):(console.log("[Vango] Self-heal: reloading page"),location.reload())}applyPatch(e){let t=this.client.getNode(e.hid);if(!t&&this._requiresTargetNode(e.type)){console.error("[Vango] Patch target not found (HID:",e.hid,"type:",e.type,")"),this._triggerSelfHeal();return}switch(e.type){case c.SET_TEXT:this._setText(t,e.value);break;case c.SET_ATTR:this._setAttr(t,e.key,e.value);break;case c.REMOVE_ATTR:this._removeAttr(t,e.key);break;case c.ADD_CLASS:t.classList.add(e.className);break;case c.REMOVE_CLASS:t.classList.remove(e.className);break;case c.TOGGLE_CLASS:t.classList.toggle(e.className);break;case c.SET_STYLE:t.style[e.key]=e.value;break;case c.REMOVE_STYLE:t.style[e.key]="";break;case c.INSERT_NODE:this._insertNode(e.parentID,e.index,e.vnode);break;case c.REMOVE_NODE:this._removeNode(t,e.hid);break;case c.MOVE_NODE:this._moveNode(t,e.parentID,e.index);break;case c.REPLACE_NODE:this._replaceNode(t,e.hid,e.vnode);break;case c.SET_VALUE:this._setValue(t,e.value);break;case c.SET_CHECKED:t.checked=e.value;break;case c.SET_SELECTED:t.selected=e.value;break;case c.FOCUS:t.focus();break;case c.BLUR:t.blur();break;case c.SCROLL_TO:t.scrollTo({left:e.x,top:e.y,behavior:e.behavior===1?"smooth":"instant"});break;case c.SET_DATA:t.dataset[e.key]=e.value;break;case c.DISPATCH:this._dispatchEvent(t,e.eventName,e.detail);break;case c.URL_PUSH:case c.URL_REPLACE:this.client.urlManager&&this.client.urlManager.applyPatch(e);break;case c.NAV_PUSH:case c.NAV_REPLACE:this._applyNavPatch(e);break;default:this.client.options.debug&&console.warn("[Vango] Unknown patch type:",e.type)}}_setText(e,t){e.textContent=t}_setAttr(e,t,s){if(t.length>2&&t.substring(0,2).toLowerCase()==="on"){console.warn("[Vango] Blocked dangerous attribute:",t);return}switch(t){case"class":e.className=s;break;case"for":e.htmlFor=s;break;case"value":this._setValue(e,s);break;case"checked":e.checked=s==="true"||s==="";break;case"selected":e.selected=s==="true"||s==="";break;case"disabled":case"readonly":case"required":case"multiple":case"autofocus":s==="true"||s===""?e.setAttribute(t,""):e.removeAttribute(t);break;default:e.setAttribute(t,s)}}_removeAttr(e,t){switch(t){case"class":e.className="";break;case"for":e.htmlFor="";break;case"value":e.value="";break;case"checked":e.checked=!1;break;case"selected":e.selected=!1;break;default:e.removeAttribute(t)}}_setValue(e,t){if(e.value!==t)if(e.type==="text"||e.tagName==="TEXTAREA"){let s=e.selectionStart,n=e.selectionEnd;e.value=t,document.activeElement===e&&e.setSelectionRange(Math.min(s,t.length),Math.min(n,t.length))}else e.value=t}_insertNode(e,t,s){let n=this.client.getNode(e);if(!n){console.error("[Vango] INSERT_NODE parent not found (parentHID:",e,")"),this._triggerSelfHeal();return}let i=this._createNode(s);t>=n.children.length?n.appendChild(i):n.insertBefore(i,n.children[t])}_removeNode(e,t){this.client.hooks.destroyForNode(e),this.client.unregisterNode(t),e.querySelectorAll("[data-hid]").forEach(s=>{this.client.hooks.destroyForNode(s),this.client.unregisterNode(s.dataset.hid)}),e.remove()}_moveNode(e,t,s){let n=this.client.getNode(t);if(!n){console.error("[Vango] MOVE_NODE parent not found (parentHID:",t,")"),this._triggerSelfHeal();return}s>=n.children.length?n.appendChild(e):n.insertBefore(e,n.children[s])}_replaceNode(e,t,s){this.client.hooks.destroyForNode(e),this.client.unregisterNode(t),e.querySelectorAll("[data-hid]").forEach(i=>{this.client.hooks.destroyForNode(i),this.client.unregisterNode(i.dataset.hid)});let n=this._createNode(s);e.replaceWith(n)}_createNode(e){switch(e.type){case"element":return this._createElement(e);case"text":return document.createTextNode(e.text);case"fragment":return this._createFragment(e);default:return this.client.options.debug&&console.warn("[Vango] Unknown vnode type:",e.type),document.createTextNode("")}}_createElement(e){let t=document.createElement(e.tag);for(let[s,n]of Object.entries(e.attrs||{}))this._setAttr(t,s,n);e.hid&&(t.dataset.hid=e.hid,this.client.registerNode(e.hid,t));for(let s of e.children||[])t.appendChild(this._createNode(s));return this.client.hooks.initializeForNode(t),t}_createFragment(e){let t=document.createDocumentFragment();for(let s of e.children||[])t.appendChild(this._createNode(s));return t}_dispatchEvent(e,t,s){let n=null;if(s)try{n=JSON.parse(s)}catch(r){n=s}if(t==="vango:navigate"){this._handleNavigate(n);return}if(t==="vango:hash"){this._handleHash(n);return}let i=e||document,o=new CustomEvent(t,{detail:n,bubbles:!0,cancelable:!0});i.dispatchEvent(o)}_applyNavPatch(e){let{path:t,type:s}=e;if(!t||!t.startsWith("/")){console.error("[Vango] Invalid navigation path (must start with /):",t);return}if(t.includes("://")||t.startsWith("//")){console.error("[Vango] Invalid navigation path (must be relative):",t);return}s===c.NAV_REPLACE?history.replaceState(null,"",t):history.pushState(null,"",t),this.client.eventCapture&&(this.client.eventCapture.pendingNavPath=null),window.scrollTo({top:0,behavior:"instant"}),this.client.options.debug&&console.log("[Vango] Navigation applied:",t,s===c.NAV_REPLACE?"(replace)":"(push)")}_handleNavigate(e){if(!e||!e.path){this.client.options.debug&&console.warn("[Vango] Invalid navigate data:",e);return}let{path:t,replace:s,scroll:n}=e;s?history.replaceState(null,"",t):history.pushState(null,"",t),this.client.sendEvent(d.NAVIGATE,"nav",{path:t}),n!==!1&&window.scrollTo({top:0,behavior:"instant"}),this.client.options.debug&&console.log("[Vango] Navigated to:",t,{replace:s,scroll:n})}_handleHash(e){if(!e||typeof e.value!="string"){this.client.options.debug&&console.warn("[Vango] Invalid hash data:",e);return}let t=!!e.replace,s=new URL(window.location);e.value===""?s.hash="":e.value.startsWith("#")?s.hash=e.value:s.hash=`#${e.value}`,s.toString()!==window.location.href&&(t?history.replaceState(null,"",s.toString()):history.pushState(null,"",s.toString()))}};var D=class{constructor(e){this.client=e,this.pending=new Map}applyOptimistic(e,t){let s=e.dataset.hid;if(!s)return;let n=e.dataset.optimistic;if(n)try{let i=JSON.parse(n);i.class&&this._applyClassOptimistic(e,s,i.class),i.text&&this._applyTextOptimistic(e,s,i.text),i.attr&&i.value!==void 0&&this._applyAttrOptimistic(e,s,i.attr,i.value)}catch(i){console.warn("[Vango] Invalid optimistic config:",i)}}_applyClassOptimistic(e,t,s){let[n,i="toggle"]=s.split(":"),o=e.classList.contains(n);switch(i){case"add":e.classList.add(n);break;case"remove":e.classList.remove(n);break;case"toggle":default:e.classList.toggle(n)}this._trackPending(t,"class",n,o)}_applyTextOptimistic(e,t,s){let n=e.textContent;e.textContent=s,this._trackPending(t,"text",null,n)}_applyAttrOptimistic(e,t,s,n){if(s.length>2&&s.substring(0,2).toLowerCase()==="on"){console.warn("[Vango] Blocked dangerous optimistic attribute:",s);return}let i=e.getAttribute(s);n==="null"||n===""?e.removeAttribute(s):e.setAttribute(s,n),this._trackPending(t,"attr",s,i)}_trackPending(e,t,s,n){this.pending.has(e)||this.pending.set(e,[]),this.pending.get(e).push({type:t,key:s,original:n})}clearPending(){this.pending.clear()}revertAll(){for(let[e,t]of this.pending){let s=this.client.getNode(e);if(s)for(let n of t)this._revertUpdate(s,n)}this.pending.clear()}_revertUpdate(e,t){switch(t.type){case"class":t.original?e.classList.add(t.key):e.classList.remove(t.key);break;case"text":e.textContent=t.original;break;case"attr":t.original===null?e.removeAttribute(t.key):e.setAttribute(t.key,t.original);break}}};var L=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.animation=t.animation||150,this.handle=t.handle||null,this.ghostClass=t.ghostClass||"sortable-ghost",this.dragClass=t.dragClass||"sortable-drag",this.dragging=null,this.ghost=null,this.startIndex=-1,this._bindEvents()}updated(e,t,s){this.config=t,this.pushEvent=s}destroyed(e){this._unbindEvents()}_bindEvents(){this._onMouseDown=this._handleMouseDown.bind(this),this._onMouseMove=this._handleMouseMove.bind(this),this._onMouseUp=this._handleMouseUp.bind(this),this.el.addEventListener("mousedown",this._onMouseDown),document.addEventListener("mousemove",this._onMouseMove),document.addEventListener("mouseup",this._onMouseUp),this._onTouchStart=this._handleTouchStart.bind(this),this._onTouchMove=this._handleTouchMove.bind(this),this._onTouchEnd=this._handleTouchEnd.bind(this),this.el.addEventListener("touchstart",this._onTouchStart,{passive:!1}),document.addEventListener("touchmove",this._onTouchMove,{passive:!1}),document.addEventListener("touchend",this._onTouchEnd)}_unbindEvents(){this.el.removeEventListener("mousedown",this._onMouseDown),document.removeEventListener("mousemove",this._onMouseMove),document.removeEventListener("mouseup",this._onMouseUp),this.el.removeEventListener("touchstart",this._onTouchStart),document.removeEventListener("touchmove",this._onTouchMove),document.removeEventListener("touchend",this._onTouchEnd)}_handleMouseDown(e){var s;let t=this._findItem(e.target);t&&(this.handle&&(typeof((s=e.target)==null?void 0:s.closest)!="function"||!e.target.closest(this.handle))||(e.preventDefault(),this._startDrag(t,e.clientX,e.clientY)))}_handleTouchStart(e){var n;let t=this._findItem(e.target);if(!t||this.handle&&(typeof((n=e.target)==null?void 0:n.closest)!="function"||!e.target.closest(this.handle)))return;e.preventDefault();let s=e.touches[0];this._startDrag(t,s.clientX,s.clientY)}_findItem(e){let t=e;for(;t&&t.parentElement!==this.el;)t=t.parentElement;return t}_startDrag(e,t,s){this.dragging=e,this.activeContainer=e.parentElement,this.initialContainer=this.activeContainer,this.startIndex=Array.from(this.activeContainer.children).indexOf(e),this.startY=s,this.startX=t,this.itemHeight=e.offsetHeight;let n=e.getBoundingClientRect();this.ghostStartTop=n.top,this.ghostStartLeft=n.left,this.ghost=e.cloneNode(!0),this.ghost.classList.add(this.ghostClass),this.ghost.style.position="fixed",this.ghost.style.zIndex="9999",this.ghost.style.width=`${e.offsetWidth}px`,this.ghost.style.height=`${e.offsetHeight}px`,this.ghost.style.left=`${this.ghostStartLeft}px`,this.ghost.style.top=`${this.ghostStartTop}px`,this.ghost.style.pointerEvents="none",this.ghost.style.opacity="0.8",this.ghost.style.transition="none",document.body.appendChild(this.ghost),e.classList.add(this.dragClass),e.style.opacity="0.4"}_handleMouseMove(e){this.dragging&&(e.preventDefault(),this._updateDrag(e.clientX,e.clientY))}_handleTouchMove(e){if(!this.dragging)return;e.preventDefault();let t=e.touches[0];this._updateDrag(t.clientX,t.clientY)}_updateDrag(e,t){let s=t-this.startY,n=e-this.startX;this.ghost&&(this.ghost.style.top=`${this.ghostStartTop+s}px`,this.ghost.style.left=`${this.ghostStartLeft+n}px`),this.ghost.style.display="none";let i=document.elementFromPoint(e,t);if(this.ghost.style.display="",i){let a=i.closest('[data-hook="Sortable"]');if(a&&a!==this.activeContainer&&a.dataset.hookConfig)try{JSON.parse(a.dataset.hookConfig).group===this.config.group&&(this.activeContainer=a,this.activeContainer.appendChild(this.dragging))}catch(h){}}let o=Array.from(this.activeContainer.children),r=o.indexOf(this.dragging);for(let a=0;a<o.length;a++){if(a===r)continue;let h=o[a],u=h.getBoundingClientRect(),p=u.top+u.height/2;if(t<p&&a<r){this.activeContainer.insertBefore(this.dragging,h);break}else if(t>p&&a>r){this.activeContainer.insertBefore(this.dragging,h.nextSibling);break}}}_handleMouseUp(e){this.dragging&&this._endDrag()}_handleTouchEnd(e){this.dragging&&this._endDrag()}_endDrag(){let e=Array.from(this.activeContainer.children).indexOf(this.dragging),t=this.dragging.dataset.id||this.dragging.dataset.itemId||this.dragging.dataset.hid,s=this.initialContainer.dataset.id||this.initialContainer.dataset.listId||this.initialContainer.dataset.containerId||this.initialContainer.dataset.hid,n=this.activeContainer.dataset.id||this.activeContainer.dataset.listId||this.activeContainer.dataset.containerId||this.activeContainer.dataset.hid,i=this.initialContainer,o=this.startIndex,r=this.dragging;if(this.dragging.classList.remove(this.dragClass),this.dragging.style.opacity="",this.ghost&&this.ghost.remove(),this.activeContainer!==this.initialContainer||e!==this.startIndex){let a=()=>{if(!i||!r)return;let u=Array.from(i.children)[o]||null;i.insertBefore(r,u)};this.pushEvent("reorder",{id:t,itemId:t,fromContainerId:s,toContainerId:n,fromIndex:this.startIndex,toIndex:e},a)}this.dragging=null,this.ghost=null,this.activeContainer=null,this.initialContainer=null}};var N=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.axis=t.axis||"both",this.handle=t.handle||null,this.bounds=t.bounds||null,this.dragging=!1,this.startX=0,this.startY=0,this.currentX=0,this.currentY=0,this._bindEvents()}updated(e,t,s){this.config=t,this.pushEvent=s,this.axis=t.axis||"both",this.bounds=t.bounds||null}destroyed(e){this._unbindEvents()}_bindEvents(){this._onMouseDown=this._handleMouseDown.bind(this),this._onMouseMove=this._handleMouseMove.bind(this),this._onMouseUp=this._handleMouseUp.bind(this);let e=this.handle?this.el.querySelector(this.handle):this.el;e&&e.addEventListener("mousedown",this._onMouseDown),document.addEventListener("mousemove",this._onMouseMove),document.addEventListener("mouseup",this._onMouseUp),this._onTouchStart=this._handleTouchStart.bind(this),this._onTouchMove=this._handleTouchMove.bind(this),this._onTouchEnd=this._handleTouchEnd.bind(this),e&&e.addEventListener("touchstart",this._onTouchStart,{passive:!1}),document.addEventListener("touchmove",this._onTouchMove,{passive:!1}),document.addEventListener("touchend",this._onTouchEnd)}_unbindEvents(){let e=this.handle?this.el.querySelector(this.handle):this.el;e&&(e.removeEventListener("mousedown",this._onMouseDown),e.removeEventListener("touchstart",this._onTouchStart)),document.removeEventListener("mousemove",this._onMouseMove),document.removeEventListener("mouseup",this._onMouseUp),document.removeEventListener("touchmove",this._onTouchMove),document.removeEventListener("touchend",this._onTouchEnd)}_handleMouseDown(e){e.preventDefault(),this._startDrag(e.clientX,e.clientY)}_handleTouchStart(e){e.preventDefault();let t=e.touches[0];this._startDrag(t.clientX,t.clientY)}_startDrag(e,t){this.dragging=!0,this.startX=e-this.currentX,this.startY=t-this.currentY,this.el.classList.add("dragging")}_handleMouseMove(e){this.dragging&&(e.preventDefault(),this._updatePosition(e.clientX,e.clientY))}_handleTouchMove(e){if(!this.dragging)return;e.preventDefault();let t=e.touches[0];this._updatePosition(t.clientX,t.clientY)}_updatePosition(e,t){let s=e-this.startX,n=t-this.startY;if(this.axis==="x"?n=this.currentY:this.axis==="y"&&(s=this.currentX),this.bounds){let i=this._getBounds();s=Math.max(i.minX,Math.min(i.maxX,s)),n=Math.max(i.minY,Math.min(i.maxY,n))}this.currentX=s,this.currentY=n,this.el.style.transform=`translate(${s}px, ${n}px)`}_getBounds(){let e=this.el.getBoundingClientRect(),t;return this.bounds==="parent"?t=this.el.parentElement.getBoundingClientRect():t={left:0,top:0,right:window.innerWidth,bottom:window.innerHeight},{minX:t.left-e.left+this.currentX,maxX:t.right-e.right+this.currentX,minY:t.top-e.top+this.currentY,maxY:t.bottom-e.bottom+this.currentY}}_handleMouseUp(e){this.dragging&&this._endDrag()}_handleTouchEnd(e){this.dragging&&this._endDrag()}_endDrag(){this.dragging=!1,this.el.classList.remove("dragging"),this.pushEvent("position",{x:this.currentX,y:this.currentY})}};var O=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.hoverClass=t.hoverClass||"drag-over",this._bind()}updated(e,t,s){this.config=t,this.pushEvent=s,this.hoverClass=t.hoverClass||"drag-over"}destroyed(){this._unbind()}_bind(){this._enter=e=>{e.preventDefault(),this.el.classList.add(this.hoverClass)},this._over=e=>{e.preventDefault(),e.dataTransfer.dropEffect="move"},this._leave=e=>{this.el.contains(e.relatedTarget)||this.el.classList.remove(this.hoverClass)},this._drop=e=>{var i,o;e.preventDefault(),this.el.classList.remove(this.hoverClass);let t={x:e.clientX,y:e.clientY};if(window.__vango_dragging__){let r=window.__vango_dragging__;t.id=r.dataset.id||r.dataset.hid,window.__vango_dragging__=null}let s=(i=e.dataTransfer)==null?void 0:i.files;s!=null&&s.length&&(t.fileNames=Array.from(s).map(r=>r.name));let n=(o=e.dataTransfer)==null?void 0:o.getData("text/plain");n&&(t.text=n),this.pushEvent("drop",t)},this.el.addEventListener("dragenter",this._enter),this.el.addEventListener("dragover",this._over),this.el.addEventListener("dragleave",this._leave),this.el.addEventListener("drop",this._drop)}_unbind(){this.el.removeEventListener("dragenter",this._enter),this.el.removeEventListener("dragover",this._over),this.el.removeEventListener("dragleave",this._leave),this.el.removeEventListener("drop",this._drop)}};var M=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.handles=(t.handles||"se").split(",").map(n=>n.trim()),this.resizing=!1,this._handleEls=[],this._createHandles(),this._bind()}updated(e,t,s){this.config=t,this.pushEvent=s}destroyed(){this._unbind(),this._handleEls.forEach(e=>e.remove())}_createHandles(){getComputedStyle(this.el).position==="static"&&(this.el.style.position="relative");let e={n:"n",s:"s",e:"e",w:"w",ne:"ne",se:"se",sw:"sw",nw:"nw"};for(let t of this.handles){if(!e[t])continue;let s=document.createElement("div");s.dataset.handle=t,s.style.cssText=`position:absolute;z-index:10;cursor:${t}-resize;`+this._pos(t),this.el.appendChild(s),this._handleEls.push(s)}}_pos(e){return{n:"top:-4px;left:25%;width:50%;height:8px",s:"bottom:-4px;left:25%;width:50%;height:8px",e:"right:-4px;top:25%;width:8px;height:50%",w:"left:-4px;top:25%;width:8px;height:50%",ne:"top:-4px;right:-4px;width:8px;height:8px",se:"bottom:-4px;right:-4px;width:8px;height:8px",sw:"bottom:-4px;left:-4px;width:8px;height:8px",nw:"top:-4px;left:-4px;width:8px;height:8px"}[e]||""}_bind(){this._onDown=e=>{e.preventDefault(),this._start(e.target.dataset.handle,e.clientX,e.clientY)},this._onMove=e=>{this.resizing&&(e.preventDefault(),this._update(e.clientX,e.clientY))},this._onUp=()=>{this.resizing&&this._end()},this._handleEls.forEach(e=>e.addEventListener("mousedown",this._onDown)),document.addEventListener("mousemove",this._onMove),document.addEventListener("mouseup",this._onUp)}_unbind(){this._handleEls.forEach(e=>e.removeEventListener("mousedown",this._onDown)),document.removeEventListener("mousemove",this._onMove),document.removeEventListener("mouseup",this._onUp)}_start(e,t,s){this.resizing=!0,this._h=e,this._sx=t,this._sy=s;let n=this.el.getBoundingClientRect();this._sw=n.width,this._sh=n.height}_update(e,t){let s=e-this._sx,n=t-this._sy,i=this._sw,o=this._sh;this._h.includes("e")&&(i=this._sw+s),this._h.includes("w")&&(i=this._sw-s),this._h.includes("s")&&(o=this._sh+n),this._h.includes("n")&&(o=this._sh-n);let r=this.config;i=Math.max(r.minWidth||0,Math.min(r.maxWidth||1/0,i)),o=Math.max(r.minHeight||0,Math.min(r.maxHeight||1/0,o)),this.el.style.width=i+"px",this.el.style.height=o+"px"}_end(){this.resizing=!1;let e=this.el.getBoundingClientRect();this.pushEvent("resize",{width:Math.round(e.width),height:Math.round(e.height)})}};var I=class{mounted(e,t,s){this.el=e,this.config=t,this.tooltip=null,this.content=t.content||"",this.placement=t.placement||"top",this.delay=t.delay||200,this._bindEvents()}updated(e,t,s){this.content=t.content||"",this.placement=t.placement||"top",this.tooltip&&(this.tooltip.textContent=this.content)}destroyed(e){this._unbindEvents(),this._hideTooltip()}_bindEvents(){this._onMouseEnter=this._handleMouseEnter.bind(this),this._onMouseLeave=this._handleMouseLeave.bind(this),this.el.addEventListener("mouseenter",this._onMouseEnter),this.el.addEventListener("mouseleave",this._onMouseLeave)}_unbindEvents(){this.el.removeEventListener("mouseenter",this._onMouseEnter),this.el.removeEventListener("mouseleave",this._onMouseLeave)}_handleMouseEnter(){this._showTimer=setTimeout(()=>{this._showTooltip()},this.delay)}_handleMouseLeave(){clearTimeout(this._showTimer),this._hideTooltip()}_showTooltip(){this.content&&(this.tooltip=document.createElement("div"),this.tooltip.className="vango-tooltip",this.tooltip.textContent=this.content,this.tooltip.style.cssText=`
+var Vango=(()=>{var g=Object.defineProperty;var j=Object.getOwnPropertyDescriptor;var l=Object.getOwnPropertyNames;var q=Object.prototype.hasOwnProperty;var t=(gc,hc)=>{for(var ic in hc)g(gc,ic,{get:hc[ic],enumerable:true})};var u=(jc,kc,lc,mc)=>{if(kc&&typeof kc==="object"||typeof kc==="function"){for(let nc of l(kc))if(!q.call(jc,nc)&&nc!==lc)g(jc,nc,{get:()=>kc[nc],enumerable:!(mc=j(kc,nc))||mc.enumerable})}return jc};var z=oc=>u(g({},"__esModule",{value:true}),oc);var A={};t(A,{ConnectionManager:()=>Db,ConnectionState:()=>Bb,EventType:()=>R,VangoClient:()=>dc,default:()=>fc});function B(pc){return parseInt(pc.slice(1),10)}function C(qc){return"h"+qc}function D(rc){let sc=0;for(let vc=0;vc<rc.length;vc++){sc+=rc[vc].length}const tc=new Uint8Array(sc);let uc=0;for(let wc=0;wc<rc.length;wc++){tc.set(rc[wc],uc);uc+=rc[wc].length}return tc}function E(xc,yc){let zc=null;return function(...Ac){if(zc)clearTimeout(zc);zc=setTimeout(()=>{zc=null;xc.apply(this,Ac)},yc)}}function F(Bc,Cc){let Dc=false;return function(...Ec){if(!Dc){Bc.apply(this,Ec);Dc=true;setTimeout(()=>{Dc=false},Cc)}}}function G(Fc){if(typeof File!=="undefined"&&Fc instanceof File){return Fc.name}return String(Fc!=null?Fc:"")}const H=32768;const I=[3,4,5,6,7,8,9,10,11,13,15,17,19,23,27,31,35,43,51,59,67,83,99,115,131,163,195,227,258];const J=[0,0,0,0,0,0,0,0,1,1,1,1,2,2,2,2,3,3,3,3,4,4,4,4,5,5,5,5,0];const K=[1,2,3,4,5,7,9,13,17,25,33,49,65,97,129,193,257,385,513,769,1025,1537,2049,3073,4097,6145,8193,12289,16385,24577];const L=[0,0,0,0,1,1,2,2,3,3,4,4,5,5,6,6,7,7,8,8,9,9,10,10,11,11,12,12,13,13];const M=[16,17,18,0,8,7,9,6,10,5,11,4,12,3,13,2,14,1,15];function N(Gc){const Hc=new Uint16Array(16);for(let Kc=0;Kc<Gc.length;Kc++){Hc[Gc[Kc]]++}Hc[0]=0;const Ic=new Uint16Array(16);for(let Lc=1;Lc<16;Lc++){Ic[Lc]=Ic[Lc-1]+Hc[Lc-1]}const Jc=new Uint16Array(Gc.length);for(let Mc=0;Mc<Gc.length;Mc++){if(Gc[Mc]!==0){Jc[Ic[Gc[Mc]]++]=Mc}}return{counts:Hc,symbols:Jc}}let O=null;function P(){if(!O){const Nc=new Uint8Array(288);Nc.fill(8,0,144);Nc.fill(9,144,256);Nc.fill(7,256,280);Nc.fill(8,280,288);const Oc=new Uint8Array(30).fill(5);O={lit:N(Nc),dist:N(Oc)}}return O}class Q{constructor(){this.history=new Uint8Array(0)}inflate(Pc,Qc){this.input=Pc;this.pos=0;this.bitBuf=0;this.bitCnt=0;const Rc=this.history.length;this.out=new Uint8Array(Rc+Qc);this.out.set(this.history);this.outPos=Rc;while(this.pos<Pc.length){const Tc=this._bits(1);const Uc=this._bits(2);switch(Uc){case 0:this._stored();break;case 1:{const Vc=P();this._codes(Vc.lit,Vc.dist);break}case 2:this._dynamic();break;default:throw new Error("Inflate: invalid block type")}if(Tc){break}}if(this.outPos!==this.out.length){throw new Error("Inflate: decompressed length mismatch")}const Sc=this.out;this.history=Sc.slice(Math.max(0,Sc.length-H));this.input=null;this.out=null;return Sc.subarray(Rc)}_bits(Wc){let Xc=this.bitBuf;while(this.bitCnt<Wc){if(this.pos>=this.input.length){throw new Error("Inflate: unexpected end of input")}Xc|=this.input[this.pos++]<<this.bitCnt;this.bitCnt+=8}this.bitBuf=Xc>>>Wc;this.bitCnt-=Wc;return Xc&(1<<Wc)-1}_decode(Yc){let Zc=0;let _c=0;let $c=0;for(let ad=1;ad<16;ad++){Zc|=this._bits(1);const bd=Yc.counts[ad];if(Zc-bd<_c){return Yc.symbols[$c+(Zc-_c)]}$c+=bd;_c=_c+bd<<1;Zc<<=1}throw new Error("Inflate: invalid Huffman code")}_put(dd){if(this.outPos>=this.out.length){throw new Error("Inflate: output exceeds declared length")}this.out[this.outPos++]=dd}_stored(){this.bitBuf=0;this.bitCnt=0;if(this.pos+4>this.input.length){throw new Error("Inflate: unexpected end of input")}const ed=this.input;const fd=ed[this.pos]|ed[this.pos+1]<<8;const gd=ed[this.pos+2]|ed[this.pos+3]<<8;this.pos+=4;if(fd!==(~gd&65535)){throw new Error("Inflate: stored block length mismatch")}if(this.pos+fd>ed.length){throw new Error("Inflate: unexpected end of input")}if(this.outPos+fd>this.out.length){throw new Error("Inflate: output exceeds declared length")}this.out.set(ed.subarray(this.pos,this.pos+fd),this.outPos);this.pos+=fd;this.outPos+=fd}_dynamic(){const hd=this._bits(5)+257;const jd=this._bits(5)+1;const kd=this._bits(4)+4;if(hd>286||jd>30){throw new Error("Inflate: invalid code counts")}const ld=new Uint8Array(19);for(let pd=0;pd<kd;pd++){ld[M[pd]]=this._bits(3)}const md=N(ld);const nd=new Uint8Array(hd+jd);let od=0;while(od<hd+jd){const qd=this._decode(md);if(qd<16){nd[od++]=qd;continue}let rd=0;let sd;if(qd===16){if(od===0){throw new Error("Inflate: repeat with no previous length")}rd=nd[od-1];sd=3+this._bits(2)}else if(qd===17){sd=3+this._bits(3)}else{sd=11+this._bits(7)}if(od+sd>hd+jd){throw new Error("Inflate: too many code lengths")}nd.fill(rd,od,od+sd);od+=sd}this._codes(N(nd.subarray(0,hd)),N(nd.subarray(hd)))}_codes(td,ud){for(;;){const vd=this._decode(td);if(vd<256){this._put(vd);continue}if(vd===256){return}const wd=vd-257;if(wd>=I.length){throw new Error("Inflate: invalid length code")}const xd=I[wd]+this._bits(J[wd]);const yd=this._decode(ud);if(yd>=K.length){throw new Error("Inflate: invalid distance code")}const zd=K[yd]+this._bits(L[yd]);if(zd>this.outPos){throw new Error("Inflate: distance too far back")}if(this.outPos+xd>this.out.length){throw new Error("Inflate: output exceeds declared length")}const Ad=this.out;let Bd=this.outPos-zd;for(let Cd=0;Cd<xd;Cd++){Ad[this.outPos++]=Ad[Bd++]}}}}const R={CLICK:1,DBLCLICK:2,MOUSEDOWN:3,MOUSEUP:4,MOUSEMOVE:5,MOUSEENTER:6,MOUSELEAVE:7,CONTEXTMENU:9,INPUT:16,CHANGE:17,SUBMIT:18,FOCUS:19,BLUR:20,KEYDOWN:32,KEYUP:33,KEYPRESS:34,SCROLL:48,RESIZE:49,TOUCHSTART:64,TOUCHMOVE:65,TOUCHEND:66,DRAGSTART:80,DRAGEND:81,DROP:82,DRAGENTER:91,DRAGOVER:92,DRAGLEAVE:93,DRAG:94,HOOK:96,ISLAND:97,NAVIGATE:112,POINTERDOWN:128,POINTERUP:129,POINTERMOVE:130,POINTERENTER:131,POINTERLEAVE:132,POINTERCANCEL:133,COPY:144,CUT:145,PASTE:146,TOGGLE:152,PLAY:160,PAUSE:161,ENDED:162,TIMEUPDATE:163,SEEKING:164,SEEKED:165,VOLUMECHANGE:166,RATECHANGE:167,DURATIONCHANGE:168,LOADEDMETADATA:169,LOADEDDATA:170,CANPLAY:171,CANPLAYTHROUGH:172,WAITING:173,PLAYING:174,VISIBILITYCHANGE:176,ONLINE:177,OFFLINE:178,BEFOREUNLOAD:179,CUSTOM:255};const S={SET_TEXT:1,SET_ATTR:2,REMOVE_ATTR:3,INSERT_NODE:4,REMOVE_NODE:5,MOVE_NODE:6,REPLACE_NODE:7,SET_VALUE:8,SET_CHECKED:9,SET_SELECTED:10,FOCUS:11,BLUR:12,SCROLL_TO:13,ADD_CLASS:16,REMOVE_CLASS:17,TOGGLE_CLASS:18,SET_STYLE:19,REMOVE_STYLE:20,SET_DATA:21,DISPATCH:32,URL_PUSH:48,URL_REPLACE:49,NAV_PUSH:50,NAV_REPLACE:51,SET_TITLE:52,SET_META:53,SET_LINK:54,ISLAND_MESSAGE:64};const T={CTRL:1,SHIFT:2,ALT:4,META:8};const U={NONE:0,DEFLATE:1};const V=[U.DEFLATE];const W={major:2,minor:2};const X={major:2,minor:0};function Y(Dd,Ed,Fd){return Dd.major>Ed||Dd.major===Ed&&Dd.minor>=Fd}const Z={COMPRESSED:1,FINAL:4,FRAGMENT:16};const _=1;const $=2;const aa={START:1,CHUNK:2,END:3,CANCEL:4};const ba={PROGRESS:0,DONE:1,ERROR:2};const ca={ELEMENT:1,TEXT:2,FRAGMENT:3,PORTAL:6};const da={NULL:0,BOOL:1,INT:2,FLOAT:3,STRING:4,ARRAY:5,OBJECT:6};const ea=10;const fa=4*1024*1024;const ga=16*1024*1024;const ha=100000;const ia=256;const ja=128;const ka=64;const la=65535;const ma=fa;class na{constructor(Gd=ma){this.maxSize=Gd;this.partial=new Map;this.buffered=0}add(Hd,Id,Jd){const Kd=this.partial.get(Hd);if(!(Id&Z.FRAGMENT)){if(Kd){this._discard(Hd);throw new Error("Protocol decode: unfragmented frame inside a fragmented message")}return{flags:Id,payload:Jd}}const Ld=Id&~(Z.FRAGMENT|Z.FINAL);let Md=Kd;if(!Md){Md={flags:Ld,chunks:[],length:0};this.partial.set(Hd,Md)}else if(Md.flags!==Ld){this._discard(Hd);throw new Error("Protocol decode: fragment flags do not match message")}if(this.buffered+Jd.length>this.maxSize){this._discard(Hd);throw new Error("Protocol decode: reassembled message too large")}Md.chunks.push(Jd);Md.length+=Jd.length;this.buffered+=Jd.length;if(!(Id&Z.FINAL)){return null}this._discard(Hd);const Nd=new Uint8Array(Md.length);let Od=0;for(const Pd of Md.chunks){Nd.set(Pd,Od);Od+=Pd.length}return{flags:Md.flags,payload:Nd}}reset(){this.partial.clear();this.buffered=0}_discard(Qd){const Rd=this.partial.get(Qd);if(Rd){this.buffered-=Rd.length;this.partial.delete(Qd)}}}class oa{constructor(){this.textEncoder=new TextEncoder;this.textDecoder=new TextDecoder}encodeEvent(Sd,Td,Ud,Vd=null){const Wd=[];Wd.push(this.encodeUvarint(Sd));Wd.push(new Uint8Array([Td]));Wd.push(this.encodeString(Ud));switch(Td){case R.INPUT:case R.CHANGE:case R.VISIBILITYCHANGE:Wd.push(this.encodeString((Vd==null?void 0:Vd.value)||""));break;case R.SUBMIT:this.encodeFormData(Wd,Vd);break;case R.KEYDOWN:case R.KEYUP:case R.KEYPRESS:this.encodeKeyboardEvent(Wd,Vd);break;case R.MOUSEDOWN:case R.MOUSEUP:case R.MOUSEMOVE:case R.CONTEXTMENU:this.encodeMouseEvent(Wd,Vd);break;case R.POINTERDOWN:case R.POINTERUP:case R.POINTERMOVE:case R.POINTERENTER:case R.POINTERLEAVE:case R.POINTERCANCEL:this.encodePointerEvent(Wd,Vd);break;case R.PLAY:case R.PAUSE:case R.ENDED:case R.TIMEUPDATE:case R.SEEKING:case R.SEEKED:case R.VOLUMECHANGE:case R.RATECHANGE:case R.DURATIONCHANGE:case R.LOADEDMETADATA:case R.LOADEDDATA:case R.CANPLAY:case R.CANPLAYTHROUGH:case R.WAITING:case R.PLAYING:this.encodeMediaEvent(Wd,Vd);break;case R.COPY:case R.CUT:case R.PASTE:this.encodeClipboardEvent(Wd,Vd);break;case R.TOGGLE:Wd.push(new Uint8Array([(Vd==null?void 0:Vd.open)?1:0]));break;case R.SCROLL:this.encodeScrollEvent(Wd,Vd);break;case R.RESIZE:this.encodeResizeEvent(Wd,Vd);break;case R.TOUCHSTART:case R.TOUCHMOVE:case R.TOUCHEND:this.encodeTouchEvent(Wd,Vd);break;case R.DRAGSTART:case R.DRAGEND:case R.DROP:case R.DRAGENTER:case R.DRAGOVER:case R.DRAGLEAVE:case R.DRAG:this.encodeDragEvent(Wd,Vd);break;case R.HOOK:this.encodeHookEvent(Wd,Vd);break;case R.ISLAND:this.encodeHookData(Wd,(Vd==null?void 0:Vd.data)||{});break;case R.NAVIGATE:Wd.push(this.encodeString((Vd==null?void 0:Vd.path)||""));Wd.push(new Uint8Array([(Vd==null?void 0:Vd.replace)?1:0]));break;case R.CUSTOM:Wd.push(this.encodeString((Vd==null?void 0:Vd.name)||""));Wd.push(this.encodeLenBytes((Vd==null?void 0:Vd.data)||new Uint8Array(0)));break;case R.CLICK:case R.DBLCLICK:case R.FOCUS:case R.BLUR:case R.MOUSEENTER:case R.MOUSELEAVE:default:break}return D(Wd)}decodePatches(Xd){return this._decodePatchesWithDepth(Xd,0)}_decodePatchesWithDepth(Yd,Zd){if(Zd>ja){throw new Error("Protocol decode: patch depth exceeded")}let _d=0;const{value:$d,bytesRead:ae}=this.decodeUvarint(Yd,_d);_d+=ae;const{value:be,bytesRead:ce}=this.decodeCollectionCount(Yd,_d);_d+=ce;const de=[];for(let ee=0;ee<be;ee++){const{patch:fe,bytesRead:ge}=this.decodePatch(Yd,_d,Zd+1);de.push(fe);_d+=ge}return{seq:$d,patches:de}}decodePatch(he,ie,je){if(je>ja){throw new Error("Protocol decode: patch depth exceeded")}const ke=ie;const le={};this._ensureAvailable(he,ie,1,"patch type");le.type=he[ie++];const{value:me,bytesRead:oe}=this.decodeString(he,ie);le.hid=me;ie+=oe;switch(le.type){case S.SET_TEXT:case S.SET_VALUE:{const{value:pe,bytesRead:qe}=this.decodeString(he,ie);le.value=pe;ie+=qe;break}case S.SET_ATTR:case S.SET_STYLE:case S.SET_DATA:{const{value:re,bytesRead:te}=this.decodeString(he,ie);ie+=te;const{value:ue,bytesRead:we}=this.decodeString(he,ie);ie+=we;le.key=re;le.value=ue;break}case S.REMOVE_ATTR:case S.REMOVE_STYLE:{const{value:xe,bytesRead:ye}=this.decodeString(he,ie);le.key=xe;ie+=ye;break}case S.ADD_CLASS:case S.REMOVE_CLASS:case S.TOGGLE_CLASS:{const{value:ze,bytesRead:Ae}=this.decodeString(he,ie);le.className=ze;ie+=Ae;break}case S.INSERT_NODE:{const{value:Be,bytesRead:Ce}=this.decodeString(he,ie);ie+=Ce;const{value:De,bytesRead:Ee}=this.decodeUvarint(he,ie);ie+=Ee;const{vnode:Fe,bytesRead:Ge}=this.decodeVNode(he,ie,0);ie+=Ge;le.parentID=Be;le.index=De;le.vnode=Fe;break}case S.REMOVE_NODE:break;case S.MOVE_NODE:{const{value:He,bytesRead:Ie}=this.decodeString(he,ie);ie+=Ie;const{value:Je,bytesRead:Ke}=this.decodeUvarint(he,ie);ie+=Ke;le.parentID=He;le.index=Je;break}case S.REPLACE_NODE:{const{vnode:Le,bytesRead:Me}=this.decodeVNode(he,ie,0);ie+=Me;le.vnode=Le;break}case S.SET_CHECKED:case S.SET_SELECTED:{this._ensureAvailable(he,ie,1,"boolean");le.value=he[ie++]===1;break}case S.FOCUS:case S.BLUR:break;case S.SCROLL_TO:{const{value:Ne,bytesRead:Oe}=this.decodeSvarint(he,ie);ie+=Oe;const{value:Pe,bytesRead:Qe}=this.decodeSvarint(he,ie);ie+=Qe;le.x=Ne;le.y=Pe;this._ensureAvailable(he,ie,1,"scroll behavior");le.behavior=he[ie++];break}case S.DISPATCH:{const{value:Re,bytesRead:Se}=this.decodeString(he,ie);ie+=Se;const{value:Te,bytesRead:Ue}=this.decodeString(he,ie);ie+=Ue;le.eventName=Re;le.detail=Te;break}case S.URL_PUSH:case S.URL_REPLACE:{const{value:Ve,bytesRead:We}=this.decodeCollectionCount(he,ie);ie+=We;le.params={};for(let Xe=0;Xe<Ve;Xe++){const{value:Ye,bytesRead:Ze}=this.decodeString(he,ie);ie+=Ze;const{value:$e,bytesRead:af}=this.decodeString(he,ie);ie+=af;le.params[Ye]=$e}le.op=le.type;break}case S.NAV_PUSH:case S.NAV_REPLACE:{const{value:bf,bytesRead:cf}=this.decodeString(he,ie);ie+=cf;le.path=bf;break}case S.SET_TITLE:{const{value:df,bytesRead:ef}=this.decodeString(he,ie);ie+=ef;le.value=df;break}case S.SET_META:{const{value:ff,bytesRead:gf}=this.decodeString(he,ie);ie+=gf;const{value:hf,bytesRead:jf}=this.decodeString(he,ie);ie+=jf;const{value:kf,bytesRead:lf}=this.decodeString(he,ie);ie+=lf;le.attr=ff;le.key=hf;le.value=kf;break}case S.SET_LINK:{const{value:mf,bytesRead:nf}=this.decodeString(he,ie);ie+=nf;const{value:pf,bytesRead:qf}=this.decodeString(he,ie);ie+=qf;le.key=mf;le.value=pf;break}case S.ISLAND_MESSAGE:{const{value:rf,bytesRead:sf}=this.decodeHookData(he,ie);ie+=sf;le.data=rf;break}default:throw new Error(`Protocol decode: unknown patch type ${le.type}`)}return{patch:le,bytesRead:ie-ke}}decodeVNode(tf,uf,vf=0){if(vf>ia){throw new Error("Protocol decode: VNode depth exceeded")}const wf=uf;const xf={};this._ensureAvailable(tf,uf,1,"vnode type");const yf=tf[uf++];if(yf===255){return{vnode:null,bytesRead:uf-wf}}switch(yf){case ca.ELEMENT:{xf.type="element";const{value:zf,bytesRead:Af}=this.decodeString(tf,uf);xf.tag=zf;uf+=Af;const{value:Bf,bytesRead:Cf}=this.decodeString(tf,uf);xf.hid=Bf||null;uf+=Cf;const{value:Df,bytesRead:Ef}=this.decodeCollectionCount(tf,uf);uf+=Ef;xf.attrs={};for(let Hf=0;Hf<Df;Hf++){const{value:If,bytesRead:Jf}=this.decodeString(tf,uf);uf+=Jf;const{value:Kf,bytesRead:Lf}=this.decodeString(tf,uf);uf+=Lf;xf.attrs[If]=Kf}const{value:Ff,bytesRead:Gf}=this.decodeCollectionCount(tf,uf);uf+=Gf;xf.children=[];for(let Mf=0;Mf<Ff;Mf++){const{vnode:Nf,bytesRead:Of}=this.decodeVNode(tf,uf,vf+1);xf.children.push(Nf);uf+=Of}break}case ca.TEXT:{xf.type="text";const{value:Pf,bytesRead:Qf}=this.decodeString(tf,uf);xf.text=Pf;uf+=Qf;break}case ca.FRAGMENT:{xf.type="fragment";const{value:Rf,bytesRead:Sf}=this.decodeCollectionCount(tf,uf);uf+=Sf;xf.children=[];for(let Tf=0;Tf<Rf;Tf++){const{vnode:Uf,bytesRead:Vf}=this.decodeVNode(tf,uf,vf+1);xf.children.push(Uf);uf+=Vf}break}case ca.PORTAL:{xf.type="portal";const{value:Wf,bytesRead:Xf}=this.decodeString(tf,uf);xf.target=Wf;uf+=Xf;const{value:Yf,bytesRead:Zf}=this.decodeString(tf,uf);xf.hid=Yf||null;uf+=Zf;const{value:_f,bytesRead:$f}=this.decodeCollectionCount(tf,uf);uf+=$f;xf.children=[];for(let ag=0;ag<_f;ag++){const{vnode:bg,bytesRead:cg}=this.decodeVNode(tf,uf,vf+1);xf.children.push(bg);uf+=cg}break}default:throw new Error(`Protocol decode: unknown vnode type ${yf}`)}return{vnode:xf,bytesRead:uf-wf}}encodeString(dg){const eg=this.textEncoder.encode(dg);const fg=this.encodeUvarint(eg.length);return D([fg,eg])}decodeString(gg,hg){const{value:ig,bytesRead:jg}=this.decodeUvarint(gg,hg);this._checkAllocation(ig,"string");const kg=hg+jg;const lg=kg+ig;this._ensureAvailable(gg,kg,ig,"string bytes");const mg=gg.slice(kg,lg);const ng=this.textDecoder.decode(mg);return{value:ng,bytesRead:jg+ig}}encodeUvarint(og){const pg=[];while(og>127){pg.push(og&127|128);og>>>=7}pg.push(og&127);return new Uint8Array(pg)}decodeUvarint(qg,rg){let sg=0;let tg=0;let ug=0;while(true){if(rg+ug>=qg.length){throw new Error("Protocol decode: buffer too short for varint")}if(ug>=ea){throw new Error("Protocol decode: varint overflow")}if(tg>53){throw new Error("Protocol decode: varint exceeds JS safe integer")}const vg=qg[rg+ug];ug++;sg+=(vg&127)*Math.pow(2,tg);if(!Number.isSafeInteger(sg)){throw new Error("Protocol decode: varint exceeds JS safe integer")}if((vg&128)===0){break}tg+=7}return{value:sg,bytesRead:ug}}encodeSvarint(wg){const xg=wg<<1^wg>>31;return this.encodeUvarint(xg>>>0)}decodeSvarint(yg,zg){const{value:Ag,bytesRead:Bg}=this.decodeUvarint(yg,zg);const Cg=Ag%2===0?Ag/2:-((Ag+1)/2);return{value:Cg,bytesRead:Bg}}encodeLenBytes(Dg){const Eg=this.encodeUvarint(Dg.length);return D([Eg,Dg])}decodeLenBytes(Fg,Gg){const{value:Hg,bytesRead:Ig}=this.decodeUvarint(Fg,Gg);this._checkAllocation(Hg,"bytes");const Jg=Gg+Ig;const Kg=Jg+Hg;this._ensureAvailable(Fg,Jg,Hg,"bytes");const Lg=Fg.slice(Jg,Kg);return{value:Lg,bytesRead:Ig+Hg}}decodeCollectionCount(Mg,Ng){const{value:Og,bytesRead:Pg}=this.decodeUvarint(Mg,Ng);if(Og>ha){throw new Error("Protocol decode: collection count exceeds limit")}const Qg=Mg.length-(Ng+Pg);if(Og>Qg){throw new Error("Protocol decode: collection count exceeds remaining buffer")}return{value:Og,bytesRead:Pg}}encodeFormData(Rg,Sg){if(!Sg){Rg.push(this.encodeUvarint(0));return}const Tg=[];if(Sg instanceof FormData){for(const[Ug,Vg]of Sg.entries()){Tg.push({key:Ug,value:G(Vg)})}}else if(typeof Sg==="object"){for(const[Wg,Xg]of Object.entries(Sg)){const Yg=Array.isArray(Xg)?Xg:[Xg];for(const Zg of Yg){Tg.push({key:Wg,value:G(Zg)})}}}Rg.push(this.encodeUvarint(Tg.length));for(const{key:_g,value:$g}of Tg){Rg.push(this.encodeString(_g));Rg.push(this.encodeString($g))}}encodeKeyboardEvent(ah,bh){ah.push(this.encodeString((bh==null?void 0:bh.key)||""));ah.push(this.encodeString((bh==null?void 0:bh.code)||""));let ch=0;if(bh==null?void 0:bh.ctrlKey)ch|=T.CTRL;if(bh==null?void 0:bh.shiftKey)ch|=T.SHIFT;if(bh==null?void 0:bh.altKey)ch|=T.ALT;if(bh==null?void 0:bh.metaKey)ch|=T.META;ah.push(new Uint8Array([ch]));ah.push(new Uint8Array([(bh==null?void 0:bh.repeat)?1:0]));ah.push(new Uint8Array([(bh==null?void 0:bh.location)||0]))}encodeMouseEvent(dh,eh){dh.push(this.encodeSvarint((eh==null?void 0:eh.clientX)||0));dh.push(this.encodeSvarint((eh==null?void 0:eh.clientY)||0));dh.push(this.encodeSvarint((eh==null?void 0:eh.pageX)||0));dh.push(this.encodeSvarint((eh==null?void 0:eh.pageY)||0));dh.push(this.encodeSvarint((eh==null?void 0:eh.offsetX)||0));dh.push(this.encodeSvarint((eh==null?void 0:eh.offsetY)||0));dh.push(new Uint8Array([(eh==null?void 0:eh.button)||0]));dh.push(new Uint8Array([(eh==null?void 0:eh.buttons)||0]));let fh=0;if(eh==null?void 0:eh.ctrlKey)fh|=T.CTRL;if(eh==null?void 0:eh.shiftKey)fh|=T.SHIFT;if(eh==null?void 0:eh.altKey)fh|=T.ALT;if(eh==null?void 0:eh.metaKey)fh|=T.META;dh.push(new Uint8Array([fh]))}encodeScrollEvent(gh,hh){gh.push(this.encodeSvarint((hh==null?void 0:hh.scrollTop)||0));gh.push(this.encodeSvarint((hh==null?void 0:hh.scrollLeft)||0))}encodeResizeEvent(ih,jh){ih.push(this.encodeSvarint((jh==null?void 0:jh.width)||0));ih.push(this.encodeSvarint((jh==null?void 0:jh.height)||0))}encodeTouchEvent(kh,lh){const mh=(lh==null?void 0:lh.touches)||[];kh.push(this.encodeUvarint(mh.length));for(const ph of mh){kh.push(this.encodeSvarint(ph.identifier||ph.id||0));kh.push(this.encodeSvarint(ph.clientX||0));kh.push(this.encodeSvarint(ph.clientY||0));kh.push(this.encodeSvarint(ph.pageX||0));kh.push(this.encodeSvarint(ph.pageY||0))}const nh=(lh==null?void 0:lh.targetTouches)||[];kh.push(this.encodeUvarint(nh.length));for(const qh of nh){kh.push(this.encodeSvarint(qh.identifier||qh.id||0));kh.push(this.encodeSvarint(qh.clientX||0));kh.push(this.encodeSvarint(qh.clientY||0));kh.push(this.encodeSvarint(qh.pageX||0));kh.push(this.encodeSvarint(qh.pageY||0))}const oh=(lh==null?void 0:lh.changedTouches)||[];kh.push(this.encodeUvarint(oh.length));for(const rh of oh){kh.push(this.encodeSvarint(rh.identifier||rh.id||0));kh.push(this.encodeSvarint(rh.clientX||0));kh.push(this.encodeSvarint(rh.clientY||0));kh.push(this.encodeSvarint(rh.pageX||0));kh.push(this.encodeSvarint(rh.pageY||0))}}encodePointerEvent(sh,th){this.encodeMouseEvent(sh,th);sh.push(this.encodeSvarint((th==null?void 0:th.pointerId)||0));sh.push(this.encodeString((th==null?void 0:th.pointerType)||""));sh.push(this.encodeFloat64((th==null?void 0:th.pressure)||0));sh.push(this.encodeFloat64((th==null?void 0:th.width)||0));sh.push(this.encodeFloat64((th==null?void 0:th.height)||0));sh.push(this.encodeSvarint((th==null?void 0:th.tiltX)||0));sh.push(this.encodeSvarint((th==null?void 0:th.tiltY)||0));sh.push(new Uint8Array([(th==null?void 0:th.isPrimary)?1:0]))}encodeDragEvent(uh,vh){uh.push(this.encodeSvarint((vh==null?void 0:vh.clientX)||0));uh.push(this.encodeSvarint((vh==null?void 0:vh.clientY)||0));uh.push(this.encodeSvarint((vh==null?void 0:vh.pageX)||0));uh.push(this.encodeSvarint((vh==null?void 0:vh.pageY)||0));let wh=0;if(vh==null?void 0:vh.ctrlKey)wh|=T.CTRL;if(vh==null?void 0:vh.shiftKey)wh|=T.SHIFT;if(vh==null?void 0:vh.altKey)wh|=T.ALT;if(vh==null?void 0:vh.metaKey)wh|=T.META;uh.push(new Uint8Array([wh]));this.encodeStringList(uh,(vh==null?void 0:vh.types)||[]);this.encodeFileList(uh,(vh==null?void 0:vh.files)||[]);const xh=Object.entries((vh==null?void 0:vh.data)||{});uh.push(this.encodeUvarint(xh.length));for(const[yh,zh]of xh){uh.push(this.encodeString(yh));uh.push(this.encodeString(zh))}}encodeMediaEvent(Ah,Bh){Ah.push(this.encodeFloat64((Bh==null?void 0:Bh.currentTime)||0));Ah.push(this.encodeFloat64((Bh==null?void 0:Bh.duration)||0));Ah.push(new Uint8Array([(Bh==null?void 0:Bh.paused)?1:0]));Ah.push(new Uint8Array([(Bh==null?void 0:Bh.ended)?1:0]));Ah.push(this.encodeFloat64((Bh==null?void 0:Bh.volume)||0));Ah.push(new Uint8Array([(Bh==null?void 0:Bh.muted)?1:0]));Ah.push(this.encodeFloat64((Bh==null?void 0:Bh.playbackRate)||0))}encodeClipboardEvent(Ch,Dh){Ch.push(this.encodeString((Dh==null?void 0:Dh.text)||""));this.encodeStringList(Ch,(Dh==null?void 0:Dh.types)||[]);this.encodeFileList(Ch,(Dh==null?void 0:Dh.files)||[])}encodeStringList(Eh,Fh){Eh.push(this.encodeUvarint(Fh.length));for(const Gh of Fh){Eh.push(this.encodeString(Gh))}}encodeFileList(Hh,Ih){Hh.push(this.encodeUvarint(Ih.length));for(const Jh of Ih){Hh.push(this.encodeString(Jh.name||""));Hh.push(this.encodeString(Jh.type||""));Hh.push(this.encodeUvarint(Jh.size||0))}}encodeHookEvent(Kh,Lh){Kh.push(this.encodeString((Lh==null?void 0:Lh.name)||""));this.encodeHookData(Kh,(Lh==null?void 0:Lh.data)||{})}encodeHookData(Mh,Nh){const Oh=Object.entries(Nh);Mh.push(this.encodeUvarint(Oh.length));for(const[Ph,Qh]of Oh){Mh.push(this.encodeString(Ph));this.encodeHookValue(Mh,Qh)}}encodeHookValue(Rh,Sh){if(Sh===null||Sh===undefined){Rh.push(new Uint8Array([da.NULL]))}else if(typeof Sh==="boolean"){Rh.push(new Uint8Array([da.BOOL,Sh?1:0]))}else if(typeof Sh==="number"&&Number.isInteger(Sh)){Rh.push(new Uint8Array([da.INT]));Rh.push(this.encodeSvarint(Sh))}else if(typeof Sh==="number"){Rh.push(new Uint8Array([da.FLOAT]));Rh.push(this.encodeFloat64(Sh))}else if(typeof Sh==="string"){Rh.push(new Uint8Array([da.STRING]));Rh.push(this.encodeString(Sh))}else if(Array.isArray(Sh)){Rh.push(new Uint8Array([da.ARRAY]));Rh.push(this.encodeUvarint(Sh.length));for(const Th of Sh){this.encodeHookValue(Rh,Th)}}else if(typeof Sh==="object"){Rh.push(new Uint8Array([da.OBJECT]));const Uh=Object.entries(Sh);Rh.push(this.encodeUvarint(Uh.length));for(const[Vh,Wh]of Uh){Rh.push(this.encodeString(Vh));this.encodeHookValue(Rh,Wh)}}else{Rh.push(new Uint8Array([da.NULL]))}}decodeHookData(Xh,Yh){const Zh=Yh;const{value:$h,bytesRead:ai}=this.decodeCollectionCount(Xh,Yh);Yh+=ai;const bi={};for(let ci=0;ci<$h;ci++){const{value:ei,bytesRead:fi}=this.decodeString(Xh,Yh);Yh+=fi;const{value:gi,bytesRead:hi}=this.decodeHookValue(Xh,Yh,0);Yh+=hi;bi[ei]=gi}return{value:bi,bytesRead:Yh-Zh}}decodeHookValue(ii,ji,ki){if(ki>ka){throw new Error("Protocol decode: hook value depth exceeded")}const mi=ji;this._ensureAvailable(ii,ji,1,"hook value type");const ni=ii[ji++];switch(ni){case da.NULL:return{value:null,bytesRead:1};case da.BOOL:this._ensureAvailable(ii,ji,1,"hook bool");return{value:ii[ji]!==0,bytesRead:2};case da.INT:{const{value:oi,bytesRead:pi}=this.decodeSvarint(ii,ji);return{value:oi,bytesRead:1+pi}}case da.FLOAT:{this._ensureAvailable(ii,ji,8,"hook float");const qi=new DataView(ii.buffer,ii.byteOffset+ji,8);return{value:qi.getFloat64(0,true),bytesRead:9}}case da.STRING:{const{value:ri,bytesRead:si}=this.decodeString(ii,ji);return{value:ri,bytesRead:1+si}}case da.ARRAY:{const{value:ti,bytesRead:ui}=this.decodeCollectionCount(ii,ji);ji+=ui;const vi=[];for(let wi=0;wi<ti;wi++){const{value:xi,bytesRead:yi}=this.decodeHookValue(ii,ji,ki+1);ji+=yi;vi.push(xi)}return{value:vi,bytesRead:ji-mi}}case da.OBJECT:{const{value:zi,bytesRead:Ai}=this.decodeCollectionCount(ii,ji);ji+=Ai;const Bi={};for(let Ci=0;Ci<zi;Ci++){const{value:Di,bytesRead:Ei}=this.decodeString(ii,ji);ji+=Ei;const{value:Fi,bytesRead:Gi}=this.decodeHookValue(ii,ji,ki+1);ji+=Gi;Bi[Di]=Fi}return{value:Bi,bytesRead:ji-mi}}default:throw new Error(`Protocol decode: unknown hook value type ${ni}`)}}encodeFloat64(Hi){const Ii=new ArrayBuffer(8);new DataView(Ii).setFloat64(0,Hi,true);return new Uint8Array(Ii)}encodeClientHello(Ji={}){const Ki=[];Ki.push(new Uint8Array([W.major,W.minor]));Ki.push(this.encodeString(Ji.csrf||""));Ki.push(this.encodeString(Ji.sessionId||""));Ki.push(this.encodeUint32(Ji.lastSeq||0));Ki.push(this.encodeUint16(Ji.viewportW||window.innerWidth));Ki.push(this.encodeUint16(Ji.viewportH||window.innerHeight));const Li=new Date().getTimezoneOffset();Ki.push(this.encodeInt16(-Li));const Mi=Ji.codecs||[];const Ni=Ji.prefValues||[];const Oi=!!Ji.locale||!!Ji.prefs||Ni.length>0;if(Mi.length>0||Oi){Ki.push(this.encodeUvarint(Mi.length));Ki.push(new Uint8Array(Mi))}if(Oi){Ki.push(this.encodeString(Ji.locale||""));Ki.push(new Uint8Array([Ji.prefs||0]))}if(Ni.length>0){Ki.push(this.encodePrefValues(Ni))}return D(Ki)}encodeClientEnv(Pi){return D([new Uint8Array([65]),this.encodeUint16(Pi.viewportW),this.encodeUint16(Pi.viewportH),this.encodeInt16(Pi.tzOffset),this.encodeString(Pi.locale||""),new Uint8Array([Pi.prefs||0])])}encodePrefSync(Qi){return D([new Uint8Array([66]),this.encodePrefValues(Qi)])}encodePrefValues(Ri){const Si=[this.encodeUvarint(Ri.length)];for(const Ti of Ri){Si.push(this.encodeString(Ti.key));Si.push(this.encodeString(Ti.value));Si.push(this.encodeUint64(Ti.updatedAt))}return D(Si)}decodePrefSync(Ui,Vi=0){const{value:Wi,bytesRead:Xi}=this.decodeUvarint(Ui,Vi);Vi+=Xi;const Yi=[];for(let Zi=0;Zi<Wi;Zi++){const{value:_i,bytesRead:$i}=this.decodeString(Ui,Vi);Vi+=$i;const{value:aj,bytesRead:bj}=this.decodeString(Ui,Vi);Vi+=bj;const cj=this.decodeUint64(Ui,Vi);Vi+=8;Yi.push({key:_i,value:aj,updatedAt:cj})}return Yi}decodeGlobalListen(dj,ej=0){const{value:fj,bytesRead:gj}=this.decodeString(dj,ej);ej+=gj;const hj=dj[ej++];const{value:ij,bytesRead:jj}=this.decodeString(dj,ej);ej+=jj;const kj=dj[ej++];const{value:lj,bytesRead:mj}=this.decodeUvarint(dj,ej);ej+=mj;const{value:nj,bytesRead:oj}=this.decodeUvarint(dj,ej);ej+=oj;const{value:pj,bytesRead:qj}=this.decodeUvarint(dj,ej);ej+=qj;const rj=[];for(let tj=0;tj<pj;tj++){const{value:uj,bytesRead:vj}=this.decodeString(dj,ej);ej+=vj;rj.push(uj)}const sj=dj[ej];return{id:fj,target:hj,event:ij,flags:kj,debounce:lj,throttle:nj,keys:rj,keyMods:sj}}decodeHookCall(wj,xj=0){const{value:yj,bytesRead:zj}=this.decodeUvarint(wj,xj);xj+=zj;const{value:Aj,bytesRead:Bj}=this.decodeString(wj,xj);xj+=Bj;const{value:Cj,bytesRead:Dj}=this.decodeString(wj,xj);xj+=Dj;const{value:Ej}=this.decodeHookData(wj,xj);return{id:yj,hid:Aj,method:Cj,args:Ej}}encodeHookResult(Fj,Gj,Hj=""){const Ij=[new Uint8Array([70]),this.encodeUvarint(Fj),this.encodeString(Hj)];this.encodeHookValue(Ij,Gj);return D(Ij)}encodeClientHelloFrame(Jj={}){const Kj=this.encodeClientHello(Jj);const Lj=new Uint8Array(4+Kj.length);Lj[0]=0;Lj[1]=0;Lj[2]=Kj.length>>8&255;Lj[3]=Kj.length&255;Lj.set(Kj,4);return Lj}decodeServerHello(Mj){if(Mj.length<5){throw new Error("Protocol decode: handshake frame too short")}const Nj=Mj[0];if(Nj!==0){throw new Error(`Protocol decode: unexpected frame type ${Nj}`)}const Oj=Mj[2]<<8|Mj[3];if(Oj>la){throw new Error("Protocol decode: handshake payload exceeds max size")}if(Mj.length!==4+Oj){throw new Error("Protocol decode: handshake length mismatch")}let Pj=4;this._ensureAvailable(Mj,Pj,1,"handshake status");const Qj=Mj[Pj++];const{value:Rj,bytesRead:Sj}=this.decodeString(Mj,Pj);Pj+=Sj;const Tj=this.decodeUint32(Mj,Pj);Pj+=4;const Uj=this.decodeUint64(Mj,Pj);Pj+=8;const Vj=this.decodeUint16(Mj,Pj);Pj+=2;let Wj;let Xj=X;let Yj;const Zj=()=>{const _j={major:Mj[Pj],minor:Mj[Pj+1]};Pj+=2;return _j};if(Qj===0){if(Pj+2<=Mj.length){Xj=Zj()}}else if(Qj===1||Qj===5){if(Pj+4<=Mj.length){Yj=Zj();Xj=Zj()}}else if(Pj<Mj.length){Wj=Mj[Pj]}return{status:Qj,sessionId:Rj,nextSeq:Tj,serverTime:Uj,flags:Vj,codec:Vj&_?Vj>>8&255:U.NONE,authReason:Wj,version:Xj,minVersion:Yj,ok:Qj===0}}encodeFrames($j,ak,bk=0){if(ak.length<=la){return[this._encodeFrame($j,bk,ak)]}const ck=[];for(let dk=0;dk<ak.length;dk+=la){const ek=Math.min(dk+la,ak.length);let fk=bk|Z.FRAGMENT;if(ek===ak.length){fk|=Z.FINAL}ck.push(this._encodeFrame($j,fk,ak.subarray(dk,ek)))}return ck}_encodeFrame(gk,hk,ik){const jk=new Uint8Array(4+ik.length);jk[0]=gk;jk[1]=hk;jk[2]=ik.length>>8&255;jk[3]=ik.length&255;jk.set(ik,4);return jk}createDecompressor(kk){switch(kk){case U.NONE:return null;case U.DEFLATE:return new Q;default:throw new Error(`Protocol decode: unsupported codec ${kk}`)}}decompressPayload(lk,mk,nk=ma){if(!mk){throw new Error("Protocol decode: compressed frame without a negotiated codec")}const{value:pk,bytesRead:qk}=this.decodeUvarint(lk,0);if(pk>nk){throw new Error("Protocol decode: decompressed payload exceeds max size")}return mk.inflate(lk.subarray(qk),pk)}encodeUint16(rk){return new Uint8Array([rk>>8&255,rk&255])}decodeUint16(sk,tk){this._ensureAvailable(sk,tk,2,"uint16");return sk[tk]<<8|sk[tk+1]}encodeInt16(uk){return this.encodeUint16(uk&65535)}encodeUint32(vk){return new Uint8Array([vk>>24&255,vk>>16&255,vk>>8&255,vk&255])}encodeUint64(wk){const xk=Math.floor(wk/4294967296);return D([this.encodeUint32(xk),this.encodeUint32(wk-xk*4294967296)])}decodeUint32(yk,zk){this._ensureAvailable(yk,zk,4,"uint32");return yk[zk]*16777216+(yk[zk+1]<<16)+(yk[zk+2]<<8)+yk[zk+3]}decodeUint64(Ak,Bk){const Ck=this.decodeUint32(Ak,Bk);const Dk=this.decodeUint32(Ak,Bk+4);const Ek=Ck*4294967296+Dk;if(!Number.isSafeInteger(Ek)){throw new Error("Protocol decode: uint64 exceeds JS safe integer")}return Ek}_checkAllocation(Fk,Gk){if(Fk>ga){throw new Error(`Protocol decode: ${Gk} exceeds hard cap`)}if(Fk>fa){throw new Error(`Protocol decode: ${Gk} exceeds max allocation`)}}_ensureAvailable(Hk,Ik,Jk,Kk){if(Ik+Jk>Hk.length){throw new Error(`Protocol decode: buffer too short for ${Kk}`)}}encodeAck(Lk,Mk=100){return D([this.encodeUvarint(Lk),this.encodeUvarint(Mk)])}encodeBlob(Nk,Ok,Pk={}){const Qk=[new Uint8Array([Nk]),this.encodeUvarint(Ok)];switch(Nk){case aa.START:Qk.push(this.encodeString(Pk.hid));Qk.push(this.encodeString(Pk.filename||""));Qk.push(this.encodeString(Pk.type||""));Qk.push(this.encodeUvarint(Pk.size));break;case aa.CHUNK:Qk.push(this.encodeUvarint(Pk.offset));Qk.push(this.encodeLenBytes(Pk.data));break}return D(Qk)}decodeBlobAck(Rk,Sk=0){const{value:Tk,bytesRead:Uk}=this.decodeUvarint(Rk,Sk);Sk+=Uk;const{value:Vk,bytesRead:Wk}=this.decodeUvarint(Rk,Sk);Sk+=Wk;this._ensureAvailable(Rk,Sk,1,"blob status");const Xk=Rk[Sk++];const{value:Yk}=this.decodeString(Rk,Sk);return{id:Tk,received:Vk,status:Xk,message:Yk}}encodeResyncRequest(Zk){return D([new Uint8Array([16]),this.encodeUvarint(Zk)])}}function pa(_k){const $k=new URL(_k,location.href);$k.protocol=$k.protocol==="wss:"?"https:":$k.protocol==="ws:"?"http:":$k.protocol;$k.pathname=$k.pathname.replace(/\/_vango\/[^/]+$/,"/_vango/sse");return $k.toString()}function qa(al){const bl=atob(al);const cl=new Uint8Array(bl.length);for(let dl=0;dl<bl.length;dl++){cl[dl]=bl.charCodeAt(dl)}return cl.buffer}class ra{constructor(fl){this.url=fl;this.readyState=ra.CONNECTING;this.binaryType="arraybuffer";this.connId=null;this.onopen=null;this.onmessage=null;this.onclose=null;this.onerror=null;this.pending=[];this.inflight=false;this.source=new EventSource(fl);this.source.addEventListener("conn",gl=>this._onConn(gl));this.source.addEventListener("close",()=>this._finish(1000,"",true));this.source.onmessage=hl=>this._onData(hl);this.source.onerror=il=>{var jl;if(this.readyState===ra.CLOSED)return;(jl=this.onerror)==null?void 0:jl.call(this,il);this._finish(1006,"",false)}}_onConn(kl){var ll;this.connId=kl.data;this.readyState=ra.OPEN;(ll=this.onopen)==null?void 0:ll.call(this,{type:"open"})}_onData(ml){var nl;if(this.readyState!==ra.OPEN)return;let ol;try{ol=qa(ml.data)}catch(_e){this._finish(1007,"Invalid frame encoding",false);return}(nl=this.onmessage)==null?void 0:nl.call(this,{type:"message",data:ol})}send(pl){if(this.readyState===ra.CONNECTING){throw new Error("HTTPFallbackSocket is not open")}if(this.readyState!==ra.OPEN)return;this.pending.push(pl instanceof ArrayBuffer?new Uint8Array(pl):pl);if(this.pending.length===1&&!this.inflight){queueMicrotask(()=>this._flush())}}_flush(){if(this.inflight||this.pending.length===0||this.readyState!==ra.OPEN){return}const ql=this.pending;this.pending=[];let rl=0;for(const vl of ql)rl+=vl.length;const sl=new Uint8Array(rl);let tl=0;for(const wl of ql){sl.set(wl,tl);tl+=wl.length}this.inflight=true;const ul=this.url.replace(/\?.*$/,"")+"?conn="+encodeURIComponent(this.connId);fetch(ul,{method:"POST",body:sl,credentials:"same-origin",headers:{"Content-Type":"application/octet-stream"}}).then(xl=>{this.inflight=false;if(!xl.ok){this._finish(1006,`POST failed: ${xl.status}`,false);return}this._flush()},()=>{this.inflight=false;this._finish(1006,"POST failed",false)})}close(yl=1000,zl=""){if(this.readyState===ra.CLOSED)return;this.source.close();this.readyState=ra.CLOSING;setTimeout(()=>this._finish(yl,zl,true),0)}_finish(Al,Bl,Cl){var Dl;if(this.readyState===ra.CLOSED)return;this.readyState=ra.CLOSED;this.source.close();this.pending=[];(Dl=this.onclose)==null?void 0:Dl.call(this,{type:"close",code:Al,reason:Bl,wasClean:Cl})}}ra.CLOSED=3;ra.CLOSING=2;ra.OPEN=1;ra.CONNECTING=0;const sa={DARK:1,LIGHT:2,REDUCED_MOTION:4};const ta=["(prefers-color-scheme: dark)","(prefers-color-scheme: light)","(prefers-reduced-motion: reduce)"];function ua(El){return typeof window.matchMedia==="function"&&window.matchMedia(El).matches}function va(){let Fl=0;if(ua(ta[0]))Fl|=sa.DARK;else if(ua(ta[1]))Fl|=sa.LIGHT;if(ua(ta[2]))Fl|=sa.REDUCED_MOTION;return{viewportW:Math.min(window.innerWidth,65535),viewportH:Math.min(window.innerHeight,65535),tzOffset:-new Date().getTimezoneOffset(),locale:typeof navigator!=="undefined"&&navigator.language||"",prefs:Fl}}function wa(Gl,Hl){return!!Gl&&!!Hl&&Gl.viewportW===Hl.viewportW&&Gl.viewportH===Hl.viewportH&&Gl.tzOffset===Hl.tzOffset&&Gl.locale===Hl.locale&&Gl.prefs===Hl.prefs}class xa{constructor(Il,Jl={}){var Kl;this.client=Il;this.debounce=(Kl=Jl.resizeDebounce)!=null?Kl:150;this.last=null;this.timer=null;this.cleanups=[]}attach(){const Ll=()=>{clearTimeout(this.timer);this.timer=setTimeout(()=>this.report(),this.debounce)};const Ml=()=>this.report();window.addEventListener("resize",Ll);window.addEventListener("languagechange",Ml);this.cleanups.push(()=>{window.removeEventListener("resize",Ll);window.removeEventListener("languagechange",Ml);clearTimeout(this.timer)});if(typeof window.matchMedia==="function"){for(const Nl of ta){const Ol=window.matchMedia(Nl);if(Ol&&typeof Ol.addEventListener==="function"){Ol.addEventListener("change",Ml);this.cleanups.push(()=>Ol.removeEventListener("change",Ml))}}}}detach(){for(const Pl of this.cleanups)Pl();this.cleanups=[]}markReported(Ql){this.last=Ql}report(){if(!this.client.connected)return;const Rl=va();if(wa(Rl,this.last))return;this.last=Rl;const Sl=this.client.codec.encodeClientEnv(Rl);for(const Tl of this.client.codec.encodeFrames(3,Sl)){this.client.wsManager.send(Tl)}if(this.client.options.debug){console.log("[Vango] Reported client env",Rl)}}}const ya=30000;function za(Ul){return`${Ul.major}.${Ul.minor}`}class Aa{constructor(Vl,Wl={}){this.client=Vl;this.options={reconnect:Wl.reconnect!==false,reconnectInterval:Wl.reconnectInterval||1000,reconnectMaxInterval:Wl.reconnectMaxInterval||30000,heartbeatInterval:Wl.heartbeatInterval||30000,...Wl};this.ws=null;this.connected=false;this.handshakeComplete=false;this.sessionId=null;this.lastSeq=0;this.reconnectAttempts=0;this.reconnectDisabled=false;this.heartbeatTimer=null;this.messageQueue=[];this.useHTTPFallback=Wl.transport==="sse";this.fellBack=false;this.opened=false;const Xl=this._loadResumeInfo();if(Xl){this.sessionId=Xl.sessionId;this.lastSeq=Xl.lastSeq}}connect(Yl){if(this.ws){this.ws.close()}this.url=Yl;this.opened=false;this.ws=this.useHTTPFallback?new ra(pa(Yl)):new WebSocket(Yl);this.ws.binaryType="arraybuffer";this.ws.onopen=()=>this._onOpen();this.ws.onclose=Zl=>this._onClose(Zl);this.ws.onerror=_l=>this._onError(_l);this.ws.onmessage=$l=>this._onMessage($l)}_onOpen(){if(this.client.options.debug){console.log("[Vango] WebSocket connected")}this.opened=true;this.reconnectAttempts=0;this._sendHandshake();this._startHeartbeat()}_sendHandshake(){var am,bm;const cm=va();const dm=this.client.codec.encodeClientHelloFrame({csrf:this._getCSRFToken(),sessionId:this.sessionId||"",lastSeq:this.lastSeq||this.client.patchSeq||0,viewportW:cm.viewportW,viewportH:cm.viewportH,locale:cm.locale,prefs:cm.prefs,prefValues:((am=this.client.prefs)==null?void 0:am.storedValues())||[],codecs:this.client.options.compression===false?[]:V});this.ws.send(dm);(bm=this.client.env)==null?void 0:bm.markReported(cm);if(this.client.options.debug){console.log("[Vango] Sent framed ClientHello")}}_getCSRFToken(){if(window.__VANGO_CSRF__){return window.__VANGO_CSRF__}const em=document.cookie.match(/(?:^|;\s*)__vango_csrf=([^;]*)/);return em?decodeURIComponent(em[1]):""}_onMessage(fm){if(!(fm.data instanceof ArrayBuffer)){if(this.client.options.debug){console.warn("[Vango] Received non-binary message:",fm.data)}return}const gm=new Uint8Array(fm.data);if(!this.handshakeComplete){let hm;try{hm=this.client.codec.decodeServerHello(gm)}catch(err){this.client._handleProtocolError(err,"handshake");return}if(!hm.ok){const im={1:"Version mismatch",2:"Invalid CSRF token",3:"Session expired",4:"Server busy",5:"Upgrade required",6:"Invalid format",7:"Not authorized",8:"Internal error",9:"Too many active sessions from this IP"};const jm=im[hm.status]||`Handshake failed: ${hm.status}`;const km=new Error(hm.minVersion?`${jm}: client speaks ${za(W)}, server ${za(hm.minVersion)}-${za(hm.version)}`:jm);if(hm.authReason!==undefined){km.vangoAuthReason=hm.authReason;if(this.client.options.debug){console.log("[Vango] Handshake auth reason:",hm.authReason)}}this.client._onError(km);if(hm.status===1||hm.status===5){this._reloadForVersion(hm)}if(hm.status===9){this.reconnectDisabled=true;this.client.connection.setDisconnected("ip_limit")}if(hm.status===3||hm.status===7){this._clearResumeInfo();this.sessionId=null;this.lastSeq=0;this.reconnectDisabled=true;this.options.reconnect=false;setTimeout(()=>location.reload(),0)}this.ws.close();return}this.client.reassembler.reset();try{this.client.decompressor=this.client.codec.createDecompressor(hm.codec)}catch(err){this.client._handleProtocolError(err,"handshake");return}this.client.protocolVersion=hm.version;if(this.client.uploads){this.client.uploads.enabled=(hm.flags&$)!==0}this.handshakeComplete=true;this.connected=true;this.sessionId=hm.sessionId;this._persistResumeInfo(this.sessionId,this.lastSeq||this.client.patchSeq||0);this.client._onConnected();this._flushQueue();if(this.client.options.debug){console.log("[Vango] Handshake complete, session:",this.sessionId)}return}this.client._handleBinaryMessage(gm)}_reloadForVersion(lm){this.reconnectDisabled=true;this.options.reconnect=false;this.client.connection.setDisconnected("version");const mm="__vango_version_reload";const nm=Date.now();if(this._storageAvailable()){try{const om=Number(sessionStorage.getItem(mm)||0);if(nm-om<ya){return}sessionStorage.setItem(mm,String(nm))}catch(_e){}}this._clearResumeInfo();setTimeout(()=>location.reload(),0)}_onClose(pm){var qm;const rm=this.connected;this.connected=false;this.handshakeComplete=false;this._stopHeartbeat();if(this.client.options.debug){console.log("[Vango] WebSocket closed:",pm.code,pm.reason)}const sm=(qm=this.client.eventCapture)==null?void 0:qm.pendingNavPath;if(rm&&sm){console.log("[Vango] Connection lost during navigation, completing via location.assign:",sm);location.assign(sm);return}if(rm){this.client._onDisconnected()}if(!this.opened&&!this.useHTTPFallback&&this._httpFallbackAvailable()){if(this.client.options.debug){console.log("[Vango] WebSocket upgrade failed, falling back to SSE")}this.useHTTPFallback=true;this.fellBack=true;this.connect(this.url);return}if(!this.opened&&this.fellBack){this.useHTTPFallback=false;this.fellBack=false}if(this.options.reconnect&&!this.reconnectDisabled&&!pm.wasClean){this._scheduleReconnect()}}_httpFallbackAvailable(){return this.client.options.httpFallback!==false&&!this.reconnectDisabled&&typeof EventSource!=="undefined"&&typeof fetch!=="undefined"}_onError(tm){if(this.client.options.debug){console.error("[Vango] WebSocket error:",tm)}this.client._onError(new Error("WebSocket error"))}_scheduleReconnect(){const um=Math.min(this.options.reconnectInterval*Math.pow(2,this.reconnectAttempts),this.options.reconnectMaxInterval);this.reconnectAttempts++;if(this.client.options.debug){console.log(`[Vango] Reconnecting in ${um}ms (attempt ${this.reconnectAttempts})`)}setTimeout(()=>this.connect(this.url),um)}_startHeartbeat(){this.heartbeatTimer=setInterval(()=>{if(this.connected){this._sendPing()}},this.options.heartbeatInterval)}_stopHeartbeat(){if(this.heartbeatTimer){clearInterval(this.heartbeatTimer);this.heartbeatTimer=null}}_sendPing(){if(this.ws&&this.ws.readyState===WebSocket.OPEN){const vm=Date.now();const wm=new Uint8Array(9);wm[0]=1;let xm=vm;for(let zm=0;zm<8;zm++){wm[1+zm]=xm&255;xm=Math.floor(xm/256)}const ym=new Uint8Array(4+wm.length);ym[0]=3;ym[1]=0;ym[2]=wm.length>>8&255;ym[3]=wm.length&255;ym.set(wm,4);this.ws.send(ym)}}send(Am){if(this.connected&&this.ws&&this.ws.readyState===WebSocket.OPEN){this.ws.send(Am);return true}else{this.messageQueue.push(Am);return false}}_flushQueue(){while(this.messageQueue.length>0&&this.connected){const Bm=this.messageQueue.shift();this.ws.send(Bm)}}close(){this.options.reconnect=false;this._stopHeartbeat();if(this.ws){this.ws.close(1000,"Client closing");this.ws=null}}resetReconnect(){this.reconnectDisabled=false;this.reconnectAttempts=0}updateLastSeq(Cm){const Dm=Number(Cm);if(!Number.isFinite(Dm)||Dm<0)return;this.lastSeq=Dm;if(this.sessionId){this._persistResumeInfo(this.sessionId,this.lastSeq)}}_storageAvailable(){try{return typeof sessionStorage!=="undefined"}catch(_e2){return false}}_loadResumeInfo(){if(!this._storageAvailable())return null;try{const Em=sessionStorage.getItem("__vango_session_id")||"";if(!Em)return null;const Fm=sessionStorage.getItem("__vango_last_seq")||"0";const Gm=Number(Fm);return{sessionId:Em,lastSeq:Number.isFinite(Gm)&&Gm>=0?Gm:0}}catch(_e3){return null}}_persistResumeInfo(Hm,Im){if(!this._storageAvailable())return;try{sessionStorage.setItem("__vango_session_id",Hm||"");sessionStorage.setItem("__vango_last_seq",String(Im||0))}catch(_e4){}}_clearResumeInfo(){if(!this._storageAvailable())return;try{sessionStorage.removeItem("__vango_session_id");sessionStorage.removeItem("__vango_last_seq")}catch(_e5){}}clearResumeInfo(){this._clearResumeInfo();this.sessionId=null;this.lastSeq=0}}const Ba={scroll:100,pointermove:50,drag:50,dragover:50,timeupdate:250};const Ca=["pointerdown","pointerup","pointermove","pointerenter","pointerleave","pointercancel"];const Da=["dragstart","drag","dragenter","dragover","dragleave","dragend","drop"];const Ea=["play","pause","ended","timeupdate","seeking","seeked","volumechange","ratechange","durationchange","loadedmetadata","loadeddata","canplay","canplaythrough","waiting","playing"];const Fa=["copy","cut","paste"];const Ga=new Set(["contextmenu","toggle","dragenter","dragover","dragleave","drag",...Ca,...Ea,...Fa]);function Ha(Jm){return Array.from(Jm||[],Km=>({name:Km.name,type:Km.type,size:Km.size}))}class Ia{constructor(Lm){this.client=Lm;this.handlers=new Map;this.debounceTimers=new Map;this.throttled=new Set;this.prefetchedPaths=new Set}attach(){this._on("click",this._handleClick.bind(this));this._on("dblclick",this._handleDblClick.bind(this));this._on("input",this._handleInput.bind(this));this._on("change",this._handleChange.bind(this));this._on("submit",this._handleSubmit.bind(this));this._on("focus",this._handleFocus.bind(this),true);this._on("blur",this._handleBlur.bind(this),true);this._on("keydown",this._handleKeyDown.bind(this));this._on("keyup",this._handleKeyUp.bind(this));this._on("mouseenter",this._handleMouseEnter.bind(this),true);this._on("mouseleave",this._handleMouseLeave.bind(this),true);this._on("contextmenu",this._handleContextMenu.bind(this));for(const Mm of Ca){this._on(Mm,this._handlePointer.bind(this),Mm==="pointerenter"||Mm==="pointerleave")}for(const Nm of Da){this._on(Nm,this._handleDrag.bind(this))}for(const Om of Ea){this._on(Om,this._handleMedia.bind(this),true)}this._on("toggle",this._handleToggle.bind(this),true);for(const Pm of Fa){this._on(Pm,this._handleClipboard.bind(this))}this._on("scroll",this._handleScroll.bind(this),true);this._on("click",this._handleLinkClick.bind(this));window.addEventListener("popstate",this._handlePopState.bind(this));this._on("mouseenter",this._handlePrefetch.bind(this),true)}detach(){for(const[Qm,{handler:Rm,capture:Sm}]of this.handlers){document.removeEventListener(Qm,Rm,Sm)}this.handlers.clear();for(const Tm of this.debounceTimers.values()){clearTimeout(Tm)}this.debounceTimers.clear()}_on(Um,Vm,Wm=false){document.addEventListener(Um,Vm,{capture:Wm,passive:false});this.handlers.set(`${Um}-${Wm}`,{handler:Vm,capture:Wm})}_findHidElement(Xm){if(!Xm||!Xm.closest){return null}return Xm.closest("[data-hid]")}_closest(Ym,Zm){if(!Ym||typeof Ym.closest!=="function"){return null}return Ym.closest(Zm)}_hasEvent(_m,$m){const an=(_m.dataset.ve||"").split(",").map(bn=>bn.trim());return an.includes($m)}_getModifiers(cn,dn){return{preventDefault:cn.dataset[`pd${this._capitalize(dn)}`]==="true",stopPropagation:cn.dataset[`sp${this._capitalize(dn)}`]==="true",self:cn.dataset[`self${this._capitalize(dn)}`]==="true",once:cn.dataset[`once${this._capitalize(dn)}`]==="true",passive:cn.dataset[`passive${this._capitalize(dn)}`]==="true",capture:cn.dataset[`capture${this._capitalize(dn)}`]==="true",debounce:parseInt(cn.dataset[`debounce${this._capitalize(dn)}`]||cn.dataset.debounce||"0",10),throttle:parseInt(cn.dataset[`throttle${this._capitalize(dn)}`]||cn.dataset.throttle||"0",10)}}_capitalize(en){return en.charAt(0).toUpperCase()+en.slice(1)}_applyModifiers(gn,hn,jn){const kn=this._getModifiers(hn,jn);if(kn.self&&gn.target!==hn){return false}if(kn.passive){const ln=gn.preventDefault.bind(gn);gn.preventDefault=()=>{console.warn("[Vango] preventDefault() called on passive handler - ignored")};queueMicrotask(()=>{gn.preventDefault=ln})}else if(kn.preventDefault){gn.preventDefault()}if(kn.stopPropagation){gn.stopPropagation()}if(kn.once){const mn=(hn.dataset.ve||"").split(",").map(on=>on.trim());const nn=mn.filter(pn=>pn!==jn);if(nn.length>0){hn.dataset.ve=nn.join(",")}else{delete hn.dataset.ve}}return true}_getDebounce(qn,rn){const sn=this._getModifiers(qn,rn);if(rn==="input"&&sn.debounce===0){return 100}return sn.debounce}_getThrottle(tn,un){const vn=this._getModifiers(tn,un);if(vn.throttle===0&&Ba[un]){return Ba[un]}return vn.throttle}_isThrottled(wn,xn){const yn=this._getThrottle(wn,xn);if(yn<=0){return false}const zn=`${wn.dataset.hid}:${xn}`;if(this.throttled.has(zn)){return true}this.throttled.add(zn);setTimeout(()=>{this.throttled.delete(zn)},yn);return false}_delegate(An,Bn,Cn){const Dn=An.type;if(Ga.has(Dn)&&!this._supports22()){return null}const En=Bn?this._findHidElementWithEvent(An.target,Dn):this._ownHidElement(An.target,Dn);if(!En)return null;if(!this._applyModifiers(An,En,Dn)){return null}if(this._isThrottled(En,Dn)){return null}this.client.sendEvent(R[Dn.toUpperCase()],En.dataset.hid,Cn(En));return En}_supports22(){const Fn=this.client.protocolVersion;return!Fn||Y(Fn,2,2)}_ownHidElement(Gn,Hn){if(!Gn||!Gn.dataset||!Gn.dataset.hid){return null}return this._hasEvent(Gn,Hn)?Gn:null}_findHidElementWithEvent(In,Jn){if(!In||!In.closest){return null}let Kn=In.closest("[data-hid]");while(Kn){if(this._hasEvent(Kn,Jn)){return Kn}const Ln=Kn.parentElement;if(!Ln)break;Kn=Ln.closest("[data-hid]")}return null}_handleClick(Mn){if(Mn.defaultPrevented)return;if(Mn.button!==0)return;if(Mn.ctrlKey||Mn.metaKey||Mn.shiftKey||Mn.altKey)return;if(!this.client.connected)return;const Nn=this._findHidElementWithEvent(Mn.target,"click");if(!Nn)return;const On=this._closest(Mn.target,"a[href]");if(On){const Pn=On.getAttribute("target");if(Pn&&Pn!=="_self")return;if(On.hasAttribute("download"))return;const Qn=On.getAttribute("href");if(Qn){try{const Rn=new URL(Qn,location.href);if(Rn.origin!==location.origin)return}catch(_e){return}}}if(!this._applyModifiers(Mn,Nn,"click")){return}Mn.preventDefault();this.client.optimistic.applyOptimistic(Nn,"click");this.client.sendEvent(R.CLICK,Nn.dataset.hid)}_handleDblClick(Sn){const Tn=this._findHidElement(Sn.target);if(!Tn||!this._hasEvent(Tn,"dblclick"))return;if(!this._applyModifiers(Sn,Tn,"dblclick")){return}const Un=this._getModifiers(Tn,"dblclick");if(!Un.preventDefault&&!Un.passive){Sn.preventDefault()}this.client.sendEvent(R.DBLCLICK,Tn.dataset.hid)}_handleInput(Vn){const Wn=this._findHidElement(Vn.target);if(!Wn||!this._hasEvent(Wn,"input"))return;if(!this._applyModifiers(Vn,Wn,"input")){return}const Xn=Wn.dataset.hid;const Yn=this._getDebounce(Wn,"input");if(this.debounceTimers.has(Xn)){clearTimeout(this.debounceTimers.get(Xn))}if(Yn===0){this.client.sendEvent(R.INPUT,Xn,{value:Wn.value});return}const Zn=setTimeout(()=>{this.debounceTimers.delete(Xn);this.client.sendEvent(R.INPUT,Xn,{value:Wn.value})},Yn);this.debounceTimers.set(Xn,Zn)}_handleChange(_n){const $n=this._findHidElement(_n.target);if(!$n)return;if($n.type==="file"&&this._hasEvent($n,"upload")){this._uploadFiles($n,$n.files)}if(!this._hasEvent($n,"change"))return;if(!this._applyModifiers(_n,$n,"change")){return}let ao;if($n.type==="checkbox"){ao=$n.checked?"true":"false"}else if($n.type==="radio"){ao=$n.checked?$n.value:""}else if($n.tagName==="SELECT"&&$n.multiple){ao=Array.from($n.selectedOptions).map(bo=>bo.value).join(",")}else{ao=$n.value}this.client.optimistic.applyOptimistic($n,"change");this.client.sendEvent(R.CHANGE,$n.dataset.hid,{value:ao})}_handleUploadDragOver(co){if(!co.dataTransfer||!Array.from(co.dataTransfer.types||[]).includes("Files"))return;const eo=this._findHidElementWithEvent(co.target,"upload");if(!eo)return;co.preventDefault();co.dataTransfer.dropEffect="copy"}_handleUploadDrop(fo){var go;const ho=(go=fo.dataTransfer)==null?void 0:go.files;if(!ho||ho.length===0)return;const io=this._findHidElementWithEvent(fo.target,"upload");if(!io)return;fo.preventDefault();this._uploadFiles(io,ho)}_uploadFiles(jo,ko){if(!ko)return;for(const lo of Array.from(ko)){if(!this.client.uploads.upload(jo.dataset.hid,lo)){if(this.client.options.debug){console.warn("[Vango] Upload not sent: blob uploads unavailable",lo.name)}}}}_handleSubmit(mo){const no=this._closest(mo.target,"form[data-hid]");if(!no||!this._hasEvent(no,"submit"))return;if(!this.client.connected)return;if(mo.defaultPrevented)return;mo.preventDefault();const oo=new FormData(no);const po={};for(const[qo,ro]of oo.entries()){const so=G(ro);if(Object.prototype.hasOwnProperty.call(po,qo)){po[qo]=[].concat(po[qo],so)}else{po[qo]=so}}this.client.sendEvent(R.SUBMIT,no.dataset.hid,po)}_handleFocus(uo){const vo=this._findHidElement(uo.target);if(!vo||!this._hasEvent(vo,"focus"))return;if(!this._applyModifiers(uo,vo,"focus")){return}this.client.sendEvent(R.FOCUS,vo.dataset.hid)}_handleBlur(wo){const xo=this._findHidElement(wo.target);if(!xo||!this._hasEvent(xo,"blur"))return;if(!this._applyModifiers(wo,xo,"blur")){return}this.client.sendEvent(R.BLUR,xo.dataset.hid)}_handleKeyDown(yo){const zo=this._findHidElement(yo.target);if(!zo||!this._hasEvent(zo,"keydown"))return;const Ao=zo.dataset.keyFilter;if(Ao&&!this._matchesKeyFilter(yo,Ao)){return}if(!this._applyModifiers(yo,zo,"keydown")){return}this.client.sendEvent(R.KEYDOWN,zo.dataset.hid,{key:yo.key,code:yo.code,ctrlKey:yo.ctrlKey,shiftKey:yo.shiftKey,altKey:yo.altKey,metaKey:yo.metaKey,repeat:yo.repeat,location:yo.location})}_handleKeyUp(Bo){const Co=this._findHidElement(Bo.target);if(!Co||!this._hasEvent(Co,"keyup"))return;if(!this._applyModifiers(Bo,Co,"keyup")){return}this.client.sendEvent(R.KEYUP,Co.dataset.hid,{key:Bo.key,code:Bo.code,ctrlKey:Bo.ctrlKey,shiftKey:Bo.shiftKey,altKey:Bo.altKey,metaKey:Bo.metaKey,repeat:Bo.repeat,location:Bo.location})}_handleMouseEnter(Do){const Eo=this._findHidElement(Do.target);if(!Eo||!this._hasEvent(Eo,"mouseenter"))return;if(!this._applyModifiers(Do,Eo,"mouseenter")){return}this.client.sendEvent(R.MOUSEENTER,Eo.dataset.hid)}_handleMouseLeave(Fo){const Go=this._findHidElement(Fo.target);if(!Go||!this._hasEvent(Go,"mouseleave"))return;if(!this._applyModifiers(Fo,Go,"mouseleave")){return}this.client.sendEvent(R.MOUSELEAVE,Go.dataset.hid)}_handleContextMenu(Ho){const Io=this._delegate(Ho,true,()=>Ho);if(!Io)return;const Jo=this._getModifiers(Io,"contextmenu");if(!Jo.preventDefault&&!Jo.passive){Ho.preventDefault()}}_handlePointer(Ko){const Lo=Ko.type!=="pointerenter"&&Ko.type!=="pointerleave";this._delegate(Ko,Lo,()=>Ko)}_handleDrag(Mo){if(Mo.type==="dragover"){this._handleUploadDragOver(Mo)}else if(Mo.type==="drop"){this._handleUploadDrop(Mo)}if((Mo.type==="dragenter"||Mo.type==="dragover")&&this._findHidElementWithEvent(Mo.target,"drop")){Mo.preventDefault()}const No=this._delegate(Mo,true,()=>this._dragPayload(Mo));if(No&&Mo.type==="drop"){Mo.preventDefault()}}_dragPayload(Oo){const Po=Oo.dataTransfer;const Qo=Array.from((Po==null?void 0:Po.types)||[]);const Ro={};let So=[];if(Oo.type==="drop"&&Po){for(const To of Qo){if(To!=="Files"){Ro[To]=Po.getData(To)}}So=Ha(Po.files)}return{clientX:Oo.clientX,clientY:Oo.clientY,pageX:Oo.pageX,pageY:Oo.pageY,ctrlKey:Oo.ctrlKey,shiftKey:Oo.shiftKey,altKey:Oo.altKey,metaKey:Oo.metaKey,types:Qo,files:So,data:Ro}}_handleMedia(Uo){this._delegate(Uo,false,Vo=>Vo)}_handleClipboard(Wo){this._delegate(Wo,true,()=>{const Xo=Wo.clipboardData;const Yo=Wo.type==="paste";return{text:Yo?(Xo==null?void 0:Xo.getData("text/plain"))||"":"",types:Array.from((Xo==null?void 0:Xo.types)||[]),files:Yo?Ha(Xo==null?void 0:Xo.files):[]}})}_handleToggle(Zo){this._delegate(Zo,false,_o=>({open:Zo.newState?Zo.newState==="open":Boolean(_o.open)}))}_handleScroll($o){const ap=this._findHidElement($o.target);if(!ap||!this._hasEvent(ap,"scroll"))return;if(!this._applyModifiers($o,ap,"scroll")){return}const bp=ap.dataset.hid;if(this._isThrottled(ap,"scroll")){return}this.client.sendEvent(R.SCROLL,bp,{scrollTop:ap.scrollTop,scrollLeft:ap.scrollLeft})}_handleLinkClick(cp){const dp=this._closest(cp.target,"a[href]");if(!dp)return;if(cp.ctrlKey||cp.metaKey||cp.shiftKey||cp.altKey){return}if(dp.hasAttribute("download")){return}const ep=dp.getAttribute("target");if(ep&&ep!=="_self"){return}const fp=dp.hasAttribute("data-vango-link")||dp.hasAttribute("data-link");if(!fp){return}const gp=dp.getAttribute("href");if(!gp)return;if(gp.startsWith("http://")||gp.startsWith("https://")||gp.startsWith("//")){try{const hp=new URL(gp,window.location.origin);if(hp.origin!==window.location.origin){return}}catch(_e2){return}}if(dp.hasAttribute("data-external")){return}if(!this.client.connected){return}cp.preventDefault();this.pendingNavPath=gp;this.client.sendEvent(R.NAVIGATE,"nav",{path:gp,replace:false})}_handlePopState(ip){if(!this.client.connected){return}const jp=location.pathname+location.search;this.pendingNavPath=jp;this.client.sendEvent(R.NAVIGATE,"nav",{path:jp,replace:true})}_handlePrefetch(kp){const lp=this._closest(kp.target,"a[data-prefetch][href]");if(!lp)return;const mp=lp.getAttribute("href");if(!mp)return;if(mp.startsWith("http://")||mp.startsWith("https://")||mp.startsWith("//")){try{const rp=new URL(mp,window.location.origin);if(rp.origin!==window.location.origin){return}}catch(_e3){return}}if(this.prefetchedPaths.has(mp)){return}if(!this.client.connected){return}this.prefetchedPaths.add(mp);const np=JSON.stringify({path:mp});const pp=new TextEncoder;const qp=pp.encode(np);this.client.sendEvent(R.CUSTOM,"prefetch",{name:"prefetch",data:qp});if(this.client.options.debug){console.log("[Vango] Prefetching:",mp)}}_matchesKeyFilter(sp,tp){const vp=tp.split("+");const wp=vp.pop().toLowerCase();const xp=new Set(vp.map(Cp=>Cp.toLowerCase()));if(sp.key.toLowerCase()!==wp){return false}const yp=xp.has("ctrl")||xp.has("control");const zp=xp.has("shift");const Ap=xp.has("alt");const Bp=xp.has("meta")||xp.has("cmd");if(yp!==sp.ctrlKey)return false;if(zp!==sp.shiftKey)return false;if(Ap!==sp.altKey)return false;if(Bp!==sp.metaKey)return false;return true}}const Ja=new WeakMap;function Ka(Dp){let Ep=null;if(Dp){try{Ep=document.querySelector(Dp)}catch(e){console.warn("[Vango] Invalid portal target:",Dp)}}return Ep||document.body}function La(Fp){const Gp=document.createElement("template");Gp.dataset.portalAnchor=Fp.dataset.hid||"";Ja.set(Fp,Gp);return Gp}function Ma(Hp){return Ja.get(Hp)||null}function Na(Ip,Jp){const Kp=[];Ip.querySelectorAll("template[data-portal-anchor]").forEach(Lp=>{const Mp=Jp(Lp.dataset.portalAnchor);if(Mp&&Ja.get(Mp)===Lp){Kp.push(Mp)}});return Kp}function Oa(Np=document){Np.querySelectorAll("vango-portal[data-portal-target]").forEach(Op=>{var Pp;if(!Op.dataset.hid||Ma(Op))return;(Pp=Op.parentNode)==null?void 0:Pp.insertBefore(La(Op),Op);Ka(Op.dataset.portalTarget).appendChild(Op)})}const Pa=new WeakMap;const Qa=new WeakSet;function Ra(Qp){if((Qp==null?void 0:Qp.nodeType)!==1)return null;if(Qp.hasAttribute("data-transition"))return Qp;const Rp=Qp.parentElement;if(Rp==null?void 0:Rp.hasAttribute("data-transition"))return Rp;return null}function Sa(Sp){var Tp;return((Tp=Ra(Sp))==null?void 0:Tp.dataset.transition)||""}function Ta(Up){return Qa.has(Up)}function Ua(Vp){return Array.from(Vp.children).filter(Wp=>!Qa.has(Wp))}function Va(Xp){if(typeof requestAnimationFrame==="function"){requestAnimationFrame(Xp)}else{setTimeout(Xp,0)}}function Wa(Yp){return(Yp||"").split(",").map(Zp=>{const _p=Zp.trim();const $p=parseFloat(_p);if(Number.isNaN($p))return 0;return _p.endsWith("ms")?$p:$p*1000})}function Xa(aq,bq){const cq=Wa(aq);const dq=Wa(bq);return cq.reduce((eq,fq,gq)=>Math.max(eq,fq+(dq[gq%dq.length]||0)),0)}function Ya(hq){var iq;const jq=(iq=Ra(hq))==null?void 0:iq.dataset.transitionTimeout;if(jq!==undefined&&jq!==""){return Math.max(0,parseInt(jq,10)||0)}if(typeof getComputedStyle!=="function")return 0;const kq=getComputedStyle(hq);return Math.max(Xa(kq.transitionDuration,kq.transitionDelay),Xa(kq.animationDuration,kq.animationDelay))}function Za(lq,mq){let nq=false;let oq=null;const pq=()=>{if(nq)return;nq=true;clearTimeout(oq);lq.removeEventListener("transitionend",qq);lq.removeEventListener("animationend",qq);Pa.delete(lq);mq()};const qq=rq=>{if(rq.target===lq)pq()};lq.addEventListener("transitionend",qq);lq.addEventListener("animationend",qq);oq=setTimeout(pq,Ya(lq));Pa.set(lq,pq);return pq}function $a(sq,tq,uq,vq){var wq;(wq=Pa.get(sq))==null?void 0:wq();const xq=`${tq}-${uq}-from`;const yq=`${tq}-${uq}-active`;const zq=`${tq}-${uq}-to`;let Aq=false;sq.classList.add(xq,yq);Za(sq,()=>{Aq=true;sq.classList.remove(xq,yq,zq);vq==null?void 0:vq()});Va(()=>{if(Aq)return;sq.classList.remove(xq);sq.classList.add(zq)})}function ab(Bq){if((Bq==null?void 0:Bq.nodeType)!==1)return[];if(Bq.hasAttribute("data-portal-target")){return Array.from(Bq.children).filter(Cq=>Cq.hasAttribute("data-transition"))}return Sa(Bq)?[Bq]:[]}function bb(Dq){for(const Eq of ab(Dq)){$a(Eq,Sa(Eq),"enter")}}function cb(Fq,Gq){const Hq=ab(Fq);if(Hq.length===0)return false;Qa.add(Fq);Fq.setAttribute("inert","");let Iq=Hq.length;for(const Jq of Hq){$a(Jq,Sa(Jq),"leave",()=>{if(--Iq===0){Qa.delete(Fq);Gq()}})}return true}function db(Kq,Lq){var Mq;if(!Lq||!(Kq==null||(Mq=Kq.hasAttribute)==null?void 0:Mq.call(Kq,"data-transition"))||Lq.has(Kq))return;const Nq=new Map;for(const Oq of Ua(Kq)){Nq.set(Oq,Oq.getBoundingClientRect())}Lq.set(Kq,Nq)}function eb(Pq){var Qq;const Rq=[];for(const[Sq,Tq]of Pq){const Uq=Sq.dataset.transition;for(const Vq of Ua(Sq)){const Wq=Tq.get(Vq);if(!Wq)continue;const Xq=Vq.getBoundingClientRect();const Yq=Wq.left-Xq.left;const Zq=Wq.top-Xq.top;if(Yq||Zq){Rq.push({child:Vq,name:Uq,dx:Yq,dy:Zq})}}}if(Rq.length===0)return;for(const{child:_q,dx:$q,dy:ar}of Rq){(Qq=Pa.get(_q))==null?void 0:Qq();_q.style.transform=`translate(${$q}px, ${ar}px)`;_q.style.transitionDuration="0s"}void document.body.offsetHeight;for(const{child:br,name:cr}of Rq){const dr=`${cr}-move`;br.classList.add(dr);br.style.transform="";br.style.transitionDuration="";Za(br,()=>br.classList.remove(dr))}}class fb{constructor(er){this.client=er}apply(fr){this._positions=new Map;try{for(const gr of fr){this.applyPatch(gr)}eb(this._positions)}finally{this._positions=null}}_requiresTargetNode(hr){switch(hr){case S.URL_PUSH:case S.URL_REPLACE:case S.NAV_PUSH:case S.NAV_REPLACE:return false;case S.SET_TITLE:case S.SET_META:case S.SET_LINK:return false;case S.ISLAND_MESSAGE:return false;case S.INSERT_NODE:return false;default:return true}}_triggerSelfHeal(){var ir;const jr=(ir=this.client.eventCapture)==null?void 0:ir.pendingNavPath;if(jr){console.log("[Vango] Self-heal: navigating to pending path:",jr);location.assign(jr)}else{console.log("[Vango] Self-heal: reloading page");location.reload()}}applyPatch(kr){var lr;const mr=this.client.getNode(kr.hid);if(!mr&&this._requiresTargetNode(kr.type)){console.error("[Vango] Patch target not found (HID:",kr.hid,"type:",kr.type,")");this._triggerSelfHeal();return}switch(kr.type){case S.SET_TEXT:this._setText(mr,kr.value);break;case S.SET_ATTR:this._setAttr(mr,kr.key,kr.value);break;case S.REMOVE_ATTR:this._removeAttr(mr,kr.key);break;case S.ADD_CLASS:mr.classList.add(kr.className);break;case S.REMOVE_CLASS:mr.classList.remove(kr.className);break;case S.TOGGLE_CLASS:mr.classList.toggle(kr.className);break;case S.SET_STYLE:mr.style[kr.key]=kr.value;break;case S.REMOVE_STYLE:mr.style[kr.key]="";break;case S.INSERT_NODE:this._insertNode(kr.parentID,kr.index,kr.vnode);break;case S.REMOVE_NODE:this._removeNode(mr,kr.hid);break;case S.MOVE_NODE:this._moveNode(mr,kr.parentID,kr.index);break;case S.REPLACE_NODE:this._replaceNode(mr,kr.hid,kr.vnode);break;case S.SET_VALUE:this._setValue(mr,kr.value);break;case S.SET_CHECKED:mr.checked=kr.value;break;case S.SET_SELECTED:mr.selected=kr.value;break;case S.FOCUS:mr.focus();break;case S.BLUR:mr.blur();break;case S.SCROLL_TO:mr.scrollTo({left:kr.x,top:kr.y,behavior:kr.behavior===1?"smooth":"instant"});break;case S.SET_DATA:mr.dataset[kr.key]=kr.value;break;case S.DISPATCH:this._dispatchEvent(mr,kr.eventName,kr.detail);break;case S.URL_PUSH:case S.URL_REPLACE:if(this.client.urlManager){this.client.urlManager.applyPatch(kr)}break;case S.NAV_PUSH:case S.NAV_REPLACE:this._applyNavPatch(kr);break;case S.SET_TITLE:document.title=kr.value;break;case S.SET_META:this._setHeadTag("meta",kr.attr,kr.key,"content",kr.value);break;case S.SET_LINK:this._setHeadTag("link","rel",kr.key,"href",kr.value);break;case S.ISLAND_MESSAGE:(lr=this.client.islands)==null?void 0:lr.deliver(kr.hid,kr.data||{});break;default:if(this.client.options.debug){console.warn("[Vango] Unknown patch type:",kr.type)}}}_setText(nr,or){nr.textContent=or}_setAttr(pr,qr,rr){if(qr.length>2&&qr.substring(0,2).toLowerCase()==="on"){console.warn("[Vango] Blocked dangerous attribute:",qr);return}switch(qr){case"class":pr.className=rr;break;case"for":pr.htmlFor=rr;break;case"value":this._setValue(pr,rr);break;case"checked":pr.checked=rr==="true"||rr==="";break;case"selected":pr.selected=rr==="true"||rr==="";break;case"disabled":case"readonly":case"required":case"multiple":case"autofocus":if(rr==="true"||rr===""){pr.setAttribute(qr,"")}else{pr.removeAttribute(qr)}break;default:pr.setAttribute(qr,rr)}}_removeAttr(sr,tr){switch(tr){case"class":sr.className="";break;case"for":sr.htmlFor="";break;case"value":sr.value="";break;case"checked":sr.checked=false;break;case"selected":sr.selected=false;break;default:sr.removeAttribute(tr)}}_setValue(ur,vr){if(ur.value===vr)return;if(ur.type==="text"||ur.tagName==="TEXTAREA"){const wr=ur.selectionStart;const xr=ur.selectionEnd;ur.value=vr;if(document.activeElement===ur){ur.setSelectionRange(Math.min(wr,vr.length),Math.min(xr,vr.length))}}else{ur.value=vr}}_insertNode(yr,zr,Ar){const Br=this.client.getNode(yr);if(!Br){console.error("[Vango] INSERT_NODE parent not found (parentHID:",yr,")");this._triggerSelfHeal();return}db(Br,this._positions);const Cr=this._createNode(Ar);Br.insertBefore(Cr,this._childAt(Br,zr));bb(Ar.type==="portal"?this.client.getNode(Ar.hid):Cr)}_childAt(Dr,Er){return Ua(Dr)[Er]||null}_removeNode(Fr,Gr){var Hr;this._cleanupNode(Fr,Gr);db(Fr.parentElement,this._positions);(Hr=Ma(Fr))==null?void 0:Hr.remove();if(!cb(Fr,()=>Fr.remove())){Fr.remove()}}_cleanupNode(Ir,Jr){this.client.hooks.destroyForNode(Ir);this.client.unregisterNode(Jr);Ir.querySelectorAll("[data-hid]").forEach(Kr=>{this.client.hooks.destroyForNode(Kr);this.client.unregisterNode(Kr.dataset.hid)});for(const Lr of Na(Ir,Mr=>this.client.getNode(Mr))){this._removeNode(Lr,Lr.dataset.hid)}}_moveNode(Nr,Or,Pr){const Qr=this.client.getNode(Or);if(!Qr){console.error("[Vango] MOVE_NODE parent not found (parentHID:",Or,")");this._triggerSelfHeal();return}db(Qr,this._positions);const Rr=Ma(Nr)||Nr;Qr.insertBefore(Rr,this._childAt(Qr,Pr))}_replaceNode(Sr,Tr,Ur){this._cleanupNode(Sr,Tr);const Vr=this._createNode(Ur);const Wr=Ma(Sr);if(Wr){Wr.replaceWith(Vr);Sr.remove()}else{Sr.replaceWith(Vr)}bb(Ur.type==="portal"?this.client.getNode(Ur.hid):Vr)}_createNode(Xr){switch(Xr.type){case"element":return this._createElement(Xr);case"text":return document.createTextNode(Xr.text);case"fragment":return this._createFragment(Xr);case"portal":return this._createPortal(Xr);default:if(this.client.options.debug){console.warn("[Vango] Unknown vnode type:",Xr.type)}return document.createTextNode("")}}_createElement(Yr){const Zr=document.createElement(Yr.tag);for(const[_r,$r]of Object.entries(Yr.attrs||{})){this._setAttr(Zr,_r,$r)}if(Yr.hid){Zr.dataset.hid=Yr.hid;this.client.registerNode(Yr.hid,Zr)}for(const as of Yr.children||[]){Zr.appendChild(this._createNode(as))}this.client.hooks.initializeForNode(Zr);return Zr}_createPortal(bs){const cs=document.createElement("vango-portal");cs.dataset.portalTarget=bs.target;cs.style.display="contents";if(bs.hid){cs.dataset.hid=bs.hid;this.client.registerNode(bs.hid,cs)}for(const es of bs.children||[]){cs.appendChild(this._createNode(es))}const ds=La(cs);Ka(bs.target).appendChild(cs);return ds}_createFragment(fs){const gs=document.createDocumentFragment();for(const is of fs.children||[]){gs.appendChild(this._createNode(is))}return gs}_dispatchEvent(js,ks,ls){let ns=null;if(ls){try{ns=JSON.parse(ls)}catch(e){ns=ls}}if(ks==="vango:navigate"){this._handleNavigate(ns);return}if(ks==="vango:hash"){this._handleHash(ns);return}const os=js||document;const ps=new CustomEvent(ks,{detail:ns,bubbles:true,cancelable:true});os.dispatchEvent(ps)}_setHeadTag(qs,rs,ss,us,vs){const xs=document.head;if(!xs||!rs||!ss)return;let ys=null;for(const zs of xs.querySelectorAll(qs)){if(zs.getAttribute(rs)===ss){ys=zs;break}}if(!vs){if(ys)ys.remove();return}if(!ys){ys=document.createElement(qs);ys.setAttribute(rs,ss);xs.appendChild(ys)}ys.setAttribute(us,vs)}_applyNavPatch(As){const{path:Bs,type:Cs}=As;if(!Bs||!Bs.startsWith("/")){console.error("[Vango] Invalid navigation path (must start with /):",Bs);return}if(Bs.includes("://")||Bs.startsWith("//")){console.error("[Vango] Invalid navigation path (must be relative):",Bs);return}if(Cs===S.NAV_REPLACE){history.replaceState(null,"",Bs)}else{history.pushState(null,"",Bs)}if(this.client.eventCapture){this.client.eventCapture.pendingNavPath=null}window.scrollTo({top:0,behavior:"instant"});if(this.client.options.debug){console.log("[Vango] Navigation applied:",Bs,Cs===S.NAV_REPLACE?"(replace)":"(push)")}}_handleNavigate(Ds){if(!Ds||!Ds.path){if(this.client.options.debug){console.warn("[Vango] Invalid navigate data:",Ds)}return}const{path:Es,replace:Fs,scroll:Gs}=Ds;if(Fs){history.replaceState(null,"",Es)}else{history.pushState(null,"",Es)}this.client.sendEvent(R.NAVIGATE,"nav",{path:Es});if(Gs!==false){window.scrollTo({top:0,behavior:"instant"})}if(this.client.options.debug){console.log("[Vango] Navigated to:",Es,{replace:Fs,scroll:Gs})}}_handleHash(Hs){if(!Hs||typeof Hs.value!=="string"){if(this.client.options.debug){console.warn("[Vango] Invalid hash data:",Hs)}return}const Is=!!Hs.replace;const Js=new URL(window.location);if(Hs.value===""){Js.hash=""}else if(Hs.value.startsWith("#")){Js.hash=Hs.value}else{Js.hash=`#${Hs.value}`}if(Js.toString()===window.location.href){return}if(Is){history.replaceState(null,"",Js.toString())}else{history.pushState(null,"",Js.toString())}}}class gb{constructor(Ks){this.client=Ks;this.pending=new Map}applyOptimistic(Ls,Ms){const Ns=Ls.dataset.hid;if(!Ns)return;const Os=Ls.dataset.optimistic;if(!Os)return;try{const Ps=JSON.parse(Os);if(Ps.class){this._applyClassOptimistic(Ls,Ns,Ps.class)}if(Ps.text){this._applyTextOptimistic(Ls,Ns,Ps.text)}if(Ps.attr&&Ps.value!==undefined){this._applyAttrOptimistic(Ls,Ns,Ps.attr,Ps.value)}}catch(e){console.warn("[Vango] Invalid optimistic config:",e)}}_applyClassOptimistic(Qs,Rs,Ss){const[Ts,Us="toggle"]=Ss.split(":");const Vs=Qs.classList.contains(Ts);switch(Us){case"add":Qs.classList.add(Ts);break;case"remove":Qs.classList.remove(Ts);break;case"toggle":default:Qs.classList.toggle(Ts)}this._trackPending(Rs,"class",Ts,Vs)}_applyTextOptimistic(Ws,Xs,Ys){const Zs=Ws.textContent;Ws.textContent=Ys;this._trackPending(Xs,"text",null,Zs)}_applyAttrOptimistic(_s,$s,at,bt){if(at.length>2&&at.substring(0,2).toLowerCase()==="on"){console.warn("[Vango] Blocked dangerous optimistic attribute:",at);return}const ct=_s.getAttribute(at);if(bt==="null"||bt===""){_s.removeAttribute(at)}else{_s.setAttribute(at,bt)}this._trackPending($s,"attr",at,ct)}_trackPending(et,ft,gt,ht){if(!this.pending.has(et)){this.pending.set(et,[])}this.pending.get(et).push({type:ft,key:gt,original:ht})}clearPending(){this.pending.clear()}revertAll(){for(const[it,jt]of this.pending){const kt=this.client.getNode(it);if(!kt)continue;for(const lt of jt){this._revertUpdate(kt,lt)}}this.pending.clear()}_revertUpdate(mt,nt){switch(nt.type){case"class":if(nt.original){mt.classList.add(nt.key)}else{mt.classList.remove(nt.key)}break;case"text":mt.textContent=nt.original;break;case"attr":if(nt.original===null){mt.removeAttribute(nt.key)}else{mt.setAttribute(nt.key,nt.original)}break}}}class hb{mounted(ot,pt,qt){this.el=ot;this.config=pt;this.pushEvent=qt;this.animation=pt.animation||150;this.handle=pt.handle||null;this.ghostClass=pt.ghostClass||"sortable-ghost";this.dragClass=pt.dragClass||"sortable-drag";this.dragging=null;this.ghost=null;this.startIndex=-1;this._bindEvents()}updated(rt,st,tt){this.config=st;this.pushEvent=tt}destroyed(ut){this._unbindEvents()}_bindEvents(){this._onMouseDown=this._handleMouseDown.bind(this);this._onMouseMove=this._handleMouseMove.bind(this);this._onMouseUp=this._handleMouseUp.bind(this);this.el.addEventListener("mousedown",this._onMouseDown);document.addEventListener("mousemove",this._onMouseMove);document.addEventListener("mouseup",this._onMouseUp);this._onTouchStart=this._handleTouchStart.bind(this);this._onTouchMove=this._handleTouchMove.bind(this);this._onTouchEnd=this._handleTouchEnd.bind(this);this.el.addEventListener("touchstart",this._onTouchStart,{passive:false});document.addEventListener("touchmove",this._onTouchMove,{passive:false});document.addEventListener("touchend",this._onTouchEnd)}_unbindEvents(){this.el.removeEventListener("mousedown",this._onMouseDown);document.removeEventListener("mousemove",this._onMouseMove);document.removeEventListener("mouseup",this._onMouseUp);this.el.removeEventListener("touchstart",this._onTouchStart);document.removeEventListener("touchmove",this._onTouchMove);document.removeEventListener("touchend",this._onTouchEnd)}_handleMouseDown(vt){var wt;const xt=this._findItem(vt.target);if(!xt)return;if(this.handle&&(typeof((wt=vt.target)==null?void 0:wt.closest)!=="function"||!vt.target.closest(this.handle)))return;vt.preventDefault();this._startDrag(xt,vt.clientX,vt.clientY)}_handleTouchStart(yt){var zt;const At=this._findItem(yt.target);if(!At)return;if(this.handle&&(typeof((zt=yt.target)==null?void 0:zt.closest)!=="function"||!yt.target.closest(this.handle)))return;yt.preventDefault();const Bt=yt.touches[0];this._startDrag(At,Bt.clientX,Bt.clientY)}_findItem(Ct){let Dt=Ct;while(Dt&&Dt.parentElement!==this.el){Dt=Dt.parentElement}return Dt}_startDrag(Et,Ft,Gt){this.dragging=Et;this.activeContainer=Et.parentElement;this.initialContainer=this.activeContainer;this.startIndex=Array.from(this.activeContainer.children).indexOf(Et);this.startY=Gt;this.startX=Ft;this.itemHeight=Et.offsetHeight;const Ht=Et.getBoundingClientRect();this.ghostStartTop=Ht.top;this.ghostStartLeft=Ht.left;this.ghost=Et.cloneNode(true);this.ghost.classList.add(this.ghostClass);this.ghost.style.position="fixed";this.ghost.style.zIndex="9999";this.ghost.style.width=`${Et.offsetWidth}px`;this.ghost.style.height=`${Et.offsetHeight}px`;this.ghost.style.left=`${this.ghostStartLeft}px`;this.ghost.style.top=`${this.ghostStartTop}px`;this.ghost.style.pointerEvents="none";this.ghost.style.opacity="0.8";this.ghost.style.transition="none";document.body.appendChild(this.ghost);Et.classList.add(this.dragClass);Et.style.opacity="0.4"}_handleMouseMove(It){if(!this.dragging)return;It.preventDefault();this._updateDrag(It.clientX,It.clientY)}_handleTouchMove(Jt){if(!this.dragging)return;Jt.preventDefault();const Kt=Jt.touches[0];this._updateDrag(Kt.clientX,Kt.clientY)}_updateDrag(Lt,Mt){const Nt=Mt-this.startY;const Ot=Lt-this.startX;if(this.ghost){this.ghost.style.top=`${this.ghostStartTop+Nt}px`;this.ghost.style.left=`${this.ghostStartLeft+Ot}px`}this.ghost.style.display="none";const Pt=document.elementFromPoint(Lt,Mt);this.ghost.style.display="";if(Pt){const St=Pt.closest("[data-hook=\"Sortable\"]");if(St&&St!==this.activeContainer&&St.dataset.hookConfig){try{const Tt=JSON.parse(St.dataset.hookConfig);if(Tt.group===this.config.group){this.activeContainer=St;this.activeContainer.appendChild(this.dragging)}}catch(e){}}}const Qt=Array.from(this.activeContainer.children);const Rt=Qt.indexOf(this.dragging);for(let Ut=0;Ut<Qt.length;Ut++){if(Ut===Rt)continue;const Vt=Qt[Ut];const Wt=Vt.getBoundingClientRect();const Xt=Wt.top+Wt.height/2;if(Mt<Xt&&Ut<Rt){this.activeContainer.insertBefore(this.dragging,Vt);break}else if(Mt>Xt&&Ut>Rt){this.activeContainer.insertBefore(this.dragging,Vt.nextSibling);break}}}_handleMouseUp(Yt){if(!this.dragging)return;this._endDrag()}_handleTouchEnd(Zt){if(!this.dragging)return;this._endDrag()}_endDrag(){const _t=Array.from(this.activeContainer.children).indexOf(this.dragging);const $t=this.dragging.dataset.id||this.dragging.dataset.itemId||this.dragging.dataset.hid;const au=this.initialContainer.dataset.id||this.initialContainer.dataset.listId||this.initialContainer.dataset.containerId||this.initialContainer.dataset.hid;const bu=this.activeContainer.dataset.id||this.activeContainer.dataset.listId||this.activeContainer.dataset.containerId||this.activeContainer.dataset.hid;const cu=this.initialContainer;const du=this.startIndex;const eu=this.dragging;this.dragging.classList.remove(this.dragClass);this.dragging.style.opacity="";if(this.ghost){this.ghost.remove()}if(this.activeContainer!==this.initialContainer||_t!==this.startIndex){const fu=()=>{if(!cu||!eu)return;const gu=Array.from(cu.children);const hu=gu[du]||null;cu.insertBefore(eu,hu)};this.pushEvent("reorder",{id:$t,itemId:$t,fromContainerId:au,toContainerId:bu,fromIndex:this.startIndex,toIndex:_t},fu)}this.dragging=null;this.ghost=null;this.activeContainer=null;this.initialContainer=null}}class ib{mounted(iu,ju,ku){this.el=iu;this.config=ju;this.pushEvent=ku;this.axis=ju.axis||"both";this.handle=ju.handle||null;this.bounds=ju.bounds||null;this.dragging=false;this.startX=0;this.startY=0;this.currentX=0;this.currentY=0;this._bindEvents()}updated(lu,mu,nu){this.config=mu;this.pushEvent=nu;this.axis=mu.axis||"both";this.bounds=mu.bounds||null}destroyed(ou){this._unbindEvents()}_bindEvents(){this._onMouseDown=this._handleMouseDown.bind(this);this._onMouseMove=this._handleMouseMove.bind(this);this._onMouseUp=this._handleMouseUp.bind(this);const pu=this.handle?this.el.querySelector(this.handle):this.el;if(pu){pu.addEventListener("mousedown",this._onMouseDown)}document.addEventListener("mousemove",this._onMouseMove);document.addEventListener("mouseup",this._onMouseUp);this._onTouchStart=this._handleTouchStart.bind(this);this._onTouchMove=this._handleTouchMove.bind(this);this._onTouchEnd=this._handleTouchEnd.bind(this);if(pu){pu.addEventListener("touchstart",this._onTouchStart,{passive:false})}document.addEventListener("touchmove",this._onTouchMove,{passive:false});document.addEventListener("touchend",this._onTouchEnd)}_unbindEvents(){const qu=this.handle?this.el.querySelector(this.handle):this.el;if(qu){qu.removeEventListener("mousedown",this._onMouseDown);qu.removeEventListener("touchstart",this._onTouchStart)}document.removeEventListener("mousemove",this._onMouseMove);document.removeEventListener("mouseup",this._onMouseUp);document.removeEventListener("touchmove",this._onTouchMove);document.removeEventListener("touchend",this._onTouchEnd)}_handleMouseDown(ru){ru.preventDefault();this._startDrag(ru.clientX,ru.clientY)}_handleTouchStart(su){su.preventDefault();const tu=su.touches[0];this._startDrag(tu.clientX,tu.clientY)}_startDrag(uu,vu){this.dragging=true;this.startX=uu-this.currentX;this.startY=vu-this.currentY;this.el.classList.add("dragging")}_handleMouseMove(wu){if(!this.dragging)return;wu.preventDefault();this._updatePosition(wu.clientX,wu.clientY)}_handleTouchMove(xu){if(!this.dragging)return;xu.preventDefault();const yu=xu.touches[0];this._updatePosition(yu.clientX,yu.clientY)}_updatePosition(zu,Au){let Bu=zu-this.startX;let Cu=Au-this.startY;if(this.axis==="x"){Cu=this.currentY}else if(this.axis==="y"){Bu=this.currentX}if(this.bounds){const Du=this._getBounds();Bu=Math.max(Du.minX,Math.min(Du.maxX,Bu));Cu=Math.max(Du.minY,Math.min(Du.maxY,Cu))}this.currentX=Bu;this.currentY=Cu;this.el.style.transform=`translate(${Bu}px, ${Cu}px)`}_getBounds(){const Eu=this.el.getBoundingClientRect();let Fu;if(this.bounds==="parent"){Fu=this.el.parentElement.getBoundingClientRect()}else{Fu={left:0,top:0,right:window.innerWidth,bottom:window.innerHeight}}return{minX:Fu.left-Eu.left+this.currentX,maxX:Fu.right-Eu.right+this.currentX,minY:Fu.top-Eu.top+this.currentY,maxY:Fu.bottom-Eu.bottom+this.currentY}}_handleMouseUp(Gu){if(!this.dragging)return;this._endDrag()}_handleTouchEnd(Hu){if(!this.dragging)return;this._endDrag()}_endDrag(){this.dragging=false;this.el.classList.remove("dragging");this.pushEvent("position",{x:this.currentX,y:this.currentY})}}class jb{mounted(Iu,Ju,Ku){this.el=Iu;this.config=Ju;this.pushEvent=Ku;this.hoverClass=Ju.hoverClass||"drag-over";this._bind()}updated(Lu,Mu,Nu){this.config=Mu;this.pushEvent=Nu;this.hoverClass=Mu.hoverClass||"drag-over"}destroyed(){this._unbind()}_bind(){this._enter=Ou=>{Ou.preventDefault();this.el.classList.add(this.hoverClass)};this._over=Pu=>{Pu.preventDefault();Pu.dataTransfer.dropEffect="move"};this._leave=Qu=>{if(!this.el.contains(Qu.relatedTarget))this.el.classList.remove(this.hoverClass)};this._drop=Ru=>{var Su,Tu;Ru.preventDefault();this.el.classList.remove(this.hoverClass);const Uu={x:Ru.clientX,y:Ru.clientY};if(window.__vango_dragging__){const Xu=window.__vango_dragging__;Uu.id=Xu.dataset.id||Xu.dataset.hid;window.__vango_dragging__=null}const Vu=(Su=Ru.dataTransfer)==null?void 0:Su.files;if(Vu==null?void 0:Vu.length)Uu.fileNames=Array.from(Vu).map(Yu=>Yu.name);const Wu=(Tu=Ru.dataTransfer)==null?void 0:Tu.getData("text/plain");if(Wu)Uu.text=Wu;this.pushEvent("drop",Uu)};this.el.addEventListener("dragenter",this._enter);this.el.addEventListener("dragover",this._over);this.el.addEventListener("dragleave",this._leave);this.el.addEventListener("drop",this._drop)}_unbind(){this.el.removeEventListener("dragenter",this._enter);this.el.removeEventListener("dragover",this._over);this.el.removeEventListener("dragleave",this._leave);this.el.removeEventListener("drop",this._drop)}}class kb{mounted(Zu,_u,$u){this.el=Zu;this.config=_u;this.pushEvent=$u;this.handles=(_u.handles||"se").split(",").map(av=>av.trim());this.resizing=false;this._handleEls=[];this._createHandles();this._bind()}updated(bv,cv,dv){this.config=cv;this.pushEvent=dv}destroyed(){this._unbind();this._handleEls.forEach(ev=>ev.remove())}_createHandles(){if(getComputedStyle(this.el).position==="static"){this.el.style.position="relative"}const fv={n:"n",s:"s",e:"e",w:"w",ne:"ne",se:"se",sw:"sw",nw:"nw"};for(const gv of this.handles){if(!fv[gv])continue;const hv=document.createElement("div");hv.dataset.handle=gv;hv.style.cssText=`position:absolute;z-index:10;cursor:${gv}-resize;`+this._pos(gv);this.el.appendChild(hv);this._handleEls.push(hv)}}_pos(iv){const jv=8,kv=4;const lv={n:`top:-${kv}px;left:25%;width:50%;height:${jv}px`,s:`bottom:-${kv}px;left:25%;width:50%;height:${jv}px`,e:`right:-${kv}px;top:25%;width:${jv}px;height:50%`,w:`left:-${kv}px;top:25%;width:${jv}px;height:50%`,ne:`top:-${kv}px;right:-${kv}px;width:${jv}px;height:${jv}px`,se:`bottom:-${kv}px;right:-${kv}px;width:${jv}px;height:${jv}px`,sw:`bottom:-${kv}px;left:-${kv}px;width:${jv}px;height:${jv}px`,nw:`top:-${kv}px;left:-${kv}px;width:${jv}px;height:${jv}px`};return lv[iv]||""}_bind(){this._onDown=mv=>{mv.preventDefault();this._start(mv.target.dataset.handle,mv.clientX,mv.clientY)};this._onMove=nv=>{if(this.resizing){nv.preventDefault();this._update(nv.clientX,nv.clientY)}};this._onUp=()=>{if(this.resizing)this._end()};this._handleEls.forEach(ov=>ov.addEventListener("mousedown",this._onDown));document.addEventListener("mousemove",this._onMove);document.addEventListener("mouseup",this._onUp)}_unbind(){this._handleEls.forEach(pv=>pv.removeEventListener("mousedown",this._onDown));document.removeEventListener("mousemove",this._onMove);document.removeEventListener("mouseup",this._onUp)}_start(qv,rv,sv){this.resizing=true;this._h=qv;this._sx=rv;this._sy=sv;const tv=this.el.getBoundingClientRect();this._sw=tv.width;this._sh=tv.height}_update(uv,vv){const wv=uv-this._sx,xv=vv-this._sy;let yv=this._sw,zv=this._sh;if(this._h.includes("e"))yv=this._sw+wv;if(this._h.includes("w"))yv=this._sw-wv;if(this._h.includes("s"))zv=this._sh+xv;if(this._h.includes("n"))zv=this._sh-xv;const Av=this.config;yv=Math.max(Av.minWidth||0,Math.min(Av.maxWidth||Infinity,yv));zv=Math.max(Av.minHeight||0,Math.min(Av.maxHeight||Infinity,zv));this.el.style.width=yv+"px";this.el.style.height=zv+"px"}_end(){this.resizing=false;const Bv=this.el.getBoundingClientRect();this.pushEvent("resize",{width:Math.round(Bv.width),height:Math.round(Bv.height)})}}class lb{mounted(Cv,Dv,Ev){this.el=Cv;this.config=Dv;this.tooltip=null;this.content=Dv.content||"";this.placement=Dv.placement||"top";this.delay=Dv.delay||200;this._bindEvents()}updated(Fv,Gv,Hv){this.content=Gv.content||"";this.placement=Gv.placement||"top";if(this.tooltip){this.tooltip.textContent=this.content}}destroyed(Iv){this._unbindEvents();this._hideTooltip()}_bindEvents(){this._onMouseEnter=this._handleMouseEnter.bind(this);this._onMouseLeave=this._handleMouseLeave.bind(this);this.el.addEventListener("mouseenter",this._onMouseEnter);this.el.addEventListener("mouseleave",this._onMouseLeave)}_unbindEvents(){this.el.removeEventListener("mouseenter",this._onMouseEnter);this.el.removeEventListener("mouseleave",this._onMouseLeave)}_handleMouseEnter(){this._showTimer=setTimeout(()=>{this._showTooltip()},this.delay)}_handleMouseLeave(){clearTimeout(this._showTimer);this._hideTooltip()}_showTooltip(){if(!this.content)return;this.tooltip=document.createElement("div");this.tooltip.className="vango-tooltip";this.tooltip.textContent=this.content;this.tooltip.style.cssText=`
             position: fixed;
             z-index: 10000;
             padding: 4px 8px;
@@ -8,7 +8,7 @@ var Vango=(()=>{var G=Object.defineProperty;var re=Object.getOwnPropertyDescript
             font-size: 12px;
             pointer-events: none;
             white-space: nowrap;
-        `,document.body.appendChild(this.tooltip),this._position())}_position(){if(!this.tooltip)return;let e=this.el.getBoundingClientRect(),t=this.tooltip.getBoundingClientRect(),s,n;switch(this.placement){case"top":s=e.top-t.height-8,n=e.left+(e.width-t.width)/2;break;case"bottom":s=e.bottom+8,n=e.left+(e.width-t.width)/2;break;case"left":s=e.top+(e.height-t.height)/2,n=e.left-t.width-8;break;case"right":s=e.top+(e.height-t.height)/2,n=e.right+8;break;default:s=e.top-t.height-8,n=e.left+(e.width-t.width)/2}this.tooltip.style.top=`${s}px`,this.tooltip.style.left=`${n}px`}_hideTooltip(){this.tooltip&&(this.tooltip.remove(),this.tooltip=null)}};var P=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.closeOnEscape=t.closeOnEscape!==!1,this.closeOnClickOutside=t.closeOnClickOutside!==!1,this._bindEvents()}updated(e,t,s){this.config=t,this.pushEvent=s}destroyed(e){this._unbindEvents()}_bindEvents(){this._onClickOutside=this._handleClickOutside.bind(this),this._onKeyDown=this._handleKeyDown.bind(this),setTimeout(()=>{document.addEventListener("click",this._onClickOutside),document.addEventListener("keydown",this._onKeyDown)},0)}_unbindEvents(){document.removeEventListener("click",this._onClickOutside),document.removeEventListener("keydown",this._onKeyDown)}_handleClickOutside(e){this.closeOnClickOutside&&(this.el.contains(e.target)||this.pushEvent("close",{}))}_handleKeyDown(e){this.closeOnEscape&&e.key==="Escape"&&(e.preventDefault(),this.pushEvent("close",{}))}};var U=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.duration=t.duration||200,this.isOpen=!!t.open,this.animating=!1,this.isOpen||(e.style.height="0",e.style.overflow="hidden")}updated(e,t,s){this.pushEvent=s,this.duration=t.duration||200;let n=!!t.open;n!==this.isOpen&&(n?this._expand():this._collapse())}destroyed(){}_expand(){if(this.animating||this.isOpen)return;this.animating=!0,this.isOpen=!0;let e=this.el;e.style.height="auto";let t=e.scrollHeight;e.style.height="0",e.style.overflow="hidden",e.offsetHeight,e.style.transition=`height ${this.duration}ms ease`,e.style.height=t+"px",setTimeout(()=>{e.style.transition="",e.style.height="auto",e.style.overflow="",this.animating=!1,this.pushEvent("toggle",{open:!0})},this.duration)}_collapse(){if(this.animating||!this.isOpen)return;this.animating=!0,this.isOpen=!1;let e=this.el;e.style.height=e.scrollHeight+"px",e.style.overflow="hidden",e.offsetHeight,e.style.transition=`height ${this.duration}ms ease`,e.style.height="0",setTimeout(()=>{e.style.transition="",this.animating=!1,this.pushEvent("toggle",{open:!1})},this.duration)}};var F=class{mounted(e,t,s){this.el=e,this.pushEvent=s,this.active=t.active!==!1,this.restoreFocusTo=document.activeElement,this._onKeyDown=this._handleKeyDown.bind(this),this.el.addEventListener("keydown",this._onKeyDown),this.active&&this._focusFirst()}updated(e,t,s){this.active=t.active!==!1,this.pushEvent=s}destroyed(e){this.el.removeEventListener("keydown",this._onKeyDown),this.restoreFocusTo&&typeof this.restoreFocusTo.focus=="function"&&this.restoreFocusTo.focus()}_handleKeyDown(e){if(!this.active||e.key!=="Tab")return;let t=this._getFocusableElements();if(t.length===0){e.preventDefault();return}let s=t[0],n=t[t.length-1];e.shiftKey?document.activeElement===s&&(e.preventDefault(),n.focus()):document.activeElement===n&&(e.preventDefault(),s.focus())}_focusFirst(){let e=this._getFocusableElements();e.length>0?e[0].focus():(this.el.setAttribute("tabindex","-1"),this.el.focus())}_getFocusableElements(){return this.el.querySelectorAll('button:not([disabled]), [href], input:not([disabled]), select:not([disabled]), textarea:not([disabled]), [tabindex]:not([tabindex="-1"])')}};var V=class{mounted(e,t,s){this.el=e,this.pushEvent=s,this.target=t.target||"body",this.placeholder=document.createComment("portal-placeholder"),e.parentNode&&e.parentNode.insertBefore(this.placeholder,e);let n=document.getElementById("vango-portal-root");n||(n=document.createElement("div"),n.id="vango-portal-root",n.style.cssText="position: relative; z-index: 9999;",document.body.appendChild(n)),n.appendChild(e)}updated(e,t,s){this.pushEvent=s}destroyed(e){this.placeholder&&this.placeholder.parentNode&&(this.placeholder.parentNode.insertBefore(this.el,this.placeholder),this.placeholder.remove())}};function ee(){if(typeof document=="undefined")return;let l=document.getElementById("vango-portal-root");return l||(l=document.createElement("div"),l.id="vango-portal-root",l.style.cssText="position: relative; z-index: 9999;",document.body.appendChild(l)),l}var H=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.previousFocus=document.activeElement,this._onKeyDown=this._handleKeyDown.bind(this),this._onClick=this._handleClick.bind(this),document.addEventListener("keydown",this._onKeyDown),t.closeOnOutside!==!1&&setTimeout(()=>{document.addEventListener("click",this._onClick)},0),this._focusFirst(),this._originalOverflow=document.body.style.overflow,document.body.style.overflow="hidden"}updated(e,t,s){this.config=t,this.pushEvent=s}destroyed(e){document.removeEventListener("keydown",this._onKeyDown),document.removeEventListener("click",this._onClick),document.body.style.overflow=this._originalOverflow||"",this.previousFocus&&typeof this.previousFocus.focus=="function"&&this.previousFocus.focus()}_handleKeyDown(e){if(e.key==="Escape"&&this.config.closeOnEscape!==!1){e.preventDefault(),this.pushEvent("close",{});return}e.key==="Tab"&&this._trapFocus(e)}_handleClick(e){this.el.contains(e.target)||this.pushEvent("close",{})}_focusFirst(){if(this.config.initialFocus){let t=this.el.querySelector(this.config.initialFocus);if(t){t.focus();return}}let e=this._getFocusableElements();e.length>0?e[0].focus():(this.el.setAttribute("tabindex","-1"),this.el.focus())}_trapFocus(e){let t=this._getFocusableElements();if(t.length===0){e.preventDefault();return}let s=t[0],n=t[t.length-1];e.shiftKey?document.activeElement===s&&(e.preventDefault(),n.focus()):document.activeElement===n&&(e.preventDefault(),s.focus())}_getFocusableElements(){return this.el.querySelectorAll('button:not([disabled]), [href], input:not([disabled]), select:not([disabled]), textarea:not([disabled]), [tabindex]:not([tabindex="-1"])')}};var B=class{mounted(e,t,s){this.el=e,this.config=t,this.pushEvent=s,this.trigger=e.querySelector("[data-popover-trigger]"),this.content=e.querySelector("[data-popover-content]"),this.content||(this.content=e),this._onKeyDown=this._handleKeyDown.bind(this),this._onClick=this._handleClick.bind(this),this._onScroll=this._handleScroll.bind(this),this._onResize=this._handleResize.bind(this),document.addEventListener("keydown",this._onKeyDown),t.closeOnOutside!==!1&&setTimeout(()=>{document.addEventListener("click",this._onClick)},0),window.addEventListener("scroll",this._onScroll,!0),window.addEventListener("resize",this._onResize),this._position()}updated(e,t,s){this.config=t,this.pushEvent=s,this._position()}destroyed(e){document.removeEventListener("keydown",this._onKeyDown),document.removeEventListener("click",this._onClick),window.removeEventListener("scroll",this._onScroll,!0),window.removeEventListener("resize",this._onResize)}_handleKeyDown(e){e.key==="Escape"&&this.config.closeOnEscape!==!1&&(e.preventDefault(),this.pushEvent("close",{}))}_handleClick(e){this.el.contains(e.target)||this.pushEvent("close",{})}_handleScroll(){this._position()}_handleResize(){this._position()}_position(){if(!this.trigger||!this.content)return;let e=this.trigger.getBoundingClientRect(),t=this.content.getBoundingClientRect(),s=this.config.offset||4,n=this.config.side||"bottom",i=this.config.align||"center",o,r;switch(n){case"top":o=e.top-t.height-s,o<0&&(o=e.bottom+s,n="bottom");break;case"bottom":o=e.bottom+s,o+t.height>window.innerHeight&&(o=e.top-t.height-s,n="top");break;case"left":r=e.left-t.width-s,r<0&&(r=e.right+s,n="right");break;case"right":r=e.right+s,r+t.width>window.innerWidth&&(r=e.left-t.width-s,n="left");break}if(n==="top"||n==="bottom"){switch(i){case"start":r=e.left;break;case"center":r=e.left+(e.width-t.width)/2;break;case"end":r=e.right-t.width;break}r<0&&(r=0),r+t.width>window.innerWidth&&(r=window.innerWidth-t.width)}if(n==="left"||n==="right"){switch(i){case"start":o=e.top;break;case"center":o=e.top+(e.height-t.height)/2;break;case"end":o=e.bottom-t.height;break}o<0&&(o=0),o+t.height>window.innerHeight&&(o=window.innerHeight-t.height)}this.content.style.position="fixed",this.content.style.top=`${o}px`,this.content.style.left=`${r}px`,this.content.dataset.side=n}};var K=class{mounted(e,t={}){let s=t&&t.storageKey?String(t.storageKey):"theme",n=()=>{let o="";try{o=localStorage.getItem(s)||""}catch(h){o=""}let r=window.matchMedia&&window.matchMedia("(prefers-color-scheme:dark)").matches,a=o==="dark"||!o&&r;document.documentElement.classList.toggle("dark",a)},i=()=>{let o=!document.documentElement.classList.contains("dark");document.documentElement.classList.toggle("dark",o);try{localStorage.setItem(s,o?"dark":"light")}catch(r){}};n(),this._onClick=o=>{o.preventDefault(),i()},e.addEventListener("click",this._onClick)}destroyed(e){this._onClick&&e.removeEventListener("click",this._onClick),this._onClick=null}};var q=class{constructor(e){this.client=e,this.instances=new Map,this.instancesByHookID=new Map,this.pendingReverts=new Map,this._hookIdCounter=0,this.hooks={Sortable:L,Draggable:N,Droppable:O,Resizable:M,Tooltip:I,Dropdown:P,Collapsible:U,FocusTrap:F,Portal:V,Dialog:H,Popover:B,ThemeToggle:K},document.addEventListener("vango:hook-revert",t=>{var n;let s=(n=t.detail)==null?void 0:n.hid;s&&this.revert(s)})}register(e,t){this.hooks[e]=t}initializeFromDOM(){document.querySelectorAll("[data-hook]").forEach(e=>{this.initializeForNode(e)})}updateFromDOM(){document.querySelectorAll("[data-hook]").forEach(e=>{let t=e.dataset.hook,s=e.dataset.hookConfig||"",n=e.dataset.hid;if(n){let a=this.instances.get(n);if(!a){this.initializeForNode(e);return}if(a.hookName!==t){this.destroyForNode(e),this.initializeForNode(e);return}if(a.hookConfigRaw!==s){let h={};try{s&&(h=JSON.parse(s))}catch(u){this.client.options.debug&&console.warn("[Vango] Invalid hook config:",u)}this.updateConfig(n,h),a.hookConfigRaw=s}return}let o=e.dataset.vangoHookId||"",r=o?this.instancesByHookID.get(o):null;if(!r){this.initializeForNode(e);return}if(r.hookName!==t){this.destroyForNode(e),this.initializeForNode(e);return}if(r.hookConfigRaw!==s){let a={};try{s&&(a=JSON.parse(s))}catch(h){this.client.options.debug&&console.warn("[Vango] Invalid hook config:",h)}if(r.instance.updated){let h=(u,p={},_=null)=>{typeof _=="function"&&this.pendingReverts.set(o,_)};r.instance.updated(r.el,a,h)}r.hookConfigRaw=s}})}initializeForNode(e){let t=e.dataset.hook;if(!t)return;let s=this.hooks[t];if(!s){this.client.options.debug&&console.warn("[Vango] Unknown hook:",t);return}let n=e.dataset.hid,i="hid",o=n;if(o){if(this.instances.has(o))return}else if(i="hookID",o=e.dataset.vangoHookId,o||(this._hookIdCounter++,o=`hk${this._hookIdCounter}`,e.dataset.vangoHookId=o),this.instancesByHookID.has(o))return;let r={},a=e.dataset.hookConfig||"";try{a&&(r=JSON.parse(a))}catch(p){this.client.options.debug&&console.warn("[Vango] Invalid hook config:",p)}let h=(p,_={},v=null)=>{if(i!=="hid"){this.client.options.debug&&console.warn("[Vango] Hook event ignored: hook element has no data-hid");return}typeof v=="function"&&this.pendingReverts.set(o,v),this.client.sendHookEvent(o,p,_)},u=new s;u.mounted(e,r,h),i==="hid"?this.instances.set(o,{hook:s,hookName:t,hookConfigRaw:a,instance:u,el:e}):this.instancesByHookID.set(o,{hook:s,hookName:t,hookConfigRaw:a,instance:u,el:e})}destroyForNode(e){var i,o;let t=(i=e.dataset)==null?void 0:i.hid;if(t){let r=this.instances.get(t);r&&(r.instance.destroyed&&r.instance.destroyed(r.el),this.instances.delete(t));return}let s=(o=e.dataset)==null?void 0:o.vangoHookId;if(!s)return;let n=this.instancesByHookID.get(s);n&&(n.instance.destroyed&&n.instance.destroyed(n.el),this.instancesByHookID.delete(s))}destroyAll(){for(let[e,t]of this.instances)t.instance.destroyed&&t.instance.destroyed(t.el);this.instances.clear();for(let[e,t]of this.instancesByHookID)t.instance.destroyed&&t.instance.destroyed(t.el);this.instancesByHookID.clear()}updateConfig(e,t){let s=this.instances.get(e);if(s&&s.instance.updated){let n=(i,o={},r=null)=>{typeof r=="function"&&this.pendingReverts.set(e,r),this.client.sendHookEvent(e,i,o)};s.instance.updated(s.el,t,n)}}revert(e){if(!e||!this.pendingReverts.has(e))return;let t=this.pendingReverts.get(e);typeof t=="function"&&t(),this.pendingReverts.delete(e)}};var g={CONNECTING:"connecting",CONNECTED:"connected",RECONNECTING:"reconnecting",DISCONNECTED:"disconnected"},te={[g.CONNECTING]:"vango-connecting",[g.CONNECTED]:"vango-connected",[g.RECONNECTING]:"vango-reconnecting",[g.DISCONNECTED]:"vango-disconnected"},E=class{constructor(e={}){this.options={toastOnReconnect:e.toastOnReconnect||!1,toastMessage:e.toastMessage||"Connection restored",toastDuration:e.toastDuration||3e3,maxRetries:e.maxRetries||10,baseDelay:e.baseDelay||1e3,maxDelay:e.maxDelay||3e4,debug:e.debug||!1,...e},this.state=g.CONNECTING,this.retryCount=0,this.previousState=null,this.disconnectReason=null,this._updateClasses()}setState(e){this.state!==e&&(this.previousState=this.state,this.state=e,e!==g.DISCONNECTED&&(this.disconnectReason=null),this._updateClasses(),this._dispatchEvent(e,this.previousState),this.options.toastOnReconnect&&e===g.CONNECTED&&this.previousState!==g.CONNECTED&&this.showToast(this.options.toastMessage),this.options.debug&&console.log(`[Vango] Connection state: ${this.previousState} -> ${e}`))}_updateClasses(){let e=document.documentElement;Object.values(te).forEach(s=>{e.classList.remove(s)});let t=te[this.state];t&&e.classList.add(t),this.state===g.DISCONNECTED&&this.disconnectReason?e.setAttribute("data-vango-disconnect-reason",this.disconnectReason):e.removeAttribute("data-vango-disconnect-reason")}_dispatchEvent(e,t){let s=new CustomEvent("vango:connection",{detail:{state:e,previousState:t},bubbles:!0});document.dispatchEvent(s)}onConnect(){this.retryCount=0,this.setState(g.CONNECTED)}onDisconnect(){this.state===g.CONNECTED&&this.setState(g.RECONNECTING)}setDisconnected(e){this.disconnectReason=e||null,this.setState(g.DISCONNECTED)}onReconnectFailed(){return this.retryCount++,this.retryCount>=this.options.maxRetries?(this.setState(g.DISCONNECTED),!1):!0}getNextRetryDelay(){return Math.min(this.options.baseDelay*Math.pow(2,this.retryCount),this.options.maxDelay)}getState(){return this.state}isConnected(){return this.state===g.CONNECTED}isDisconnected(){return this.state===g.DISCONNECTED}reset(){this.retryCount=0,this.setState(g.CONNECTED)}showToast(e,t=this.options.toastDuration){if(typeof window.__VANGO_TOAST__=="function"){window.__VANGO_TOAST__(e,t);return}let s=document.createElement("div");s.className="vango-toast",s.textContent=e,s.setAttribute("role","alert"),s.setAttribute("aria-live","polite"),Object.assign(s.style,{position:"fixed",bottom:"20px",left:"50%",transform:"translateX(-50%)",padding:"12px 24px",backgroundColor:"hsl(var(--primary, 220 13% 18%))",color:"hsl(var(--primary-foreground, 0 0% 100%))",borderRadius:"var(--radius, 6px)",boxShadow:"0 4px 12px rgba(0, 0, 0, 0.15)",zIndex:"10001",opacity:"0",transition:"opacity 0.3s ease",fontFamily:"system-ui, -apple-system, sans-serif",fontSize:"14px"}),document.body.appendChild(s),requestAnimationFrame(()=>{s.style.opacity="1"}),setTimeout(()=>{s.style.opacity="0",setTimeout(()=>{s.parentNode&&s.parentNode.removeChild(s)},300)},t)}};function se(){if(document.getElementById("vango-connection-styles"))return;let l=document.createElement("style");l.id="vango-connection-styles",l.textContent=`
+        `;document.body.appendChild(this.tooltip);this._position()}_position(){if(!this.tooltip)return;const Jv=this.el.getBoundingClientRect();const Kv=this.tooltip.getBoundingClientRect();let Lv,Mv;switch(this.placement){case"top":Lv=Jv.top-Kv.height-8;Mv=Jv.left+(Jv.width-Kv.width)/2;break;case"bottom":Lv=Jv.bottom+8;Mv=Jv.left+(Jv.width-Kv.width)/2;break;case"left":Lv=Jv.top+(Jv.height-Kv.height)/2;Mv=Jv.left-Kv.width-8;break;case"right":Lv=Jv.top+(Jv.height-Kv.height)/2;Mv=Jv.right+8;break;default:Lv=Jv.top-Kv.height-8;Mv=Jv.left+(Jv.width-Kv.width)/2}this.tooltip.style.top=`${Lv}px`;this.tooltip.style.left=`${Mv}px`}_hideTooltip(){if(this.tooltip){this.tooltip.remove();this.tooltip=null}}}class mb{mounted(Nv,Ov,Pv){this.el=Nv;this.config=Ov;this.pushEvent=Pv;this.closeOnEscape=Ov.closeOnEscape!==false;this.closeOnClickOutside=Ov.closeOnClickOutside!==false;this._bindEvents()}updated(Qv,Rv,Sv){this.config=Rv;this.pushEvent=Sv}destroyed(Tv){this._unbindEvents()}_bindEvents(){this._onClickOutside=this._handleClickOutside.bind(this);this._onKeyDown=this._handleKeyDown.bind(this);setTimeout(()=>{document.addEventListener("click",this._onClickOutside);document.addEventListener("keydown",this._onKeyDown)},0)}_unbindEvents(){document.removeEventListener("click",this._onClickOutside);document.removeEventListener("keydown",this._onKeyDown)}_handleClickOutside(Uv){if(!this.closeOnClickOutside)return;if(!this.el.contains(Uv.target)){this.pushEvent("close",{})}}_handleKeyDown(Vv){if(!this.closeOnEscape)return;if(Vv.key==="Escape"){Vv.preventDefault();this.pushEvent("close",{})}}}class nb{mounted(Wv,Xv,Yv){this.el=Wv;this.config=Xv;this.pushEvent=Yv;this.duration=Xv.duration||200;this.isOpen=!!Xv.open;this.animating=false;if(!this.isOpen){Wv.style.height="0";Wv.style.overflow="hidden"}}updated(Zv,_v,$v){this.pushEvent=$v;this.duration=_v.duration||200;const aw=!!_v.open;if(aw!==this.isOpen){aw?this._expand():this._collapse()}}destroyed(){}_expand(){if(this.animating||this.isOpen)return;this.animating=true;this.isOpen=true;const bw=this.el;bw.style.height="auto";const cw=bw.scrollHeight;bw.style.height="0";bw.style.overflow="hidden";bw.offsetHeight;bw.style.transition=`height ${this.duration}ms ease`;bw.style.height=cw+"px";setTimeout(()=>{bw.style.transition="";bw.style.height="auto";bw.style.overflow="";this.animating=false;this.pushEvent("toggle",{open:true})},this.duration)}_collapse(){if(this.animating||!this.isOpen)return;this.animating=true;this.isOpen=false;const dw=this.el;dw.style.height=dw.scrollHeight+"px";dw.style.overflow="hidden";dw.offsetHeight;dw.style.transition=`height ${this.duration}ms ease`;dw.style.height="0";setTimeout(()=>{dw.style.transition="";this.animating=false;this.pushEvent("toggle",{open:false})},this.duration)}}class ob{mounted(ew,fw,gw){this.el=ew;this.pushEvent=gw;this.active=fw.active!==false;this.restoreFocusTo=document.activeElement;this._onKeyDown=this._handleKeyDown.bind(this);this.el.addEventListener("keydown",this._onKeyDown);if(this.active){this._focusFirst()}}updated(hw,iw,jw){this.active=iw.active!==false;this.pushEvent=jw}destroyed(kw){this.el.removeEventListener("keydown",this._onKeyDown);if(this.restoreFocusTo&&typeof this.restoreFocusTo.focus==="function"){this.restoreFocusTo.focus()}}_handleKeyDown(lw){if(!this.active||lw.key!=="Tab")return;const mw=this._getFocusableElements();if(mw.length===0){lw.preventDefault();return}const ow=mw[0];const pw=mw[mw.length-1];if(lw.shiftKey){if(document.activeElement===ow){lw.preventDefault();pw.focus()}}else{if(document.activeElement===pw){lw.preventDefault();ow.focus()}}}_focusFirst(){const qw=this._getFocusableElements();if(qw.length>0){qw[0].focus()}else{this.el.setAttribute("tabindex","-1");this.el.focus()}}_getFocusableElements(){return this.el.querySelectorAll("button:not([disabled]), "+"[href], "+"input:not([disabled]), "+"select:not([disabled]), "+"textarea:not([disabled]), "+"[tabindex]:not([tabindex=\"-1\"])")}}class pb{mounted(rw,tw,uw){this.el=rw;this.pushEvent=uw;this.target=tw.target||"body";this.placeholder=document.createComment("portal-placeholder");if(rw.parentNode){rw.parentNode.insertBefore(this.placeholder,rw)}let vw=document.getElementById("vango-portal-root");if(!vw){vw=document.createElement("div");vw.id="vango-portal-root";vw.style.cssText="position: relative; z-index: 9999;";document.body.appendChild(vw)}vw.appendChild(rw)}updated(ww,xw,yw){this.pushEvent=yw}destroyed(zw){if(this.placeholder&&this.placeholder.parentNode){this.placeholder.parentNode.insertBefore(this.el,this.placeholder);this.placeholder.remove()}}}function qb(){if(typeof document==="undefined")return;let Aw=document.getElementById("vango-portal-root");if(!Aw){Aw=document.createElement("div");Aw.id="vango-portal-root";Aw.style.cssText="position: relative; z-index: 9999;";document.body.appendChild(Aw)}return Aw}class rb{mounted(Bw,Cw,Dw){this.el=Bw;this.config=Cw;this.pushEvent=Dw;this.previousFocus=document.activeElement;this._onKeyDown=this._handleKeyDown.bind(this);this._onClick=this._handleClick.bind(this);document.addEventListener("keydown",this._onKeyDown);if(Cw.closeOnOutside!==false){setTimeout(()=>{document.addEventListener("click",this._onClick)},0)}this._focusFirst();this._originalOverflow=document.body.style.overflow;document.body.style.overflow="hidden"}updated(Ew,Fw,Gw){this.config=Fw;this.pushEvent=Gw}destroyed(Hw){document.removeEventListener("keydown",this._onKeyDown);document.removeEventListener("click",this._onClick);document.body.style.overflow=this._originalOverflow||"";if(this.previousFocus&&typeof this.previousFocus.focus==="function"){this.previousFocus.focus()}}_handleKeyDown(Iw){if(Iw.key==="Escape"&&this.config.closeOnEscape!==false){Iw.preventDefault();this.pushEvent("close",{});return}if(Iw.key==="Tab"){this._trapFocus(Iw)}}_handleClick(Jw){if(!this.el.contains(Jw.target)){this.pushEvent("close",{})}}_focusFirst(){if(this.config.initialFocus){const Lw=this.el.querySelector(this.config.initialFocus);if(Lw){Lw.focus();return}}const Kw=this._getFocusableElements();if(Kw.length>0){Kw[0].focus()}else{this.el.setAttribute("tabindex","-1");this.el.focus()}}_trapFocus(Mw){const Nw=this._getFocusableElements();if(Nw.length===0){Mw.preventDefault();return}const Ow=Nw[0];const Pw=Nw[Nw.length-1];if(Mw.shiftKey){if(document.activeElement===Ow){Mw.preventDefault();Pw.focus()}}else{if(document.activeElement===Pw){Mw.preventDefault();Ow.focus()}}}_getFocusableElements(){return this.el.querySelectorAll("button:not([disabled]), "+"[href], "+"input:not([disabled]), "+"select:not([disabled]), "+"textarea:not([disabled]), "+"[tabindex]:not([tabindex=\"-1\"])")}}class sb{mounted(Qw,Rw,Sw){this.el=Qw;this.config=Rw;this.pushEvent=Sw;this.trigger=Qw.querySelector("[data-popover-trigger]");this.content=Qw.querySelector("[data-popover-content]");if(!this.content){this.content=Qw}this._onKeyDown=this._handleKeyDown.bind(this);this._onClick=this._handleClick.bind(this);this._onScroll=this._handleScroll.bind(this);this._onResize=this._handleResize.bind(this);document.addEventListener("keydown",this._onKeyDown);if(Rw.closeOnOutside!==false){setTimeout(()=>{document.addEventListener("click",this._onClick)},0)}window.addEventListener("scroll",this._onScroll,true);window.addEventListener("resize",this._onResize);this._position()}updated(Tw,Uw,Vw){this.config=Uw;this.pushEvent=Vw;this._position()}destroyed(Ww){document.removeEventListener("keydown",this._onKeyDown);document.removeEventListener("click",this._onClick);window.removeEventListener("scroll",this._onScroll,true);window.removeEventListener("resize",this._onResize)}_handleKeyDown(Xw){if(Xw.key==="Escape"&&this.config.closeOnEscape!==false){Xw.preventDefault();this.pushEvent("close",{})}}_handleClick(Yw){if(!this.el.contains(Yw.target)){this.pushEvent("close",{})}}_handleScroll(){this._position()}_handleResize(){this._position()}_position(){if(!this.trigger||!this.content)return;const Zw=this.trigger.getBoundingClientRect();const _w=this.content.getBoundingClientRect();const $w=this.config.offset||4;let ax=this.config.side||"bottom";let bx=this.config.align||"center";let cx,ex;switch(ax){case"top":cx=Zw.top-_w.height-$w;if(cx<0){cx=Zw.bottom+$w;ax="bottom"}break;case"bottom":cx=Zw.bottom+$w;if(cx+_w.height>window.innerHeight){cx=Zw.top-_w.height-$w;ax="top"}break;case"left":ex=Zw.left-_w.width-$w;if(ex<0){ex=Zw.right+$w;ax="right"}break;case"right":ex=Zw.right+$w;if(ex+_w.width>window.innerWidth){ex=Zw.left-_w.width-$w;ax="left"}break}if(ax==="top"||ax==="bottom"){switch(bx){case"start":ex=Zw.left;break;case"center":ex=Zw.left+(Zw.width-_w.width)/2;break;case"end":ex=Zw.right-_w.width;break}if(ex<0)ex=0;if(ex+_w.width>window.innerWidth){ex=window.innerWidth-_w.width}}if(ax==="left"||ax==="right"){switch(bx){case"start":cx=Zw.top;break;case"center":cx=Zw.top+(Zw.height-_w.height)/2;break;case"end":cx=Zw.bottom-_w.height;break}if(cx<0)cx=0;if(cx+_w.height>window.innerHeight){cx=window.innerHeight-_w.height}}this.content.style.position="fixed";this.content.style.top=`${cx}px`;this.content.style.left=`${ex}px`;this.content.dataset.side=ax}}class tb{mounted(fx,gx={}){const hx=gx&&gx.storageKey?String(gx.storageKey):"theme";const ix=()=>{let kx="";try{kx=localStorage.getItem(hx)||""}catch(_e){kx=""}const lx=window.matchMedia&&window.matchMedia("(prefers-color-scheme:dark)").matches;const mx=kx==="dark"||!kx&&lx;document.documentElement.classList.toggle("dark",mx)};const jx=()=>{const nx=!document.documentElement.classList.contains("dark");document.documentElement.classList.toggle("dark",nx);try{localStorage.setItem(hx,nx?"dark":"light")}catch(_e2){}};ix();this._onClick=ox=>{ox.preventDefault();jx()};fx.addEventListener("click",this._onClick)}destroyed(px){if(this._onClick){px.removeEventListener("click",this._onClick)}this._onClick=null}}function ub(qx){let rx;let sx;try{rx=qx.selectionStart;sx=qx.selectionEnd}catch(_e){return null}if(rx===null||rx===undefined)return null;return{text:qx.value.slice(rx,sx),start:rx,end:sx,direction:qx.selectionDirection||"none"}}function vb(tx){const ux={text:"",start:0,end:0,direction:"none"};const vx=window.getSelection();if(!vx||vx.rangeCount===0)return ux;const wx=vx.getRangeAt(0);if(!wx.intersectsNode(tx))return ux;const xx=document.createRange();xx.selectNodeContents(tx);if(tx.contains(wx.startContainer)){xx.setStart(wx.startContainer,wx.startOffset)}if(tx.contains(wx.endContainer)){xx.setEnd(wx.endContainer,wx.endOffset)}const yx=document.createRange();yx.selectNodeContents(tx);yx.setEnd(xx.startContainer,xx.startOffset);const zx=xx.toString();const Ax=yx.toString().length;let Bx="none";if(!vx.isCollapsed){const Cx=vx.anchorNode===wx.startContainer&&vx.anchorOffset===wx.startOffset;Bx=Cx?"forward":"backward"}return{text:zx,start:Ax,end:Ax+zx.length,direction:Bx}}const wb={"vango:rect":Dx=>{const Ex=Dx.getBoundingClientRect();return{x:Ex.x,y:Ex.y,width:Ex.width,height:Ex.height,top:Ex.top,right:Ex.right,bottom:Ex.bottom,left:Ex.left}},"vango:scroll":Fx=>({top:Fx.scrollTop,left:Fx.scrollLeft,width:Fx.scrollWidth,height:Fx.scrollHeight}),"vango:selection":Gx=>{if(Gx instanceof HTMLInputElement||Gx instanceof HTMLTextAreaElement){const Hx=ub(Gx);if(Hx)return Hx}return vb(Gx)}};const xb=new Set(["constructor","mounted","updated","destroyed"]);class yb{constructor(Ix){this.client=Ix;this.instances=new Map;this.instancesByHookID=new Map;this.pendingReverts=new Map;this._hookIdCounter=0;this.hooks={"Sortable":hb,"Draggable":ib,"Droppable":jb,"Resizable":kb,"Tooltip":lb,"Dropdown":mb,"Collapsible":nb,"FocusTrap":ob,"Portal":pb,"Dialog":rb,"Popover":sb,"ThemeToggle":tb};document.addEventListener("vango:hook-revert",Jx=>{var Kx;const Lx=(Kx=Jx.detail)==null?void 0:Kx.hid;if(Lx)this.revert(Lx)})}register(Mx,Nx){this.hooks[Mx]=Nx}initializeFromDOM(){document.querySelectorAll("[data-hook]").forEach(Ox=>{this.initializeForNode(Ox)})}updateFromDOM(){document.querySelectorAll("[data-hook]").forEach(Px=>{const Qx=Px.dataset.hook;const Rx=Px.dataset.hookConfig||"";const Sx=Px.dataset.hid;if(Sx){const Wx=this.instances.get(Sx);if(!Wx){this.initializeForNode(Px);return}if(Wx.hookName!==Qx){this.destroyForNode(Px);this.initializeForNode(Px);return}if(Wx.hookConfigRaw!==Rx){let Xx={};try{if(Rx)Xx=JSON.parse(Rx)}catch(e){if(this.client.options.debug){console.warn("[Vango] Invalid hook config:",e)}}this.updateConfig(Sx,Xx);Wx.hookConfigRaw=Rx}return}const Tx=Px.dataset.vangoHookId;const Ux=Tx||"";const Vx=Ux?this.instancesByHookID.get(Ux):null;if(!Vx){this.initializeForNode(Px);return}if(Vx.hookName!==Qx){this.destroyForNode(Px);this.initializeForNode(Px);return}if(Vx.hookConfigRaw!==Rx){let Yx={};try{if(Rx)Yx=JSON.parse(Rx)}catch(e){if(this.client.options.debug){console.warn("[Vango] Invalid hook config:",e)}}if(Vx.instance.updated){const Zx=(_x,$x={},ay=null)=>{if(typeof ay==="function"){this.pendingReverts.set(Ux,ay)}};Vx.instance.updated(Vx.el,Yx,Zx)}Vx.hookConfigRaw=Rx}})}initializeForNode(by){const cy=by.dataset.hook;if(!cy)return;const ey=this.hooks[cy];if(!ey){if(this.client.options.debug){console.warn("[Vango] Unknown hook:",cy)}return}const fy=by.dataset.hid;let gy="hid";let hy=fy;if(!hy){gy="hookID";hy=by.dataset.vangoHookId;if(!hy){this._hookIdCounter++;hy=`hk${this._hookIdCounter}`;by.dataset.vangoHookId=hy}if(this.instancesByHookID.has(hy)){return}}else{if(this.instances.has(hy)){return}}let iy={};const jy=by.dataset.hookConfig||"";try{if(jy){iy=JSON.parse(jy)}}catch(e){if(this.client.options.debug){console.warn("[Vango] Invalid hook config:",e)}}const ky=(my,ny={},oy=null)=>{if(gy!=="hid"){if(this.client.options.debug){console.warn("[Vango] Hook event ignored: hook element has no data-hid")}return}if(typeof oy==="function"){this.pendingReverts.set(hy,oy)}this.client.sendHookEvent(hy,my,ny)};const ly=new ey;ly.mounted(by,iy,ky);if(gy==="hid"){this.instances.set(hy,{hook:ey,hookName:cy,hookConfigRaw:jy,instance:ly,el:by})}else{this.instancesByHookID.set(hy,{hook:ey,hookName:cy,hookConfigRaw:jy,instance:ly,el:by})}}destroyForNode(py){var qy,ry;const sy=(qy=py.dataset)==null?void 0:qy.hid;if(sy){const vy=this.instances.get(sy);if(vy){if(vy.instance.destroyed){vy.instance.destroyed(vy.el)}this.instances.delete(sy)}return}const ty=(ry=py.dataset)==null?void 0:ry.vangoHookId;if(!ty)return;const uy=this.instancesByHookID.get(ty);if(!uy)return;if(uy.instance.destroyed){uy.instance.destroyed(uy.el)}this.instancesByHookID.delete(ty)}destroyAll(){for(const[wy,xy]of this.instances){if(xy.instance.destroyed){xy.instance.destroyed(xy.el)}}this.instances.clear();for(const[yy,zy]of this.instancesByHookID){if(zy.instance.destroyed){zy.instance.destroyed(zy.el)}}this.instancesByHookID.clear()}updateConfig(Ay,By){const Cy=this.instances.get(Ay);if(Cy&&Cy.instance.updated){const Dy=(Ey,Fy={},Gy=null)=>{if(typeof Gy==="function"){this.pendingReverts.set(Ay,Gy)}this.client.sendHookEvent(Ay,Ey,Fy)};Cy.instance.updated(Cy.el,By,Dy)}}async call(Hy,Iy,Jy={}){const Ky=this.instances.get(Hy);if(Ky&&!xb.has(Iy)&&typeof Ky.instance[Iy]==="function"){return await Ky.instance[Iy](Ky.el,Jy)}const Ly=wb[Iy];if(Ly){const My=(Ky==null?void 0:Ky.el)||this.client.getNode(Hy);if(!My)throw new Error(`no element with HID ${Hy}`);return Ly(My,Jy)}if(!Ky)throw new Error(`no hook on element ${Hy}`);throw new Error(`hook ${Ky.hookName} has no method ${Iy}`)}revert(Ny){if(!Ny||!this.pendingReverts.has(Ny))return;const Oy=this.pendingReverts.get(Ny);if(typeof Oy==="function"){Oy()}this.pendingReverts.delete(Ny)}}const zb=64;class Ab{constructor(Py){this.client=Py;this.instances=new Map;this.modules=new Map;this.pending=new Map;this.loadModule=Py.options.islandLoader||(Qy=>import(Qy))}register(Ry,Sy){this.modules.set(Ry,Sy)}initializeFromDOM(){document.querySelectorAll("[data-island]").forEach(Ty=>{this.mount(Ty)})}updateFromDOM(){for(const[Uy,Vy]of this.instances){if(!Vy.el.isConnected){this.destroy(Uy)}}document.querySelectorAll("[data-island]").forEach(Wy=>{const Xy=this.instances.get(Wy.dataset.island);if(!Xy||Xy.el!==Wy||Xy.moduleSrc!==Wy.dataset.module){if(Xy)this.destroy(Wy.dataset.island);this.mount(Wy);return}const Yy=Wy.dataset.props||"";if(Xy.propsRaw!==Yy){Xy.propsRaw=Yy;if(Xy.mounted&&Xy.instance&&typeof Xy.instance.update==="function"){Xy.instance.update(this._parseProps(Yy))}}});this.pending.clear()}mount(Zy){const _y=Zy.dataset.island;const $y=Zy.dataset.module;if(!_y||!$y||this.instances.has(_y))return;const az={el:Zy,moduleSrc:$y,propsRaw:Zy.dataset.props||"",instance:null,handlers:[],pending:this.pending.get(_y)||[],mounted:false,destroyed:false};this.pending.delete(_y);this.instances.set(_y,az);const bz={id:_y,send:(ez={})=>this.send(_y,ez),onMessage:fz=>{az.handlers.push(fz);return()=>{az.handlers=az.handlers.filter(gz=>gz!==fz)}}};const cz=hz=>{var iz;if(az.destroyed)return;const jz=(hz==null?void 0:hz.mount)||(hz==null||(iz=hz.default)==null?void 0:iz.mount)||(hz==null?void 0:hz.default);if(typeof jz!=="function"){console.error("[Vango] Island module has no mount():",$y);return}az.instance=jz(Zy,this._parseProps(az.propsRaw),bz)||null;az.mounted=true;const kz=az.pending;az.pending=[];for(const lz of kz){this._dispatch(az,lz)}};const dz=this.modules.get($y);if(dz){cz(dz);return}Promise.resolve().then(()=>this.loadModule($y)).then(cz).catch(mz=>{console.error("[Vango] Failed to load island module:",$y,mz)})}destroy(nz){const oz=this.instances.get(nz);if(!oz)return;oz.destroyed=true;this.instances.delete(nz);const pz=oz.instance;if(typeof pz==="function"){pz()}else if(pz&&typeof pz.destroy==="function"){pz.destroy()}}destroyAll(){for(const qz of[...this.instances.keys()]){this.destroy(qz)}this.pending.clear()}deliver(rz,sz){const tz=this.instances.get(rz);if(!tz){if(!this.pending.has(rz))this.pending.set(rz,[]);this.pending.get(rz).push(sz);return}if(!tz.mounted){tz.pending.push(sz);return}this._dispatch(tz,sz)}send(uz,vz={}){const wz=Math.max(0,...Object.values(vz||{}).map(xz=>this._depth(xz,0)));if(wz>zb){console.error("[Vango] Island message exceeds max depth, dropped:",uz);return false}return this.client.sendIslandMessage(uz,vz)}_dispatch(yz,zz){for(const Az of yz.handlers){try{Az(zz)}catch(err){console.error("[Vango] Island message handler error:",err)}}}_parseProps(Bz){if(!Bz)return{};try{return JSON.parse(Bz)}catch(e){if(this.client.options.debug){console.warn("[Vango] Invalid island props:",e)}return{}}}_depth(Cz,Dz){if(Dz>zb)return Dz;let Ez=Dz;if(Array.isArray(Cz)){for(const Fz of Cz){Ez=Math.max(Ez,this._depth(Fz,Dz+1))}}else if(Cz!==null&&typeof Cz==="object"){for(const Gz of Object.values(Cz)){Ez=Math.max(Ez,this._depth(Gz,Dz+1))}}return Ez}}const Bb={CONNECTING:"connecting",CONNECTED:"connected",RECONNECTING:"reconnecting",DISCONNECTED:"disconnected"};const Cb={[Bb.CONNECTING]:"vango-connecting",[Bb.CONNECTED]:"vango-connected",[Bb.RECONNECTING]:"vango-reconnecting",[Bb.DISCONNECTED]:"vango-disconnected"};class Db{constructor(Hz={}){this.options={toastOnReconnect:Hz.toastOnReconnect||false,toastMessage:Hz.toastMessage||"Connection restored",toastDuration:Hz.toastDuration||3000,maxRetries:Hz.maxRetries||10,baseDelay:Hz.baseDelay||1000,maxDelay:Hz.maxDelay||30000,debug:Hz.debug||false,...Hz};this.state=Bb.CONNECTING;this.retryCount=0;this.previousState=null;this.disconnectReason=null;this._updateClasses()}setState(Iz){if(this.state===Iz)return;this.previousState=this.state;this.state=Iz;if(Iz!==Bb.DISCONNECTED){this.disconnectReason=null}this._updateClasses();this._dispatchEvent(Iz,this.previousState);if(this.options.toastOnReconnect&&Iz===Bb.CONNECTED&&this.previousState!==Bb.CONNECTED){this.showToast(this.options.toastMessage)}if(this.options.debug){console.log(`[Vango] Connection state: ${this.previousState} -> ${Iz}`)}}_updateClasses(){const Jz=document.documentElement;Object.values(Cb).forEach(Lz=>{Jz.classList.remove(Lz)});const Kz=Cb[this.state];if(Kz){Jz.classList.add(Kz)}if(this.state===Bb.DISCONNECTED&&this.disconnectReason){Jz.setAttribute("data-vango-disconnect-reason",this.disconnectReason)}else{Jz.removeAttribute("data-vango-disconnect-reason")}}_dispatchEvent(Mz,Nz){const Oz=new CustomEvent("vango:connection",{detail:{state:Mz,previousState:Nz},bubbles:true});document.dispatchEvent(Oz)}onConnect(){this.retryCount=0;this.setState(Bb.CONNECTED)}onDisconnect(){if(this.state===Bb.CONNECTED){this.setState(Bb.RECONNECTING)}}setDisconnected(Pz){this.disconnectReason=Pz||null;this.setState(Bb.DISCONNECTED)}onReconnectFailed(){this.retryCount++;if(this.retryCount>=this.options.maxRetries){this.setState(Bb.DISCONNECTED);return false}return true}getNextRetryDelay(){const Qz=Math.min(this.options.baseDelay*Math.pow(2,this.retryCount),this.options.maxDelay);return Qz}getState(){return this.state}isConnected(){return this.state===Bb.CONNECTED}isDisconnected(){return this.state===Bb.DISCONNECTED}reset(){this.retryCount=0;this.setState(Bb.CONNECTED)}showToast(Rz,Sz=this.options.toastDuration){if(typeof window.__VANGO_TOAST__==="function"){window.__VANGO_TOAST__(Rz,Sz);return}const Tz=document.createElement("div");Tz.className="vango-toast";Tz.textContent=Rz;Tz.setAttribute("role","alert");Tz.setAttribute("aria-live","polite");Object.assign(Tz.style,{position:"fixed",bottom:"20px",left:"50%",transform:"translateX(-50%)",padding:"12px 24px",backgroundColor:"hsl(var(--primary, 220 13% 18%))",color:"hsl(var(--primary-foreground, 0 0% 100%))",borderRadius:"var(--radius, 6px)",boxShadow:"0 4px 12px rgba(0, 0, 0, 0.15)",zIndex:"10001",opacity:"0",transition:"opacity 0.3s ease",fontFamily:"system-ui, -apple-system, sans-serif",fontSize:"14px"});document.body.appendChild(Tz);requestAnimationFrame(()=>{Tz.style.opacity="1"});setTimeout(()=>{Tz.style.opacity="0";setTimeout(()=>{if(Tz.parentNode){Tz.parentNode.removeChild(Tz)}},300)},Sz)}}function Eb(){if(document.getElementById("vango-connection-styles")){return}const Uz=document.createElement("style");Uz.id="vango-connection-styles";Uz.textContent=`
         /* Vango Connection State Styles */
 
         /* Connecting indicator - pulsing bar at top */
@@ -85,4 +85,4 @@ var Vango=(()=>{var G=Object.defineProperty;var re=Object.getOwnPropertyDescript
                 opacity: 1;
             }
         }
-    `,document.head.appendChild(l)}var $=class{constructor(e,t={}){this.client=e,this.options={debug:t.debug||!1,...t},this.pending=new Map}applyPatch(e){let{op:t,params:s}=e;if(!s||typeof s!="object"){this.options.debug&&console.warn("[Vango URL] Invalid params:",s);return}let n=new URL(window.location);for(let[o,r]of Object.entries(s))r===""||r===null||r===void 0?n.searchParams.delete(o):n.searchParams.set(o,r);let i=t===48;this.options.debug&&console.log("[Vango URL]",i?"Push":"Replace",n.toString()),i?history.pushState(null,"",n.toString()):history.replaceState(null,"",n.toString()),this._dispatchEvent(s,i)}_dispatchEvent(e,t){let s=new CustomEvent("vango:url",{detail:{params:e,mode:t?"push":"replace",url:window.location.href},bubbles:!0});document.dispatchEvent(s)}getParams(){let e={},t=new URLSearchParams(window.location.search);for(let[s,n]of t)e[s]=n;return e}getParam(e){return new URLSearchParams(window.location.search).get(e)}hasParam(e){return new URLSearchParams(window.location.search).has(e)}};var S={DB_WINS:0,LOCAL_WINS:1,PROMPT:2,LWW:3},Y=class{constructor(e,t={}){this.client=e,this.options={channelName:"vango:prefs",storagePrefix:"vango_pref_",debug:t.debug||!1,...t},this.prefs=new Map,this.channel=null,typeof BroadcastChannel!="undefined"&&(this.channel=new BroadcastChannel(this.options.channelName),this.channel.onmessage=s=>this._handleBroadcast(s)),typeof window!="undefined"&&window.addEventListener("storage",s=>this._handleStorageEvent(s))}register(e,t,s={}){let n=new j(this,e,t,s);return this.prefs.set(e,n),n._loadFromStorage(),n}get(e){return this.prefs.get(e)}broadcast(e,t,s){this.channel&&this.channel.postMessage({type:"update",key:e,value:t,updatedAt:s.toISOString()})}_handleBroadcast(e){let{type:t,key:s,value:n,updatedAt:i}=e.data;if(t==="update"){let o=this.prefs.get(s);o&&o._setFromRemote(n,new Date(i))}this.options.debug&&console.log("[Vango Prefs] Broadcast received:",e.data)}_handleStorageEvent(e){if(!e.key||!e.key.startsWith(this.options.storagePrefix))return;let t=e.key.slice(this.options.storagePrefix.length),s=this.prefs.get(t);if(s&&e.newValue)try{let n=JSON.parse(e.newValue);s._setFromRemote(n.value,new Date(n.updatedAt))}catch(n){this.options.debug&&console.warn("[Vango Prefs] Failed to parse storage event:",n)}}saveToStorage(e,t,s){if(typeof localStorage=="undefined")return;let n=this.options.storagePrefix+e,i={value:t,updatedAt:s.toISOString()};try{localStorage.setItem(n,JSON.stringify(i))}catch(o){this.options.debug&&console.warn("[Vango Prefs] Failed to save to storage:",o)}}loadFromStorage(e){if(typeof localStorage=="undefined")return null;let t=this.options.storagePrefix+e;try{let s=localStorage.getItem(t);if(s){let n=JSON.parse(s);return{value:n.value,updatedAt:new Date(n.updatedAt)}}}catch(s){this.options.debug&&console.warn("[Vango Prefs] Failed to load from storage:",s)}return null}removeFromStorage(e){if(typeof localStorage=="undefined")return;let t=this.options.storagePrefix+e;try{localStorage.removeItem(t)}catch(s){}}async syncWithServer(e){for(let[t,s]of Object.entries(e)){let n=this.prefs.get(t);n&&n._mergeWithServer(s.value,new Date(s.updatedAt))}}getAllForSync(){let e={};for(let[t,s]of this.prefs)s.options.syncToServer!==!1&&(e[t]={value:s.value,updatedAt:s.updatedAt.toISOString()});return e}destroy(){this.channel&&(this.channel.close(),this.channel=null)}},j=class{constructor(e,t,s,n={}){this.manager=e,this.key=t,this.defaultValue=s,this.value=s,this.updatedAt=new Date,this.options={mergeStrategy:S.LWW,persistLocal:!0,syncToServer:!0,onConflict:null,onChange:null,...n},this.subscribers=new Set}get(){return this.value}set(e){if(this._isEqual(this.value,e))return;let t=this.value;this.value=e,this.updatedAt=new Date,this.options.persistLocal&&this.manager.saveToStorage(this.key,e,this.updatedAt),this.manager.broadcast(this.key,e,this.updatedAt),this.options.syncToServer&&this.manager.client&&this._syncToServer(),this._notifySubscribers(e,t),this.options.onChange&&this.options.onChange(e,t)}reset(){this.set(this.defaultValue)}subscribe(e){return this.subscribers.add(e),()=>this.subscribers.delete(e)}_loadFromStorage(){let e=this.manager.loadFromStorage(this.key);e&&(this.value=e.value,this.updatedAt=e.updatedAt)}_setFromRemote(e,t){let s=this._resolveConflict(this.value,e,this.updatedAt,t);if(!this._isEqual(this.value,s)){let n=this.value;this.value=s,t>this.updatedAt&&(this.updatedAt=t),this.options.persistLocal&&this.manager.saveToStorage(this.key,this.value,this.updatedAt),this._notifySubscribers(this.value,n),this.options.onChange&&this.options.onChange(this.value,n)}}_mergeWithServer(e,t){let s=this._resolveConflict(this.value,e,this.updatedAt,t);if(!this._isEqual(this.value,s)){let n=this.value;this.value=s,t>this.updatedAt&&(this.updatedAt=t),this.options.persistLocal&&this.manager.saveToStorage(this.key,this.value,this.updatedAt),this._notifySubscribers(this.value,n),this.options.onChange&&this.options.onChange(this.value,n)}}_resolveConflict(e,t,s,n){if(this.options.onConflict)return this.options.onConflict(e,t,s,n);switch(this.options.mergeStrategy){case S.DB_WINS:return t;case S.LOCAL_WINS:return e;case S.LWW:return n>s?t:e;case S.PROMPT:return n>s?t:e;default:return e}}_syncToServer(){!this.manager.client||!this.manager.client.connected||this.manager.client.sendEvent(16,"",{key:this.key,value:this.value,updatedAt:this.updatedAt.toISOString()})}_notifySubscribers(e,t){for(let s of this.subscribers)try{s(e,t)}catch(n){console.error("[Vango Prefs] Subscriber error:",n)}}_isEqual(e,t){return e===t?!0:typeof e!=typeof t?!1:typeof e=="object"&&e!==null&&t!==null?JSON.stringify(e)===JSON.stringify(t):!1}};var w={HANDSHAKE:0,EVENT:1,PATCHES:2,CONTROL:3,ACK:4,ERROR:5},me=65535,x={PING:1,PONG:2,RESYNC_REQUEST:16,RESYNC_PATCHES:17,RESYNC_FULL:18,HOOK_REVERT:48,AUTH_COMMAND:49,CLOSE:32},Q={FORCE_RELOAD:1,HARD_NAVIGATE:2,BROADCAST:3},C=class{constructor(e={}){this.options={wsUrl:e.wsUrl||this._defaultWsUrl(),reconnect:e.reconnect!==!1,reconnectInterval:e.reconnectInterval||1e3,reconnectMaxInterval:e.reconnectMaxInterval||3e4,heartbeatInterval:e.heartbeatInterval||3e4,debug:e.debug||!1,...e},this.codec=new k,this.nodeMap=new Map,this.connected=!1,this.seq=0,this.patchSeq=0,this.expectedPatchSeq=1,this.pendingResync=!1,this.wsManager=new T(this,this.options),this.patchApplier=new R(this),this.eventCapture=new A(this),this.optimistic=new D(this),this.hooks=new q(this),this.connection=new E({toastOnReconnect:e.toastOnReconnect||window.__VANGO_TOAST_ON_RECONNECT__,toastMessage:e.toastMessage||"Connection restored",maxRetries:e.maxRetries||10,baseDelay:e.reconnectInterval||1e3,maxDelay:e.reconnectMaxInterval||3e4,debug:e.debug}),this.urlManager=new $(this,{debug:e.debug}),this.prefs=new Y(this,{debug:e.debug}),this._authChannel="vango:auth",this._authBroadcast=null,this._authStorageHandler=null,this._authReloadScheduled=!1,this._authPollUrl=e.authPollUrl||"",this._authPollInterval=e.authPollInterval||3e4,this._authPollTimer=null,this.onConnect=e.onConnect||(()=>{}),this.onDisconnect=e.onDisconnect||(()=>{}),this.onError=e.onError||(t=>console.error("[Vango]",t)),ee(),se(),this._buildNodeMap(),this._initAuthBroadcast(),this.wsManager.connect(this.options.wsUrl),this.eventCapture.attach(),this.hooks.initializeFromDOM()}_buildNodeMap(){document.querySelectorAll("[data-hid]").forEach(e=>{let t=e.dataset.hid;this.nodeMap.set(t,e)}),this.options.debug&&console.log(`[Vango] Mapped ${this.nodeMap.size} nodes`)}_defaultWsUrl(){let e=location.protocol==="https:"?"wss:":"ws:",t=encodeURIComponent(location.pathname+location.search);return`${e}//${location.host}/_vango/live?path=${t}`}_onConnected(){this.connected=!0,this.connection.onConnect(),this.onConnect()}_onDisconnected(){this.connected=!1,this.connection.onDisconnect(),this.onDisconnect()}_onError(e){this.onError(e)}_handleProtocolError(e,t){let s=e instanceof Error?e.message:String(e);this.options.debug?console.error("[Vango] Protocol error:",t||"decode failure",e):console.error("[Vango] Protocol error:",t||"decode failure",s);let n=e instanceof Error?e:new Error(s);this._onError(n),this.wsManager.close(),this._triggerSelfHeal()}_triggerSelfHeal(){var t;let e=(t=this.eventCapture)==null?void 0:t.pendingNavPath;e?(console.log("[Vango] Self-heal: navigating to pending path:",e),location.assign(e)):(console.log("[Vango] Self-heal: reloading page"),location.reload())}retryConnect(){this.wsManager.resetReconnect(),this.connection.setState(g.CONNECTING),this.wsManager.connect(this.options.wsUrl)}_handleBinaryMessage(e){if(e.length<4){this._handleProtocolError(new Error("Frame header too short"),"frame header");return}let t=e[0],s=e[2]<<8|e[3];if(s>me){this._handleProtocolError(new Error("Frame payload exceeds max size"),"frame header");return}if(e.length!==4+s){this._handleProtocolError(new Error("Frame length mismatch"),"frame header");return}let n=e.slice(4,4+s);try{switch(t){case w.PATCHES:this._handlePatches(n);break;case w.CONTROL:this._handleControl(n);break;case w.ERROR:this._handleServerError(n);break;default:this.options.debug&&console.warn("[Vango] Unknown frame type:",t)}}catch(i){this._handleProtocolError(i,`frame type ${t}`)}}_handlePatches(e){this.options.debug&&console.log("[Vango] Received patches buffer, length:",e.length);let{seq:t,patches:s}=this.codec.decodePatches(e);if(this.options.debug&&console.log("[Vango] Decoded",s.length,"patches, seq:",t),t<this.expectedPatchSeq){this.options.debug&&console.log("[Vango] Ignoring duplicate patch seq:",t,"expected:",this.expectedPatchSeq);return}if(t>this.expectedPatchSeq){console.warn("[Vango] Patch sequence gap detected","expected:",this.expectedPatchSeq,"received:",t),this._requestResync(this.patchSeq);return}if(this.options.debug){for(let n of s)console.log("[Vango] Patch:",n);console.log("[Vango] Applying",s.length,"patches (seq:",t,")")}this.optimistic.clearPending(),this.patchApplier.apply(s),this.hooks.updateFromDOM(),this.patchSeq=t,this.expectedPatchSeq=t+1,this.pendingResync=!1,this.wsManager.updateLastSeq(this.patchSeq),this._sendAck(t)}_handleControl(e){if(e.length===0)throw new Error("Control frame empty");let t=e[0];switch(t){case x.PONG:this.options.debug&&console.log("[Vango] Pong received");break;case x.RESYNC_FULL:this._handleResyncFull(e.slice(1));break;case x.HOOK_REVERT:{let{value:s}=this.codec.decodeString(e,1);this.hooks.revert(s);break}case x.AUTH_COMMAND:this._handleAuthCommand(e.slice(1));break;case x.CLOSE:this.wsManager.close();break;default:this.options.debug&&console.log("[Vango] Unknown control type:",t)}}_handleAuthCommand(e){if(e.length<2)return;let t=e[0],s=e[1],n=2,i=()=>{if(n>=e.length)return"";let{value:h,bytesRead:u}=this.codec.decodeString(e,n);return n+=u,h},o=i(),r=i()||"vango:auth",a=i()||"expired";switch(t){case Q.FORCE_RELOAD:this._broadcastAuth(r,a,s),setTimeout(()=>location.reload(),0);break;case Q.HARD_NAVIGATE:{this._broadcastAuth(r,a,s);let h=o||location.pathname;setTimeout(()=>location.assign(h),0);break}case Q.BROADCAST:this._broadcastAuth(r,a,s);break;default:this.options.debug&&console.log("[Vango] Unknown auth action:",t)}}_initAuthBroadcast(){if(typeof BroadcastChannel!="undefined"){this._authBroadcast=new BroadcastChannel(this._authChannel),this._authBroadcast.onmessage=e=>{this._handleAuthBroadcast(e.data)};return}if(!this._canUseLocalStorage()){this._startAuthPolling();return}if(typeof window=="undefined"||!window.addEventListener){this._startAuthPolling();return}this._authStorageHandler=e=>{if(!(!e||!e.key||e.key!==`__vango_auth_${this._authChannel}`)&&e.newValue)try{let t=JSON.parse(e.newValue);this._handleAuthBroadcast(t.payload)}catch(t){}},window.addEventListener("storage",this._authStorageHandler)}_handleAuthBroadcast(e){!e||e.type!=="expired"&&e.type!=="logout"||this._scheduleAuthReload(e)}_scheduleAuthReload(e){this._authReloadScheduled||(this._authReloadScheduled=!0,this.options.debug&&console.log("[Vango] Auth reload scheduled:",e||{}),setTimeout(()=>location.reload(),0))}_startAuthPolling(){if(!this._authPollUrl||this._authPollInterval<=0||this._authPollTimer)return;let e=async()=>{try{(await fetch(this._authPollUrl,{method:"GET",credentials:"same-origin",cache:"no-store",headers:{"X-Vango-Auth-Poll":"1"}})).status===401&&this._scheduleAuthReload({type:"expired",reason:"poll"})}catch(t){}};e(),this._authPollTimer=setInterval(e,this._authPollInterval)}_stopAuthPolling(){this._authPollTimer&&(clearInterval(this._authPollTimer),this._authPollTimer=null)}_canUseLocalStorage(){try{if(typeof localStorage=="undefined")return!1;let e="__vango_auth_probe__";return localStorage.setItem(e,"1"),localStorage.removeItem(e),!0}catch(e){return!1}}_broadcastAuth(e,t,s){let n={type:t,reason:s};if(typeof BroadcastChannel!="undefined"){let i=new BroadcastChannel(e);i.postMessage(n),i.close();return}try{if(typeof localStorage=="undefined")return;let i=`__vango_auth_${e}`,o=JSON.stringify({payload:n,ts:Date.now()});localStorage.setItem(i,o),localStorage.removeItem(i)}catch(i){}}_handleResyncFull(e){if(e.length===0)throw new Error("ResyncFull payload empty");let{value:t}=this.codec.decodeString(e,0);this.options.debug&&console.log("[Vango] ResyncFull received, replacing body content");let s=document.createElement("template");for(s.innerHTML=t,this.nodeMap.clear(),this.hooks.destroyAll(),document.body.innerHTML="";s.content.firstChild;)document.body.appendChild(s.content.firstChild);this._buildNodeMap(),this.hooks.initializeFromDOM(),this.patchSeq=0,this.expectedPatchSeq=1,this.pendingResync=!1,this.wsManager.updateLastSeq(0)}_sendAck(e){if(!this.connected)return;let t=this.codec.encodeAck(e,100),s=this._encodeFrame(w.ACK,t);this.wsManager.send(s),this.options.debug&&console.log("[Vango] Sent ACK for seq:",e)}_requestResync(e){if(this.pendingResync){this.options.debug&&console.log("[Vango] Resync already pending, skipping");return}this.pendingResync=!0,this.options.debug&&console.log("[Vango] Requesting resync from seq:",e);let t=this.codec.encodeResyncRequest(e),s=this._encodeFrame(w.CONTROL,t);this.wsManager.send(s)}_handleServerError(e){if(e.length<3)throw new Error("Error frame too short");let t=e[0]<<8|e[1],{value:s,bytesRead:n}=this.codec.decodeString(e,2),i=2+n,o=i<e.length?e[i]===1:!1,a={0:"Unknown error",1:"Invalid frame",2:"Invalid event",3:"Handler not found",4:"Handler panic",5:"Session expired",6:"Rate limited",256:"Server error",257:"Not authorized",258:"Not found",259:"Validation failed",260:"Route error"}[t]||s||`Unknown error: ${t}`,h=new Error(a);h.code=t,h.fatal=o,this.onError(h),this.optimistic.revertAll(),o&&this.wsManager.close()}sendEvent(e,t,s=null){this.connected||this.options.debug&&console.log("[Vango] Not connected, queueing event"),this.seq++;let n=this.codec.encodeEvent(this.seq,e,t,s),i=this._encodeFrame(w.EVENT,n);this.wsManager.send(i),this.options.debug&&console.log("[Vango] Sent event:",{type:e,hid:t,data:s,seq:this.seq})}_encodeFrame(e,t){let s=t.length,n=new Uint8Array(4+s);return n[0]=e,n[1]=0,n[2]=s>>8&255,n[3]=s&255,n.set(t,4),n}sendHookEvent(e,t,s={}){this.sendEvent(d.HOOK,e,{name:t,data:s})}getNode(e){return this.nodeMap.get(e)}registerNode(e,t){this.nodeMap.set(e,t)}unregisterNode(e){this.nodeMap.delete(e)}registerHook(e,t){this.hooks.register(e,t)}registerPref(e,t,s={}){return this.prefs.register(e,t,s)}destroy(){this.eventCapture.detach(),this.hooks.destroyAll(),this.prefs.destroy(),this._authBroadcast&&(this._authBroadcast.close(),this._authBroadcast=null),this._authStorageHandler&&typeof window!="undefined"&&window.removeEventListener("storage",this._authStorageHandler),this._stopAuthPolling(),this.wsManager.close()}};function ne(){if(window.__vango__)return;let l=document.currentScript||document.querySelector("script[data-vango]"),e={};l&&(l.dataset.wsUrl&&(e.wsUrl=l.dataset.wsUrl),l.dataset.debug&&(e.debug=l.dataset.debug==="true")),window.__vango__=new C(e)}typeof document!="undefined"&&(document.readyState==="loading"?document.addEventListener("DOMContentLoaded",ne):ne());var ye=C;return de(Ee);})();
+    `;document.head.appendChild(Uz)}class Fb{constructor(Vz,Wz={}){this.client=Vz;this.options={debug:Wz.debug||false,...Wz};this.pending=new Map}applyPatch(Xz){const{op:Yz,params:Zz}=Xz;if(!Zz||typeof Zz!=="object"){if(this.options.debug){console.warn("[Vango URL] Invalid params:",Zz)}return}const _z=new URL(window.location);for(const[aA,bA]of Object.entries(Zz)){if(bA===""||bA===null||bA===undefined){_z.searchParams.delete(aA)}else{_z.searchParams.set(aA,bA)}}const $z=Yz===48;if(this.options.debug){console.log("[Vango URL]",$z?"Push":"Replace",_z.toString())}if($z){history.pushState(null,"",_z.toString())}else{history.replaceState(null,"",_z.toString())}this._dispatchEvent(Zz,$z)}_dispatchEvent(cA,dA){const eA=new CustomEvent("vango:url",{detail:{params:cA,mode:dA?"push":"replace",url:window.location.href},bubbles:true});document.dispatchEvent(eA)}getParams(){const fA={};const gA=new URLSearchParams(window.location.search);for(const[hA,iA]of gA){fA[hA]=iA}return fA}getParam(jA){const kA=new URLSearchParams(window.location.search);return kA.get(jA)}hasParam(lA){const mA=new URLSearchParams(window.location.search);return mA.has(lA)}}const Gb={DB_WINS:0,LOCAL_WINS:1,PROMPT:2,LWW:3};class Hb{constructor(nA,oA={}){this.client=nA;this.options={channelName:"vango:prefs",storagePrefix:"vango_pref_",debug:oA.debug||false,...oA};this.prefs=new Map;this.channel=null;if(typeof BroadcastChannel!=="undefined"){this.channel=new BroadcastChannel(this.options.channelName);this.channel.onmessage=pA=>this._handleBroadcast(pA)}if(typeof window!=="undefined"){window.addEventListener("storage",qA=>this._handleStorageEvent(qA))}}register(rA,sA,tA={}){const uA=new Ib(this,rA,sA,tA);this.prefs.set(rA,uA);uA._loadFromStorage();return uA}get(vA){return this.prefs.get(vA)}broadcast(wA,xA,yA){if(this.channel){this.channel.postMessage({type:"update",key:wA,value:xA,updatedAt:yA.toISOString()})}}_handleBroadcast(zA){const{type:AA,key:BA,value:CA,updatedAt:DA}=zA.data;if(AA==="update"){const EA=this.prefs.get(BA);if(EA){EA._setFromRemote(CA,new Date(DA))}this.sendToServer([{key:BA,value:CA,updatedAt:new Date(DA)}])}if(this.options.debug){console.log("[Vango Prefs] Broadcast received:",zA.data)}}_handleStorageEvent(FA){if(!FA.key||!FA.key.startsWith(this.options.storagePrefix)){return}const GA=FA.key.slice(this.options.storagePrefix.length);const HA=this.prefs.get(GA);if(FA.newValue){try{const IA=JSON.parse(FA.newValue);const JA=new Date(IA.updatedAt);if(HA){HA._setFromRemote(IA.value,JA)}if(!this.channel){this.sendToServer([{key:GA,value:IA.value,updatedAt:JA}])}}catch(e){if(this.options.debug){console.warn("[Vango Prefs] Failed to parse storage event:",e)}}}}saveToStorage(KA,LA,MA){if(typeof localStorage==="undefined")return;const NA=this.options.storagePrefix+KA;const OA={value:LA,updatedAt:MA.toISOString()};try{localStorage.setItem(NA,JSON.stringify(OA))}catch(e){if(this.options.debug){console.warn("[Vango Prefs] Failed to save to storage:",e)}}}loadFromStorage(PA){if(typeof localStorage==="undefined")return null;const QA=this.options.storagePrefix+PA;try{const RA=localStorage.getItem(QA);if(RA){const SA=JSON.parse(RA);return{value:SA.value,updatedAt:new Date(SA.updatedAt)}}}catch(e){if(this.options.debug){console.warn("[Vango Prefs] Failed to load from storage:",e)}}return null}removeFromStorage(TA){if(typeof localStorage==="undefined")return;const UA=this.options.storagePrefix+TA;try{localStorage.removeItem(UA)}catch(e){}}storedValues(){if(typeof localStorage==="undefined")return[];const VA=[];const WA=this.options.storagePrefix;try{for(let XA=0;XA<localStorage.length;XA++){const YA=localStorage.key(XA);if(!YA||!YA.startsWith(WA))continue;const ZA=YA.slice(WA.length);const _A=this.loadFromStorage(ZA);const $A=_A?_A.updatedAt.getTime():NaN;if(!_A||_A.value===undefined||Number.isNaN($A))continue;VA.push({key:ZA,value:JSON.stringify(_A.value),updatedAt:$A})}}catch(e){if(this.options.debug){console.warn("[Vango Prefs] Failed to list storage:",e)}}return VA}sendToServer(aB){const bB=this.client;if(!bB||!bB.connected||!bB.codec||aB.length===0){return}if(bB.protocolVersion&&!Y(bB.protocolVersion,2,2)){return}const cB=bB.codec.encodePrefSync(aB.map(dB=>({key:dB.key,value:JSON.stringify(dB.value),updatedAt:dB.updatedAt.getTime()})));for(const eB of bB.codec.encodeFrames(3,cB)){bB.wsManager.send(eB)}}handleSync(fB){for(const{key:gB,value:hB,updatedAt:iB}of fB){let jB;try{jB=JSON.parse(hB)}catch(e){if(this.options.debug){console.warn("[Vango Prefs] Invalid value from server:",gB,e)}continue}const kB=new Date(iB);const lB=this.prefs.get(gB);if(lB){lB._setFromServer(jB,kB)}else{this.saveToStorage(gB,jB,kB)}this.broadcast(gB,jB,kB)}if(this.options.debug){console.log("[Vango Prefs] Sync received:",fB)}}async syncWithServer(mB){for(const[nB,oB]of Object.entries(mB)){const pB=this.prefs.get(nB);if(pB){pB._mergeWithServer(oB.value,new Date(oB.updatedAt))}}}getAllForSync(){const qB={};for(const[rB,sB]of this.prefs){if(sB.options.syncToServer!==false){qB[rB]={value:sB.value,updatedAt:sB.updatedAt.toISOString()}}}return qB}destroy(){if(this.channel){this.channel.close();this.channel=null}}}class Ib{constructor(tB,uB,vB,wB={}){this.manager=tB;this.key=uB;this.defaultValue=vB;this.value=vB;this.updatedAt=new Date;this.pendingConflict=null;this.options={mergeStrategy:Gb.LWW,persistLocal:true,syncToServer:true,onConflict:null,onChange:null,...wB};this.subscribers=new Set}get(){return this.value}set(xB){if(this._isEqual(this.value,xB)){return}const yB=this.value;this.value=xB;this.updatedAt=new Date;if(this.options.persistLocal){this.manager.saveToStorage(this.key,xB,this.updatedAt)}this.manager.broadcast(this.key,xB,this.updatedAt);if(this.options.syncToServer&&this.manager.client){this._syncToServer()}this._notifySubscribers(xB,yB);if(this.options.onChange){this.options.onChange(xB,yB)}}reset(){this.set(this.defaultValue)}conflict(){return this.pendingConflict}resolve(zB){const AB=this.pendingConflict;if(!AB)return;this.pendingConflict=null;if(zB){this._setFromServer(AB.value,AB.updatedAt)}else{this.updatedAt=new Date;if(this.options.persistLocal){this.manager.saveToStorage(this.key,this.value,this.updatedAt)}this.manager.broadcast(this.key,this.value,this.updatedAt);if(this.options.syncToServer){this._syncToServer()}}}subscribe(BB){this.subscribers.add(BB);return()=>this.subscribers.delete(BB)}_loadFromStorage(){const CB=this.manager.loadFromStorage(this.key);if(CB){this.value=CB.value;this.updatedAt=CB.updatedAt}}_setFromRemote(DB,EB){const FB=this._resolveConflict(this.value,DB,this.updatedAt,EB);if(!this._isEqual(this.value,FB)){const GB=this.value;this.value=FB;if(EB>this.updatedAt){this.updatedAt=EB}if(this.options.persistLocal){this.manager.saveToStorage(this.key,this.value,this.updatedAt)}this._notifySubscribers(this.value,GB);if(this.options.onChange){this.options.onChange(this.value,GB)}}}_setFromServer(HB,IB){this.pendingConflict=null;this.updatedAt=IB;if(this.options.persistLocal){this.manager.saveToStorage(this.key,HB,IB)}if(!this._isEqual(this.value,HB)){const JB=this.value;this.value=HB;this._notifySubscribers(HB,JB);if(this.options.onChange){this.options.onChange(HB,JB)}}}_mergeWithServer(KB,LB){const MB=this._resolveConflict(this.value,KB,this.updatedAt,LB);if(!this._isEqual(this.value,MB)){const NB=this.value;this.value=MB;if(LB>this.updatedAt){this.updatedAt=LB}if(this.options.persistLocal){this.manager.saveToStorage(this.key,this.value,this.updatedAt)}this._notifySubscribers(this.value,NB);if(this.options.onChange){this.options.onChange(this.value,NB)}}}_resolveConflict(OB,PB,QB,RB){if(this.options.onConflict){return this.options.onConflict(OB,PB,QB,RB)}switch(this.options.mergeStrategy){case Gb.DB_WINS:return PB;case Gb.LOCAL_WINS:return OB;case Gb.LWW:return RB>QB?PB:OB;case Gb.PROMPT:if(!this._isEqual(OB,PB)){this.pendingConflict={value:PB,updatedAt:RB}}return OB;default:return OB}}_syncToServer(){this.manager.sendToServer([{key:this.key,value:this.value,updatedAt:this.updatedAt}])}_notifySubscribers(SB,TB){for(const UB of this.subscribers){try{UB(SB,TB)}catch(e){console.error("[Vango Prefs] Subscriber error:",e)}}}_isEqual(VB,WB){if(VB===WB)return true;if(typeof VB!==typeof WB)return false;if(typeof VB==="object"&&VB!==null&&WB!==null){return JSON.stringify(VB)===JSON.stringify(WB)}return false}}const Jb={WINDOW:1,DOCUMENT:2};const Kb={PREVENT_DEFAULT:1,STOP_PROPAGATION:2,ONCE:4,PASSIVE:8,CAPTURE:16};const Lb=1;const Mb=2;const Nb=4;const Ob=8;function Pb(XB){return(XB.ctrlKey?Lb:0)|(XB.shiftKey?Mb:0)|(XB.altKey?Nb:0)|(XB.metaKey?Ob:0)}class Qb{constructor(YB,ZB={}){this.client=YB;this.options=ZB;this.subs=new Map;this.listeners=new Map}listen(_B){const $B=R[_B.event.toUpperCase()];if($B===undefined){console.warn("[Vango] Unsupported global event:",_B.event);return}this.unlisten(_B.id);const aC=(_B.flags&Kb.CAPTURE)!==0;const bC=`${_B.target}:${_B.event}:${aC?1:0}`;let cC=this.listeners.get(bC);if(!cC){const dC=_B.target===Jb.DOCUMENT?document:window;cC={target:dC,event:_B.event,capture:aC,ids:new Set};cC.handler=eC=>{for(const fC of[...cC.ids]){const gC=this.subs.get(fC);if(gC)this._handle(gC,eC)}};dC.addEventListener(_B.event,cC.handler,{capture:aC});this.listeners.set(bC,cC)}cC.ids.add(_B.id);this.subs.set(_B.id,{..._B,type:$B,key:bC,timer:null,last:0});if(this.options.debug){console.log("[Vango] Global listen:",_B.id,_B.event)}}unlisten(hC){const iC=this.subs.get(hC);if(!iC)return;clearTimeout(iC.timer);this.subs.delete(hC);const jC=this.listeners.get(iC.key);if(!jC)return;jC.ids.delete(hC);if(jC.ids.size===0){jC.target.removeEventListener(jC.event,jC.handler,{capture:jC.capture});this.listeners.delete(iC.key)}}reset(){for(const kC of[...this.subs.keys()]){this.unlisten(kC)}}_handle(lC,mC){if(lC.type===R.KEYDOWN||lC.type===R.KEYUP){if(lC.keys.length>0&&!lC.keys.includes(mC.key))return;if(lC.keyMods&&Pb(mC)!==lC.keyMods)return}if(lC.flags&Kb.PREVENT_DEFAULT&&!(lC.flags&Kb.PASSIVE)){mC.preventDefault();if(mC.type==="beforeunload"){mC.returnValue=""}}if(lC.flags&Kb.STOP_PROPAGATION){mC.stopPropagation()}const nC=this._payload(lC.type,mC);const oC=()=>this.client.sendEvent(lC.type,lC.id,nC);if(lC.flags&Kb.ONCE){this.unlisten(lC.id);oC();return}if(lC.debounce>0){clearTimeout(lC.timer);lC.timer=setTimeout(oC,lC.debounce);return}if(lC.throttle>0){const pC=Date.now();if(pC-lC.last<lC.throttle)return;lC.last=pC}oC()}_payload(qC,rC){switch(qC){case R.RESIZE:return{width:window.innerWidth,height:window.innerHeight};case R.SCROLL:return{scrollTop:Math.round(window.scrollY),scrollLeft:Math.round(window.scrollX)};case R.KEYDOWN:case R.KEYUP:return{key:rC.key,code:rC.code,ctrlKey:rC.ctrlKey,shiftKey:rC.shiftKey,altKey:rC.altKey,metaKey:rC.metaKey,repeat:rC.repeat,location:rC.location};case R.VISIBILITYCHANGE:return{value:document.visibilityState};default:return null}}}const Rb=32*1024;const Sb=8*Rb;const Tb=6;class Ub{constructor(sC){this.client=sC;this.enabled=false;this.nextId=1;this.uploads=new Map}upload(tC,uC){if(!this.enabled||!this.client.connected){return 0}const vC=this.nextId++;const wC={id:vC,hid:tC,file:uC,sent:0,acked:0,ended:false,pumping:false};this.uploads.set(vC,wC);this._send(this.client.codec.encodeBlob(aa.START,vC,{hid:tC,filename:uC.name,type:uC.type,size:uC.size}));this._dispatch(wC,"progress");this._pump(wC);return vC}cancel(xC){const yC=this.uploads.get(xC);if(!yC)return;this.uploads.delete(xC);this._send(this.client.codec.encodeBlob(aa.CANCEL,xC));this._dispatch(yC,"error","canceled")}handleAck(zC){const{id:AC,received:BC,status:CC,message:DC}=this.client.codec.decodeBlobAck(zC);const EC=this.uploads.get(AC);if(!EC)return;if(BC>EC.acked){EC.acked=BC}switch(CC){case ba.PROGRESS:this._dispatch(EC,"progress");this._pump(EC);break;case ba.DONE:this.uploads.delete(AC);this._dispatch(EC,"done");break;case ba.ERROR:this.uploads.delete(AC);this._dispatch(EC,"error",DC);break}}reset(){const FC=[...this.uploads.values()];this.uploads.clear();for(const GC of FC){this._dispatch(GC,"error","disconnected")}}async _pump(HC){if(HC.pumping)return;HC.pumping=true;try{while(this.uploads.get(HC.id)===HC&&HC.sent<HC.file.size){const IC=Math.min(HC.sent+Rb,HC.file.size);if(IC-HC.acked>Sb){return}const JC=new Uint8Array(await HC.file.slice(HC.sent,IC).arrayBuffer());if(this.uploads.get(HC.id)!==HC){return}this._send(this.client.codec.encodeBlob(aa.CHUNK,HC.id,{offset:HC.sent,data:JC}));HC.sent=IC}if(this.uploads.get(HC.id)===HC&&!HC.ended){HC.ended=true;this._send(this.client.codec.encodeBlob(aa.END,HC.id))}}catch(err){if(this.uploads.get(HC.id)===HC){this.cancel(HC.id)}this.client._onError(err)}finally{HC.pumping=false}}_send(KC){for(const LC of this.client.codec.encodeFrames(Tb,KC)){this.client.wsManager.send(LC)}}_dispatch(MC,NC,OC){const PC=this.client.nodeMap.get(MC.hid);if(!PC)return;PC.dispatchEvent(new CustomEvent("vango:upload",{detail:{id:MC.id,name:MC.file.name,size:MC.file.size,loaded:MC.acked,status:NC,error:OC||null},bubbles:true}))}}const Vb="G";const Wb="vango-inspector";function Xb(QC){const RC=new Map;const SC=new Map;const TC=new Map;const UC=new Map;const VC=[];for(const WC of QC.nodes||[]){RC.set(WC.id,WC)}for(const XC of QC.nodes||[]){if(XC.owner&&RC.has(XC.owner)){if(!SC.has(XC.owner))SC.set(XC.owner,[]);SC.get(XC.owner).push(XC)}else{VC.push(XC)}}for(const YC of QC.edges||[]){if(!TC.has(YC.to))TC.set(YC.to,[]);TC.get(YC.to).push(YC.from);if(!UC.has(YC.from))UC.set(YC.from,[]);UC.get(YC.from).push(YC.to)}return{byId:RC,children:SC,deps:TC,subs:UC,roots:VC}}function Yb(ZC){let _C=`${ZC.kind} #${ZC.id}`;if(ZC.label)_C+=` ${ZC.label}`;if(ZC.hid)_C+=` [${ZC.hid}]`;if(ZC.scope)_C+=` (${ZC.scope})`;if(ZC.persistKey)_C+=` key=${ZC.persistKey}`;if(ZC.value!==undefined&&ZC.kind!=="owner"&&ZC.kind!=="component"){_C+=` = ${ZC.value}`}_C+=` runs=${ZC.runs}`;if(ZC.disposed)_C+=" disposed";return _C}class Zb{constructor($C){this.client=$C;this.panel=null;this.highlighted=null;this._onKeyDown=aD=>{if(aD.altKey&&aD.shiftKey&&aD.key.toUpperCase()===Vb){aD.preventDefault();this.toggle()}}}attach(){document.addEventListener("keydown",this._onKeyDown)}detach(){document.removeEventListener("keydown",this._onKeyDown);this.close()}toggle(){if(this.panel){this.close()}else{this.open()}}async open(){if(!this.panel){this.panel=document.createElement("div");this.panel.id=Wb;this.panel.style.cssText="position:fixed;right:0;top:0;bottom:0;width:420px;overflow:auto;"+"z-index:2147483647;background:#1e1e1e;color:#ddd;font:12px/1.5 monospace;padding:8px;";document.body.appendChild(this.panel)}await this.refresh()}close(){this._highlight(null);if(this.panel){this.panel.remove();this.panel=null}}async refresh(){const bD=this.client.wsManager.sessionId;if(!bD){this._renderMessage("Not connected");return}try{const cD=await fetch(`/_vango/inspect?session=${encodeURIComponent(bD)}`,{cache:"no-store"});if(!cD.ok){this._renderMessage(`Inspector unavailable (${cD.status}); it requires DevMode`);return}this.render(await cD.json())}catch(err){this._renderMessage(`Inspector request failed: ${err.message}`)}}render(dD){if(!this.panel)return;const eD=Xb(dD);this.panel.textContent="";const fD=document.createElement("div");fD.textContent=`${dD.nodes.length} nodes, ${dD.edges.length} edges `;const gD=document.createElement("button");gD.textContent="Refresh";gD.addEventListener("click",()=>this.refresh());fD.appendChild(gD);this.panel.appendChild(fD);const hD=document.createElement("ul");hD.style.cssText="margin:0;padding-left:12px;list-style:none;";for(const iD of eD.roots){hD.appendChild(this._renderNode(iD,eD))}this.panel.appendChild(hD)}_renderNode(jD,kD){const lD=document.createElement("li");const mD=document.createElement("div");mD.textContent=Yb(jD);mD.dataset.nodeId=jD.id;const nD=[];const oD=kD.deps.get(jD.id);if(oD)nD.push(`← ${oD.map(rD=>"#"+rD).join(" ")}`);const pD=kD.subs.get(jD.id);if(pD)nD.push(`→ ${pD.map(sD=>"#"+sD).join(" ")}`);if(jD.subscribers>(pD?pD.length:0)){nD.push(`${jD.subscribers} subscriber(s) total`)}if(nD.length>0){const tD=document.createElement("div");tD.style.cssText="color:#888;padding-left:12px;";tD.textContent=nD.join("  ");mD.appendChild(tD)}if(jD.hid){mD.addEventListener("mouseenter",()=>this._highlight(this.client.nodeMap.get(jD.hid)));mD.addEventListener("mouseleave",()=>this._highlight(null))}lD.appendChild(mD);const qD=kD.children.get(jD.id);if(qD){const uD=document.createElement("ul");uD.style.cssText="margin:0;padding-left:12px;list-style:none;";for(const vD of qD){uD.appendChild(this._renderNode(vD,kD))}lD.appendChild(uD)}return lD}_renderMessage(wD){if(!this.panel)return;this.panel.textContent=wD}_highlight(xD){if(this.highlighted){this.highlighted.style.outline=this.highlighted.__vangoOutline||"";this.highlighted=null}if(xD){xD.__vangoOutline=xD.style.outline;xD.style.outline="2px solid #e91e63";this.highlighted=xD}}}const _b={HANDSHAKE:0,EVENT:1,PATCHES:2,CONTROL:3,ACK:4,ERROR:5,BLOB:6};const $b=65535;const ac={PING:1,PONG:2,RESYNC_REQUEST:16,RESYNC_PATCHES:17,RESYNC_FULL:18,HOOK_REVERT:48,AUTH_COMMAND:49,BLOB_ACK:64,CLIENT_ENV:65,PREF_SYNC:66,GLOBAL_LISTEN:67,GLOBAL_UNLISTEN:68,HOOK_CALL:69,HOOK_RESULT:70,CLOSE:32};const cc={FORCE_RELOAD:1,HARD_NAVIGATE:2,BROADCAST:3};class dc{constructor(yD={}){this.options={wsUrl:yD.wsUrl||this._defaultWsUrl(),reconnect:yD.reconnect!==false,reconnectInterval:yD.reconnectInterval||1000,reconnectMaxInterval:yD.reconnectMaxInterval||30000,heartbeatInterval:yD.heartbeatInterval||30000,debug:yD.debug||false,...yD};this.codec=new oa;this.decompressor=null;this.reassembler=new na(yD.maxMessageSize);this.nodeMap=new Map;this.connected=false;this.seq=0;this.patchSeq=0;this.expectedPatchSeq=1;this.pendingResync=false;this.protocolVersion=W;this.wsManager=new Aa(this,this.options);this.patchApplier=new fb(this);this.eventCapture=new Ia(this);this.optimistic=new gb(this);this.hooks=new yb(this);this.islands=new Ab(this);this.uploads=new Ub(this);this.env=new xa(this,yD);this.connection=new Db({toastOnReconnect:yD.toastOnReconnect||window.__VANGO_TOAST_ON_RECONNECT__,toastMessage:yD.toastMessage||"Connection restored",maxRetries:yD.maxRetries||10,baseDelay:yD.reconnectInterval||1000,maxDelay:yD.reconnectMaxInterval||30000,debug:yD.debug});this.urlManager=new Fb(this,{debug:yD.debug});this.prefs=new Hb(this,{debug:yD.debug});this.globalEvents=new Qb(this,{debug:yD.debug});this.inspector=this.options.debug?new Zb(this):null;this._authChannel="vango:auth";this._authBroadcast=null;this._authStorageHandler=null;this._authReloadScheduled=false;this._authPollUrl=yD.authPollUrl||"";this._authPollInterval=yD.authPollInterval||30000;this._authPollTimer=null;this.onConnect=yD.onConnect||(()=>{});this.onDisconnect=yD.onDisconnect||(()=>{});this.onError=yD.onError||(zD=>console.error("[Vango]",zD));qb();Eb();this._buildNodeMap();this._initAuthBroadcast();this.wsManager.connect(this.options.wsUrl);this.eventCapture.attach();this.env.attach();this.hooks.initializeFromDOM();this.islands.initializeFromDOM();if(this.inspector){this.inspector.attach()}}_buildNodeMap(){Oa(document);document.querySelectorAll("[data-hid]").forEach(AD=>{const BD=AD.dataset.hid;this.nodeMap.set(BD,AD)});if(this.options.debug){console.log(`[Vango] Mapped ${this.nodeMap.size} nodes`)}}_defaultWsUrl(){const CD=location.protocol==="https:"?"wss:":"ws:";const DD=encodeURIComponent(location.pathname+location.search);return`${CD}//${location.host}/_vango/live?path=${DD}`}_onConnected(){this.connected=true;this.globalEvents.reset();this.connection.onConnect();this.onConnect()}_onDisconnected(){this.connected=false;this.uploads.reset();this.connection.onDisconnect();this.onDisconnect()}_onError(ED){this.onError(ED)}_handleProtocolError(FD,GD){const HD=FD instanceof Error?FD.message:String(FD);if(this.options.debug){console.error("[Vango] Protocol error:",GD||"decode failure",FD)}else{console.error("[Vango] Protocol error:",GD||"decode failure",HD)}const ID=FD instanceof Error?FD:new Error(HD);this._onError(ID);this.wsManager.close();this._triggerSelfHeal()}_triggerSelfHeal(){var JD;const KD=(JD=this.eventCapture)==null?void 0:JD.pendingNavPath;if(KD){console.log("[Vango] Self-heal: navigating to pending path:",KD);location.assign(KD)}else{console.log("[Vango] Self-heal: reloading page");location.reload()}}retryConnect(){this.wsManager.resetReconnect();this.connection.setState(Bb.CONNECTING);this.wsManager.connect(this.options.wsUrl)}_handleBinaryMessage(LD){if(LD.length<4){this._handleProtocolError(new Error("Frame header too short"),"frame header");return}const MD=LD[0];const ND=LD[2]<<8|LD[3];if(ND>$b){this._handleProtocolError(new Error("Frame payload exceeds max size"),"frame header");return}if(LD.length!==4+ND){this._handleProtocolError(new Error("Frame length mismatch"),"frame header");return}let OD=LD.slice(4,4+ND);try{const PD=this.reassembler.add(MD,LD[1],OD);if(!PD){return}OD=PD.payload;if(PD.flags&Z.COMPRESSED){OD=this.codec.decompressPayload(OD,this.decompressor,this.reassembler.maxSize)}switch(MD){case _b.PATCHES:this._handlePatches(OD);break;case _b.CONTROL:this._handleControl(OD);break;case _b.ERROR:this._handleServerError(OD);break;default:if(this.options.debug){console.warn("[Vango] Unknown frame type:",MD)}}}catch(err){this._handleProtocolError(err,`frame type ${MD}`)}}_handlePatches(QD){if(this.options.debug){console.log("[Vango] Received patches buffer, length:",QD.length)}const{seq:RD,patches:SD}=this.codec.decodePatches(QD);if(this.options.debug){console.log("[Vango] Decoded",SD.length,"patches, seq:",RD)}if(RD<this.expectedPatchSeq){if(this.options.debug){console.log("[Vango] Ignoring duplicate patch seq:",RD,"expected:",this.expectedPatchSeq)}return}if(RD>this.expectedPatchSeq){console.warn("[Vango] Patch sequence gap detected","expected:",this.expectedPatchSeq,"received:",RD);this._requestResync(this.patchSeq);return}if(this.options.debug){for(const TD of SD){console.log("[Vango] Patch:",TD)}console.log("[Vango] Applying",SD.length,"patches (seq:",RD,")")}this.optimistic.clearPending();this.patchApplier.apply(SD);this.hooks.updateFromDOM();this.islands.updateFromDOM();this.patchSeq=RD;this.expectedPatchSeq=RD+1;this.pendingResync=false;this.wsManager.updateLastSeq(this.patchSeq);this._sendAck(RD)}_handleControl(UD){if(UD.length===0){throw new Error("Control frame empty")}const VD=UD[0];switch(VD){case ac.PONG:if(this.options.debug){console.log("[Vango] Pong received")}break;case ac.RESYNC_FULL:this._handleResyncFull(UD.slice(1));break;case ac.HOOK_REVERT:{const{value:WD}=this.codec.decodeString(UD,1);this.hooks.revert(WD);break}case ac.AUTH_COMMAND:this._handleAuthCommand(UD.slice(1));break;case ac.BLOB_ACK:this.uploads.handleAck(UD.subarray(1));break;case ac.PREF_SYNC:this.prefs.handleSync(this.codec.decodePrefSync(UD,1));break;case ac.GLOBAL_LISTEN:this.globalEvents.listen(this.codec.decodeGlobalListen(UD,1));break;case ac.GLOBAL_UNLISTEN:{const{value:XD}=this.codec.decodeString(UD,1);this.globalEvents.unlisten(XD);break}case ac.HOOK_CALL:this._handleHookCall(this.codec.decodeHookCall(UD,1));break;case ac.CLOSE:this.wsManager.close();break;default:if(this.options.debug){console.log("[Vango] Unknown control type:",VD)}}}async _handleHookCall(YD){let ZD=null;let _D="";try{ZD=await this.hooks.call(YD.hid,YD.method,YD.args)}catch(err){_D=(err==null?void 0:err.message)||String(err)}if(!this.connected)return;const $D=this.codec.encodeHookResult(YD.id,ZD,_D);for(const aE of this.codec.encodeFrames(_b.CONTROL,$D)){this.wsManager.send(aE)}if(this.options.debug){console.log("[Vango] Hook call:",YD.hid,YD.method,_D||ZD)}}_handleAuthCommand(bE){if(bE.length<2){return}const cE=bE[0];const dE=bE[1];let eE=2;const fE=()=>{if(eE>=bE.length){return""}const{value:jE,bytesRead:kE}=this.codec.decodeString(bE,eE);eE+=kE;return jE};const gE=fE();const hE=fE()||"vango:auth";const iE=fE()||"expired";switch(cE){case cc.FORCE_RELOAD:this._broadcastAuth(hE,iE,dE);this.wsManager.clearResumeInfo();setTimeout(()=>location.reload(),0);break;case cc.HARD_NAVIGATE:{this._broadcastAuth(hE,iE,dE);this.wsManager.clearResumeInfo();const lE=gE||location.pathname;setTimeout(()=>location.assign(lE),0);break}case cc.BROADCAST:this._broadcastAuth(hE,iE,dE);break;default:if(this.options.debug){console.log("[Vango] Unknown auth action:",cE)}}}_initAuthBroadcast(){if(typeof BroadcastChannel!=="undefined"){this._authBroadcast=new BroadcastChannel(this._authChannel);this._authBroadcast.onmessage=mE=>{this._handleAuthBroadcast(mE.data)};return}if(!this._canUseLocalStorage()){this._startAuthPolling();return}if(typeof window==="undefined"||!window.addEventListener){this._startAuthPolling();return}this._authStorageHandler=nE=>{if(!nE||!nE.key||nE.key!==`__vango_auth_${this._authChannel}`){return}if(!nE.newValue){return}try{const oE=JSON.parse(nE.newValue);this._handleAuthBroadcast(oE.payload)}catch(_e){}};window.addEventListener("storage",this._authStorageHandler)}_handleAuthBroadcast(pE){if(!pE||pE.type!=="expired"&&pE.type!=="logout"){return}this._scheduleAuthReload(pE)}_scheduleAuthReload(qE){if(this._authReloadScheduled){return}this._authReloadScheduled=true;if(this.options.debug){console.log("[Vango] Auth reload scheduled:",qE||{})}setTimeout(()=>location.reload(),0)}_startAuthPolling(){if(!this._authPollUrl||this._authPollInterval<=0){return}if(this._authPollTimer){return}const rE=async()=>{try{const sE=await fetch(this._authPollUrl,{method:"GET",credentials:"same-origin",cache:"no-store",headers:{"X-Vango-Auth-Poll":"1"}});if(sE.status===401){this._scheduleAuthReload({type:"expired",reason:"poll"})}}catch(_e2){}};rE();this._authPollTimer=setInterval(rE,this._authPollInterval)}_stopAuthPolling(){if(!this._authPollTimer){return}clearInterval(this._authPollTimer);this._authPollTimer=null}_canUseLocalStorage(){try{if(typeof localStorage==="undefined"){return false}const tE="__vango_auth_probe__";localStorage.setItem(tE,"1");localStorage.removeItem(tE);return true}catch(_e3){return false}}_broadcastAuth(uE,vE,wE){const xE={type:vE,reason:wE};if(typeof BroadcastChannel!=="undefined"){const yE=new BroadcastChannel(uE);yE.postMessage(xE);yE.close();return}try{if(typeof localStorage==="undefined"){return}const zE=`__vango_auth_${uE}`;const AE=JSON.stringify({payload:xE,ts:Date.now()});localStorage.setItem(zE,AE);localStorage.removeItem(zE)}catch(_e4){}}_handleResyncFull(BE){if(BE.length===0){throw new Error("ResyncFull payload empty")}const{value:CE}=this.codec.decodeString(BE,0);if(this.options.debug){console.log("[Vango] ResyncFull received, replacing body content")}const DE=document.createElement("template");DE.innerHTML=CE;this.nodeMap.clear();this.hooks.destroyAll();this.islands.destroyAll();document.body.innerHTML="";while(DE.content.firstChild){document.body.appendChild(DE.content.firstChild)}this._buildNodeMap();this.hooks.initializeFromDOM();this.islands.initializeFromDOM();this.patchSeq=0;this.expectedPatchSeq=1;this.pendingResync=false;this.wsManager.updateLastSeq(0)}_sendAck(EE){if(!this.connected)return;const FE=this.codec.encodeAck(EE,100);const GE=this._encodeFrame(_b.ACK,FE);this.wsManager.send(GE);if(this.options.debug){console.log("[Vango] Sent ACK for seq:",EE)}}_requestResync(HE){if(this.pendingResync){if(this.options.debug){console.log("[Vango] Resync already pending, skipping")}return}this.pendingResync=true;if(this.options.debug){console.log("[Vango] Requesting resync from seq:",HE)}const IE=this.codec.encodeResyncRequest(HE);const JE=this._encodeFrame(_b.CONTROL,IE);this.wsManager.send(JE)}_handleServerError(KE){if(KE.length<3){throw new Error("Error frame too short")}const LE=KE[0]<<8|KE[1];const{value:ME,bytesRead:NE}=this.codec.decodeString(KE,2);const OE=2+NE;const PE=OE<KE.length?KE[OE]===1:false;const QE={0:"Unknown error",1:"Invalid frame",2:"Invalid event",3:"Handler not found",4:"Handler panic",5:"Session expired",6:"Rate limited",256:"Server error",257:"Not authorized",258:"Not found",259:"Validation failed",260:"Route error"};const RE=QE[LE]||ME||`Unknown error: ${LE}`;const SE=new Error(RE);SE.code=LE;SE.fatal=PE;this.onError(SE);this.optimistic.revertAll();if(PE){this.wsManager.close()}}sendEvent(TE,UE,VE=null){if(!this.connected){if(this.options.debug){console.log("[Vango] Not connected, queueing event")}}this.seq++;const WE=this.codec.encodeEvent(this.seq,TE,UE,VE);const XE=this.codec.encodeFrames(_b.EVENT,WE);if(XE.length>1&&!Y(this.protocolVersion,2,1)){this.onError(new Error(`Event of ${WE.length} bytes is too large for protocol ${this.protocolVersion.major}.${this.protocolVersion.minor}`));return}for(const YE of XE){this.wsManager.send(YE)}if(this.options.debug){console.log("[Vango] Sent event:",{type:TE,hid:UE,data:VE,seq:this.seq})}}_encodeFrame(ZE,_E){const $E=_E.length;const aF=new Uint8Array(4+$E);aF[0]=ZE;aF[1]=0;aF[2]=$E>>8&255;aF[3]=$E&255;aF.set(_E,4);return aF}sendHookEvent(bF,cF,dF={}){this.sendEvent(R.HOOK,bF,{name:cF,data:dF})}sendIslandMessage(eF,fF={}){const gF=this.codec.encodeEvent(this.seq+1,R.ISLAND,eF,{data:fF}).length;if(gF>$b){console.error("[Vango] Island message exceeds max frame size, dropped:",eF);return false}this.sendEvent(R.ISLAND,eF,{data:fF});return true}registerIsland(hF,iF){this.islands.register(hF,iF)}getNode(jF){return this.nodeMap.get(jF)}registerNode(kF,lF){this.nodeMap.set(kF,lF)}unregisterNode(mF){this.nodeMap.delete(mF)}registerHook(nF,oF){this.hooks.register(nF,oF)}registerPref(pF,qF,rF={}){return this.prefs.register(pF,qF,rF)}destroy(){this.eventCapture.detach();this.hooks.destroyAll();this.islands.destroyAll();this.prefs.destroy();this.globalEvents.reset();if(this._authBroadcast){this._authBroadcast.close();this._authBroadcast=null}if(this._authStorageHandler&&typeof window!=="undefined"){window.removeEventListener("storage",this._authStorageHandler)}this._stopAuthPolling();this.wsManager.close()}}function ec(){if(window.__vango__){return}const sF=document.currentScript||document.querySelector("script[data-vango]");const tF={};if(sF){if(sF.dataset.wsUrl)tF.wsUrl=sF.dataset.wsUrl;if(sF.dataset.debug)tF.debug=sF.dataset.debug==="true"}window.__vango__=new dc(tF)}if(typeof document!=="undefined"){if(document.readyState==="loading"){document.addEventListener("DOMContentLoaded",ec)}else{ec()}}var fc=dc;return z(A)})();
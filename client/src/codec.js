@@ -5,13 +5,14 @@
  * that matches the Go server implementation in pkg/protocol/.
  */
 
-import { hidToInt, intToHid, concat } from './utils.js';
+import { hidToInt, intToHid, concat, formValueString } from './utils.js';
+import { Inflater } from './inflate.js';
 
 /**
  * Event type constants - must match pkg/protocol/event.go
  */
 export const EventType = {
-    // Mouse events (0x01-0x07)
+    // Mouse events (0x01-0x09)
     CLICK: 0x01,
     DBLCLICK: 0x02,
     MOUSEDOWN: 0x03,
@@ -19,6 +20,7 @@ export const EventType = {
     MOUSEMOVE: 0x05,
     MOUSEENTER: 0x06,
     MOUSELEAVE: 0x07,
+    CONTEXTMENU: 0x09,
 
     // Form events (0x10-0x14)
     INPUT: 0x10,
@@ -41,14 +43,59 @@ export const EventType = {
     TOUCHMOVE: 0x41,
     TOUCHEND: 0x42,
 
-    // Drag events (0x50-0x52)
+    // Drag events (0x50-0x52, 0x5B-0x5E)
     DRAGSTART: 0x50,
     DRAGEND: 0x51,
     DROP: 0x52,
+    DRAGENTER: 0x5B,
+    DRAGOVER: 0x5C,
+    DRAGLEAVE: 0x5D,
+    DRAG: 0x5E,
 
     // Special events (0x60+)
     HOOK: 0x60,
+    ISLAND: 0x61,
     NAVIGATE: 0x70,
+
+    // Pointer events (0x80-0x85)
+    POINTERDOWN: 0x80,
+    POINTERUP: 0x81,
+    POINTERMOVE: 0x82,
+    POINTERENTER: 0x83,
+    POINTERLEAVE: 0x84,
+    POINTERCANCEL: 0x85,
+
+    // Clipboard events (0x90-0x92)
+    COPY: 0x90,
+    CUT: 0x91,
+    PASTE: 0x92,
+
+    // Toggle event (0x98)
+    TOGGLE: 0x98,
+
+    // Media events (0xA0-0xAE)
+    PLAY: 0xA0,
+    PAUSE: 0xA1,
+    ENDED: 0xA2,
+    TIMEUPDATE: 0xA3,
+    SEEKING: 0xA4,
+    SEEKED: 0xA5,
+    VOLUMECHANGE: 0xA6,
+    RATECHANGE: 0xA7,
+    DURATIONCHANGE: 0xA8,
+    LOADEDMETADATA: 0xA9,
+    LOADEDDATA: 0xAA,
+    CANPLAY: 0xAB,
+    CANPLAYTHROUGH: 0xAC,
+    WAITING: 0xAD,
+    PLAYING: 0xAE,
+
+    // Page events (0xB0-0xB3), for window and document subscriptions
+    VISIBILITYCHANGE: 0xB0,
+    ONLINE: 0xB1,
+    OFFLINE: 0xB2,
+    BEFOREUNLOAD: 0xB3,
+
     CUSTOM: 0xFF,
 };
 
@@ -83,6 +130,12 @@ export const PatchType = {
     // Navigation operations (full route navigation)
     NAV_PUSH: 0x32,
     NAV_REPLACE: 0x33,
+    // Document head operations (route metadata)
+    SET_TITLE: 0x34,
+    SET_META: 0x35,
+    SET_LINK: 0x36,
+    // Island operations (JS islands)
+    ISLAND_MESSAGE: 0x40,
 };
 
 /**
@@ -95,6 +148,65 @@ export const KeyMod = {
     META: 0x08,
 };
 
+/**
+ * Payload compression codecs - must match pkg/protocol/compress.go
+ */
+export const Codec = {
+    NONE: 0x00,
+    DEFLATE: 0x01,
+};
+
+/**
+ * Codecs this client can decode, most preferred first
+ */
+export const SupportedCodecs = [Codec.DEFLATE];
+
+/**
+ * Protocol version this client speaks - must match pkg/protocol/version.go
+ * CurrentVersion. Servers that do not report a version speak 2.0.
+ */
+export const ProtocolVersion = { major: 2, minor: 2 };
+const LegacyVersion = { major: 2, minor: 0 };
+
+/**
+ * Whether a negotiated version is at least major.minor
+ */
+export function versionAtLeast(version, major, minor) {
+    return version.major > major || (version.major === major && version.minor >= minor);
+}
+
+/**
+ * Frame flags - must match pkg/protocol/frame.go
+ */
+export const FrameFlags = {
+    COMPRESSED: 0x01,
+    FINAL: 0x04,     // Last fragment of a fragmented message
+    FRAGMENT: 0x10,  // Part of a message larger than one frame
+};
+
+// ServerHello capability flags; the negotiated codec is in the high byte
+const ServerFlagCompression = 0x0001;
+export const ServerFlagBinaryBlobs = 0x0002;
+
+/**
+ * Blob upload operations - must match pkg/protocol/blob.go
+ */
+export const BlobOp = {
+    START: 0x01,
+    CHUNK: 0x02,
+    END: 0x03,
+    CANCEL: 0x04,
+};
+
+/**
+ * BlobAck statuses - must match pkg/protocol/blob.go
+ */
+export const BlobStatus = {
+    PROGRESS: 0x00,
+    DONE: 0x01,
+    ERROR: 0x02,
+};
+
 /**
  * VNode type constants for wire format
  */
@@ -102,6 +214,7 @@ const VNodeType = {
     ELEMENT: 0x01,
     TEXT: 0x02,
     FRAGMENT: 0x03,
+    PORTAL: 0x06,
 };
 
 /**
@@ -126,8 +239,94 @@ const HardMaxAllocation = 16 * 1024 * 1024;
 const MaxCollectionCount = 100000;
 const MaxVNodeDepth = 256;
 const MaxPatchDepth = 128;
+const MaxHookDepth = 64;
 const MaxFramePayload = 0xFFFF;
 
+/**
+ * Default cap on bytes buffered for partially received messages
+ * (matches protocol.DefaultMaxMessageSize)
+ */
+export const DefaultMaxMessageSize = DefaultMaxAllocation;
+
+/**
+ * Joins fragmented frames back into whole messages.
+ * Must match protocol.Reassembler in pkg/protocol/fragment.go
+ *
+ * Each frame type has its own partial message, so a ping can arrive between
+ * the fragments of a large patch frame. Total buffered bytes are capped.
+ */
+export class FrameReassembler {
+    constructor(maxSize = DefaultMaxMessageSize) {
+        this.maxSize = maxSize;
+        this.partial = new Map(); // frameType -> { flags, chunks }
+        this.buffered = 0;
+    }
+
+    /**
+     * Add a received frame.
+     * Returns { flags, payload } for a complete message, or null while
+     * waiting for more fragments. Fragment flags are cleared on the result.
+     */
+    add(frameType, flags, payload) {
+        const partial = this.partial.get(frameType);
+
+        if (!(flags & FrameFlags.FRAGMENT)) {
+            if (partial) {
+                this._discard(frameType);
+                throw new Error('Protocol decode: unfragmented frame inside a fragmented message');
+            }
+            return { flags, payload };
+        }
+
+        const messageFlags = flags & ~(FrameFlags.FRAGMENT | FrameFlags.FINAL);
+        let message = partial;
+        if (!message) {
+            message = { flags: messageFlags, chunks: [], length: 0 };
+            this.partial.set(frameType, message);
+        } else if (message.flags !== messageFlags) {
+            this._discard(frameType);
+            throw new Error('Protocol decode: fragment flags do not match message');
+        }
+
+        if (this.buffered + payload.length > this.maxSize) {
+            this._discard(frameType);
+            throw new Error('Protocol decode: reassembled message too large');
+        }
+        message.chunks.push(payload);
+        message.length += payload.length;
+        this.buffered += payload.length;
+
+        if (!(flags & FrameFlags.FINAL)) {
+            return null;
+        }
+
+        this._discard(frameType);
+        const out = new Uint8Array(message.length);
+        let offset = 0;
+        for (const chunk of message.chunks) {
+            out.set(chunk, offset);
+            offset += chunk.length;
+        }
+        return { flags: message.flags, payload: out };
+    }
+
+    /**
+     * Drop all partial messages (new connection)
+     */
+    reset() {
+        this.partial.clear();
+        this.buffered = 0;
+    }
+
+    _discard(frameType) {
+        const message = this.partial.get(frameType);
+        if (message) {
+            this.buffered -= message.length;
+            this.partial.delete(frameType);
+        }
+    }
+}
+
 /**
  * Binary codec for Vango protocol
  */
@@ -157,6 +356,7 @@ export class BinaryCodec {
         switch (type) {
             case EventType.INPUT:
             case EventType.CHANGE:
+            case EventType.VISIBILITYCHANGE:
                 // String value
                 parts.push(this.encodeString(data?.value || ''));
                 break;
@@ -175,9 +375,47 @@ export class BinaryCodec {
             case EventType.MOUSEDOWN:
             case EventType.MOUSEUP:
             case EventType.MOUSEMOVE:
+            case EventType.CONTEXTMENU:
                 this.encodeMouseEvent(parts, data);
                 break;
 
+            case EventType.POINTERDOWN:
+            case EventType.POINTERUP:
+            case EventType.POINTERMOVE:
+            case EventType.POINTERENTER:
+            case EventType.POINTERLEAVE:
+            case EventType.POINTERCANCEL:
+                this.encodePointerEvent(parts, data);
+                break;
+
+            case EventType.PLAY:
+            case EventType.PAUSE:
+            case EventType.ENDED:
+            case EventType.TIMEUPDATE:
+            case EventType.SEEKING:
+            case EventType.SEEKED:
+            case EventType.VOLUMECHANGE:
+            case EventType.RATECHANGE:
+            case EventType.DURATIONCHANGE:
+            case EventType.LOADEDMETADATA:
+            case EventType.LOADEDDATA:
+            case EventType.CANPLAY:
+            case EventType.CANPLAYTHROUGH:
+            case EventType.WAITING:
+            case EventType.PLAYING:
+                this.encodeMediaEvent(parts, data);
+                break;
+
+            case EventType.COPY:
+            case EventType.CUT:
+            case EventType.PASTE:
+                this.encodeClipboardEvent(parts, data);
+                break;
+
+            case EventType.TOGGLE:
+                parts.push(new Uint8Array([data?.open ? 1 : 0]));
+                break;
+
             case EventType.SCROLL:
                 this.encodeScrollEvent(parts, data);
                 break;
@@ -195,6 +433,10 @@ export class BinaryCodec {
             case EventType.DRAGSTART:
             case EventType.DRAGEND:
             case EventType.DROP:
+            case EventType.DRAGENTER:
+            case EventType.DRAGOVER:
+            case EventType.DRAGLEAVE:
+            case EventType.DRAG:
                 this.encodeDragEvent(parts, data);
                 break;
 
@@ -202,6 +444,10 @@ export class BinaryCodec {
                 this.encodeHookEvent(parts, data);
                 break;
 
+            case EventType.ISLAND:
+                this.encodeHookData(parts, data?.data || {});
+                break;
+
             case EventType.NAVIGATE:
                 parts.push(this.encodeString(data?.path || ''));
                 parts.push(new Uint8Array([data?.replace ? 1 : 0]));
@@ -417,6 +663,46 @@ export class BinaryCodec {
                 break;
             }
 
+            case PatchType.SET_TITLE: {
+                const { value: title, bytesRead: titleBytes } = this.decodeString(buffer, offset);
+                offset += titleBytes;
+                patch.value = title;
+                break;
+            }
+
+            case PatchType.SET_META: {
+                // Wire format: [0x35][hid:string][attr:string][key:string][content:string]
+                const { value: attr, bytesRead: attrBytes } = this.decodeString(buffer, offset);
+                offset += attrBytes;
+                const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
+                offset += keyBytes;
+                const { value: content, bytesRead: contentBytes } = this.decodeString(buffer, offset);
+                offset += contentBytes;
+                patch.attr = attr;
+                patch.key = key;
+                patch.value = content;
+                break;
+            }
+
+            case PatchType.SET_LINK: {
+                // Wire format: [0x36][hid:string][rel:string][href:string]
+                const { value: rel, bytesRead: relBytes } = this.decodeString(buffer, offset);
+                offset += relBytes;
+                const { value: href, bytesRead: hrefBytes } = this.decodeString(buffer, offset);
+                offset += hrefBytes;
+                patch.key = rel;
+                patch.value = href;
+                break;
+            }
+
+            case PatchType.ISLAND_MESSAGE: {
+                // Wire format: [0x40][id:string][data:hookdata]
+                const { value: data, bytesRead: dataBytes } = this.decodeHookData(buffer, offset);
+                offset += dataBytes;
+                patch.data = data;
+                break;
+            }
+
             default:
                 throw new Error(`Protocol decode: unknown patch type ${patch.type}`);
         }
@@ -505,6 +791,30 @@ export class BinaryCodec {
                 break;
             }
 
+            case VNodeType.PORTAL: {
+                vnode.type = 'portal';
+
+                // Target selector
+                const { value: target, bytesRead: targetBytes } = this.decodeString(buffer, offset);
+                vnode.target = target;
+                offset += targetBytes;
+
+                const { value: hid, bytesRead: hidBytes } = this.decodeString(buffer, offset);
+                vnode.hid = hid || null;
+                offset += hidBytes;
+
+                const { value: childCount, bytesRead: countBytes } = this.decodeCollectionCount(buffer, offset);
+                offset += countBytes;
+                vnode.children = [];
+
+                for (let i = 0; i < childCount; i++) {
+                    const { vnode: child, bytesRead: childBytes } = this.decodeVNode(buffer, offset, depth + 1);
+                    vnode.children.push(child);
+                    offset += childBytes;
+                }
+                break;
+            }
+
             default:
                 throw new Error(`Protocol decode: unknown vnode type ${nodeType}`);
         }
@@ -644,14 +954,18 @@ export class BinaryCodec {
             return;
         }
 
+        // Repeated values are encoded as repeated (key, value) pairs
         const entries = [];
         if (formData instanceof FormData) {
             for (const [key, value] of formData.entries()) {
-                entries.push({ key, value: String(value) });
+                entries.push({ key, value: formValueString(value) });
             }
         } else if (typeof formData === 'object') {
             for (const [key, value] of Object.entries(formData)) {
-                entries.push({ key, value: String(value) });
+                const values = Array.isArray(value) ? value : [value];
+                for (const v of values) {
+                    entries.push({ key, value: formValueString(v) });
+                }
             }
         }
 
@@ -776,11 +1090,28 @@ export class BinaryCodec {
     }
 
     /**
-     * Encode drag event
+     * Encode pointer event: the mouse fields followed by pointer details
+     */
+    encodePointerEvent(parts, data) {
+        this.encodeMouseEvent(parts, data);
+        parts.push(this.encodeSvarint(data?.pointerId || 0));
+        parts.push(this.encodeString(data?.pointerType || ''));
+        parts.push(this.encodeFloat64(data?.pressure || 0));
+        parts.push(this.encodeFloat64(data?.width || 0));
+        parts.push(this.encodeFloat64(data?.height || 0));
+        parts.push(this.encodeSvarint(data?.tiltX || 0));
+        parts.push(this.encodeSvarint(data?.tiltY || 0));
+        parts.push(new Uint8Array([data?.isPrimary ? 1 : 0]));
+    }
+
+    /**
+     * Encode drag event: position, modifiers and the data transfer
      */
     encodeDragEvent(parts, data) {
         parts.push(this.encodeSvarint(data?.clientX || 0));
         parts.push(this.encodeSvarint(data?.clientY || 0));
+        parts.push(this.encodeSvarint(data?.pageX || 0));
+        parts.push(this.encodeSvarint(data?.pageY || 0));
 
         let modifiers = 0;
         if (data?.ctrlKey) modifiers |= KeyMod.CTRL;
@@ -788,6 +1119,60 @@ export class BinaryCodec {
         if (data?.altKey) modifiers |= KeyMod.ALT;
         if (data?.metaKey) modifiers |= KeyMod.META;
         parts.push(new Uint8Array([modifiers]));
+
+        this.encodeStringList(parts, data?.types || []);
+        this.encodeFileList(parts, data?.files || []);
+        const entries = Object.entries(data?.data || {});
+        parts.push(this.encodeUvarint(entries.length));
+        for (const [format, value] of entries) {
+            parts.push(this.encodeString(format));
+            parts.push(this.encodeString(value));
+        }
+    }
+
+    /**
+     * Encode media event: the element's playback state
+     */
+    encodeMediaEvent(parts, data) {
+        // NaN (unknown duration) is sent as 0
+        parts.push(this.encodeFloat64(data?.currentTime || 0));
+        parts.push(this.encodeFloat64(data?.duration || 0));
+        parts.push(new Uint8Array([data?.paused ? 1 : 0]));
+        parts.push(new Uint8Array([data?.ended ? 1 : 0]));
+        parts.push(this.encodeFloat64(data?.volume || 0));
+        parts.push(new Uint8Array([data?.muted ? 1 : 0]));
+        parts.push(this.encodeFloat64(data?.playbackRate || 0));
+    }
+
+    /**
+     * Encode clipboard event
+     */
+    encodeClipboardEvent(parts, data) {
+        parts.push(this.encodeString(data?.text || ''));
+        this.encodeStringList(parts, data?.types || []);
+        this.encodeFileList(parts, data?.files || []);
+    }
+
+    /**
+     * Encode string list
+     */
+    encodeStringList(parts, list) {
+        parts.push(this.encodeUvarint(list.length));
+        for (const s of list) {
+            parts.push(this.encodeString(s));
+        }
+    }
+
+    /**
+     * Encode file metadata list: [count][name][type][size]...
+     */
+    encodeFileList(parts, files) {
+        parts.push(this.encodeUvarint(files.length));
+        for (const file of files) {
+            parts.push(this.encodeString(file.name || ''));
+            parts.push(this.encodeString(file.type || ''));
+            parts.push(this.encodeUvarint(file.size || 0));
+        }
     }
 
     /**
@@ -847,6 +1232,85 @@ export class BinaryCodec {
         }
     }
 
+    /**
+     * Decode hook data map
+     */
+    decodeHookData(buffer, offset) {
+        const startOffset = offset;
+        const { value: count, bytesRead: countBytes } = this.decodeCollectionCount(buffer, offset);
+        offset += countBytes;
+
+        const data = {};
+        for (let i = 0; i < count; i++) {
+            const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
+            offset += keyBytes;
+            const { value, bytesRead } = this.decodeHookValue(buffer, offset, 0);
+            offset += bytesRead;
+            data[key] = value;
+        }
+        return { value: data, bytesRead: offset - startOffset };
+    }
+
+    /**
+     * Decode single hook value
+     */
+    decodeHookValue(buffer, offset, depth) {
+        if (depth > MaxHookDepth) {
+            throw new Error('Protocol decode: hook value depth exceeded');
+        }
+
+        const startOffset = offset;
+        this._ensureAvailable(buffer, offset, 1, 'hook value type');
+        const type = buffer[offset++];
+
+        switch (type) {
+            case HookValueType.NULL:
+                return { value: null, bytesRead: 1 };
+            case HookValueType.BOOL:
+                this._ensureAvailable(buffer, offset, 1, 'hook bool');
+                return { value: buffer[offset] !== 0, bytesRead: 2 };
+            case HookValueType.INT: {
+                const { value, bytesRead } = this.decodeSvarint(buffer, offset);
+                return { value, bytesRead: 1 + bytesRead };
+            }
+            case HookValueType.FLOAT: {
+                this._ensureAvailable(buffer, offset, 8, 'hook float');
+                const view = new DataView(buffer.buffer, buffer.byteOffset + offset, 8);
+                return { value: view.getFloat64(0, true), bytesRead: 9 };
+            }
+            case HookValueType.STRING: {
+                const { value, bytesRead } = this.decodeString(buffer, offset);
+                return { value, bytesRead: 1 + bytesRead };
+            }
+            case HookValueType.ARRAY: {
+                const { value: count, bytesRead: countBytes } = this.decodeCollectionCount(buffer, offset);
+                offset += countBytes;
+                const arr = [];
+                for (let i = 0; i < count; i++) {
+                    const { value, bytesRead } = this.decodeHookValue(buffer, offset, depth + 1);
+                    offset += bytesRead;
+                    arr.push(value);
+                }
+                return { value: arr, bytesRead: offset - startOffset };
+            }
+            case HookValueType.OBJECT: {
+                const { value: count, bytesRead: countBytes } = this.decodeCollectionCount(buffer, offset);
+                offset += countBytes;
+                const obj = {};
+                for (let i = 0; i < count; i++) {
+                    const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
+                    offset += keyBytes;
+                    const { value, bytesRead } = this.decodeHookValue(buffer, offset, depth + 1);
+                    offset += bytesRead;
+                    obj[key] = value;
+                }
+                return { value: obj, bytesRead: offset - startOffset };
+            }
+            default:
+                throw new Error(`Protocol decode: unknown hook value type ${type}`);
+        }
+    }
+
     /**
      * Encode 64-bit float (little-endian)
      */
@@ -858,13 +1322,16 @@ export class BinaryCodec {
 
     /**
      * Encode ClientHello for handshake (raw payload, no frame header)
-     * Format: [major:1][minor:1][csrf:string][sessionID:string][lastSeq:4][viewportW:2][viewportH:2][tzOffset:2]
+     * Format: [major:1][minor:1][csrf:string][sessionID:string][lastSeq:4][viewportW:2][viewportH:2][tzOffset:2][codecs?][env?][prefValues?]
+     * The optional codec list is [count:varint][codec:1]... and is omitted when empty.
+     * The optional env is [locale:string][prefs:1]; when present the codec list is always written.
+     * The optional pref values follow the env (see encodePrefValues); when present the env is always written.
      */
     encodeClientHello(options = {}) {
         const parts = [];
 
-        // Protocol version (2.0)
-        parts.push(new Uint8Array([0x02, 0x00]));
+        // Protocol version
+        parts.push(new Uint8Array([ProtocolVersion.major, ProtocolVersion.minor]));
 
         // CSRF token
         parts.push(this.encodeString(options.csrf || ''));
@@ -883,6 +1350,147 @@ export class BinaryCodec {
         const tzOffset = new Date().getTimezoneOffset();
         parts.push(this.encodeInt16(-tzOffset)); // Negate because JS gives opposite sign
 
+        // Supported payload codecs
+        const codecs = options.codecs || [];
+        const prefValues = options.prefValues || [];
+        const hasEnv = !!options.locale || !!options.prefs || prefValues.length > 0;
+        if (codecs.length > 0 || hasEnv) {
+            parts.push(this.encodeUvarint(codecs.length));
+            parts.push(new Uint8Array(codecs));
+        }
+
+        // Locale and ClientPref flags
+        if (hasEnv) {
+            parts.push(this.encodeString(options.locale || ''));
+            parts.push(new Uint8Array([options.prefs || 0]));
+        }
+
+        // Stored user preferences
+        if (prefValues.length > 0) {
+            parts.push(this.encodePrefValues(prefValues));
+        }
+
+        return concat(parts);
+    }
+
+    /**
+     * Encode ClientEnv control payload
+     * Format: [controlType:1][viewportW:2][viewportH:2][tzOffset:2][locale:string][prefs:1]
+     * Matches vango/pkg/protocol/control.go ControlClientEnv (0x41)
+     */
+    encodeClientEnv(env) {
+        return concat([
+            new Uint8Array([0x41]), // ControlClientEnv
+            this.encodeUint16(env.viewportW),
+            this.encodeUint16(env.viewportH),
+            this.encodeInt16(env.tzOffset),
+            this.encodeString(env.locale || ''),
+            new Uint8Array([env.prefs || 0]),
+        ]);
+    }
+
+    /**
+     * Encode PrefSync control payload
+     * Format: [controlType:1][prefValues]
+     * Matches vango/pkg/protocol/control.go ControlPrefSync (0x42)
+     */
+    encodePrefSync(values) {
+        return concat([
+            new Uint8Array([0x42]), // ControlPrefSync
+            this.encodePrefValues(values),
+        ]);
+    }
+
+    /**
+     * Encode preference values
+     * Format: [count:varint]([key:string][value:string][updatedAt:8])...
+     * value is JSON; updatedAt is Unix milliseconds.
+     */
+    encodePrefValues(values) {
+        const parts = [this.encodeUvarint(values.length)];
+        for (const v of values) {
+            parts.push(this.encodeString(v.key));
+            parts.push(this.encodeString(v.value));
+            parts.push(this.encodeUint64(v.updatedAt));
+        }
+        return concat(parts);
+    }
+
+    /**
+     * Decode a PrefSync control payload (after the control type byte)
+     * Format: [count:varint]([key:string][value:string][updatedAt:8])...
+     */
+    decodePrefSync(buffer, offset = 0) {
+        const { value: count, bytesRead: countLen } = this.decodeUvarint(buffer, offset);
+        offset += countLen;
+        const values = [];
+        for (let i = 0; i < count; i++) {
+            const { value: key, bytesRead: keyLen } = this.decodeString(buffer, offset);
+            offset += keyLen;
+            const { value, bytesRead: valueLen } = this.decodeString(buffer, offset);
+            offset += valueLen;
+            const updatedAt = this.decodeUint64(buffer, offset);
+            offset += 8;
+            values.push({ key, value, updatedAt });
+        }
+        return values;
+    }
+
+    /**
+     * Decode a GlobalListen control payload (after the control type byte)
+     * Format: [id:string][target:1][event:string][flags:1][debounce:varint]
+     *         [throttle:varint][keys:count+strings][keyMods:1]
+     */
+    decodeGlobalListen(buffer, offset = 0) {
+        const { value: id, bytesRead: idLen } = this.decodeString(buffer, offset);
+        offset += idLen;
+        const target = buffer[offset++];
+        const { value: event, bytesRead: eventLen } = this.decodeString(buffer, offset);
+        offset += eventLen;
+        const flags = buffer[offset++];
+        const { value: debounce, bytesRead: debounceLen } = this.decodeUvarint(buffer, offset);
+        offset += debounceLen;
+        const { value: throttle, bytesRead: throttleLen } = this.decodeUvarint(buffer, offset);
+        offset += throttleLen;
+        const { value: count, bytesRead: countLen } = this.decodeUvarint(buffer, offset);
+        offset += countLen;
+        const keys = [];
+        for (let i = 0; i < count; i++) {
+            const { value: key, bytesRead: keyLen } = this.decodeString(buffer, offset);
+            offset += keyLen;
+            keys.push(key);
+        }
+        const keyMods = buffer[offset];
+        return { id, target, event, flags, debounce, throttle, keys, keyMods };
+    }
+
+    /**
+     * Decode a HookCall control payload (after the control type byte)
+     * Format: [id:varint][hid:string][method:string][args:hookData]
+     */
+    decodeHookCall(buffer, offset = 0) {
+        const { value: id, bytesRead: idLen } = this.decodeUvarint(buffer, offset);
+        offset += idLen;
+        const { value: hid, bytesRead: hidLen } = this.decodeString(buffer, offset);
+        offset += hidLen;
+        const { value: method, bytesRead: methodLen } = this.decodeString(buffer, offset);
+        offset += methodLen;
+        const { value: args } = this.decodeHookData(buffer, offset);
+        return { id, hid, method, args };
+    }
+
+    /**
+     * Encode HookResult control payload
+     * Format: [controlType:1][id:varint][error:string][result:hookValue]
+     * Matches vango/pkg/protocol/control.go ControlHookResult (0x46)
+     */
+    encodeHookResult(id, result, error = '') {
+        const parts = [
+            new Uint8Array([0x46]), // ControlHookResult
+            this.encodeUvarint(id),
+            this.encodeString(error),
+        ];
+        this.encodeHookValue(parts, result);
         return concat(parts);
     }
 
@@ -906,7 +1514,11 @@ export class BinaryCodec {
     /**
      * Decode ServerHello from handshake response
      * Frame format: [type:1][flags:1][len:2][payload...]
-     * ServerHello payload: [status:1][sessionID:string][nextSeq:4][serverTime:8][flags:2][authReason?:1]
+     * ServerHello payload: [status:1][sessionID:string][nextSeq:4][serverTime:8][flags:2] followed by
+     *   OK:                         [version:2]
+     *   Version mismatch / upgrade: [minVersion:2][version:2]
+     *   Other errors:               [authReason?:1]
+     * Servers older than 2.1 send nothing after the flags.
      */
     decodeServerHello(buffer) {
         // Need at least 4-byte header + 1-byte status
@@ -951,7 +1563,23 @@ export class BinaryCodec {
         offset += 2;
 
         let authReason;
-        if (offset < buffer.length) {
+        let version = LegacyVersion;
+        let minVersion;
+        const readVersion = () => {
+            const v = { major: buffer[offset], minor: buffer[offset + 1] };
+            offset += 2;
+            return v;
+        };
+        if (status === 0x00) {
+            if (offset + 2 <= buffer.length) {
+                version = readVersion();
+            }
+        } else if (status === 0x01 /* Version mismatch */ || status === 0x05 /* Upgrade required */) {
+            if (offset + 4 <= buffer.length) {
+                minVersion = readVersion();
+                version = readVersion();
+            }
+        } else if (offset < buffer.length) {
             authReason = buffer[offset];
         }
 
@@ -961,11 +1589,75 @@ export class BinaryCodec {
             nextSeq,
             serverTime,
             flags,
+            codec: (flags & ServerFlagCompression) ? (flags >> 8) & 0xFF : Codec.NONE,
             authReason,
+            version,
+            minVersion,
             ok: status === 0,
         };
     }
 
+    /**
+     * Encode a payload as one or more frames, fragmenting payloads larger
+     * than one frame. Format: [type:1][flags:1][length:2 big-endian][payload]
+     */
+    encodeFrames(frameType, payload, flags = 0) {
+        if (payload.length <= MaxFramePayload) {
+            return [this._encodeFrame(frameType, flags, payload)];
+        }
+        const frames = [];
+        for (let offset = 0; offset < payload.length; offset += MaxFramePayload) {
+            const end = Math.min(offset + MaxFramePayload, payload.length);
+            let fragmentFlags = flags | FrameFlags.FRAGMENT;
+            if (end === payload.length) {
+                fragmentFlags |= FrameFlags.FINAL;
+            }
+            frames.push(this._encodeFrame(frameType, fragmentFlags, payload.subarray(offset, end)));
+        }
+        return frames;
+    }
+
+    _encodeFrame(frameType, flags, payload) {
+        const frame = new Uint8Array(4 + payload.length);
+        frame[0] = frameType;
+        frame[1] = flags;
+        frame[2] = (payload.length >> 8) & 0xFF;
+        frame[3] = payload.length & 0xFF;
+        frame.set(payload, 4);
+        return frame;
+    }
+
+    /**
+     * Create a decompressor for a negotiated codec (null for Codec.NONE)
+     */
+    createDecompressor(codec) {
+        switch (codec) {
+            case Codec.NONE:
+                return null;
+            case Codec.DEFLATE:
+                return new Inflater();
+            default:
+                throw new Error(`Protocol decode: unsupported codec ${codec}`);
+        }
+    }
+
+    /**
+     * Decompress a message payload flagged FrameFlags.COMPRESSED, after
+     * reassembly. Messages are compressed whole, so the raw length is
+     * limited by maxSize rather than the frame size.
+     * Format: [rawLength:varint][compressed data]
+     */
+    decompressPayload(payload, decompressor, maxSize = DefaultMaxMessageSize) {
+        if (!decompressor) {
+            throw new Error('Protocol decode: compressed frame without a negotiated codec');
+        }
+        const { value: rawLength, bytesRead } = this.decodeUvarint(payload, 0);
+        if (rawLength > maxSize) {
+            throw new Error('Protocol decode: decompressed payload exceeds max size');
+        }
+        return decompressor.inflate(payload.subarray(bytesRead), rawLength);
+    }
+
     /**
      * Encode uint16 big-endian (matches Go protocol)
      */
@@ -1000,6 +1692,18 @@ export class BinaryCodec {
         ]);
     }
 
+    /**
+     * Encode uint64 big-endian (matches Go protocol)
+     * value must be a non-negative safe integer.
+     */
+    encodeUint64(value) {
+        const high = Math.floor(value / 0x100000000);
+        return concat([
+            this.encodeUint32(high),
+            this.encodeUint32(value - high * 0x100000000),
+        ]);
+    }
+
     /**
      * Decode uint32 big-endian (matches Go protocol)
      */
@@ -1052,6 +1756,45 @@ export class BinaryCodec {
         ]);
     }
 
+    /**
+     * Encode a blob upload payload
+     * Format: [op:1][id:varint] followed by
+     *   START: [hid:string][filename:string][type:string][size:varint]
+     *   CHUNK: [offset:varint][data:len-bytes]
+     * Matches vango/pkg/protocol/blob.go
+     */
+    encodeBlob(op, id, fields = {}) {
+        const parts = [new Uint8Array([op]), this.encodeUvarint(id)];
+        switch (op) {
+            case BlobOp.START:
+                parts.push(this.encodeString(fields.hid));
+                parts.push(this.encodeString(fields.filename || ''));
+                parts.push(this.encodeString(fields.type || ''));
+                parts.push(this.encodeUvarint(fields.size));
+                break;
+            case BlobOp.CHUNK:
+                parts.push(this.encodeUvarint(fields.offset));
+                parts.push(this.encodeLenBytes(fields.data));
+                break;
+        }
+        return concat(parts);
+    }
+
+    /**
+     * Decode a BlobAck control payload (after the control type byte)
+     * Format: [id:varint][received:varint][status:1][message:string]
+     */
+    decodeBlobAck(buffer, offset = 0) {
+        const { value: id, bytesRead: idLen } = this.decodeUvarint(buffer, offset);
+        offset += idLen;
+        const { value: received, bytesRead: recvLen } = this.decodeUvarint(buffer, offset);
+        offset += recvLen;
+        this._ensureAvailable(buffer, offset, 1, 'blob status');
+        const status = buffer[offset++];
+        const { value: message } = this.decodeString(buffer, offset);
+        return { id, received, status, message };
+    }
+
     /**
      * Encode ResyncRequest control payload
      * Format: [controlType:1][lastSeq:varint]
@@ -0,0 +1,126 @@
+/**
+ * Client Environment Reporting
+ *
+ * The handshake carries the viewport, timezone offset, locale and
+ * color-scheme / reduced-motion preferences; ctx.Client() exposes them on the
+ * server. Afterwards this module reports changes as CLIENT_ENV control
+ * messages: resizes (debounced), media query changes and locale changes.
+ */
+
+/**
+ * Preference flags - must match pkg/protocol/control.go ClientPref*
+ */
+export const ClientPref = {
+    DARK: 0x01,
+    LIGHT: 0x02,
+    REDUCED_MOTION: 0x04,
+};
+
+const MEDIA_QUERIES = [
+    '(prefers-color-scheme: dark)',
+    '(prefers-color-scheme: light)',
+    '(prefers-reduced-motion: reduce)',
+];
+
+function matches(query) {
+    return typeof window.matchMedia === 'function' && window.matchMedia(query).matches;
+}
+
+/**
+ * Read the current environment.
+ */
+export function readClientEnv() {
+    let prefs = 0;
+    if (matches(MEDIA_QUERIES[0])) prefs |= ClientPref.DARK;
+    else if (matches(MEDIA_QUERIES[1])) prefs |= ClientPref.LIGHT;
+    if (matches(MEDIA_QUERIES[2])) prefs |= ClientPref.REDUCED_MOTION;
+
+    return {
+        viewportW: Math.min(window.innerWidth, 0xFFFF),
+        viewportH: Math.min(window.innerHeight, 0xFFFF),
+        tzOffset: -new Date().getTimezoneOffset(), // JS gives minutes west of UTC
+        locale: (typeof navigator !== 'undefined' && navigator.language) || '',
+        prefs,
+    };
+}
+
+function sameEnv(a, b) {
+    return !!a && !!b &&
+        a.viewportW === b.viewportW &&
+        a.viewportH === b.viewportH &&
+        a.tzOffset === b.tzOffset &&
+        a.locale === b.locale &&
+        a.prefs === b.prefs;
+}
+
+export class ClientEnvReporter {
+    constructor(client, options = {}) {
+        this.client = client;
+        this.debounce = options.resizeDebounce ?? 150;
+        this.last = null; // Last environment the server was told about
+        this.timer = null;
+        this.cleanups = [];
+    }
+
+    /**
+     * Start listening for changes.
+     */
+    attach() {
+        const onResize = () => {
+            clearTimeout(this.timer);
+            this.timer = setTimeout(() => this.report(), this.debounce);
+        };
+        const onChange = () => this.report();
+
+        window.addEventListener('resize', onResize);
+        window.addEventListener('languagechange', onChange);
+        this.cleanups.push(() => {
+            window.removeEventListener('resize', onResize);
+            window.removeEventListener('languagechange', onChange);
+            clearTimeout(this.timer);
+        });
+
+        if (typeof window.matchMedia === 'function') {
+            for (const query of MEDIA_QUERIES) {
+                const mql = window.matchMedia(query);
+                if (mql && typeof mql.addEventListener === 'function') {
+                    mql.addEventListener('change', onChange);
+                    this.cleanups.push(() => mql.removeEventListener('change', onChange));
+                }
+            }
+        }
+    }
+
+    detach() {
+        for (const cleanup of this.cleanups) cleanup();
+        this.cleanups = [];
+    }
+
+    /**
+     * Record the environment sent in a handshake.
+     */
+    markReported(env) {
+        this.last = env;
+    }
+
+    /**
+     * Send the environment if it changed since the last report.
+     * The timezone is re-read each time, so DST transitions are picked up
+     * with the next change.
+     */
+    report() {
+        if (!this.client.connected) return;
+        const env = readClientEnv();
+        if (sameEnv(env, this.last)) return;
+        this.last = env;
+
+        const payload = this.client.codec.encodeClientEnv(env);
+        for (const frame of this.client.codec.encodeFrames(0x03 /* CONTROL */, payload)) {
+            this.client.wsManager.send(frame);
+        }
+
+        if (this.client.options.debug) {
+            console.log('[Vango] Reported client env', env);
+        }
+    }
+}
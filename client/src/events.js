@@ -5,14 +5,56 @@
  * to the server via WebSocket.
  */
 
-import { EventType } from './codec.js';
+import { EventType, versionAtLeast } from './codec.js';
+import { formValueString } from './utils.js';
+
+/**
+ * Default throttle (ms) for high-frequency events, so that they do not flood
+ * the session. data-throttle-{event} overrides these.
+ */
+const DEFAULT_THROTTLE = {
+    scroll: 100,
+    pointermove: 50,
+    drag: 50,
+    dragover: 50,
+    timeupdate: 250,
+};
+
+const POINTER_EVENTS = [
+    'pointerdown', 'pointerup', 'pointermove', 'pointerenter', 'pointerleave', 'pointercancel',
+];
+
+const DRAG_EVENTS = ['dragstart', 'drag', 'dragenter', 'dragover', 'dragleave', 'dragend', 'drop'];
+
+const MEDIA_EVENTS = [
+    'play', 'pause', 'ended', 'timeupdate', 'seeking', 'seeked', 'volumechange', 'ratechange',
+    'durationchange', 'loadedmetadata', 'loadeddata', 'canplay', 'canplaythrough', 'waiting', 'playing',
+];
+
+const CLIPBOARD_EVENTS = ['copy', 'cut', 'paste'];
+
+/**
+ * Element events added in protocol 2.2 - must match eventVersions in
+ * pkg/protocol/version.go. Older servers cannot decode them.
+ */
+const EVENTS_2_2 = new Set([
+    'contextmenu', 'toggle', 'dragenter', 'dragover', 'dragleave', 'drag',
+    ...POINTER_EVENTS, ...MEDIA_EVENTS, ...CLIPBOARD_EVENTS,
+]);
+
+/**
+ * File metadata sent with drag and clipboard events
+ */
+function fileInfos(files) {
+    return Array.from(files || [], (f) => ({ name: f.name, type: f.type, size: f.size }));
+}
 
 export class EventCapture {
     constructor(client) {
         this.client = client;
         this.handlers = new Map();
         this.debounceTimers = new Map();
-        this.scrollThrottled = new Set();
+        this.throttled = new Set(); // hid:event pairs inside their throttle window
         this.prefetchedPaths = new Set(); // Track prefetched paths to avoid duplicates
     }
 
@@ -43,6 +85,28 @@ export class EventCapture {
         this._on('mouseenter', this._handleMouseEnter.bind(this), true);
         this._on('mouseleave', this._handleMouseLeave.bind(this), true);
 
+        // Context menu and pointer events (enter/leave do not bubble)
+        this._on('contextmenu', this._handleContextMenu.bind(this));
+        for (const name of POINTER_EVENTS) {
+            this._on(name, this._handlePointer.bind(this), name === 'pointerenter' || name === 'pointerleave');
+        }
+
+        // Drag and drop, including files dropped onto OnUpload elements
+        for (const name of DRAG_EVENTS) {
+            this._on(name, this._handleDrag.bind(this));
+        }
+
+        // Media and toggle events do not bubble, so they are captured
+        for (const name of MEDIA_EVENTS) {
+            this._on(name, this._handleMedia.bind(this), true);
+        }
+        this._on('toggle', this._handleToggle.bind(this), true);
+
+        // Clipboard events
+        for (const name of CLIPBOARD_EVENTS) {
+            this._on(name, this._handleClipboard.bind(this));
+        }
+
         // Scroll events (throttled)
         this._on('scroll', this._handleScroll.bind(this), true);
 
@@ -205,13 +269,79 @@ export class EventCapture {
      */
     _getThrottle(el, eventName) {
         const mods = this._getModifiers(el, eventName);
-        // Fall back to default for scroll events
-        if (eventName === 'scroll' && mods.throttle === 0) {
-            return 100;
+        // Fall back to default for high-frequency events
+        if (mods.throttle === 0 && DEFAULT_THROTTLE[eventName]) {
+            return DEFAULT_THROTTLE[eventName];
         }
         return mods.throttle;
     }
 
+    /**
+     * Check whether an event on an element falls inside its throttle window.
+     * Starts a new window if it does not.
+     */
+    _isThrottled(el, eventName) {
+        const throttleMs = this._getThrottle(el, eventName);
+        if (throttleMs <= 0) {
+            return false;
+        }
+
+        const key = `${el.dataset.hid}:${eventName}`;
+        if (this.throttled.has(key)) {
+            return true;
+        }
+        this.throttled.add(key);
+        setTimeout(() => {
+            this.throttled.delete(key);
+        }, throttleMs);
+        return false;
+    }
+
+    /**
+     * Send a delegated event to the element that handles it.
+     * Bubbling events go to the closest handling ancestor; non-bubbling events
+     * only to their target. Returns the element, or null if nothing was sent.
+     */
+    _delegate(event, bubbles, payload) {
+        const name = event.type;
+        if (EVENTS_2_2.has(name) && !this._supports22()) {
+            return null;
+        }
+        const el = bubbles
+            ? this._findHidElementWithEvent(event.target, name)
+            : this._ownHidElement(event.target, name);
+        if (!el) return null;
+
+        // Apply modifiers (may skip if Self modifier fails)
+        if (!this._applyModifiers(event, el, name)) {
+            return null;
+        }
+        if (this._isThrottled(el, name)) {
+            return null;
+        }
+
+        this.client.sendEvent(EventType[name.toUpperCase()], el.dataset.hid, payload(el));
+        return el;
+    }
+
+    /**
+     * Whether the server negotiated protocol 2.2 or later
+     */
+    _supports22() {
+        const version = this.client.protocolVersion;
+        return !version || versionAtLeast(version, 2, 2);
+    }
+
+    /**
+     * Return target if it is a HID element with the event in data-ve.
+     */
+    _ownHidElement(target, eventName) {
+        if (!target || !target.dataset || !target.dataset.hid) {
+            return null;
+        }
+        return this._hasEvent(target, eventName) ? target : null;
+    }
+
     /**
      * Find closest element with data-hid that has a specific event in data-ve.
      * Parses data-ve="click,input,change" format per spec Section 5.2.
@@ -352,7 +482,14 @@ export class EventCapture {
      */
     _handleChange(event) {
         const el = this._findHidElement(event.target);
-        if (!el || !this._hasEvent(el, 'change')) return;
+        if (!el) return;
+
+        // Files picked in an OnUpload input stream over the connection
+        if (el.type === 'file' && this._hasEvent(el, 'upload')) {
+            this._uploadFiles(el, el.files);
+        }
+
+        if (!this._hasEvent(el, 'change')) return;
 
         // Apply modifiers (may skip if Self modifier fails)
         if (!this._applyModifiers(event, el, 'change')) {
@@ -376,6 +513,40 @@ export class EventCapture {
         this.client.sendEvent(EventType.CHANGE, el.dataset.hid, { value });
     }
 
+    /**
+     * Allow dropping files on elements with an OnUpload handler
+     */
+    _handleUploadDragOver(event) {
+        if (!event.dataTransfer || !Array.from(event.dataTransfer.types || []).includes('Files')) return;
+        const el = this._findHidElementWithEvent(event.target, 'upload');
+        if (!el) return;
+        event.preventDefault();
+        event.dataTransfer.dropEffect = 'copy';
+    }
+
+    /**
+     * Stream files dropped on an element with an OnUpload handler
+     */
+    _handleUploadDrop(event) {
+        const files = event.dataTransfer?.files;
+        if (!files || files.length === 0) return;
+        const el = this._findHidElementWithEvent(event.target, 'upload');
+        if (!el) return;
+        event.preventDefault();
+        this._uploadFiles(el, files);
+    }
+
+    _uploadFiles(el, files) {
+        if (!files) return;
+        for (const file of Array.from(files)) {
+            if (!this.client.uploads.upload(el.dataset.hid, file)) {
+                if (this.client.options.debug) {
+                    console.warn('[Vango] Upload not sent: blob uploads unavailable', file.name);
+                }
+            }
+        }
+    }
+
     /**
      * Handle form submit
      *
@@ -394,10 +565,17 @@ export class EventCapture {
 
         event.preventDefault();
 
+        // Repeated names (multi-selects, checkbox groups, tags[] inputs) are
+        // collected as arrays so every value reaches the server.
         const formData = new FormData(form);
         const fields = {};
         for (const [key, value] of formData.entries()) {
-            fields[key] = String(value);
+            const v = formValueString(value);
+            if (Object.prototype.hasOwnProperty.call(fields, key)) {
+                fields[key] = [].concat(fields[key], v);
+            } else {
+                fields[key] = v;
+            }
         }
 
         this.client.sendEvent(EventType.SUBMIT, form.dataset.hid, fields);
@@ -517,6 +695,115 @@ export class EventCapture {
         this.client.sendEvent(EventType.MOUSELEAVE, el.dataset.hid);
     }
 
+    /**
+     * Handle contextmenu event
+     */
+    _handleContextMenu(event) {
+        const el = this._delegate(event, true, () => event);
+        if (!el) return;
+
+        // Default preventDefault so the handler can show its own menu (unless passive)
+        const mods = this._getModifiers(el, 'contextmenu');
+        if (!mods.preventDefault && !mods.passive) {
+            event.preventDefault();
+        }
+    }
+
+    /**
+     * Handle pointer events (pointermove is throttled)
+     */
+    _handlePointer(event) {
+        const bubbles = event.type !== 'pointerenter' && event.type !== 'pointerleave';
+        this._delegate(event, bubbles, () => event);
+    }
+
+    /**
+     * Handle drag and drop events (drag and dragover are throttled)
+     */
+    _handleDrag(event) {
+        if (event.type === 'dragover') {
+            this._handleUploadDragOver(event);
+        } else if (event.type === 'drop') {
+            this._handleUploadDrop(event);
+        }
+
+        // Elements only receive drop if dragenter and dragover are cancelled
+        if ((event.type === 'dragenter' || event.type === 'dragover') &&
+            this._findHidElementWithEvent(event.target, 'drop')) {
+            event.preventDefault();
+        }
+
+        const el = this._delegate(event, true, () => this._dragPayload(event));
+
+        // Keep the browser from opening dropped files
+        if (el && event.type === 'drop') {
+            event.preventDefault();
+        }
+    }
+
+    /**
+     * Build drag event payload. Browsers only expose the dragged data on drop.
+     */
+    _dragPayload(event) {
+        const dt = event.dataTransfer;
+        const types = Array.from(dt?.types || []);
+        const data = {};
+        let files = [];
+        if (event.type === 'drop' && dt) {
+            for (const type of types) {
+                if (type !== 'Files') {
+                    data[type] = dt.getData(type);
+                }
+            }
+            files = fileInfos(dt.files);
+        }
+        return {
+            clientX: event.clientX,
+            clientY: event.clientY,
+            pageX: event.pageX,
+            pageY: event.pageY,
+            ctrlKey: event.ctrlKey,
+            shiftKey: event.shiftKey,
+            altKey: event.altKey,
+            metaKey: event.metaKey,
+            types,
+            files,
+            data,
+        };
+    }
+
+    /**
+     * Handle media events (timeupdate is throttled)
+     */
+    _handleMedia(event) {
+        // The media element carries currentTime, duration, paused, etc.
+        this._delegate(event, false, (el) => el);
+    }
+
+    /**
+     * Handle copy, cut and paste events
+     */
+    _handleClipboard(event) {
+        this._delegate(event, true, () => {
+            const cd = event.clipboardData;
+            const paste = event.type === 'paste';
+            return {
+                text: paste ? cd?.getData('text/plain') || '' : '',
+                types: Array.from(cd?.types || []),
+                files: paste ? fileInfos(cd?.files) : [],
+            };
+        });
+    }
+
+    /**
+     * Handle toggle event from details and popover elements
+     */
+    _handleToggle(event) {
+        this._delegate(event, false, (el) => ({
+            open: event.newState ? event.newState === 'open' : Boolean(el.open),
+        }));
+    }
+
     /**
      * Handle scroll event (throttled)
      */
@@ -530,20 +817,10 @@ export class EventCapture {
         }
 
         const hid = el.dataset.hid;
-        const throttleMs = this._getThrottle(el, 'scroll');
-
-        // Simple throttle (skip if throttle is 0 for immediate dispatch)
-        if (throttleMs > 0 && this.scrollThrottled.has(hid)) {
+        if (this._isThrottled(el, 'scroll')) {
             return;
         }
 
-        if (throttleMs > 0) {
-            this.scrollThrottled.add(hid);
-            setTimeout(() => {
-                this.scrollThrottled.delete(hid);
-            }, throttleMs);
-        }
-
         this.client.sendEvent(EventType.SCROLL, hid, {
             scrollTop: el.scrollTop,
             scrollLeft: el.scrollLeft,
@@ -0,0 +1,152 @@
+/**
+ * HTTP Fallback Transport
+ *
+ * A WebSocket-shaped connection over Server-Sent Events plus HTTP POST, for
+ * networks whose proxies block WebSocket upgrades. Server frames arrive as
+ * base64 SSE messages on GET /_vango/sse; client frames are batched and
+ * POSTed back to back to /_vango/sse?conn=<id>. The frames themselves are
+ * unchanged, so the rest of the client cannot tell the transports apart.
+ */
+
+/**
+ * Convert a /_vango/live WebSocket URL to the matching fallback URL.
+ */
+export function httpFallbackUrl(wsUrl) {
+    const url = new URL(wsUrl, location.href);
+    url.protocol = url.protocol === 'wss:' ? 'https:' : url.protocol === 'ws:' ? 'http:' : url.protocol;
+    url.pathname = url.pathname.replace(/\/_vango\/[^/]+$/, '/_vango/sse');
+    return url.toString();
+}
+
+function decodeBase64(data) {
+    const binary = atob(data);
+    const bytes = new Uint8Array(binary.length);
+    for (let i = 0; i < binary.length; i++) {
+        bytes[i] = binary.charCodeAt(i);
+    }
+    return bytes.buffer;
+}
+
+export class HTTPFallbackSocket {
+    static CONNECTING = 0;
+    static OPEN = 1;
+    static CLOSING = 2;
+    static CLOSED = 3;
+
+    constructor(url) {
+        this.url = url;
+        this.readyState = HTTPFallbackSocket.CONNECTING;
+        this.binaryType = 'arraybuffer';
+        this.connId = null;
+
+        this.onopen = null;
+        this.onmessage = null;
+        this.onclose = null;
+        this.onerror = null;
+
+        this.pending = [];
+        this.inflight = false;
+
+        this.source = new EventSource(url);
+        this.source.addEventListener('conn', (e) => this._onConn(e));
+        this.source.addEventListener('close', () => this._finish(1000, '', true));
+        this.source.onmessage = (e) => this._onData(e);
+        this.source.onerror = (e) => {
+            // EventSource reconnects on its own; the session layer owns
+            // reconnection and resume, so stop it here.
+            if (this.readyState === HTTPFallbackSocket.CLOSED) return;
+            this.onerror?.(e);
+            this._finish(1006, '', false);
+        };
+    }
+
+    _onConn(e) {
+        this.connId = e.data;
+        this.readyState = HTTPFallbackSocket.OPEN;
+        this.onopen?.({ type: 'open' });
+    }
+
+    _onData(e) {
+        if (this.readyState !== HTTPFallbackSocket.OPEN) return;
+        let data;
+        try {
+            data = decodeBase64(e.data);
+        } catch {
+            this._finish(1007, 'Invalid frame encoding', false);
+            return;
+        }
+        this.onmessage?.({ type: 'message', data });
+    }
+
+    /**
+     * Queue a frame. Frames sent in the same task share one POST.
+     */
+    send(buffer) {
+        if (this.readyState === HTTPFallbackSocket.CONNECTING) {
+            throw new Error('HTTPFallbackSocket is not open');
+        }
+        if (this.readyState !== HTTPFallbackSocket.OPEN) return;
+
+        this.pending.push(buffer instanceof ArrayBuffer ? new Uint8Array(buffer) : buffer);
+        if (this.pending.length === 1 && !this.inflight) {
+            queueMicrotask(() => this._flush());
+        }
+    }
+
+    /**
+     * POST queued frames, one request at a time so the server reads them in
+     * order.
+     */
+    _flush() {
+        if (this.inflight || this.pending.length === 0 || this.readyState !== HTTPFallbackSocket.OPEN) {
+            return;
+        }
+
+        const frames = this.pending;
+        this.pending = [];
+        let size = 0;
+        for (const f of frames) size += f.length;
+        const body = new Uint8Array(size);
+        let offset = 0;
+        for (const f of frames) {
+            body.set(f, offset);
+            offset += f.length;
+        }
+
+        this.inflight = true;
+        const url = this.url.replace(/\?.*$/, '') + '?conn=' + encodeURIComponent(this.connId);
+        fetch(url, {
+            method: 'POST',
+            body,
+            credentials: 'same-origin',
+            headers: { 'Content-Type': 'application/octet-stream' },
+        }).then((resp) => {
+            this.inflight = false;
+            if (!resp.ok) {
+                // 410 Gone: the server closed this connection
+                this._finish(1006, `POST failed: ${resp.status}`, false);
+                return;
+            }
+            this._flush();
+        }, () => {
+            this.inflight = false;
+            this._finish(1006, 'POST failed', false);
+        });
+    }
+
+    close(code = 1000, reason = '') {
+        if (this.readyState === HTTPFallbackSocket.CLOSED) return;
+        // Like WebSocket, the close event fires asynchronously.
+        this.source.close();
+        this.readyState = HTTPFallbackSocket.CLOSING;
+        setTimeout(() => this._finish(code, reason, true), 0);
+    }
+
+    _finish(code, reason, wasClean) {
+        if (this.readyState === HTTPFallbackSocket.CLOSED) return;
+        this.readyState = HTTPFallbackSocket.CLOSED;
+        this.source.close();
+        this.pending = [];
+        this.onclose?.({ type: 'close', code, reason, wasClean });
+    }
+}
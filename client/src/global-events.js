@@ -0,0 +1,201 @@
+/**
+ * Window and Document Events
+ *
+ * vango.OnWindow and vango.OnDocument subscribe through GLOBAL_LISTEN
+ * control messages (pkg/server/global_events.go). Each subscription has an
+ * ID that is sent as the HID of its events. Subscriptions to the same
+ * target, event and phase share one DOM listener, which is removed when the
+ * last of them ends.
+ *
+ * Modifiers, key filters, debounce and throttle are applied here, so
+ * filtered events never reach the server.
+ */
+
+import { EventType } from './codec.js';
+
+/**
+ * Listener targets - must match GlobalTarget* in pkg/protocol/control.go
+ */
+export const GlobalTarget = {
+    WINDOW: 0x01,
+    DOCUMENT: 0x02,
+};
+
+/**
+ * Listener flags - must match Listen* in pkg/protocol/control.go
+ */
+export const ListenFlags = {
+    PREVENT_DEFAULT: 0x01,
+    STOP_PROPAGATION: 0x02,
+    ONCE: 0x04,
+    PASSIVE: 0x08,
+    CAPTURE: 0x10,
+};
+
+// Keyboard modifier bits, as in pkg/protocol Modifiers.
+const ModCtrl = 0x01;
+const ModShift = 0x02;
+const ModAlt = 0x04;
+const ModMeta = 0x08;
+
+/**
+ * Return the modifier bits held during a keyboard event.
+ */
+function keyModifiers(event) {
+    return (event.ctrlKey ? ModCtrl : 0) |
+        (event.shiftKey ? ModShift : 0) |
+        (event.altKey ? ModAlt : 0) |
+        (event.metaKey ? ModMeta : 0);
+}
+
+/**
+ * GlobalEvents manages the client's window and document subscriptions.
+ */
+export class GlobalEvents {
+    constructor(client, options = {}) {
+        this.client = client;
+        this.options = options;
+
+        // Subscription ID -> subscription
+        this.subs = new Map();
+
+        // "target:event:capture" -> { target, event, capture, handler, ids }
+        this.listeners = new Map();
+    }
+
+    /**
+     * Add a subscription from a GLOBAL_LISTEN message. A subscription sent
+     * again with the same ID replaces the old one.
+     */
+    listen(sub) {
+        const type = EventType[sub.event.toUpperCase()];
+        if (type === undefined) {
+            console.warn('[Vango] Unsupported global event:', sub.event);
+            return;
+        }
+        this.unlisten(sub.id);
+
+        const capture = (sub.flags & ListenFlags.CAPTURE) !== 0;
+        const key = `${sub.target}:${sub.event}:${capture ? 1 : 0}`;
+        let entry = this.listeners.get(key);
+        if (!entry) {
+            const target = sub.target === GlobalTarget.DOCUMENT ? document : window;
+            entry = { target, event: sub.event, capture, ids: new Set() };
+            entry.handler = (event) => {
+                // Handlers may end subscriptions, so iterate over a copy
+                for (const id of [...entry.ids]) {
+                    const current = this.subs.get(id);
+                    if (current) this._handle(current, event);
+                }
+            };
+            target.addEventListener(sub.event, entry.handler, { capture });
+            this.listeners.set(key, entry);
+        }
+        entry.ids.add(sub.id);
+        this.subs.set(sub.id, { ...sub, type, key, timer: null, last: 0 });
+
+        if (this.options.debug) {
+            console.log('[Vango] Global listen:', sub.id, sub.event);
+        }
+    }
+
+    /**
+     * End a subscription. The DOM listener is removed with its last
+     * subscription.
+     */
+    unlisten(id) {
+        const sub = this.subs.get(id);
+        if (!sub) return;
+
+        clearTimeout(sub.timer);
+        this.subs.delete(id);
+
+        const entry = this.listeners.get(sub.key);
+        if (!entry) return;
+        entry.ids.delete(id);
+        if (entry.ids.size === 0) {
+            entry.target.removeEventListener(entry.event, entry.handler, { capture: entry.capture });
+            this.listeners.delete(sub.key);
+        }
+    }
+
+    /**
+     * End every subscription.
+     */
+    reset() {
+        for (const id of [...this.subs.keys()]) {
+            this.unlisten(id);
+        }
+    }
+
+    /**
+     * Apply a subscription's filters and modifiers to an event and send it.
+     */
+    _handle(sub, event) {
+        if (sub.type === EventType.KEYDOWN || sub.type === EventType.KEYUP) {
+            if (sub.keys.length > 0 && !sub.keys.includes(event.key)) return;
+            if (sub.keyMods && keyModifiers(event) !== sub.keyMods) return;
+        }
+
+        // Passive subscriptions never prevent the default action
+        if ((sub.flags & ListenFlags.PREVENT_DEFAULT) && !(sub.flags & ListenFlags.PASSIVE)) {
+            event.preventDefault();
+            if (event.type === 'beforeunload') {
+                // Older browsers show the leave-page prompt only when set
+                event.returnValue = '';
+            }
+        }
+        if (sub.flags & ListenFlags.STOP_PROPAGATION) {
+            event.stopPropagation();
+        }
+
+        // Read the payload now; it may change before a debounced send
+        const payload = this._payload(sub.type, event);
+        const send = () => this.client.sendEvent(sub.type, sub.id, payload);
+
+        if (sub.flags & ListenFlags.ONCE) {
+            this.unlisten(sub.id);
+            send();
+            return;
+        }
+        if (sub.debounce > 0) {
+            clearTimeout(sub.timer);
+            sub.timer = setTimeout(send, sub.debounce);
+            return;
+        }
+        if (sub.throttle > 0) {
+            const now = Date.now();
+            if (now - sub.last < sub.throttle) return;
+            sub.last = now;
+        }
+        send();
+    }
+
+    /**
+     * Build the event payload for a window or document event.
+     */
+    _payload(type, event) {
+        switch (type) {
+            case EventType.RESIZE:
+                return { width: window.innerWidth, height: window.innerHeight };
+            case EventType.SCROLL:
+                return { scrollTop: Math.round(window.scrollY), scrollLeft: Math.round(window.scrollX) };
+            case EventType.KEYDOWN:
+            case EventType.KEYUP:
+                return {
+                    key: event.key,
+                    code: event.code,
+                    ctrlKey: event.ctrlKey,
+                    shiftKey: event.shiftKey,
+                    altKey: event.altKey,
+                    metaKey: event.metaKey,
+                    repeat: event.repeat,
+                    location: event.location,
+                };
+            case EventType.VISIBILITYCHANGE:
+                return { value: document.visibilityState };
+            default:
+                return null;
+        }
+    }
+}
@@ -17,6 +17,10 @@ import { PortalHook } from './portal.js';
 import { DialogHook } from './dialog.js';
 import { PopoverHook } from './popover.js';
 import { ThemeToggleHook } from './theme_toggle.js';
+import { DOMQueries } from './queries.js';
+
+// Lifecycle methods the server cannot call through CallHook
+const lifecycleMethods = new Set(['constructor', 'mounted', 'updated', 'destroyed']);
 
 export class HookManager {
     constructor(client) {
@@ -283,6 +287,30 @@ export class HookManager {
         }
     }
 
+    /**
+     * Call a method for the server (ctx.CallHook). The hook instance's
+     * method is called with the element and args; its return value, or the
+     * value its promise resolves to, is the result. Built-in DOM queries
+     * (vango:rect, ...) work on any element with a HID.
+     * Throws if there is nothing to call.
+     */
+    async call(hid, method, args = {}) {
+        const entry = this.instances.get(hid);
+        if (entry && !lifecycleMethods.has(method) && typeof entry.instance[method] === 'function') {
+            return await entry.instance[method](entry.el, args);
+        }
+
+        const query = DOMQueries[method];
+        if (query) {
+            const el = entry?.el || this.client.getNode(hid);
+            if (!el) throw new Error(`no element with HID ${hid}`);
+            return query(el, args);
+        }
+
+        if (!entry) throw new Error(`no hook on element ${hid}`);
+        throw new Error(`hook ${entry.hookName} has no method ${method}`);
+    }
+
     /**
      * Revert an optimistic hook change for a given HID.
      */
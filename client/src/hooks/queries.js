@@ -0,0 +1,90 @@
+/**
+ * Built-in Hook Calls
+ *
+ * DOM queries the server can run on any element with a HID through
+ * ctx.CallHook, without a hook on the element. Method names match
+ * HookMethod* in pkg/server/hook_call.go.
+ */
+
+/**
+ * Return the selection range of an input or textarea, or null if the
+ * element has none (e.g. type="number").
+ */
+function inputSelection(el) {
+    let start;
+    let end;
+    try {
+        start = el.selectionStart;
+        end = el.selectionEnd;
+    } catch {
+        return null;
+    }
+    if (start === null || start === undefined) return null;
+    return {
+        text: el.value.slice(start, end),
+        start,
+        end,
+        direction: el.selectionDirection || 'none',
+    };
+}
+
+/**
+ * Return the part of the page selection inside el, with offsets into its
+ * text content.
+ */
+function rangeSelection(el) {
+    const none = { text: '', start: 0, end: 0, direction: 'none' };
+    const sel = window.getSelection();
+    if (!sel || sel.rangeCount === 0) return none;
+
+    const range = sel.getRangeAt(0);
+    if (!range.intersectsNode(el)) return none;
+
+    // Clip the range to the element
+    const clipped = document.createRange();
+    clipped.selectNodeContents(el);
+    if (el.contains(range.startContainer)) {
+        clipped.setStart(range.startContainer, range.startOffset);
+    }
+    if (el.contains(range.endContainer)) {
+        clipped.setEnd(range.endContainer, range.endOffset);
+    }
+
+    const before = document.createRange();
+    before.selectNodeContents(el);
+    before.setEnd(clipped.startContainer, clipped.startOffset);
+
+    const text = clipped.toString();
+    const start = before.toString().length;
+    let direction = 'none';
+    if (!sel.isCollapsed) {
+        const forward = sel.anchorNode === range.startContainer && sel.anchorOffset === range.startOffset;
+        direction = forward ? 'forward' : 'backward';
+    }
+    return { text, start, end: start + text.length, direction };
+}
+
+export const DOMQueries = {
+    'vango:rect': (el) => {
+        const r = el.getBoundingClientRect();
+        return {
+            x: r.x, y: r.y, width: r.width, height: r.height,
+            top: r.top, right: r.right, bottom: r.bottom, left: r.left,
+        };
+    },
+
+    'vango:scroll': (el) => ({
+        top: el.scrollTop,
+        left: el.scrollLeft,
+        width: el.scrollWidth,
+        height: el.scrollHeight,
+    }),
+
+    'vango:selection': (el) => {
+        if (el instanceof HTMLInputElement || el instanceof HTMLTextAreaElement) {
+            const sel = inputSelection(el);
+            if (sel) return sel;
+        }
+        return rangeSelection(el);
+    },
+};
@@ -7,16 +7,22 @@
  * Target size: < 15KB gzipped
  */
 
-import { BinaryCodec, EventType } from './codec.js';
+import { BinaryCodec, EventType, FrameFlags, FrameReassembler, ProtocolVersion, versionAtLeast } from './codec.js';
 import { WebSocketManager } from './websocket.js';
 import { EventCapture } from './events.js';
 import { PatchApplier } from './patches.js';
 import { OptimisticUpdates } from './optimistic.js';
 import { HookManager } from './hooks/manager.js';
+import { IslandManager } from './islands.js';
 import { ensurePortalRoot } from './hooks/portal.js';
+import { mountPortals } from './portals.js';
 import { ConnectionManager, ConnectionState, injectDefaultStyles } from './connection.js';
 import { URLManager } from './url.js';
 import { PrefManager, MergeStrategy } from './prefs.js';
+import { GlobalEvents } from './global-events.js';
+import { BlobUploader } from './uploads.js';
+import { ClientEnvReporter } from './env.js';
+import { GraphInspector } from './inspector.js';
 
 /**
  * Frame type constants for wire protocol
@@ -29,6 +35,7 @@ const FrameType = {
     CONTROL: 0x03,
     ACK: 0x04,
     ERROR: 0x05,
+    BLOB: 0x06,
 };
 
 const MaxFramePayload = 0xFFFF;
@@ -45,6 +52,13 @@ const ControlType = {
     RESYNC_FULL: 0x12,     // Server -> Client: full HTML replacement
     HOOK_REVERT: 0x30,     // Server -> Client: revert hook optimistic change (by HID)
     AUTH_COMMAND: 0x31,    // Server -> Client: auth expired command
+    BLOB_ACK: 0x40,        // Server -> Client: upload progress
+    CLIENT_ENV: 0x41,      // Client -> Server: viewport or preference change
+    PREF_SYNC: 0x42,       // Both directions: changed user preferences
+    GLOBAL_LISTEN: 0x43,   // Server -> Client: subscribe to a window/document event
+    GLOBAL_UNLISTEN: 0x44, // Server -> Client: end a window/document subscription
+    HOOK_CALL: 0x45,       // Server -> Client: call a hook method (ctx.CallHook)
+    HOOK_RESULT: 0x46,     // Client -> Server: result of a hook call
     CLOSE: 0x20,
 };
 
@@ -71,6 +85,8 @@ export class VangoClient {
 
         // Core components
         this.codec = new BinaryCodec();
+        this.decompressor = null; // Per-connection, set by the handshake
+        this.reassembler = new FrameReassembler(options.maxMessageSize);
         this.nodeMap = new Map(); // hid -> DOM node
         this.connected = false;
         this.seq = 0; // Event sequence number
@@ -80,12 +96,18 @@ export class VangoClient {
         this.expectedPatchSeq = 1;      // Next expected patch sequence
         this.pendingResync = false;     // Debounce resync requests
 
+        // Protocol version negotiated in the last handshake
+        this.protocolVersion = ProtocolVersion;
+
         // Sub-systems
         this.wsManager = new WebSocketManager(this, this.options);
         this.patchApplier = new PatchApplier(this);
         this.eventCapture = new EventCapture(this);
         this.optimistic = new OptimisticUpdates(this);
         this.hooks = new HookManager(this);
+        this.islands = new IslandManager(this);
+        this.uploads = new BlobUploader(this);
+        this.env = new ClientEnvReporter(this, options);
         this.connection = new ConnectionManager({
             toastOnReconnect: options.toastOnReconnect || window.__VANGO_TOAST_ON_RECONNECT__,
             toastMessage: options.toastMessage || 'Connection restored',
@@ -96,6 +118,8 @@ export class VangoClient {
         });
         this.urlManager = new URLManager(this, { debug: options.debug });
         this.prefs = new PrefManager(this, { debug: options.debug });
+        this.globalEvents = new GlobalEvents(this, { debug: options.debug });
+        this.inspector = this.options.debug ? new GraphInspector(this) : null;
         this._authChannel = 'vango:auth';
         this._authBroadcast = null;
         this._authStorageHandler = null;
@@ -120,13 +144,21 @@ export class VangoClient {
         this._initAuthBroadcast();
         this.wsManager.connect(this.options.wsUrl);
         this.eventCapture.attach();
+        this.env.attach();
         this.hooks.initializeFromDOM();
+        this.islands.initializeFromDOM();
+        if (this.inspector) {
+            this.inspector.attach();
+        }
     }
 
     /**
      * Build initial map of data-hid -> DOM node
      */
     _buildNodeMap() {
+        // Move server-rendered portals into their targets first
+        mountPortals(document);
+
         document.querySelectorAll('[data-hid]').forEach(el => {
             const hid = el.dataset.hid;
             this.nodeMap.set(hid, el);
@@ -151,6 +183,8 @@ export class VangoClient {
      */
     _onConnected() {
         this.connected = true;
+        // The server sends every subscription again after the handshake
+        this.globalEvents.reset();
         this.connection.onConnect();
         this.onConnect();
     }
@@ -160,6 +194,7 @@ export class VangoClient {
      */
     _onDisconnected() {
         this.connected = false;
+        this.uploads.reset();
         this.connection.onDisconnect();
         this.onDisconnect();
     }
@@ -224,9 +259,20 @@ export class VangoClient {
             this._handleProtocolError(new Error('Frame length mismatch'), 'frame header');
             return;
         }
-        const payload = buffer.slice(4, 4 + length);
+        let payload = buffer.slice(4, 4 + length);
 
         try {
+            // Large messages arrive as fragments; wait for the last one
+            const message = this.reassembler.add(frameType, buffer[1], payload);
+            if (!message) {
+                return;
+            }
+            payload = message.payload;
+
+            if (message.flags & FrameFlags.COMPRESSED) {
+                payload = this.codec.decompressPayload(payload, this.decompressor, this.reassembler.maxSize);
+            }
+
             switch (frameType) {
                 case FrameType.PATCHES:
                     this._handlePatches(payload);
@@ -294,6 +340,9 @@ export class VangoClient {
         // Re-initialize hooks on new elements
         this.hooks.updateFromDOM();
 
+        // Mount new islands and deliver queued island messages
+        this.islands.updateFromDOM();
+
         // Update sequence tracking
         this.patchSeq = seq;
         this.expectedPatchSeq = seq + 1;
@@ -333,6 +382,23 @@ export class VangoClient {
             case ControlType.AUTH_COMMAND:
                 this._handleAuthCommand(buffer.slice(1));
                 break;
+            case ControlType.BLOB_ACK:
+                this.uploads.handleAck(buffer.subarray(1));
+                break;
+            case ControlType.PREF_SYNC:
+                this.prefs.handleSync(this.codec.decodePrefSync(buffer, 1));
+                break;
+            case ControlType.GLOBAL_LISTEN:
+                this.globalEvents.listen(this.codec.decodeGlobalListen(buffer, 1));
+                break;
+            case ControlType.GLOBAL_UNLISTEN: {
+                const { value: id } = this.codec.decodeString(buffer, 1);
+                this.globalEvents.unlisten(id);
+                break;
+            }
+            case ControlType.HOOK_CALL:
+                this._handleHookCall(this.codec.decodeHookCall(buffer, 1));
+                break;
             case ControlType.CLOSE:
                 // Server requesting close
                 this.wsManager.close();
@@ -344,6 +410,30 @@ export class VangoClient {
         }
     }
 
+    /**
+     * Run a hook call from the server and send back its result or error.
+     * Calls are answered as they complete, not in order.
+     */
+    async _handleHookCall(call) {
+        let result = null;
+        let error = '';
+        try {
+            result = await this.hooks.call(call.hid, call.method, call.args);
+        } catch (err) {
+            error = err?.message || String(err);
+        }
+        if (!this.connected) return;
+
+        const payload = this.codec.encodeHookResult(call.id, result, error);
+        for (const frame of this.codec.encodeFrames(FrameType.CONTROL, payload)) {
+            this.wsManager.send(frame);
+        }
+
+        if (this.options.debug) {
+            console.log('[Vango] Hook call:', call.hid, call.method, error || result);
+        }
+    }
+
     _handleAuthCommand(buffer) {
         if (buffer.length < 2) {
             return;
@@ -538,8 +628,9 @@ export class VangoClient {
         // Clear existing node map
         this.nodeMap.clear();
 
-        // Destroy existing hooks before replacing DOM
+        // Destroy existing hooks and islands before replacing DOM
         this.hooks.destroyAll();
+        this.islands.destroyAll();
 
         // Replace body children
         document.body.innerHTML = '';
@@ -550,8 +641,9 @@ export class VangoClient {
         // Rebuild node map from new DOM
         this._buildNodeMap();
 
-        // Reinitialize hooks on new elements
+        // Reinitialize hooks and islands on new elements
         this.hooks.initializeFromDOM();
+        this.islands.initializeFromDOM();
 
         // Reset patch sequence tracking after full resync
         this.patchSeq = 0;
@@ -665,10 +757,17 @@ export class VangoClient {
         this.seq++;
         const eventBuffer = this.codec.encodeEvent(this.seq, type, hid, data);
 
-        // Wrap in frame with 4-byte header: [type][flags][length-hi][length-lo]
-        const frame = this._encodeFrame(FrameType.EVENT, eventBuffer);
-
-        this.wsManager.send(frame);
+        // Wrap in frames with 4-byte header: [type][flags][length-hi][length-lo],
+        // fragmenting events larger than one frame
+        const frames = this.codec.encodeFrames(FrameType.EVENT, eventBuffer);
+        if (frames.length > 1 && !versionAtLeast(this.protocolVersion, 2, 1)) {
+            // Servers before 2.1 cannot reassemble fragments
+            this.onError(new Error(`Event of ${eventBuffer.length} bytes is too large for protocol ${this.protocolVersion.major}.${this.protocolVersion.minor}`));
+            return;
+        }
+        for (const frame of frames) {
+            this.wsManager.send(frame);
+        }
 
         if (this.options.debug) {
             console.log('[Vango] Sent event:', { type, hid, data, seq: this.seq });
@@ -697,6 +796,27 @@ export class VangoClient {
         this.sendEvent(EventType.HOOK, hid, { name: eventName, data });
     }
 
+    /**
+     * Send a JS island message to the server.
+     * Returns false if the message does not fit in a single frame.
+     */
+    sendIslandMessage(id, data = {}) {
+        const size = this.codec.encodeEvent(this.seq + 1, EventType.ISLAND, id, { data }).length;
+        if (size > MaxFramePayload) {
+            console.error('[Vango] Island message exceeds max frame size, dropped:', id);
+            return false;
+        }
+        this.sendEvent(EventType.ISLAND, id, { data });
+        return true;
+    }
+
+    /**
+     * Register a JS island module for a data-module path
+     */
+    registerIsland(src, module) {
+        this.islands.register(src, module);
+    }
+
     /**
      * Get DOM node by hydration ID
      */
@@ -742,7 +862,9 @@ export class VangoClient {
     destroy() {
         this.eventCapture.detach();
         this.hooks.destroyAll();
+        this.islands.destroyAll();
         this.prefs.destroy();
+        this.globalEvents.reset();
         if (this._authBroadcast) {
             this._authBroadcast.close();
             this._authBroadcast = null;
@@ -0,0 +1,276 @@
+/**
+ * Raw DEFLATE (RFC 1951) decoder for compressed frames.
+ *
+ * The server keeps one DEFLATE stream per connection and sync-flushes it
+ * after every frame, so each frame holds whole blocks that may refer back
+ * into the output of earlier frames. Inflater keeps the last 32KB of output
+ * between calls for those references. Frames must be inflated in the order
+ * they arrive, and a new Inflater is needed for every connection.
+ *
+ * Must match pkg/protocol/compress.go
+ */
+
+const WINDOW_SIZE = 32768;
+
+const LENGTH_BASE = [
+    3, 4, 5, 6, 7, 8, 9, 10, 11, 13, 15, 17, 19, 23, 27, 31,
+    35, 43, 51, 59, 67, 83, 99, 115, 131, 163, 195, 227, 258,
+];
+const LENGTH_EXTRA = [
+    0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2,
+    3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 5, 5, 0,
+];
+const DIST_BASE = [
+    1, 2, 3, 4, 5, 7, 9, 13, 17, 25, 33, 49, 65, 97, 129, 193,
+    257, 385, 513, 769, 1025, 1537, 2049, 3073, 4097, 6145, 8193, 12289, 16385, 24577,
+];
+const DIST_EXTRA = [
+    0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6,
+    7, 7, 8, 8, 9, 9, 10, 10, 11, 11, 12, 12, 13, 13,
+];
+
+// Order in which code length code lengths are sent (RFC 1951 §3.2.7)
+const CLEN_ORDER = [16, 17, 18, 0, 8, 7, 9, 6, 10, 5, 11, 4, 12, 3, 13, 2, 14, 1, 15];
+
+/**
+ * Build a canonical Huffman decoding table from code lengths.
+ */
+function buildHuffman(lengths) {
+    const counts = new Uint16Array(16);
+    for (let i = 0; i < lengths.length; i++) {
+        counts[lengths[i]]++;
+    }
+    counts[0] = 0;
+
+    const offsets = new Uint16Array(16);
+    for (let len = 1; len < 16; len++) {
+        offsets[len] = offsets[len - 1] + counts[len - 1];
+    }
+
+    const symbols = new Uint16Array(lengths.length);
+    for (let i = 0; i < lengths.length; i++) {
+        if (lengths[i] !== 0) {
+            symbols[offsets[lengths[i]]++] = i;
+        }
+    }
+    return { counts, symbols };
+}
+
+let fixedTables = null;
+
+function getFixedTables() {
+    if (!fixedTables) {
+        const lit = new Uint8Array(288);
+        lit.fill(8, 0, 144);
+        lit.fill(9, 144, 256);
+        lit.fill(7, 256, 280);
+        lit.fill(8, 280, 288);
+        const dist = new Uint8Array(30).fill(5);
+        fixedTables = { lit: buildHuffman(lit), dist: buildHuffman(dist) };
+    }
+    return fixedTables;
+}
+
+export class Inflater {
+    constructor() {
+        this.history = new Uint8Array(0);
+    }
+
+    /**
+     * Inflate one frame's DEFLATE data.
+     * @param {Uint8Array} input - Compressed bytes (after the length prefix)
+     * @param {number} rawLength - Expected decompressed length
+     * @returns {Uint8Array} Decompressed bytes
+     */
+    inflate(input, rawLength) {
+        this.input = input;
+        this.pos = 0;
+        this.bitBuf = 0;
+        this.bitCnt = 0;
+
+        const histLen = this.history.length;
+        this.out = new Uint8Array(histLen + rawLength);
+        this.out.set(this.history);
+        this.outPos = histLen;
+
+        while (this.pos < input.length) {
+            const final = this._bits(1);
+            const type = this._bits(2);
+            switch (type) {
+                case 0:
+                    this._stored();
+                    break;
+                case 1: {
+                    const fixed = getFixedTables();
+                    this._codes(fixed.lit, fixed.dist);
+                    break;
+                }
+                case 2:
+                    this._dynamic();
+                    break;
+                default:
+                    throw new Error('Inflate: invalid block type');
+            }
+            if (final) {
+                break;
+            }
+        }
+
+        if (this.outPos !== this.out.length) {
+            throw new Error('Inflate: decompressed length mismatch');
+        }
+
+        const out = this.out;
+        this.history = out.slice(Math.max(0, out.length - WINDOW_SIZE));
+        this.input = null;
+        this.out = null;
+        return out.subarray(histLen);
+    }
+
+    _bits(n) {
+        let buf = this.bitBuf;
+        while (this.bitCnt < n) {
+            if (this.pos >= this.input.length) {
+                throw new Error('Inflate: unexpected end of input');
+            }
+            buf |= this.input[this.pos++] << this.bitCnt;
+            this.bitCnt += 8;
+        }
+        this.bitBuf = buf >>> n;
+        this.bitCnt -= n;
+        return buf & ((1 << n) - 1);
+    }
+
+    _decode(table) {
+        let code = 0;
+        let first = 0;
+        let index = 0;
+        for (let len = 1; len < 16; len++) {
+            code |= this._bits(1);
+            const count = table.counts[len];
+            if (code - count < first) {
+                return table.symbols[index + (code - first)];
+            }
+            index += count;
+            first = (first + count) << 1;
+            code <<= 1;
+        }
+        throw new Error('Inflate: invalid Huffman code');
+    }
+
+    _put(byte) {
+        if (this.outPos >= this.out.length) {
+            throw new Error('Inflate: output exceeds declared length');
+        }
+        this.out[this.outPos++] = byte;
+    }
+
+    _stored() {
+        // Discard the remaining bits of the current byte
+        this.bitBuf = 0;
+        this.bitCnt = 0;
+
+        if (this.pos + 4 > this.input.length) {
+            throw new Error('Inflate: unexpected end of input');
+        }
+        const input = this.input;
+        const len = input[this.pos] | (input[this.pos + 1] << 8);
+        const nlen = input[this.pos + 2] | (input[this.pos + 3] << 8);
+        this.pos += 4;
+        if (len !== (~nlen & 0xFFFF)) {
+            throw new Error('Inflate: stored block length mismatch');
+        }
+        if (this.pos + len > input.length) {
+            throw new Error('Inflate: unexpected end of input');
+        }
+        if (this.outPos + len > this.out.length) {
+            throw new Error('Inflate: output exceeds declared length');
+        }
+        this.out.set(input.subarray(this.pos, this.pos + len), this.outPos);
+        this.pos += len;
+        this.outPos += len;
+    }
+
+    _dynamic() {
+        const nlen = this._bits(5) + 257;
+        const ndist = this._bits(5) + 1;
+        const ncode = this._bits(4) + 4;
+        if (nlen > 286 || ndist > 30) {
+            throw new Error('Inflate: invalid code counts');
+        }
+
+        const clens = new Uint8Array(19);
+        for (let i = 0; i < ncode; i++) {
+            clens[CLEN_ORDER[i]] = this._bits(3);
+        }
+        const clenTable = buildHuffman(clens);
+
+        const lengths = new Uint8Array(nlen + ndist);
+        let i = 0;
+        while (i < nlen + ndist) {
+            const sym = this._decode(clenTable);
+            if (sym < 16) {
+                lengths[i++] = sym;
+                continue;
+            }
+            let value = 0;
+            let repeat;
+            if (sym === 16) {
+                if (i === 0) {
+                    throw new Error('Inflate: repeat with no previous length');
+                }
+                value = lengths[i - 1];
+                repeat = 3 + this._bits(2);
+            } else if (sym === 17) {
+                repeat = 3 + this._bits(3);
+            } else {
+                repeat = 11 + this._bits(7);
+            }
+            if (i + repeat > nlen + ndist) {
+                throw new Error('Inflate: too many code lengths');
+            }
+            lengths.fill(value, i, i + repeat);
+            i += repeat;
+        }
+
+        this._codes(buildHuffman(lengths.subarray(0, nlen)), buildHuffman(lengths.subarray(nlen)));
+    }
+
+    _codes(litTable, distTable) {
+        for (;;) {
+            const sym = this._decode(litTable);
+            if (sym < 256) {
+                this._put(sym);
+                continue;
+            }
+            if (sym === 256) {
+                return;
+            }
+
+            const li = sym - 257;
+            if (li >= LENGTH_BASE.length) {
+                throw new Error('Inflate: invalid length code');
+            }
+            const len = LENGTH_BASE[li] + this._bits(LENGTH_EXTRA[li]);
+
+            const di = this._decode(distTable);
+            if (di >= DIST_BASE.length) {
+                throw new Error('Inflate: invalid distance code');
+            }
+            const dist = DIST_BASE[di] + this._bits(DIST_EXTRA[di]);
+            if (dist > this.outPos) {
+                throw new Error('Inflate: distance too far back');
+            }
+            if (this.outPos + len > this.out.length) {
+                throw new Error('Inflate: output exceeds declared length');
+            }
+
+            // Byte-by-byte so overlapping copies repeat correctly
+            const out = this.out;
+            let from = this.outPos - dist;
+            for (let k = 0; k < len; k++) {
+                out[this.outPos++] = out[from++];
+            }
+        }
+    }
+}
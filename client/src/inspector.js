@@ -0,0 +1,209 @@
+/**
+ * Reactive Graph Inspector
+ *
+ * Debug-only overlay for the server's /_vango/inspect endpoint (DevMode).
+ * Alt+Shift+G toggles a panel listing the session's owners, components,
+ * signals, memos and effects with their run counts and subscriptions.
+ * Hovering a component outlines its root element.
+ */
+
+const TOGGLE_KEY = 'G';
+const PANEL_ID = 'vango-inspector';
+
+/**
+ * Group graph nodes into a tree by owner, and index edges in both directions.
+ */
+export function buildInspectorTree(graph) {
+    const byId = new Map();
+    const children = new Map();
+    const deps = new Map();  // id -> ids it depends on
+    const subs = new Map();  // id -> ids that depend on it
+    const roots = [];
+
+    for (const node of graph.nodes || []) {
+        byId.set(node.id, node);
+    }
+    for (const node of graph.nodes || []) {
+        if (node.owner && byId.has(node.owner)) {
+            if (!children.has(node.owner)) children.set(node.owner, []);
+            children.get(node.owner).push(node);
+        } else {
+            roots.push(node);
+        }
+    }
+    for (const edge of graph.edges || []) {
+        if (!deps.has(edge.to)) deps.set(edge.to, []);
+        deps.get(edge.to).push(edge.from);
+        if (!subs.has(edge.from)) subs.set(edge.from, []);
+        subs.get(edge.from).push(edge.to);
+    }
+
+    return { byId, children, deps, subs, roots };
+}
+
+/**
+ * One-line description of a node.
+ */
+export function describeNode(node) {
+    let text = `${node.kind} #${node.id}`;
+    if (node.label) text += ` ${node.label}`;
+    if (node.hid) text += ` [${node.hid}]`;
+    if (node.scope) text += ` (${node.scope})`;
+    if (node.persistKey) text += ` key=${node.persistKey}`;
+    if (node.value !== undefined && node.kind !== 'owner' && node.kind !== 'component') {
+        text += ` = ${node.value}`;
+    }
+    text += ` runs=${node.runs}`;
+    if (node.disposed) text += ' disposed';
+    return text;
+}
+
+export class GraphInspector {
+    constructor(client) {
+        this.client = client;
+        this.panel = null;
+        this.highlighted = null;
+        this._onKeyDown = (e) => {
+            if (e.altKey && e.shiftKey && e.key.toUpperCase() === TOGGLE_KEY) {
+                e.preventDefault();
+                this.toggle();
+            }
+        };
+    }
+
+    attach() {
+        document.addEventListener('keydown', this._onKeyDown);
+    }
+
+    detach() {
+        document.removeEventListener('keydown', this._onKeyDown);
+        this.close();
+    }
+
+    toggle() {
+        if (this.panel) {
+            this.close();
+        } else {
+            this.open();
+        }
+    }
+
+    async open() {
+        if (!this.panel) {
+            this.panel = document.createElement('div');
+            this.panel.id = PANEL_ID;
+            this.panel.style.cssText = 'position:fixed;right:0;top:0;bottom:0;width:420px;overflow:auto;' +
+                'z-index:2147483647;background:#1e1e1e;color:#ddd;font:12px/1.5 monospace;padding:8px;';
+            document.body.appendChild(this.panel);
+        }
+        await this.refresh();
+    }
+
+    close() {
+        this._highlight(null);
+        if (this.panel) {
+            this.panel.remove();
+            this.panel = null;
+        }
+    }
+
+    async refresh() {
+        const sessionId = this.client.wsManager.sessionId;
+        if (!sessionId) {
+            this._renderMessage('Not connected');
+            return;
+        }
+        try {
+            const res = await fetch(`/_vango/inspect?session=${encodeURIComponent(sessionId)}`, {
+                cache: 'no-store',
+            });
+            if (!res.ok) {
+                this._renderMessage(`Inspector unavailable (${res.status}); it requires DevMode`);
+                return;
+            }
+            this.render(await res.json());
+        } catch (err) {
+            this._renderMessage(`Inspector request failed: ${err.message}`);
+        }
+    }
+
+    /**
+     * Render a graph into the panel.
+     */
+    render(graph) {
+        if (!this.panel) return;
+        const tree = buildInspectorTree(graph);
+        this.panel.textContent = '';
+
+        const header = document.createElement('div');
+        header.textContent = `${graph.nodes.length} nodes, ${graph.edges.length} edges `;
+        const refresh = document.createElement('button');
+        refresh.textContent = 'Refresh';
+        refresh.addEventListener('click', () => this.refresh());
+        header.appendChild(refresh);
+        this.panel.appendChild(header);
+
+        const list = document.createElement('ul');
+        list.style.cssText = 'margin:0;padding-left:12px;list-style:none;';
+        for (const node of tree.roots) {
+            list.appendChild(this._renderNode(node, tree));
+        }
+        this.panel.appendChild(list);
+    }
+
+    _renderNode(node, tree) {
+        const item = document.createElement('li');
+        const line = document.createElement('div');
+        line.textContent = describeNode(node);
+        line.dataset.nodeId = node.id;
+
+        const links = [];
+        const deps = tree.deps.get(node.id);
+        if (deps) links.push(`← ${deps.map(id => '#' + id).join(' ')}`);
+        const subs = tree.subs.get(node.id);
+        if (subs) links.push(`→ ${subs.map(id => '#' + id).join(' ')}`);
+        if (node.subscribers > (subs ? subs.length : 0)) {
+            links.push(`${node.subscribers} subscriber(s) total`);
+        }
+        if (links.length > 0) {
+            const detail = document.createElement('div');
+            detail.style.cssText = 'color:#888;padding-left:12px;';
+            detail.textContent = links.join('  ');
+            line.appendChild(detail);
+        }
+
+        if (node.hid) {
+            line.addEventListener('mouseenter', () => this._highlight(this.client.nodeMap.get(node.hid)));
+            line.addEventListener('mouseleave', () => this._highlight(null));
+        }
+        item.appendChild(line);
+
+        const children = tree.children.get(node.id);
+        if (children) {
+            const list = document.createElement('ul');
+            list.style.cssText = 'margin:0;padding-left:12px;list-style:none;';
+            for (const child of children) {
+                list.appendChild(this._renderNode(child, tree));
+            }
+            item.appendChild(list);
+        }
+        return item;
+    }
+
+    _renderMessage(message) {
+        if (!this.panel) return;
+        this.panel.textContent = message;
+    }
+
+    _highlight(el) {
+        if (this.highlighted) {
+            this.highlighted.style.outline = this.highlighted.__vangoOutline || '';
+            this.highlighted = null;
+        }
+        if (el) {
+            el.__vangoOutline = el.style.outline;
+            el.style.outline = '2px solid #e91e63';
+            this.highlighted = el;
+        }
+    }
+}
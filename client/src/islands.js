@@ -0,0 +1,238 @@
+/**
+ * JS Island Lifecycle and Messaging
+ *
+ * Mounts JavaScript islands rendered by islands.JSIsland and routes
+ * messages between them and the server:
+ *
+ *   - Server -> island: ISLAND_MESSAGE patches, delivered to island.onMessage
+ *   - Island -> server: island.send(data), sent as an ISLAND event
+ *
+ * An island module exports mount(el, props, island). It may return a cleanup
+ * function or an object with destroy() (and optionally update(props)).
+ */
+
+/**
+ * Max nesting depth for island message data (matches protocol.MaxHookDepth)
+ */
+const MaxIslandDepth = 64;
+
+export class IslandManager {
+    constructor(client) {
+        this.client = client;
+        this.instances = new Map(); // island id -> { el, moduleSrc, propsRaw, instance, handlers, pending, mounted }
+        this.modules = new Map();   // module src -> registered module
+        this.pending = new Map();   // island id -> messages received before the island was rendered
+        this.loadModule = client.options.islandLoader || ((src) => import(/* webpackIgnore: true */ src));
+    }
+
+    /**
+     * Register an island module for a data-module path.
+     * Registered modules are used instead of loading the path.
+     */
+    register(src, module) {
+        this.modules.set(src, module);
+    }
+
+    /**
+     * Mount islands in the current DOM
+     */
+    initializeFromDOM() {
+        document.querySelectorAll('[data-island]').forEach(el => {
+            this.mount(el);
+        });
+    }
+
+    /**
+     * Reconcile islands after DOM changes: destroy removed islands,
+     * update changed props and mount new islands.
+     */
+    updateFromDOM() {
+        for (const [id, entry] of this.instances) {
+            if (!entry.el.isConnected) {
+                this.destroy(id);
+            }
+        }
+
+        document.querySelectorAll('[data-island]').forEach(el => {
+            const entry = this.instances.get(el.dataset.island);
+            if (!entry || entry.el !== el || entry.moduleSrc !== el.dataset.module) {
+                if (entry) this.destroy(el.dataset.island);
+                this.mount(el);
+                return;
+            }
+
+            const propsRaw = el.dataset.props || '';
+            if (entry.propsRaw !== propsRaw) {
+                entry.propsRaw = propsRaw;
+                if (entry.mounted && entry.instance && typeof entry.instance.update === 'function') {
+                    entry.instance.update(this._parseProps(propsRaw));
+                }
+            }
+        });
+
+        // Drop messages for islands that were not rendered by this frame
+        this.pending.clear();
+    }
+
+    /**
+     * Mount a single island element
+     */
+    mount(el) {
+        const id = el.dataset.island;
+        const moduleSrc = el.dataset.module;
+        if (!id || !moduleSrc || this.instances.has(id)) return;
+
+        const entry = {
+            el,
+            moduleSrc,
+            propsRaw: el.dataset.props || '',
+            instance: null,
+            handlers: [],
+            pending: this.pending.get(id) || [],
+            mounted: false,
+            destroyed: false,
+        };
+        this.pending.delete(id);
+        this.instances.set(id, entry);
+
+        const island = {
+            id,
+            send: (data = {}) => this.send(id, data),
+            onMessage: (fn) => {
+                entry.handlers.push(fn);
+                return () => {
+                    entry.handlers = entry.handlers.filter(h => h !== fn);
+                };
+            },
+        };
+
+        const start = (mod) => {
+            if (entry.destroyed) return;
+            const mountFn = mod?.mount || mod?.default?.mount || mod?.default;
+            if (typeof mountFn !== 'function') {
+                console.error('[Vango] Island module has no mount():', moduleSrc);
+                return;
+            }
+            entry.instance = mountFn(el, this._parseProps(entry.propsRaw), island) || null;
+            entry.mounted = true;
+
+            // Deliver messages that arrived while the module was loading
+            const queued = entry.pending;
+            entry.pending = [];
+            for (const data of queued) {
+                this._dispatch(entry, data);
+            }
+        };
+
+        const registered = this.modules.get(moduleSrc);
+        if (registered) {
+            start(registered);
+            return;
+        }
+
+        Promise.resolve()
+            .then(() => this.loadModule(moduleSrc))
+            .then(start)
+            .catch(err => {
+                console.error('[Vango] Failed to load island module:', moduleSrc, err);
+            });
+    }
+
+    /**
+     * Destroy an island by ID
+     */
+    destroy(id) {
+        const entry = this.instances.get(id);
+        if (!entry) return;
+        entry.destroyed = true;
+        this.instances.delete(id);
+
+        const instance = entry.instance;
+        if (typeof instance === 'function') {
+            instance();
+        } else if (instance && typeof instance.destroy === 'function') {
+            instance.destroy();
+        }
+    }
+
+    /**
+     * Destroy all islands
+     */
+    destroyAll() {
+        for (const id of [...this.instances.keys()]) {
+            this.destroy(id);
+        }
+        this.pending.clear();
+    }
+
+    /**
+     * Deliver a server message to an island.
+     * Messages for islands that are not mounted yet are queued.
+     */
+    deliver(id, data) {
+        const entry = this.instances.get(id);
+        if (!entry) {
+            // The island may be inserted by a later patch in the same frame
+            if (!this.pending.has(id)) this.pending.set(id, []);
+            this.pending.get(id).push(data);
+            return;
+        }
+        if (!entry.mounted) {
+            entry.pending.push(data);
+            return;
+        }
+        this._dispatch(entry, data);
+    }
+
+    /**
+     * Send a message from an island to the server
+     */
+    send(id, data = {}) {
+        const depth = Math.max(0, ...Object.values(data || {}).map(v => this._depth(v, 0)));
+        if (depth > MaxIslandDepth) {
+            console.error('[Vango] Island message exceeds max depth, dropped:', id);
+            return false;
+        }
+        return this.client.sendIslandMessage(id, data);
+    }
+
+    _dispatch(entry, data) {
+        for (const fn of entry.handlers) {
+            try {
+                fn(data);
+            } catch (err) {
+                console.error('[Vango] Island message handler error:', err);
+            }
+        }
+    }
+
+    _parseProps(raw) {
+        if (!raw) return {};
+        try {
+            return JSON.parse(raw);
+        } catch (e) {
+            if (this.client.options.debug) {
+                console.warn('[Vango] Invalid island props:', e);
+            }
+            return {};
+        }
+    }
+
+    /**
+     * Nesting depth of a message value, counted like the server decoder.
+     */
+    _depth(value, depth) {
+        if (depth > MaxIslandDepth) return depth;
+        let max = depth;
+        if (Array.isArray(value)) {
+            for (const item of value) {
+                max = Math.max(max, this._depth(item, depth + 1));
+            }
+        } else if (value !== null && typeof value === 'object') {
+            for (const item of Object.values(value)) {
+                max = Math.max(max, this._depth(item, depth + 1));
+            }
+        }
+        return max;
+    }
+}
@@ -5,6 +5,8 @@
  */
 
 import { PatchType, EventType } from './codec.js';
+import { portalTarget, createPortalAnchor, portalAnchor, nestedPortals } from './portals.js';
+import { enter, leave, liveChildren, recordPositions, playMoves } from './transitions.js';
 
 export class PatchApplier {
     constructor(client) {
@@ -15,8 +17,15 @@ export class PatchApplier {
      * Apply array of patches
      */
     apply(patches) {
-        for (const patch of patches) {
-            this.applyPatch(patch);
+        // Positions of transition children, for animating moves (FLIP)
+        this._positions = new Map();
+        try {
+            for (const patch of patches) {
+                this.applyPatch(patch);
+            }
+            playMoves(this._positions);
+        } finally {
+            this._positions = null;
         }
     }
 
@@ -33,6 +42,14 @@ export class PatchApplier {
             case PatchType.NAV_PUSH:
             case PatchType.NAV_REPLACE:
                 return false;
+            // Head patches target document.head, not an HID
+            case PatchType.SET_TITLE:
+            case PatchType.SET_META:
+            case PatchType.SET_LINK:
+                return false;
+            // Island messages are routed by island ID, not HID
+            case PatchType.ISLAND_MESSAGE:
+                return false;
             // INSERT_NODE checks parent separately
             case PatchType.INSERT_NODE:
                 return false;
@@ -175,6 +192,22 @@ export class PatchApplier {
                 this._applyNavPatch(patch);
                 break;
 
+            case PatchType.SET_TITLE:
+                document.title = patch.value;
+                break;
+
+            case PatchType.SET_META:
+                this._setHeadTag('meta', patch.attr, patch.key, 'content', patch.value);
+                break;
+
+            case PatchType.SET_LINK:
+                this._setHeadTag('link', 'rel', patch.key, 'href', patch.value);
+                break;
+
+            case PatchType.ISLAND_MESSAGE:
+                this.client.islands?.deliver(patch.hid, patch.data || {});
+                break;
+
             default:
                 if (this.client.options.debug) {
                     console.warn('[Vango] Unknown patch type:', patch.type);
@@ -294,19 +327,39 @@ export class PatchApplier {
             return;
         }
 
+        recordPositions(parentEl, this._positions);
         const newEl = this._createNode(vnode);
+        parentEl.insertBefore(newEl, this._childAt(parentEl, index));
+        enter(vnode.type === 'portal' ? this.client.getNode(vnode.hid) : newEl);
+    }
 
-        if (index >= parentEl.children.length) {
-            parentEl.appendChild(newEl);
-        } else {
-            parentEl.insertBefore(newEl, parentEl.children[index]);
-        }
+    /**
+     * Return the element child at index, or null past the end. Children
+     * still playing a leave transition are not counted.
+     */
+    _childAt(parentEl, index) {
+        return liveChildren(parentEl)[index] || null;
     }
 
     /**
      * Remove node
      */
     _removeNode(el, hid) {
+        this._cleanupNode(el, hid);
+        recordPositions(el.parentElement, this._positions);
+
+        // Remove from DOM, after its leave transition if it has one
+        portalAnchor(el)?.remove();
+        if (!leave(el, () => el.remove())) {
+            el.remove();
+        }
+    }
+
+    /**
+     * Destroy hooks and unregister el and its descendants, including the
+     * contents of portals declared inside it, which are removed.
+     */
+    _cleanupNode(el, hid) {
         // Cleanup hooks
         this.client.hooks.destroyForNode(el);
 
@@ -319,8 +372,10 @@ export class PatchApplier {
             this.client.unregisterNode(child.dataset.hid);
         });
 
-        // Remove from DOM
-        el.remove();
+        // Portals live outside el but unmount with it
+        for (const wrapper of nestedPortals(el, (h) => this.client.getNode(h))) {
+            this._removeNode(wrapper, wrapper.dataset.hid);
+        }
     }
 
     /**
@@ -335,11 +390,11 @@ export class PatchApplier {
             return;
         }
 
-        if (index >= parentEl.children.length) {
-            parentEl.appendChild(el);
-        } else {
-            parentEl.insertBefore(el, parentEl.children[index]);
-        }
+        recordPositions(parentEl, this._positions);
+
+        // A portal's place among its siblings is held by its anchor
+        const node = portalAnchor(el) || el;
+        parentEl.insertBefore(node, this._childAt(parentEl, index));
     }
 
     /**
@@ -347,16 +402,18 @@ export class PatchApplier {
      */
     _replaceNode(el, hid, vnode) {
         // Cleanup old
-        this.client.hooks.destroyForNode(el);
-        this.client.unregisterNode(hid);
-        el.querySelectorAll('[data-hid]').forEach(child => {
-            this.client.hooks.destroyForNode(child);
-            this.client.unregisterNode(child.dataset.hid);
-        });
+        this._cleanupNode(el, hid);
 
         // Create and insert new
         const newEl = this._createNode(vnode);
-        el.replaceWith(newEl);
+        const anchor = portalAnchor(el);
+        if (anchor) {
+            anchor.replaceWith(newEl);
+            el.remove();
+        } else {
+            el.replaceWith(newEl);
+        }
+        enter(vnode.type === 'portal' ? this.client.getNode(vnode.hid) : newEl);
     }
 
     /**
@@ -370,6 +427,8 @@ export class PatchApplier {
                 return document.createTextNode(vnode.text);
             case 'fragment':
                 return this._createFragment(vnode);
+            case 'portal':
+                return this._createPortal(vnode);
             default:
                 if (this.client.options.debug) {
                     console.warn('[Vango] Unknown vnode type:', vnode.type);
@@ -406,6 +465,29 @@ export class PatchApplier {
         return el;
     }
 
+    /**
+     * Mount a portal's wrapper in its target and return the anchor that
+     * takes its place in the parent.
+     */
+    _createPortal(vnode) {
+        const wrapper = document.createElement('vango-portal');
+        wrapper.dataset.portalTarget = vnode.target;
+        wrapper.style.display = 'contents';
+
+        if (vnode.hid) {
+            wrapper.dataset.hid = vnode.hid;
+            this.client.registerNode(vnode.hid, wrapper);
+        }
+
+        for (const child of vnode.children || []) {
+            wrapper.appendChild(this._createNode(child));
+        }
+
+        const anchor = createPortalAnchor(wrapper);
+        portalTarget(vnode.target).appendChild(wrapper);
+        return anchor;
+    }
+
     /**
      * Create document fragment
      */
@@ -453,6 +535,36 @@ export class PatchApplier {
         target.dispatchEvent(event);
     }
 
+    /**
+     * Create, update or remove a <meta>/<link> tag in document.head.
+     * The tag is identified by attr=key (e.g. name="description" or
+     * rel="canonical"); valueAttr holds its value. An empty value removes it.
+     */
+    _setHeadTag(tag, attr, key, valueAttr, value) {
+        const head = document.head;
+        if (!head || !attr || !key) return;
+
+        let el = null;
+        for (const candidate of head.querySelectorAll(tag)) {
+            if (candidate.getAttribute(attr) === key) {
+                el = candidate;
+                break;
+            }
+        }
+
+        if (!value) {
+            if (el) el.remove();
+            return;
+        }
+
+        if (!el) {
+            el = document.createElement(tag);
+            el.setAttribute(attr, key);
+            head.appendChild(el);
+        }
+        el.setAttribute(valueAttr, value);
+    }
+
     /**
      * Handle server-initiated navigation via NAV_PUSH/NAV_REPLACE patches.
      * This is the contract-compliant implementation that does NOT send
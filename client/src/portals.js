@@ -0,0 +1,71 @@
+/**
+ * Portals
+ *
+ * A portal's children render into another container (vdom.Portal). The
+ * portal itself is a <vango-portal data-hid> wrapper appended to its target,
+ * and an empty <template data-portal-anchor> element stays where the portal
+ * was declared so that sibling indexes in patches still line up with the
+ * server's tree.
+ */
+
+// Portal wrapper -> anchor element.
+const anchors = new WeakMap();
+
+/**
+ * Resolve a portal target selector, falling back to document.body.
+ */
+export function portalTarget(selector) {
+    let target = null;
+    if (selector) {
+        try {
+            target = document.querySelector(selector);
+        } catch (e) {
+            console.warn('[Vango] Invalid portal target:', selector);
+        }
+    }
+    return target || document.body;
+}
+
+/**
+ * Create the anchor left in place of a portal wrapper.
+ */
+export function createPortalAnchor(wrapper) {
+    const anchor = document.createElement('template');
+    anchor.dataset.portalAnchor = wrapper.dataset.hid || '';
+    anchors.set(wrapper, anchor);
+    return anchor;
+}
+
+/**
+ * Return the anchor of a portal wrapper, or null if el is not one.
+ */
+export function portalAnchor(wrapper) {
+    return anchors.get(wrapper) || null;
+}
+
+/**
+ * Return the portal wrappers whose anchors are inside el.
+ */
+export function nestedPortals(el, getNode) {
+    const wrappers = [];
+    el.querySelectorAll('template[data-portal-anchor]').forEach((anchor) => {
+        const wrapper = getNode(anchor.dataset.portalAnchor);
+        if (wrapper && anchors.get(wrapper) === anchor) {
+            wrappers.push(wrapper);
+        }
+    });
+    return wrappers;
+}
+
+/**
+ * Move server-rendered portals, which are rendered in place, into their
+ * targets. Wrappers already mounted are left alone.
+ */
+export function mountPortals(root = document) {
+    root.querySelectorAll('vango-portal[data-portal-target]').forEach((wrapper) => {
+        if (!wrapper.dataset.hid || portalAnchor(wrapper)) return;
+
+        wrapper.parentNode?.insertBefore(createPortalAnchor(wrapper), wrapper);
+        portalTarget(wrapper.dataset.portalTarget).appendChild(wrapper);
+    });
+}
@@ -3,8 +3,14 @@
  *
  * Client-side preference management with cross-tab sync via BroadcastChannel.
  * Works with both anonymous users (LocalStorage) and authenticated users (server sync).
+ *
+ * Stored values are sent to the server in the handshake and changes are sent
+ * as PrefSync control messages, so server-side pref.Pref values match the
+ * client's. Values the server changes arrive as PrefSync and are stored here.
  */
 
+import { versionAtLeast } from './codec.js';
+
 /**
  * Merge strategies for conflict resolution
  */
@@ -93,6 +99,8 @@ export class PrefManager {
             if (pref) {
                 pref._setFromRemote(value, new Date(updatedAt));
             }
+            // This tab's session keeps its own copy of the value.
+            this.sendToServer([{ key, value, updatedAt: new Date(updatedAt) }]);
         }
 
         if (this.options.debug) {
@@ -111,10 +119,16 @@ export class PrefManager {
         const prefKey = event.key.slice(this.options.storagePrefix.length);
         const pref = this.prefs.get(prefKey);
 
-        if (pref && event.newValue) {
+        if (event.newValue) {
             try {
                 const data = JSON.parse(event.newValue);
-                pref._setFromRemote(data.value, new Date(data.updatedAt));
+                const updatedAt = new Date(data.updatedAt);
+                if (pref) {
+                    pref._setFromRemote(data.value, updatedAt);
+                }
+                if (!this.channel) {
+                    this.sendToServer([{ key: prefKey, value: data.value, updatedAt }]);
+                }
             } catch (e) {
                 if (this.options.debug) {
                     console.warn('[Vango Prefs] Failed to parse storage event:', e);
@@ -182,6 +196,91 @@ export class PrefManager {
         }
     }
 
+    /**
+     * List every preference kept in LocalStorage, registered or not, for the
+     * handshake.
+     * @returns {Array<{key: string, value: string, updatedAt: number}>}
+     *   JSON values with Unix millisecond timestamps
+     */
+    storedValues() {
+        if (typeof localStorage === 'undefined') return [];
+
+        const values = [];
+        const prefix = this.options.storagePrefix;
+        try {
+            for (let i = 0; i < localStorage.length; i++) {
+                const storageKey = localStorage.key(i);
+                if (!storageKey || !storageKey.startsWith(prefix)) continue;
+                const key = storageKey.slice(prefix.length);
+                const stored = this.loadFromStorage(key);
+                const updatedAt = stored ? stored.updatedAt.getTime() : NaN;
+                if (!stored || stored.value === undefined || Number.isNaN(updatedAt)) continue;
+                values.push({ key, value: JSON.stringify(stored.value), updatedAt });
+            }
+        } catch (e) {
+            if (this.options.debug) {
+                console.warn('[Vango Prefs] Failed to list storage:', e);
+            }
+        }
+        return values;
+    }
+
+    /**
+     * Send changed preferences to the server in a PrefSync control message.
+     * @param {Array<{key: string, value: *, updatedAt: Date}>} values
+     */
+    sendToServer(values) {
+        const client = this.client;
+        if (!client || !client.connected || !client.codec || values.length === 0) {
+            return;
+        }
+        // Servers speaking 2.1 or older do not know PrefSync
+        if (client.protocolVersion && !versionAtLeast(client.protocolVersion, 2, 2)) {
+            return;
+        }
+
+        const payload = client.codec.encodePrefSync(values.map((v) => ({
+            key: v.key,
+            value: JSON.stringify(v.value),
+            updatedAt: v.updatedAt.getTime(),
+        })));
+        for (const frame of client.codec.encodeFrames(0x03 /* CONTROL */, payload)) {
+            client.wsManager.send(frame);
+        }
+    }
+
+    /**
+     * Handle a PrefSync message from the server. The server has already
+     * merged these values, so they replace the local ones.
+     * @param {Array<{key: string, value: string, updatedAt: number}>} values
+     */
+    handleSync(values) {
+        for (const { key, value: raw, updatedAt: ms } of values) {
+            let value;
+            try {
+                value = JSON.parse(raw);
+            } catch (e) {
+                if (this.options.debug) {
+                    console.warn('[Vango Prefs] Invalid value from server:', key, e);
+                }
+                continue;
+            }
+            const updatedAt = new Date(ms);
+
+            const pref = this.prefs.get(key);
+            if (pref) {
+                pref._setFromServer(value, updatedAt);
+            } else {
+                this.saveToStorage(key, value, updatedAt);
+            }
+            this.broadcast(key, value, updatedAt);
+        }
+
+        if (this.options.debug) {
+            console.log('[Vango Prefs] Sync received:', values);
+        }
+    }
+
     /**
      * Sync all preferences with server
      * Called when user logs in
@@ -233,6 +332,9 @@ export class Pref {
         this.value = defaultValue;
         this.updatedAt = new Date();
 
+        // Remote value awaiting resolve() (PROMPT strategy)
+        this.pendingConflict = null;
+
         this.options = {
             mergeStrategy: MergeStrategy.LWW,
             persistLocal: true,
@@ -294,6 +396,38 @@ export class Pref {
         this.set(this.defaultValue);
     }
 
+    /**
+     * Get the remote value awaiting a decision (PROMPT strategy)
+     * @returns {{value: *, updatedAt: Date}|null}
+     */
+    conflict() {
+        return this.pendingConflict;
+    }
+
+    /**
+     * Settle a pending conflict
+     * @param {boolean} useRemote - Take the remote value instead of keeping the current one
+     */
+    resolve(useRemote) {
+        const pending = this.pendingConflict;
+        if (!pending) return;
+        this.pendingConflict = null;
+
+        if (useRemote) {
+            this._setFromServer(pending.value, pending.updatedAt);
+        } else {
+            // Re-stamp the kept value so it supersedes the remote one
+            this.updatedAt = new Date();
+            if (this.options.persistLocal) {
+                this.manager.saveToStorage(this.key, this.value, this.updatedAt);
+            }
+            this.manager.broadcast(this.key, this.value, this.updatedAt);
+            if (this.options.syncToServer) {
+                this._syncToServer();
+            }
+        }
+    }
+
     /**
      * Subscribe to value changes
      * @param {Function} callback - Called with (newValue, oldValue)
@@ -344,6 +478,28 @@ export class Pref {
         }
     }
 
+    /**
+     * Apply a value already merged by the server
+     */
+    _setFromServer(value, serverUpdatedAt) {
+        this.pendingConflict = null;
+        this.updatedAt = serverUpdatedAt;
+
+        if (this.options.persistLocal) {
+            this.manager.saveToStorage(this.key, value, serverUpdatedAt);
+        }
+
+        if (!this._isEqual(this.value, value)) {
+            const oldValue = this.value;
+            this.value = value;
+            this._notifySubscribers(value, oldValue);
+
+            if (this.options.onChange) {
+                this.options.onChange(value, oldValue);
+            }
+        }
+    }
+
     /**
      * Merge with server value (called on login)
      */
@@ -393,9 +549,11 @@ export class Pref {
                 return remoteTime > localTime ? remote : local;
 
             case MergeStrategy.PROMPT:
-                // For PROMPT, we'd need to show UI to user
-                // For now, fall back to LWW
-                return remoteTime > localTime ? remote : local;
+                // Keep the local value until resolve() is called
+                if (!this._isEqual(local, remote)) {
+                    this.pendingConflict = { value: remote, updatedAt: remoteTime };
+                }
+                return local;
 
             default:
                 return local;
@@ -406,20 +564,11 @@ export class Pref {
      * Sync preference to server
      */
     _syncToServer() {
-        if (!this.manager.client || !this.manager.client.connected) {
-            return;
-        }
-
-        // Send preference update event
-        this.manager.client.sendEvent(
-            0x10, // EventType.PREF (custom event type for preferences)
-            '',   // No HID needed
-            {
-                key: this.key,
-                value: this.value,
-                updatedAt: this.updatedAt.toISOString(),
-            }
-        );
+        this.manager.sendToServer([{
+            key: this.key,
+            value: this.value,
+            updatedAt: this.updatedAt,
+        }]);
     }
 
     /**
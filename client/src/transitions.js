@@ -0,0 +1,253 @@
+/**
+ * Transitions
+ *
+ * Elements marked with vdom.Transition (data-transition="name") animate
+ * when they, or their element children, are inserted, removed or moved by
+ * patches. Class names follow the name-enter-from / name-enter-active /
+ * name-enter-to, name-leave-* and name-move convention.
+ *
+ * A leaving element has already been unregistered when its animation
+ * starts. It is marked as leaving so that patch indexes skip it, and it is
+ * removed once its transition or animation ends or the timeout elapses.
+ */
+
+// Element -> function that ends its running enter/leave/move transition.
+const running = new WeakMap();
+
+// Elements waiting for their leave transition before removal.
+const leaving = new WeakSet();
+
+/**
+ * Return the element whose data-transition applies to el: el itself or
+ * its parent element.
+ */
+function transitionSource(el) {
+    if (el?.nodeType !== 1) return null;
+    if (el.hasAttribute('data-transition')) return el;
+    const parent = el.parentElement;
+    if (parent?.hasAttribute('data-transition')) return parent;
+    return null;
+}
+
+/**
+ * Return the transition name applying to el, or '' if it has none.
+ */
+export function transitionName(el) {
+    return transitionSource(el)?.dataset.transition || '';
+}
+
+/**
+ * Report whether el is waiting for its leave transition.
+ */
+export function isLeaving(el) {
+    return leaving.has(el);
+}
+
+/**
+ * Return the element children of parent that are not leaving.
+ */
+export function liveChildren(parent) {
+    return Array.from(parent.children).filter((child) => !leaving.has(child));
+}
+
+/**
+ * Run fn on the next animation frame.
+ */
+function nextFrame(fn) {
+    if (typeof requestAnimationFrame === 'function') {
+        requestAnimationFrame(fn);
+    } else {
+        setTimeout(fn, 0);
+    }
+}
+
+/**
+ * Parse a CSS time list ("0.3s, 150ms") into milliseconds.
+ */
+function parseTimes(value) {
+    return (value || '').split(',').map((part) => {
+        const s = part.trim();
+        const n = parseFloat(s);
+        if (Number.isNaN(n)) return 0;
+        return s.endsWith('ms') ? n : n * 1000;
+    });
+}
+
+/**
+ * Return the longest duration + delay among parallel CSS time lists.
+ */
+function longest(durations, delays) {
+    const d = parseTimes(durations);
+    const w = parseTimes(delays);
+    return d.reduce((max, ms, i) => Math.max(max, ms + (w[i % w.length] || 0)), 0);
+}
+
+/**
+ * Return how long to wait for el's transition, in milliseconds: the
+ * data-transition-timeout of its source, or the longest transition or
+ * animation in its computed style.
+ */
+function transitionTimeout(el) {
+    const explicit = transitionSource(el)?.dataset.transitionTimeout;
+    if (explicit !== undefined && explicit !== '') {
+        return Math.max(0, parseInt(explicit, 10) || 0);
+    }
+    if (typeof getComputedStyle !== 'function') return 0;
+    const style = getComputedStyle(el);
+    return Math.max(
+        longest(style.transitionDuration, style.transitionDelay),
+        longest(style.animationDuration, style.animationDelay),
+    );
+}
+
+/**
+ * Call done once el's transition or animation ends, or its timeout
+ * elapses. Returns a function that ends the wait early.
+ */
+function whenDone(el, done) {
+    let finished = false;
+    let timer = null;
+
+    const finish = () => {
+        if (finished) return;
+        finished = true;
+        clearTimeout(timer);
+        el.removeEventListener('transitionend', onEnd);
+        el.removeEventListener('animationend', onEnd);
+        running.delete(el);
+        done();
+    };
+    // Ignore events bubbling from descendants
+    const onEnd = (event) => {
+        if (event.target === el) finish();
+    };
+
+    el.addEventListener('transitionend', onEnd);
+    el.addEventListener('animationend', onEnd);
+    timer = setTimeout(finish, transitionTimeout(el));
+    running.set(el, finish);
+    return finish;
+}
+
+/**
+ * Play the from -> to class sequence of a transition on el.
+ */
+function play(el, name, phase, done) {
+    running.get(el)?.();
+
+    const from = `${name}-${phase}-from`;
+    const active = `${name}-${phase}-active`;
+    const to = `${name}-${phase}-to`;
+
+    let finished = false;
+    el.classList.add(from, active);
+    whenDone(el, () => {
+        finished = true;
+        el.classList.remove(from, active, to);
+        done?.();
+    });
+    nextFrame(() => {
+        if (finished) return;
+        el.classList.remove(from);
+        el.classList.add(to);
+    });
+}
+
+/**
+ * Return the elements that animate for a node inserted or removed by a
+ * patch: the node itself, or for a portal the children with their own
+ * transitions.
+ */
+function animated(el) {
+    if (el?.nodeType !== 1) return [];
+    if (el.hasAttribute('data-portal-target')) {
+        return Array.from(el.children).filter((child) => child.hasAttribute('data-transition'));
+    }
+    return transitionName(el) ? [el] : [];
+}
+
+/**
+ * Start the enter transition of a newly inserted node.
+ */
+export function enter(el) {
+    for (const target of animated(el)) {
+        play(target, transitionName(target), 'enter');
+    }
+}
+
+/**
+ * Start the leave transition of a removed node and call remove when it
+ * ends. Returns false, without calling remove, if the node has no
+ * transition.
+ */
+export function leave(el, remove) {
+    const targets = animated(el);
+    if (targets.length === 0) return false;
+
+    leaving.add(el);
+    el.setAttribute('inert', '');
+    let pending = targets.length;
+    for (const target of targets) {
+        play(target, transitionName(target), 'leave', () => {
+            if (--pending === 0) {
+                leaving.delete(el);
+                remove();
+            }
+        });
+    }
+    return true;
+}
+
+/**
+ * Record the positions of parent's children before patches move them, if
+ * parent has a transition. positions maps parent -> Map(child -> rect).
+ */
+export function recordPositions(parent, positions) {
+    if (!positions || !parent?.hasAttribute?.('data-transition') || positions.has(parent)) return;
+
+    const rects = new Map();
+    for (const child of liveChildren(parent)) {
+        rects.set(child, child.getBoundingClientRect());
+    }
+    positions.set(parent, rects);
+}
+
+/**
+ * Animate children whose position changed since recordPositions (FLIP):
+ * each is moved back to its old position with a transform, then released
+ * with the name-move class applied.
+ */
+export function playMoves(positions) {
+    const moved = [];
+    for (const [parent, rects] of positions) {
+        const name = parent.dataset.transition;
+        for (const child of liveChildren(parent)) {
+            const before = rects.get(child);
+            if (!before) continue;
+            const after = child.getBoundingClientRect();
+            const dx = before.left - after.left;
+            const dy = before.top - after.top;
+            if (dx || dy) {
+                moved.push({ child, name, dx, dy });
+            }
+        }
+    }
+    if (moved.length === 0) return;
+
+    // Invert
+    for (const { child, dx, dy } of moved) {
+        running.get(child)?.();
+        child.style.transform = `translate(${dx}px, ${dy}px)`;
+        child.style.transitionDuration = '0s';
+    }
+    // Force a reflow so the inverted positions are painted
+    void document.body.offsetHeight;
+    // Play
+    for (const { child, name } of moved) {
+        const moveClass = `${name}-move`;
+        child.classList.add(moveClass);
+        child.style.transform = '';
+        child.style.transitionDuration = '';
+        whenDone(child, () => child.classList.remove(moveClass));
+    }
+}
@@ -0,0 +1,175 @@
+/**
+ * Live File Uploads
+ *
+ * Streams files picked in (or dropped on) an element with an OnUpload
+ * handler over the live connection as FrameBlob frames:
+ *
+ *   - BlobStart opens the upload, then the file is sent in BlobChunkSize
+ *     chunks and closed with BlobEnd
+ *   - The server acknowledges stored bytes with BLOB_ACK control messages;
+ *     at most BlobWindow bytes beyond the last ack are in flight, so a slow
+ *     store slows the upload down instead of buffering it in memory
+ *
+ * Progress is dispatched on the element as a bubbling 'vango:upload' event.
+ * Uploads do not survive a reconnect.
+ */
+
+import { BlobOp, BlobStatus } from './codec.js';
+
+/**
+ * Must match pkg/protocol/blob.go
+ */
+export const BlobChunkSize = 32 * 1024;
+export const BlobWindow = 8 * BlobChunkSize;
+
+const FrameBlob = 0x06;
+
+export class BlobUploader {
+    constructor(client) {
+        this.client = client;
+        this.enabled = false; // Set when the server advertises ServerFlagBinaryBlobs
+        this.nextId = 1;
+        this.uploads = new Map(); // id -> upload state
+    }
+
+    /**
+     * Start streaming a file for the element with the given HID.
+     * Returns the upload ID, or 0 if the server does not accept blobs.
+     */
+    upload(hid, file) {
+        if (!this.enabled || !this.client.connected) {
+            return 0;
+        }
+
+        const id = this.nextId++;
+        const up = {
+            id,
+            hid,
+            file,
+            sent: 0,
+            acked: 0,
+            ended: false,
+            pumping: false,
+        };
+        this.uploads.set(id, up);
+
+        this._send(this.client.codec.encodeBlob(BlobOp.START, id, {
+            hid,
+            filename: file.name,
+            type: file.type,
+            size: file.size,
+        }));
+        this._dispatch(up, 'progress');
+        this._pump(up);
+        return id;
+    }
+
+    /**
+     * Abandon an upload.
+     */
+    cancel(id) {
+        const up = this.uploads.get(id);
+        if (!up) return;
+        this.uploads.delete(id);
+        this._send(this.client.codec.encodeBlob(BlobOp.CANCEL, id));
+        this._dispatch(up, 'error', 'canceled');
+    }
+
+    /**
+     * Handle a BLOB_ACK control payload.
+     */
+    handleAck(buffer) {
+        const { id, received, status, message } = this.client.codec.decodeBlobAck(buffer);
+        const up = this.uploads.get(id);
+        if (!up) return;
+
+        if (received > up.acked) {
+            up.acked = received;
+        }
+
+        switch (status) {
+            case BlobStatus.PROGRESS:
+                this._dispatch(up, 'progress');
+                this._pump(up);
+                break;
+            case BlobStatus.DONE:
+                this.uploads.delete(id);
+                this._dispatch(up, 'done');
+                break;
+            case BlobStatus.ERROR:
+                this.uploads.delete(id);
+                this._dispatch(up, 'error', message);
+                break;
+        }
+    }
+
+    /**
+     * Fail every upload in flight. Called when the connection drops.
+     */
+    reset() {
+        const uploads = [...this.uploads.values()];
+        this.uploads.clear();
+        for (const up of uploads) {
+            this._dispatch(up, 'error', 'disconnected');
+        }
+    }
+
+    /**
+     * Send chunks while the window allows, then BlobEnd.
+     */
+    async _pump(up) {
+        if (up.pumping) return;
+        up.pumping = true;
+        try {
+            while (this.uploads.get(up.id) === up && up.sent < up.file.size) {
+                const end = Math.min(up.sent + BlobChunkSize, up.file.size);
+                if (end - up.acked > BlobWindow) {
+                    return; // Resumed by the next ack
+                }
+                const data = new Uint8Array(await up.file.slice(up.sent, end).arrayBuffer());
+                if (this.uploads.get(up.id) !== up) {
+                    return;
+                }
+                this._send(this.client.codec.encodeBlob(BlobOp.CHUNK, up.id, {
+                    offset: up.sent,
+                    data,
+                }));
+                up.sent = end;
+            }
+            if (this.uploads.get(up.id) === up && !up.ended) {
+                up.ended = true;
+                this._send(this.client.codec.encodeBlob(BlobOp.END, up.id));
+            }
+        } catch (err) {
+            // The file could not be read (e.g. it was deleted)
+            if (this.uploads.get(up.id) === up) {
+                this.cancel(up.id);
+            }
+            this.client._onError(err);
+        } finally {
+            up.pumping = false;
+        }
+    }
+
+    _send(payload) {
+        for (const frame of this.client.codec.encodeFrames(FrameBlob, payload)) {
+            this.client.wsManager.send(frame);
+        }
+    }
+
+    _dispatch(up, status, error) {
+        const el = this.client.nodeMap.get(up.hid);
+        if (!el) return;
+        el.dispatchEvent(new CustomEvent('vango:upload', {
+            detail: {
+                id: up.id,
+                name: up.file.name,
+                size: up.file.size,
+                loaded: up.acked,
+                status,
+                error: error || null,
+            },
+            bubbles: true,
+        }));
+    }
+}
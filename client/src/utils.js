@@ -77,3 +77,17 @@ export function throttle(fn, limit) {
         }
     };
 }
+
+/**
+ * Convert a form entry value to the string sent to the server.
+ * File inputs submit the file name; file contents are sent through the
+ * upload endpoint, not the event stream.
+ * @param {string|File} value - FormData entry value
+ * @returns {string} String value
+ */
+export function formValueString(value) {
+    if (typeof File !== 'undefined' && value instanceof File) {
+        return value.name;
+    }
+    return String(value ?? '');
+}
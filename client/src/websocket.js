@@ -4,6 +4,17 @@
  * Handles WebSocket connection lifecycle, reconnection, and message routing.
  */
 
+import { SupportedCodecs, ServerFlagBinaryBlobs, ProtocolVersion } from './codec.js';
+import { HTTPFallbackSocket, httpFallbackUrl } from './fallback.js';
+import { readClientEnv } from './env.js';
+
+// Minimum time between reloads caused by protocol version mismatches
+const VersionReloadInterval = 30000;
+
+function formatVersion(v) {
+    return `${v.major}.${v.minor}`;
+}
+
 export class WebSocketManager {
     constructor(client, options = {}) {
         this.client = client;
@@ -25,6 +36,12 @@ export class WebSocketManager {
         this.heartbeatTimer = null;
         this.messageQueue = [];
 
+        // Set once a WebSocket fails before opening (e.g. a proxy rejected the
+        // upgrade); later connects use SSE + POST instead.
+        this.useHTTPFallback = options.transport === 'sse';
+        this.fellBack = false;
+        this.opened = false;
+
         // Session durability: persist sessionId across reloads within the same tab.
         // Use sessionStorage (not localStorage) to preserve "one tab = one session".
         const resume = this._loadResumeInfo();
@@ -43,7 +60,10 @@ export class WebSocketManager {
         }
 
         this.url = url;
-        this.ws = new WebSocket(url);
+        this.opened = false;
+        this.ws = this.useHTTPFallback
+            ? new HTTPFallbackSocket(httpFallbackUrl(url))
+            : new WebSocket(url);
         this.ws.binaryType = 'arraybuffer';
 
         this.ws.onopen = () => this._onOpen();
@@ -60,6 +80,7 @@ export class WebSocketManager {
             console.log('[Vango] WebSocket connected');
         }
 
+        this.opened = true;
         this.reconnectAttempts = 0;
 
         // Send handshake
@@ -74,17 +95,23 @@ export class WebSocketManager {
      * Per spec: All protocol messages use consistent framing.
      */
     _sendHandshake() {
+        const env = readClientEnv();
         const helloFrame = this.client.codec.encodeClientHelloFrame({
             csrf: this._getCSRFToken(),
             sessionId: this.sessionId || '',
             // Last patch sequence we successfully applied. Used by the server to
             // reason about resync/replay on resume.
             lastSeq: this.lastSeq || this.client.patchSeq || 0,
-            viewportW: window.innerWidth,
-            viewportH: window.innerHeight,
+            viewportW: env.viewportW,
+            viewportH: env.viewportH,
+            locale: env.locale,
+            prefs: env.prefs,
+            prefValues: this.client.prefs?.storedValues() || [],
+            codecs: this.client.options.compression === false ? [] : SupportedCodecs,
         });
 
         this.ws.send(helloFrame);
+        this.client.env?.markReported(env);
 
         if (this.client.options.debug) {
             console.log('[Vango] Sent framed ClientHello');
@@ -141,7 +168,9 @@ export class WebSocketManager {
                     0x09: 'Too many active sessions from this IP',
                 };
                 const msg = errorMessages[hello.status] || `Handshake failed: ${hello.status}`;
-                const err = new Error(msg);
+                const err = new Error(hello.minVersion
+                    ? `${msg}: client speaks ${formatVersion(ProtocolVersion)}, server ${formatVersion(hello.minVersion)}-${formatVersion(hello.version)}`
+                    : msg);
                 if (hello.authReason !== undefined) {
                     err.vangoAuthReason = hello.authReason;
                     if (this.client.options.debug) {
@@ -150,6 +179,12 @@ export class WebSocketManager {
                 }
                 this.client._onError(err);
 
+                // The page was served by a deploy this server no longer
+                // speaks to; reload to fetch a matching thin client.
+                if (hello.status === 0x01 /* Version mismatch */ || hello.status === 0x05 /* Upgrade required */) {
+                    this._reloadForVersion(hello);
+                }
+
                 if (hello.status === 0x09 /* Limit exceeded */) {
                     this.reconnectDisabled = true;
                     this.client.connection.setDisconnected('ip_limit');
@@ -170,6 +205,22 @@ export class WebSocketManager {
                 return;
             }
 
+            // The server's compression stream and any partial messages start
+            // fresh with every handshake.
+            this.client.reassembler.reset();
+            try {
+                this.client.decompressor = this.client.codec.createDecompressor(hello.codec);
+            } catch (err) {
+                this.client._handleProtocolError(err, 'handshake');
+                return;
+            }
+
+            this.client.protocolVersion = hello.version;
+
+            if (this.client.uploads) {
+                this.client.uploads.enabled = (hello.flags & ServerFlagBinaryBlobs) !== 0;
+            }
+
             this.handshakeComplete = true;
             this.connected = true;
             this.sessionId = hello.sessionId;
@@ -189,6 +240,33 @@ export class WebSocketManager {
         this.client._handleBinaryMessage(buffer);
     }
 
+    /**
+     * Reload after the server rejected our protocol version. A reload that
+     * lands on the same mismatch (e.g. a cached old bundle) is not retried
+     * for a while, so the tab does not loop.
+     */
+    _reloadForVersion(hello) {
+        this.reconnectDisabled = true;
+        this.options.reconnect = false;
+        this.client.connection.setDisconnected('version');
+
+        const key = '__vango_version_reload';
+        const now = Date.now();
+        if (this._storageAvailable()) {
+            try {
+                const last = Number(sessionStorage.getItem(key) || 0);
+                if (now - last < VersionReloadInterval) {
+                    return;
+                }
+                sessionStorage.setItem(key, String(now));
+            } catch {
+                // Storage errors just skip the loop guard
+            }
+        }
+        this._clearResumeInfo();
+        setTimeout(() => location.reload(), 0);
+    }
+
     /**
      * Handle WebSocket close
      *
@@ -219,12 +297,41 @@ export class WebSocketManager {
             this.client._onDisconnected();
         }
 
+        // A WebSocket that never opened usually means the upgrade was
+        // blocked; retry right away over the HTTP fallback.
+        if (!this.opened && !this.useHTTPFallback && this._httpFallbackAvailable()) {
+            if (this.client.options.debug) {
+                console.log('[Vango] WebSocket upgrade failed, falling back to SSE');
+            }
+            this.useHTTPFallback = true;
+            this.fellBack = true;
+            this.connect(this.url);
+            return;
+        }
+
+        // The fallback is off by default on the server; if it never opened
+        // either, go back to WebSocket for the next attempt.
+        if (!this.opened && this.fellBack) {
+            this.useHTTPFallback = false;
+            this.fellBack = false;
+        }
+
         // Reconnect if enabled and not a clean close
         if (this.options.reconnect && !this.reconnectDisabled && !event.wasClean) {
             this._scheduleReconnect();
         }
     }
 
+    /**
+     * Whether the SSE + POST fallback can be used in this browser.
+     */
+    _httpFallbackAvailable() {
+        return this.client.options.httpFallback !== false &&
+            !this.reconnectDisabled &&
+            typeof EventSource !== 'undefined' &&
+            typeof fetch !== 'undefined';
+    }
+
     /**
      * Handle WebSocket error
      */
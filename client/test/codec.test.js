@@ -164,6 +164,23 @@ describe('BinaryCodec', () => {
             expect(buffer.length).toBeGreaterThan(10);
         });
 
+        test('encodes repeated form values as repeated pairs', () => {
+            const fromObject = codec.encodeEvent(1, EventType.SUBMIT, 'h1', {
+                tags: ['go', 'web'],
+                name: 'John',
+            });
+
+            const formData = new FormData();
+            formData.append('tags', 'go');
+            formData.append('tags', 'web');
+            formData.append('name', 'John');
+            const fromFormData = codec.encodeEvent(1, EventType.SUBMIT, 'h1', formData);
+
+            expect(Array.from(fromObject)).toEqual(Array.from(fromFormData));
+            // seq + type + hid("h1") + pair count
+            expect(fromObject[1 + 1 + 3]).toBe(3);
+        });
+
         test('encodes keyboard event', () => {
             const buffer = codec.encodeEvent(1, EventType.KEYDOWN, 'h1', {
                 key: 'Enter',
@@ -174,6 +191,71 @@ describe('BinaryCodec', () => {
             });
             expect(buffer.length).toBeGreaterThan(5);
         });
+
+        test('encodes island event as hook data', () => {
+            const buffer = codec.encodeEvent(1, EventType.ISLAND, 'chart', { data: { point: 3 } });
+            // seq + type + id string
+            const header = 1 + 1 + codec.encodeString('chart').length;
+            expect(buffer[1]).toBe(EventType.ISLAND);
+
+            const { value, bytesRead } = codec.decodeHookData(buffer, header);
+            expect(value).toEqual({ point: 3 });
+            expect(header + bytesRead).toBe(buffer.length);
+        });
+
+        test('encodes pointer event after the mouse fields', () => {
+            const mouse = codec.encodeEvent(1, EventType.MOUSEDOWN, 'h1', { clientX: 5 });
+            const buffer = codec.encodeEvent(1, EventType.POINTERDOWN, 'h1', {
+                clientX: 5,
+                pointerId: 2,
+                pointerType: 'pen',
+                pressure: 0.5,
+                isPrimary: true,
+            });
+            const tail = buffer.slice(mouse.length);
+
+            // pointerId(zigzag 2) + "pen" + pressure, width, height + tilts + primary
+            expect(tail[0]).toBe(4);
+            expect(Array.from(tail.slice(1, 5))).toEqual([3, 0x70, 0x65, 0x6e]);
+            expect(new DataView(tail.buffer, tail.byteOffset + 5, 8).getFloat64(0, true)).toBe(0.5);
+            expect(tail.length).toBe(1 + 4 + 24 + 2 + 1);
+            expect(tail[tail.length - 1]).toBe(1);
+        });
+
+        test('encodes drag event with data transfer', () => {
+            const buffer = codec.encodeEvent(1, EventType.DROP, 'h1', {
+                clientX: 1,
+                clientY: 2,
+                pageX: 3,
+                pageY: 4,
+                shiftKey: true,
+                types: ['Files'],
+                files: [{ name: 'a', type: 'b', size: 300 }],
+                data: { x: 'y' },
+            });
+            const header = 1 + 1 + 3;
+            expect(Array.from(buffer.slice(header))).toEqual([
+                2, 4, 6, 8, 0x02,
+                1, 5, 0x46, 0x69, 0x6c, 0x65, 0x73,
+                1, 1, 0x61, 1, 0x62, 0xac, 0x02,
+                1, 1, 0x78, 1, 0x79,
+            ]);
+        });
+
+        test('encodes media, clipboard and toggle events', () => {
+            const header = 1 + 1 + 3;
+            const media = codec.encodeEvent(1, EventType.TIMEUPDATE, 'h1', {
+                currentTime: 1.5, duration: NaN, paused: true, volume: 1, playbackRate: 1,
+            });
+            expect(media.length).toBe(header + 8 + 8 + 1 + 1 + 8 + 1 + 8);
+            expect(new DataView(media.buffer, header + 8, 8).getFloat64(0, true)).toBe(0);
+
+            const paste = codec.encodeEvent(1, EventType.PASTE, 'h1', { text: 'hi', types: [], files: [] });
+            expect(Array.from(paste.slice(header))).toEqual([2, 0x68, 0x69, 0, 0]);
+
+            const toggle = codec.encodeEvent(1, EventType.TOGGLE, 'h1', { open: true });
+            expect(Array.from(toggle.slice(header))).toEqual([1]);
+        });
     });
 
     describe('patch decoding', () => {
@@ -266,6 +348,97 @@ describe('BinaryCodec', () => {
             expect(patches[1].type).toBe(PatchType.ADD_CLASS);
             expect(patches[1].className).toBe('visible');
         });
+
+        test('decodes head patches', () => {
+            const parts = [
+                codec.encodeUvarint(2), // seq
+                codec.encodeUvarint(3), // count
+                new Uint8Array([PatchType.SET_TITLE]),
+                codec.encodeString(''),
+                codec.encodeString('Projects'),
+                new Uint8Array([PatchType.SET_META]),
+                codec.encodeString(''),
+                codec.encodeString('property'),
+                codec.encodeString('og:title'),
+                codec.encodeString('All projects'),
+                new Uint8Array([PatchType.SET_LINK]),
+                codec.encodeString(''),
+                codec.encodeString('canonical'),
+                codec.encodeString('https://example.com/projects'),
+            ];
+
+            let totalLength = 0;
+            for (const p of parts) totalLength += p.length;
+            const buffer = new Uint8Array(totalLength);
+            let offset = 0;
+            for (const p of parts) {
+                buffer.set(p, offset);
+                offset += p.length;
+            }
+
+            const { patches } = codec.decodePatches(buffer);
+
+            expect(patches.length).toBe(3);
+            expect(patches[0].type).toBe(PatchType.SET_TITLE);
+            expect(patches[0].value).toBe('Projects');
+            expect(patches[1].type).toBe(PatchType.SET_META);
+            expect(patches[1].attr).toBe('property');
+            expect(patches[1].key).toBe('og:title');
+            expect(patches[1].value).toBe('All projects');
+            expect(patches[2].type).toBe(PatchType.SET_LINK);
+            expect(patches[2].key).toBe('canonical');
+            expect(patches[2].value).toBe('https://example.com/projects');
+        });
+
+        test('decodes island message patch', () => {
+            const parts = [
+                codec.encodeUvarint(3), // seq
+                codec.encodeUvarint(1), // count
+                new Uint8Array([PatchType.ISLAND_MESSAGE]),
+                codec.encodeString('chart'),
+            ];
+            codec.encodeHookData(parts, {
+                series: [1, 2.5, 'x', null],
+                opts: { stacked: true },
+            });
+
+            let totalLength = 0;
+            for (const p of parts) totalLength += p.length;
+            const buffer = new Uint8Array(totalLength);
+            let offset = 0;
+            for (const p of parts) {
+                buffer.set(p, offset);
+                offset += p.length;
+            }
+
+            const { patches } = codec.decodePatches(buffer);
+
+            expect(patches.length).toBe(1);
+            expect(patches[0].type).toBe(PatchType.ISLAND_MESSAGE);
+            expect(patches[0].hid).toBe('chart');
+            expect(patches[0].data).toEqual({
+                series: [1, 2.5, 'x', null],
+                opts: { stacked: true },
+            });
+        });
+
+        test('rejects island message data nested too deeply', () => {
+            let deep = 1;
+            for (let i = 0; i < 70; i++) deep = [deep];
+            const parts = [];
+            codec.encodeHookData(parts, { deep });
+
+            let totalLength = 0;
+            for (const p of parts) totalLength += p.length;
+            const buffer = new Uint8Array(totalLength);
+            let offset = 0;
+            for (const p of parts) {
+                buffer.set(p, offset);
+                offset += p.length;
+            }
+
+            expect(() => codec.decodeHookData(buffer, 0)).toThrow(/depth/);
+        });
     });
 
     describe('VNode decoding', () => {
@@ -349,6 +522,35 @@ describe('BinaryCodec', () => {
             expect(vnode.attrs.class).toBe('btn primary');
             expect(vnode.attrs.type).toBe('submit');
         });
+
+        test('decodes portal', () => {
+            // VNode format: [type:0x06][target][hid][childCount][children...]
+            const parts = [
+                new Uint8Array([0x06]), // PORTAL type
+                codec.encodeString('#modals'),
+                codec.encodeString('h3'), // hid
+                codec.encodeUvarint(1), // 1 child
+                new Uint8Array([0x02]), // TEXT type
+                codec.encodeString('Hi'),
+            ];
+
+            let totalLength = 0;
+            for (const p of parts) totalLength += p.length;
+            const buffer = new Uint8Array(totalLength);
+            let offset = 0;
+            for (const p of parts) {
+                buffer.set(p, offset);
+                offset += p.length;
+            }
+
+            const { vnode, bytesRead } = codec.decodeVNode(buffer, 0);
+
+            expect(vnode.type).toBe('portal');
+            expect(vnode.target).toBe('#modals');
+            expect(vnode.hid).toBe('h3');
+            expect(vnode.children).toEqual([{ type: 'text', text: 'Hi' }]);
+            expect(bytesRead).toBe(buffer.length);
+        });
     });
 });
 
@@ -375,8 +577,22 @@ describe('EventType constants', () => {
 
     test('special events have correct values', () => {
         expect(EventType.HOOK).toBe(0x60);
+        expect(EventType.ISLAND).toBe(0x61);
         expect(EventType.NAVIGATE).toBe(0x70);
     });
+
+    test('pointer, drag, clipboard and media events match the server', () => {
+        expect(EventType.CONTEXTMENU).toBe(0x09);
+        expect(EventType.DRAGENTER).toBe(0x5B);
+        expect(EventType.DRAG).toBe(0x5E);
+        expect(EventType.POINTERDOWN).toBe(0x80);
+        expect(EventType.POINTERCANCEL).toBe(0x85);
+        expect(EventType.COPY).toBe(0x90);
+        expect(EventType.PASTE).toBe(0x92);
+        expect(EventType.TOGGLE).toBe(0x98);
+        expect(EventType.PLAY).toBe(0xA0);
+        expect(EventType.PLAYING).toBe(0xAE);
+    });
 });
 
 describe('PatchType constants', () => {
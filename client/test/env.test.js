@@ -0,0 +1,120 @@
+/**
+ * Client environment tests
+ *
+ * The handshake and CLIENT_ENV control messages carry the viewport,
+ * timezone, locale and preferences read by pkg/server/client_env.go.
+ */
+
+import { describe, test, expect, beforeEach, afterEach, jest } from '@jest/globals';
+import { BinaryCodec } from '../src/codec.js';
+import { ClientEnvReporter, ClientPref, readClientEnv } from '../src/env.js';
+
+function mockMatchMedia(matching) {
+    const lists = {};
+    window.matchMedia = (query) => {
+        if (!lists[query]) {
+            lists[query] = {
+                matches: matching.includes(query),
+                listeners: [],
+                addEventListener(type, fn) { this.listeners.push(fn); },
+                removeEventListener() {},
+            };
+        }
+        return lists[query];
+    };
+    return lists;
+}
+
+describe('readClientEnv', () => {
+    afterEach(() => {
+        delete window.matchMedia;
+    });
+
+    test('reads preferences from media queries', () => {
+        mockMatchMedia(['(prefers-color-scheme: dark)', '(prefers-reduced-motion: reduce)']);
+        const env = readClientEnv();
+        expect(env.prefs).toBe(ClientPref.DARK | ClientPref.REDUCED_MOTION);
+        expect(env.viewportW).toBe(window.innerWidth);
+        expect(env.tzOffset).toBe(-new Date().getTimezoneOffset());
+        expect(env.locale).toBe(navigator.language);
+    });
+
+    test('no matchMedia means no preferences', () => {
+        expect(readClientEnv().prefs).toBe(0);
+    });
+});
+
+describe('ClientHello env', () => {
+    const codec = new BinaryCodec();
+
+    test('locale and prefs follow an empty codec list', () => {
+        const base = codec.encodeClientHello({ viewportW: 10, viewportH: 20 });
+        const withEnv = codec.encodeClientHello({ viewportW: 10, viewportH: 20, locale: 'de', prefs: ClientPref.LIGHT });
+        // [count=0]["de"][prefs]
+        expect(Array.from(withEnv.slice(base.length))).toEqual([0, 2, 0x64, 0x65, ClientPref.LIGHT]);
+    });
+});
+
+describe('ClientEnvReporter', () => {
+    let sent;
+    let reporter;
+    let client;
+
+    beforeEach(() => {
+        jest.useFakeTimers();
+        sent = [];
+        client = {
+            codec: new BinaryCodec(),
+            connected: true,
+            options: {},
+            wsManager: { send: (frame) => sent.push(frame) },
+        };
+        reporter = new ClientEnvReporter(client, { resizeDebounce: 100 });
+    });
+
+    afterEach(() => {
+        reporter.detach();
+        jest.useRealTimers();
+        delete window.matchMedia;
+    });
+
+    test('reports changes only', () => {
+        reporter.markReported(readClientEnv());
+        reporter.report();
+        expect(sent).toHaveLength(0);
+
+        reporter.markReported({ ...readClientEnv(), viewportW: 1 });
+        reporter.report();
+        expect(sent).toHaveLength(1);
+
+        const frame = sent[0];
+        expect(frame[0]).toBe(0x03); // CONTROL
+        expect(frame[4]).toBe(0x41); // ClientEnv
+        const w = (frame[5] << 8) | frame[6];
+        expect(w).toBe(window.innerWidth);
+    });
+
+    test('debounces resizes and follows media query changes', () => {
+        const lists = mockMatchMedia([]);
+        reporter.attach();
+        reporter.markReported({ ...readClientEnv(), viewportW: 1 });
+
+        window.dispatchEvent(new Event('resize'));
+        window.dispatchEvent(new Event('resize'));
+        expect(sent).toHaveLength(0);
+        jest.advanceTimersByTime(100);
+        expect(sent).toHaveLength(1);
+
+        lists['(prefers-color-scheme: dark)'].matches = true;
+        lists['(prefers-color-scheme: dark)'].listeners[0]();
+        expect(sent).toHaveLength(2);
+        const frame = sent[1];
+        expect(frame[frame.length - 1]).toBe(ClientPref.DARK);
+    });
+
+    test('does nothing while disconnected', () => {
+        client.connected = false;
+        reporter.report();
+        expect(sent).toHaveLength(0);
+    });
+});
@@ -146,9 +146,9 @@ describe('BinaryCodec handshake', () => {
             });
 
             // Payload starts with version bytes [major, minor]
-            // Protocol version 2.0 = [0x02, 0x00]
+            // Protocol version 2.2 = [0x02, 0x02]
             expect(frame[4]).toBe(0x02); // Major version
-            expect(frame[5]).toBe(0x00); // Minor version
+            expect(frame[5]).toBe(0x02); // Minor version
         });
 
         test('includes CSRF after version bytes', () => {
@@ -272,16 +272,16 @@ describe('BinaryCodec handshake', () => {
     });
 
     describe('encodeClientHello (raw payload)', () => {
-        test('starts with protocol version 2.0', () => {
+        test('starts with protocol version 2.2', () => {
             const payload = codec.encodeClientHello({
                 csrf: '',
                 sessionId: '',
                 viewportW: 800,
                 viewportH: 600,
             });
-            // Protocol version 2.0 = [0x02, 0x00]
+            // Protocol version 2.2 = [0x02, 0x02]
             expect(payload[0]).toBe(0x02); // Major
-            expect(payload[1]).toBe(0x00); // Minor
+            expect(payload[1]).toBe(0x02); // Minor
         });
 
         test('includes CSRF token after version', () => {
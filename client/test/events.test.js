@@ -7,6 +7,7 @@
 
 import { jest, describe, test, expect, beforeEach } from '@jest/globals';
 import { EventCapture } from '../src/events.js';
+import { EventType } from '../src/codec.js';
 
 /**
  * Create a mock click event with configurable properties
@@ -385,6 +386,49 @@ describe('EventCapture', () => {
                 expect.objectContaining({ username: 'testuser' })
             );
         });
+
+        test('collects repeated fields as arrays and file inputs by name', () => {
+            const form = document.createElement('form');
+            form.dataset.hid = 'f1';
+            form.dataset.ve = 'submit';
+            form.innerHTML = `
+                <select name="colors" multiple>
+                    <option value="red" selected>Red</option>
+                    <option value="green">Green</option>
+                    <option value="blue" selected>Blue</option>
+                </select>
+                <input type="checkbox" name="tags[]" value="a" checked>
+                <input type="checkbox" name="tags[]" value="b">
+                <input type="checkbox" name="tags[]" value="c" checked>
+                <input name="title" value="Hello">
+            `;
+            const fileInput = document.createElement('input');
+            fileInput.type = 'file';
+            fileInput.name = 'avatar';
+            const file = new File(['x'], 'me.png', { type: 'image/png' });
+            Object.defineProperty(fileInput, 'files', { value: [file] });
+            form.appendChild(fileInput);
+            document.body.appendChild(form);
+
+            const OriginalFormData = global.FormData;
+            global.FormData = class extends OriginalFormData {
+                constructor(f) {
+                    super(f);
+                    this.set('avatar', file);
+                }
+            };
+            try {
+                eventCapture._handleSubmit(createSubmitEvent({ target: form }));
+            } finally {
+                global.FormData = OriginalFormData;
+            }
+
+            const fields = client.sendEvent.mock.calls[0][2];
+            expect(fields.colors).toEqual(['red', 'blue']);
+            expect(fields['tags[]']).toEqual(['a', 'c']);
+            expect(fields.title).toBe('Hello');
+            expect(fields.avatar).toBe('me.png');
+        });
     });
 
     describe('_findHidElementWithEvent', () => {
@@ -433,6 +477,143 @@ describe('EventCapture', () => {
         });
     });
 
+    describe('pointer, media, clipboard and drag delegation', () => {
+        function domEvent(type, target, props = {}) {
+            return {
+                type,
+                target,
+                preventDefault: jest.fn(),
+                stopPropagation: jest.fn(),
+                ...props,
+            };
+        }
+
+        test('pointermove is throttled by default', () => {
+            jest.useFakeTimers();
+            try {
+                const el = createHidElement('h1', 'pointermove');
+                document.body.appendChild(el);
+
+                eventCapture._handlePointer(domEvent('pointermove', el, { clientX: 1, pointerId: 4 }));
+                eventCapture._handlePointer(domEvent('pointermove', el, { clientX: 2 }));
+                expect(client.sendEvent).toHaveBeenCalledTimes(1);
+                expect(client.sendEvent.mock.calls[0][0]).toBe(EventType.POINTERMOVE);
+                expect(client.sendEvent.mock.calls[0][2].pointerId).toBe(4);
+
+                jest.advanceTimersByTime(50);
+                eventCapture._handlePointer(domEvent('pointermove', el, { clientX: 3 }));
+                expect(client.sendEvent).toHaveBeenCalledTimes(2);
+            } finally {
+                jest.useRealTimers();
+            }
+        });
+
+        test('data-throttle overrides the default', () => {
+            const el = createHidElement('h1', 'pointermove');
+            el.dataset.throttlePointermove = '500';
+            expect(eventCapture._getThrottle(el, 'pointermove')).toBe(500);
+            expect(eventCapture._getThrottle(el, 'pointerdown')).toBe(0);
+        });
+
+        test('pointerenter only fires for its own target', () => {
+            const el = createHidElement('h1', 'pointerenter');
+            const child = document.createElement('span');
+            el.appendChild(child);
+            document.body.appendChild(el);
+
+            eventCapture._handlePointer(domEvent('pointerenter', child));
+            expect(client.sendEvent).not.toHaveBeenCalled();
+            eventCapture._handlePointer(domEvent('pointerenter', el));
+            expect(client.sendEvent).toHaveBeenCalledWith(EventType.POINTERENTER, 'h1', expect.anything());
+        });
+
+        test('contextmenu prevents the native menu', () => {
+            const el = createHidElement('h1', 'contextmenu');
+            document.body.appendChild(el);
+
+            const event = domEvent('contextmenu', el, { clientX: 7, button: 2 });
+            eventCapture._handleContextMenu(event);
+            expect(client.sendEvent).toHaveBeenCalledWith(EventType.CONTEXTMENU, 'h1', event);
+            expect(event.preventDefault).toHaveBeenCalled();
+        });
+
+        test('media events send the element state', () => {
+            const video = document.createElement('video');
+            video.dataset.hid = 'h1';
+            video.dataset.ve = 'pause';
+            document.body.appendChild(video);
+
+            eventCapture._handleMedia(domEvent('pause', video));
+            expect(client.sendEvent).toHaveBeenCalledWith(EventType.PAUSE, 'h1', video);
+        });
+
+        test('paste sends text and file metadata', () => {
+            const el = createHidElement('h1', 'paste');
+            document.body.appendChild(el);
+
+            eventCapture._handleClipboard(domEvent('paste', el, {
+                clipboardData: {
+                    types: ['text/plain', 'Files'],
+                    files: [{ name: 'a.png', type: 'image/png', size: 10 }],
+                    getData: (type) => (type === 'text/plain' ? 'hello' : ''),
+                },
+            }));
+            expect(client.sendEvent).toHaveBeenCalledWith(EventType.PASTE, 'h1', {
+                text: 'hello',
+                types: ['text/plain', 'Files'],
+                files: [{ name: 'a.png', type: 'image/png', size: 10 }],
+            });
+        });
+
+        test('drop targets accept dragover and receive the dropped data', () => {
+            const el = createHidElement('h1', 'drop');
+            document.body.appendChild(el);
+
+            const over = domEvent('dragover', el, { dataTransfer: { types: ['text/plain'] } });
+            eventCapture._handleDrag(over);
+            expect(over.preventDefault).toHaveBeenCalled();
+            expect(client.sendEvent).not.toHaveBeenCalled();
+
+            const drop = domEvent('drop', el, {
+                clientX: 1,
+                dataTransfer: {
+                    types: ['text/plain'],
+                    files: [],
+                    getData: () => 'dragged',
+                },
+            });
+            eventCapture._handleDrag(drop);
+            expect(drop.preventDefault).toHaveBeenCalled();
+            const [type, hid, payload] = client.sendEvent.mock.calls[0];
+            expect(type).toBe(EventType.DROP);
+            expect(hid).toBe('h1');
+            expect(payload.types).toEqual(['text/plain']);
+            expect(payload.data).toEqual({ 'text/plain': 'dragged' });
+        });
+
+        test('events added in 2.2 are not sent to older servers', () => {
+            client.protocolVersion = { major: 2, minor: 1 };
+            const el = createHidElement('h1', 'pointerdown,contextmenu,paste,play,dragover,drop');
+            document.body.appendChild(el);
+
+            eventCapture._handlePointer(domEvent('pointerdown', el));
+            eventCapture._handleContextMenu(domEvent('contextmenu', el));
+            eventCapture._handleClipboard(domEvent('paste', el, { clipboardData: null }));
+            eventCapture._handleMedia(domEvent('play', el));
+            eventCapture._handleDrag(domEvent('dragover', el, { dataTransfer: { types: [] } }));
+            expect(client.sendEvent).not.toHaveBeenCalled();
+
+            // Drop predates 2.2
+            eventCapture._handleDrag(domEvent('drop', el, { dataTransfer: { types: [], files: [] } }));
+            expect(client.sendEvent).toHaveBeenCalledTimes(1);
+            expect(client.sendEvent.mock.calls[0][0]).toBe(EventType.DROP);
+
+            client.protocolVersion = { major: 2, minor: 2 };
+            eventCapture._handlePointer(domEvent('pointerdown', el));
+            expect(client.sendEvent).toHaveBeenLastCalledWith(EventType.POINTERDOWN, 'h1', expect.anything());
+        });
+    });
+
     describe('_hasEvent', () => {
         test('returns true for single event match', () => {
             const el = createHidElement('h1', 'click');
@@ -0,0 +1,233 @@
+/**
+ * HTTP fallback transport tests
+ *
+ * SSE + POST stand in for a WebSocket when the upgrade is blocked, as served
+ * by pkg/server/http_fallback.go.
+ */
+
+import { describe, test, expect, beforeEach, afterEach, jest } from '@jest/globals';
+import { HTTPFallbackSocket, httpFallbackUrl } from '../src/fallback.js';
+import { WebSocketManager } from '../src/websocket.js';
+
+class MockEventSource {
+    static instances = [];
+
+    constructor(url) {
+        this.url = url;
+        this.listeners = {};
+        this.closed = false;
+        this.onmessage = null;
+        this.onerror = null;
+        MockEventSource.instances.push(this);
+    }
+
+    addEventListener(type, fn) {
+        this.listeners[type] = fn;
+    }
+
+    emit(type, data) {
+        if (type === 'message') {
+            this.onmessage({ data });
+        } else {
+            this.listeners[type]({ data });
+        }
+    }
+
+    close() {
+        this.closed = true;
+    }
+}
+
+function base64(bytes) {
+    return btoa(String.fromCharCode(...bytes));
+}
+
+const flush = () => new Promise((resolve) => setTimeout(resolve, 0));
+
+describe('HTTPFallbackSocket', () => {
+    let posts;
+
+    beforeEach(() => {
+        MockEventSource.instances = [];
+        posts = [];
+        global.EventSource = MockEventSource;
+        global.fetch = jest.fn((url, init) => {
+            posts.push({ url, body: Array.from(init.body) });
+            return Promise.resolve({ ok: true, status: 204 });
+        });
+    });
+
+    afterEach(() => {
+        delete global.EventSource;
+        delete global.fetch;
+    });
+
+    test('converts WebSocket URLs', () => {
+        expect(httpFallbackUrl('wss://example.com/_vango/live?path=%2Fa'))
+            .toBe('https://example.com/_vango/sse?path=%2Fa');
+        expect(httpFallbackUrl('ws://localhost:3000/_vango/live?path=%2F'))
+            .toBe('http://localhost:3000/_vango/sse?path=%2F');
+    });
+
+    test('opens on the conn event and decodes frames', () => {
+        const sock = new HTTPFallbackSocket('http://localhost/_vango/sse?path=%2F');
+        const opened = jest.fn();
+        const messages = [];
+        sock.onopen = opened;
+        sock.onmessage = (e) => messages.push(e.data);
+
+        expect(sock.readyState).toBe(HTTPFallbackSocket.CONNECTING);
+        const es = MockEventSource.instances[0];
+        es.emit('conn', 'abc');
+        expect(opened).toHaveBeenCalled();
+        expect(sock.readyState).toBe(HTTPFallbackSocket.OPEN);
+
+        es.emit('message', base64([0x03, 0x00, 0x00, 0x01, 0x02]));
+        expect(messages).toHaveLength(1);
+        expect(messages[0]).toBeInstanceOf(ArrayBuffer);
+        expect(Array.from(new Uint8Array(messages[0]))).toEqual([0x03, 0x00, 0x00, 0x01, 0x02]);
+    });
+
+    test('batches frames into ordered POSTs', async () => {
+        const sock = new HTTPFallbackSocket('http://localhost/_vango/sse?path=%2F');
+        MockEventSource.instances[0].emit('conn', 'abc');
+
+        sock.send(new Uint8Array([1, 2]));
+        sock.send(new Uint8Array([3]));
+        await flush();
+        expect(posts).toEqual([{ url: 'http://localhost/_vango/sse?conn=abc', body: [1, 2, 3] }]);
+
+        sock.send(new Uint8Array([4]));
+        await flush();
+        expect(posts[1].body).toEqual([4]);
+    });
+
+    test('a failed POST closes the socket uncleanly', async () => {
+        global.fetch = jest.fn(() => Promise.resolve({ ok: false, status: 410 }));
+        const sock = new HTTPFallbackSocket('http://localhost/_vango/sse');
+        const es = MockEventSource.instances[0];
+        const closes = [];
+        sock.onclose = (e) => closes.push(e);
+        es.emit('conn', 'abc');
+
+        sock.send(new Uint8Array([1]));
+        await flush();
+        expect(closes).toHaveLength(1);
+        expect(closes[0].wasClean).toBe(false);
+        expect(es.closed).toBe(true);
+    });
+
+    test('the close event is a clean close', () => {
+        const sock = new HTTPFallbackSocket('http://localhost/_vango/sse');
+        const closes = [];
+        sock.onclose = (e) => closes.push(e);
+        const es = MockEventSource.instances[0];
+        es.emit('conn', 'abc');
+        es.listeners.close({});
+        expect(closes[0]).toMatchObject({ code: 1000, wasClean: true });
+        expect(es.closed).toBe(true);
+    });
+
+    test('stream errors stop EventSource reconnecting', () => {
+        const sock = new HTTPFallbackSocket('http://localhost/_vango/sse');
+        const closes = [];
+        sock.onclose = (e) => closes.push(e);
+        const es = MockEventSource.instances[0];
+        es.onerror({});
+        expect(es.closed).toBe(true);
+        expect(closes[0]).toMatchObject({ code: 1006, wasClean: false });
+    });
+});
+
+describe('WebSocketManager fallback', () => {
+    let sockets;
+
+    beforeEach(() => {
+        MockEventSource.instances = [];
+        sockets = [];
+        global.EventSource = MockEventSource;
+        global.fetch = jest.fn(() => Promise.resolve({ ok: true, status: 204 }));
+        global.WebSocket = class {
+            constructor(url) {
+                this.url = url;
+                sockets.push(this);
+            }
+            close() {}
+        };
+        global.WebSocket.OPEN = 1;
+    });
+
+    afterEach(() => {
+        delete global.EventSource;
+        delete global.fetch;
+        delete global.WebSocket;
+    });
+
+    function newManager(options = {}) {
+        const client = {
+            options: { debug: false, ...options },
+            eventCapture: null,
+            _onDisconnected() {},
+            _onError() {},
+        };
+        return new WebSocketManager(client, { reconnect: true, ...options });
+    }
+
+    test('falls back when the upgrade fails', () => {
+        const mgr = newManager();
+        mgr.connect('ws://localhost/_vango/live?path=%2F');
+        expect(sockets).toHaveLength(1);
+
+        sockets[0].onclose({ code: 1006, wasClean: false });
+        expect(mgr.useHTTPFallback).toBe(true);
+        expect(mgr.ws).toBeInstanceOf(HTTPFallbackSocket);
+        expect(MockEventSource.instances[0].url).toBe('http://localhost/_vango/sse?path=%2F');
+    });
+
+    test('does not fall back after the WebSocket opened', () => {
+        jest.useFakeTimers();
+        try {
+            const mgr = newManager();
+            mgr._sendHandshake = () => {};
+            mgr.connect('ws://localhost/_vango/live?path=%2F');
+            sockets[0].onopen();
+            mgr._stopHeartbeat();
+            sockets[0].onclose({ code: 1006, wasClean: false });
+            expect(mgr.useHTTPFallback).toBe(false);
+            expect(MockEventSource.instances).toHaveLength(0);
+        } finally {
+            jest.useRealTimers();
+        }
+    });
+
+    test('returns to WebSocket when the fallback does not open', () => {
+        jest.useFakeTimers();
+        try {
+            const mgr = newManager();
+            mgr.connect('ws://localhost/_vango/live?path=%2F');
+            sockets[0].onclose({ code: 1006, wasClean: false });
+            expect(mgr.ws).toBeInstanceOf(HTTPFallbackSocket);
+
+            // e.g. the server has ServerConfig.HTTPFallback off
+            mgr.ws.onclose({ code: 1006, wasClean: false });
+            expect(mgr.useHTTPFallback).toBe(false);
+            jest.runOnlyPendingTimers();
+            expect(sockets).toHaveLength(2);
+        } finally {
+            jest.useRealTimers();
+        }
+    });
+
+    test('httpFallback: false disables the fallback', () => {
+        jest.useFakeTimers();
+        try {
+            const mgr = newManager({ httpFallback: false });
+            mgr.connect('ws://localhost/_vango/live?path=%2F');
+            sockets[0].onclose({ code: 1006, wasClean: false });
+            expect(mgr.useHTTPFallback).toBe(false);
+            expect(MockEventSource.instances).toHaveLength(0);
+        } finally {
+            jest.useRealTimers();
+        }
+    });
+});
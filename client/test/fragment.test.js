@@ -0,0 +1,100 @@
+/**
+ * Frame fragmentation tests
+ *
+ * Messages larger than one frame are split into fragments carrying
+ * FRAGMENT, the last also FINAL, as written by pkg/protocol/fragment.go.
+ */
+
+import { describe, test, expect } from '@jest/globals';
+import { BinaryCodec, FrameFlags, FrameReassembler } from '../src/codec.js';
+
+const MAX = 0xFFFF;
+const PATCHES = 0x02;
+const CONTROL = 0x03;
+
+function pattern(n) {
+    const out = new Uint8Array(n);
+    for (let i = 0; i < n; i++) {
+        out[i] = (i * 7) & 0xFF;
+    }
+    return out;
+}
+
+// Feed an encoded frame to a reassembler the way _handleBinaryMessage does
+function feed(reassembler, frame) {
+    const length = (frame[2] << 8) | frame[3];
+    expect(frame.length).toBe(4 + length);
+    return reassembler.add(frame[0], frame[1], frame.slice(4));
+}
+
+describe('Frame fragmentation', () => {
+    const codec = new BinaryCodec();
+
+    test('small payloads are a single unflagged frame', () => {
+        const frames = codec.encodeFrames(PATCHES, pattern(10));
+        expect(frames).toHaveLength(1);
+        expect(frames[0][1]).toBe(0);
+
+        const exact = codec.encodeFrames(PATCHES, pattern(MAX));
+        expect(exact).toHaveLength(1);
+    });
+
+    test('large payloads split and reassemble', () => {
+        const payload = pattern(2 * MAX + 100);
+        const frames = codec.encodeFrames(PATCHES, payload, FrameFlags.COMPRESSED);
+        expect(frames).toHaveLength(3);
+        expect(frames.map((f) => f[1])).toEqual([
+            FrameFlags.COMPRESSED | FrameFlags.FRAGMENT,
+            FrameFlags.COMPRESSED | FrameFlags.FRAGMENT,
+            FrameFlags.COMPRESSED | FrameFlags.FRAGMENT | FrameFlags.FINAL,
+        ]);
+
+        const reassembler = new FrameReassembler();
+        expect(feed(reassembler, frames[0])).toBeNull();
+        expect(feed(reassembler, frames[1])).toBeNull();
+        const message = feed(reassembler, frames[2]);
+        expect(message.flags).toBe(FrameFlags.COMPRESSED);
+        expect(Array.from(message.payload)).toEqual(Array.from(payload));
+        expect(reassembler.buffered).toBe(0);
+    });
+
+    test('control frames pass through between fragments', () => {
+        const payload = pattern(2 * MAX);
+        const [first, last] = codec.encodeFrames(PATCHES, payload);
+        const ping = codec.encodeFrames(CONTROL, new Uint8Array([0x01, 42]))[0];
+
+        const reassembler = new FrameReassembler();
+        expect(feed(reassembler, first)).toBeNull();
+        const control = feed(reassembler, ping);
+        expect(Array.from(control.payload)).toEqual([0x01, 42]);
+        expect(reassembler.buffered).toBe(MAX);
+
+        const message = feed(reassembler, last);
+        expect(message.payload.length).toBe(payload.length);
+    });
+
+    test('rejects messages over the cap', () => {
+        const frames = codec.encodeFrames(PATCHES, pattern(2 * MAX));
+        const reassembler = new FrameReassembler(MAX + 1);
+        feed(reassembler, frames[0]);
+        expect(() => feed(reassembler, frames[1])).toThrow(/too large/);
+        expect(reassembler.buffered).toBe(0);
+    });
+
+    test('rejects an unfragmented frame of the same type mid-message', () => {
+        const frames = codec.encodeFrames(PATCHES, pattern(2 * MAX));
+        const reassembler = new FrameReassembler();
+        feed(reassembler, frames[0]);
+        expect(() => reassembler.add(PATCHES, 0, pattern(4))).toThrow(/inside a fragmented message/);
+    });
+
+    test('reset drops partial messages', () => {
+        const frames = codec.encodeFrames(PATCHES, pattern(2 * MAX));
+        const reassembler = new FrameReassembler();
+        feed(reassembler, frames[0]);
+        reassembler.reset();
+        expect(reassembler.buffered).toBe(0);
+        // The final fragment alone now forms a message of its own length
+        expect(feed(reassembler, frames[1]).payload.length).toBe(MAX);
+    });
+});
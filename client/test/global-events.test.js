@@ -0,0 +1,148 @@
+/**
+ * Window and document event subscription tests
+ *
+ * Subscriptions arrive as GLOBAL_LISTEN control messages sent by
+ * pkg/server/global_events.go.
+ */
+
+import { describe, test, expect, beforeEach, afterEach, jest } from '@jest/globals';
+import { BinaryCodec, EventType } from '../src/codec.js';
+import { GlobalEvents, GlobalTarget, ListenFlags } from '../src/global-events.js';
+
+function sub(id, event, options = {}) {
+    return {
+        id,
+        target: GlobalTarget.WINDOW,
+        event,
+        flags: 0,
+        debounce: 0,
+        throttle: 0,
+        keys: [],
+        keyMods: 0,
+        ...options,
+    };
+}
+
+describe('GlobalListen codec', () => {
+    test('decodes every field', () => {
+        const codec = new BinaryCodec();
+        // [0x43]["g3"][doc]["keydown"][pd|once][200][0][1 key "k"][meta]
+        const payload = new Uint8Array([
+            0x43, 2, 0x67, 0x33, 0x02, 7, ...new TextEncoder().encode('keydown'),
+            0x05, 0xC8, 0x01, 0, 1, 1, 0x6B, 0x08,
+        ]);
+        expect(codec.decodeGlobalListen(payload, 1)).toEqual({
+            id: 'g3',
+            target: GlobalTarget.DOCUMENT,
+            event: 'keydown',
+            flags: ListenFlags.PREVENT_DEFAULT | ListenFlags.ONCE,
+            debounce: 200,
+            throttle: 0,
+            keys: ['k'],
+            keyMods: 0x08,
+        });
+    });
+});
+
+describe('GlobalEvents', () => {
+    let client;
+    let events;
+
+    beforeEach(() => {
+        client = { sendEvent: jest.fn() };
+        events = new GlobalEvents(client);
+    });
+
+    afterEach(() => {
+        events.reset();
+        jest.useRealTimers();
+    });
+
+    test('subscriptions to one event share a DOM listener', () => {
+        const add = jest.spyOn(window, 'addEventListener');
+        const remove = jest.spyOn(window, 'removeEventListener');
+
+        events.listen(sub('g1', 'online'));
+        events.listen(sub('g2', 'online'));
+        expect(add.mock.calls.filter(([name]) => name === 'online')).toHaveLength(1);
+
+        window.dispatchEvent(new Event('online'));
+        expect(client.sendEvent.mock.calls).toEqual([
+            [EventType.ONLINE, 'g1', null],
+            [EventType.ONLINE, 'g2', null],
+        ]);
+
+        events.unlisten('g1');
+        expect(remove.mock.calls.filter(([name]) => name === 'online')).toHaveLength(0);
+        events.unlisten('g2');
+        expect(remove.mock.calls.filter(([name]) => name === 'online')).toHaveLength(1);
+
+        add.mockRestore();
+        remove.mockRestore();
+    });
+
+    test('key filters and preventDefault apply before sending', () => {
+        events.listen(sub('g1', 'keydown', {
+            target: GlobalTarget.DOCUMENT,
+            flags: ListenFlags.PREVENT_DEFAULT,
+            keys: ['k'],
+            keyMods: 0x08,
+        }));
+
+        const plain = new KeyboardEvent('keydown', { key: 'k', cancelable: true });
+        document.dispatchEvent(plain);
+        expect(plain.defaultPrevented).toBe(false);
+        expect(client.sendEvent).not.toHaveBeenCalled();
+
+        const shortcut = new KeyboardEvent('keydown', { key: 'k', metaKey: true, cancelable: true });
+        document.dispatchEvent(shortcut);
+        expect(shortcut.defaultPrevented).toBe(true);
+        expect(client.sendEvent).toHaveBeenCalledWith(EventType.KEYDOWN, 'g1', expect.objectContaining({ key: 'k', metaKey: true }));
+    });
+
+    test('debounced resize sends the last size once', () => {
+        jest.useFakeTimers();
+        events.listen(sub('g1', 'resize', { debounce: 200 }));
+
+        window.innerWidth = 500;
+        window.dispatchEvent(new Event('resize'));
+        window.innerWidth = 640;
+        window.dispatchEvent(new Event('resize'));
+        expect(client.sendEvent).not.toHaveBeenCalled();
+
+        jest.advanceTimersByTime(200);
+        expect(client.sendEvent).toHaveBeenCalledTimes(1);
+        expect(client.sendEvent.mock.calls[0][2].width).toBe(640);
+    });
+
+    test('once subscriptions end after the first event', () => {
+        events.listen(sub('g1', 'visibilitychange', {
+            target: GlobalTarget.DOCUMENT,
+            flags: ListenFlags.ONCE,
+        }));
+
+        document.dispatchEvent(new Event('visibilitychange'));
+        document.dispatchEvent(new Event('visibilitychange'));
+        expect(client.sendEvent).toHaveBeenCalledTimes(1);
+        expect(client.sendEvent).toHaveBeenCalledWith(EventType.VISIBILITYCHANGE, 'g1', { value: 'visible' });
+        expect(events.subs.size).toBe(0);
+    });
+
+    test('reset ends every subscription', () => {
+        events.listen(sub('g1', 'offline'));
+        events.listen(sub('g2', 'resize'));
+        events.reset();
+
+        window.dispatchEvent(new Event('offline'));
+        expect(client.sendEvent).not.toHaveBeenCalled();
+        expect(events.listeners.size).toBe(0);
+    });
+
+    test('visibilitychange payload encodes as a string', () => {
+        const codec = new BinaryCodec();
+        const buf = codec.encodeEvent(1, EventType.VISIBILITYCHANGE, 'g1', { value: 'hidden' });
+        // [seq=1][type][hid "g1"]["hidden"]
+        expect(Array.from(buf.slice(0, 5))).toEqual([1, 0xB0, 2, 0x67, 0x31]);
+        expect(codec.decodeString(buf, 5).value).toBe('hidden');
+    });
+});
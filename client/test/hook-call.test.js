@@ -0,0 +1,108 @@
+/**
+ * Hook call tests
+ *
+ * Calls arrive as HOOK_CALL control messages sent by ctx.CallHook
+ * (pkg/server/hook_call.go) and are answered with HOOK_RESULT.
+ */
+
+import { describe, test, expect, beforeEach, jest } from '@jest/globals';
+import { BinaryCodec } from '../src/codec.js';
+import { HookManager } from '../src/hooks/manager.js';
+
+class EditorHook {
+    mounted(el, config, pushEvent) {
+        this.text = config.text || '';
+    }
+
+    getText(el, args) {
+        return args.upper ? this.text.toUpperCase() : this.text;
+    }
+
+    async save() {
+        throw new Error('read only');
+    }
+}
+
+describe('HookCall codec', () => {
+    test('decodes a call and encodes its result', () => {
+        const codec = new BinaryCodec();
+        // [0x45][id=300]["h4"]["getText"][1 arg: "upper" -> bool true]
+        const payload = new Uint8Array([
+            0x45, 0xAC, 0x02, 2, 0x68, 0x34, 7, ...new TextEncoder().encode('getText'),
+            1, 5, ...new TextEncoder().encode('upper'), 0x01, 0x01,
+        ]);
+        expect(codec.decodeHookCall(payload, 1)).toEqual({
+            id: 300,
+            hid: 'h4',
+            method: 'getText',
+            args: { upper: true },
+        });
+
+        // [0x46][id=300][error ""][string "hi"]
+        expect(Array.from(codec.encodeHookResult(300, 'hi'))).toEqual([
+            0x46, 0xAC, 0x02, 0, 0x04, 2, 0x68, 0x69,
+        ]);
+    });
+});
+
+describe('HookManager.call', () => {
+    let hooks;
+
+    beforeEach(() => {
+        document.body.innerHTML = `
+            <div data-hid="h1" data-hook="Editor" data-hook-config='{"text":"hello"}'></div>
+            <input data-hid="h2" value="hello world">
+            <p data-hid="h3">one <b>two</b> three</p>
+        `;
+        const client = {
+            options: { debug: false },
+            sendHookEvent: jest.fn(),
+            getNode: (hid) => document.querySelector(`[data-hid="${hid}"]`),
+        };
+        hooks = new HookManager(client);
+        hooks.register('Editor', EditorHook);
+        hooks.initializeFromDOM();
+    });
+
+    test('calls hook methods with the element and args', async () => {
+        await expect(hooks.call('h1', 'getText', { upper: true })).resolves.toBe('HELLO');
+        await expect(hooks.call('h1', 'save')).rejects.toThrow('read only');
+        await expect(hooks.call('h1', 'missing')).rejects.toThrow('hook Editor has no method missing');
+        await expect(hooks.call('h1', 'mounted')).rejects.toThrow('has no method mounted');
+        await expect(hooks.call('h9', 'getText')).rejects.toThrow('no hook on element h9');
+    });
+
+    test('built-in queries work without a hook', async () => {
+        const rect = await hooks.call('h3', 'vango:rect');
+        expect(Object.keys(rect).sort()).toEqual(['bottom', 'height', 'left', 'right', 'top', 'width', 'x', 'y']);
+
+        const scroll = await hooks.call('h3', 'vango:scroll');
+        expect(scroll).toEqual({ top: 0, left: 0, width: 0, height: 0 });
+
+        await expect(hooks.call('h9', 'vango:rect')).rejects.toThrow('no element with HID h9');
+    });
+
+    test('selection reads inputs and the page selection', async () => {
+        const input = document.querySelector('input');
+        input.setSelectionRange(6, 11);
+        await expect(hooks.call('h2', 'vango:selection')).resolves.toEqual({
+            text: 'world', start: 6, end: 11, direction: 'none',
+        });
+
+        // Select from inside "two" to the middle of "three"
+        const p = document.querySelector('p');
+        const range = document.createRange();
+        range.setStart(p.querySelector('b').firstChild, 1);
+        range.setEnd(p.lastChild, 3);
+        window.getSelection().removeAllRanges();
+        window.getSelection().addRange(range);
+
+        await expect(hooks.call('h3', 'vango:selection')).resolves.toEqual({
+            text: 'wo th', start: 5, end: 10, direction: 'forward',
+        });
+        await expect(hooks.call('h2', 'vango:selection')).resolves.toMatchObject({ text: 'world' });
+        await expect(hooks.call('h1', 'vango:selection')).resolves.toEqual({
+            text: '', start: 0, end: 0, direction: 'none',
+        });
+    });
+});
@@ -0,0 +1,133 @@
+/**
+ * Frame compression tests
+ *
+ * Fixtures are raw DEFLATE streams sync-flushed after every frame and
+ * prefixed with the varint raw length, the format of pkg/protocol/compress.go.
+ */
+
+import { describe, test, expect } from '@jest/globals';
+import zlib from 'zlib';
+import { BinaryCodec, Codec, SupportedCodecs } from '../src/codec.js';
+import { Inflater } from '../src/inflate.js';
+
+const b64 = (s) => new Uint8Array(Buffer.from(s, 'base64'));
+const text = (bytes) => new TextDecoder().decode(bytes);
+
+function rows() {
+    let out = '';
+    for (let i = 0; i < 200; i++) {
+        out += `<tr><td>row ${i}</td></tr>`;
+    }
+    return out;
+}
+
+function noise() {
+    const out = new Uint8Array(300);
+    let x = 1;
+    for (let i = 0; i < out.length; i++) {
+        x = (Math.imul(x, 1103515245) + 12345) >>> 0;
+        out[i] = (x >>> 16) & 0xFF;
+    }
+    return out;
+}
+
+// One stream covering fixed, dynamic and stored blocks; the last frame is
+// only back-references into the first.
+const zlibStream = [
+    'EcpIzcnJV8hAkAAAAAD//w==',
+    'miZ02EtKA0EARdGtuAPr1b8guBrnQhDcvjrSQZ1JCG926KT7Vj8+n2+Pz/e358fXS3m8/nz7+fid/ubc53qf233u93nc53mf133e9/mAIyacATSQBtTAGmADbcANvBXequsKb4W3wlvhrfBWeCu8Fd4Gb4O36YcMb4O3wdvgbfA2eBu8Hd4Ob4e3658Lb4e3w9vh7fB2eAe8A94B74B36FYF74B3wDvgHfBOeCe8E94J74R36t4M74R3wjvhXfAueBe8C94F74J36WEE74J3wbvh3fBueDe8G94N74Z36+kL74b3wHvgPfAeeA+8B94D74H3KDfYGwqOouIoSo6i5iiKjqLqKMqOou4oCo8iuVNLcsYWa4u5xd5icLG4mFxqrii6UlmZkqu7ovCKyitKr6i9oviK6ivKr6i/0hjYkivBogaLIiyqsCjDog6LQiwqsSjF0nm2kFw1FuVY1GNRkEVFFiVZ1GRRlEVVlsFjleQKs6jMojSL2iyKs6jOojyL+iwKtEyeKCVXo0WRFlValGlRp0WhFpValGpRq2XxMC25ci3qtSjYomKLki1qtijaomqLsi2b7xEkV7lF6Ra1WxRvUb1F+Rb1WxRwUcHl8BXKP/k3AAAA//8=',
+    'mibs2IEAAAAAgCB/60EujMyYGTNjZsyMmTEzZsbMmBkzY2bMjJkxM2bGzJgZe85YAAAA//8=',
+    'rAIALAHT/sZ+gWtL++L7VPa933wc4YcBvzHeVnIPR2dmh1mqiDxZ6lYTe9KFodg8VFUvN65lW9oCeZjM4xp2jl/ZmY8fPzbuQ3hNDfq+ptrkho7cKW1O/1bhcCD7j7FYBZDFCdxTzao7SJlS01KdBp/qtcIGE5hJsgEerDKIMZxSRpVxNo9X9jkdFvqIdPWYfBdcQbttcY4PcFnHARsvMz2RwB2lDQ2rM41+Xo8+5mh0pjqxw5MRqGTH28rgYOHzvwkAZ6LjJaAhMYfVYsWoT34uCWuUn7BtqZ5aC0ZwgLbPRwympSrYrPug67d5JHIjkkiAxaanhbfXjJDkq2NEUmbjnDMl+V6qunNgXUtxfr6pjFcZccPKXuUqM6yIUWahe3VnZJpp729WQqAdUcUC97uSRQAAAP//',
+    'EcpAX3AHAAAA//8=',
+];
+
+// The same rows twice, as written by protocol.Compressor.
+const goStream = [
+    'miZ01juKHEkARdGtzA46b/4iAopaTfsDTYG2L5AjGXnc5x2ec1+fn/fr8/3++f/Xf9vr6/P9fn39mf7OPc/783w8z+fzfD3P9/M8nuf5PK/nOTHhDNAgDdRgDdigDdzg3eHd9Su8O7w7vDu8O7w7vDu8O7wHvAe8B7wHvAe8B7wHvAe8B7wHvCe8J7wnvCe8J7wnvCe8J7wnvCe8F7wXvBe8F7wXvBe8F7wXvBe8F7w3vDe8N7w3vDe8N7w3vDe8N7w3vAPeAe+Ad8A74B3wDngHvAPeAe+Ed8I74Z3wTngnvBPeCe+Ed8K74F3wLngXvAveBe+Cd8G74F3wtgHcBnEbyG0wtwHdBnUb2G1wtwHeJrlTS3LGFmuLucXeYnCxuJhcaq4UXam6Unal7krhlcorpVdqrxRfqb5SfqX+SgGWCiwlWGqwFGGpwlKGpQ5LIZZKLKVYarEUY6nGUo6lHktBloosJVlqshRlqcpSlqUuS2GWyiylWWqzFGepzlKepT5LgZYKLSVaarQUaanSUqalTkuhlkotpVpqtRRrqdZSrqVeS8GWii0lW2q2FG2p2lK2pW5L4ZbKLaVbarcUb6neUr6lfksBlwouJVz/NtxvAAAA//8=',
+    'mibs2KERAAAIxLD9t8YiiMDXgvqry35o933GZizGXqzFVizFTvXVTNVVXLVVWpVVWHVl1mQsGUvGkrFkLBlLxpKxZCwZS8aSsWQsGUvGkrFk7CNjAwAA//8=',
+];
+
+describe('Inflater', () => {
+    const codec = new BinaryCodec();
+
+    test('decodes a stream of fixed, dynamic and stored blocks', () => {
+        const inflater = codec.createDecompressor(Codec.DEFLATE);
+        const frames = zlibStream.map((f) => codec.decompressPayload(b64(f), inflater));
+
+        expect(text(frames[0])).toBe('hello hello hello');
+        expect(text(frames[1])).toBe(rows());
+        expect(text(frames[2])).toBe(rows());
+        expect(Array.from(frames[3])).toEqual(Array.from(noise()));
+        expect(text(frames[4])).toBe('hello hello hello');
+    });
+
+    test('decodes frames written by the Go compressor', () => {
+        const inflater = new Inflater();
+        for (const f of goStream) {
+            expect(text(codec.decompressPayload(b64(f), inflater))).toBe(rows());
+        }
+    });
+
+    test('back-references need the earlier frames', () => {
+        const inflater = new Inflater();
+        expect(() => codec.decompressPayload(b64(goStream[1]), inflater)).toThrow(/distance too far back/);
+    });
+
+    test('rejects a length prefix that disagrees with the data', () => {
+        const frame = b64(zlibStream[0]);
+        frame[0] = 16; // real length is 17
+        expect(() => codec.decompressPayload(frame, new Inflater())).toThrow(/exceeds declared length/);
+    });
+
+    test('rejects truncated data', () => {
+        const frame = b64(zlibStream[1]).subarray(0, 40);
+        expect(() => codec.decompressPayload(frame, new Inflater())).toThrow(/unexpected end of input/);
+    });
+
+    test('decodes messages larger than one frame', () => {
+        // Messages are compressed whole and only split into frames after
+        // compression, so the raw length may exceed a frame payload.
+        const raw = new TextEncoder().encode(rows().repeat(30));
+        const deflated = zlib.deflateRawSync(raw, { finishFlush: zlib.constants.Z_SYNC_FLUSH });
+        const payload = new Uint8Array([...codec.encodeUvarint(raw.length), ...deflated]);
+        expect(raw.length).toBeGreaterThan(0xFFFF);
+
+        expect(text(codec.decompressPayload(payload, new Inflater()))).toBe(rows().repeat(30));
+        expect(() => codec.decompressPayload(payload, new Inflater(), 0xFFFF)).toThrow(/exceeds max size/);
+    });
+
+    test('rejects compressed frames without a negotiated codec', () => {
+        expect(() => codec.decompressPayload(b64(zlibStream[0]), null)).toThrow(/without a negotiated codec/);
+    });
+});
+
+describe('Codec negotiation', () => {
+    const codec = new BinaryCodec();
+
+    test('ClientHello lists supported codecs after the fixed fields', () => {
+        const hello = codec.encodeClientHello({ csrf: '', sessionId: '', codecs: SupportedCodecs });
+        const plain = codec.encodeClientHello({ csrf: '', sessionId: '' });
+
+        expect(hello.length).toBe(plain.length + 2);
+        expect(Array.from(hello.subarray(plain.length))).toEqual([1, Codec.DEFLATE]);
+    });
+
+    test('ServerHello exposes the negotiated codec', () => {
+        function helloFrame(flags) {
+            const payload = new Uint8Array(1 + 1 + 4 + 8 + 2);
+            payload[payload.length - 2] = flags >> 8;
+            payload[payload.length - 1] = flags & 0xFF;
+            const frame = new Uint8Array(4 + payload.length);
+            frame[3] = payload.length;
+            frame.set(payload, 4);
+            return frame;
+        }
+
+        expect(codec.decodeServerHello(helloFrame(0x0101)).codec).toBe(Codec.DEFLATE);
+        expect(codec.decodeServerHello(helloFrame(0x0004)).codec).toBe(Codec.NONE);
+        // The codec byte is ignored unless compression is flagged
+        expect(codec.decodeServerHello(helloFrame(0x0100)).codec).toBe(Codec.NONE);
+        expect(codec.createDecompressor(Codec.NONE)).toBeNull();
+        expect(() => codec.createDecompressor(0x7F)).toThrow(/unsupported codec/);
+    });
+});
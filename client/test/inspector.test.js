@@ -0,0 +1,91 @@
+/**
+ * Reactive graph inspector overlay tests
+ *
+ * The overlay renders the JSON served by /_vango/inspect
+ * (pkg/server/inspect.go) in DevMode.
+ */
+
+import { describe, test, expect, beforeEach, afterEach, jest } from '@jest/globals';
+import { GraphInspector, buildInspectorTree, describeNode } from '../src/inspector.js';
+
+const graph = {
+    nodes: [
+        { id: 1, kind: 'owner', runs: 0 },
+        { id: 2, kind: 'component', owner: 1, label: 'app.Counter', hid: 'h1', runs: 3 },
+        { id: 3, kind: 'signal', owner: 2, label: 'int', persistKey: 'count', value: '4', runs: 4, subscribers: 1 },
+        { id: 4, kind: 'signal', label: 'string', scope: 'global', value: 'online', runs: 0, subscribers: 5 },
+    ],
+    edges: [
+        { from: 3, to: 2 },
+        { from: 4, to: 2 },
+    ],
+};
+
+describe('buildInspectorTree', () => {
+    test('nests nodes under their owners and indexes edges', () => {
+        const tree = buildInspectorTree(graph);
+        expect(tree.roots.map(n => n.id)).toEqual([1, 4]);
+        expect(tree.children.get(1).map(n => n.id)).toEqual([2]);
+        expect(tree.children.get(2).map(n => n.id)).toEqual([3]);
+        expect(tree.deps.get(2)).toEqual([3, 4]);
+        expect(tree.subs.get(3)).toEqual([2]);
+    });
+
+    test('describeNode', () => {
+        expect(describeNode(graph.nodes[1])).toBe('component #2 app.Counter [h1] runs=3');
+        expect(describeNode(graph.nodes[2])).toBe('signal #3 int key=count = 4 runs=4');
+        expect(describeNode(graph.nodes[3])).toBe('signal #4 string (global) = online runs=0');
+    });
+});
+
+describe('GraphInspector', () => {
+    let client;
+    let inspector;
+    let target;
+
+    beforeEach(() => {
+        target = document.createElement('div');
+        document.body.appendChild(target);
+        client = {
+            wsManager: { sessionId: 'abc' },
+            nodeMap: new Map([['h1', target]]),
+        };
+        inspector = new GraphInspector(client);
+        inspector.attach();
+        global.fetch = jest.fn(async () => ({ ok: true, json: async () => graph }));
+    });
+
+    afterEach(() => {
+        inspector.detach();
+        target.remove();
+        delete global.fetch;
+    });
+
+    test('Alt+Shift+G fetches the session graph and renders it', async () => {
+        document.dispatchEvent(new KeyboardEvent('keydown', { key: 'G', altKey: true, shiftKey: true }));
+        await Promise.resolve();
+        await Promise.resolve();
+        await Promise.resolve();
+
+        expect(global.fetch.mock.calls[0][0]).toBe('/_vango/inspect?session=abc');
+        const panel = document.getElementById('vango-inspector');
+        expect(panel.textContent).toContain('4 nodes, 2 edges');
+        expect(panel.textContent).toContain('← #3 #4');
+        expect(panel.textContent).toContain('5 subscriber(s) total');
+
+        const line = panel.querySelector('[data-node-id="2"]');
+        line.dispatchEvent(new Event('mouseenter'));
+        expect(target.style.outline).toContain('solid');
+        line.dispatchEvent(new Event('mouseleave'));
+        expect(target.style.outline).toBe('');
+
+        inspector.toggle();
+        expect(document.getElementById('vango-inspector')).toBeNull();
+    });
+
+    test('reports when the endpoint is disabled', async () => {
+        global.fetch = jest.fn(async () => ({ ok: false, status: 404 }));
+        await inspector.open();
+        expect(document.getElementById('vango-inspector').textContent).toContain('requires DevMode');
+    });
+});
@@ -0,0 +1,104 @@
+import { IslandManager } from '../src/islands.js';
+import { jest } from '@jest/globals';
+
+describe('IslandManager', () => {
+    let client;
+    let islands;
+    let mounted;
+
+    const chart = {
+        mount(el, props, island) {
+            const received = [];
+            island.onMessage(data => received.push(data));
+            mounted = { el, props, island, received, destroyed: false };
+            return { destroy: () => { mounted.destroyed = true; }, update: (p) => { mounted.props = p; } };
+        },
+    };
+
+    beforeEach(() => {
+        mounted = null;
+        client = { options: { debug: false }, sendIslandMessage: jest.fn(() => true) };
+        islands = new IslandManager(client);
+        islands.register('/js/chart.js', chart);
+    });
+
+    test('mounts registered modules with parsed props', () => {
+        document.body.innerHTML = `<div data-island="c1" data-module="/js/chart.js" data-props='{"n":1}'></div>`;
+        islands.initializeFromDOM();
+
+        expect(mounted.island.id).toBe('c1');
+        expect(mounted.props).toEqual({ n: 1 });
+    });
+
+    test('delivers server messages to onMessage handlers', () => {
+        document.body.innerHTML = `<div data-island="c1" data-module="/js/chart.js"></div>`;
+        islands.initializeFromDOM();
+
+        islands.deliver('c1', { point: 3 });
+        expect(mounted.received).toEqual([{ point: 3 }]);
+    });
+
+    test('queues messages for islands inserted later in the same frame', () => {
+        document.body.innerHTML = '';
+        islands.deliver('c1', { ready: true });
+
+        document.body.innerHTML = `<div data-island="c1" data-module="/js/chart.js"></div>`;
+        islands.updateFromDOM();
+        expect(mounted.received).toEqual([{ ready: true }]);
+    });
+
+    test('drops queued messages for islands that are never rendered', () => {
+        islands.deliver('gone', { x: 1 });
+        islands.updateFromDOM();
+
+        document.body.innerHTML = `<div data-island="gone" data-module="/js/chart.js"></div>`;
+        islands.updateFromDOM();
+        expect(mounted.received).toEqual([]);
+    });
+
+    test('queues messages until an async module loads', async () => {
+        client.options.islandLoader = jest.fn(() => Promise.resolve(chart));
+        islands = new IslandManager(client);
+        document.body.innerHTML = `<div data-island="c2" data-module="/js/lazy.js"></div>`;
+        islands.initializeFromDOM();
+        islands.deliver('c2', { a: 1 });
+
+        await new Promise(resolve => setTimeout(resolve, 0));
+        expect(client.options.islandLoader).toHaveBeenCalledWith('/js/lazy.js');
+        expect(mounted.received).toEqual([{ a: 1 }]);
+    });
+
+    test('island.send forwards to the client', () => {
+        document.body.innerHTML = `<div data-island="c1" data-module="/js/chart.js"></div>`;
+        islands.initializeFromDOM();
+
+        expect(mounted.island.send({ clicked: 2 })).toBe(true);
+        expect(client.sendIslandMessage).toHaveBeenCalledWith('c1', { clicked: 2 });
+    });
+
+    test('island.send rejects messages that exceed the depth limit', () => {
+        document.body.innerHTML = `<div data-island="c1" data-module="/js/chart.js"></div>`;
+        islands.initializeFromDOM();
+
+        let deep = 1;
+        for (let i = 0; i < 70; i++) deep = [deep];
+        const spy = jest.spyOn(console, 'error').mockImplementation(() => {});
+        expect(mounted.island.send({ deep })).toBe(false);
+        expect(client.sendIslandMessage).not.toHaveBeenCalled();
+        spy.mockRestore();
+    });
+
+    test('updates props and destroys removed islands', () => {
+        document.body.innerHTML = `<div data-island="c1" data-module="/js/chart.js" data-props='{"n":1}'></div>`;
+        islands.initializeFromDOM();
+
+        document.querySelector('[data-island]').dataset.props = '{"n":2}';
+        islands.updateFromDOM();
+        expect(mounted.props).toEqual({ n: 2 });
+
+        document.body.innerHTML = '';
+        islands.updateFromDOM();
+        expect(mounted.destroyed).toBe(true);
+        expect(islands.instances.size).toBe(0);
+    });
+});
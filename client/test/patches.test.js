@@ -318,6 +318,66 @@ describe('PatchApplier', () => {
         });
     });
 
+    describe('head patch handling', () => {
+        beforeEach(() => {
+            document.head.innerHTML = '<meta name="description" content="old">';
+            document.title = 'Old';
+        });
+
+        test('head patches do not require a target node', () => {
+            expect(patchApplier._requiresTargetNode(PatchType.SET_TITLE)).toBe(false);
+            expect(patchApplier._requiresTargetNode(PatchType.SET_META)).toBe(false);
+            expect(patchApplier._requiresTargetNode(PatchType.SET_LINK)).toBe(false);
+        });
+
+        test('SET_TITLE updates document.title', () => {
+            patchApplier.applyPatch({ type: PatchType.SET_TITLE, hid: '', value: 'New Title' });
+
+            expect(document.title).toBe('New Title');
+        });
+
+        test('SET_META updates an existing tag', () => {
+            patchApplier.applyPatch({ type: PatchType.SET_META, hid: '', attr: 'name', key: 'description', value: 'new' });
+
+            const tags = document.head.querySelectorAll('meta[name="description"]');
+            expect(tags.length).toBe(1);
+            expect(tags[0].getAttribute('content')).toBe('new');
+        });
+
+        test('SET_META creates a missing tag', () => {
+            patchApplier.applyPatch({ type: PatchType.SET_META, hid: '', attr: 'property', key: 'og:title', value: 'OG' });
+
+            const tag = document.head.querySelector('meta[property="og:title"]');
+            expect(tag).not.toBeNull();
+            expect(tag.getAttribute('content')).toBe('OG');
+        });
+
+        test('SET_META with empty content removes the tag', () => {
+            patchApplier.applyPatch({ type: PatchType.SET_META, hid: '', attr: 'name', key: 'description', value: '' });
+
+            expect(document.head.querySelector('meta[name="description"]')).toBeNull();
+        });
+
+        test('SET_LINK sets and removes the canonical link', () => {
+            patchApplier.applyPatch({ type: PatchType.SET_LINK, hid: '', key: 'canonical', value: 'https://example.com/a' });
+            expect(document.head.querySelector('link[rel="canonical"]').getAttribute('href')).toBe('https://example.com/a');
+
+            patchApplier.applyPatch({ type: PatchType.SET_LINK, hid: '', key: 'canonical', value: '' });
+            expect(document.head.querySelector('link[rel="canonical"]')).toBeNull();
+        });
+    });
+
+    describe('island message handling', () => {
+        test('ISLAND_MESSAGE is routed to the island manager', () => {
+            client.islands = { deliver: jest.fn() };
+
+            expect(patchApplier._requiresTargetNode(PatchType.ISLAND_MESSAGE)).toBe(false);
+            patchApplier.applyPatch({ type: PatchType.ISLAND_MESSAGE, hid: 'chart', data: { a: 1 } });
+
+            expect(client.islands.deliver).toHaveBeenCalledWith('chart', { a: 1 });
+        });
+    });
+
     describe('self-heal triggers on missing elements', () => {
         // These tests verify that self-heal conditions are detected.
         // We can't fully test location.assign/reload in jsdom, but we can verify
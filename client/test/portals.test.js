@@ -0,0 +1,147 @@
+/**
+ * Portal tests
+ *
+ * Portals (vdom.Portal) mount their children into another container and
+ * leave an anchor where they were declared.
+ */
+
+import { jest, describe, test, expect, beforeEach } from '@jest/globals';
+import { PatchApplier } from '../src/patches.js';
+import { PatchType } from '../src/codec.js';
+import { mountPortals, portalAnchor } from '../src/portals.js';
+
+function createMockClient() {
+    const nodeMap = new Map();
+    return {
+        options: { debug: false },
+        nodeMap,
+        getNode: (hid) => nodeMap.get(hid),
+        registerNode: (hid, node) => nodeMap.set(hid, node),
+        unregisterNode: (hid) => nodeMap.delete(hid),
+        hooks: {
+            destroyForNode: jest.fn(),
+            initializeForNode: jest.fn(),
+        },
+    };
+}
+
+function element(tag, hid, children = []) {
+    return { type: 'element', tag, hid, attrs: {}, children };
+}
+
+function portal(target, hid, children = []) {
+    return { type: 'portal', target, hid, children };
+}
+
+describe('Portals', () => {
+    let client;
+    let patches;
+    let parent;
+    let modals;
+
+    beforeEach(() => {
+        document.body.innerHTML = '<div data-hid="h1"><p></p></div><div id="modals"></div>';
+        client = createMockClient();
+        patches = new PatchApplier(client);
+        parent = document.querySelector('[data-hid="h1"]');
+        modals = document.getElementById('modals');
+        client.registerNode('h1', parent);
+    });
+
+    function insertPortal(index = 0) {
+        patches.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h1',
+            index,
+            vnode: portal('#modals', 'h2', [element('button', 'h3')]),
+        }]);
+        return client.getNode('h2');
+    }
+
+    test('INSERT_NODE mounts the portal in its target', () => {
+        const wrapper = insertPortal();
+
+        expect(wrapper.parentNode).toBe(modals);
+        expect(wrapper.tagName).toBe('VANGO-PORTAL');
+        expect(wrapper.firstChild).toBe(client.getNode('h3'));
+        expect(parent.children[0]).toBe(portalAnchor(wrapper));
+        expect(parent.children[1].tagName).toBe('P');
+    });
+
+    test('patches inside the portal target its wrapper', () => {
+        const wrapper = insertPortal();
+
+        patches.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h2',
+            index: 1,
+            vnode: element('span', 'h4'),
+        }]);
+
+        expect(wrapper.children[1]).toBe(client.getNode('h4'));
+    });
+
+    test('REMOVE_NODE of the portal removes its anchor and contents', () => {
+        const wrapper = insertPortal();
+        const anchor = portalAnchor(wrapper);
+
+        patches.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+
+        expect(modals.children.length).toBe(0);
+        expect(anchor.isConnected).toBe(false);
+        expect(client.getNode('h2')).toBeUndefined();
+        expect(client.getNode('h3')).toBeUndefined();
+    });
+
+    test('removing the declaring element unmounts the portal', () => {
+        insertPortal();
+
+        patches.apply([{ type: PatchType.REMOVE_NODE, hid: 'h1' }]);
+
+        expect(modals.children.length).toBe(0);
+        expect(client.getNode('h3')).toBeUndefined();
+    });
+
+    test('REPLACE_NODE of the portal takes the anchor position', () => {
+        insertPortal();
+
+        patches.apply([{ type: PatchType.REPLACE_NODE, hid: 'h2', vnode: element('span', 'h5') }]);
+
+        expect(modals.children.length).toBe(0);
+        expect(parent.children[0]).toBe(client.getNode('h5'));
+    });
+
+    test('MOVE_NODE of the portal moves its anchor', () => {
+        const wrapper = insertPortal();
+
+        patches.apply([{ type: PatchType.MOVE_NODE, hid: 'h2', parentID: 'h1', index: 2 }]);
+
+        expect(parent.children[1]).toBe(portalAnchor(wrapper));
+        expect(wrapper.parentNode).toBe(modals);
+    });
+
+    test('mountPortals moves server-rendered portals', () => {
+        parent.innerHTML = '<vango-portal data-portal-target="#modals" data-hid="h2" style="display:contents">' +
+            '<button data-hid="h3"></button></vango-portal><p></p>';
+        const wrapper = parent.firstChild;
+
+        mountPortals(document);
+        mountPortals(document);
+
+        expect(wrapper.parentNode).toBe(modals);
+        expect(parent.children[0]).toBe(portalAnchor(wrapper));
+        expect(parent.children[0].dataset.portalAnchor).toBe('h2');
+        expect(parent.children.length).toBe(2);
+    });
+
+    test('unknown targets fall back to the body', () => {
+        patches.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h1',
+            index: 0,
+            vnode: portal('#missing', 'h2'),
+        }]);
+
+        expect(client.getNode('h2').parentNode).toBe(document.body);
+    });
+});
@@ -0,0 +1,143 @@
+/**
+ * Preference sync tests
+ *
+ * Stored preferences travel in the handshake and PREF_SYNC control messages
+ * read by pkg/server/prefs.go.
+ */
+
+import { describe, test, expect, beforeEach, afterEach } from '@jest/globals';
+import { BinaryCodec } from '../src/codec.js';
+import { PrefManager, MergeStrategy } from '../src/prefs.js';
+
+function mockClient() {
+    const codec = new BinaryCodec();
+    const sent = [];
+    return {
+        codec,
+        connected: true,
+        sent,
+        wsManager: { send: (frame) => sent.push(frame) },
+        // Decode the PrefSync messages sent so far.
+        syncs() {
+            return sent.map((frame) => {
+                const payload = frame.subarray(4);
+                expect(payload[0]).toBe(0x42);
+                return codec.decodePrefSync(payload, 1);
+            });
+        },
+    };
+}
+
+describe('PrefSync codec', () => {
+    const codec = new BinaryCodec();
+
+    test('round-trips values with millisecond timestamps', () => {
+        const values = [
+            { key: 'theme', value: '"dark"', updatedAt: 1760000000123 },
+            { key: 'size', value: '{"w":3}', updatedAt: 0 },
+        ];
+        const payload = codec.encodePrefSync(values);
+        expect(payload[0]).toBe(0x42);
+        expect(codec.decodePrefSync(payload, 1)).toEqual(values);
+    });
+
+    test('uint64 is big-endian above 2^32', () => {
+        expect(Array.from(codec.encodeUint64(0x100000002))).toEqual([0, 0, 0, 1, 0, 0, 0, 2]);
+        expect(codec.decodeUint64(codec.encodeUint64(1760000000123), 0)).toBe(1760000000123);
+    });
+
+    test('hello carries pref values after the env', () => {
+        const base = codec.encodeClientHello({ viewportW: 10, viewportH: 20 });
+        const hello = codec.encodeClientHello({
+            viewportW: 10,
+            viewportH: 20,
+            prefValues: [{ key: 'a', value: '1', updatedAt: 2 }],
+        });
+        // [codecs=0][locale=""][prefs=0][count=1]["a"]["1"][updatedAt:8]
+        expect(Array.from(hello.slice(base.length))).toEqual([
+            0, 0, 0, 1, 1, 0x61, 1, 0x31, 0, 0, 0, 0, 0, 0, 0, 2,
+        ]);
+    });
+});
+
+describe('PrefManager server sync', () => {
+    let client;
+    let manager;
+
+    beforeEach(() => {
+        localStorage.clear();
+        client = mockClient();
+        manager = new PrefManager(client);
+    });
+
+    afterEach(() => {
+        manager.destroy();
+        localStorage.clear();
+    });
+
+    test('storedValues lists every stored preference', () => {
+        manager.saveToStorage('theme', 'dark', new Date(1000));
+        manager.saveToStorage('layout', { cols: 2 }, new Date(2000));
+        localStorage.setItem('unrelated', 'x');
+
+        const values = manager.storedValues().sort((a, b) => a.key.localeCompare(b.key));
+        expect(values).toEqual([
+            { key: 'layout', value: '{"cols":2}', updatedAt: 2000 },
+            { key: 'theme', value: '"dark"', updatedAt: 1000 },
+        ]);
+    });
+
+    test('set sends a PrefSync message', () => {
+        const theme = manager.register('theme', 'light');
+        theme.set('dark');
+
+        const [values] = client.syncs();
+        expect(values).toHaveLength(1);
+        expect(values[0].key).toBe('theme');
+        expect(values[0].value).toBe('"dark"');
+        expect(values[0].updatedAt).toBe(theme.updatedAt.getTime());
+    });
+
+    test('nothing is sent while disconnected', () => {
+        client.connected = false;
+        manager.register('theme', 'light').set('dark');
+        expect(client.sent).toHaveLength(0);
+    });
+
+    test('nothing is sent to servers older than 2.2', () => {
+        client.protocolVersion = { major: 2, minor: 1 };
+        manager.register('theme', 'light').set('dark');
+        expect(client.sent).toHaveLength(0);
+    });
+
+    test('handleSync stores server values and updates registered prefs', () => {
+        const theme = manager.register('theme', 'light');
+        const seen = [];
+        theme.subscribe((value) => seen.push(value));
+
+        manager.handleSync([
+            { key: 'theme', value: '"dark"', updatedAt: 5000 },
+            { key: 'other', value: '42', updatedAt: 6000 },
+        ]);
+
+        expect(theme.get()).toBe('dark');
+        expect(theme.updatedAt.getTime()).toBe(5000);
+        expect(seen).toEqual(['dark']);
+        expect(manager.loadFromStorage('other')).toEqual({ value: 42, updatedAt: new Date(6000) });
+        // Server values are not echoed back.
+        expect(client.sent).toHaveLength(0);
+    });
+
+    test('PROMPT keeps the local value until resolved', () => {
+        const theme = manager.register('theme', 'light', { mergeStrategy: MergeStrategy.PROMPT });
+        theme._setFromRemote('dark', new Date(Date.now() + 1000));
+
+        expect(theme.get()).toBe('light');
+        expect(theme.conflict().value).toBe('dark');
+
+        theme.resolve(false);
+        expect(theme.get()).toBe('light');
+        expect(theme.conflict()).toBeNull();
+        expect(client.syncs()[0][0].value).toBe('"light"');
+    });
+});
@@ -0,0 +1,141 @@
+/**
+ * Transition tests
+ *
+ * Elements marked with vdom.Transition (data-transition) animate when
+ * patches insert, remove or move them.
+ */
+
+import { jest, describe, test, expect, beforeEach, afterEach } from '@jest/globals';
+import { PatchApplier } from '../src/patches.js';
+import { PatchType } from '../src/codec.js';
+import { isLeaving } from '../src/transitions.js';
+
+function createMockClient() {
+    const nodeMap = new Map();
+    return {
+        options: { debug: false },
+        nodeMap,
+        getNode: (hid) => nodeMap.get(hid),
+        registerNode: (hid, node) => nodeMap.set(hid, node),
+        unregisterNode: (hid) => nodeMap.delete(hid),
+        hooks: {
+            destroyForNode: jest.fn(),
+            initializeForNode: jest.fn(),
+        },
+    };
+}
+
+function item(hid) {
+    return { type: 'element', tag: 'li', hid, attrs: {}, children: [] };
+}
+
+describe('Transitions', () => {
+    let client;
+    let patches;
+    let list;
+
+    beforeEach(() => {
+        jest.useFakeTimers();
+        document.body.innerHTML =
+            '<ul data-hid="h1" data-transition="list" data-transition-timeout="300">' +
+            '<li data-hid="h2"></li><li data-hid="h3"></li></ul>';
+        client = createMockClient();
+        patches = new PatchApplier(client);
+        list = document.querySelector('ul');
+        client.registerNode('h1', list);
+        client.registerNode('h2', list.children[0]);
+        client.registerNode('h3', list.children[1]);
+    });
+
+    afterEach(() => {
+        jest.useRealTimers();
+    });
+
+    test('inserted children play the enter classes', () => {
+        patches.apply([{ type: PatchType.INSERT_NODE, parentID: 'h1', index: 2, vnode: item('h4') }]);
+        const el = client.getNode('h4');
+
+        expect(el.classList.contains('list-enter-from')).toBe(true);
+        expect(el.classList.contains('list-enter-active')).toBe(true);
+
+        jest.advanceTimersByTime(20);
+        expect(el.classList.contains('list-enter-from')).toBe(false);
+        expect(el.classList.contains('list-enter-to')).toBe(true);
+
+        jest.advanceTimersByTime(300);
+        expect(el.className).toBe('');
+    });
+
+    test('removed children stay until the leave transition ends', () => {
+        const el = client.getNode('h2');
+        patches.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+
+        expect(client.getNode('h2')).toBeUndefined();
+        expect(el.isConnected).toBe(true);
+        expect(isLeaving(el)).toBe(true);
+        expect(el.hasAttribute('inert')).toBe(true);
+        expect(el.classList.contains('list-leave-active')).toBe(true);
+
+        jest.advanceTimersByTime(20);
+        expect(el.classList.contains('list-leave-to')).toBe(true);
+
+        jest.advanceTimersByTime(300);
+        expect(el.isConnected).toBe(false);
+    });
+
+    test('transitionend ends the leave early', () => {
+        const el = client.getNode('h2');
+        patches.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+
+        // Events from descendants are ignored
+        const child = document.createElement('span');
+        el.appendChild(child);
+        child.dispatchEvent(new Event('transitionend', { bubbles: true }));
+        expect(el.isConnected).toBe(true);
+
+        el.dispatchEvent(new Event('transitionend'));
+        expect(el.isConnected).toBe(false);
+    });
+
+    test('indexes skip leaving children', () => {
+        const leavingEl = client.getNode('h2');
+        patches.apply([
+            { type: PatchType.REMOVE_NODE, hid: 'h2' },
+            { type: PatchType.INSERT_NODE, parentID: 'h1', index: 0, vnode: item('h4') },
+            { type: PatchType.MOVE_NODE, hid: 'h3', parentID: 'h1', index: 0 },
+        ]);
+
+        const live = Array.from(list.children).filter((el) => el !== leavingEl);
+        expect(live).toEqual([client.getNode('h3'), client.getNode('h4')]);
+    });
+
+    test('elements without a transition are removed at once', () => {
+        list.removeAttribute('data-transition');
+        const el = client.getNode('h2');
+
+        patches.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+
+        expect(el.isConnected).toBe(false);
+    });
+
+    test('moved children are animated with FLIP', () => {
+        const [a, b] = list.children;
+        // jsdom has no layout: report positions from DOM order
+        for (const el of [a, b]) {
+            el.getBoundingClientRect = () => {
+                const i = Array.from(list.children).indexOf(el);
+                return { left: 0, top: i * 10 };
+            };
+        }
+
+        patches.apply([{ type: PatchType.MOVE_NODE, hid: 'h3', parentID: 'h1', index: 0 }]);
+
+        expect(list.children[0]).toBe(b);
+        expect(b.classList.contains('list-move')).toBe(true);
+        expect(a.classList.contains('list-move')).toBe(true);
+        expect(b.style.transform).toBe('');
+
+        jest.advanceTimersByTime(300);
+        expect(b.classList.contains('list-move')).toBe(false);
+    });
+});
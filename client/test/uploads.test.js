@@ -0,0 +1,161 @@
+/**
+ * Live upload tests
+ *
+ * Files stream as FrameBlob frames under a window extended by BLOB_ACK, as
+ * read by pkg/server/blob.go.
+ */
+
+import { describe, test, expect, beforeEach } from '@jest/globals';
+import { BinaryCodec, BlobOp, BlobStatus } from '../src/codec.js';
+import { BlobUploader, BlobChunkSize, BlobWindow } from '../src/uploads.js';
+import { concat } from '../src/utils.js';
+
+const FRAME_BLOB = 0x06;
+
+function fakeFile(size, name = 'photo.png') {
+    const bytes = new Uint8Array(size);
+    for (let i = 0; i < size; i++) {
+        bytes[i] = i & 0xFF;
+    }
+    return {
+        name,
+        type: 'image/png',
+        size,
+        slice(start, end) {
+            return { arrayBuffer: async () => bytes.slice(start, end).buffer };
+        },
+    };
+}
+
+// Decode a sent blob frame: [op][id] and the op's fields
+function decodeBlob(codec, frame) {
+    expect(frame[0]).toBe(FRAME_BLOB);
+    const payload = frame.slice(4);
+    let offset = 1;
+    const { value: id, bytesRead } = codec.decodeUvarint(payload, offset);
+    offset += bytesRead;
+    const blob = { op: payload[0], id };
+    if (blob.op === BlobOp.START) {
+        const hid = codec.decodeString(payload, offset);
+        offset += hid.bytesRead;
+        const filename = codec.decodeString(payload, offset);
+        offset += filename.bytesRead;
+        const type = codec.decodeString(payload, offset);
+        offset += type.bytesRead;
+        blob.hid = hid.value;
+        blob.filename = filename.value;
+        blob.type = type.value;
+        blob.size = codec.decodeUvarint(payload, offset).value;
+    } else if (blob.op === BlobOp.CHUNK) {
+        const off = codec.decodeUvarint(payload, offset);
+        offset += off.bytesRead;
+        blob.offset = off.value;
+        const len = codec.decodeUvarint(payload, offset);
+        blob.length = len.value;
+    }
+    return blob;
+}
+
+function ack(codec, id, received, status, message = '') {
+    return concat([
+        codec.encodeUvarint(id),
+        codec.encodeUvarint(received),
+        new Uint8Array([status]),
+        codec.encodeString(message),
+    ]);
+}
+
+const flush = async () => {
+    for (let i = 0; i < 20; i++) {
+        await new Promise((resolve) => setTimeout(resolve, 0));
+    }
+};
+
+describe('BlobUploader', () => {
+    let codec;
+    let sent;
+    let el;
+    let events;
+    let uploader;
+
+    beforeEach(() => {
+        codec = new BinaryCodec();
+        sent = [];
+        events = [];
+        el = document.createElement('input');
+        el.addEventListener('vango:upload', (e) => events.push(e.detail));
+        const client = {
+            codec,
+            connected: true,
+            nodeMap: new Map([['h1', el]]),
+            wsManager: { send: (frame) => sent.push(frame) },
+            _onError() {},
+        };
+        uploader = new BlobUploader(client);
+        uploader.enabled = true;
+    });
+
+    test('does nothing unless the server accepts blobs', () => {
+        uploader.enabled = false;
+        expect(uploader.upload('h1', fakeFile(10))).toBe(0);
+        expect(sent).toHaveLength(0);
+    });
+
+    test('streams a small file', async () => {
+        const id = uploader.upload('h1', fakeFile(100));
+        await flush();
+
+        const blobs = sent.map((f) => decodeBlob(codec, f));
+        expect(blobs.map((b) => b.op)).toEqual([BlobOp.START, BlobOp.CHUNK, BlobOp.END]);
+        expect(blobs[0]).toMatchObject({ id, hid: 'h1', filename: 'photo.png', type: 'image/png', size: 100 });
+        expect(blobs[1]).toMatchObject({ offset: 0, length: 100 });
+
+        uploader.handleAck(ack(codec, id, 100, BlobStatus.DONE));
+        expect(events[events.length - 1]).toMatchObject({ id, status: 'done', loaded: 100 });
+        expect(uploader.uploads.size).toBe(0);
+    });
+
+    test('waits for acks beyond the window', async () => {
+        const size = BlobWindow * 2;
+        const id = uploader.upload('h1', fakeFile(size));
+        await flush();
+
+        let chunks = sent.map((f) => decodeBlob(codec, f)).filter((b) => b.op === BlobOp.CHUNK);
+        expect(chunks).toHaveLength(BlobWindow / BlobChunkSize);
+
+        uploader.handleAck(ack(codec, id, BlobChunkSize, BlobStatus.PROGRESS));
+        await flush();
+        chunks = sent.map((f) => decodeBlob(codec, f)).filter((b) => b.op === BlobOp.CHUNK);
+        expect(chunks).toHaveLength(BlobWindow / BlobChunkSize + 1);
+        expect(chunks[chunks.length - 1].offset).toBe(BlobWindow);
+        expect(events[events.length - 1]).toMatchObject({ status: 'progress', loaded: BlobChunkSize });
+
+        uploader.handleAck(ack(codec, id, size, BlobStatus.PROGRESS));
+        await flush();
+        const ops = sent.map((f) => decodeBlob(codec, f).op);
+        expect(ops[ops.length - 1]).toBe(BlobOp.END);
+    });
+
+    test('server errors stop the upload', async () => {
+        const id = uploader.upload('h1', fakeFile(BlobWindow * 2));
+        await flush();
+        const before = sent.length;
+
+        uploader.handleAck(ack(codec, id, 0, BlobStatus.ERROR, 'upload: file too large'));
+        await flush();
+        expect(sent).toHaveLength(before);
+        expect(events[events.length - 1]).toMatchObject({ status: 'error', error: 'upload: file too large' });
+    });
+
+    test('cancel and reset', async () => {
+        const a = uploader.upload('h1', fakeFile(10));
+        uploader.cancel(a);
+        const last = decodeBlob(codec, sent[sent.length - 1]);
+        expect(last).toMatchObject({ op: BlobOp.CANCEL, id: a });
+
+        uploader.upload('h1', fakeFile(BlobWindow * 2));
+        uploader.reset();
+        expect(uploader.uploads.size).toBe(0);
+        expect(events[events.length - 1]).toMatchObject({ status: 'error', error: 'disconnected' });
+    });
+});
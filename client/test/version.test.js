@@ -0,0 +1,100 @@
+/**
+ * Protocol version negotiation tests
+ *
+ * The client offers ProtocolVersion in its hello; the server answers with the
+ * version it will speak (pkg/protocol/version.go), or with the range it
+ * supports when it cannot speak to this client at all.
+ */
+
+import { describe, test, expect, beforeEach, afterEach, jest } from '@jest/globals';
+import { BinaryCodec, ProtocolVersion, versionAtLeast } from '../src/codec.js';
+import { WebSocketManager } from '../src/websocket.js';
+
+const codec = new BinaryCodec();
+
+// [type:1][flags:1][len:2][status:1][sessionId:""][nextSeq:4][serverTime:8][flags:2][trailer...]
+function serverHello(status, trailer = []) {
+    const payload = [status, 0, ...new Array(14).fill(0), ...trailer];
+    return new Uint8Array([0x00, 0x00, 0, payload.length, ...payload]);
+}
+
+describe('ServerHello version', () => {
+    test('reads the negotiated version', () => {
+        const hello = codec.decodeServerHello(serverHello(0x00, [2, 0]));
+        expect(hello.version).toEqual({ major: 2, minor: 0 });
+        expect(hello.authReason).toBeUndefined();
+    });
+
+    test('servers without a version speak 2.0', () => {
+        expect(codec.decodeServerHello(serverHello(0x00)).version).toEqual({ major: 2, minor: 0 });
+    });
+
+    test('version errors carry the supported range', () => {
+        const hello = codec.decodeServerHello(serverHello(0x01, [3, 0, 3, 2]));
+        expect(hello.minVersion).toEqual({ major: 3, minor: 0 });
+        expect(hello.version).toEqual({ major: 3, minor: 2 });
+        expect(hello.authReason).toBeUndefined();
+    });
+
+    test('other errors still carry an auth reason', () => {
+        expect(codec.decodeServerHello(serverHello(0x07, [0x02])).authReason).toBe(0x02);
+    });
+
+    test('versionAtLeast', () => {
+        expect(versionAtLeast(ProtocolVersion, 2, 1)).toBe(true);
+        expect(versionAtLeast({ major: 2, minor: 0 }, 2, 1)).toBe(false);
+        expect(versionAtLeast({ major: 3, minor: 0 }, 2, 1)).toBe(true);
+    });
+});
+
+describe('WebSocketManager version handling', () => {
+    let client;
+    let mgr;
+
+    beforeEach(() => {
+        jest.useFakeTimers();
+        sessionStorage.clear();
+
+        client = {
+            codec,
+            options: { debug: false },
+            protocolVersion: ProtocolVersion,
+            reassembler: { reset() {} },
+            connection: { setDisconnected: jest.fn() },
+            errors: [],
+            _onError(err) { this.errors.push(err); },
+            _onConnected() {},
+        };
+        mgr = new WebSocketManager(client, {});
+        mgr.ws = { close: jest.fn(), send: jest.fn() };
+    });
+
+    afterEach(() => {
+        jest.clearAllTimers();
+        jest.useRealTimers();
+    });
+
+    function receive(frame) {
+        mgr._onMessage({ data: frame.buffer });
+    }
+
+    test('an older server sets the negotiated version', () => {
+        receive(serverHello(0x00, [2, 0]));
+        expect(client.protocolVersion).toEqual({ major: 2, minor: 0 });
+        expect(mgr.handshakeComplete).toBe(true);
+    });
+
+    test('a version mismatch reloads once with a clear error', () => {
+        receive(serverHello(0x01, [3, 0, 3, 0]));
+        // jsdom cannot reload, so check for the scheduled reload instead
+        expect(jest.getTimerCount()).toBe(1);
+        expect(sessionStorage.getItem('__vango_version_reload')).not.toBeNull();
+        expect(client.errors[0].message).toBe('Version mismatch: client speaks 2.2, server 3.0-3.0');
+        expect(mgr.reconnectDisabled).toBe(true);
+
+        // Landing on the same mismatch right after the reload does not loop
+        jest.clearAllTimers();
+        receive(serverHello(0x05, [3, 0, 3, 0]));
+        expect(jest.getTimerCount()).toBe(0);
+    });
+});
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// defaultDiffListSize is the list size used by -diff when -list is not set.
+const defaultDiffListSize = 500
+
+// diffScenario describes one keyed list update.
+type diffScenario struct {
+	Name string
+	Next func(keys []string) []string
+}
+
+var diffScenarios = []diffScenario{
+	{"prepend", func(keys []string) []string {
+		return append([]string{"new"}, keys...)
+	}},
+	{"append", func(keys []string) []string {
+		return append(append([]string(nil), keys...), "new")
+	}},
+	{"remove-first", func(keys []string) []string {
+		return append([]string(nil), keys[1:]...)
+	}},
+	{"swap-ends", func(keys []string) []string {
+		out := append([]string(nil), keys...)
+		out[0], out[len(out)-1] = out[len(out)-1], out[0]
+		return out
+	}},
+	{"move-last-to-first", func(keys []string) []string {
+		out := []string{keys[len(keys)-1]}
+		return append(out, keys[:len(keys)-1]...)
+	}},
+	{"reverse", func(keys []string) []string {
+		out := make([]string, len(keys))
+		for i, k := range keys {
+			out[len(keys)-1-i] = k
+		}
+		return out
+	}},
+	{"shuffle", func(keys []string) []string {
+		out := append([]string(nil), keys...)
+		rng := rand.New(rand.NewSource(42))
+		rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+		return out
+	}},
+}
+
+// diffResult holds patch statistics for one algorithm.
+type diffResult struct {
+	Patches         int `json:"patches"`
+	Moves           int `json:"moves"`
+	Inserts         int `json:"inserts"`
+	Removes         int `json:"removes"`
+	EncodedBytes    int `json:"encoded_bytes"`
+	CompressedBytes int `json:"compressed_bytes"`
+}
+
+// diffScenarioReport compares the legacy and current keyed diff.
+type diffScenarioReport struct {
+	Scenario string     `json:"scenario"`
+	ListSize int        `json:"list_size"`
+	Legacy   diffResult `json:"legacy"`
+	Current  diffResult `json:"current"`
+}
+
+type diffReport struct {
+	Commit    string               `json:"commit"`
+	ListSize  int                  `json:"list_size"`
+	Scenarios []diffScenarioReport `json:"scenarios"`
+}
+
+// runDiffReport measures patch counts and encoded frame sizes for common
+// keyed list updates, comparing vdom.Diff against the previous
+// move-every-shifted-child algorithm.
+func runDiffReport(cfg benchConfig) diffReport {
+	size := cfg.ListSize
+	if size <= 0 {
+		size = defaultDiffListSize
+	}
+
+	keys := make([]string, size)
+	for i := range keys {
+		keys[i] = "row-" + strconv.Itoa(i)
+	}
+
+	report := diffReport{Commit: gitCommit(), ListSize: size}
+	for _, sc := range diffScenarios {
+		nextKeys := sc.Next(keys)
+
+		prev, next := diffTrees(keys, nextKeys)
+		current := summarizePatches(vdom.Diff(prev, next))
+
+		prev, next = diffTrees(keys, nextKeys)
+		legacy := summarizePatches(legacyKeyedDiff(prev, next))
+
+		report.Scenarios = append(report.Scenarios, diffScenarioReport{
+			Scenario: sc.Name,
+			ListSize: size,
+			Legacy:   legacy,
+			Current:  current,
+		})
+	}
+	return report
+}
+
+// diffTrees builds prev/next lists and assigns HIDs the way a session
+// re-render does.
+func diffTrees(prevKeys, nextKeys []string) (*vdom.VNode, *vdom.VNode) {
+	gen := vdom.NewHIDGenerator()
+	prev := diffList(prevKeys)
+	vdom.AssignHIDs(prev, gen)
+
+	next := diffList(nextKeys)
+	vdom.CopyHIDs(prev, next)
+	vdom.AssignHIDs(next, gen)
+	return prev, next
+}
+
+func diffList(keys []string) *vdom.VNode {
+	items := make([]any, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, vdom.Li(vdom.Key(k), vdom.Text(k)))
+	}
+	return vdom.Ul(items...)
+}
+
+// legacyKeyedDiff reproduces the previous keyed algorithm for the top-level
+// list: every matched child whose index changed is moved and every new
+// child is inserted before unmatched children are removed.
+func legacyKeyedDiff(prev, next *vdom.VNode) []vdom.Patch {
+	var patches []vdom.Patch
+	prevIndex := make(map[string]int, len(prev.Children))
+	for i, child := range prev.Children {
+		prevIndex[child.Key] = i
+	}
+
+	matched := make(map[int]bool)
+	for i, child := range next.Children {
+		prevIdx, ok := prevIndex[child.Key]
+		if !ok {
+			patches = append(patches, vdom.Patch{
+				Op:       vdom.PatchInsertNode,
+				ParentID: prev.HID,
+				Index:    i,
+				Node:     child,
+			})
+			continue
+		}
+		matched[prevIdx] = true
+		if prevIdx != i {
+			patches = append(patches, vdom.Patch{
+				Op:       vdom.PatchMoveNode,
+				HID:      prev.Children[prevIdx].HID,
+				ParentID: prev.HID,
+				Index:    i,
+			})
+		}
+	}
+	for i, child := range prev.Children {
+		if !matched[i] {
+			patches = append(patches, vdom.Patch{Op: vdom.PatchRemoveNode, HID: child.HID})
+		}
+	}
+	return patches
+}
+
+// summarizePatches counts patches and measures their encoded frame size.
+func summarizePatches(patches []vdom.Patch) diffResult {
+	res := diffResult{Patches: len(patches)}
+	wire := make([]protocol.Patch, len(patches))
+	for i, p := range patches {
+		switch p.Op {
+		case vdom.PatchMoveNode:
+			res.Moves++
+		case vdom.PatchInsertNode:
+			res.Inserts++
+		case vdom.PatchRemoveNode:
+			res.Removes++
+		}
+		wire[i] = protocol.Patch{
+			Op:       protocol.PatchOp(p.Op),
+			HID:      p.HID,
+			Key:      p.Key,
+			Value:    p.Value,
+			ParentID: p.ParentID,
+			Index:    p.Index,
+		}
+		if p.Node != nil {
+			wire[i].Node = protocol.VNodeToWire(p.Node)
+		}
+	}
+	payload := protocol.EncodePatches(&protocol.PatchesFrame{Seq: 1, Patches: wire})
+	res.EncodedBytes = len(payload)
+	res.CompressedBytes = compressedSize(payload)
+	return res
+}
+
+// compressedSize returns the payload size after compression on a fresh
+// connection, or the raw size if the payload is too large to compress.
+func compressedSize(payload []byte) int {
+	c, err := protocol.NewCompressor(protocol.CodecDeflate)
+	if err != nil {
+		return len(payload)
+	}
+	compressed, err := c.Compress(payload)
+	if err != nil {
+		return len(payload)
+	}
+	return len(compressed)
+}
+
+func writeDiffSummary(w io.Writer, report diffReport) {
+	fmt.Fprintln(w, "=== Vango Keyed Diff Report ===")
+	fmt.Fprintf(w, "List size: %d\n", report.ListSize)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-20s %18s %18s %22s %22s\n", "scenario", "patches (old/new)", "moves (old/new)", "bytes (old/new)", "deflate (old/new)")
+	for _, sc := range report.Scenarios {
+		fmt.Fprintf(w, "%-20s %18s %18s %22s %22s\n",
+			sc.Scenario,
+			fmt.Sprintf("%d/%d", sc.Legacy.Patches, sc.Current.Patches),
+			fmt.Sprintf("%d/%d", sc.Legacy.Moves, sc.Current.Moves),
+			fmt.Sprintf("%d/%d", sc.Legacy.EncodedBytes, sc.Current.EncodedBytes),
+			fmt.Sprintf("%d/%d", sc.Legacy.CompressedBytes, sc.Current.CompressedBytes))
+	}
+}
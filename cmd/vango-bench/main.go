@@ -124,6 +124,9 @@ type benchConfig struct {
 	MaxMemTotal   int64
 	JSONOutput    string
 	EventTimeout  time.Duration
+	DiffReport    bool
+	Compress      bool
+	CompressMin   int
 }
 
 type benchCounters struct {
@@ -132,7 +135,9 @@ type benchCounters struct {
 	eventsComplete atomic.Uint64
 	eventBytes     atomic.Uint64
 	patchBytes     atomic.Uint64
+	patchRawBytes  atomic.Uint64
 	patchFrames    atomic.Uint64
+	compressed     atomic.Uint64
 	patchesTotal   atomic.Uint64
 }
 
@@ -178,6 +183,15 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if cfg.DiffReport {
+		report := runDiffReport(cfg)
+		writeDiffSummary(os.Stderr, report)
+		if err := writeJSON(cfg.JSONOutput, report); err != nil {
+			log.Fatalf("write json: %v", err)
+		}
+		return
+	}
+
 	if cfg.MaxProcs > 0 {
 		runtime.GOMAXPROCS(cfg.MaxProcs)
 	}
@@ -188,6 +202,9 @@ func main() {
 	debug.SetGCPercent(100)
 
 	sessionCfg := server.DefaultSessionConfig()
+	if cfg.CompressMin >= 0 {
+		sessionCfg.CompressionThreshold = cfg.CompressMin
+	}
 	if cfg.RPS <= 0 && cfg.Duration >= sessionCfg.ReadTimeout {
 		sessionCfg.ReadTimeout = cfg.Duration + 5*time.Second
 	}
@@ -317,6 +334,9 @@ func parseConfig() (benchConfig, error) {
 	maxMemSessionFlag := flag.String("server-max-mem-per-session", "", "server max memory per session (e.g. 200KB, 0 disables)")
 	maxMemTotalFlag := flag.String("server-max-total-mem", "", "server max total memory (e.g. 1GiB, 0 disables)")
 	jsonFlag := flag.String("json", "-", "JSON output path ('-' for stdout)")
+	diffFlag := flag.Bool("diff", false, "report keyed diff patch counts and sizes instead of running the macrobench")
+	compressFlag := flag.Bool("compress", true, "advertise frame compression in the client handshake")
+	compressMinFlag := flag.Int("compress-threshold", -1, "server patch frame compression threshold in bytes (-1 for the server default)")
 	flag.Parse()
 
 	name := strings.ToLower(strings.TrimSpace(*profileFlag))
@@ -342,6 +362,9 @@ func parseConfig() (benchConfig, error) {
 		MaxMemSession: base.MaxMemSession,
 		MaxMemTotal:   base.MaxMemTotal,
 		JSONOutput:    strings.TrimSpace(*jsonFlag),
+		DiffReport:    *diffFlag,
+		Compress:      *compressFlag,
+		CompressMin:   *compressMinFlag,
 	}
 
 	if *clientsFlag != -1 {
@@ -359,6 +382,8 @@ func parseConfig() (benchConfig, error) {
 	}
 	if *listFlag != -1 {
 		cfg.ListSize = *listFlag
+	} else if cfg.DiffReport {
+		cfg.ListSize = 0
 	}
 	if *payloadFlag != -1 {
 		cfg.PayloadBytes = *payloadFlag
@@ -591,6 +616,9 @@ func runClient(
 	ch.ViewportW = 1280
 	ch.ViewportH = 720
 	ch.TZOffset = 0
+	if cfg.Compress {
+		ch.Codecs = protocol.SupportedCodecs
+	}
 
 	handshakeFrame := protocol.NewFrame(protocol.FrameHandshake, protocol.EncodeClientHello(ch))
 	if err := conn.WriteMessage(websocket.BinaryMessage, handshakeFrame.Encode()); err != nil {
@@ -623,6 +651,16 @@ func runClient(
 	}
 	counters.handshakesOK.Add(1)
 
+	var decompressor *protocol.Decompressor
+	if codec := sh.Codec(); codec != protocol.CodecNone {
+		decompressor, err = protocol.NewDecompressor(codec)
+		if err != nil {
+			errCounts.handshakeFailures.Add(1)
+			return fmt.Errorf("handshake codec: %w", err)
+		}
+	}
+	reassembler := protocol.NewReassembler(0)
+
 	if cfg.RPS <= 0 {
 		<-ctx.Done()
 		return nil
@@ -664,7 +702,7 @@ func runClient(
 			conn.SetReadDeadline(time.Now().Add(cfg.EventTimeout))
 		}
 		eventCtx, cancel := context.WithTimeout(ctx, cfg.EventTimeout)
-		found, err := waitForToken(eventCtx, conn, token, counters, errCounts, patchOps)
+		found, err := waitForToken(eventCtx, conn, decompressor, reassembler, token, counters, errCounts, patchOps)
 		cancel()
 		if err != nil {
 			if ctx.Err() != nil {
@@ -701,6 +739,8 @@ func runClient(
 func waitForToken(
 	ctx context.Context,
 	conn *websocket.Conn,
+	decompressor *protocol.Decompressor,
+	reassembler *protocol.Reassembler,
 	token string,
 	counters *benchCounters,
 	errCounts *benchErrors,
@@ -718,17 +758,38 @@ func waitForToken(
 			return false, err
 		}
 
-		frame, err := protocol.DecodeFrame(msg)
+		// Count wire bytes per frame, including every fragment
+		if len(msg) > 0 && protocol.FrameType(msg[0]) == protocol.FramePatches {
+			counters.patchBytes.Add(uint64(len(msg)))
+		}
+
+		frame, err := reassembler.DecodeFrame(msg)
 		if err != nil {
 			errCounts.frameDecodeFailures.Add(1)
 			return false, err
 		}
+		if frame == nil {
+			continue
+		}
 
 		switch frame.Type {
 		case protocol.FramePatches:
 			counters.patchFrames.Add(1)
-			counters.patchBytes.Add(uint64(len(msg)))
-			pf, err := protocol.DecodePatches(frame.Payload)
+			payload := frame.Payload
+			if frame.Flags.Has(protocol.FlagCompressed) {
+				if decompressor == nil {
+					errCounts.frameDecodeFailures.Add(1)
+					return false, fmt.Errorf("compressed frame without a negotiated codec")
+				}
+				payload, err = decompressor.Decompress(payload)
+				if err != nil {
+					errCounts.frameDecodeFailures.Add(1)
+					return false, err
+				}
+				counters.compressed.Add(1)
+			}
+			counters.patchRawBytes.Add(uint64(protocol.FrameHeaderSize + len(payload)))
+			pf, err := protocol.DecodePatches(payload)
 			if err != nil {
 				errCounts.patchDecodeFailures.Add(1)
 				return false, err
@@ -945,14 +1006,18 @@ type gcInfo struct {
 }
 
 type protocolInfo struct {
-	EventBytesTotal uint64            `json:"event_bytes_total"`
-	PatchBytesTotal uint64            `json:"patch_bytes_total"`
-	PatchFrames     uint64            `json:"patch_frames_total"`
-	PatchesTotal    uint64            `json:"patches_total"`
-	AvgEventBytes   float64           `json:"avg_event_bytes"`
-	AvgPatchBytes   float64           `json:"avg_patch_bytes"`
-	PatchesPerEvent float64           `json:"patches_per_event"`
-	PatchOps        map[string]uint64 `json:"patch_ops"`
+	EventBytesTotal       uint64            `json:"event_bytes_total"`
+	PatchBytesTotal       uint64            `json:"patch_bytes_total"`
+	PatchRawBytesTotal    uint64            `json:"patch_raw_bytes_total"`
+	PatchFrames           uint64            `json:"patch_frames_total"`
+	PatchFramesCompressed uint64            `json:"patch_frames_compressed"`
+	PatchesTotal          uint64            `json:"patches_total"`
+	AvgEventBytes         float64           `json:"avg_event_bytes"`
+	AvgPatchBytes         float64           `json:"avg_patch_bytes"`
+	AvgPatchRawBytes      float64           `json:"avg_patch_raw_bytes"`
+	CompressionRatio      float64           `json:"compression_ratio"`
+	PatchesPerEvent       float64           `json:"patches_per_event"`
+	PatchOps              map[string]uint64 `json:"patch_ops"`
 }
 
 type errorInfo struct {
@@ -985,6 +1050,7 @@ func buildReport(
 	patchFrames := counters.patchFrames.Load()
 	eventBytes := counters.eventBytes.Load()
 	patchBytes := counters.patchBytes.Load()
+	patchRawBytes := counters.patchRawBytes.Load()
 
 	elapsedSeconds := math.Max(0.001, elapsed.Seconds())
 	eventsPerSec := float64(eventsTotal) / elapsedSeconds
@@ -1006,8 +1072,14 @@ func buildReport(
 		avgEventBytes = float64(eventBytes) / float64(eventsSent)
 	}
 	avgPatchBytes := 0.0
+	avgPatchRawBytes := 0.0
 	if eventsTotal > 0 {
 		avgPatchBytes = float64(patchBytes) / float64(eventsTotal)
+		avgPatchRawBytes = float64(patchRawBytes) / float64(eventsTotal)
+	}
+	compressionRatio := 0.0
+	if patchBytes > 0 {
+		compressionRatio = float64(patchRawBytes) / float64(patchBytes)
 	}
 	patchesPerEvent := 0.0
 	if eventsTotal > 0 {
@@ -1061,14 +1133,18 @@ func buildReport(
 			AllocsObjects: afterMetrics.heapAllocsObjects - beforeMetrics.heapAllocsObjects,
 		},
 		Protocol: protocolInfo{
-			EventBytesTotal: eventBytes,
-			PatchBytesTotal: patchBytes,
-			PatchFrames:     patchFrames,
-			PatchesTotal:    patchesTotal,
-			AvgEventBytes:   avgEventBytes,
-			AvgPatchBytes:   avgPatchBytes,
-			PatchesPerEvent: patchesPerEvent,
-			PatchOps:        patchOps.snapshot(),
+			EventBytesTotal:       eventBytes,
+			PatchBytesTotal:       patchBytes,
+			PatchRawBytesTotal:    patchRawBytes,
+			PatchFrames:           patchFrames,
+			PatchFramesCompressed: counters.compressed.Load(),
+			PatchesTotal:          patchesTotal,
+			AvgEventBytes:         avgEventBytes,
+			AvgPatchBytes:         avgPatchBytes,
+			AvgPatchRawBytes:      avgPatchRawBytes,
+			CompressionRatio:      compressionRatio,
+			PatchesPerEvent:       patchesPerEvent,
+			PatchOps:              patchOps.snapshot(),
 		},
 		Errors: errorInfo{
 			TotalErrors:         errors.totalErrors.Load(),
@@ -1133,7 +1209,9 @@ func writeSummary(w io.Writer, report benchReport) {
 
 	fmt.Fprintln(w, "Protocol (avg per event):")
 	fmt.Fprintf(w, "  event bytes: %.1f\n", report.Protocol.AvgEventBytes)
-	fmt.Fprintf(w, "  patch bytes: %.1f\n", report.Protocol.AvgPatchBytes)
+	fmt.Fprintf(w, "  patch bytes: %.1f (raw %.1f)\n", report.Protocol.AvgPatchBytes, report.Protocol.AvgPatchRawBytes)
+	fmt.Fprintf(w, "  compressed frames: %d/%d (ratio %.2fx)\n",
+		report.Protocol.PatchFramesCompressed, report.Protocol.PatchFrames, report.Protocol.CompressionRatio)
 	fmt.Fprintf(w, "  patches/event: %.2f\n", report.Protocol.PatchesPerEvent)
 	fmt.Fprintln(w)
 
@@ -1146,7 +1224,7 @@ func writeSummary(w io.Writer, report benchReport) {
 	fmt.Fprintf(w, "  gc_cpu:    %.2f%%\n", report.GC.GCCPUFraction*100)
 }
 
-func writeJSON(path string, report benchReport) error {
+func writeJSON(path string, report any) error {
 	var out io.Writer
 	if path == "-" {
 		out = os.Stdout
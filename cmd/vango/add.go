@@ -1,22 +1,201 @@
 package main
 
 import (
-	"errors"
+	stderrors "errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/vango-go/vango/internal/config"
+	"github.com/vango-go/vango/internal/errors"
+	"github.com/vango-go/vango/internal/registry"
 )
 
 func addCmd() *cobra.Command {
+	var (
+		force       bool
+		list        bool
+		registryDir string
+	)
+
 	cmd := &cobra.Command{
-		Use:   "add",
-		Short: "VangoUI placeholder",
-		Long: `VangoUI is planned but not yet implemented.
+		Use:   "add <component>...",
+		Short: "Add VangoUI components to your project",
+		Long: `Install prebuilt VangoUI components into your project.
+
+Components are copied into the UI components directory (app/components/ui
+by default, see "ui.path" in vango.json) together with the components they
+depend on. Imports between components are rewritten to your module path.
+
+Files you have edited since they were added are never overwritten unless
+--force is given.
 
-This command is reserved for the future VangoUI installer.`,
+The registry is embedded in the vango binary. Use --registry (or "ui.registry"
+in vango.json) to install from a local registry directory instead.
+
+Examples:
+  vango add dialog
+  vango add tabs combobox
+  vango add data-table --force
+  vango add --list
+  vango add dialog --registry ../my-registry`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.New("VangoUI not yet implemented")
+			if len(args) == 0 && !list {
+				return cmd.Usage()
+			}
+			return runAdd(args, addOptions{
+				force:       force,
+				list:        list,
+				registryDir: registryDir,
+			})
 		},
 	}
 
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite locally modified component files")
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List available components")
+	cmd.Flags().StringVar(&registryDir, "registry", "", "Local registry directory (default: embedded registry)")
+
 	return cmd
 }
+
+type addOptions struct {
+	force       bool
+	list        bool
+	registryDir string
+}
+
+func runAdd(names []string, opts addOptions) error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return err
+	}
+
+	source, err := registrySource(cfg, opts.registryDir)
+	if err != nil {
+		return err
+	}
+	fsys, err := registry.Open(source)
+	if err != nil {
+		return errors.New("E144").Wrap(err)
+	}
+	manifest, err := registry.LoadManifest(fsys)
+	if err != nil {
+		return errors.New("E144").Wrap(err)
+	}
+
+	if opts.list {
+		printComponents(manifest)
+		return nil
+	}
+
+	modulePath, err := getModulePath(cfg.Dir())
+	if err != nil {
+		return err
+	}
+	uiDir := cfg.UIComponentsPath()
+	rel, err := filepath.Rel(cfg.Dir(), uiDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("UI components directory %s is outside the project module", uiDir)
+	}
+
+	installer := &registry.Installer{
+		FS:         fsys,
+		Manifest:   manifest,
+		Dir:        uiDir,
+		ImportPath: path.Join(modulePath, filepath.ToSlash(rel)),
+		Force:      opts.force,
+	}
+
+	plan, err := installer.Install(names...)
+	if err != nil {
+		var conflict *registry.ConflictError
+		switch {
+		case stderrors.As(err, &conflict):
+			return errors.New("E148").
+				WithDetail("Modified: " + strings.Join(conflict.Files, ", ")).
+				WithSuggestion("Re-run with --force to overwrite your changes")
+		case stderrors.Is(err, registry.ErrUnknownComponent):
+			return errors.New("E143").
+				WithDetail(err.Error()).
+				WithSuggestion("Run 'vango add --list' to see available components")
+		}
+		return err
+	}
+
+	for _, f := range plan.Files {
+		rel := filepath.Join(rel, filepath.FromSlash(f.Path))
+		switch f.Action {
+		case registry.ActionCreate:
+			success("Created %s", rel)
+		case registry.ActionUpdate:
+			success("Updated %s", rel)
+		case registry.ActionConflict:
+			warn("Overwrote modified %s", rel)
+		case registry.ActionUnchanged:
+			info("Unchanged %s", rel)
+		}
+	}
+
+	// Record installed components in vango.json
+	installed := append([]string(nil), cfg.UI.Installed...)
+	for _, c := range plan.Components {
+		if !containsString(installed, c.Name) {
+			installed = append(installed, c.Name)
+		}
+	}
+	sort.Strings(installed)
+	cfg.UI.Installed = installed
+	cfg.UI.Version = manifest.Version
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	success("Added %s (VangoUI %s)", strings.Join(names, ", "), manifest.Version)
+	return nil
+}
+
+// registrySource returns the local registry directory to install from, or ""
+// for the embedded registry.
+func registrySource(cfg *config.Config, flagDir string) (string, error) {
+	source := flagDir
+	if source == "" {
+		source = cfg.UI.Registry
+	}
+	if source == "" || source == config.DefaultRegistry {
+		return "", nil
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return "", errors.New("E144").
+			WithDetail("Remote registries are not supported: " + source).
+			WithSuggestion("Point ui.registry at a local registry directory or remove it to use the embedded registry")
+	}
+	if !filepath.IsAbs(source) && flagDir == "" {
+		source = filepath.Join(cfg.Dir(), source)
+	}
+	return source, nil
+}
+
+func printComponents(manifest *registry.Manifest) {
+	fmt.Printf("VangoUI %s components:\n\n", manifest.Version)
+	for _, name := range manifest.Names() {
+		c := manifest.Components[name]
+		line := fmt.Sprintf("%-12s %s", name, c.Description)
+		if len(c.Dependencies) > 0 {
+			line += fmt.Sprintf(" (requires %s)", strings.Join(c.Dependencies, ", "))
+		}
+		info("%s", line)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -47,6 +47,7 @@ and a thin JavaScript client. Features include:
 		testCmd(),
 		genCmd(),
 		addCmd(),
+		replayCmd(),
 		versionCmd(),
 	)
 
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/vango-go/vango/internal/config"
+)
+
+func replayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay <file>",
+		Short: "Replay a recorded session",
+		Long: `Replay a session recorded with Session.Record against the current code.
+
+The application's routes are mounted in a headless session, the recorded
+events are fed to it one at a time, and the patches it sends are compared
+with the recorded ones. The command reports the first event after which
+they differ and exits with status 1 if they do.
+
+Examples:
+  vango replay recordings/3f2a9c.vrec`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReplay(args[0])
+		},
+	}
+
+	return cmd
+}
+
+// replayMain is the harness built inside the project, so the replay runs
+// against the application's own routes.
+var replayMain = template.Must(template.New("replay").Parse(`// Code generated by vango replay. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vango-go/vango"
+	"github.com/vango-go/vango/pkg/server"
+
+	"{{.RoutesImport}}"
+)
+
+func main() {
+	app := vango.New(vango.Config{})
+	routes.Register(app)
+
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	rec, err := server.ReadRecording(f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fmt.Printf("Replaying %s (%d records)\n", rec.Path, len(rec.Records))
+
+	report, err := app.Server().Replay(rec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fmt.Print(report)
+	if report.Divergence != nil {
+		os.Exit(1)
+	}
+}
+`))
+
+func runReplay(file string) error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return err
+	}
+
+	recording, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(recording); err != nil {
+		return err
+	}
+
+	modulePath, err := getModulePath(cfg.Dir())
+	if err != nil {
+		return fmt.Errorf("could not determine module path: %w", err)
+	}
+	rel, err := filepath.Rel(cfg.Dir(), cfg.RoutesPath())
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(cfg.Dir(), ".vango", "replay")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, "main.go"))
+	if err != nil {
+		return err
+	}
+	err = replayMain.Execute(f, struct{ RoutesImport string }{
+		RoutesImport: path.Join(modulePath, filepath.ToSlash(rel)),
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	goCmd := exec.Command("go", "run", "./.vango/replay", recording)
+	goCmd.Dir = cfg.Dir()
+	goCmd.Stdout = os.Stdout
+	goCmd.Stderr = os.Stderr
+	if err := goCmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return fmt.Errorf("replay diverged from the recording")
+		}
+		return err
+	}
+
+	success("Replay matches the recording")
+	return nil
+}
@@ -40,6 +40,17 @@ type Config struct {
 	// If nil, slog.Default() is used.
 	Logger *slog.Logger
 
+	// GlobalSignalBackend synchronizes global signals created with SyncKey
+	// across server instances. If nil, global signals are process-local.
+	// The app owns the backend and closes it on Shutdown.
+	// Use vango.NewMemoryGlobalSignalBackend() or session.NewRedisSignalBackend().
+	GlobalSignalBackend GlobalSignalBackend
+
+	// BroadcastTransport carries Server.Broadcast messages between server
+	// instances. If nil, broadcasts are delivered in-process only.
+	// Use session.NewRedisBroadcastTransport() for Redis pub/sub.
+	BroadcastTransport BroadcastTransport
+
 	// OnSessionStart is called when a new WebSocket session is established.
 	// Use this to transfer data from the HTTP context (e.g., authenticated user)
 	// to the Vango session before the handshake completes.
@@ -372,6 +383,14 @@ func buildServerConfig(cfg Config) *server.ServerConfig {
 		serverCfg = serverCfg.WithDevMode()
 	}
 
+	// Global signal synchronization
+	if cfg.GlobalSignalBackend != nil {
+		serverCfg.GlobalSignalBackend = cfg.GlobalSignalBackend
+	}
+	if cfg.BroadcastTransport != nil {
+		serverCfg.BroadcastTransport = cfg.BroadcastTransport
+	}
+
 	// Context bridge
 	if cfg.OnSessionStart != nil {
 		serverCfg.OnSessionStart = cfg.OnSessionStart
@@ -1,7 +1,11 @@
 // This file re-exports vdom attribute helpers for the el package.
 package el
 
-import "github.com/vango-go/vango/pkg/vdom"
+import (
+	"time"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
 
 func ID(id string) Attr {
 	return vdom.ID(id)
@@ -312,3 +316,9 @@ func Inputmode(mode string) Attr {
 func Enterkeyhint(hint string) Attr {
 	return vdom.Enterkeyhint(hint)
 }
+func Transition(name string) Attr {
+	return vdom.Transition(name)
+}
+func TransitionTimeout(d time.Duration) Attr {
+	return vdom.TransitionTimeout(d)
+}
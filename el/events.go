@@ -96,6 +96,9 @@ func OnDragLeave(handler any) EventHandler {
 func OnDrop(handler any) EventHandler {
 	return vdom.OnDrop(handler)
 }
+func OnUpload(handler any) EventHandler {
+	return vdom.OnUpload(handler)
+}
 func OnTouchStart(handler any) EventHandler {
 	return vdom.OnTouchStart(handler)
 }
@@ -15,6 +15,9 @@ func Raw(html string) *VNode {
 func Fragment(children ...any) *VNode {
 	return vdom.Fragment(children...)
 }
+func Portal(target string, children ...any) *VNode {
+	return vdom.Portal(target, children...)
+}
 func If(condition bool, node *VNode) *VNode {
 	return vdom.If(condition, node)
 }
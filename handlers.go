@@ -53,6 +53,20 @@ type LayoutHandler = func(Ctx, Slot) *VNode
 // The framework inspects the handler signature to determine how to decode.
 type APIHandler = any
 
+// MetaHandler returns page metadata for the document head.
+// Two signatures are supported, mirroring PageHandler:
+//   - func(ctx Ctx) PageMeta                  - static page
+//   - func(ctx Ctx, params P) PageMeta        - dynamic page with typed params struct
+//
+// Register it with App.Meta; generated route registries do this for route
+// files that export a Meta function.
+type MetaHandler = any
+
+// PageMeta contains page metadata (title, description, OpenGraph tags,
+// canonical URL) rendered into <head> during SSR and applied to the
+// document during client-side navigation.
+type PageMeta = server.PageMeta
+
 // Slot represents child content passed to layouts.
 // It is the rendered VNode tree of the wrapped page or nested layout.
 type Slot = *VNode
@@ -286,6 +300,54 @@ func wrapPageHandler(handler any) router.PageHandler {
 	}
 }
 
+// wrapMetaHandler converts a user MetaHandler to the internal router.MetaHandler.
+func wrapMetaHandler(handler any) router.MetaHandler {
+	if fn, ok := handler.(func(Ctx) PageMeta); ok {
+		return func(ctx server.Ctx, params any) PageMeta {
+			return fn(ctx)
+		}
+	}
+
+	handlerVal := reflect.ValueOf(handler)
+	handlerType := handlerVal.Type()
+	if handlerType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vango: meta handler must be a function, got %T", handler))
+	}
+	pageMetaType := reflect.TypeOf(PageMeta{})
+	if handlerType.NumOut() != 1 || handlerType.Out(0) != pageMetaType {
+		panic(fmt.Sprintf("vango: meta handler must return exactly 1 value (PageMeta), got %s", handlerType))
+	}
+
+	ctxArg := func(ctx server.Ctx) reflect.Value {
+		if ctx == nil {
+			return reflect.Zero(handlerType.In(0))
+		}
+		return reflect.ValueOf(ctx)
+	}
+
+	switch handlerType.NumIn() {
+	case 1:
+		return func(ctx server.Ctx, params any) PageMeta {
+			results := handlerVal.Call([]reflect.Value{ctxArg(ctx)})
+			return results[0].Interface().(PageMeta)
+		}
+
+	case 2:
+		decoder := buildParamDecoder(handlerType.In(1))
+		return func(ctx server.Ctx, rawParams any) PageMeta {
+			paramsMap, ok := rawParams.(map[string]string)
+			if !ok {
+				paramsMap = make(map[string]string)
+			}
+			results := handlerVal.Call([]reflect.Value{ctxArg(ctx), decoder(paramsMap)})
+			return results[0].Interface().(PageMeta)
+		}
+
+	default:
+		panic(fmt.Sprintf("vango: meta handler has invalid signature (expected 1 or 2 args, got %d)", handlerType.NumIn()))
+	}
+}
+
 // wrapLayoutHandler converts a user LayoutHandler to the internal router.LayoutHandler.
 func wrapLayoutHandler(handler LayoutHandler) router.LayoutHandler {
 	return func(ctx server.Ctx, children router.Slot) *vdom.VNode {
@@ -314,6 +314,12 @@ var registry = map[string]ErrorTemplate{
 		Detail:   "Project names must be valid Go module names.",
 		DocURL:   "https://vango.dev/docs/errors/E147",
 	},
+	"E148": {
+		Category: CategoryCLI,
+		Message:  "Component files modified",
+		Detail:   "Installing would overwrite component files that were edited after they were added.",
+		DocURL:   "https://vango.dev/docs/errors/E148",
+	},
 
 	// ============================================
 	// Compile Errors (E160-E179)
@@ -0,0 +1,35 @@
+package registry
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+//go:embed bundle/registry.json bundle/*/*.go
+var bundleFS embed.FS
+
+// Bundle returns the registry embedded in the vango binary.
+func Bundle() fs.FS {
+	sub, err := fs.Sub(bundleFS, "bundle")
+	if err != nil {
+		panic(err) // the embedded layout is fixed at build time
+	}
+	return sub
+}
+
+// Open returns the registry at dir, or the embedded bundle if dir is empty.
+func Open(dir string) (fs.FS, error) {
+	if dir == "" {
+		return Bundle(), nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("registry: %s is not a directory", dir)
+	}
+	return os.DirFS(dir), nil
+}
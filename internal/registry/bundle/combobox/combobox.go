@@ -0,0 +1,127 @@
+// Package combobox provides a filterable select input.
+package combobox
+
+import (
+	"strings"
+
+	"github.com/vango-go/vango"
+	. "github.com/vango-go/vango/el"
+
+	"github.com/vango-go/vango/internal/registry/bundle/utils"
+)
+
+// Item is a selectable combobox entry.
+type Item struct {
+	Value string
+	Label string
+}
+
+// Options configures a Combobox.
+type Options struct {
+	// Value holds the selected item value.
+	Value *vango.Signal[string]
+
+	// Query holds the current filter text.
+	Query *vango.Signal[string]
+
+	// Open controls whether the list is shown.
+	Open *vango.Signal[bool]
+
+	// ID prefixes the generated listbox and option IDs.
+	ID          string
+	Placeholder string
+	Class       string
+}
+
+// Combobox renders a text input that filters items as the user types.
+// Enter selects the first match and Escape closes the list.
+func Combobox(p Options, items ...Item) *vango.VNode {
+	query := p.Query.Get()
+	open := p.Open.Get()
+	selected := p.Value.Get()
+	matches := Filter(items, query)
+	listID := p.ID + "-listbox"
+
+	choose := func(it Item) {
+		p.Value.Set(it.Value)
+		p.Query.Set(it.Label)
+		p.Open.Set(false)
+	}
+
+	var list *vango.VNode
+	if open {
+		list = Ul(
+			ID(listID),
+			Role("listbox"),
+			Class("absolute z-10 mt-1 max-h-60 w-full overflow-auto rounded-md border bg-white py-1 shadow dark:bg-gray-900"),
+			Range(matches, func(it Item, i int) *vango.VNode {
+				return Li(
+					Key(it.Value),
+					ID(p.ID+"-option-"+it.Value),
+					Role("option"),
+					AriaSelected(it.Value == selected),
+					Class(utils.Cn(
+						"cursor-pointer px-3 py-1.5 text-sm hover:bg-gray-100 dark:hover:bg-gray-800",
+						classIf(it.Value == selected, "font-medium"),
+					)),
+					OnClick(func() { choose(it) }),
+					Text(it.Label),
+				)
+			}),
+			If(len(matches) == 0, Li(Class("px-3 py-1.5 text-sm text-gray-500"), Text("No results"))),
+		)
+	}
+
+	return Div(Class(utils.Cn("relative w-full", p.Class)),
+		Input(
+			ID(p.ID),
+			Type("text"),
+			Role("combobox"),
+			Autocomplete("off"),
+			AriaExpanded(open),
+			AriaControls(listID),
+			Placeholder(p.Placeholder),
+			Value(query),
+			Class("w-full rounded-md border px-3 py-2 text-sm"),
+			OnInput(func(v string) {
+				p.Query.Set(v)
+				p.Open.Set(true)
+			}),
+			OnKeyDown(func(e vango.KeyboardEvent) {
+				switch e.Key {
+				case "Enter":
+					if len(matches) > 0 {
+						choose(matches[0])
+					}
+				case "Escape":
+					p.Open.Set(false)
+				case "ArrowDown":
+					p.Open.Set(true)
+				}
+			}),
+		),
+		list,
+	)
+}
+
+// Filter returns the items whose label contains query, ignoring case.
+func Filter(items []Item, query string) []Item {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return items
+	}
+	out := make([]Item, 0, len(items))
+	for _, it := range items {
+		if strings.Contains(strings.ToLower(it.Label), query) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func classIf(cond bool, class string) string {
+	if cond {
+		return class
+	}
+	return ""
+}
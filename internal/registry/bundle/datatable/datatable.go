@@ -0,0 +1,123 @@
+// Package datatable provides a sortable table for typed rows.
+package datatable
+
+import (
+	"sort"
+
+	"github.com/vango-go/vango"
+	. "github.com/vango-go/vango/el"
+
+	"github.com/vango-go/vango/internal/registry/bundle/utils"
+)
+
+// Column describes how to render and sort one table column.
+type Column[T any] struct {
+	Header string
+	Cell   func(row T) *vango.VNode
+
+	// Less orders rows for this column. Columns without Less are not sortable.
+	Less func(a, b T) bool
+}
+
+// SortState is the current sort column and direction.
+// Column is -1 when the table is unsorted.
+type SortState struct {
+	Column int
+	Desc   bool
+}
+
+// Options configures a DataTable.
+type Options struct {
+	// Sort holds the sort state. Clicking a sortable header updates it.
+	Sort *vango.Signal[SortState]
+
+	Empty string
+	Class string
+}
+
+// DataTable renders rows as a table. Clicking a sortable column header sorts
+// by that column; clicking it again reverses the order.
+func DataTable[T any](p Options, columns []Column[T], rows []T) *vango.VNode {
+	state := SortState{Column: -1}
+	if p.Sort != nil {
+		state = p.Sort.Get()
+	}
+	rows = sortRows(columns, rows, state)
+
+	toggle := func(col int) {
+		next := SortState{Column: col}
+		if state.Column == col {
+			next.Desc = !state.Desc
+		}
+		p.Sort.Set(next)
+	}
+
+	header := make([]*vango.VNode, len(columns))
+	for i, col := range columns {
+		sortable := col.Less != nil && p.Sort != nil
+		ariaSort := "none"
+		if state.Column == i {
+			ariaSort = "ascending"
+			if state.Desc {
+				ariaSort = "descending"
+			}
+		}
+		header[i] = Th(
+			Scope("col"),
+			Class(utils.Cn("px-3 py-2 text-left font-medium", classIf(sortable, "cursor-pointer select-none"))),
+			AttrIf(sortable, Data("sort", ariaSort)),
+			OnClick(func() {
+				if sortable {
+					toggle(i)
+				}
+			}),
+			Text(col.Header),
+		)
+	}
+
+	var body []*vango.VNode
+	for _, row := range rows {
+		cells := make([]*vango.VNode, len(columns))
+		for i, col := range columns {
+			cells[i] = Td(Class("px-3 py-2"), col.Cell(row))
+		}
+		body = append(body, Tr(Class("border-t"), cells))
+	}
+	if len(rows) == 0 {
+		empty := p.Empty
+		if empty == "" {
+			empty = "No results."
+		}
+		body = append(body, Tr(Td(Class("px-3 py-6 text-center text-gray-500"), Text(empty))))
+	}
+
+	return Div(Class(utils.Cn("w-full overflow-auto rounded-md border", p.Class)),
+		Table(Class("w-full text-sm"),
+			Thead(Tr(header)),
+			Tbody(body),
+		),
+	)
+}
+
+// sortRows returns a sorted copy of rows; the input slice is not modified.
+func sortRows[T any](columns []Column[T], rows []T, state SortState) []T {
+	if state.Column < 0 || state.Column >= len(columns) || columns[state.Column].Less == nil {
+		return rows
+	}
+	less := columns[state.Column].Less
+	out := append([]T(nil), rows...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if state.Desc {
+			return less(out[j], out[i])
+		}
+		return less(out[i], out[j])
+	})
+	return out
+}
+
+func classIf(cond bool, class string) string {
+	if cond {
+		return class
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+// Package dialog provides a modal dialog.
+package dialog
+
+import (
+	"github.com/vango-go/vango"
+	. "github.com/vango-go/vango/el"
+
+	"github.com/vango-go/vango/internal/registry/bundle/utils"
+)
+
+// Options configures a Modal.
+type Options struct {
+	// Open controls visibility. The dialog closes itself by setting it to false.
+	Open *vango.Signal[bool]
+
+	// ID is used to link the title and description for assistive technology.
+	ID string
+
+	Title       string
+	Description string
+	Class       string
+}
+
+// Modal renders a modal dialog with an overlay. Clicking the overlay, the
+// close button or pressing Escape closes it.
+func Modal(p Options, children ...any) *vango.VNode {
+	if !p.Open.Get() {
+		return nil
+	}
+
+	close := func() { p.Open.Set(false) }
+	titleID := p.ID + "-title"
+	descID := p.ID + "-description"
+
+	return Div(Class("fixed inset-0 z-50 flex items-center justify-center"),
+		Div(Class("fixed inset-0 bg-black/50"), AriaHidden(true), OnClick(close)),
+		Div(
+			ID(p.ID),
+			Role("dialog"),
+			AriaModal(true),
+			AriaLabelledBy(titleID),
+			AttrIf(p.Description != "", AriaDescribedBy(descID)),
+			TabIndex(-1),
+			Class(utils.Cn("relative z-50 w-full max-w-lg rounded-lg border bg-white p-6 shadow-lg dark:bg-gray-900", p.Class)),
+			OnKeyDown(func(e vango.KeyboardEvent) {
+				if e.Key == "Escape" {
+					close()
+				}
+			}),
+			H2(ID(titleID), Class("text-lg font-semibold"), Text(p.Title)),
+			If(p.Description != "", P(ID(descID), Class("mt-1 text-sm text-gray-500"), Text(p.Description))),
+			Div(append([]any{Class("mt-4")}, children...)...),
+			Button(
+				Type("button"),
+				Class("absolute right-4 top-4 opacity-70 hover:opacity-100"),
+				AriaLabel("Close"),
+				OnClick(close),
+				Text("×"),
+			),
+		),
+	)
+}
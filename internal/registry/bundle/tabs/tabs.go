@@ -0,0 +1,109 @@
+// Package tabs provides an accessible tab list with panels.
+package tabs
+
+import (
+	"github.com/vango-go/vango"
+	. "github.com/vango-go/vango/el"
+
+	"github.com/vango-go/vango/internal/registry/bundle/utils"
+)
+
+// Tab is a single tab and its panel content.
+type Tab struct {
+	Value   string
+	Label   string
+	Content *vango.VNode
+}
+
+// Options configures Tabs.
+type Options struct {
+	// Active holds the value of the selected tab.
+	Active *vango.Signal[string]
+
+	// ID prefixes the generated tab and panel IDs.
+	ID    string
+	Class string
+}
+
+// Tabs renders a tab list and the panel of the active tab. Arrow keys move
+// the selection between tabs.
+func Tabs(p Options, tabs ...Tab) *vango.VNode {
+	active := p.Active.Get()
+	if active == "" && len(tabs) > 0 {
+		active = tabs[0].Value
+	}
+
+	index := 0
+	for i, t := range tabs {
+		if t.Value == active {
+			index = i
+		}
+	}
+	move := func(delta int) {
+		if len(tabs) == 0 {
+			return
+		}
+		next := (index + delta + len(tabs)) % len(tabs)
+		p.Active.Set(tabs[next].Value)
+	}
+
+	var panel *vango.VNode
+	if len(tabs) > 0 {
+		t := tabs[index]
+		panel = Div(
+			ID(p.ID+"-panel-"+t.Value),
+			Role("tabpanel"),
+			AriaLabelledBy(p.ID+"-tab-"+t.Value),
+			Class("mt-2"),
+			t.Content,
+		)
+	}
+
+	return Div(Class(utils.Cn("w-full", p.Class)),
+		Div(
+			Role("tablist"),
+			Class("inline-flex items-center gap-1 rounded-md bg-gray-100 p-1 dark:bg-gray-800"),
+			OnKeyDown(func(e vango.KeyboardEvent) {
+				switch e.Key {
+				case "ArrowRight":
+					move(1)
+				case "ArrowLeft":
+					move(-1)
+				}
+			}),
+			Range(tabs, func(t Tab, i int) *vango.VNode {
+				selected := i == index
+				return Button(
+					Key(t.Value),
+					ID(p.ID+"-tab-"+t.Value),
+					Type("button"),
+					Role("tab"),
+					AriaSelected(selected),
+					AriaControls(p.ID+"-panel-"+t.Value),
+					TabIndex(tabIndex(selected)),
+					Class(utils.Cn(
+						"rounded px-3 py-1.5 text-sm font-medium",
+						classIf(selected, "bg-white shadow dark:bg-gray-900"),
+					)),
+					OnClick(func() { p.Active.Set(t.Value) }),
+					Text(t.Label),
+				)
+			}),
+		),
+		panel,
+	)
+}
+
+func tabIndex(selected bool) int {
+	if selected {
+		return 0
+	}
+	return -1
+}
+
+func classIf(cond bool, class string) string {
+	if cond {
+		return class
+	}
+	return ""
+}
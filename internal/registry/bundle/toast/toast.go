@@ -0,0 +1,89 @@
+// Package toast provides toast notifications and the viewport that shows them.
+package toast
+
+import (
+	"github.com/vango-go/vango"
+	. "github.com/vango-go/vango/el"
+
+	"github.com/vango-go/vango/internal/registry/bundle/utils"
+)
+
+// Toast is a single notification.
+type Toast struct {
+	ID          int
+	Title       string
+	Description string
+
+	// Variant is "default" or "destructive".
+	Variant string
+}
+
+// Toaster holds the visible toasts.
+type Toaster struct {
+	toasts *vango.Signal[[]Toast]
+	next   int
+}
+
+// New creates a Toaster. Call it during render (or once per session) so
+// the underlying signal is owned by the component tree.
+func New() *Toaster {
+	return &Toaster{toasts: vango.NewSignal([]Toast(nil))}
+}
+
+// Show adds a toast and returns its ID.
+func (t *Toaster) Show(toast Toast) int {
+	t.next++
+	toast.ID = t.next
+	t.toasts.Update(func(list []Toast) []Toast {
+		return append(append([]Toast(nil), list...), toast)
+	})
+	return toast.ID
+}
+
+// Dismiss removes the toast with the given ID.
+func (t *Toaster) Dismiss(id int) {
+	t.toasts.Update(func(list []Toast) []Toast {
+		out := make([]Toast, 0, len(list))
+		for _, toast := range list {
+			if toast.ID != id {
+				out = append(out, toast)
+			}
+		}
+		return out
+	})
+}
+
+// Viewport renders the toasts in a fixed corner region that screen readers
+// announce politely.
+func Viewport(t *Toaster, class ...string) *vango.VNode {
+	return Ol(
+		AriaLive("polite"),
+		Class(utils.Cn(append([]string{"fixed bottom-0 right-0 z-50 flex w-full max-w-sm flex-col gap-2 p-4"}, class...)...)),
+		Range(t.toasts.Get(), func(toast Toast, i int) *vango.VNode {
+			return Li(
+				Key(toast.ID),
+				Role("status"),
+				Class(utils.Cn(
+					"relative rounded-md border p-4 pr-8 shadow-lg",
+					variantClass(toast.Variant),
+				)),
+				Div(Class("text-sm font-semibold"), Text(toast.Title)),
+				If(toast.Description != "", Div(Class("text-sm opacity-90"), Text(toast.Description))),
+				Button(
+					Type("button"),
+					Class("absolute right-2 top-2 opacity-70 hover:opacity-100"),
+					AriaLabel("Dismiss"),
+					OnClick(func() { t.Dismiss(toast.ID) }),
+					Text("×"),
+				),
+			)
+		}),
+	)
+}
+
+func variantClass(variant string) string {
+	if variant == "destructive" {
+		return "border-red-600 bg-red-600 text-white"
+	}
+	return "bg-white dark:bg-gray-900"
+}
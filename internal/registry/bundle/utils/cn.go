@@ -0,0 +1,15 @@
+// Package utils contains helpers shared by VangoUI components.
+package utils
+
+import "strings"
+
+// Cn joins class names, skipping empty values.
+func Cn(classes ...string) string {
+	out := make([]string, 0, len(classes))
+	for _, c := range classes {
+		if c = strings.TrimSpace(c); c != "" {
+			out = append(out, c)
+		}
+	}
+	return strings.Join(out, " ")
+}
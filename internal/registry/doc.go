@@ -0,0 +1,44 @@
+// Package registry installs prebuilt VangoUI components into a project.
+//
+// A registry is a directory (or embedded bundle) containing a registry.json
+// manifest and the component source files it lists:
+//
+//	registry.json
+//	utils/cn.go
+//	dialog/dialog.go
+//
+// The manifest is versioned and declares the import path the component
+// sources use for each other:
+//
+//	{
+//	  "schema": 1,
+//	  "version": "0.1.0",
+//	  "module": "github.com/acme/ui",
+//	  "components": {
+//	    "utils":  {"files": ["utils/cn.go"]},
+//	    "dialog": {"files": ["dialog/dialog.go"], "dependencies": ["utils"]}
+//	  }
+//	}
+//
+// # Installing
+//
+// An Installer resolves the requested components and their dependencies,
+// rewrites imports of the registry module to the project's import path, and
+// writes the files into the destination directory:
+//
+//	in := &registry.Installer{
+//	    FS:         registry.Bundle(),
+//	    Manifest:   manifest,
+//	    Dir:        "/path/to/app/components/ui",
+//	    ImportPath: "myapp/app/components/ui",
+//	}
+//	plan, err := in.Install("dialog")
+//
+// Installed file hashes are recorded in a lock file in the destination
+// directory. A file that no longer matches its recorded hash has been edited
+// locally; Install refuses to overwrite it unless Force is set.
+//
+// The default bundle is embedded in the vango binary. Its sources live in
+// the bundle directory of this package and are compiled with the framework,
+// so they always match the current API.
+package registry
@@ -0,0 +1,294 @@
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LockFile is the name of the lock file written to the destination directory.
+const LockFile = "registry.lock.json"
+
+// Action describes what Install does with a file.
+type Action int
+
+const (
+	// ActionCreate writes a file that does not exist yet.
+	ActionCreate Action = iota
+	// ActionUpdate replaces a file that has not been modified since install.
+	ActionUpdate
+	// ActionUnchanged leaves a file that already has the new content.
+	ActionUnchanged
+	// ActionConflict marks a locally modified file. It is only overwritten
+	// when Force is set.
+	ActionConflict
+)
+
+// String returns the action name.
+func (a Action) String() string {
+	switch a {
+	case ActionCreate:
+		return "create"
+	case ActionUpdate:
+		return "update"
+	case ActionUnchanged:
+		return "unchanged"
+	case ActionConflict:
+		return "conflict"
+	default:
+		return "unknown"
+	}
+}
+
+// ConflictError is returned by Install when files were modified locally
+// and Force is not set. No files are written in that case.
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("registry: %d locally modified file(s) would be overwritten: %s",
+		len(e.Files), strings.Join(e.Files, ", "))
+}
+
+// PlannedFile is a file that Install writes or checks.
+type PlannedFile struct {
+	// Path is the file path relative to the destination directory.
+	Path string
+
+	// Component is the name of the component the file belongs to.
+	Component string
+
+	Action Action
+
+	content []byte
+}
+
+// Plan is the result of resolving an install.
+type Plan struct {
+	// Version is the registry version being installed.
+	Version string
+
+	// Components are the installed components, dependencies first.
+	Components []*Component
+
+	Files []PlannedFile
+}
+
+// Conflicts returns the paths of locally modified files.
+func (p *Plan) Conflicts() []string {
+	var out []string
+	for _, f := range p.Files {
+		if f.Action == ActionConflict {
+			out = append(out, f.Path)
+		}
+	}
+	return out
+}
+
+// Lock records the registry version and the hashes of installed files.
+type Lock struct {
+	Version    string            `json:"version"`
+	Components []string          `json:"components"`
+	Files      map[string]string `json:"files"`
+}
+
+// Installer copies registry components into a project.
+type Installer struct {
+	// FS is the registry file system.
+	FS fs.FS
+
+	// Manifest is the registry manifest loaded from FS.
+	Manifest *Manifest
+
+	// Dir is the destination directory.
+	Dir string
+
+	// ImportPath is the Go import path of Dir in the project's module.
+	// Imports of Manifest.Module are rewritten to it.
+	ImportPath string
+
+	// Force overwrites locally modified files.
+	Force bool
+}
+
+// Plan resolves the named components and decides what to do with each file
+// without writing anything.
+func (in *Installer) Plan(names ...string) (*Plan, error) {
+	components, err := in.Manifest.Resolve(names...)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := ReadLock(in.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Version: in.Manifest.Version, Components: components}
+	for _, c := range components {
+		for _, file := range c.Files {
+			src, err := fs.ReadFile(in.FS, file)
+			if err != nil {
+				return nil, fmt.Errorf("registry: component %q: %w", c.Name, err)
+			}
+			if strings.HasSuffix(file, ".go") {
+				src, err = RewriteImports(src, in.Manifest.Module, in.ImportPath)
+				if err != nil {
+					return nil, fmt.Errorf("registry: %s: %w", file, err)
+				}
+			}
+			plan.Files = append(plan.Files, PlannedFile{
+				Path:      file,
+				Component: c.Name,
+				Action:    in.action(file, src, lock),
+				content:   src,
+			})
+		}
+	}
+	return plan, nil
+}
+
+// action decides how to handle a file given its current state on disk.
+func (in *Installer) action(file string, content []byte, lock *Lock) Action {
+	existing, err := os.ReadFile(filepath.Join(in.Dir, filepath.FromSlash(file)))
+	if err != nil {
+		return ActionCreate
+	}
+	if bytes.Equal(existing, content) {
+		return ActionUnchanged
+	}
+	if recorded, ok := lock.Files[file]; ok && recorded == hashBytes(existing) {
+		return ActionUpdate
+	}
+	return ActionConflict
+}
+
+// Install writes the named components and their dependencies into Dir and
+// updates the lock file. If any file was modified locally and Force is not
+// set, it returns a *ConflictError and writes nothing.
+func (in *Installer) Install(names ...string) (*Plan, error) {
+	plan, err := in.Plan(names...)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts := plan.Conflicts(); len(conflicts) > 0 && !in.Force {
+		return plan, &ConflictError{Files: conflicts}
+	}
+
+	lock, err := ReadLock(in.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range plan.Files {
+		if f.Action != ActionUnchanged {
+			dst := filepath.Join(in.Dir, filepath.FromSlash(f.Path))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(dst, f.content, 0644); err != nil {
+				return nil, err
+			}
+		}
+		lock.Files[f.Path] = hashBytes(f.content)
+	}
+
+	lock.Version = plan.Version
+	for _, c := range plan.Components {
+		lock.Components = appendUnique(lock.Components, c.Name)
+	}
+	sort.Strings(lock.Components)
+	if err := writeLock(in.Dir, lock); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ReadLock reads the lock file in dir. A missing lock file yields an empty lock.
+func ReadLock(dir string) (*Lock, error) {
+	lock := &Lock{Files: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(dir, LockFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("registry: parse %s: %w", LockFile, err)
+	}
+	if lock.Files == nil {
+		lock.Files = make(map[string]string)
+	}
+	return lock, nil
+}
+
+func writeLock(dir string, lock *Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, LockFile), append(data, '\n'), 0644)
+}
+
+// RewriteImports replaces imports of from (and its subpackages) in a Go
+// source file with the same packages under to.
+func RewriteImports(src []byte, from, to string) ([]byte, error) {
+	if from == "" || from == to {
+		return src, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	out := src
+	// Replace from the last import backwards so earlier offsets stay valid.
+	for i := len(file.Imports) - 1; i >= 0; i-- {
+		lit := file.Imports[i].Path
+		importPath, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		if importPath != from && !strings.HasPrefix(importPath, from+"/") {
+			continue
+		}
+		rewritten := path.Join(to, strings.TrimPrefix(importPath, from))
+		start := fset.Position(lit.Pos()).Offset
+		end := fset.Position(lit.End()).Offset
+		out = append(append(append([]byte(nil), out[:start]...), strconv.Quote(rewritten)...), out[end:]...)
+	}
+	// Re-sort import groups after the paths changed.
+	return format.Source(out)
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
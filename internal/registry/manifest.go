@@ -0,0 +1,157 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the name of the manifest at the root of a registry.
+const ManifestFile = "registry.json"
+
+// SchemaVersion is the manifest schema version understood by this package.
+const SchemaVersion = 1
+
+var (
+	// ErrUnknownComponent is returned when a requested component or a
+	// dependency is not in the manifest.
+	ErrUnknownComponent = errors.New("registry: unknown component")
+
+	// ErrDependencyCycle is returned when component dependencies form a cycle.
+	ErrDependencyCycle = errors.New("registry: dependency cycle")
+)
+
+// Manifest describes the components available in a registry.
+type Manifest struct {
+	// Schema is the manifest schema version.
+	Schema int `json:"schema"`
+
+	// Version is the registry release version.
+	Version string `json:"version"`
+
+	// Module is the import path prefix the component sources use to import
+	// each other. It is rewritten to the project's import path on install.
+	Module string `json:"module"`
+
+	// Components maps component names to their definitions.
+	Components map[string]*Component `json:"components"`
+}
+
+// Component is a single installable component.
+type Component struct {
+	// Name is the component name (the manifest key).
+	Name string `json:"-"`
+
+	// Description is a short summary shown by `vango add --list`.
+	Description string `json:"description,omitempty"`
+
+	// Files are the component's source files, relative to the registry root.
+	Files []string `json:"files"`
+
+	// Dependencies are the names of components this component imports.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// LoadManifest reads and validates the manifest at the root of fsys.
+func LoadManifest(fsys fs.FS) (*Manifest, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("registry: read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("registry: parse manifest: %w", err)
+	}
+	if m.Schema != SchemaVersion {
+		return nil, fmt.Errorf("registry: unsupported manifest schema %d (want %d)", m.Schema, SchemaVersion)
+	}
+	if m.Version == "" {
+		return nil, errors.New("registry: manifest has no version")
+	}
+
+	for name, c := range m.Components {
+		if c == nil {
+			return nil, fmt.Errorf("registry: component %q has no definition", name)
+		}
+		c.Name = name
+		if len(c.Files) == 0 {
+			return nil, fmt.Errorf("registry: component %q has no files", name)
+		}
+		for _, f := range c.Files {
+			if !fs.ValidPath(f) || f != path.Clean(f) {
+				return nil, fmt.Errorf("registry: component %q has invalid file path %q", name, f)
+			}
+			if _, err := fs.Stat(fsys, f); err != nil {
+				return nil, fmt.Errorf("registry: component %q: %w", name, err)
+			}
+		}
+		for _, dep := range c.Dependencies {
+			if _, ok := m.Components[dep]; !ok {
+				return nil, fmt.Errorf("%w: %q (dependency of %q)", ErrUnknownComponent, dep, name)
+			}
+		}
+	}
+	return &m, nil
+}
+
+// Names returns the component names in sorted order.
+func (m *Manifest) Names() []string {
+	names := make([]string, 0, len(m.Components))
+	for name := range m.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the named components and all of their dependencies,
+// ordered so that every component comes after its dependencies.
+func (m *Manifest) Resolve(names ...string) ([]*Component, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var order []*Component
+	var stack []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		c, ok := m.Components[name]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownComponent, name)
+		}
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, strings.Join(stack, " -> "), name)
+		}
+
+		state[name] = visiting
+		stack = append(stack, name)
+		deps := append([]string(nil), c.Dependencies...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		order = append(order, c)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
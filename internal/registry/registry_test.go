@@ -0,0 +1,238 @@
+package registry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const testManifest = `{
+  "schema": 1,
+  "version": "1.2.0",
+  "module": "example.com/ui",
+  "components": {
+    "base":   {"files": ["base/base.go"]},
+    "button": {"files": ["button/button.go"], "dependencies": ["base"]},
+    "dialog": {"files": ["dialog/dialog.go", "dialog/dialog.css"], "dependencies": ["button", "base"]}
+  }
+}`
+
+func testRegistry() fstest.MapFS {
+	return fstest.MapFS{
+		"registry.json":     {Data: []byte(testManifest)},
+		"base/base.go":      {Data: []byte("package base\n\nconst Name = \"base\"\n")},
+		"button/button.go":  {Data: []byte("package button\n\nimport \"example.com/ui/base\"\n\nvar _ = base.Name\n")},
+		"dialog/dialog.go":  {Data: []byte("package dialog\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/ui/base\"\n\t\"example.com/ui/button\"\n)\n\nvar _ = fmt.Sprint(base.Name)\nvar _ = button.X\n")},
+		"dialog/dialog.css": {Data: []byte(".dialog {}\n")},
+	}
+}
+
+func testInstaller(t *testing.T) *Installer {
+	t.Helper()
+	fsys := testRegistry()
+	m, err := LoadManifest(fsys)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	return &Installer{FS: fsys, Manifest: m, Dir: t.TempDir(), ImportPath: "shop/app/components/ui"}
+}
+
+func TestLoadManifestValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"schema", `{"schema": 2, "version": "1"}`, "unsupported manifest schema"},
+		{"version", `{"schema": 1}`, "no version"},
+		{"missing file", `{"schema": 1, "version": "1", "components": {"a": {"files": ["a/a.go"]}}}`, "a/a.go"},
+		{"bad path", `{"schema": 1, "version": "1", "components": {"a": {"files": ["../a.go"]}}}`, "invalid file path"},
+		{"unknown dep", `{"schema": 1, "version": "1", "components": {"a": {"files": ["registry.json"], "dependencies": ["b"]}}}`, "unknown component"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadManifest(fstest.MapFS{"registry.json": {Data: []byte(tt.manifest)}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadManifest error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOrdersDependenciesFirst(t *testing.T) {
+	m, err := LoadManifest(testRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := m.Resolve("dialog", "base")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	var names []string
+	for _, c := range got {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "base,button,dialog" {
+		t.Errorf("Resolve order = %v, want [base button dialog]", names)
+	}
+
+	if _, err := m.Resolve("nope"); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("Resolve(nope) error = %v, want ErrUnknownComponent", err)
+	}
+}
+
+func TestResolveDetectsCycles(t *testing.T) {
+	m := &Manifest{Components: map[string]*Component{
+		"a": {Name: "a", Dependencies: []string{"b"}},
+		"b": {Name: "b", Dependencies: []string{"a"}},
+	}}
+	if _, err := m.Resolve("a"); !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Resolve error = %v, want ErrDependencyCycle", err)
+	}
+}
+
+func TestRewriteImports(t *testing.T) {
+	src := []byte("package x\n\nimport (\n\t\"example.com/ui\"\n\t\"example.com/ui/base\"\n\t\"example.com/uikit\"\n)\n")
+	out, err := RewriteImports(src, "example.com/ui", "shop/ui")
+	if err != nil {
+		t.Fatalf("RewriteImports: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{`"shop/ui"`, `"shop/ui/base"`, `"example.com/uikit"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("output missing %s:\n%s", want, s)
+		}
+	}
+}
+
+func TestInstallWritesFilesAndLock(t *testing.T) {
+	in := testInstaller(t)
+
+	plan, err := in.Install("dialog")
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if len(plan.Files) != 4 {
+		t.Fatalf("planned %d files, want 4", len(plan.Files))
+	}
+
+	dialog, err := os.ReadFile(filepath.Join(in.Dir, "dialog", "dialog.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(dialog), `"shop/app/components/ui/button"`) || strings.Contains(string(dialog), "example.com/ui") {
+		t.Errorf("imports not rewritten:\n%s", dialog)
+	}
+	if css, _ := os.ReadFile(filepath.Join(in.Dir, "dialog", "dialog.css")); string(css) != ".dialog {}\n" {
+		t.Errorf("non-Go file = %q, want copied verbatim", css)
+	}
+
+	lock, err := ReadLock(in.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Version != "1.2.0" || len(lock.Files) != 4 || strings.Join(lock.Components, ",") != "base,button,dialog" {
+		t.Errorf("lock = %+v", lock)
+	}
+
+	// Reinstalling leaves files alone.
+	plan, err = in.Install("dialog")
+	if err != nil {
+		t.Fatalf("second Install: %v", err)
+	}
+	for _, f := range plan.Files {
+		if f.Action != ActionUnchanged {
+			t.Errorf("%s action = %s, want unchanged", f.Path, f.Action)
+		}
+	}
+}
+
+func TestInstallUpdatesUnmodifiedFiles(t *testing.T) {
+	in := testInstaller(t)
+	if _, err := in.Install("base"); err != nil {
+		t.Fatal(err)
+	}
+
+	in.FS.(fstest.MapFS)["base/base.go"] = &fstest.MapFile{Data: []byte("package base\n\nconst Name = \"v2\"\n")}
+	plan, err := in.Install("base")
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if plan.Files[0].Action != ActionUpdate {
+		t.Errorf("action = %s, want update", plan.Files[0].Action)
+	}
+	if got, _ := os.ReadFile(filepath.Join(in.Dir, "base", "base.go")); !strings.Contains(string(got), "v2") {
+		t.Errorf("file not updated: %s", got)
+	}
+}
+
+func TestInstallRefusesModifiedFiles(t *testing.T) {
+	in := testInstaller(t)
+	if _, err := in.Install("button"); err != nil {
+		t.Fatal(err)
+	}
+	edited := filepath.Join(in.Dir, "base", "base.go")
+	if err := os.WriteFile(edited, []byte("package base\n\nconst Name = \"mine\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := in.Install("dialog")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != "base/base.go" {
+		t.Fatalf("Install error = %v, want conflict on base/base.go", err)
+	}
+	if _, err := os.Stat(filepath.Join(in.Dir, "dialog", "dialog.go")); !os.IsNotExist(err) {
+		t.Error("no files should be written when there are conflicts")
+	}
+
+	in.Force = true
+	if _, err := in.Install("dialog"); err != nil {
+		t.Fatalf("forced Install: %v", err)
+	}
+	if got, _ := os.ReadFile(edited); strings.Contains(string(got), "mine") {
+		t.Error("--force should overwrite the modified file")
+	}
+}
+
+func TestInstallTreatsUntrackedExistingFileAsModified(t *testing.T) {
+	in := testInstaller(t)
+	if err := os.MkdirAll(filepath.Join(in.Dir, "base"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(in.Dir, "base", "base.go"), []byte("package base\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var conflict *ConflictError
+	if _, err := in.Install("base"); !errors.As(err, &conflict) {
+		t.Fatalf("Install error = %v, want ConflictError", err)
+	}
+}
+
+func TestBundleManifest(t *testing.T) {
+	m, err := LoadManifest(Bundle())
+	if err != nil {
+		t.Fatalf("embedded manifest: %v", err)
+	}
+	for _, name := range []string{"dialog", "tabs", "combobox", "data-table", "toast"} {
+		if _, err := m.Resolve(name); err != nil {
+			t.Errorf("Resolve(%q): %v", name, err)
+		}
+	}
+
+	in := &Installer{FS: Bundle(), Manifest: m, Dir: t.TempDir(), ImportPath: "shop/ui"}
+	if _, err := in.Install(m.Names()...); err != nil {
+		t.Fatalf("Install all: %v", err)
+	}
+	src, err := os.ReadFile(filepath.Join(in.Dir, "dialog", "dialog.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), `"shop/ui/utils"`) {
+		t.Errorf("bundle imports not rewritten:\n%s", src)
+	}
+}
@@ -0,0 +1,199 @@
+package form
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormValues is submitted form data that a Form can bind from.
+// It is implemented by vango.FormData and server.FormData.
+type FormValues interface {
+	GetAll(key string) []string
+}
+
+// Bind copies submitted form values into the form's struct fields.
+//
+// Fields are looked up by their form path (e.g., "email", "address.city").
+// Slice fields receive every submitted value, so multi-selects, checkbox
+// groups and repeated inputs map onto []string, []int and similar fields;
+// a "tags[]" key is accepted for a "tags" slice field. Scalar fields receive
+// the first value.
+//
+// Browsers submit nothing for an unchecked checkbox, an empty checkbox group
+// or a multi-select with nothing selected, so bool and slice fields without
+// submitted values are cleared. Other fields without submitted values keep
+// their current value.
+//
+// Values that cannot be converted to the field type are recorded as field
+// errors and leave the field unchanged. Returns false if any value failed
+// to convert.
+func (f *Form[T]) Bind(data FormValues) bool {
+	f.mu.RLock()
+	paths := make([]string, 0, len(f.fieldMeta))
+	for path := range f.fieldMeta {
+		paths = append(paths, path)
+	}
+	meta := make(map[string]fieldMeta, len(f.fieldMeta))
+	for k, v := range f.fieldMeta {
+		meta[k] = v
+	}
+	f.mu.RUnlock()
+	sort.Strings(paths)
+
+	current := reflect.ValueOf(f.values.Peek())
+	bound := make(map[string]reflect.Value)
+	failed := make(map[string]string)
+	for _, path := range paths {
+		fieldType := meta[path].fieldType
+		if !bindable(fieldType) {
+			continue
+		}
+
+		raw := data.GetAll(path)
+		if raw == nil && fieldType.Kind() == reflect.Slice {
+			raw = data.GetAll(path + "[]")
+		}
+		if raw == nil {
+			if clearedWhenAbsent(fieldType) && !isZeroField(current, path) {
+				bound[path] = reflect.Zero(fieldType)
+			}
+			continue
+		}
+
+		value, err := convertFormValues(raw, fieldType)
+		if err != nil {
+			failed[path] = err.Error()
+			continue
+		}
+		bound[path] = value
+	}
+
+	if len(bound) > 0 {
+		f.values.Update(func(current T) T {
+			v := reflect.ValueOf(&current).Elem()
+			for path, value := range bound {
+				setFieldValue(v, path, value.Interface())
+			}
+			return current
+		})
+		f.dirty.Update(func(m map[string]bool) map[string]bool {
+			newMap := make(map[string]bool, len(m)+len(bound))
+			for k, v := range m {
+				newMap[k] = v
+			}
+			for path := range bound {
+				newMap[path] = true
+			}
+			return newMap
+		})
+	}
+
+	for _, path := range paths {
+		if msg, ok := failed[path]; ok {
+			f.SetError(path, msg)
+		}
+	}
+	return len(failed) == 0
+}
+
+// bindable reports whether a field type can be bound from form values.
+func bindable(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		return scalarKind(t.Elem().Kind())
+	}
+	return scalarKind(t.Kind())
+}
+
+// clearedWhenAbsent reports whether a field of type t is cleared when the
+// submission has no value for it.
+func clearedWhenAbsent(t reflect.Type) bool {
+	return t.Kind() == reflect.Bool || t.Kind() == reflect.Slice
+}
+
+// isZeroField reports whether the field at path in v holds its zero value.
+func isZeroField(v reflect.Value, path string) bool {
+	value := getFieldValue(v, path)
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
+
+func scalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertFormValues converts submitted strings to a value of type t.
+func convertFormValues(raw []string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() != reflect.Slice {
+		s := ""
+		if len(raw) > 0 {
+			s = raw[0]
+		}
+		return convertFormValue(s, t)
+	}
+
+	out := reflect.MakeSlice(t, 0, len(raw))
+	for _, s := range raw {
+		v, err := convertFormValue(s, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out = reflect.Append(out, v)
+	}
+	return out, nil
+}
+
+// convertFormValue converts a single submitted string to a value of type t.
+// Empty strings convert to the zero value (an empty number input submits "").
+func convertFormValue(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t.Kind() == reflect.String {
+		v.SetString(s)
+		return v, nil
+	}
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if s == "on" {
+			v.SetBool(true)
+			break
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, errors.New("must be true or false")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return v, errors.New("must be a whole number")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return v, errors.New("must be a positive whole number")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, errors.New("must be a number")
+		}
+		v.SetFloat(n)
+	}
+	return v, nil
+}
@@ -0,0 +1,124 @@
+package form
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+type TestPreferences struct {
+	Name       string      `form:"name"`
+	Tags       []string    `form:"tags"`
+	Scores     []int       `form:"scores"`
+	Age        int         `form:"age"`
+	Newsletter bool        `form:"newsletter"`
+	Ratio      float64     `form:"ratio"`
+	Address    TestAddress `form:"address"`
+	Items      []OrderItem `form:"items"`
+}
+
+func TestBindMultiValueFields(t *testing.T) {
+	form := UseForm(TestPreferences{Name: "keep", Age: 1})
+
+	ok := form.Bind(vango.NewFormData(map[string][]string{
+		"tags":         {"go", "web"},
+		"scores[]":     {"3", "5"},
+		"age":          {"42"},
+		"newsletter":   {"on"},
+		"ratio":        {"0.5"},
+		"address.city": {"Paris"},
+	}))
+	if !ok {
+		t.Fatalf("Bind() = false, errors = %v", form.Errors())
+	}
+
+	got := form.Values()
+	if !reflect.DeepEqual(got.Tags, []string{"go", "web"}) {
+		t.Errorf("Tags = %v, want [go web]", got.Tags)
+	}
+	if !reflect.DeepEqual(got.Scores, []int{3, 5}) {
+		t.Errorf("Scores = %v, want [3 5]", got.Scores)
+	}
+	if got.Age != 42 || !got.Newsletter || got.Ratio != 0.5 {
+		t.Errorf("scalars = %d/%v/%v, want 42/true/0.5", got.Age, got.Newsletter, got.Ratio)
+	}
+	if got.Address.City != "Paris" {
+		t.Errorf("Address.City = %q, want Paris", got.Address.City)
+	}
+	if got.Name != "keep" {
+		t.Errorf("Name = %q, fields without values should be unchanged", got.Name)
+	}
+	if !form.FieldDirty("tags") || form.FieldDirty("name") {
+		t.Error("only bound fields should be marked dirty")
+	}
+}
+
+func TestBindConversionErrors(t *testing.T) {
+	form := UseForm(TestPreferences{Age: 7})
+
+	ok := form.Bind(vango.NewFormData(map[string][]string{
+		"age":    {"old"},
+		"scores": {"1", "x"},
+		"tags":   {"a"},
+	}))
+	if ok {
+		t.Fatal("Bind() = true, want false for invalid values")
+	}
+	if !form.HasError("age") || !form.HasError("scores") {
+		t.Errorf("errors = %v, want age and scores errors", form.Errors())
+	}
+
+	got := form.Values()
+	if got.Age != 7 || got.Scores != nil {
+		t.Errorf("invalid fields should be unchanged, got age=%d scores=%v", got.Age, got.Scores)
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"a"}) {
+		t.Errorf("valid fields should still bind, got tags=%v", got.Tags)
+	}
+}
+
+func TestBindEmptyNumberIsZero(t *testing.T) {
+	form := UseForm(TestPreferences{Age: 7})
+
+	if !form.Bind(vango.NewFormData(map[string][]string{"age": {""}})) {
+		t.Fatalf("Bind() = false, errors = %v", form.Errors())
+	}
+	if form.Values().Age != 0 {
+		t.Errorf("Age = %d, want 0 for empty input", form.Values().Age)
+	}
+}
+
+func TestBindClearsUncheckedAndDeselectedFields(t *testing.T) {
+	form := UseForm(TestPreferences{
+		Name:       "keep",
+		Tags:       []string{"go", "web"},
+		Scores:     []int{3},
+		Newsletter: true,
+	})
+
+	// Unchecking every box and deselecting every option submits no values
+	// for those fields at all.
+	if !form.Bind(vango.NewFormData(map[string][]string{"age": {"42"}})) {
+		t.Fatalf("Bind() = false, errors = %v", form.Errors())
+	}
+
+	got := form.Values()
+	if got.Newsletter {
+		t.Error("Newsletter = true, want false after unchecking")
+	}
+	if len(got.Tags) != 0 || len(got.Scores) != 0 {
+		t.Errorf("Tags = %v, Scores = %v, want both empty after deselecting all", got.Tags, got.Scores)
+	}
+	if got.Name != "keep" || got.Age != 42 {
+		t.Errorf("Name = %q, Age = %d, want keep and 42", got.Name, got.Age)
+	}
+	for _, path := range []string{"newsletter", "tags", "scores"} {
+		if !form.FieldDirty(path) {
+			t.Errorf("%s should be dirty after clearing", path)
+		}
+	}
+	if form.FieldDirty("ratio") || form.FieldDirty("name") {
+		t.Error("fields that were already zero or not submitted should not be dirty")
+	}
+}
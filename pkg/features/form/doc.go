@@ -46,6 +46,26 @@
 //   - Min/Max: Numeric range constraints
 //   - Custom: User-defined validation logic
 //
+// # Binding Submitted Data
+//
+// Bind copies a submit event's values into the struct. Slice fields receive
+// every value of a multi-select, checkbox group or repeated "tags[]" input:
+//
+//	type Filters struct {
+//	    Colors []string `form:"colors"`
+//	    Sizes  []int    `form:"sizes"`
+//	}
+//
+//	Form(OnSubmit(func(data vango.FormData) {
+//	    if form.Bind(data) && form.Validate() {
+//	        apply(form.Values())
+//	    }
+//	}), ...)
+//
+// Bool and slice fields with no submitted value are cleared, since that is
+// how an unchecked checkbox or an empty multi-select arrives. Bind the
+// struct only from a form that renders all of its bool and slice fields.
+//
 // # Form Arrays
 //
 // For forms with dynamic arrays of nested objects, use the Array method:
@@ -0,0 +1,150 @@
+package islands
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+// BridgeKey is the context key for the island bridge.
+// The session sets this on the root owner so islands can send and receive
+// messages without direct access to the session.
+var BridgeKey = &struct{ name string }{"IslandBridge"}
+
+// ErrNoBridge is returned when an island message is sent outside of a
+// session (for example during SSR or in a detached goroutine).
+var ErrNoBridge = errors.New("islands: no island bridge in context")
+
+// Bridge routes messages between the server and the JS islands of one session.
+//
+// Outgoing messages are queued as IslandMessage patches and sent to the
+// client with the next render frame, after any DOM patches that mount the
+// island. Incoming messages arrive as Island events and are delivered to the
+// handler registered for the island ID on the session loop.
+type Bridge struct {
+	queuePatch func(protocol.Patch)
+
+	mu       sync.Mutex
+	handlers map[string]bridgeHandler
+	nextID   uint64
+}
+
+type bridgeHandler struct {
+	id uint64
+	fn func(map[string]any)
+}
+
+// NewBridge creates a bridge that queues outgoing patches via queuePatch.
+// The session passes in a closure that appends to its pending patch buffer.
+func NewBridge(queuePatch func(protocol.Patch)) *Bridge {
+	return &Bridge{
+		queuePatch: queuePatch,
+		handlers:   make(map[string]bridgeHandler),
+	}
+}
+
+// Send queues a message for the island with the given ID.
+//
+// Message values are normalized to the types supported by the wire format
+// (nil, bool, int64, float64, string, []any and map[string]any); other values
+// are converted through encoding/json. The message must satisfy the protocol
+// depth and frame size limits.
+func (b *Bridge) Send(id string, message map[string]any) error {
+	if b == nil || b.queuePatch == nil {
+		return ErrNoBridge
+	}
+	data, err := normalizeMessage(message)
+	if err != nil {
+		return err
+	}
+	if err := protocol.ValidateHookData(data); err != nil {
+		return err
+	}
+	b.queuePatch(protocol.NewIslandMessagePatch(id, data))
+	return nil
+}
+
+// Handle registers fn for messages from the island with the given ID,
+// replacing any previous handler. The returned function unregisters fn;
+// it is a no-op if fn has since been replaced.
+func (b *Bridge) Handle(id string, fn func(map[string]any)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	token := b.nextID
+	b.handlers[id] = bridgeHandler{id: token, fn: fn}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if h, ok := b.handlers[id]; ok && h.id == token {
+			delete(b.handlers, id)
+		}
+	}
+}
+
+// Deliver calls the handler registered for the island with the given ID.
+// It must be called on the session loop. Returns false if no handler is
+// registered.
+func (b *Bridge) Deliver(id string, message map[string]any) bool {
+	b.mu.Lock()
+	h, ok := b.handlers[id]
+	b.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if message == nil {
+		message = map[string]any{}
+	}
+	h.fn(message)
+	return true
+}
+
+// normalizeMessage converts message values to wire-supported types.
+func normalizeMessage(message map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(message))
+	for k, v := range message {
+		nv, err := normalizeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = nv
+	}
+	return out, nil
+}
+
+func normalizeValue(v any) (any, error) {
+	switch val := v.(type) {
+	case nil, bool, int64, float64, string:
+		return val, nil
+	case int:
+		return int64(val), nil
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			nv, err := normalizeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = nv
+		}
+		return out, nil
+	case map[string]any:
+		return normalizeMessage(val)
+	}
+
+	// Structs, typed slices and maps, other numeric types: go through JSON,
+	// which only produces supported types.
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -6,4 +6,22 @@
 // Usage:
 //
 //	JSIsland("my-chart", "/js/chart.js", JSProps{"data": [...]})
+//
+// Messaging:
+//
+// Components exchange messages with a mounted island by ID. Outgoing messages
+// are sent as IslandMessage patches after the frame's DOM patches; incoming
+// messages arrive as Island events and run on the session loop.
+//
+//	islands.OnIslandMessage("my-chart", func(msg map[string]any) {
+//	    selected.Set(msg["point"])
+//	})
+//	islands.SendToIsland("my-chart", map[string]any{"highlight": 3})
+//
+// On the client, the island module receives a handle in mount():
+//
+//	export function mount(el, props, island) {
+//	    island.onMessage(msg => chart.highlight(msg.highlight));
+//	    chart.on('click', p => island.send({ point: p.index }));
+//	}
 package islands
@@ -33,32 +33,73 @@ func JSIsland(id string, module JSModule, props JSProps) *vdom.VNode {
 	}
 }
 
-// SendToIsland sends a message to the client-side island.
-func SendToIsland(id string, message map[string]any) {
-	// In a real implementation, this would queue a message to the active session.
-	// For now, we mock it or require session context.
-	// TODO: Integrate with Session.Send(id, message)
+// SendToIsland sends a message to the client-side island with the given ID.
+//
+// It must be called on the session loop: during render, in an effect or
+// event handler, or inside ctx.Dispatch. The message is delivered to the
+// island after the DOM patches of the current tick are applied, so it is safe
+// to send data to an island in the same tick it is mounted.
+//
+// Returns ErrNoBridge outside of a session, or a protocol error if the
+// message exceeds the depth or frame size limits.
+func SendToIsland(id string, message map[string]any) error {
+	return currentBridge().Send(id, message)
 }
 
-// OnIslandMessage registers a handler for messages from the island.
+// OnIslandMessage registers a handler for messages sent by the island with
+// the given ID. The island sends messages with island.send(data) on the client.
+//
+// The handler runs on the session loop, so it may update signals directly.
+// It is registered after the component mounts and removed when it unmounts.
+//
+// This is a hook-like API and MUST be called unconditionally during render.
 func OnIslandMessage(id string, handler func(map[string]any)) {
-	// This registers an effect that listens for events.
-	// In Vango, interacting with outside inputs typically happens via Signals or specialized listeners.
-	// The runtime would route events to this handler.
-
-	// We use CreateEffect to ensure this runs in the right scope?
-	// Actually, handlers are usually static.
-	// But if the handler closes over component state (Signal), it's fine.
-
-	// We need to register this with the Session so incoming WebSocket messages
-	// for this Island ID are routed here.
+	// Keep a stable listener across renders so the latest handler closure
+	// is always used without re-registering.
+	slot := vango.UseHookSlot()
+	var l *islandListener
+	if slot != nil {
+		existing, ok := slot.(*islandListener)
+		if !ok {
+			panic("vango: hook slot type mismatch for OnIslandMessage")
+		}
+		l = existing
+	} else {
+		l = &islandListener{}
+		vango.SetHookSlot(l)
+	}
+	l.handler = handler
 
+	bridge := currentBridge()
 	vango.CreateEffect(func() vango.Cleanup {
-		// Registration logic
-		// session := vango.GetContext(SessionKey)?
-
-		return func() {
-			// Cleanup/Unregister logic
+		if bridge == nil {
+			return nil
 		}
+		return bridge.Handle(id, func(message map[string]any) {
+			l.handler(message)
+		})
 	})
 }
+
+// islandListener holds the latest handler passed to OnIslandMessage.
+type islandListener struct {
+	handler func(map[string]any)
+}
+
+// bridgeProvider is implemented by runtime contexts that expose the
+// session's island bridge.
+type bridgeProvider interface {
+	IslandBridge() *Bridge
+}
+
+// currentBridge returns the island bridge of the current session, or nil.
+func currentBridge() *Bridge {
+	if b, ok := vango.GetContext(BridgeKey).(*Bridge); ok {
+		return b
+	}
+	// Dispatch callbacks run without an owner; fall back to the context.
+	if p, ok := vango.UseCtx().(bridgeProvider); ok {
+		return p.IslandBridge()
+	}
+	return nil
+}
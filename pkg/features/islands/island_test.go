@@ -2,7 +2,11 @@ package islands
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
 )
 
 func TestJSIsland(t *testing.T) {
@@ -35,3 +39,114 @@ func TestPlaceholders(t *testing.T) {
 	SendToIsland("id", nil)
 	OnIslandMessage("id", func(m map[string]any) {})
 }
+
+func TestBridgeSendQueuesNormalizedPatch(t *testing.T) {
+	var queued []protocol.Patch
+	b := NewBridge(func(p protocol.Patch) { queued = append(queued, p) })
+
+	type point struct {
+		X int `json:"x"`
+	}
+	err := b.Send("chart", map[string]any{
+		"count":  3,
+		"points": []point{{X: 1}, {X: 2}},
+		"label":  "Q3",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(queued) != 1 {
+		t.Fatalf("queued %d patches, want 1", len(queued))
+	}
+	p := queued[0]
+	if p.Op != protocol.PatchIslandMessage || p.HID != "chart" {
+		t.Fatalf("patch = %+v, want IslandMessage for chart", p)
+	}
+	if p.Data["count"] != int64(3) {
+		t.Errorf("count = %#v, want int64(3)", p.Data["count"])
+	}
+	points, ok := p.Data["points"].([]any)
+	if !ok || len(points) != 2 {
+		t.Fatalf("points = %#v, want 2 normalized items", p.Data["points"])
+	}
+	if first, ok := points[0].(map[string]any); !ok || first["x"] != float64(1) {
+		t.Errorf("points[0] = %#v, want map with x=1", points[0])
+	}
+}
+
+func TestBridgeSendRespectsLimits(t *testing.T) {
+	b := NewBridge(func(protocol.Patch) { t.Error("oversized message should not be queued") })
+
+	var deep any = "leaf"
+	for i := 0; i <= protocol.MaxHookDepth; i++ {
+		deep = []any{deep}
+	}
+	if err := b.Send("chart", map[string]any{"v": deep}); err != protocol.ErrMaxDepthExceeded {
+		t.Errorf("deep message error = %v, want %v", err, protocol.ErrMaxDepthExceeded)
+	}
+	big := strings.Repeat("x", protocol.MaxPayloadSize)
+	if err := b.Send("chart", map[string]any{"v": big}); err != protocol.ErrFrameTooLarge {
+		t.Errorf("large message error = %v, want %v", err, protocol.ErrFrameTooLarge)
+	}
+}
+
+func TestBridgeHandleAndDeliver(t *testing.T) {
+	b := NewBridge(nil)
+
+	var got []map[string]any
+	unregister := b.Handle("map", func(m map[string]any) { got = append(got, m) })
+
+	if !b.Deliver("map", map[string]any{"zoom": int64(4)}) {
+		t.Fatal("Deliver() = false, want true")
+	}
+	if len(got) != 1 || got[0]["zoom"] != int64(4) {
+		t.Fatalf("handler got %#v", got)
+	}
+	if b.Deliver("other", nil) {
+		t.Error("Deliver() to unknown island = true, want false")
+	}
+
+	// A newer registration is not removed by the stale unregister func.
+	var replaced bool
+	b.Handle("map", func(map[string]any) { replaced = true })
+	unregister()
+	if !b.Deliver("map", nil) || !replaced {
+		t.Error("replacement handler should survive stale unregister")
+	}
+}
+
+func TestOnIslandMessageRegistersWithBridge(t *testing.T) {
+	var queued int
+	b := NewBridge(func(protocol.Patch) { queued++ })
+	root := vango.NewOwner(nil)
+	root.SetValue(BridgeKey, b)
+
+	var got map[string]any
+	vango.WithOwner(root, func() {
+		OnIslandMessage("chart", func(m map[string]any) { got = m })
+		if err := SendToIsland("chart", nil); err != nil {
+			t.Errorf("SendToIsland() error = %v", err)
+		}
+	})
+	if queued != 1 {
+		t.Errorf("queued %d patches, want 1", queued)
+	}
+
+	if !b.Deliver("chart", map[string]any{"clicked": true}) {
+		t.Fatal("OnIslandMessage did not register a handler")
+	}
+	if got["clicked"] != true {
+		t.Errorf("handler got %#v", got)
+	}
+
+	root.Dispose()
+	if b.Deliver("chart", nil) {
+		t.Error("handler should be unregistered when the owner is disposed")
+	}
+}
+
+func TestSendToIslandWithoutSession(t *testing.T) {
+	if err := SendToIsland("chart", map[string]any{"a": 1}); err != ErrNoBridge {
+		t.Errorf("SendToIsland() error = %v, want %v", err, ErrNoBridge)
+	}
+}
@@ -20,4 +20,15 @@
 //	    resource.OnError(func(err error) *vdom.VNode { return Error(err) }),
 //	    resource.OnReady(func(u *User) *vdom.VNode { return UserProfile(u) }),
 //	)
+//
+// Suspense:
+//
+// Suspense shows a fallback until the resources read by its children settle.
+// With render.StreamingRenderer the fallback is streamed immediately and the
+// resolved children are streamed in later, so a slow resource does not delay
+// the rest of the page:
+//
+//	resource.Suspense(Spinner(), func() *vdom.VNode {
+//	    return user.Match(resource.OnReady(func(u *User) *vdom.VNode { return UserProfile(u) }))
+//	})
 package resource
@@ -41,7 +41,8 @@ type Resource[T any] struct {
 
 	// Internal
 	lastFetch time.Time
-	fetchID   uint64 // For cancelling/ignoring outdated fetches
+	fetchID   uint64        // For cancelling/ignoring outdated fetches
+	settledCh chan struct{} // Closed when the in-flight fetch completes (see settled)
 	mu        sync.Mutex
 }
 
@@ -146,21 +147,27 @@ func NewWithKey[K comparable, T any](key func() K, fetcher func(K) (T, error)) *
 
 // State methods
 
+// State returns the current state. Reading a Pending or Loading resource
+// inside a Suspense boundary suspends the boundary.
 func (r *Resource[T]) State() State {
-	return r.state.Get()
+	s := r.state.Get()
+	if s == Pending || s == Loading {
+		suspendOn(r.settled)
+	}
+	return s
 }
 
 func (r *Resource[T]) IsLoading() bool {
-	s := r.state.Get()
+	s := r.State()
 	return s == Loading || s == Pending
 }
 
 func (r *Resource[T]) IsReady() bool {
-	return r.state.Get() == Ready
+	return r.State() == Ready
 }
 
 func (r *Resource[T]) IsError() bool {
-	return r.state.Get() == Error
+	return r.State() == Error
 }
 
 // Data access methods
@@ -207,6 +214,7 @@ func (r *Resource[T]) Refetch() {
 					if r.onError != nil {
 						r.onError(vango.ErrBudgetExceeded)
 					}
+					r.markSettled()
 				})
 				return
 			}
@@ -282,13 +290,44 @@ func (r *Resource[T]) Refetch() {
 		}
 
 		if r.ctx != nil {
-			r.ctx.Dispatch(updateSignals)
+			r.ctx.Dispatch(func() {
+				updateSignals()
+				r.markSettled()
+			})
 		} else {
 			updateSignals()
+			r.markSettled()
 		}
 	}()
 }
 
+// settled returns a channel that is closed when the resource leaves the
+// Pending/Loading states. The channel is already closed if it has.
+func (r *Resource[T]) settled() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.state.Peek(); s != Pending && s != Loading {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	if r.settledCh == nil {
+		r.settledCh = make(chan struct{})
+	}
+	return r.settledCh
+}
+
+// markSettled wakes up waiters on settled. It runs after the state signal
+// has been set, so a waiter that still saw Loading is always woken.
+func (r *Resource[T]) markSettled() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.settledCh != nil {
+		close(r.settledCh)
+		r.settledCh = nil
+	}
+}
+
 // Invalidate marks the current data as stale.
 func (r *Resource[T]) Invalidate() {
 	r.mu.Lock()
@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"sync"
+
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// suspenseKey is the context key for the boundary whose children are
+// currently rendering.
+var suspenseKey = &struct{ name string }{"Suspense"}
+
+// Suspense renders fallback while any resource read by children is Pending or
+// Loading, and the output of children once they have all settled.
+//
+// Resources are usually created in the enclosing component and read inside
+// children, typically through Match:
+//
+//	user := resource.New(loadUser)
+//
+//	return Div(
+//	    Header(),
+//	    resource.Suspense(Spinner(), func() *vdom.VNode {
+//	        return user.Match(
+//	            resource.OnError(func(err error) *vdom.VNode { return ErrorBox(err) }),
+//	            resource.OnReady(func(u *User) *vdom.VNode { return Profile(u) }),
+//	        )
+//	    }),
+//	)
+//
+// With render.StreamingRenderer the rest of the page is not held up: the
+// fallback is streamed immediately and the resolved children are streamed
+// into place when the fetch completes. In a live session the boundary
+// re-renders when the resources settle, like any other component.
+//
+// Only resources read while children runs suspend the boundary; reads inside
+// nested components rendered later do not.
+func Suspense(fallback *vdom.VNode, children func() *vdom.VNode) *vdom.VNode {
+	return &vdom.VNode{
+		Kind: vdom.KindComponent,
+		Comp: &suspense{fallback: fallback, children: children},
+	}
+}
+
+// suspense is the boundary component created by Suspense.
+// It implements render.Suspender.
+type suspense struct {
+	fallback *vdom.VNode
+	children func() *vdom.VNode
+
+	// scope hosts the children's hooks when rendered outside a component
+	// scope (server-side rendering), so they survive repeated Suspend calls.
+	scope *vango.Owner
+}
+
+// waitSet collects the settled channels of resources read while loading.
+type waitSet struct {
+	mu    sync.Mutex
+	waits []<-chan struct{}
+}
+
+// suspendOn registers a loading resource with the boundary whose children are
+// rendering, if any.
+func suspendOn(settled func() <-chan struct{}) {
+	ws, ok := vango.GetContext(suspenseKey).(*waitSet)
+	if !ok {
+		return
+	}
+	ch := settled()
+	ws.mu.Lock()
+	ws.waits = append(ws.waits, ch)
+	ws.mu.Unlock()
+}
+
+// Render implements vdom.Component.
+func (s *suspense) Render() *vdom.VNode {
+	node, waits := s.renderChildren()
+	if len(waits) > 0 {
+		return s.fallback
+	}
+	return node
+}
+
+// Suspend implements render.Suspender.
+func (s *suspense) Suspend() (*vdom.VNode, <-chan struct{}) {
+	node, waits := s.renderChildren()
+	if len(waits) == 0 {
+		return node, nil
+	}
+	return s.fallback, firstClosed(waits)
+}
+
+// renderChildren runs children with a waitSet in context and returns their
+// output together with the resources they were waiting on.
+func (s *suspense) renderChildren() (*vdom.VNode, []<-chan struct{}) {
+	ws := &waitSet{}
+	var node *vdom.VNode
+
+	prev := vango.GetContext(suspenseKey)
+	vango.SetContext(suspenseKey, ws)
+	if vango.GetContext(suspenseKey) == ws {
+		// Inside a component scope (the boundary's own owner in a session).
+		node = s.children()
+		vango.SetContext(suspenseKey, prev)
+	} else {
+		if s.scope == nil {
+			s.scope = vango.NewOwner(nil)
+		}
+		s.scope.SetValue(suspenseKey, ws)
+		vango.WithOwner(s.scope, func() {
+			s.scope.StartRender()
+			defer s.scope.EndRender()
+			node = s.children()
+		})
+		s.scope.RunPendingEffects(nil)
+		s.scope.SetValue(suspenseKey, nil)
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return node, ws.waits
+}
+
+// firstClosed returns a channel that is closed when any of chans is closed.
+func firstClosed(chans []<-chan struct{}) <-chan struct{} {
+	if len(chans) == 1 {
+		return chans[0]
+	}
+	out := make(chan struct{})
+	var once sync.Once
+	for _, ch := range chans {
+		go func(ch <-chan struct{}) {
+			select {
+			case <-ch:
+				once.Do(func() { close(out) })
+			case <-out:
+			}
+		}(ch)
+	}
+	return out
+}
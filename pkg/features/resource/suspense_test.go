@@ -0,0 +1,121 @@
+package resource
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/render"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestSuspenseSuspendsUntilResourceSettles(t *testing.T) {
+	release := make(chan struct{})
+	r := newTestResource(t, func() *Resource[string] {
+		return New(func() (string, error) {
+			<-release
+			return "Alice", nil
+		})
+	})
+
+	node := Suspense(vdom.Text("Loading"), func() *vdom.VNode {
+		return r.Match(
+			OnReady(func(name string) *vdom.VNode { return vdom.Span(vdom.Text(name)) }),
+		)
+	})
+	boundary, ok := node.Comp.(render.Suspender)
+	if !ok {
+		t.Fatal("Suspense should implement render.Suspender")
+	}
+
+	got, ready := boundary.Suspend()
+	if ready == nil || got.Text != "Loading" {
+		t.Fatalf("Suspend() = %v, %v; want fallback and a ready channel", got, ready)
+	}
+
+	close(release)
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		t.Fatal("ready channel was not closed after the fetch completed")
+	}
+
+	got, ready = boundary.Suspend()
+	if ready != nil {
+		t.Fatal("boundary should no longer be suspended")
+	}
+	if got.Tag != "span" || got.Children[0].Text != "Alice" {
+		t.Errorf("Suspend() = %+v, want resolved content", got)
+	}
+}
+
+func TestSuspenseRenderUsesFallbackWhileLoading(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	r := newTestResource(t, func() *Resource[int] {
+		return New(func() (int, error) {
+			<-release
+			return 1, nil
+		})
+	})
+
+	node := Suspense(vdom.Text("Loading"), func() *vdom.VNode {
+		return vdom.Textf("%d", r.DataOr(0))
+	})
+	if got := node.Comp.Render(); got.Text != "Loading" {
+		t.Errorf("Render() = %q, want fallback", got.Text)
+	}
+}
+
+func TestSuspenseStreamsResolvedResource(t *testing.T) {
+	release := make(chan struct{})
+	var r *Resource[[]string]
+
+	page := func() *vdom.VNode {
+		// Created during SSR, outside any component scope.
+		r = New(func() ([]string, error) {
+			<-release
+			return []string{"Alice", "Bob"}, nil
+		})
+		return vdom.Div(
+			vdom.H1(vdom.Text("Users")),
+			Suspense(vdom.P(vdom.Text("Loading...")), func() *vdom.VNode {
+				return r.Match(
+					OnLoadingOrPending[[]string](func() *vdom.VNode { return vdom.P(vdom.Text("Still loading")) }),
+					OnReady(func(names []string) *vdom.VNode {
+						items := make([]any, len(names))
+						for i, n := range names {
+							items[i] = vdom.Li(vdom.Text(n))
+						}
+						return vdom.Ul(items...)
+					}),
+				)
+			}),
+		)
+	}
+
+	w := httptest.NewRecorder()
+	sr := render.NewStreamingRenderer(w, render.RendererConfig{SuspenseTimeout: 5 * time.Second})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	if err := sr.RenderPage(render.PageData{Body: page()}); err != nil {
+		t.Fatal(err)
+	}
+
+	html := w.Body.String()
+	fallback := strings.Index(html, "Loading...")
+	resolved := strings.Index(html, `<template id="vango-s:1"><ul data-hid=`)
+	if fallback < 0 || resolved < 0 || resolved < fallback {
+		t.Fatalf("expected fallback followed by streamed content, got %q", html)
+	}
+	if !strings.Contains(html, ">Bob</li>") {
+		t.Errorf("resolved content missing, got %q", html)
+	}
+	if strings.Contains(html, "Still loading") {
+		t.Errorf("Match loading handler should be replaced by the boundary fallback, got %q", html)
+	}
+}
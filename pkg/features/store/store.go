@@ -51,6 +51,14 @@ func (s *SessionStore) GetOrCreateSignal(id uint64, createFn func() any) any {
 	return actual
 }
 
+// Range calls fn for each signal in the store until fn returns false.
+// The reactive graph inspector uses it to list a session's shared signals.
+func (s *SessionStore) Range(fn func(id uint64, signal any) bool) {
+	s.signals.Range(func(k, v any) bool {
+		return fn(k.(uint64), v)
+	})
+}
+
 // NewGlobalSignal creates a signal shared across all sessions.
 //
 // Deprecated: Use vango.NewGlobalSignal instead.
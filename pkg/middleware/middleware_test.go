@@ -101,6 +101,13 @@ func (m *mockCtx) Navigate(path string, opts ...server.NavigateOption) {}
 func (m *mockCtx) StormBudget() vango.StormBudgetChecker               { return nil }
 func (m *mockCtx) Mode() int                                           { return 0 } // ModeNormal
 func (m *mockCtx) Asset(source string) string                          { return source }
+func (m *mockCtx) Client() *server.Client                              { return nil }
+func (m *mockCtx) CallHook(hid, method string, args map[string]any) (any, error) {
+	return nil, server.ErrNoConnection
+}
+func (m *mockCtx) CallHookAsync(hid, method string, args map[string]any, fn func(any, error)) {
+	fn(nil, server.ErrNoConnection)
+}
 
 // =============================================================================
 // OpenTelemetry Tests
@@ -0,0 +1,354 @@
+package pref
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// ManagerKey is the context key for the preference manager.
+// The session sets this on the root owner so Pref methods resolve to the
+// session's values.
+var ManagerKey = &struct{ name string }{"PrefManager"}
+
+// Syncer carries preference changes out of a session. The server implements
+// it; a Manager calls it on the session loop.
+type Syncer interface {
+	// SaveLocal sends values to the client, which keeps them in localStorage
+	// and shares them with its other tabs.
+	SaveLocal(entries []Entry)
+
+	// SaveRemote saves a value to the user's Store and broadcasts it to the
+	// user's other sessions.
+	SaveRemote(userID string, e Entry)
+
+	// LoadRemote loads the preferences stored for userID and passes them to
+	// Manager.Login on the session loop.
+	LoadRemote(userID string)
+}
+
+// Manager holds the preference values of one session.
+//
+// Values come from the client's localStorage at handshake, from the user's
+// Store when they log in, and from the user's other sessions. Pref reads
+// subscribe the current listener like signal reads, so components re-render
+// when a value changes.
+//
+// All methods except Lookup must be called on the session loop.
+type Manager struct {
+	syncer Syncer
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	userID  string
+}
+
+// entry is the session state of one preference.
+type entry struct {
+	raw       json.RawMessage // nil until a value is known
+	updatedAt time.Time
+	value     any    // raw decoded by the last Pref.Get
+	conflict  *Entry // stored value awaiting Resolve
+	rev       *vango.Signal[uint64]
+}
+
+// NewManager creates a manager that propagates changes through syncer.
+func NewManager(syncer Syncer) *Manager {
+	return &Manager{
+		syncer:  syncer,
+		entries: make(map[string]*entry),
+	}
+}
+
+// currentManager returns the manager of the current session, or nil.
+func currentManager() *Manager {
+	m, _ := vango.GetContext(ManagerKey).(*Manager)
+	return m
+}
+
+// UserID returns the user the session's preferences are synced for, or ""
+// before login.
+func (m *Manager) UserID() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.userID
+}
+
+// Lookup returns the current value of key, if one is known.
+func (m *Manager) Lookup(key string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.entries[key]
+	if e == nil || e.raw == nil {
+		return Entry{}, false
+	}
+	return Entry{Key: key, Value: e.raw, UpdatedAt: e.updatedAt}, true
+}
+
+// entryLocked returns the entry for key, creating it if needed.
+// Caller must hold m.mu.
+func (m *Manager) entryLocked(key string) *entry {
+	e := m.entries[key]
+	if e == nil {
+		e = &entry{}
+		// Not a hook: the signal belongs to the session, not to the
+		// component whose render first reads the preference.
+		vango.WithOwner(nil, func() {
+			e.rev = vango.NewSignal[uint64](0)
+		})
+		m.entries[key] = e
+	}
+	return e
+}
+
+// changed notifies the readers of the given entries. It must be called
+// without holding m.mu.
+func changed(entries ...*entry) {
+	for _, e := range entries {
+		e.rev.Update(func(n uint64) uint64 { return n + 1 })
+	}
+}
+
+// get returns the raw value of key and the value cached by the last get,
+// subscribing the current listener to changes.
+func (m *Manager) get(key string) (raw json.RawMessage, cached any) {
+	m.mu.Lock()
+	e := m.entryLocked(key)
+	raw, cached = e.raw, e.value
+	m.mu.Unlock()
+
+	e.rev.Get()
+	return raw, cached
+}
+
+// cache stores the decoded form of raw for key, if raw is still current.
+func (m *Manager) cache(key string, raw json.RawMessage, value any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.entries[key]; e != nil && bytes.Equal(e.raw, raw) {
+		e.value = value
+	}
+}
+
+// set stores a value written in this session and propagates it.
+func (m *Manager) set(key string, raw json.RawMessage, value any, cfg prefConfig) {
+	m.mu.Lock()
+	e := m.entryLocked(key)
+	if bytes.Equal(e.raw, raw) && e.conflict == nil {
+		m.mu.Unlock()
+		return
+	}
+	e.raw, e.value, e.conflict = raw, value, nil
+	e.updatedAt = time.Now()
+	out := Entry{Key: key, Value: raw, UpdatedAt: e.updatedAt}
+	userID := m.userID
+	m.mu.Unlock()
+
+	changed(e)
+	if cfg.persistLocal {
+		m.syncer.SaveLocal([]Entry{out})
+	}
+	if userID != "" && cfg.syncToServer {
+		m.syncer.SaveRemote(userID, out)
+	}
+}
+
+// applyNewer stores v if it is newer than the current value.
+// Caller must hold m.mu.
+func (m *Manager) applyNewerLocked(v Entry) (*entry, bool) {
+	e := m.entryLocked(v.Key)
+	if e.raw != nil && (!v.UpdatedAt.After(e.updatedAt) || bytes.Equal(e.raw, v.Value)) {
+		if v.UpdatedAt.After(e.updatedAt) {
+			e.updatedAt = v.UpdatedAt
+		}
+		return e, false
+	}
+	e.raw, e.value = compact(v.Value), nil
+	e.updatedAt = v.UpdatedAt
+	return e, true
+}
+
+// ApplyLocal applies values reported by the client: the contents of its
+// localStorage at handshake, or a change made in one of its tabs. Newer
+// values replace the session's. Changes to synced preferences are saved for
+// the logged-in user.
+func (m *Manager) ApplyLocal(values []Entry) {
+	m.mu.Lock()
+	var updated []*entry
+	var save []Entry
+	for _, v := range values {
+		if v.Key == "" || !json.Valid(v.Value) {
+			continue
+		}
+		e, ok := m.applyNewerLocked(v)
+		if !ok {
+			continue
+		}
+		updated = append(updated, e)
+		if m.userID != "" && configFor(v.Key).syncToServer {
+			save = append(save, Entry{Key: v.Key, Value: e.raw, UpdatedAt: e.updatedAt})
+		}
+	}
+	userID := m.userID
+	m.mu.Unlock()
+
+	changed(updated...)
+	for _, v := range save {
+		m.syncer.SaveRemote(userID, v)
+	}
+}
+
+// ApplyRemote applies a value written in another session of the same user.
+// A newer value replaces the session's and is sent to the client.
+func (m *Manager) ApplyRemote(v Entry) {
+	if v.Key == "" || !json.Valid(v.Value) {
+		return
+	}
+	m.mu.Lock()
+	e, ok := m.applyNewerLocked(v)
+	out := Entry{Key: v.Key, Value: e.raw, UpdatedAt: e.updatedAt}
+	m.mu.Unlock()
+
+	if ok {
+		changed(e)
+		if configFor(v.Key).persistLocal {
+			m.syncer.SaveLocal([]Entry{out})
+		}
+	}
+}
+
+// Login records userID as the session's user and merges the preferences
+// stored for them with the session's values, using each preference's merge
+// strategy. Values kept from the session are saved to the store; values
+// taken from the store are sent to the client. With the Prompt strategy
+// differing values are kept as a conflict for Pref.Resolve.
+//
+// Preferences created with LocalOnly are not merged.
+func (m *Manager) Login(userID string, stored []Entry) {
+	m.mu.Lock()
+	m.userID = userID
+
+	remote := make(map[string]Entry, len(stored))
+	for _, v := range stored {
+		if json.Valid(v.Value) {
+			remote[v.Key] = v
+		}
+	}
+
+	var updated []*entry
+	var toClient, toStore []Entry
+	for key, e := range m.entries {
+		if e.raw == nil {
+			continue
+		}
+		if _, ok := remote[key]; !ok && configFor(key).syncToServer {
+			toStore = append(toStore, Entry{Key: key, Value: e.raw, UpdatedAt: e.updatedAt})
+		}
+	}
+	for key, r := range remote {
+		cfg, def := lookupDefinition(key)
+		if !cfg.syncToServer {
+			continue
+		}
+		e := m.entryLocked(key)
+		local := Entry{Key: key, Value: e.raw, UpdatedAt: e.updatedAt}
+		if e.raw != nil && bytes.Equal(e.raw, compact(r.Value)) {
+			if r.UpdatedAt.After(e.updatedAt) {
+				e.updatedAt = r.UpdatedAt
+			}
+			continue
+		}
+
+		switch resolveEntries(cfg, def, local, r) {
+		case keepLocal:
+			toStore = append(toStore, local)
+		case takeRemote:
+			e.raw, e.value, e.updatedAt = compact(r.Value), nil, r.UpdatedAt
+			toClient = append(toClient, Entry{Key: key, Value: e.raw, UpdatedAt: e.updatedAt})
+			updated = append(updated, e)
+		case prompt:
+			conflict := r
+			e.conflict = &conflict
+			updated = append(updated, e)
+		case merged:
+			raw, err := def.mergeRaw(e.raw, r.Value)
+			if err != nil {
+				continue
+			}
+			e.raw, e.value, e.updatedAt = compact(raw), nil, time.Now()
+			v := Entry{Key: key, Value: e.raw, UpdatedAt: e.updatedAt}
+			toClient = append(toClient, v)
+			toStore = append(toStore, v)
+			updated = append(updated, e)
+		}
+	}
+	m.mu.Unlock()
+
+	changed(updated...)
+	if len(toClient) > 0 {
+		m.syncer.SaveLocal(toClient)
+	}
+	for _, v := range toStore {
+		m.syncer.SaveRemote(userID, v)
+	}
+}
+
+// Logout stops syncing the session's preferences for its user. The values
+// stay as they are, as does the client's copy.
+func (m *Manager) Logout() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.userID = ""
+}
+
+// conflict returns the stored value awaiting Resolve for key.
+func (m *Manager) conflict(key string) (*Entry, bool) {
+	m.mu.Lock()
+	e := m.entryLocked(key)
+	c := e.conflict
+	m.mu.Unlock()
+
+	e.rev.Get()
+	return c, c != nil
+}
+
+// resolve settles a conflict for key, taking the stored value or keeping
+// the session's.
+func (m *Manager) resolve(key string, useRemote bool) {
+	m.mu.Lock()
+	e := m.entries[key]
+	if e == nil || e.conflict == nil {
+		m.mu.Unlock()
+		return
+	}
+	c := e.conflict
+	e.conflict = nil
+	if useRemote {
+		e.raw, e.value, e.updatedAt = compact(c.Value), nil, c.UpdatedAt
+	} else {
+		// Stamp the kept value so it supersedes the stored one everywhere.
+		e.updatedAt = time.Now()
+	}
+	out := Entry{Key: key, Value: e.raw, UpdatedAt: e.updatedAt}
+	userID := m.userID
+	m.mu.Unlock()
+
+	changed(e)
+	m.syncer.SaveLocal([]Entry{out})
+	if !useRemote && userID != "" {
+		m.syncer.SaveRemote(userID, out)
+	}
+}
+
+// compact returns raw without insignificant whitespace, so that values
+// encoded by the client and the server compare equal.
+func compact(raw json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
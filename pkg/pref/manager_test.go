@@ -0,0 +1,241 @@
+package pref
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// fakeSyncer records what a Manager propagates.
+type fakeSyncer struct {
+	local  []Entry
+	remote []Entry
+	loads  []string
+}
+
+func (f *fakeSyncer) SaveLocal(entries []Entry)         { f.local = append(f.local, entries...) }
+func (f *fakeSyncer) SaveRemote(userID string, e Entry) { f.remote = append(f.remote, e) }
+func (f *fakeSyncer) LoadRemote(userID string)          { f.loads = append(f.loads, userID) }
+func (f *fakeSyncer) reset()                            { f.local, f.remote, f.loads = nil, nil, nil }
+func entryAt(key, value string, at time.Time) Entry {
+	return Entry{Key: key, Value: json.RawMessage(value), UpdatedAt: at}
+}
+func inSession(m *Manager, fn func()) {
+	owner := vango.NewOwner(nil)
+	owner.SetValue(ManagerKey, m)
+	vango.WithOwner(owner, fn)
+}
+func newTestManager() (*Manager, *fakeSyncer) { f := &fakeSyncer{}; return NewManager(f), f }
+
+func TestManagerSessionValues(t *testing.T) {
+	p := New("mgr-theme", "light")
+	m1, sync1 := newTestManager()
+	m2, _ := newTestManager()
+
+	inSession(m1, func() { p.Set("dark") })
+
+	inSession(m1, func() {
+		if got := p.Get(); got != "dark" {
+			t.Errorf("session 1 Get = %q, want dark", got)
+		}
+	})
+	inSession(m2, func() {
+		if got := p.Get(); got != "light" {
+			t.Errorf("session 2 Get = %q, want the default", got)
+		}
+	})
+	if got := p.Get(); got != "light" {
+		t.Errorf("standalone Get = %q, want the default", got)
+	}
+
+	if len(sync1.local) != 1 || string(sync1.local[0].Value) != `"dark"` {
+		t.Errorf("SaveLocal got %v, want the new value", sync1.local)
+	}
+	if len(sync1.remote) != 0 {
+		t.Errorf("SaveRemote called before login: %v", sync1.remote)
+	}
+}
+
+func TestManagerGetTracksChanges(t *testing.T) {
+	p := New("mgr-tracked", 1)
+	m, _ := newTestManager()
+
+	owner := vango.NewOwner(nil)
+	defer owner.Dispose()
+	owner.SetValue(ManagerKey, m)
+
+	// Components read preferences while rendering, under their owner.
+	var seen []int
+	vango.WithOwner(owner, func() {
+		vango.CreateEffect(func() vango.Cleanup {
+			vango.WithOwner(owner, func() { seen = append(seen, p.Get()) })
+			return nil
+		})
+	})
+	m.ApplyLocal([]Entry{entryAt("mgr-tracked", "2", time.Now())})
+	owner.RunPendingEffects(nil)
+
+	if len(seen) != 2 || seen[1] != 2 {
+		t.Errorf("effect saw %v, want a re-run with the client's value", seen)
+	}
+}
+
+func TestManagerApplyLocal(t *testing.T) {
+	New("mgr-local", "a")
+	m, syncer := newTestManager()
+	now := time.Now()
+
+	m.ApplyLocal([]Entry{entryAt("mgr-local", `"b"`, now)})
+	m.ApplyLocal([]Entry{entryAt("mgr-local", `"old"`, now.Add(-time.Minute))})
+
+	if e, _ := m.Lookup("mgr-local"); string(e.Value) != `"b"` {
+		t.Errorf("value = %s, want the newer client value", e.Value)
+	}
+
+	m.Login("u1", nil)
+	syncer.reset()
+	m.ApplyLocal([]Entry{entryAt("mgr-local", `"c"`, now.Add(time.Minute))})
+	if len(syncer.remote) != 1 || string(syncer.remote[0].Value) != `"c"` {
+		t.Errorf("SaveRemote got %v, want the client's change once logged in", syncer.remote)
+	}
+}
+
+func TestManagerApplyRemote(t *testing.T) {
+	m, syncer := newTestManager()
+	now := time.Now()
+	m.ApplyLocal([]Entry{entryAt("mgr-remote", `"a"`, now)})
+
+	m.ApplyRemote(entryAt("mgr-remote", `"stale"`, now.Add(-time.Minute)))
+	if len(syncer.local) != 0 {
+		t.Errorf("stale remote value was sent to the client: %v", syncer.local)
+	}
+
+	m.ApplyRemote(entryAt("mgr-remote", `"b"`, now.Add(time.Minute)))
+	if e, _ := m.Lookup("mgr-remote"); string(e.Value) != `"b"` {
+		t.Errorf("value = %s, want the newer remote value", e.Value)
+	}
+	if len(syncer.local) != 1 {
+		t.Errorf("SaveLocal got %v, want the remote value", syncer.local)
+	}
+}
+
+func TestManagerLoginStrategies(t *testing.T) {
+	New("mgr-lww", "")
+	New("mgr-db", "", MergeWith(DBWins))
+	New("mgr-localwins", "", MergeWith(LocalWins))
+	New("mgr-merge", []string{}, OnConflict(func(local, remote any) any {
+		return append(local.([]string), remote.([]string)...)
+	}))
+	New("mgr-localonly", "", LocalOnly())
+
+	now := time.Now()
+	earlier, later := now.Add(-time.Minute), now.Add(time.Minute)
+
+	m, syncer := newTestManager()
+	m.ApplyLocal([]Entry{
+		entryAt("mgr-lww", `"local"`, now),
+		entryAt("mgr-db", `"local"`, later),
+		entryAt("mgr-localwins", `"local"`, earlier),
+		entryAt("mgr-merge", `["a"]`, now),
+		entryAt("mgr-localonly", `"local"`, now),
+		entryAt("mgr-new", `"local"`, now),
+	})
+
+	m.Login("u1", []Entry{
+		entryAt("mgr-lww", `"remote"`, later),
+		entryAt("mgr-db", `"remote"`, earlier),
+		entryAt("mgr-localwins", `"remote"`, later),
+		entryAt("mgr-merge", `["b"]`, now),
+		entryAt("mgr-localonly", `"remote"`, later),
+		entryAt("mgr-stored", `"remote"`, now),
+	})
+
+	want := map[string]string{
+		"mgr-lww":       `"remote"`,
+		"mgr-db":        `"remote"`,
+		"mgr-localwins": `"local"`,
+		"mgr-merge":     `["a","b"]`,
+		"mgr-localonly": `"local"`,
+		"mgr-new":       `"local"`,
+		"mgr-stored":    `"remote"`,
+	}
+	for key, value := range want {
+		if e, _ := m.Lookup(key); string(e.Value) != value {
+			t.Errorf("%s = %s, want %s", key, e.Value, value)
+		}
+	}
+	if m.UserID() != "u1" {
+		t.Errorf("UserID = %q, want u1", m.UserID())
+	}
+
+	saved := make(map[string]string)
+	for _, e := range syncer.remote {
+		saved[e.Key] = string(e.Value)
+	}
+	for _, key := range []string{"mgr-localwins", "mgr-merge", "mgr-new"} {
+		if saved[key] != want[key] {
+			t.Errorf("store got %s = %q, want %s", key, saved[key], want[key])
+		}
+	}
+	if _, ok := saved["mgr-localonly"]; ok {
+		t.Error("LocalOnly preference was saved to the store")
+	}
+}
+
+func TestManagerPromptConflict(t *testing.T) {
+	p := New("mgr-prompt", "light", MergeWith(Prompt))
+	now := time.Now()
+
+	for _, useRemote := range []bool{true, false} {
+		m, syncer := newTestManager()
+		m.ApplyLocal([]Entry{entryAt("mgr-prompt", `"dark"`, now)})
+		m.Login("u1", []Entry{entryAt("mgr-prompt", `"blue"`, now.Add(time.Minute))})
+
+		inSession(m, func() {
+			remote, ok := p.Conflict()
+			if !ok || remote != "blue" {
+				t.Fatalf("Conflict = %q, %v, want the stored value", remote, ok)
+			}
+			if got := p.Get(); got != "dark" {
+				t.Errorf("Get = %q while prompting, want the session value", got)
+			}
+
+			syncer.reset()
+			p.Resolve(useRemote)
+
+			if _, ok := p.Conflict(); ok {
+				t.Error("conflict remains after Resolve")
+			}
+		})
+
+		want := "dark"
+		if useRemote {
+			want = "blue"
+		}
+		inSession(m, func() {
+			if got := p.Get(); got != want {
+				t.Errorf("Resolve(%v): Get = %q, want %q", useRemote, got, want)
+			}
+		})
+		if saved := len(syncer.remote) > 0; saved == useRemote {
+			t.Errorf("Resolve(%v): SaveRemote got %v", useRemote, syncer.remote)
+		}
+	}
+}
+
+func TestLoginLogout(t *testing.T) {
+	m, syncer := newTestManager()
+
+	inSession(m, func() { Login("u1") })
+	if len(syncer.loads) != 1 || syncer.loads[0] != "u1" {
+		t.Errorf("LoadRemote got %v, want u1", syncer.loads)
+	}
+
+	m.Login("u1", nil)
+	inSession(m, func() { Logout() })
+	if m.UserID() != "" {
+		t.Errorf("UserID = %q after Logout", m.UserID())
+	}
+}
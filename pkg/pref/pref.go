@@ -5,21 +5,36 @@
 //   - Sync when user logs in (merge with database)
 //   - Stay consistent across tabs and devices
 //
-// Example:
+// Preferences are declared once, usually at package level, and hold a
+// separate value in each session:
 //
 //	// Simple theme preference
-//	theme := pref.New("theme", "light")
+//	var Theme = pref.New("theme", "light")
 //
 //	// With merge strategy
-//	settings := pref.New("settings", Settings{}, pref.MergeWith(pref.DBWins))
+//	var Settings = pref.New("settings", SettingsData{}, pref.MergeWith(pref.DBWins))
 //
-//	// Read/write
-//	current := theme.Get()
-//	theme.Set("dark")
+//	// Read/write in a component or handler
+//	current := Theme.Get()
+//	Theme.Set("dark")
+//
+// Inside a session, Get subscribes the current component like a signal read.
+// The session loads the values kept in the client's localStorage at
+// handshake, sends changed values back to the client, and shares them with
+// the user's other tabs. Once a user is known (see Login), values are merged
+// with the server's Store and later changes are saved to it and broadcast to
+// the user's other sessions.
+//
+// With the Prompt strategy, a stored value that differs from the session's
+// is exposed through Conflict so a component can ask the user, who settles
+// it with Resolve.
+//
+// Outside a session a Pref holds a single value of its own.
 package pref
 
 import (
 	"encoding/json"
+	"errors"
 	"sync"
 	"time"
 )
@@ -78,6 +93,74 @@ func LocalOnly() PrefOption {
 	}
 }
 
+// defaultConfig is the configuration of preferences created without options.
+func defaultConfig() prefConfig {
+	return prefConfig{
+		mergeStrategy: LWW,
+		persistLocal:  true,
+		syncToServer:  true,
+	}
+}
+
+// definition is the type-erased form of a Pref, registered by key so that a
+// session can merge stored values before the Pref is read.
+type definition interface {
+	prefConfig() prefConfig
+	mergeRaw(local, remote json.RawMessage) (json.RawMessage, error)
+}
+
+// definitions maps preference keys to their most recent definition.
+var definitions sync.Map
+
+// lookupDefinition returns the configuration and definition registered for
+// key. Unknown keys use the default configuration.
+func lookupDefinition(key string) (prefConfig, definition) {
+	if v, ok := definitions.Load(key); ok {
+		def := v.(definition)
+		return def.prefConfig(), def
+	}
+	return defaultConfig(), nil
+}
+
+// configFor returns the configuration registered for key.
+func configFor(key string) prefConfig {
+	cfg, _ := lookupDefinition(key)
+	return cfg
+}
+
+// resolution is the outcome of merging a stored value into a session.
+type resolution int
+
+const (
+	keepLocal resolution = iota
+	takeRemote
+	prompt
+	merged
+)
+
+// resolveEntries decides between differing local and stored values.
+func resolveEntries(cfg prefConfig, def definition, local, remote Entry) resolution {
+	if local.Value == nil {
+		return takeRemote
+	}
+	if cfg.conflictHandler != nil && def != nil {
+		return merged
+	}
+	switch cfg.mergeStrategy {
+	case DBWins:
+		return takeRemote
+	case LocalWins:
+		return keepLocal
+	case Prompt:
+		return prompt
+	default:
+		if remote.UpdatedAt.After(local.UpdatedAt) {
+			return takeRemote
+		}
+		return keepLocal
+	}
+}
+
 // Pref represents a user preference with sync capabilities.
 type Pref[T any] struct {
 	key       string
@@ -86,6 +169,10 @@ type Pref[T any] struct {
 	updatedAt time.Time
 	config    prefConfig
 
+	// conflict is a remote value awaiting Resolve (Prompt strategy).
+	conflict   *T
+	conflictAt time.Time
+
 	mu sync.RWMutex
 
 	// Context for persistence (set during initialization)
@@ -95,34 +182,68 @@ type Pref[T any] struct {
 }
 
 // New creates a new preference with the given key and default value.
+// Keys are global: a later New with the same key replaces the merge options
+// sessions use for it.
 func New[T any](key string, defaultValue T, opts ...PrefOption) *Pref[T] {
-	config := prefConfig{
-		mergeStrategy: LWW,
-		persistLocal:  true,
-		syncToServer:  true,
-	}
+	config := defaultConfig()
 	for _, opt := range opts {
 		opt(&config)
 	}
 
-	return &Pref[T]{
+	p := &Pref[T]{
 		key:       key,
 		value:     defaultValue,
 		defaults:  defaultValue,
 		updatedAt: time.Now(),
 		config:    config,
 	}
+	definitions.Store(key, definition(p))
+	return p
 }
 
 // Get returns the current preference value.
+// In a session it returns the session's value and subscribes the current
+// listener, so components re-render when it changes.
 func (p *Pref[T]) Get() T {
+	if m := currentManager(); m != nil {
+		return p.getIn(m)
+	}
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 	return p.value
 }
 
+// getIn returns the value of p in a session, decoding it at most once per
+// change.
+func (p *Pref[T]) getIn(m *Manager) T {
+	raw, cached := m.get(p.key)
+	if raw == nil {
+		return p.defaults
+	}
+	if v, ok := cached.(T); ok {
+		return v
+	}
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return p.defaults
+	}
+	m.cache(p.key, raw, v)
+	return v
+}
+
 // Set updates the preference value and triggers sync.
+// In a session the value is sent to the client and, for a logged-in user,
+// saved to the Store and broadcast to the user's other sessions.
 func (p *Pref[T]) Set(value T) {
+	if m := currentManager(); m != nil {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return
+		}
+		m.set(p.key, raw, value, p.config)
+		return
+	}
+
 	p.mu.Lock()
 	p.value = value
 	p.updatedAt = time.Now()
@@ -157,17 +278,34 @@ func (p *Pref[T]) Key() string {
 
 // UpdatedAt returns when the preference was last updated.
 func (p *Pref[T]) UpdatedAt() time.Time {
+	if m := currentManager(); m != nil {
+		if e, ok := m.Lookup(p.key); ok {
+			return e.UpdatedAt
+		}
+	}
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 	return p.updatedAt
 }
 
 // SetFromRemote updates the value from a remote source (another tab or server).
-// Uses the configured merge strategy to resolve conflicts.
+// Uses the configured merge strategy to resolve conflicts. With Prompt, a
+// differing value is kept as a conflict until Resolve is called.
+//
+// Sessions merge remote values themselves; SetFromRemote only affects a
+// Pref's own value, outside a session.
 func (p *Pref[T]) SetFromRemote(value T, remoteUpdatedAt time.Time) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.config.mergeStrategy == Prompt && p.config.conflictHandler == nil {
+		if !jsonEqual(p.value, value) {
+			p.conflict = &value
+			p.conflictAt = remoteUpdatedAt
+		}
+		return
+	}
+
 	resolved := p.resolveConflict(p.value, value, p.updatedAt, remoteUpdatedAt)
 	if resolvedT, ok := resolved.(T); ok {
 		p.value = resolvedT
@@ -195,20 +333,99 @@ func (p *Pref[T]) resolveConflict(local, remote any, localTime, remoteTime time.
 			return remote
 		}
 		return local
-	case Prompt:
-		// For prompt, we need to notify the UI
-		// For now, default to LWW
-		if remoteTime.After(localTime) {
-			return remote
-		}
-		return local
 	default:
 		return local
 	}
 }
 
-// SetPersistHandlers sets the persistence handlers.
-// Called during component initialization.
+// Conflict returns the remote value awaiting a decision for a preference
+// with the Prompt strategy, and whether there is one. In a session it
+// subscribes the current listener, so a component can render a prompt:
+//
+//	if remote, ok := Theme.Conflict(); ok {
+//	    return Div(
+//	        Text("Use your saved theme " + remote + "?"),
+//	        Button(OnClick(func() { Theme.Resolve(true) }), Text("Yes")),
+//	        Button(OnClick(func() { Theme.Resolve(false) }), Text("Keep current")),
+//	    )
+//	}
+func (p *Pref[T]) Conflict() (remote T, ok bool) {
+	if m := currentManager(); m != nil {
+		c, ok := m.conflict(p.key)
+		if !ok {
+			return remote, false
+		}
+		if err := json.Unmarshal(c.Value, &remote); err != nil {
+			return remote, false
+		}
+		return remote, true
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.conflict == nil {
+		return remote, false
+	}
+	return *p.conflict, true
+}
+
+// Resolve settles the pending conflict: useRemote takes the remote value,
+// otherwise the current value is kept and saved over the remote one.
+func (p *Pref[T]) Resolve(useRemote bool) {
+	if m := currentManager(); m != nil {
+		m.resolve(p.key, useRemote)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conflict == nil {
+		return
+	}
+	if useRemote {
+		p.value = *p.conflict
+		p.updatedAt = p.conflictAt
+	} else {
+		p.updatedAt = time.Now()
+	}
+	p.conflict = nil
+}
+
+// prefConfig implements definition.
+func (p *Pref[T]) prefConfig() prefConfig {
+	return p.config
+}
+
+// errConflictType is returned when an OnConflict handler returns a value of
+// the wrong type.
+var errConflictType = errors.New("pref: conflict handler returned a value of the wrong type")
+
+// mergeRaw implements definition by running the OnConflict handler on the
+// decoded values.
+func (p *Pref[T]) mergeRaw(localRaw, remoteRaw json.RawMessage) (json.RawMessage, error) {
+	var local, remote T
+	if err := json.Unmarshal(localRaw, &local); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(remoteRaw, &remote); err != nil {
+		return nil, err
+	}
+	resolved, ok := p.config.conflictHandler(local, remote).(T)
+	if !ok {
+		return nil, errConflictType
+	}
+	return json.Marshal(resolved)
+}
+
+// jsonEqual reports whether a and b have the same JSON encoding.
+func jsonEqual(a, b any) bool {
+	ra, errA := json.Marshal(a)
+	rb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ra) == string(rb)
+}
+
+// SetPersistHandlers sets the persistence handlers used outside a session.
+// Sessions persist values through their Manager instead.
 func (p *Pref[T]) SetPersistHandlers(
 	local func(key string, value any, updatedAt time.Time),
 	db func(key string, value any, updatedAt time.Time),
@@ -253,3 +470,25 @@ func (p *Pref[T]) UnmarshalJSON(data []byte) error {
 	p.updatedAt = temp.UpdatedAt
 	return nil
 }
+
+// Login syncs the current session's preferences for userID: the values
+// stored for the user are loaded and merged, and later changes are saved
+// for them. Sessions that are authenticated at handshake are synced
+// automatically; call Login after authenticating a user mid-session:
+//
+//	auth.Login(ctx, user)
+//	pref.Login(user.ID)
+//
+// Login does nothing outside a session.
+func Login(userID string) {
+	if m := currentManager(); m != nil && userID != "" {
+		m.syncer.LoadRemote(userID)
+	}
+}
+
+// Logout stops syncing the current session's preferences for its user.
+func Logout() {
+	if m := currentManager(); m != nil {
+		m.Logout()
+	}
+}
@@ -122,6 +122,28 @@ func TestPrefSetFromRemote(t *testing.T) {
 	})
 }
 
+// TestPrefPromptConflict tests that Prompt keeps a remote value until Resolve.
+func TestPrefPromptConflict(t *testing.T) {
+	pref := New("prompt-theme", "light", MergeWith(Prompt))
+	pref.SetFromRemote("dark", time.Now().Add(time.Hour))
+
+	if pref.Get() != "light" {
+		t.Errorf("Get: got %v, want light while prompting", pref.Get())
+	}
+	remote, ok := pref.Conflict()
+	if !ok || remote != "dark" {
+		t.Fatalf("Conflict: got %v, %v, want dark", remote, ok)
+	}
+
+	pref.Resolve(true)
+	if pref.Get() != "dark" {
+		t.Errorf("Get: got %v, want dark after Resolve", pref.Get())
+	}
+	if _, ok := pref.Conflict(); ok {
+		t.Error("Conflict remains after Resolve")
+	}
+}
+
 // TestPrefCustomConflictHandler tests custom conflict resolution.
 func TestPrefCustomConflictHandler(t *testing.T) {
 	handler := func(local, remote any) any {
@@ -0,0 +1,161 @@
+package pref
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vango-go/vango/pkg/session"
+)
+
+// SQLStore is a SQL-backed preference store.
+// It works with any database/sql compatible driver (PostgreSQL, MySQL, SQLite).
+// Requires a table with schema:
+//
+//	CREATE TABLE vango_prefs (
+//	    user_id VARCHAR(255) NOT NULL,
+//	    pref_key VARCHAR(255) NOT NULL,
+//	    value TEXT NOT NULL,
+//	    updated_at BIGINT NOT NULL, -- Unix milliseconds
+//	    PRIMARY KEY (user_id, pref_key)
+//	);
+type SQLStore struct {
+	db        *sql.DB
+	tableName string
+	dialect   session.SQLDialect
+}
+
+// SQLStoreOption configures SQLStore behavior.
+type SQLStoreOption func(*SQLStore)
+
+// WithSQLTableName sets the table name for preference storage.
+// Default: "vango_prefs".
+func WithSQLTableName(name string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.tableName = name
+	}
+}
+
+// WithSQLDialect sets the SQL dialect for query generation.
+// Default: session.DialectPostgreSQL.
+func WithSQLDialect(dialect session.SQLDialect) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.dialect = dialect
+	}
+}
+
+// NewSQLStore creates a new SQL-backed preference store.
+func NewSQLStore(db *sql.DB, opts ...SQLStoreOption) *SQLStore {
+	s := &SQLStore{
+		db:        db,
+		tableName: "vango_prefs",
+		dialect:   session.DialectPostgreSQL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Load implements Store.
+func (s *SQLStore) Load(ctx context.Context, userID string) ([]Entry, error) {
+	placeholder := "?"
+	if s.dialect == session.DialectPostgreSQL {
+		placeholder = "$1"
+	}
+	query := fmt.Sprintf(`SELECT pref_key, value, updated_at FROM %s WHERE user_id = %s`, s.tableName, placeholder)
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var (
+			key       string
+			value     string
+			updatedAt int64
+		)
+		if err := rows.Scan(&key, &value, &updatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{
+			Key:       key,
+			Value:     json.RawMessage(value),
+			UpdatedAt: time.UnixMilli(updatedAt),
+		})
+	}
+	return entries, rows.Err()
+}
+
+// Save implements Store. A stored value with a newer timestamp is kept.
+func (s *SQLStore) Save(ctx context.Context, userID string, e Entry) error {
+	var query string
+	switch s.dialect {
+	case session.DialectPostgreSQL:
+		query = fmt.Sprintf(`
+			INSERT INTO %s (user_id, pref_key, value, updated_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, pref_key) DO UPDATE SET
+				value = EXCLUDED.value,
+				updated_at = EXCLUDED.updated_at
+			WHERE %s.updated_at <= EXCLUDED.updated_at
+		`, s.tableName, s.tableName)
+	case session.DialectMySQL:
+		// value is assigned first so it still compares against the old updated_at.
+		query = fmt.Sprintf(`
+			INSERT INTO %s (user_id, pref_key, value, updated_at)
+			VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				value = IF(VALUES(updated_at) >= updated_at, VALUES(value), value),
+				updated_at = GREATEST(updated_at, VALUES(updated_at))
+		`, s.tableName)
+	case session.DialectSQLite:
+		query = fmt.Sprintf(`
+			INSERT INTO %s (user_id, pref_key, value, updated_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (user_id, pref_key) DO UPDATE SET
+				value = excluded.value,
+				updated_at = excluded.updated_at
+			WHERE %s.updated_at <= excluded.updated_at
+		`, s.tableName, s.tableName)
+	}
+
+	_, err := s.db.ExecContext(ctx, query, userID, e.Key, string(e.Value), e.UpdatedAt.UnixMilli())
+	return err
+}
+
+// CreateTable creates the preference table if it doesn't exist.
+// This is a convenience method for development/testing.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	var query string
+	switch s.dialect {
+	case session.DialectSQLite:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				user_id TEXT NOT NULL,
+				pref_key TEXT NOT NULL,
+				value TEXT NOT NULL,
+				updated_at INTEGER NOT NULL,
+				PRIMARY KEY (user_id, pref_key)
+			)
+		`, s.tableName)
+	default:
+		query = fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				user_id VARCHAR(255) NOT NULL,
+				pref_key VARCHAR(255) NOT NULL,
+				value TEXT NOT NULL,
+				updated_at BIGINT NOT NULL,
+				PRIMARY KEY (user_id, pref_key)
+			)
+		`, s.tableName)
+	}
+
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,70 @@
+package pref
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Entry is a stored preference value.
+type Entry struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Store persists the preferences of authenticated users.
+//
+// When a user logs in, their stored preferences are merged with the values
+// the client kept in localStorage, using each preference's merge strategy.
+// Afterwards every change to a synced preference is saved.
+type Store interface {
+	// Load returns the preferences stored for userID.
+	Load(ctx context.Context, userID string) ([]Entry, error)
+
+	// Save stores a preference for userID. Implementations must keep the
+	// stored value if it is newer than e, as saves may arrive out of order.
+	Save(ctx context.Context, userID string, e Entry) error
+}
+
+// MemoryStore is an in-memory Store, for development and tests.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	users map[string]map[string]Entry
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: make(map[string]map[string]Entry)}
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(ctx context.Context, userID string) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(s.users[userID]))
+	for _, e := range s.users[userID] {
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(ctx context.Context, userID string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefs := s.users[userID]
+	if prefs == nil {
+		prefs = make(map[string]Entry)
+		s.users[userID] = prefs
+	}
+	if cur, ok := prefs[e.Key]; ok && cur.UpdatedAt.After(e.UpdatedAt) {
+		return nil
+	}
+	e.Value = append(json.RawMessage(nil), e.Value...)
+	prefs[e.Key] = e
+	return nil
+}
@@ -0,0 +1,31 @@
+package pref
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreKeepsNewer(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := s.Save(ctx, "u1", entryAt("theme", `"dark"`, now)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(ctx, "u1", entryAt("theme", `"stale"`, now.Add(-time.Second))); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.Load(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || string(entries[0].Value) != `"dark"` {
+		t.Errorf("Load = %v, want the newer value", entries)
+	}
+	if entries, _ := s.Load(ctx, "u2"); len(entries) != 0 {
+		t.Errorf("Load for another user = %v, want none", entries)
+	}
+}
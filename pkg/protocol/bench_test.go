@@ -151,10 +151,10 @@ func BenchmarkEvent_EncodeSubmit(b *testing.B) {
 		Type: EventSubmit,
 		HID:  "h7",
 		Payload: &SubmitEventData{
-			Fields: map[string]string{
-				"username": "john",
-				"password": "secret",
-				"email":    "john@example.com",
+			Fields: map[string][]string{
+				"username": {"john"},
+				"password": {"secret"},
+				"email":    {"john@example.com"},
 			},
 		},
 	}
@@ -170,10 +170,10 @@ func BenchmarkEvent_DecodeSubmit(b *testing.B) {
 		Type: EventSubmit,
 		HID:  "h7",
 		Payload: &SubmitEventData{
-			Fields: map[string]string{
-				"username": "john",
-				"password": "secret",
-				"email":    "john@example.com",
+			Fields: map[string][]string{
+				"username": {"john"},
+				"password": {"secret"},
+				"email":    {"john@example.com"},
 			},
 		},
 	}
@@ -0,0 +1,165 @@
+package protocol
+
+import "errors"
+
+// Binary blob uploads stream files from the client over the live session.
+//
+// The client opens an upload with BlobStart, sends the file in BlobChunk
+// frames and finishes with BlobEnd (or gives up with BlobCancel). Uploads
+// are flow controlled: the client keeps at most BlobWindow bytes beyond the
+// last acknowledged offset in flight, and the server acknowledges with
+// ControlBlobAck as the data is stored. Chunks are separate frames, so
+// events and pings interleave with a large upload instead of queueing
+// behind it.
+//
+// Blob payload formats:
+//
+//	BlobStart:  [op:1][id:varint][hid:string][filename:string][type:string][size:varint]
+//	BlobChunk:  [op:1][id:varint][offset:varint][data:len-bytes]
+//	BlobEnd:    [op:1][id:varint]
+//	BlobCancel: [op:1][id:varint]
+
+const (
+	// BlobChunkSize is the amount of file data the client sends per chunk.
+	BlobChunkSize = 32 * 1024
+
+	// BlobWindow is the most unacknowledged data a client may have in flight
+	// for one upload.
+	BlobWindow = 8 * BlobChunkSize
+)
+
+// ErrInvalidBlobOp is returned when decoding a blob message with an unknown
+// operation.
+var ErrInvalidBlobOp = errors.New("protocol: invalid blob operation")
+
+// BlobOp identifies a blob frame operation.
+type BlobOp uint8
+
+const (
+	BlobStart  BlobOp = 0x01 // Open an upload
+	BlobChunk  BlobOp = 0x02 // File data at an offset
+	BlobEnd    BlobOp = 0x03 // All data sent
+	BlobCancel BlobOp = 0x04 // Client abandoned the upload
+)
+
+// String returns the string representation of the blob operation.
+func (op BlobOp) String() string {
+	switch op {
+	case BlobStart:
+		return "Start"
+	case BlobChunk:
+		return "Chunk"
+	case BlobEnd:
+		return "End"
+	case BlobCancel:
+		return "Cancel"
+	default:
+		return "Unknown"
+	}
+}
+
+// Blob is the payload of a FrameBlob frame. Which fields are set depends
+// on Op.
+type Blob struct {
+	Op BlobOp
+	ID uint64 // Client-chosen upload ID, unique per connection
+
+	// BlobStart
+	HID         string // Element the upload belongs to
+	Filename    string
+	ContentType string // As reported by the browser; not trusted
+	Size        uint64
+
+	// BlobChunk
+	Offset uint64
+	Data   []byte
+}
+
+// EncodeBlob encodes a blob message to bytes.
+func EncodeBlob(b *Blob) []byte {
+	e := NewEncoder()
+	EncodeBlobTo(e, b)
+	return e.Bytes()
+}
+
+// EncodeBlobTo encodes a blob message using the provided encoder.
+func EncodeBlobTo(e *Encoder, b *Blob) {
+	e.WriteByte(byte(b.Op))
+	e.WriteUvarint(b.ID)
+
+	switch b.Op {
+	case BlobStart:
+		e.WriteString(b.HID)
+		e.WriteString(b.Filename)
+		e.WriteString(b.ContentType)
+		e.WriteUvarint(b.Size)
+	case BlobChunk:
+		e.WriteUvarint(b.Offset)
+		e.WriteLenBytes(b.Data)
+	}
+}
+
+// DecodeBlob decodes a blob message from bytes.
+func DecodeBlob(data []byte) (*Blob, error) {
+	d := NewDecoder(data)
+
+	op, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	b := &Blob{Op: BlobOp(op)}
+	if b.ID, err = d.ReadUvarint(); err != nil {
+		return nil, err
+	}
+
+	switch b.Op {
+	case BlobStart:
+		if b.HID, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if b.Filename, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if b.ContentType, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if b.Size, err = d.ReadUvarint(); err != nil {
+			return nil, err
+		}
+	case BlobChunk:
+		if b.Offset, err = d.ReadUvarint(); err != nil {
+			return nil, err
+		}
+		if b.Data, err = d.ReadLenBytes(); err != nil {
+			return nil, err
+		}
+	case BlobEnd, BlobCancel:
+	default:
+		return nil, ErrInvalidBlobOp
+	}
+
+	return b, nil
+}
+
+// BlobStatus is the state of an upload reported in a BlobAck.
+type BlobStatus uint8
+
+const (
+	BlobStatusProgress BlobStatus = 0x00 // Data up to Received is stored
+	BlobStatusDone     BlobStatus = 0x01 // Upload complete
+	BlobStatusError    BlobStatus = 0x02 // Upload failed; Message says why
+)
+
+// BlobAck reports upload progress to the client. Received is the number of
+// bytes the server has consumed, which extends the client's send window.
+type BlobAck struct {
+	ID       uint64
+	Received uint64
+	Status   BlobStatus
+	Message  string
+}
+
+// NewBlobAck creates a new BlobAck control message.
+func NewBlobAck(id, received uint64, status BlobStatus, message string) (ControlType, *BlobAck) {
+	return ControlBlobAck, &BlobAck{ID: id, Received: received, Status: status, Message: message}
+}
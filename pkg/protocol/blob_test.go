@@ -0,0 +1,54 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlobEncodeDecode(t *testing.T) {
+	tests := []*Blob{
+		{Op: BlobStart, ID: 1, HID: "h3", Filename: "photo.png", ContentType: "image/png", Size: 1 << 20},
+		{Op: BlobChunk, ID: 1, Offset: 32768, Data: bytes.Repeat([]byte{0xAB}, 100)},
+		{Op: BlobChunk, ID: 1, Offset: 0, Data: []byte{}},
+		{Op: BlobEnd, ID: 1},
+		{Op: BlobCancel, ID: 300},
+	}
+
+	for _, want := range tests {
+		t.Run(want.Op.String(), func(t *testing.T) {
+			got, err := DecodeBlob(EncodeBlob(want))
+			if err != nil {
+				t.Fatalf("DecodeBlob error: %v", err)
+			}
+			if got.Op != want.Op || got.ID != want.ID || got.HID != want.HID ||
+				got.Filename != want.Filename || got.ContentType != want.ContentType ||
+				got.Size != want.Size || got.Offset != want.Offset || !bytes.Equal(got.Data, want.Data) {
+				t.Errorf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBlobDecodeErrors(t *testing.T) {
+	if _, err := DecodeBlob([]byte{0x09, 0x01}); err != ErrInvalidBlobOp {
+		t.Errorf("unknown op error = %v, want ErrInvalidBlobOp", err)
+	}
+	start := EncodeBlob(&Blob{Op: BlobStart, ID: 1, HID: "h1", Filename: "a.txt", Size: 10})
+	if _, err := DecodeBlob(start[:len(start)-1]); err == nil {
+		t.Error("truncated BlobStart decoded without error")
+	}
+}
+
+func TestBlobAckControl(t *testing.T) {
+	ct, ack := NewBlobAck(7, BlobWindow, BlobStatusError, "upload: file too large")
+	got, payload, err := DecodeControl(EncodeControl(ct, ack))
+	if err != nil {
+		t.Fatalf("DecodeControl error: %v", err)
+	}
+	if got != ControlBlobAck {
+		t.Fatalf("control type = %v, want BlobAck", got)
+	}
+	if *payload.(*BlobAck) != *ack {
+		t.Errorf("BlobAck = %+v, want %+v", payload, ack)
+	}
+}
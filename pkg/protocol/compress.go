@@ -0,0 +1,151 @@
+package protocol
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Codec identifies a frame payload compression codec.
+//
+// The client lists the codecs it can decode in ClientHello.Codecs; the server
+// picks one with NegotiateCodec and announces it in ServerHello.Flags. Frames
+// whose payload was compressed carry FlagCompressed.
+type Codec uint8
+
+const (
+	CodecNone    Codec = 0x00 // No compression
+	CodecDeflate Codec = 0x01 // Raw DEFLATE (RFC 1951), window shared across frames
+)
+
+// String returns the string representation of the codec.
+func (c Codec) String() string {
+	switch c {
+	case CodecNone:
+		return "none"
+	case CodecDeflate:
+		return "deflate"
+	default:
+		return fmt.Sprintf("Codec(%d)", uint8(c))
+	}
+}
+
+// SupportedCodecs lists the codecs implemented by this package.
+var SupportedCodecs = []Codec{CodecDeflate}
+
+// NegotiateCodec returns the first codec in the client's preference list
+// that this package supports, or CodecNone.
+func NegotiateCodec(offered []Codec) Codec {
+	for _, c := range offered {
+		for _, s := range SupportedCodecs {
+			if c == s {
+				return c
+			}
+		}
+	}
+	return CodecNone
+}
+
+// ErrUnsupportedCodec is returned when creating a compressor or
+// decompressor for an unknown codec.
+var ErrUnsupportedCodec = errors.New("protocol: unsupported compression codec")
+
+// MaxCompressiblePayload is the largest payload Compress accepts and
+// Decompress produces. Compressed payloads larger than MaxPayloadSize are
+// sent fragmented, like any other large message.
+const MaxCompressiblePayload = DefaultMaxMessageSize
+
+// Compressor compresses frame payloads for one connection.
+//
+// Compressed payloads are encoded as:
+//
+//	[RawLength: varint][DEFLATE data ending in a sync flush]
+//
+// The DEFLATE stream is never reset, so every frame can refer back to
+// the previous 32KB of payload data. This shared window is what makes
+// repeated markup (table rows, list items) cheap after the first frame, and
+// it means frames must be decompressed in the order they were compressed,
+// each exactly once. A Compressor is not safe for concurrent use.
+type Compressor struct {
+	buf bytes.Buffer
+	fw  *flate.Writer
+}
+
+// NewCompressor creates a compressor for the given codec.
+func NewCompressor(codec Codec) (*Compressor, error) {
+	if codec != CodecDeflate {
+		return nil, ErrUnsupportedCodec
+	}
+	c := &Compressor{}
+	fw, err := flate.NewWriter(&c.buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	c.fw = fw
+	return c, nil
+}
+
+// Compress compresses a frame payload. It returns ErrFrameTooLarge, without
+// touching the shared window, if the payload exceeds MaxCompressiblePayload.
+func (c *Compressor) Compress(payload []byte) ([]byte, error) {
+	if len(payload) > MaxCompressiblePayload {
+		return nil, ErrFrameTooLarge
+	}
+
+	c.buf.Reset()
+	var prefix [MaxVarintLen]byte
+	n := EncodeUvarint(prefix[:], uint64(len(payload)))
+	c.buf.Write(prefix[:n])
+
+	if _, err := c.fw.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := c.fw.Flush(); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, c.buf.Len())
+	copy(out, c.buf.Bytes())
+	return out, nil
+}
+
+// Decompressor reverses a Compressor on the receiving side of a connection.
+// Payloads must be passed in the order they were compressed. A Decompressor
+// is not safe for concurrent use.
+type Decompressor struct {
+	src bytes.Buffer
+	fr  io.ReadCloser
+}
+
+// NewDecompressor creates a decompressor for the given codec.
+func NewDecompressor(codec Codec) (*Decompressor, error) {
+	if codec != CodecDeflate {
+		return nil, ErrUnsupportedCodec
+	}
+	d := &Decompressor{}
+	d.fr = flate.NewReader(&d.src)
+	return d, nil
+}
+
+// Decompress decompresses a payload produced by Compressor.Compress.
+func (d *Decompressor) Decompress(payload []byte) ([]byte, error) {
+	rawLen, n := DecodeUvarint(payload)
+	if n == -2 {
+		return nil, ErrVarintOverflow
+	}
+	if n <= 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if rawLen > MaxCompressiblePayload {
+		return nil, ErrFrameTooLarge
+	}
+
+	d.src.Write(payload[n:])
+	out := make([]byte, rawLen)
+	if _, err := io.ReadFull(d.fr, out); err != nil {
+		return nil, fmt.Errorf("protocol: decompress: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,178 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompressorRoundTrip(t *testing.T) {
+	c, err := NewCompressor(CodecDeflate)
+	if err != nil {
+		t.Fatalf("NewCompressor: %v", err)
+	}
+	d, err := NewDecompressor(CodecDeflate)
+	if err != nil {
+		t.Fatalf("NewDecompressor: %v", err)
+	}
+
+	random := make([]byte, 4096)
+	rand.Read(random)
+
+	payloads := [][]byte{
+		[]byte("<tr><td>row</td></tr>"),
+		bytes.Repeat([]byte("<tr><td>row</td></tr>"), 500),
+		random,
+		{0x01},
+		bytes.Repeat([]byte{0xAB}, MaxCompressiblePayload),
+		[]byte(strings.Repeat("x", 100)),
+	}
+	// Run the sequence twice so later frames decode against a window filled
+	// by earlier ones.
+	for round := 0; round < 2; round++ {
+		for i, p := range payloads {
+			compressed, err := c.Compress(p)
+			if err != nil {
+				t.Fatalf("round %d payload %d: Compress: %v", round, i, err)
+			}
+			got, err := d.Decompress(compressed)
+			if err != nil {
+				t.Fatalf("round %d payload %d: Decompress: %v", round, i, err)
+			}
+			if !bytes.Equal(got, p) {
+				t.Fatalf("round %d payload %d: round trip mismatch (%d bytes, want %d)", round, i, len(got), len(p))
+			}
+		}
+	}
+}
+
+func TestCompressorFragmentedMessage(t *testing.T) {
+	c, _ := NewCompressor(CodecDeflate)
+	d, _ := NewDecompressor(CodecDeflate)
+
+	random := make([]byte, 3*MaxPayloadSize)
+	rand.Read(random)
+	payloads := [][]byte{
+		bytes.Repeat([]byte("<tr><td>row</td></tr>"), 50000),
+		random,
+	}
+
+	// Messages are compressed whole, fragmented, and decompressed after
+	// reassembly.
+	r := NewReassembler(0)
+	for i, p := range payloads {
+		compressed, err := c.Compress(p)
+		if err != nil {
+			t.Fatalf("payload %d: Compress: %v", i, err)
+		}
+		var msg *Frame
+		for _, frag := range NewFrameWithFlags(FramePatches, FlagCompressed, compressed).Fragments() {
+			if msg, err = r.Add(frag); err != nil {
+				t.Fatalf("payload %d: Add: %v", i, err)
+			}
+		}
+		if msg == nil || msg.Flags&FlagCompressed == 0 {
+			t.Fatalf("payload %d: reassembled %+v, want a compressed message", i, msg)
+		}
+		got, err := d.Decompress(msg.Payload)
+		if err != nil {
+			t.Fatalf("payload %d: Decompress: %v", i, err)
+		}
+		if !bytes.Equal(got, p) {
+			t.Fatalf("payload %d: round trip mismatch (%d bytes, want %d)", i, len(got), len(p))
+		}
+	}
+}
+
+func TestCompressorSharedWindow(t *testing.T) {
+	c, _ := NewCompressor(CodecDeflate)
+	var rows strings.Builder
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&rows, `<tr class="row"><td class="name">Item %d</td><td class="price">$%d.00</td></tr>`, i, i*3)
+	}
+	payload := []byte(rows.String())
+
+	first, _ := c.Compress(payload)
+	second, _ := c.Compress(payload)
+	if len(second) >= len(first)/4 {
+		t.Errorf("repeated frame compressed to %d bytes (first %d); window not shared across frames", len(second), len(first))
+	}
+
+	fresh, _ := NewCompressor(CodecDeflate)
+	alone, _ := fresh.Compress(payload)
+	if len(alone) != len(first) {
+		t.Errorf("first frame size %d differs from a fresh compressor's %d", len(first), len(alone))
+	}
+}
+
+func TestCompressorTooLarge(t *testing.T) {
+	c, _ := NewCompressor(CodecDeflate)
+	d, _ := NewDecompressor(CodecDeflate)
+
+	if _, err := c.Compress(make([]byte, MaxCompressiblePayload+1)); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("err = %v, want ErrFrameTooLarge", err)
+	}
+
+	// A rejected payload must not desynchronize the stream.
+	compressed, err := c.Compress([]byte("after"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.Decompress(compressed)
+	if err != nil || string(got) != "after" {
+		t.Errorf("Decompress = %q, %v; want %q", got, err, "after")
+	}
+}
+
+func TestDecompressorRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		want    error
+	}{
+		{"empty", nil, nil},
+		{"oversized length", []byte{0x80, 0x80, 0x80, 0x04}, ErrFrameTooLarge},
+		{"truncated stream", []byte{0x05, 0x00}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, _ := NewDecompressor(CodecDeflate)
+			_, err := d.Decompress(tc.payload)
+			if err == nil {
+				t.Fatal("Decompress succeeded, want error")
+			}
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnsupportedCodec(t *testing.T) {
+	if _, err := NewCompressor(CodecNone); !errors.Is(err, ErrUnsupportedCodec) {
+		t.Errorf("NewCompressor(CodecNone) err = %v", err)
+	}
+	if _, err := NewDecompressor(Codec(0x7F)); !errors.Is(err, ErrUnsupportedCodec) {
+		t.Errorf("NewDecompressor(0x7F) err = %v", err)
+	}
+}
+
+func TestNegotiateCodec(t *testing.T) {
+	tests := []struct {
+		offered []Codec
+		want    Codec
+	}{
+		{nil, CodecNone},
+		{[]Codec{CodecDeflate}, CodecDeflate},
+		{[]Codec{Codec(0x7F), CodecDeflate}, CodecDeflate},
+		{[]Codec{Codec(0x7F)}, CodecNone},
+	}
+	for _, tc := range tests {
+		if got := NegotiateCodec(tc.offered); got != tc.want {
+			t.Errorf("NegotiateCodec(%v) = %v, want %v", tc.offered, got, tc.want)
+		}
+	}
+}
@@ -4,14 +4,21 @@ package protocol
 type ControlType uint8
 
 const (
-	ControlPing          ControlType = 0x01 // Client/server ping
-	ControlPong          ControlType = 0x02 // Response to ping
-	ControlResyncRequest ControlType = 0x10 // Client requests missed patches
-	ControlResyncPatches ControlType = 0x11 // Server sends missed patches
-	ControlResyncFull    ControlType = 0x12 // Server sends full HTML reload
-	ControlHookRevert    ControlType = 0x30 // Server requests hook revert by HID
-	ControlAuthCommand   ControlType = 0x31 // Server auth command (reload, navigate, broadcast)
-	ControlClose         ControlType = 0x20 // Session close
+	ControlPing           ControlType = 0x01 // Client/server ping
+	ControlPong           ControlType = 0x02 // Response to ping
+	ControlResyncRequest  ControlType = 0x10 // Client requests missed patches
+	ControlResyncPatches  ControlType = 0x11 // Server sends missed patches
+	ControlResyncFull     ControlType = 0x12 // Server sends full HTML reload
+	ControlHookRevert     ControlType = 0x30 // Server requests hook revert by HID
+	ControlAuthCommand    ControlType = 0x31 // Server auth command (reload, navigate, broadcast)
+	ControlClose          ControlType = 0x20 // Session close
+	ControlBlobAck        ControlType = 0x40 // Server reports blob upload progress
+	ControlClientEnv      ControlType = 0x41 // Client reports a viewport or preference change
+	ControlPrefSync       ControlType = 0x42 // Either side reports changed user preferences
+	ControlGlobalListen   ControlType = 0x43 // Server subscribes to a window or document event
+	ControlGlobalUnlisten ControlType = 0x44 // Server ends a window or document subscription
+	ControlHookCall       ControlType = 0x45 // Server calls a method on a client hook
+	ControlHookResult     ControlType = 0x46 // Client returns the result of a hook call
 )
 
 // String returns the string representation of the control type.
@@ -33,6 +40,20 @@ func (ct ControlType) String() string {
 		return "AuthCommand"
 	case ControlClose:
 		return "Close"
+	case ControlBlobAck:
+		return "BlobAck"
+	case ControlClientEnv:
+		return "ClientEnv"
+	case ControlPrefSync:
+		return "PrefSync"
+	case ControlGlobalListen:
+		return "GlobalListen"
+	case ControlGlobalUnlisten:
+		return "GlobalUnlisten"
+	case ControlHookCall:
+		return "HookCall"
+	case ControlHookResult:
+		return "HookResult"
 	default:
 		return "Unknown"
 	}
@@ -114,6 +135,95 @@ type AuthCommand struct {
 	Type    string
 }
 
+// Client preference flags, reported in ClientHello.Prefs and ClientEnv.Prefs.
+const (
+	ClientPrefDark          uint8 = 0x01 // prefers-color-scheme: dark
+	ClientPrefLight         uint8 = 0x02 // prefers-color-scheme: light
+	ClientPrefReducedMotion uint8 = 0x04 // prefers-reduced-motion: reduce
+)
+
+// ClientEnv is sent by the client when its viewport, timezone, locale or
+// preferences change after the handshake. It carries the full current state.
+type ClientEnv struct {
+	ViewportW uint16
+	ViewportH uint16
+	TZOffset  int16 // Minutes east of UTC
+	Locale    string
+	Prefs     uint8 // ClientPref* flags
+}
+
+// PrefValue is one user preference as stored by the client and pkg/pref:
+// its key, its JSON-encoded value and when it was last written.
+type PrefValue struct {
+	Key       string
+	Value     string // JSON
+	UpdatedAt uint64 // Unix milliseconds
+}
+
+// PrefSync carries changed preference values. The client sends it when a
+// preference changes locally or in another tab; the server sends it when a
+// preference changes on the server so the client can store it.
+type PrefSync struct {
+	Values []PrefValue
+}
+
+// maxPrefValues bounds the preference values in one message.
+const maxPrefValues = 256
+
+// Global event targets, in GlobalListen.Target.
+const (
+	GlobalTargetWindow   uint8 = 0x01
+	GlobalTargetDocument uint8 = 0x02
+)
+
+// Global listener flags, in GlobalListen.Flags.
+const (
+	ListenPreventDefault  uint8 = 0x01
+	ListenStopPropagation uint8 = 0x02
+	ListenOnce            uint8 = 0x04
+	ListenPassive         uint8 = 0x08
+	ListenCapture         uint8 = 0x10
+)
+
+// GlobalListen subscribes the client to an event on window or document.
+// The client sends matching events with ID as their HID. Subscriptions with
+// the same target, event and capture flag share one DOM listener.
+type GlobalListen struct {
+	ID       string
+	Target   uint8  // GlobalTarget*
+	Event    string // DOM event name, e.g. "resize"
+	Flags    uint8  // Listen* flags
+	Debounce uint32 // Milliseconds
+	Throttle uint32 // Milliseconds
+
+	// Keyboard events are only sent for these keys, and if KeyMods is
+	// nonzero, only with exactly these modifiers held.
+	Keys    []string
+	KeyMods Modifiers
+}
+
+// GlobalUnlisten ends the subscription with the given ID.
+type GlobalUnlisten struct {
+	ID string
+}
+
+// HookCall asks the client to call a method on the hook instance at HID.
+// The client answers with a HookResult carrying the same ID.
+type HookCall struct {
+	ID     uint64
+	HID    string
+	Method string
+	Args   map[string]any // Hook values, as in HookEventData
+}
+
+// HookResult answers the HookCall with the same ID. Error is set when the
+// hook is missing, has no such method or the method failed.
+type HookResult struct {
+	ID     uint64
+	Error  string
+	Result any // Hook value, as in HookEventData
+}
+
 // EncodeControl encodes a control message to bytes.
 func EncodeControl(ct ControlType, payload any) []byte {
 	e := NewEncoder()
@@ -189,7 +299,110 @@ func EncodeControlTo(e *Encoder, ct ControlType, payload any) {
 			e.WriteByte(byte(CloseNormal))
 			e.WriteString("")
 		}
+
+	case ControlBlobAck:
+		if ba, ok := payload.(*BlobAck); ok {
+			e.WriteUvarint(ba.ID)
+			e.WriteUvarint(ba.Received)
+			e.WriteByte(byte(ba.Status))
+			e.WriteString(ba.Message)
+		} else {
+			e.WriteUvarint(0)
+			e.WriteUvarint(0)
+			e.WriteByte(byte(BlobStatusError))
+			e.WriteString("")
+		}
+
+	case ControlClientEnv:
+		ce, ok := payload.(*ClientEnv)
+		if !ok {
+			ce = &ClientEnv{}
+		}
+		e.WriteUint16(ce.ViewportW)
+		e.WriteUint16(ce.ViewportH)
+		e.WriteInt16(ce.TZOffset)
+		e.WriteString(ce.Locale)
+		e.WriteByte(ce.Prefs)
+
+	case ControlPrefSync:
+		var values []PrefValue
+		if ps, ok := payload.(*PrefSync); ok {
+			values = ps.Values
+		}
+		encodePrefValues(e, values)
+
+	case ControlGlobalListen:
+		gl, ok := payload.(*GlobalListen)
+		if !ok {
+			gl = &GlobalListen{}
+		}
+		e.WriteString(gl.ID)
+		e.WriteByte(gl.Target)
+		e.WriteString(gl.Event)
+		e.WriteByte(gl.Flags)
+		e.WriteUvarint(uint64(gl.Debounce))
+		e.WriteUvarint(uint64(gl.Throttle))
+		encodeStrings(e, gl.Keys)
+		e.WriteByte(byte(gl.KeyMods))
+
+	case ControlGlobalUnlisten:
+		if gu, ok := payload.(*GlobalUnlisten); ok {
+			e.WriteString(gu.ID)
+		} else {
+			e.WriteString("")
+		}
+
+	case ControlHookCall:
+		hc, ok := payload.(*HookCall)
+		if !ok {
+			hc = &HookCall{}
+		}
+		e.WriteUvarint(hc.ID)
+		e.WriteString(hc.HID)
+		e.WriteString(hc.Method)
+		encodeHookData(e, hc.Args)
+
+	case ControlHookResult:
+		hr, ok := payload.(*HookResult)
+		if !ok {
+			hr = &HookResult{}
+		}
+		e.WriteUvarint(hr.ID)
+		e.WriteString(hr.Error)
+		encodeHookValue(e, hr.Result)
+	}
+}
+
+func encodePrefValues(e *Encoder, values []PrefValue) {
+	e.WriteUvarint(uint64(len(values)))
+	for _, v := range values {
+		e.WriteString(v.Key)
+		e.WriteString(v.Value)
+		e.WriteUint64(v.UpdatedAt)
+	}
+}
+
+func decodePrefValues(d *Decoder) ([]PrefValue, error) {
+	count, err := d.ReadCollectionCount()
+	if err != nil {
+		return nil, err
+	}
+	if count > maxPrefValues {
+		return nil, ErrCollectionTooLarge
+	}
+	values := make([]PrefValue, count)
+	for i := range values {
+		if values[i].Key, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if values[i].Value, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if values[i].UpdatedAt, err = d.ReadUint64(); err != nil {
+			return nil, err
+		}
 	}
+	return values, nil
 }
 
 // DecodeControl decodes a control message from bytes.
@@ -310,6 +523,125 @@ func DecodeControlFrom(d *Decoder) (ControlType, any, error) {
 			Message: message,
 		}, nil
 
+	case ControlBlobAck:
+		id, err := d.ReadUvarint()
+		if err != nil {
+			return ct, nil, err
+		}
+		received, err := d.ReadUvarint()
+		if err != nil {
+			return ct, nil, err
+		}
+		status, err := d.ReadByte()
+		if err != nil {
+			return ct, nil, err
+		}
+		message, err := d.ReadString()
+		if err != nil {
+			return ct, nil, err
+		}
+		return ct, &BlobAck{
+			ID:       id,
+			Received: received,
+			Status:   BlobStatus(status),
+			Message:  message,
+		}, nil
+
+	case ControlClientEnv:
+		ce := &ClientEnv{}
+		if ce.ViewportW, err = d.ReadUint16(); err != nil {
+			return ct, nil, err
+		}
+		if ce.ViewportH, err = d.ReadUint16(); err != nil {
+			return ct, nil, err
+		}
+		if ce.TZOffset, err = d.ReadInt16(); err != nil {
+			return ct, nil, err
+		}
+		if ce.Locale, err = d.ReadString(); err != nil {
+			return ct, nil, err
+		}
+		if ce.Prefs, err = d.ReadByte(); err != nil {
+			return ct, nil, err
+		}
+		return ct, ce, nil
+
+	case ControlPrefSync:
+		values, err := decodePrefValues(d)
+		if err != nil {
+			return ct, nil, err
+		}
+		return ct, &PrefSync{Values: values}, nil
+
+	case ControlGlobalListen:
+		gl := &GlobalListen{}
+		if gl.ID, err = d.ReadString(); err != nil {
+			return ct, nil, err
+		}
+		if gl.Target, err = d.ReadByte(); err != nil {
+			return ct, nil, err
+		}
+		if gl.Event, err = d.ReadString(); err != nil {
+			return ct, nil, err
+		}
+		if gl.Flags, err = d.ReadByte(); err != nil {
+			return ct, nil, err
+		}
+		debounce, err := d.ReadUvarint()
+		if err != nil {
+			return ct, nil, err
+		}
+		throttle, err := d.ReadUvarint()
+		if err != nil {
+			return ct, nil, err
+		}
+		gl.Debounce, gl.Throttle = uint32(debounce), uint32(throttle)
+		if gl.Keys, err = decodeStrings(d); err != nil {
+			return ct, nil, err
+		}
+		mods, err := d.ReadByte()
+		if err != nil {
+			return ct, nil, err
+		}
+		gl.KeyMods = Modifiers(mods)
+		return ct, gl, nil
+
+	case ControlGlobalUnlisten:
+		id, err := d.ReadString()
+		if err != nil {
+			return ct, nil, err
+		}
+		return ct, &GlobalUnlisten{ID: id}, nil
+
+	case ControlHookCall:
+		hc := &HookCall{}
+		if hc.ID, err = d.ReadUvarint(); err != nil {
+			return ct, nil, err
+		}
+		if hc.HID, err = d.ReadString(); err != nil {
+			return ct, nil, err
+		}
+		if hc.Method, err = d.ReadString(); err != nil {
+			return ct, nil, err
+		}
+		if hc.Args, err = decodeHookData(d); err != nil {
+			return ct, nil, err
+		}
+		return ct, hc, nil
+
+	case ControlHookResult:
+		hr := &HookResult{}
+		if hr.ID, err = d.ReadUvarint(); err != nil {
+			return ct, nil, err
+		}
+		if hr.Error, err = d.ReadString(); err != nil {
+			return ct, nil, err
+		}
+		if hr.Result, err = decodeHookValue(d); err != nil {
+			return ct, nil, err
+		}
+		return ct, hr, nil
+
 	default:
 		return ct, nil, nil
 	}
@@ -361,3 +693,33 @@ func NewAuthCommand(cmd *AuthCommand) (ControlType, *AuthCommand) {
 func NewClose(reason CloseReason, message string) (ControlType, *CloseMessage) {
 	return ControlClose, &CloseMessage{Reason: reason, Message: message}
 }
+
+// NewClientEnv creates a new ClientEnv message.
+func NewClientEnv(env *ClientEnv) (ControlType, *ClientEnv) {
+	return ControlClientEnv, env
+}
+
+// NewPrefSync creates a new PrefSync message.
+func NewPrefSync(values []PrefValue) (ControlType, *PrefSync) {
+	return ControlPrefSync, &PrefSync{Values: values}
+}
+
+// NewGlobalListen creates a new GlobalListen message.
+func NewGlobalListen(gl *GlobalListen) (ControlType, *GlobalListen) {
+	return ControlGlobalListen, gl
+}
+
+// NewGlobalUnlisten creates a new GlobalUnlisten message.
+func NewGlobalUnlisten(id string) (ControlType, *GlobalUnlisten) {
+	return ControlGlobalUnlisten, &GlobalUnlisten{ID: id}
+}
+
+// NewHookCall creates a new HookCall message.
+func NewHookCall(id uint64, hid, method string, args map[string]any) (ControlType, *HookCall) {
+	return ControlHookCall, &HookCall{ID: id, HID: hid, Method: method, Args: args}
+}
+
+// NewHookResult creates a new HookResult message.
+func NewHookResult(id uint64, result any, errMsg string) (ControlType, *HookResult) {
+	return ControlHookResult, &HookResult{ID: id, Result: result, Error: errMsg}
+}
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -72,6 +73,66 @@ func TestControlEncodeDecode(t *testing.T) {
 				Type:    "expired",
 			},
 		},
+		{
+			name: "client_env",
+			ct:   ControlClientEnv,
+			payload: &ClientEnv{
+				ViewportW: 1024,
+				ViewportH: 768,
+				TZOffset:  -300,
+				Locale:    "en-US",
+				Prefs:     ClientPrefLight,
+			},
+		},
+		{
+			name: "pref_sync",
+			ct:   ControlPrefSync,
+			payload: &PrefSync{Values: []PrefValue{
+				{Key: "theme", Value: `"dark"`, UpdatedAt: 1702000000000},
+			}},
+		},
+		{
+			name: "global_listen",
+			ct:   ControlGlobalListen,
+			payload: &GlobalListen{
+				ID:       "g3",
+				Target:   GlobalTargetDocument,
+				Event:    "keydown",
+				Flags:    ListenPreventDefault | ListenOnce,
+				Debounce: 200,
+				Throttle: 70000,
+				Keys:     []string{"k"},
+				KeyMods:  ModMeta,
+			},
+		},
+		{
+			name:    "global_unlisten",
+			ct:      ControlGlobalUnlisten,
+			payload: &GlobalUnlisten{ID: "g3"},
+		},
+		{
+			name: "hook_call",
+			ct:   ControlHookCall,
+			payload: &HookCall{
+				ID:     300,
+				HID:    "h12",
+				Method: "getSelection",
+				Args:   map[string]any{"trim": true, "max": int64(80)},
+			},
+		},
+		{
+			name: "hook_result",
+			ct:   ControlHookResult,
+			payload: &HookResult{
+				ID:     300,
+				Result: map[string]any{"text": "hello", "range": []any{int64(0), 5.5}},
+			},
+		},
+		{
+			name:    "hook_result_error",
+			ct:      ControlHookResult,
+			payload: &HookResult{ID: 301, Error: "no method"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -168,6 +229,31 @@ func verifyControlPayload(t *testing.T, _ string, got, want any) {
 		if g.Type != w.Type {
 			t.Errorf("Type = %q, want %q", g.Type, w.Type)
 		}
+
+	case *ClientEnv:
+		g, ok := got.(*ClientEnv)
+		if !ok {
+			t.Errorf("Payload type = %T, want *ClientEnv", got)
+			return
+		}
+		if *g != *w {
+			t.Errorf("ClientEnv = %+v, want %+v", *g, *w)
+		}
+
+	case *PrefSync:
+		g, ok := got.(*PrefSync)
+		if !ok {
+			t.Errorf("Payload type = %T, want *PrefSync", got)
+			return
+		}
+		if !reflect.DeepEqual(g, w) {
+			t.Errorf("PrefSync = %+v, want %+v", *g, *w)
+		}
+
+	case *GlobalListen, *GlobalUnlisten, *HookCall, *HookResult:
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Payload = %+v, want %+v", got, want)
+		}
 	}
 }
 
@@ -183,6 +269,12 @@ func TestControlTypeString(t *testing.T) {
 		{ControlResyncFull, "ResyncFull"},
 		{ControlAuthCommand, "AuthCommand"},
 		{ControlClose, "Close"},
+		{ControlClientEnv, "ClientEnv"},
+		{ControlPrefSync, "PrefSync"},
+		{ControlGlobalListen, "GlobalListen"},
+		{ControlGlobalUnlisten, "GlobalUnlisten"},
+		{ControlHookCall, "HookCall"},
+		{ControlHookResult, "HookResult"},
 		{ControlType(0xFF), "Unknown"},
 	}
 
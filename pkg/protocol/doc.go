@@ -75,6 +75,35 @@
 //
 // Error ServerHello messages may include an optional auth-expired reason byte.
 //
+// # Compression
+//
+// Clients list the payload codecs they can decode in ClientHello.Codecs. The
+// server picks one with NegotiateCodec and records it in ServerHello.Flags
+// (see ServerHello.SetCodec). Frames whose payload was compressed carry
+// FlagCompressed; the server only compresses patch frames at or above its
+// configured threshold.
+//
+// A message is compressed whole before it is fragmented, so its fragments
+// all carry FlagCompressed and the receiver decompresses the reassembled
+// payload. Decompressed payloads are limited to MaxCompressiblePayload.
+//
+// CodecDeflate keeps one DEFLATE stream per connection and sync-flushes it
+// after every frame, so later frames reuse the earlier frames' window as a
+// shared dictionary:
+//
+//	[RawLength: varint][DEFLATE data ending in 00 00 FF FF]
+//
+// Compressed frames must be decompressed in order, and the stream restarts
+// with every handshake.
+//
+// # Fragmentation
+//
+// A message whose payload exceeds MaxPayloadSize is split into fragments
+// (see Frame.Fragments). Each fragment keeps the message's type and flags
+// and adds FlagFragment; the last one also carries FlagFinal. Frames of
+// other types may be interleaved with the fragments, and the receiver joins
+// them with a Reassembler, which caps the total bytes it will buffer.
+//
 // # Control Messages
 //
 //   - Ping/Pong: Heartbeat for connection health
@@ -10,15 +10,16 @@ type EventType uint8
 
 // Event type constants.
 const (
-	// Mouse events (0x01-0x08)
-	EventClick      EventType = 0x01
-	EventDblClick   EventType = 0x02
-	EventMouseDown  EventType = 0x03
-	EventMouseUp    EventType = 0x04
-	EventMouseMove  EventType = 0x05
-	EventMouseEnter EventType = 0x06
-	EventMouseLeave EventType = 0x07
-	EventWheel      EventType = 0x08 // Mouse wheel event
+	// Mouse events (0x01-0x09)
+	EventClick       EventType = 0x01
+	EventDblClick    EventType = 0x02
+	EventMouseDown   EventType = 0x03
+	EventMouseUp     EventType = 0x04
+	EventMouseMove   EventType = 0x05
+	EventMouseEnter  EventType = 0x06
+	EventMouseLeave  EventType = 0x07
+	EventWheel       EventType = 0x08 // Mouse wheel event
+	EventContextMenu EventType = 0x09
 
 	// Form events (0x10-0x14)
 	EventInput  EventType = 0x10
@@ -58,10 +59,57 @@ const (
 	EventTransitionRun    EventType = 0x59
 	EventTransitionCancel EventType = 0x5A
 
+	// More drag events (0x5B-0x5E)
+	EventDragEnter EventType = 0x5B
+	EventDragOver  EventType = 0x5C
+	EventDragLeave EventType = 0x5D
+	EventDrag      EventType = 0x5E
+
 	// Special events (0x60+)
 	EventHook     EventType = 0x60 // Client hook event
+	EventIsland   EventType = 0x61 // Message from a JS island
 	EventNavigate EventType = 0x70 // Navigation request
-	EventCustom   EventType = 0xFF // Custom event
+
+	// Pointer events (0x80-0x85)
+	EventPointerDown   EventType = 0x80
+	EventPointerUp     EventType = 0x81
+	EventPointerMove   EventType = 0x82
+	EventPointerEnter  EventType = 0x83
+	EventPointerLeave  EventType = 0x84
+	EventPointerCancel EventType = 0x85
+
+	// Clipboard events (0x90-0x92)
+	EventCopy  EventType = 0x90
+	EventCut   EventType = 0x91
+	EventPaste EventType = 0x92
+
+	// Toggle event (0x98) from details and popover elements
+	EventToggle EventType = 0x98
+
+	// Media events (0xA0-0xAE)
+	EventPlay           EventType = 0xA0
+	EventPause          EventType = 0xA1
+	EventEnded          EventType = 0xA2
+	EventTimeUpdate     EventType = 0xA3
+	EventSeeking        EventType = 0xA4
+	EventSeeked         EventType = 0xA5
+	EventVolumeChange   EventType = 0xA6
+	EventRateChange     EventType = 0xA7
+	EventDurationChange EventType = 0xA8
+	EventLoadedMetadata EventType = 0xA9
+	EventLoadedData     EventType = 0xAA
+	EventCanPlay        EventType = 0xAB
+	EventCanPlayThrough EventType = 0xAC
+	EventWaiting        EventType = 0xAD
+	EventPlaying        EventType = 0xAE
+
+	// Page events (0xB0-0xB3), sent for window and document subscriptions
+	EventVisibilityChange EventType = 0xB0
+	EventOnline           EventType = 0xB1
+	EventOffline          EventType = 0xB2
+	EventBeforeUnload     EventType = 0xB3
+
+	EventCustom EventType = 0xFF // Custom event
 )
 
 // String returns the string representation of the event type.
@@ -81,6 +129,8 @@ func (et EventType) String() string {
 		return "MouseEnter"
 	case EventMouseLeave:
 		return "MouseLeave"
+	case EventContextMenu:
+		return "ContextMenu"
 	case EventInput:
 		return "Input"
 	case EventChange:
@@ -113,6 +163,14 @@ func (et EventType) String() string {
 		return "DragEnd"
 	case EventDrop:
 		return "Drop"
+	case EventDragEnter:
+		return "DragEnter"
+	case EventDragOver:
+		return "DragOver"
+	case EventDragLeave:
+		return "DragLeave"
+	case EventDrag:
+		return "Drag"
 	case EventWheel:
 		return "Wheel"
 	case EventAnimationStart:
@@ -133,8 +191,68 @@ func (et EventType) String() string {
 		return "TransitionCancel"
 	case EventHook:
 		return "Hook"
+	case EventIsland:
+		return "Island"
 	case EventNavigate:
 		return "Navigate"
+	case EventPointerDown:
+		return "PointerDown"
+	case EventPointerUp:
+		return "PointerUp"
+	case EventPointerMove:
+		return "PointerMove"
+	case EventPointerEnter:
+		return "PointerEnter"
+	case EventPointerLeave:
+		return "PointerLeave"
+	case EventPointerCancel:
+		return "PointerCancel"
+	case EventPlay:
+		return "Play"
+	case EventPause:
+		return "Pause"
+	case EventEnded:
+		return "Ended"
+	case EventTimeUpdate:
+		return "TimeUpdate"
+	case EventSeeking:
+		return "Seeking"
+	case EventSeeked:
+		return "Seeked"
+	case EventVolumeChange:
+		return "VolumeChange"
+	case EventRateChange:
+		return "RateChange"
+	case EventDurationChange:
+		return "DurationChange"
+	case EventLoadedMetadata:
+		return "LoadedMetadata"
+	case EventLoadedData:
+		return "LoadedData"
+	case EventCanPlay:
+		return "CanPlay"
+	case EventCanPlayThrough:
+		return "CanPlayThrough"
+	case EventWaiting:
+		return "Waiting"
+	case EventPlaying:
+		return "Playing"
+	case EventCopy:
+		return "Copy"
+	case EventCut:
+		return "Cut"
+	case EventPaste:
+		return "Paste"
+	case EventToggle:
+		return "Toggle"
+	case EventVisibilityChange:
+		return "VisibilityChange"
+	case EventOnline:
+		return "Online"
+	case EventOffline:
+		return "Offline"
+	case EventBeforeUnload:
+		return "BeforeUnload"
 	case EventCustom:
 		return "Custom"
 	default:
@@ -206,8 +324,10 @@ type ScrollEventData struct {
 }
 
 // SubmitEventData contains form submission data.
+// Fields holds every submitted value per field name, in document order, so
+// multi-selects, checkbox groups and repeated inputs keep all their values.
 type SubmitEventData struct {
-	Fields map[string]string
+	Fields map[string][]string
 }
 
 // ResizeEventData contains resize event data.
@@ -246,6 +366,60 @@ type TransitionEventData struct {
 	PseudoElement string
 }
 
+// PointerEventData contains pointer event data.
+type PointerEventData struct {
+	MouseEventData
+	PointerID   int
+	PointerType string  // "mouse", "pen" or "touch"
+	Pressure    float64 // Normalized pressure from 0 to 1
+	Width       float64 // Contact geometry in pixels
+	Height      float64
+	TiltX       int     // Pen tilt in degrees
+	TiltY       int
+	IsPrimary   bool
+}
+
+// FileInfo describes a dragged or pasted file. Contents are not sent.
+type FileInfo struct {
+	Name string
+	Type string // MIME type
+	Size int64
+}
+
+// DragEventData contains drag-and-drop event data.
+// Data holds the readable data transfer entries and Files the dropped
+// files; browsers only expose both on drop.
+type DragEventData struct {
+	MouseEventData
+	Types []string          // Data transfer formats
+	Files []FileInfo
+	Data  map[string]string // Format to data, e.g. "text/plain"
+}
+
+// MediaEventData contains media element state at the time of the event.
+type MediaEventData struct {
+	CurrentTime  float64 // Seconds
+	Duration     float64 // Seconds; NaN is sent as 0
+	Paused       bool
+	Ended        bool
+	Volume       float64
+	Muted        bool
+	PlaybackRate float64
+}
+
+// ClipboardEventData contains clipboard event data.
+// Text and Files are only populated on paste.
+type ClipboardEventData struct {
+	Text  string
+	Types []string
+	Files []FileInfo
+}
+
+// ToggleEventData contains toggle event data.
+type ToggleEventData struct {
+	Open bool
+}
+
 // HookValueType identifies the type of a hook data value.
 type HookValueType uint8
 
@@ -265,6 +439,12 @@ type HookEventData struct {
 	Data map[string]any
 }
 
+// IslandEventData contains a message sent by a JS island.
+// The island ID is carried in Event.HID.
+type IslandEventData struct {
+	Data map[string]any
+}
+
 // NavigateEventData contains navigation event data.
 type NavigateEventData struct {
 	Path    string
@@ -310,7 +490,7 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 		EventMouseEnter, EventMouseLeave:
 		// No payload
 
-	case EventInput, EventChange:
+	case EventInput, EventChange, EventVisibilityChange:
 		// String payload
 		if s, ok := e.Payload.(string); ok {
 			enc.WriteString(s)
@@ -323,10 +503,18 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 		if !ok || data == nil {
 			enc.WriteUvarint(0)
 		} else {
-			enc.WriteUvarint(uint64(len(data.Fields)))
-			for k, v := range data.Fields {
-				enc.WriteString(k)
-				enc.WriteString(v)
+			// Encoded as a flat list of (name, value) pairs; repeated values
+			// for the same name are written as repeated pairs.
+			count := 0
+			for _, vals := range data.Fields {
+				count += len(vals)
+			}
+			enc.WriteUvarint(uint64(count))
+			for k, vals := range data.Fields {
+				for _, v := range vals {
+					enc.WriteString(k)
+					enc.WriteString(v)
+				}
 			}
 		}
 
@@ -346,7 +534,7 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 			enc.WriteByte(data.Location)
 		}
 
-	case EventMouseDown, EventMouseUp, EventMouseMove:
+	case EventMouseDown, EventMouseUp, EventMouseMove, EventContextMenu:
 		data, ok := e.Payload.(*MouseEventData)
 		if !ok || data == nil {
 			enc.WriteSvarint(0)
@@ -446,22 +634,79 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 			}
 		}
 
-	case EventDragStart, EventDragEnd, EventDrop:
-		// Similar to mouse events - include position and modifiers
-		data, ok := e.Payload.(*MouseEventData)
+	case EventDragStart, EventDragEnd, EventDrop,
+		EventDragEnter, EventDragOver, EventDragLeave, EventDrag:
+		// Position and modifiers, followed by the data transfer. A bare
+		// *MouseEventData is accepted and sent without data transfer.
+		var data *DragEventData
+		switch p := e.Payload.(type) {
+		case *DragEventData:
+			data = p
+		case *MouseEventData:
+			if p != nil {
+				data = &DragEventData{MouseEventData: *p}
+			}
+		}
+		if data == nil {
+			data = &DragEventData{}
+		}
+		enc.WriteSvarint(int64(data.ClientX))
+		enc.WriteSvarint(int64(data.ClientY))
+		enc.WriteSvarint(int64(data.PageX))
+		enc.WriteSvarint(int64(data.PageY))
+		enc.WriteByte(byte(data.Modifiers))
+		encodeStrings(enc, data.Types)
+		encodeFiles(enc, data.Files)
+		enc.WriteUvarint(uint64(len(data.Data)))
+		for k, v := range data.Data {
+			enc.WriteString(k)
+			enc.WriteString(v)
+		}
+
+	case EventPointerDown, EventPointerUp, EventPointerMove,
+		EventPointerEnter, EventPointerLeave, EventPointerCancel:
+		data, ok := e.Payload.(*PointerEventData)
 		if !ok || data == nil {
-			enc.WriteSvarint(0)
-			enc.WriteSvarint(0)
-			enc.WriteSvarint(0)
-			enc.WriteSvarint(0)
-			enc.WriteByte(0)
-		} else {
-			enc.WriteSvarint(int64(data.ClientX))
-			enc.WriteSvarint(int64(data.ClientY))
-			enc.WriteSvarint(int64(data.PageX))
-			enc.WriteSvarint(int64(data.PageY))
-			enc.WriteByte(byte(data.Modifiers))
+			data = &PointerEventData{}
+		}
+		encodeMouseData(enc, &data.MouseEventData)
+		enc.WriteSvarint(int64(data.PointerID))
+		enc.WriteString(data.PointerType)
+		enc.WriteFloat64(data.Pressure)
+		enc.WriteFloat64(data.Width)
+		enc.WriteFloat64(data.Height)
+		enc.WriteSvarint(int64(data.TiltX))
+		enc.WriteSvarint(int64(data.TiltY))
+		enc.WriteBool(data.IsPrimary)
+
+	case EventPlay, EventPause, EventEnded, EventTimeUpdate,
+		EventSeeking, EventSeeked, EventVolumeChange, EventRateChange,
+		EventDurationChange, EventLoadedMetadata, EventLoadedData,
+		EventCanPlay, EventCanPlayThrough, EventWaiting, EventPlaying:
+		data, ok := e.Payload.(*MediaEventData)
+		if !ok || data == nil {
+			data = &MediaEventData{}
+		}
+		enc.WriteFloat64(data.CurrentTime)
+		enc.WriteFloat64(data.Duration)
+		enc.WriteBool(data.Paused)
+		enc.WriteBool(data.Ended)
+		enc.WriteFloat64(data.Volume)
+		enc.WriteBool(data.Muted)
+		enc.WriteFloat64(data.PlaybackRate)
+
+	case EventCopy, EventCut, EventPaste:
+		data, ok := e.Payload.(*ClipboardEventData)
+		if !ok || data == nil {
+			data = &ClipboardEventData{}
 		}
+		enc.WriteString(data.Text)
+		encodeStrings(enc, data.Types)
+		encodeFiles(enc, data.Files)
+
+	case EventToggle:
+		data, ok := e.Payload.(*ToggleEventData)
+		enc.WriteBool(ok && data != nil && data.Open)
 
 	case EventAnimationStart, EventAnimationEnd, EventAnimationIteration, EventAnimationCancel:
 		data, ok := e.Payload.(*AnimationEventData)
@@ -497,6 +742,14 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 			encodeHookData(enc, data.Data)
 		}
 
+	case EventIsland:
+		data, ok := e.Payload.(*IslandEventData)
+		if !ok || data == nil {
+			enc.WriteUvarint(0)
+		} else {
+			encodeHookData(enc, data.Data)
+		}
+
 	case EventNavigate:
 		data, ok := e.Payload.(*NavigateEventData)
 		if !ok || data == nil {
@@ -519,6 +772,38 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 	}
 }
 
+// encodeMouseData encodes the full mouse payload shared by mouse and
+// pointer events.
+func encodeMouseData(enc *Encoder, data *MouseEventData) {
+	enc.WriteSvarint(int64(data.ClientX))
+	enc.WriteSvarint(int64(data.ClientY))
+	enc.WriteSvarint(int64(data.PageX))
+	enc.WriteSvarint(int64(data.PageY))
+	enc.WriteSvarint(int64(data.OffsetX))
+	enc.WriteSvarint(int64(data.OffsetY))
+	enc.WriteByte(data.Button)
+	enc.WriteByte(data.Buttons)
+	enc.WriteByte(byte(data.Modifiers))
+}
+
+// encodeStrings encodes a string list.
+func encodeStrings(enc *Encoder, list []string) {
+	enc.WriteUvarint(uint64(len(list)))
+	for _, s := range list {
+		enc.WriteString(s)
+	}
+}
+
+// encodeFiles encodes file metadata.
+func encodeFiles(enc *Encoder, files []FileInfo) {
+	enc.WriteUvarint(uint64(len(files)))
+	for _, f := range files {
+		enc.WriteString(f.Name)
+		enc.WriteString(f.Type)
+		enc.WriteUvarint(uint64(f.Size))
+	}
+}
+
 // encodeHookData encodes hook event data map.
 func encodeHookData(enc *Encoder, data map[string]any) {
 	enc.WriteUvarint(uint64(len(data)))
@@ -603,7 +888,7 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 		EventMouseEnter, EventMouseLeave:
 		// No payload
 
-	case EventInput, EventChange:
+	case EventInput, EventChange, EventVisibilityChange:
 		s, err := d.ReadString()
 		if err != nil {
 			return nil, err
@@ -615,7 +900,7 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 		if err != nil {
 			return nil, err
 		}
-		fields := make(map[string]string, count)
+		fields := make(map[string][]string, count)
 		for i := 0; i < count; i++ {
 			k, err := d.ReadString()
 			if err != nil {
@@ -625,7 +910,7 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 			if err != nil {
 				return nil, err
 			}
-			fields[k] = v
+			fields[k] = append(fields[k], v)
 		}
 		e.Payload = &SubmitEventData{Fields: fields}
 
@@ -658,7 +943,7 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 			Location:  location,
 		}
 
-	case EventMouseDown, EventMouseUp, EventMouseMove:
+	case EventMouseDown, EventMouseUp, EventMouseMove, EventContextMenu:
 		x, err := d.ReadSvarint()
 		if err != nil {
 			return nil, err
@@ -907,13 +1192,129 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 		if err != nil {
 			return nil, err
 		}
-		e.Payload = &MouseEventData{
-			ClientX:   int(x),
-			ClientY:   int(y),
-			PageX:     int(pageX),
-			PageY:     int(pageY),
-			Modifiers: Modifiers(mods),
+		data := &DragEventData{
+			MouseEventData: MouseEventData{
+				ClientX:   int(x),
+				ClientY:   int(y),
+				PageX:     int(pageX),
+				PageY:     int(pageY),
+				Modifiers: Modifiers(mods),
+			},
+		}
+		// Clients before pointer and media support sent no data transfer.
+		if !d.EOF() {
+			if data.Types, err = decodeStrings(d); err != nil {
+				return nil, err
+			}
+			if data.Files, err = decodeFiles(d); err != nil {
+				return nil, err
+			}
+			count, err := d.ReadCollectionCount()
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				data.Data = make(map[string]string, count)
+			}
+			for i := 0; i < count; i++ {
+				k, err := d.ReadString()
+				if err != nil {
+					return nil, err
+				}
+				v, err := d.ReadString()
+				if err != nil {
+					return nil, err
+				}
+				data.Data[k] = v
+			}
+		}
+		e.Payload = data
+
+	case EventPointerDown, EventPointerUp, EventPointerMove,
+		EventPointerEnter, EventPointerLeave, EventPointerCancel:
+		mouse, err := decodeMouseData(d)
+		if err != nil {
+			return nil, err
+		}
+		data := &PointerEventData{MouseEventData: *mouse}
+		id, err := d.ReadSvarint()
+		if err != nil {
+			return nil, err
+		}
+		data.PointerID = int(id)
+		if data.PointerType, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if data.Pressure, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		if data.Width, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		if data.Height, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		tiltX, err := d.ReadSvarint()
+		if err != nil {
+			return nil, err
 		}
+		tiltY, err := d.ReadSvarint()
+		if err != nil {
+			return nil, err
+		}
+		data.TiltX, data.TiltY = int(tiltX), int(tiltY)
+		if data.IsPrimary, err = d.ReadBool(); err != nil {
+			return nil, err
+		}
+		e.Payload = data
+
+	case EventPlay, EventPause, EventEnded, EventTimeUpdate,
+		EventSeeking, EventSeeked, EventVolumeChange, EventRateChange,
+		EventDurationChange, EventLoadedMetadata, EventLoadedData,
+		EventCanPlay, EventCanPlayThrough, EventWaiting, EventPlaying:
+		data := &MediaEventData{}
+		if data.CurrentTime, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		if data.Duration, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		if data.Paused, err = d.ReadBool(); err != nil {
+			return nil, err
+		}
+		if data.Ended, err = d.ReadBool(); err != nil {
+			return nil, err
+		}
+		if data.Volume, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		if data.Muted, err = d.ReadBool(); err != nil {
+			return nil, err
+		}
+		if data.PlaybackRate, err = d.ReadFloat64(); err != nil {
+			return nil, err
+		}
+		e.Payload = data
+
+	case EventCopy, EventCut, EventPaste:
+		data := &ClipboardEventData{}
+		if data.Text, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if data.Types, err = decodeStrings(d); err != nil {
+			return nil, err
+		}
+		if data.Files, err = decodeFiles(d); err != nil {
+			return nil, err
+		}
+		e.Payload = data
+
+	case EventToggle:
+		open, err := d.ReadBool()
+		if err != nil {
+			return nil, err
+		}
+		e.Payload = &ToggleEventData{Open: open}
 
 	case EventAnimationStart, EventAnimationEnd, EventAnimationIteration, EventAnimationCancel:
 		animName, err := d.ReadString()
@@ -964,6 +1365,13 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 		}
 		e.Payload = &HookEventData{Name: name, Data: data}
 
+	case EventIsland:
+		data, err := decodeHookData(d)
+		if err != nil {
+			return nil, err
+		}
+		e.Payload = &IslandEventData{Data: data}
+
 	case EventNavigate:
 		path, err := d.ReadString()
 		if err != nil {
@@ -994,6 +1402,76 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 	return e, nil
 }
 
+// decodeMouseData decodes the full mouse payload shared by mouse and
+// pointer events.
+func decodeMouseData(d *Decoder) (*MouseEventData, error) {
+	var coords [6]int64
+	for i := range coords {
+		v, err := d.ReadSvarint()
+		if err != nil {
+			return nil, err
+		}
+		coords[i] = v
+	}
+	var flags [3]byte
+	for i := range flags {
+		b, err := d.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		flags[i] = b
+	}
+	return &MouseEventData{
+		ClientX:   int(coords[0]),
+		ClientY:   int(coords[1]),
+		PageX:     int(coords[2]),
+		PageY:     int(coords[3]),
+		OffsetX:   int(coords[4]),
+		OffsetY:   int(coords[5]),
+		Button:    flags[0],
+		Buttons:   flags[1],
+		Modifiers: Modifiers(flags[2]),
+	}, nil
+}
+
+// decodeStrings decodes a string list.
+func decodeStrings(d *Decoder) ([]string, error) {
+	count, err := d.ReadCollectionCount()
+	if err != nil || count == 0 {
+		return nil, err
+	}
+	list := make([]string, count)
+	for i := range list {
+		if list[i], err = d.ReadString(); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// decodeFiles decodes file metadata.
+func decodeFiles(d *Decoder) ([]FileInfo, error) {
+	count, err := d.ReadCollectionCount()
+	if err != nil || count == 0 {
+		return nil, err
+	}
+	files := make([]FileInfo, count)
+	for i := range files {
+		if files[i].Name, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if files[i].Type, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		size, err := d.ReadUvarint()
+		if err != nil {
+			return nil, err
+		}
+		files[i].Size = int64(size)
+	}
+	return files, nil
+}
+
 // decodeHookData decodes hook event data map.
 func decodeHookData(d *Decoder) (map[string]any, error) {
 	count, err := d.ReadCollectionCount()
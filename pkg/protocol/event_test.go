@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -50,9 +51,10 @@ func TestEventEncodeDecode(t *testing.T) {
 				Type: EventSubmit,
 				HID:  "h7",
 				Payload: &SubmitEventData{
-					Fields: map[string]string{
-						"name":  "John",
-						"email": "john@example.com",
+					Fields: map[string][]string{
+						"name":   {"John"},
+						"email":  {"john@example.com"},
+						"tags[]": {"go", "web", ""},
 					},
 				},
 			},
@@ -153,13 +155,111 @@ func TestEventEncodeDecode(t *testing.T) {
 				Seq:  13,
 				Type: EventDragStart,
 				HID:  "h15",
-				Payload: &MouseEventData{
-					ClientX:   150,
-					ClientY:   250,
-					Modifiers: ModShift,
+				Payload: &DragEventData{
+					MouseEventData: MouseEventData{
+						ClientX:   150,
+						ClientY:   250,
+						Modifiers: ModShift,
+					},
+				},
+			},
+		},
+		{
+			name: "drop",
+			event: &Event{
+				Seq:  19,
+				Type: EventDrop,
+				HID:  "h20",
+				Payload: &DragEventData{
+					MouseEventData: MouseEventData{ClientX: 5, ClientY: 6, PageX: 7, PageY: 8},
+					Types:          []string{"text/plain", "Files"},
+					Files:          []FileInfo{{Name: "a.png", Type: "image/png", Size: 1 << 33}},
+					Data:           map[string]string{"text/plain": "hello"},
+				},
+			},
+		},
+		{
+			name: "contextmenu",
+			event: &Event{
+				Seq:     20,
+				Type:    EventContextMenu,
+				HID:     "h21",
+				Payload: &MouseEventData{ClientX: 10, ClientY: 20, Button: 2, Modifiers: ModCtrl},
+			},
+		},
+		{
+			name: "pointermove",
+			event: &Event{
+				Seq:  21,
+				Type: EventPointerMove,
+				HID:  "h22",
+				Payload: &PointerEventData{
+					MouseEventData: MouseEventData{ClientX: -3, ClientY: 4, OffsetX: 1, Buttons: 1},
+					PointerID:      7,
+					PointerType:    "pen",
+					Pressure:       0.5,
+					Width:          1.5,
+					Height:         2.5,
+					TiltX:          -30,
+					TiltY:          45,
+					IsPrimary:      true,
 				},
 			},
 		},
+		{
+			name: "timeupdate",
+			event: &Event{
+				Seq:  22,
+				Type: EventTimeUpdate,
+				HID:  "h23",
+				Payload: &MediaEventData{
+					CurrentTime:  12.25,
+					Duration:     300,
+					Muted:        true,
+					Volume:       0.8,
+					PlaybackRate: 1.5,
+				},
+			},
+		},
+		{
+			name: "paste",
+			event: &Event{
+				Seq:  23,
+				Type: EventPaste,
+				HID:  "h24",
+				Payload: &ClipboardEventData{
+					Text:  "pasted",
+					Types: []string{"text/plain"},
+					Files: []FileInfo{{Name: "b.txt", Type: "text/plain", Size: 3}},
+				},
+			},
+		},
+		{
+			name: "toggle",
+			event: &Event{
+				Seq:     24,
+				Type:    EventToggle,
+				HID:     "h25",
+				Payload: &ToggleEventData{Open: true},
+			},
+		},
+		{
+			name: "visibilitychange",
+			event: &Event{
+				Seq:     25,
+				Type:    EventVisibilityChange,
+				HID:     "g1",
+				Payload: "hidden",
+			},
+		},
+		{
+			name: "online",
+			event: &Event{
+				Seq:  26,
+				Type: EventOnline,
+				HID:  "g2",
+			},
+		},
 		{
 			name: "hook",
 			event: &Event{
@@ -176,6 +276,20 @@ func TestEventEncodeDecode(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "island",
+			event: &Event{
+				Seq:  15,
+				Type: EventIsland,
+				HID:  "sales-chart",
+				Payload: &IslandEventData{
+					Data: map[string]any{
+						"point": int64(4),
+						"label": "Q3",
+					},
+				},
+			},
+		},
 		{
 			name: "navigate",
 			event: &Event{
@@ -276,7 +390,7 @@ func verifyPayload(t *testing.T, _ string, got, want any) {
 			return
 		}
 		for k, v := range w.Fields {
-			if g.Fields[k] != v {
+			if !reflect.DeepEqual(g.Fields[k], v) {
 				t.Errorf("Field[%q] = %q, want %q", k, g.Fields[k], v)
 			}
 		}
@@ -361,6 +475,21 @@ func verifyPayload(t *testing.T, _ string, got, want any) {
 			t.Errorf("Data count = %d, want %d", len(g.Data), len(w.Data))
 		}
 
+	case *IslandEventData:
+		g, ok := got.(*IslandEventData)
+		if !ok {
+			t.Errorf("Payload type = %T, want *IslandEventData", got)
+			return
+		}
+		if len(g.Data) != len(w.Data) {
+			t.Errorf("Data count = %d, want %d", len(g.Data), len(w.Data))
+		}
+		for k, v := range w.Data {
+			if g.Data[k] != v {
+				t.Errorf("Data[%q] = %v, want %v", k, g.Data[k], v)
+			}
+		}
+
 	case *NavigateEventData:
 		g, ok := got.(*NavigateEventData)
 		if !ok {
@@ -374,6 +503,12 @@ func verifyPayload(t *testing.T, _ string, got, want any) {
 			t.Errorf("Replace = %v, want %v", g.Replace, w.Replace)
 		}
 
+	case *DragEventData, *PointerEventData, *MediaEventData,
+		*ClipboardEventData, *ToggleEventData:
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Payload = %+v, want %+v", got, want)
+		}
+
 	case *CustomEventData:
 		g, ok := got.(*CustomEventData)
 		if !ok {
@@ -417,7 +552,18 @@ func TestEventTypeString(t *testing.T) {
 		{EventDragStart, "DragStart"},
 		{EventDragEnd, "DragEnd"},
 		{EventDrop, "Drop"},
+		{EventDragOver, "DragOver"},
+		{EventContextMenu, "ContextMenu"},
+		{EventPointerDown, "PointerDown"},
+		{EventPointerCancel, "PointerCancel"},
+		{EventTimeUpdate, "TimeUpdate"},
+		{EventCanPlayThrough, "CanPlayThrough"},
+		{EventPaste, "Paste"},
+		{EventToggle, "Toggle"},
+		{EventVisibilityChange, "VisibilityChange"},
+		{EventBeforeUnload, "BeforeUnload"},
 		{EventHook, "Hook"},
+		{EventIsland, "Island"},
 		{EventNavigate, "Navigate"},
 		{EventCustom, "Custom"},
 		{EventType(0x99), "Unknown"},
@@ -525,7 +671,7 @@ func TestEmptyPayloads(t *testing.T) {
 		Seq:     1,
 		Type:    EventSubmit,
 		HID:     "h1",
-		Payload: &SubmitEventData{Fields: map[string]string{}},
+		Payload: &SubmitEventData{Fields: map[string][]string{}},
 	}
 
 	encoded := EncodeEvent(event)
@@ -559,6 +705,30 @@ func TestEmptyPayloads(t *testing.T) {
 	}
 }
 
+func TestDragEventWithoutDataTransfer(t *testing.T) {
+	// Drag events from older clients end after the modifiers.
+	enc := NewEncoder()
+	enc.WriteUvarint(1)
+	enc.WriteByte(byte(EventDrop))
+	enc.WriteString("h1")
+	enc.WriteSvarint(10)
+	enc.WriteSvarint(20)
+	enc.WriteSvarint(30)
+	enc.WriteSvarint(40)
+	enc.WriteByte(byte(ModAlt))
+
+	decoded, err := DecodeEvent(enc.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeEvent() error = %v", err)
+	}
+	want := &DragEventData{
+		MouseEventData: MouseEventData{ClientX: 10, ClientY: 20, PageX: 30, PageY: 40, Modifiers: ModAlt},
+	}
+	if !reflect.DeepEqual(decoded.Payload, want) {
+		t.Errorf("Payload = %+v, want %+v", decoded.Payload, want)
+	}
+}
+
 func BenchmarkEncodeClickEvent(b *testing.B) {
 	event := &Event{
 		Seq:  1,
@@ -617,12 +787,12 @@ func BenchmarkEncodeSubmitEvent(b *testing.B) {
 		Type: EventSubmit,
 		HID:  "h7",
 		Payload: &SubmitEventData{
-			Fields: map[string]string{
-				"name":     "John Doe",
-				"email":    "john@example.com",
-				"password": "secret123",
-				"confirm":  "secret123",
-				"terms":    "on",
+			Fields: map[string][]string{
+				"name":     {"John Doe"},
+				"email":    {"john@example.com"},
+				"password": {"secret123"},
+				"confirm":  {"secret123"},
+				"terms":    {"on"},
 			},
 		},
 	}
@@ -638,12 +808,12 @@ func BenchmarkDecodeSubmitEvent(b *testing.B) {
 		Type: EventSubmit,
 		HID:  "h7",
 		Payload: &SubmitEventData{
-			Fields: map[string]string{
-				"name":     "John Doe",
-				"email":    "john@example.com",
-				"password": "secret123",
-				"confirm":  "secret123",
-				"terms":    "on",
+			Fields: map[string][]string{
+				"name":     {"John Doe"},
+				"email":    {"john@example.com"},
+				"password": {"secret123"},
+				"confirm":  {"secret123"},
+				"terms":    {"on"},
 			},
 		},
 	}
@@ -0,0 +1,255 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// DefaultMaxMessageSize is the default cap on the total payload a
+// Reassembler buffers across all partially received messages. It matches
+// the decoder's allocation limit, since no single field can be larger.
+const DefaultMaxMessageSize = DefaultMaxAllocation
+
+// fragmentFlags are the flags that describe a fragment's position rather
+// than the message it belongs to.
+const fragmentFlags = FlagFragment | FlagFinal
+
+// Fragmentation errors.
+var (
+	ErrMessageTooLarge     = errors.New("protocol: reassembled message too large")
+	ErrFragmentInterrupted = errors.New("protocol: unfragmented frame inside a fragmented message")
+	ErrFragmentMismatch    = errors.New("protocol: fragment flags do not match message")
+)
+
+// Fragments splits the frame into frames whose payloads fit in
+// MaxPayloadSize.
+//
+// A frame that already fits is returned as the only element, unchanged.
+// Otherwise every fragment carries the frame's type and flags plus
+// FlagFragment, and the last one also carries FlagFinal:
+//
+//	[Patches|Fragment 65535B][Patches|Fragment 65535B][Patches|Fragment|Final 1234B]
+//
+// Fragments of one message must be sent in order, but frames of other
+// types (a ping, say) may be sent between them.
+func (f *Frame) Fragments() []*Frame {
+	if len(f.Payload) <= MaxPayloadSize {
+		return []*Frame{f}
+	}
+
+	n := (len(f.Payload) + MaxPayloadSize - 1) / MaxPayloadSize
+	frames := make([]*Frame, 0, n)
+	flags := f.Flags&^fragmentFlags | FlagFragment
+	for off := 0; off < len(f.Payload); off += MaxPayloadSize {
+		end := off + MaxPayloadSize
+		if end >= len(f.Payload) {
+			end = len(f.Payload)
+			flags |= FlagFinal
+		}
+		frames = append(frames, &Frame{
+			Type:    f.Type,
+			Flags:   flags,
+			Payload: f.Payload[off:end],
+		})
+	}
+	return frames
+}
+
+// EncodeMessage encodes the frame like Encode, fragmenting it first if the
+// payload exceeds MaxPayloadSize. The result is the fragments' encodings
+// back to back; use SplitFrames to recover them.
+func (f *Frame) EncodeMessage() []byte {
+	if len(f.Payload) <= MaxPayloadSize {
+		return f.Encode()
+	}
+
+	fragments := f.Fragments()
+	buf := make([]byte, 0, len(f.Payload)+len(fragments)*FrameHeaderSize)
+	for _, frag := range fragments {
+		buf = append(buf, byte(frag.Type), byte(frag.Flags),
+			byte(len(frag.Payload)>>8), byte(len(frag.Payload)))
+		buf = append(buf, frag.Payload...)
+	}
+	return buf
+}
+
+// SplitFrames splits back-to-back encoded frames, as produced by
+// EncodeMessage, into one slice per frame. The slices share data's
+// backing array.
+func SplitFrames(data []byte) ([][]byte, error) {
+	var frames [][]byte
+	for len(data) > 0 {
+		_, _, length, err := DecodeFrameHeader(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < FrameHeaderSize+length {
+			return nil, io.ErrUnexpectedEOF
+		}
+		frames = append(frames, data[:FrameHeaderSize+length])
+		data = data[FrameHeaderSize+length:]
+	}
+	return frames, nil
+}
+
+// WriteMessage writes a frame of any size to w, fragmenting it if needed.
+// Unlike WriteFrame it never returns ErrFrameTooLarge.
+func WriteMessage(w io.Writer, f *Frame) error {
+	for _, frag := range f.Fragments() {
+		if err := WriteFrame(w, frag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reassembler joins fragmented frames back into whole messages.
+//
+// Each frame type has its own partial message, so frames of other types can
+// arrive between the fragments of a message and are returned as they come.
+// The total payload buffered across all partial messages is capped by
+// maxSize. A Reassembler is not safe for concurrent use.
+type Reassembler struct {
+	maxSize  int
+	buffered int
+	partial  map[FrameType]*partialMessage
+}
+
+// partialMessage is a fragmented message still waiting for its final
+// fragment. A dropped message has failed; its remaining fragments are
+// skipped so they are not mistaken for the start of a new message.
+type partialMessage struct {
+	flags   FrameFlags
+	buf     bytes.Buffer
+	dropped bool
+}
+
+// NewReassembler creates a reassembler that buffers at most maxSize bytes
+// of partial payload. A maxSize of zero or less uses DefaultMaxMessageSize.
+func NewReassembler(maxSize int) *Reassembler {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	return &Reassembler{
+		maxSize: maxSize,
+		partial: make(map[FrameType]*partialMessage),
+	}
+}
+
+// Add feeds one received frame to the reassembler.
+//
+// Unfragmented frames are returned as is. Fragments are buffered and nil is
+// returned until the final fragment arrives, at which point the whole
+// message is returned with FlagFragment and FlagFinal cleared.
+//
+// On error the message is dropped: the rest of its fragments are skipped
+// and the reassembler stays usable for later messages.
+func (r *Reassembler) Add(f *Frame) (*Frame, error) {
+	p := r.partial[f.Type]
+
+	if !f.Flags.Has(FlagFragment) {
+		if p == nil {
+			return f, nil
+		}
+		dropped := p.dropped
+		r.release(f.Type)
+		if dropped {
+			return f, nil
+		}
+		return nil, ErrFragmentInterrupted
+	}
+
+	final := f.Flags.Has(FlagFinal)
+	flags := f.Flags &^ fragmentFlags
+	switch {
+	case p == nil:
+		p = &partialMessage{flags: flags}
+		r.partial[f.Type] = p
+	case p.dropped:
+		if final {
+			r.release(f.Type)
+		}
+		return nil, nil
+	case p.flags != flags:
+		r.drop(f.Type, final)
+		return nil, ErrFragmentMismatch
+	}
+
+	if r.buffered+len(f.Payload) > r.maxSize {
+		r.drop(f.Type, final)
+		return nil, ErrMessageTooLarge
+	}
+	p.buf.Write(f.Payload)
+	r.buffered += len(f.Payload)
+
+	if !final {
+		return nil, nil
+	}
+
+	r.buffered -= p.buf.Len()
+	delete(r.partial, f.Type)
+	return &Frame{
+		Type:    f.Type,
+		Flags:   p.flags,
+		Payload: p.buf.Bytes(),
+	}, nil
+}
+
+// DecodeFrame decodes one encoded frame and feeds it to Add. It returns
+// nil, nil while a fragmented message is incomplete.
+func (r *Reassembler) DecodeFrame(data []byte) (*Frame, error) {
+	f, err := DecodeFrame(data)
+	if err != nil {
+		return nil, err
+	}
+	return r.Add(f)
+}
+
+// ReadFrame reads frames from rd until it has a complete message, which
+// may be an unfragmented frame that arrived between fragments.
+func (r *Reassembler) ReadFrame(rd io.Reader) (*Frame, error) {
+	for {
+		f, err := ReadFrame(rd)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := r.Add(f)
+		if err != nil || msg != nil {
+			return msg, err
+		}
+	}
+}
+
+// Buffered returns the payload bytes held in partial messages.
+func (r *Reassembler) Buffered() int {
+	return r.buffered
+}
+
+// Reset discards all partial messages.
+func (r *Reassembler) Reset() {
+	clear(r.partial)
+	r.buffered = 0
+}
+
+// drop frees a failed message's buffer. Unless the failing fragment was
+// the final one, the message stays marked so its remaining fragments are
+// skipped.
+func (r *Reassembler) drop(ft FrameType, final bool) {
+	if final {
+		r.release(ft)
+		return
+	}
+	p := r.partial[ft]
+	r.buffered -= p.buf.Len()
+	p.buf = bytes.Buffer{}
+	p.dropped = true
+}
+
+// release forgets the partial message for ft.
+func (r *Reassembler) release(ft FrameType) {
+	if p := r.partial[ft]; p != nil {
+		r.buffered -= p.buf.Len()
+		delete(r.partial, ft)
+	}
+}
@@ -0,0 +1,217 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func patternPayload(n int) []byte {
+	p := make([]byte, n)
+	for i := range p {
+		p[i] = byte(i * 7)
+	}
+	return p
+}
+
+func TestFragmentsSmallFrameUnchanged(t *testing.T) {
+	f := NewFrameWithFlags(FramePatches, FlagSequenced, []byte("hello"))
+	frags := f.Fragments()
+	if len(frags) != 1 || frags[0] != f {
+		t.Fatalf("Fragments() = %v, want the frame itself", frags)
+	}
+	if !bytes.Equal(f.EncodeMessage(), f.Encode()) {
+		t.Error("EncodeMessage() differs from Encode() for a small frame")
+	}
+
+	exact := NewFrame(FramePatches, make([]byte, MaxPayloadSize))
+	if n := len(exact.Fragments()); n != 1 {
+		t.Errorf("MaxPayloadSize frame split into %d fragments", n)
+	}
+}
+
+func TestFragmentsRoundTrip(t *testing.T) {
+	for _, size := range []int{MaxPayloadSize + 1, 2 * MaxPayloadSize, 200_000} {
+		payload := patternPayload(size)
+		f := NewFrameWithFlags(FramePatches, FlagSequenced, payload)
+
+		frags := f.Fragments()
+		want := (size + MaxPayloadSize - 1) / MaxPayloadSize
+		if len(frags) != want {
+			t.Fatalf("size %d: %d fragments, want %d", size, len(frags), want)
+		}
+		for i, frag := range frags {
+			if len(frag.Payload) > MaxPayloadSize {
+				t.Fatalf("size %d: fragment %d payload %d bytes", size, i, len(frag.Payload))
+			}
+			if !frag.Flags.Has(FlagFragment | FlagSequenced) {
+				t.Errorf("size %d: fragment %d flags = %#x", size, i, frag.Flags)
+			}
+			if last := i == len(frags)-1; frag.Flags.Has(FlagFinal) != last {
+				t.Errorf("size %d: fragment %d FlagFinal = %v", size, i, !last)
+			}
+		}
+
+		split, err := SplitFrames(f.EncodeMessage())
+		if err != nil {
+			t.Fatalf("size %d: SplitFrames: %v", size, err)
+		}
+		r := NewReassembler(0)
+		var got *Frame
+		for i, data := range split {
+			got, err = r.DecodeFrame(data)
+			if err != nil {
+				t.Fatalf("size %d: DecodeFrame(%d): %v", size, i, err)
+			}
+			if (got != nil) != (i == len(split)-1) {
+				t.Fatalf("size %d: message returned after fragment %d of %d", size, i, len(split))
+			}
+		}
+		if got.Type != FramePatches || got.Flags != FlagSequenced {
+			t.Errorf("size %d: message type %v flags %#x", size, got.Type, got.Flags)
+		}
+		if !bytes.Equal(got.Payload, payload) {
+			t.Errorf("size %d: reassembled payload mismatch", size)
+		}
+		if r.Buffered() != 0 {
+			t.Errorf("size %d: Buffered() = %d after final fragment", size, r.Buffered())
+		}
+	}
+}
+
+func TestReassemblerInterleavedControl(t *testing.T) {
+	payload := patternPayload(3 * MaxPayloadSize)
+	frags := NewFrame(FramePatches, payload).Fragments()
+
+	ct, pp := NewPing(42)
+	ping := NewFrame(FrameControl, EncodeControl(ct, pp))
+
+	// Write a ping between every pair of fragments.
+	var buf bytes.Buffer
+	for i, frag := range frags {
+		if err := WriteFrame(&buf, frag); err != nil {
+			t.Fatal(err)
+		}
+		if i < len(frags)-1 {
+			if err := WriteFrame(&buf, ping); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	r := NewReassembler(0)
+	for i := 0; i < len(frags)-1; i++ {
+		f, err := r.ReadFrame(&buf)
+		if err != nil {
+			t.Fatalf("ReadFrame: %v", err)
+		}
+		if f.Type != FrameControl {
+			t.Fatalf("message %d type = %v, want ping first", i, f.Type)
+		}
+		gotType, got, err := DecodeControl(f.Payload)
+		if err != nil || gotType != ControlPing || got.(*PingPong).Timestamp != 42 {
+			t.Fatalf("ping = %v %+v %v", gotType, got, err)
+		}
+		if r.Buffered() != (i+1)*MaxPayloadSize {
+			t.Errorf("Buffered() = %d while ping was delivered", r.Buffered())
+		}
+	}
+
+	f, err := r.ReadFrame(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if f.Type != FramePatches || !bytes.Equal(f.Payload, payload) {
+		t.Fatalf("final message type %v, %d bytes", f.Type, len(f.Payload))
+	}
+}
+
+func TestReassemblerMaxSize(t *testing.T) {
+	r := NewReassembler(MaxPayloadSize + 10)
+	frags := NewFrame(FramePatches, patternPayload(2*MaxPayloadSize)).Fragments()
+
+	if _, err := r.Add(frags[0]); err != nil {
+		t.Fatalf("first fragment: %v", err)
+	}
+	if _, err := r.Add(frags[1]); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("err = %v, want ErrMessageTooLarge", err)
+	}
+	if r.Buffered() != 0 {
+		t.Errorf("Buffered() = %d after discarding the message", r.Buffered())
+	}
+
+	// The cap is shared across frame types.
+	events := NewFrame(FrameEvent, patternPayload(MaxPayloadSize+1)).Fragments()
+	if _, err := r.Add(frags[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Add(events[0]); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("second partial message: err = %v, want ErrMessageTooLarge", err)
+	}
+
+	// The rest of a dropped message is skipped, not taken for a new one.
+	r.Reset()
+	long := NewFrame(FramePatches, patternPayload(3*MaxPayloadSize)).Fragments()
+	r.Add(long[0])
+	if _, err := r.Add(long[1]); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("err = %v, want ErrMessageTooLarge", err)
+	}
+	if got, err := r.Add(long[2]); got != nil || err != nil {
+		t.Fatalf("trailing fragment = %v, %v; want skipped", got, err)
+	}
+
+	// Later messages are still accepted.
+	small := NewFrame(FramePatches, patternPayload(MaxPayloadSize+5))
+	var got *Frame
+	for _, frag := range small.Fragments() {
+		var err error
+		if got, err = r.Add(frag); err != nil {
+			t.Fatalf("after dropped message: %v", err)
+		}
+	}
+	if got == nil || !bytes.Equal(got.Payload, small.Payload) {
+		t.Error("message under the cap was not reassembled")
+	}
+}
+
+func TestReassemblerProtocolErrors(t *testing.T) {
+	frags := NewFrameWithFlags(FramePatches, FlagSequenced, patternPayload(2*MaxPayloadSize)).Fragments()
+
+	r := NewReassembler(0)
+	r.Add(frags[0])
+	if _, err := r.Add(NewFrame(FramePatches, []byte("x"))); !errors.Is(err, ErrFragmentInterrupted) {
+		t.Errorf("unfragmented frame mid-message: err = %v", err)
+	}
+
+	r.Add(frags[0])
+	bad := *frags[1]
+	bad.Flags &^= FlagSequenced
+	if _, err := r.Add(&bad); !errors.Is(err, ErrFragmentMismatch) {
+		t.Errorf("mismatched flags: err = %v", err)
+	}
+	if r.Buffered() != 0 {
+		t.Errorf("Buffered() = %d after protocol error", r.Buffered())
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	payload := patternPayload(MaxPayloadSize * 2)
+	var buf bytes.Buffer
+	if err := WriteMessage(&buf, NewFrame(FramePatches, payload)); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if err := WriteFrame(&bytes.Buffer{}, NewFrame(FramePatches, payload)); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("WriteFrame err = %v, want ErrFrameTooLarge", err)
+	}
+
+	f, err := NewReassembler(0).ReadFrame(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if !bytes.Equal(f.Payload, payload) {
+		t.Error("payload mismatch")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%d bytes left unread", buf.Len())
+	}
+}
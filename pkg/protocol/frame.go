@@ -24,6 +24,7 @@ const (
 	FrameControl   FrameType = 0x03 // Control messages (ping, etc.)
 	FrameAck       FrameType = 0x04 // Acknowledgment
 	FrameError     FrameType = 0x05 // Error message
+	FrameBlob      FrameType = 0x06 // Client → Server binary upload chunks
 )
 
 // String returns the string representation of the frame type.
@@ -41,6 +42,8 @@ func (ft FrameType) String() string {
 		return "Ack"
 	case FrameError:
 		return "Error"
+	case FrameBlob:
+		return "Blob"
 	default:
 		return "Unknown"
 	}
@@ -50,10 +53,11 @@ func (ft FrameType) String() string {
 type FrameFlags uint8
 
 const (
-	FlagCompressed FrameFlags = 0x01 // Payload is gzip compressed
+	FlagCompressed FrameFlags = 0x01 // Payload is compressed with the negotiated codec
 	FlagSequenced  FrameFlags = 0x02 // Includes sequence number
-	FlagFinal      FrameFlags = 0x04 // Last frame in batch
+	FlagFinal      FrameFlags = 0x04 // Last fragment of a fragmented message
 	FlagPriority   FrameFlags = 0x08 // High priority (skip queue)
+	FlagFragment   FrameFlags = 0x10 // Part of a message larger than MaxPayloadSize
 )
 
 // Has returns true if the flags contain the specified flag.
@@ -144,6 +148,7 @@ func DecodeFrameHeader(data []byte) (FrameType, FrameFlags, int, error) {
 }
 
 // ReadFrame reads a complete frame from an io.Reader.
+// Fragments are returned as read; use a Reassembler to join them.
 func ReadFrame(r io.Reader) (*Frame, error) {
 	header := make([]byte, FrameHeaderSize)
 	if _, err := io.ReadFull(r, header); err != nil {
@@ -173,6 +178,7 @@ func ReadFrame(r io.Reader) (*Frame, error) {
 }
 
 // WriteFrame writes a complete frame to an io.Writer.
+// Payloads larger than MaxPayloadSize must be sent with WriteMessage.
 func WriteFrame(w io.Writer, f *Frame) error {
 	if len(f.Payload) > MaxPayloadSize {
 		return ErrFrameTooLarge
@@ -44,15 +44,6 @@ func (hs HandshakeStatus) String() string {
 	}
 }
 
-// ProtocolVersion represents a protocol version as major.minor.
-type ProtocolVersion struct {
-	Major uint8
-	Minor uint8
-}
-
-// CurrentVersion is the current protocol version.
-var CurrentVersion = ProtocolVersion{Major: 2, Minor: 0}
-
 // ClientHello is sent by the client after WebSocket connection is established.
 type ClientHello struct {
 	Version   ProtocolVersion // Protocol version
@@ -62,6 +53,13 @@ type ClientHello struct {
 	ViewportW uint16          // Viewport width
 	ViewportH uint16          // Viewport height
 	TZOffset  int16           // Timezone offset in minutes from UTC
+	Codecs    []Codec         // Supported payload codecs, most preferred first (optional)
+	Locale    string          // Browser locale, e.g. "en-US" (optional)
+	Prefs     uint8           // ClientPref* flags (optional)
+
+	// PrefValues are the user preferences kept in the client's
+	// localStorage (optional).
+	PrefValues []PrefValue
 }
 
 // ServerHello is the server's response to ClientHello.
@@ -75,6 +73,19 @@ type ServerHello struct {
 	// AuthReasonSet indicates whether the reason was included in the payload.
 	AuthReason    uint8
 	AuthReasonSet bool
+
+	// Version is the negotiated protocol version on success, and the
+	// server's newest version on HandshakeVersionMismatch and
+	// HandshakeUpgradeRequired, where MinVersion is its oldest. Servers
+	// older than 2.1 do not send it; it then decodes as Version20.
+	Version    ProtocolVersion
+	MinVersion ProtocolVersion
+}
+
+// isVersionError reports whether the status carries the server's version
+// range.
+func (hs HandshakeStatus) isVersionError() bool {
+	return hs == HandshakeVersionMismatch || hs == HandshakeUpgradeRequired
 }
 
 // Server capability flags.
@@ -84,6 +95,32 @@ const (
 	ServerFlagStreaming   uint16 = 0x0004 // Server supports streaming responses
 )
 
+// serverCodecShift is the bit offset of the negotiated codec in
+// ServerHello.Flags. The high byte is only meaningful when
+// ServerFlagCompression is set.
+const serverCodecShift = 8
+
+// maxClientCodecs bounds the codec list accepted in a ClientHello.
+const maxClientCodecs = 16
+
+// SetCodec records the negotiated compression codec in Flags. CodecNone
+// clears ServerFlagCompression.
+func (sh *ServerHello) SetCodec(c Codec) {
+	sh.Flags &^= ServerFlagCompression | 0xFF<<serverCodecShift
+	if c != CodecNone {
+		sh.Flags |= ServerFlagCompression | uint16(c)<<serverCodecShift
+	}
+}
+
+// Codec returns the negotiated compression codec, or CodecNone if the
+// server did not enable compression.
+func (sh *ServerHello) Codec() Codec {
+	if sh.Flags&ServerFlagCompression == 0 {
+		return CodecNone
+	}
+	return Codec(sh.Flags >> serverCodecShift)
+}
+
 // EncodeClientHello encodes a ClientHello to bytes.
 func EncodeClientHello(ch *ClientHello) []byte {
 	e := NewEncoder()
@@ -101,6 +138,21 @@ func EncodeClientHelloTo(e *Encoder, ch *ClientHello) {
 	e.WriteUint16(ch.ViewportW)
 	e.WriteUint16(ch.ViewportH)
 	e.WriteInt16(ch.TZOffset)
+	hasPrefValues := len(ch.PrefValues) > 0
+	hasEnv := ch.Locale != "" || ch.Prefs != 0 || hasPrefValues
+	if len(ch.Codecs) > 0 || hasEnv {
+		e.WriteUvarint(uint64(len(ch.Codecs)))
+		for _, c := range ch.Codecs {
+			e.WriteByte(byte(c))
+		}
+	}
+	if hasEnv {
+		e.WriteString(ch.Locale)
+		e.WriteByte(ch.Prefs)
+	}
+	if hasPrefValues {
+		encodePrefValues(e, ch.PrefValues)
+	}
 }
 
 // DecodeClientHello decodes a ClientHello from bytes.
@@ -154,6 +206,42 @@ func DecodeClientHelloFrom(d *Decoder) (*ClientHello, error) {
 		return nil, err
 	}
 
+	// Codecs are optional; older clients end the hello here.
+	if d.Remaining() > 0 {
+		count, err := d.ReadCollectionCount()
+		if err != nil {
+			return nil, err
+		}
+		if count > maxClientCodecs {
+			return nil, ErrCollectionTooLarge
+		}
+		ch.Codecs = make([]Codec, count)
+		for i := range ch.Codecs {
+			c, err := d.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			ch.Codecs[i] = Codec(c)
+		}
+	}
+
+	// Locale and preferences are optional and follow the codec list.
+	if d.Remaining() > 0 {
+		if ch.Locale, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if ch.Prefs, err = d.ReadByte(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Stored preference values are optional and follow the environment.
+	if d.Remaining() > 0 {
+		if ch.PrefValues, err = decodePrefValues(d); err != nil {
+			return nil, err
+		}
+	}
+
 	return ch, nil
 }
 
@@ -171,7 +259,16 @@ func EncodeServerHelloTo(e *Encoder, sh *ServerHello) {
 	e.WriteUint32(sh.NextSeq)
 	e.WriteUint64(sh.ServerTime)
 	e.WriteUint16(sh.Flags)
-	if sh.AuthReasonSet {
+	switch {
+	case sh.Status == HandshakeOK:
+		e.WriteByte(sh.Version.Major)
+		e.WriteByte(sh.Version.Minor)
+	case sh.Status.isVersionError():
+		e.WriteByte(sh.MinVersion.Major)
+		e.WriteByte(sh.MinVersion.Minor)
+		e.WriteByte(sh.Version.Major)
+		e.WriteByte(sh.Version.Minor)
+	case sh.AuthReasonSet:
 		e.WriteByte(sh.AuthReason)
 	}
 }
@@ -213,18 +310,50 @@ func DecodeServerHelloFrom(d *Decoder) (*ServerHello, error) {
 		return nil, err
 	}
 
-	if d.Remaining() > 0 {
-		reason, err := d.ReadByte()
-		if err != nil {
-			return nil, err
+	// The trailing fields depend on the status; older servers send none.
+	sh.Version = Version20
+	switch {
+	case sh.Status == HandshakeOK:
+		if d.Remaining() >= 2 {
+			if sh.Version, err = readVersion(d); err != nil {
+				return nil, err
+			}
+		}
+	case sh.Status.isVersionError():
+		if d.Remaining() >= 4 {
+			if sh.MinVersion, err = readVersion(d); err != nil {
+				return nil, err
+			}
+			if sh.Version, err = readVersion(d); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if d.Remaining() > 0 {
+			reason, err := d.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			sh.AuthReason = reason
+			sh.AuthReasonSet = true
 		}
-		sh.AuthReason = reason
-		sh.AuthReasonSet = true
 	}
 
 	return sh, nil
 }
 
+func readVersion(d *Decoder) (ProtocolVersion, error) {
+	major, err := d.ReadByte()
+	if err != nil {
+		return ProtocolVersion{}, err
+	}
+	minor, err := d.ReadByte()
+	if err != nil {
+		return ProtocolVersion{}, err
+	}
+	return ProtocolVersion{Major: major, Minor: minor}, nil
+}
+
 // NewClientHello creates a new ClientHello with default version.
 func NewClientHello(csrfToken string) *ClientHello {
 	return &ClientHello{
@@ -240,6 +369,7 @@ func NewServerHello(sessionID string, nextSeq uint32, serverTime uint64) *Server
 		SessionID:  sessionID,
 		NextSeq:    nextSeq,
 		ServerTime: serverTime,
+		Version:    CurrentVersion,
 	}
 }
 
@@ -250,6 +380,16 @@ func NewServerHelloError(status HandshakeStatus) *ServerHello {
 	}
 }
 
+// NewServerHelloVersionError creates a ServerHello rejecting a client's
+// protocol version. It carries the range of versions the server speaks.
+func NewServerHelloVersionError(status HandshakeStatus) *ServerHello {
+	return &ServerHello{
+		Status:     status,
+		Version:    CurrentVersion,
+		MinVersion: MinSupportedVersion,
+	}
+}
+
 // NewServerHelloErrorWithReason creates a ServerHello with an error status and reason.
 func NewServerHelloErrorWithReason(status HandshakeStatus, reason uint8) *ServerHello {
 	return &ServerHello{
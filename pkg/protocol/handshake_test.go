@@ -33,6 +33,45 @@ func TestClientHelloEncodeDecode(t *testing.T) {
 				TZOffset:  60, // UTC+1
 			},
 		},
+		{
+			name: "with_codecs",
+			hello: &ClientHello{
+				Version:   CurrentVersion,
+				CSRFToken: "abc",
+				ViewportW: 800,
+				ViewportH: 600,
+				Codecs:    []Codec{Codec(0x7F), CodecDeflate},
+			},
+		},
+		{
+			name: "with_env",
+			hello: &ClientHello{
+				Version:   CurrentVersion,
+				ViewportW: 390,
+				ViewportH: 844,
+				TZOffset:  330,
+				Locale:    "hi-IN",
+				Prefs:     ClientPrefDark | ClientPrefReducedMotion,
+			},
+		},
+		{
+			name: "with_codecs_and_env",
+			hello: &ClientHello{
+				Version: CurrentVersion,
+				Codecs:  []Codec{CodecDeflate},
+				Locale:  "de",
+			},
+		},
+		{
+			name: "with_pref_values",
+			hello: &ClientHello{
+				Version: CurrentVersion,
+				PrefValues: []PrefValue{
+					{Key: "theme", Value: `"dark"`, UpdatedAt: 1702000000000},
+					{Key: "volume", Value: "40", UpdatedAt: 1702000000001},
+				},
+			},
+		},
 		{
 			name: "minimal",
 			hello: &ClientHello{
@@ -71,10 +110,72 @@ func TestClientHelloEncodeDecode(t *testing.T) {
 			if decoded.TZOffset != tc.hello.TZOffset {
 				t.Errorf("TZOffset = %d, want %d", decoded.TZOffset, tc.hello.TZOffset)
 			}
+			if decoded.Locale != tc.hello.Locale {
+				t.Errorf("Locale = %q, want %q", decoded.Locale, tc.hello.Locale)
+			}
+			if decoded.Prefs != tc.hello.Prefs {
+				t.Errorf("Prefs = %#x, want %#x", decoded.Prefs, tc.hello.Prefs)
+			}
+			if len(decoded.Codecs) != len(tc.hello.Codecs) {
+				t.Fatalf("Codecs = %v, want %v", decoded.Codecs, tc.hello.Codecs)
+			}
+			for i := range decoded.Codecs {
+				if decoded.Codecs[i] != tc.hello.Codecs[i] {
+					t.Errorf("Codecs[%d] = %v, want %v", i, decoded.Codecs[i], tc.hello.Codecs[i])
+				}
+			}
+			if len(decoded.PrefValues) != len(tc.hello.PrefValues) {
+				t.Fatalf("PrefValues = %v, want %v", decoded.PrefValues, tc.hello.PrefValues)
+			}
+			for i := range decoded.PrefValues {
+				if decoded.PrefValues[i] != tc.hello.PrefValues[i] {
+					t.Errorf("PrefValues[%d] = %+v, want %+v", i, decoded.PrefValues[i], tc.hello.PrefValues[i])
+				}
+			}
 		})
 	}
 }
 
+func TestClientHelloCodecsOptional(t *testing.T) {
+	// A hello without codecs must encode exactly as before the field existed.
+	hello := &ClientHello{Version: CurrentVersion, CSRFToken: "t", ViewportW: 1, ViewportH: 2, TZOffset: 3}
+	encoded := EncodeClientHello(hello)
+	if want := 2 + 2 + 1 + 4 + 2 + 2 + 2; len(encoded) != want {
+		t.Errorf("encoded length = %d, want %d", len(encoded), want)
+	}
+
+	tooMany := append(encoded[:len(encoded):len(encoded)], byte(maxClientCodecs+1))
+	tooMany = append(tooMany, make([]byte, maxClientCodecs+1)...)
+	if _, err := DecodeClientHello(tooMany); err == nil {
+		t.Error("DecodeClientHello accepted an oversized codec list")
+	}
+}
+
+func TestServerHelloCodec(t *testing.T) {
+	sh := NewServerHello("s", 0, 0)
+	sh.Flags = ServerFlagStreaming
+	if sh.Codec() != CodecNone {
+		t.Errorf("Codec() = %v before SetCodec, want none", sh.Codec())
+	}
+
+	sh.SetCodec(CodecDeflate)
+	decoded, err := DecodeServerHello(EncodeServerHello(sh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Codec() != CodecDeflate {
+		t.Errorf("Codec() = %v, want deflate", decoded.Codec())
+	}
+	if decoded.Flags&ServerFlagCompression == 0 || decoded.Flags&ServerFlagStreaming == 0 {
+		t.Errorf("Flags = %#x, want compression and streaming set", decoded.Flags)
+	}
+
+	sh.SetCodec(CodecNone)
+	if sh.Flags != ServerFlagStreaming {
+		t.Errorf("Flags after SetCodec(CodecNone) = %#x, want %#x", sh.Flags, ServerFlagStreaming)
+	}
+}
+
 func TestServerHelloEncodeDecode(t *testing.T) {
 	tests := []struct {
 		name  string
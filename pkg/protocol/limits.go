@@ -76,3 +76,43 @@ func checkDepth(current, max int) error {
 	}
 	return nil
 }
+
+// ValidateHookData checks that data can be carried as a hook or island
+// payload: values must not nest deeper than MaxHookDepth, and the encoded
+// map must fit in a single frame payload. Returns ErrMaxDepthExceeded or
+// ErrFrameTooLarge otherwise.
+func ValidateHookData(data map[string]any) error {
+	for _, v := range data {
+		if err := checkHookValueDepth(v, 0); err != nil {
+			return err
+		}
+	}
+	enc := NewEncoder()
+	encodeHookData(enc, data)
+	if enc.Len() > MaxPayloadSize {
+		return ErrFrameTooLarge
+	}
+	return nil
+}
+
+// checkHookValueDepth mirrors the depth accounting of decodeHookValueWithDepth.
+func checkHookValueDepth(v any, depth int) error {
+	if err := checkDepth(depth, MaxHookDepth); err != nil {
+		return err
+	}
+	switch val := v.(type) {
+	case []any:
+		for _, item := range val {
+			if err := checkHookValueDepth(item, depth+1); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, item := range val {
+			if err := checkHookValueDepth(item, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
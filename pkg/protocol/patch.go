@@ -40,6 +40,14 @@ const (
 	// Navigation operations (full route navigation)
 	PatchNavPush    PatchOp = 0x32 // Full navigation, push history (NAV_PUSH)
 	PatchNavReplace PatchOp = 0x33 // Full navigation, replace history (NAV_REPLACE)
+
+	// Document head operations (route metadata)
+	PatchSetTitle PatchOp = 0x34 // Set document.title
+	PatchSetMeta  PatchOp = 0x35 // Set or remove a <meta> tag in <head>
+	PatchSetLink  PatchOp = 0x36 // Set or remove a <link> tag in <head>
+
+	// Island operations (JS islands)
+	PatchIslandMessage PatchOp = 0x40 // Deliver a server message to a JS island
 )
 
 // String returns the string representation of the patch operation.
@@ -93,6 +101,14 @@ func (op PatchOp) String() string {
 		return "NavPush"
 	case PatchNavReplace:
 		return "NavReplace"
+	case PatchSetTitle:
+		return "SetTitle"
+	case PatchSetMeta:
+		return "SetMeta"
+	case PatchSetLink:
+		return "SetLink"
+	case PatchIslandMessage:
+		return "IslandMessage"
 	default:
 		return "Unknown"
 	}
@@ -121,6 +137,8 @@ type Patch struct {
 	Behavior ScrollBehavior    // For ScrollTo
 	Params   map[string]string // For URLPush/URLReplace
 	Path     string            // For NavPush/NavReplace (includes query string)
+	Attr     string            // For SetMeta: identifying attribute ("name" or "property")
+	Data     map[string]any    // For IslandMessage (hook value encoding)
 }
 
 // PatchesFrame represents a batch of patches with sequence number.
@@ -223,6 +241,27 @@ func encodePatch(e *Encoder, p *Patch) {
 		// Wire format: [0x32|0x33][hid:string][path:string]
 		// SECURITY: Validated server-side that path starts with "/" and is relative
 		e.WriteString(p.Path)
+
+	case PatchSetTitle:
+		e.WriteString(p.Value)
+
+	case PatchSetMeta:
+		// Wire format: [0x35][hid:string][attr:string][key:string][content:string]
+		// An empty content removes the tag.
+		e.WriteString(p.Attr)
+		e.WriteString(p.Key)
+		e.WriteString(p.Value)
+
+	case PatchSetLink:
+		// Wire format: [0x36][hid:string][rel:string][href:string]
+		// An empty href removes the tag.
+		e.WriteString(p.Key)
+		e.WriteString(p.Value)
+
+	case PatchIslandMessage:
+		// Wire format: [0x40][id:string][data:hookdata]
+		// The island ID is carried in the HID field.
+		encodeHookData(e, p.Data)
 	}
 }
 
@@ -410,6 +449,30 @@ func decodePatchWithDepth(d *Decoder, p *Patch, depth int) error {
 		// Decode path (includes query string)
 		p.Path, err = d.ReadString()
 
+	case PatchSetTitle:
+		p.Value, err = d.ReadString()
+
+	case PatchSetMeta:
+		p.Attr, err = d.ReadString()
+		if err != nil {
+			return err
+		}
+		p.Key, err = d.ReadString()
+		if err != nil {
+			return err
+		}
+		p.Value, err = d.ReadString()
+
+	case PatchSetLink:
+		p.Key, err = d.ReadString()
+		if err != nil {
+			return err
+		}
+		p.Value, err = d.ReadString()
+
+	case PatchIslandMessage:
+		p.Data, err = decodeHookData(d)
+
 	default:
 		// Unknown patch op - skip for forward compatibility
 	}
@@ -544,3 +607,26 @@ func NewNavPushPatch(path string) Patch {
 func NewNavReplacePatch(path string) Patch {
 	return Patch{Op: PatchNavReplace, Path: path}
 }
+
+// NewSetTitlePatch creates a SetTitle patch that updates document.title.
+func NewSetTitlePatch(title string) Patch {
+	return Patch{Op: PatchSetTitle, Value: title}
+}
+
+// NewSetMetaPatch creates a SetMeta patch for the <meta> tag whose attr
+// ("name" or "property") equals key. An empty content removes the tag.
+func NewSetMetaPatch(attr, key, content string) Patch {
+	return Patch{Op: PatchSetMeta, Attr: attr, Key: key, Value: content}
+}
+
+// NewSetLinkPatch creates a SetLink patch for the <link> tag with the given
+// rel. An empty href removes the tag.
+func NewSetLinkPatch(rel, href string) Patch {
+	return Patch{Op: PatchSetLink, Key: rel, Value: href}
+}
+
+// NewIslandMessagePatch creates an IslandMessage patch delivering data to
+// the JS island with the given ID.
+func NewIslandMessagePatch(id string, data map[string]any) Patch {
+	return Patch{Op: PatchIslandMessage, HID: id, Data: data}
+}
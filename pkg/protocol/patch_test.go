@@ -140,6 +140,29 @@ func TestPatchEncodeDecode(t *testing.T) {
 			name:  "nav_push_root",
 			patch: NewNavPushPatch("/"),
 		},
+		{
+			name:  "set_title",
+			patch: NewSetTitlePatch("Projects | Acme"),
+		},
+		{
+			name:  "set_meta",
+			patch: NewSetMetaPatch("property", "og:title", "Projects"),
+		},
+		{
+			name:  "set_meta_remove",
+			patch: NewSetMetaPatch("name", "description", ""),
+		},
+		{
+			name:  "set_link",
+			patch: NewSetLinkPatch("canonical", "https://example.com/projects"),
+		},
+		{
+			name: "island_message",
+			patch: NewIslandMessagePatch("sales-chart", map[string]any{
+				"series": []any{int64(1), int64(2), int64(3)},
+				"label":  "Q3",
+			}),
+		},
 	}
 
 	for _, tc := range tests {
@@ -211,6 +234,12 @@ func verifyPatch(t *testing.T, _ string, got, want Patch) {
 	if got.Path != want.Path {
 		t.Errorf("Path = %q, want %q", got.Path, want.Path)
 	}
+	if got.Attr != want.Attr {
+		t.Errorf("Attr = %q, want %q", got.Attr, want.Attr)
+	}
+	if len(got.Data) != len(want.Data) {
+		t.Errorf("Data length = %d, want %d", len(got.Data), len(want.Data))
+	}
 	// Verify Params map
 	if len(got.Params) != len(want.Params) {
 		t.Errorf("Params length = %d, want %d", len(got.Params), len(want.Params))
@@ -291,6 +320,10 @@ func TestPatchOpString(t *testing.T) {
 		{PatchURLReplace, "URLReplace"},
 		{PatchNavPush, "NavPush"},
 		{PatchNavReplace, "NavReplace"},
+		{PatchSetTitle, "SetTitle"},
+		{PatchSetMeta, "SetMeta"},
+		{PatchSetLink, "SetLink"},
+		{PatchIslandMessage, "IslandMessage"},
 		{PatchOp(0xFF), "Unknown"},
 	}
 
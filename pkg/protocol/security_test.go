@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -84,6 +85,36 @@ func TestDepthLimits(t *testing.T) {
 	}
 }
 
+func TestValidateHookData(t *testing.T) {
+	nested := func(depth int) map[string]any {
+		var v any = "leaf"
+		for i := 0; i < depth; i++ {
+			v = []any{v}
+		}
+		return map[string]any{"v": v}
+	}
+
+	if err := ValidateHookData(map[string]any{"a": int64(1), "b": []any{"x", map[string]any{"c": true}}}); err != nil {
+		t.Errorf("valid data: error = %v", err)
+	}
+	if err := ValidateHookData(nested(MaxHookDepth)); err != nil {
+		t.Errorf("data at depth limit: error = %v", err)
+	}
+	if err := ValidateHookData(nested(MaxHookDepth + 1)); err != ErrMaxDepthExceeded {
+		t.Errorf("data over depth limit: error = %v, want %v", err, ErrMaxDepthExceeded)
+	}
+	if err := ValidateHookData(map[string]any{"big": strings.Repeat("x", MaxPayloadSize)}); err != ErrFrameTooLarge {
+		t.Errorf("oversized data: error = %v, want %v", err, ErrFrameTooLarge)
+	}
+
+	// Data accepted by ValidateHookData must decode within the same limits.
+	enc := NewEncoder()
+	encodeHookData(enc, nested(MaxHookDepth))
+	if _, err := decodeHookData(NewDecoder(enc.Bytes())); err != nil {
+		t.Errorf("decode at depth limit: error = %v", err)
+	}
+}
+
 // TestVNodeDepthLimit verifies VNode decoding depth is limited.
 func TestVNodeDepthLimit(t *testing.T) {
 	// Create deeply nested VNode
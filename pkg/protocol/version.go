@@ -0,0 +1,222 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// ProtocolVersion represents a protocol version as major.minor.
+//
+// Minor versions are backwards compatible: a server speaks every minor
+// version from MinSupportedVersion up to CurrentVersion, leaving out what a
+// client's version lacks, so browser tabs running an older thin client keep
+// working while a deploy rolls out. A different major version cannot be
+// spoken; the handshake fails and the client reloads to fetch a matching
+// thin client.
+//
+// Version history:
+//   - 2.0: baseline
+//   - 2.1: head patches (SetTitle, SetMeta, SetLink), island messages,
+//     frame fragmentation, blob uploads and client environment reports
+//   - 2.2: preference sync, window and document event subscriptions, hook
+//     calls, portal nodes, and pointer, media, clipboard, context-menu,
+//     drag and page lifecycle events
+type ProtocolVersion struct {
+	Major uint8
+	Minor uint8
+}
+
+var (
+	Version20 = ProtocolVersion{Major: 2, Minor: 0}
+	Version21 = ProtocolVersion{Major: 2, Minor: 1}
+	Version22 = ProtocolVersion{Major: 2, Minor: 2}
+)
+
+// CurrentVersion is the newest protocol version, spoken with clients that
+// support it.
+var CurrentVersion = Version22
+
+// MinSupportedVersion is the oldest protocol version a server still speaks.
+var MinSupportedVersion = Version20
+
+// String returns the version as "major.minor".
+func (v ProtocolVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Less reports whether v is older than other.
+func (v ProtocolVersion) Less(other ProtocolVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	return v.Minor < other.Minor
+}
+
+// AtLeast reports whether v is other or newer.
+func (v ProtocolVersion) AtLeast(other ProtocolVersion) bool {
+	return !v.Less(other)
+}
+
+// NegotiateVersion picks the version to speak with a client that offered
+// client, given the range the server supports. A client newer than max
+// within the same major version is spoken to at max. It returns
+// HandshakeUpgradeRequired for clients older than min and
+// HandshakeVersionMismatch for a different major version.
+func NegotiateVersion(client, min, max ProtocolVersion) (ProtocolVersion, HandshakeStatus) {
+	switch {
+	case client.Major != max.Major:
+		if client.Less(max) {
+			return ProtocolVersion{}, HandshakeUpgradeRequired
+		}
+		return ProtocolVersion{}, HandshakeVersionMismatch
+	case client.Less(min):
+		return ProtocolVersion{}, HandshakeUpgradeRequired
+	case max.Less(client):
+		return max, HandshakeOK
+	default:
+		return client, HandshakeOK
+	}
+}
+
+// patchOpVersions lists patch operations added after Version20.
+var patchOpVersions = map[PatchOp]ProtocolVersion{
+	PatchSetTitle:      Version21,
+	PatchSetMeta:       Version21,
+	PatchSetLink:       Version21,
+	PatchIslandMessage: Version21,
+}
+
+// SupportsPatchOp reports whether clients speaking v understand op.
+func (v ProtocolVersion) SupportsPatchOp(op PatchOp) bool {
+	since, ok := patchOpVersions[op]
+	return !ok || v.AtLeast(since)
+}
+
+// controlVersions lists control messages added after Version20.
+var controlVersions = map[ControlType]ProtocolVersion{
+	ControlBlobAck:        Version21,
+	ControlClientEnv:      Version21,
+	ControlPrefSync:       Version22,
+	ControlGlobalListen:   Version22,
+	ControlGlobalUnlisten: Version22,
+	ControlHookCall:       Version22,
+	ControlHookResult:     Version22,
+}
+
+// SupportsControl reports whether clients speaking v understand control
+// messages of type ct.
+func (v ProtocolVersion) SupportsControl(ct ControlType) bool {
+	since, ok := controlVersions[ct]
+	return !ok || v.AtLeast(since)
+}
+
+// eventVersions lists event types added after Version21. Clients only send
+// them to servers that negotiated a version defining them.
+var eventVersions = map[EventType]ProtocolVersion{
+	EventContextMenu:      Version22,
+	EventDragEnter:        Version22,
+	EventDragOver:         Version22,
+	EventDragLeave:        Version22,
+	EventDrag:             Version22,
+	EventPointerDown:      Version22,
+	EventPointerUp:        Version22,
+	EventPointerMove:      Version22,
+	EventPointerEnter:     Version22,
+	EventPointerLeave:     Version22,
+	EventPointerCancel:    Version22,
+	EventCopy:             Version22,
+	EventCut:              Version22,
+	EventPaste:            Version22,
+	EventToggle:           Version22,
+	EventPlay:             Version22,
+	EventPause:            Version22,
+	EventEnded:            Version22,
+	EventTimeUpdate:       Version22,
+	EventSeeking:          Version22,
+	EventSeeked:           Version22,
+	EventVolumeChange:     Version22,
+	EventRateChange:       Version22,
+	EventDurationChange:   Version22,
+	EventLoadedMetadata:   Version22,
+	EventLoadedData:       Version22,
+	EventCanPlay:          Version22,
+	EventCanPlayThrough:   Version22,
+	EventWaiting:          Version22,
+	EventPlaying:          Version22,
+	EventVisibilityChange: Version22,
+	EventOnline:           Version22,
+	EventOffline:          Version22,
+	EventBeforeUnload:     Version22,
+}
+
+// SupportsEvent reports whether et is defined in version v.
+func (v ProtocolVersion) SupportsEvent(et EventType) bool {
+	since, ok := eventVersions[et]
+	return !ok || v.AtLeast(since)
+}
+
+// nodeKindVersions lists VNodeWire kinds added after Version21.
+var nodeKindVersions = map[vdom.VKind]ProtocolVersion{
+	vdom.KindPortal: Version22,
+}
+
+// SupportsNode reports whether clients speaking v can decode n and its
+// descendants.
+func (v ProtocolVersion) SupportsNode(n *VNodeWire) bool {
+	if n == nil {
+		return true
+	}
+	if since, ok := nodeKindVersions[n.Kind]; ok && !v.AtLeast(since) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !v.SupportsNode(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// SupportsFragmentation reports whether clients speaking v reassemble
+// messages split with FlagFragment.
+func (v ProtocolVersion) SupportsFragmentation() bool {
+	return v.AtLeast(Version21)
+}
+
+// DowngradePatches returns patches without the operations clients speaking
+// v do not understand. Those operations only touch the document head and JS
+// islands, so the rest of the page still converges. The input slice is
+// returned unchanged when nothing needs removing.
+//
+// Nodes are kept as they are: check the result with CanDecodePatches, since
+// a subtree the client cannot decode cannot be left out.
+func DowngradePatches(patches []Patch, v ProtocolVersion) []Patch {
+	keep := 0
+	for i := range patches {
+		if v.SupportsPatchOp(patches[i].Op) {
+			keep++
+		}
+	}
+	if keep == len(patches) {
+		return patches
+	}
+	out := make([]Patch, 0, keep)
+	for _, p := range patches {
+		if v.SupportsPatchOp(p.Op) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// CanDecodePatches reports whether clients speaking v can decode every node
+// inserted by patches.
+func (v ProtocolVersion) CanDecodePatches(patches []Patch) bool {
+	for i := range patches {
+		if !v.SupportsNode(patches[i].Node) {
+			return false
+		}
+	}
+	return true
+}
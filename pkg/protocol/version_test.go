@@ -0,0 +1,150 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     ProtocolVersion
+		want       ProtocolVersion
+		wantStatus HandshakeStatus
+	}{
+		{"oldest supported", Version20, Version20, HandshakeOK},
+		{"current", Version21, Version21, HandshakeOK},
+		{"newer minor", ProtocolVersion{2, 9}, Version21, HandshakeOK},
+		{"older major", ProtocolVersion{1, 5}, ProtocolVersion{}, HandshakeUpgradeRequired},
+		{"newer major", ProtocolVersion{3, 0}, ProtocolVersion{}, HandshakeVersionMismatch},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, status := NegotiateVersion(tc.client, Version20, Version21)
+			if got != tc.want || status != tc.wantStatus {
+				t.Errorf("NegotiateVersion(%s) = %s, %v; want %s, %v", tc.client, got, status, tc.want, tc.wantStatus)
+			}
+		})
+	}
+
+	// A client older than the minimum within the same major version.
+	if _, status := NegotiateVersion(Version20, Version21, Version21); status != HandshakeUpgradeRequired {
+		t.Errorf("2.0 against 2.1-2.1: status = %v, want UpgradeRequired", status)
+	}
+}
+
+func TestDowngradePatches(t *testing.T) {
+	patches := []Patch{
+		NewSetTextPatch("h1", "hello"),
+		NewSetTitlePatch("Inbox"),
+		NewSetMetaPatch("name", "description", "mail"),
+		NewIslandMessagePatch("chart", map[string]any{"n": 1}),
+		NewSetTextPatch("h2", "world"),
+	}
+
+	got := DowngradePatches(patches, Version20)
+	if len(got) != 2 || got[0].HID != "h1" || got[1].HID != "h2" {
+		t.Errorf("DowngradePatches(2.0) = %+v, want only the text patches", got)
+	}
+	if len(patches) != 5 {
+		t.Error("DowngradePatches modified its input")
+	}
+
+	if got := DowngradePatches(patches, Version21); len(got) != len(patches) {
+		t.Errorf("DowngradePatches(2.1) kept %d patches, want %d", len(got), len(patches))
+	}
+}
+
+func TestVersionFeatures(t *testing.T) {
+	if !Version20.SupportsControl(ControlAuthCommand) || Version20.SupportsControl(ControlBlobAck) {
+		t.Error("2.0: want AuthCommand and no BlobAck")
+	}
+	if !Version21.SupportsControl(ControlClientEnv) || Version21.SupportsControl(ControlHookCall) {
+		t.Error("2.1: want ClientEnv and no HookCall")
+	}
+	for _, ct := range []ControlType{ControlPrefSync, ControlGlobalListen, ControlHookCall} {
+		if !Version22.SupportsControl(ct) {
+			t.Errorf("2.2 does not support control %#x", ct)
+		}
+	}
+
+	if !Version21.SupportsEvent(EventClick) || Version21.SupportsEvent(EventPointerDown) || !Version22.SupportsEvent(EventBeforeUnload) {
+		t.Error("event table: want click in 2.1, pointer and lifecycle events in 2.2")
+	}
+
+	portal := []Patch{
+		NewSetTextPatch("h1", "hello"),
+		NewInsertNodePatch("h2", "h1", 0, &VNodeWire{
+			Kind:     vdom.KindElement,
+			Tag:      "div",
+			Children: []*VNodeWire{NewPortalWire("body", "h3")},
+		}),
+	}
+	if Version21.CanDecodePatches(portal) || !Version22.CanDecodePatches(portal) {
+		t.Error("portal nodes: want 2.2 only")
+	}
+	if !Version20.CanDecodePatches(portal[:1]) {
+		t.Error("2.0 cannot decode a text patch")
+	}
+}
+
+func TestServerHelloVersion(t *testing.T) {
+	t.Run("ok carries the negotiated version", func(t *testing.T) {
+		sh := NewServerHello("s", 1, 0)
+		sh.Version = Version20
+		decoded, err := DecodeServerHello(EncodeServerHello(sh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Version != Version20 {
+			t.Errorf("Version = %s, want 2.0", decoded.Version)
+		}
+	})
+
+	t.Run("version error carries the supported range", func(t *testing.T) {
+		decoded, err := DecodeServerHello(EncodeServerHello(NewServerHelloVersionError(HandshakeVersionMismatch)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.MinVersion != MinSupportedVersion || decoded.Version != CurrentVersion {
+			t.Errorf("range = %s-%s, want %s-%s", decoded.MinVersion, decoded.Version, MinSupportedVersion, CurrentVersion)
+		}
+		if decoded.AuthReasonSet {
+			t.Error("version bytes decoded as an auth reason")
+		}
+	})
+
+	t.Run("server without version bytes decodes as 2.0", func(t *testing.T) {
+		// A 2.0 server ends the hello after the flags.
+		e := NewEncoder()
+		e.WriteByte(byte(HandshakeOK))
+		e.WriteString("s")
+		e.WriteUint32(1)
+		e.WriteUint64(0)
+		e.WriteUint16(0)
+		decoded, err := DecodeServerHello(e.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Version != Version20 {
+			t.Errorf("Version = %s, want 2.0", decoded.Version)
+		}
+	})
+}
+
+func TestClientHelloVersion20(t *testing.T) {
+	// A 2.0 client sends no codec list or environment fields.
+	hello := &ClientHello{Version: Version20, CSRFToken: "t", TZOffset: 60}
+	decoded, err := DecodeClientHello(EncodeClientHello(hello))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Version != Version20 {
+		t.Errorf("Version = %s, want 2.0", decoded.Version)
+	}
+	if v, status := NegotiateVersion(decoded.Version, MinSupportedVersion, CurrentVersion); status != HandshakeOK || v != Version20 {
+		t.Errorf("NegotiateVersion = %s, %v; want 2.0, OK", v, status)
+	}
+}
@@ -8,7 +8,7 @@ import (
 // It contains only serializable data (no event handlers or components).
 type VNodeWire struct {
 	Kind     vdom.VKind        // Node type
-	Tag      string            // Element tag name
+	Tag      string            // Element tag name, or the portal target
 	HID      string            // Hydration ID
 	Attrs    map[string]string // String attributes only (no handlers)
 	Children []*VNodeWire      // Child nodes
@@ -89,6 +89,14 @@ func EncodeVNodeWire(e *Encoder, node *VNodeWire) {
 	case vdom.KindRaw:
 		e.WriteString(node.Text)
 
+	case vdom.KindPortal:
+		e.WriteString(node.Tag)
+		e.WriteString(node.HID)
+		e.WriteUvarint(uint64(len(node.Children)))
+		for _, child := range node.Children {
+			EncodeVNodeWire(e, child)
+		}
+
 	case vdom.KindComponent:
 		// Components should be rendered before encoding
 		// Encode as empty fragment
@@ -208,6 +216,35 @@ func decodeVNodeWireWithDepth(d *Decoder, depth int) (*VNodeWire, error) {
 			return nil, err
 		}
 
+	case vdom.KindPortal:
+		node.Tag, err = d.ReadString()
+		if err != nil {
+			return nil, err
+		}
+
+		node.HID, err = d.ReadString()
+		if err != nil {
+			return nil, err
+		}
+
+		// SECURITY: Use ReadCollectionCount to prevent DoS
+		childCount, err := d.ReadCollectionCount()
+		if err != nil {
+			return nil, err
+		}
+
+		if childCount > 0 {
+			node.Children = make([]*VNodeWire, childCount)
+			for i := 0; i < childCount; i++ {
+				// SECURITY: Increment depth for child nodes
+				child, err := decodeVNodeWireWithDepth(d, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				node.Children[i] = child
+			}
+		}
+
 	default:
 		// Unknown kind - try to continue
 	}
@@ -274,6 +311,16 @@ func NewFragmentWire(children ...*VNodeWire) *VNodeWire {
 	}
 }
 
+// NewPortalWire creates a portal VNodeWire mounted into target.
+func NewPortalWire(target, hid string, children ...*VNodeWire) *VNodeWire {
+	return &VNodeWire{
+		Kind:     vdom.KindPortal,
+		Tag:      target,
+		HID:      hid,
+		Children: children,
+	}
+}
+
 // NewRawWire creates a raw HTML VNodeWire.
 func NewRawWire(html string) *VNodeWire {
 	return &VNodeWire{
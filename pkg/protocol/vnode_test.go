@@ -56,6 +56,12 @@ func TestVNodeWireEncodeDecode(t *testing.T) {
 			name: "raw_html",
 			node: NewRawWire("<strong>Bold</strong>"),
 		},
+		{
+			name: "portal",
+			node: NewPortalWire("#modals", "h7",
+				NewElementWire("div", map[string]string{"class": "modal"}, NewTextWire("Hi")),
+			),
+		},
 		{
 			name: "deeply_nested",
 			node: NewElementWire("div", map[string]string{"class": "l1"},
@@ -46,7 +46,16 @@
 // For large pages, use StreamingRenderer to flush content incrementally:
 //
 //	sr := render.NewStreamingRenderer(w, config)
-//	err := sr.RenderPage(page)
+//	err := sr.RenderPageContext(r.Context(), page)
+//
+// Suspense boundaries (resource.Suspense) that are waiting on data do not
+// hold up the page: their fallback is streamed in place, and the resolved
+// content follows at the end of the body, out of order, with an inline
+// script that swaps it in. Fallbacks get no HIDs; the server numbers the
+// resolved content with its final HIDs and tells the swap script how far to
+// shift the HIDs after it, so the document matches the final tree for
+// hydration. RendererConfig.SuspenseTimeout (DefaultSuspenseTimeout if zero)
+// and the request context bound how long the response stays open.
 //
 // # Security
 //
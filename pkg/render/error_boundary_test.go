@@ -0,0 +1,63 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestRenderErrorBoundary(t *testing.T) {
+	broken := vdom.Func(func() *vdom.VNode {
+		return vdom.Div(vdom.Button(vdom.OnClick(func() {}), vdom.Text("half")), vdom.Func(func() *vdom.VNode {
+			panic("boom")
+		}))
+	})
+	fallback := func(err error, reset func()) *vdom.VNode {
+		return vdom.Button(vdom.OnClick(reset), vdom.Text("failed: "+err.Error()))
+	}
+
+	t.Run("renders children", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{})
+		html, err := renderer.RenderToString(vango.ErrorBoundary(fallback, vdom.P(vdom.Text("ok"))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(html, `>ok</p>`) || !strings.Contains(html, `data-error-boundary`) {
+			t.Errorf("got %s", html)
+		}
+	})
+
+	t.Run("renders fallback and discards partial output", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{})
+		html, err := renderer.RenderToString(vdom.Div(vango.ErrorBoundary(fallback, broken)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(html, "half") {
+			t.Errorf("partial output leaked: %s", html)
+		}
+		if !strings.Contains(html, "failed: render panic: boom") {
+			t.Errorf("fallback missing: %s", html)
+		}
+		// HIDs of the discarded children are reused so the fallback lines up
+		// with the live session's first render.
+		if !strings.Contains(html, `data-hid="h3"`) || strings.Contains(html, `data-hid="h4"`) {
+			t.Errorf("HIDs not rolled back: %s", html)
+		}
+	})
+
+	t.Run("nil fallback uses the error page", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{ErrorPage: func(err error) *vdom.VNode {
+			return vdom.H1(vdom.Text("error page"))
+		}})
+		html, err := renderer.RenderToString(vango.ErrorBoundary(nil, broken))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(html, `>error page</h1>`) {
+			t.Errorf("got %s", html)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// ApplyHead merges the Title, Meta and Links of page into the <head> of root.
+//
+// This is used when a layout renders the full document itself, so the page
+// title and SEO tags can be supplied separately (for example by a route's
+// Meta function). An existing <title> is replaced. Meta tags with the same
+// name, property or http-equiv and link tags with the same rel are updated
+// in place; other tags are appended to <head>.
+//
+// If root contains no <head>, a new one is created: inside <html> when root
+// is an <html> element, otherwise as the first node of a fragment wrapping
+// root. ApplyHead returns the (possibly new) root.
+func ApplyHead(root *vdom.VNode, page PageData) *vdom.VNode {
+	if root == nil || (page.Title == "" && len(page.Meta) == 0 && len(page.Links) == 0) {
+		return root
+	}
+
+	head := findHead(root)
+	if head == nil {
+		head = &vdom.VNode{Kind: vdom.KindElement, Tag: "head"}
+		if root.Kind == vdom.KindElement && root.Tag == "html" {
+			root.Children = append([]*vdom.VNode{head}, root.Children...)
+		} else {
+			root = &vdom.VNode{Kind: vdom.KindFragment, Children: []*vdom.VNode{head, root}}
+		}
+	}
+
+	if page.Title != "" {
+		title := &vdom.VNode{
+			Kind:     vdom.KindElement,
+			Tag:      "title",
+			Children: []*vdom.VNode{{Kind: vdom.KindText, Text: page.Title}},
+		}
+		if i := findHeadChild(head, "title", "", ""); i >= 0 {
+			title.HID = head.Children[i].HID
+			head.Children[i] = title
+		} else {
+			head.Children = append(head.Children, title)
+		}
+	}
+
+	for _, meta := range page.Meta {
+		node := metaTagNode(meta)
+		attr, value := metaTagIdentity(meta)
+		if i := findHeadChild(head, "meta", attr, value); attr != "" && i >= 0 {
+			node.HID = head.Children[i].HID
+			head.Children[i] = node
+			continue
+		}
+		head.Children = append(head.Children, node)
+	}
+
+	for _, link := range page.Links {
+		node := linkTagNode(link)
+		if i := findHeadChild(head, "link", "rel", link.Rel); link.Rel != "" && link.Rel != "stylesheet" && i >= 0 {
+			node.HID = head.Children[i].HID
+			head.Children[i] = node
+			continue
+		}
+		head.Children = append(head.Children, node)
+	}
+
+	return root
+}
+
+// DocumentTitle returns the text of the <title> in the <head> of root, or ""
+// if there is none.
+func DocumentTitle(root *vdom.VNode) string {
+	head := findHead(root)
+	if head == nil {
+		return ""
+	}
+	i := findHeadChild(head, "title", "", "")
+	if i < 0 {
+		return ""
+	}
+	var title strings.Builder
+	for _, child := range head.Children[i].Children {
+		if child != nil && child.Kind == vdom.KindText {
+			title.WriteString(child.Text)
+		}
+	}
+	return title.String()
+}
+
+// findHead returns the first <head> element in the tree, without descending
+// into <body>.
+func findHead(node *vdom.VNode) *vdom.VNode {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == vdom.KindElement {
+		switch node.Tag {
+		case "head":
+			return node
+		case "body":
+			return nil
+		}
+	}
+	for _, child := range node.Children {
+		if head := findHead(child); head != nil {
+			return head
+		}
+	}
+	return nil
+}
+
+// findHeadChild returns the index of the first direct child of head with the
+// given tag and, if attr is set, with attr equal to value. Returns -1 if none.
+func findHeadChild(head *vdom.VNode, tag, attr, value string) int {
+	for i, child := range head.Children {
+		if child == nil || child.Kind != vdom.KindElement || child.Tag != tag {
+			continue
+		}
+		if attr == "" {
+			return i
+		}
+		if v, ok := child.Props[attr].(string); ok && v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// metaTagIdentity returns the attribute that identifies a meta tag.
+func metaTagIdentity(meta MetaTag) (attr, value string) {
+	switch {
+	case meta.Name != "":
+		return "name", meta.Name
+	case meta.Property != "":
+		return "property", meta.Property
+	case meta.HTTPEquiv != "":
+		return "http-equiv", meta.HTTPEquiv
+	case meta.Charset != "":
+		return "charset", meta.Charset
+	}
+	return "", ""
+}
+
+// metaTagNode converts a MetaTag to a <meta> element.
+func metaTagNode(meta MetaTag) *vdom.VNode {
+	props := vdom.Props{}
+	setProp(props, "charset", meta.Charset)
+	setProp(props, "name", meta.Name)
+	setProp(props, "property", meta.Property)
+	setProp(props, "http-equiv", meta.HTTPEquiv)
+	setProp(props, "content", meta.Content)
+	return &vdom.VNode{Kind: vdom.KindElement, Tag: "meta", Props: props}
+}
+
+// linkTagNode converts a LinkTag to a <link> element.
+func linkTagNode(link LinkTag) *vdom.VNode {
+	props := vdom.Props{}
+	setProp(props, "rel", link.Rel)
+	setProp(props, "href", link.Href)
+	setProp(props, "type", link.Type)
+	setProp(props, "sizes", link.Sizes)
+	setProp(props, "crossorigin", link.CrossOrigin)
+	setProp(props, "media", link.Media)
+	return &vdom.VNode{Kind: vdom.KindElement, Tag: "link", Props: props}
+}
+
+func setProp(props vdom.Props, key, value string) {
+	if value != "" {
+		props[key] = value
+	}
+}
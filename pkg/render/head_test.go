@@ -0,0 +1,103 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+var hidAttrPattern = regexp.MustCompile(` data-hid="[^"]*"`)
+
+// renderHeadTest renders root without hydration IDs.
+func renderHeadTest(t *testing.T, root *vdom.VNode) string {
+	t.Helper()
+	html, err := NewRenderer(RendererConfig{}).RenderToString(root)
+	if err != nil {
+		t.Fatalf("RenderToString error: %v", err)
+	}
+	return hidAttrPattern.ReplaceAllString(html, "")
+}
+
+func TestApplyHead_ReplacesAndAppends(t *testing.T) {
+	root := vdom.Html(
+		vdom.Head(
+			vdom.Meta(vdom.Charset("utf-8")),
+			vdom.Title(vdom.Text("Layout Title")),
+			vdom.Meta(vdom.Name("description"), vdom.Content("layout description")),
+		),
+		vdom.Body(vdom.Div(vdom.Text("content"))),
+	)
+
+	root = ApplyHead(root, PageData{
+		Title: "Page Title",
+		Meta: []MetaTag{
+			{Name: "description", Content: "page description"},
+			{Property: "og:title", Content: "OG Title"},
+		},
+		Links: []LinkTag{{Rel: "canonical", Href: "https://example.com/page"}},
+	})
+
+	html := renderHeadTest(t, root)
+	if strings.Contains(html, "Layout Title") || !strings.Contains(html, "<title>Page Title</title>") {
+		t.Errorf("title not replaced: %s", html)
+	}
+	if strings.Contains(html, "layout description") || strings.Count(html, `name="description"`) != 1 {
+		t.Errorf("description not replaced in place: %s", html)
+	}
+	if !strings.Contains(html, `property="og:title"`) || !strings.Contains(html, `content="OG Title"`) {
+		t.Errorf("og:title missing: %s", html)
+	}
+	if !strings.Contains(html, `rel="canonical"`) {
+		t.Errorf("canonical link missing: %s", html)
+	}
+
+	// Everything must stay inside <head>.
+	headEnd := strings.Index(html, "</head>")
+	if headEnd < 0 || strings.Index(html, "canonical") > headEnd {
+		t.Errorf("tags rendered outside <head>: %s", html)
+	}
+}
+
+func TestApplyHead_CreatesHeadInHTML(t *testing.T) {
+	root := vdom.Html(vdom.Body(vdom.Text("content")))
+
+	root = ApplyHead(root, PageData{Title: "Created"})
+
+	html := renderHeadTest(t, root)
+	if !strings.Contains(html, "<html><head><title>Created</title></head><body>") {
+		t.Errorf("expected head before body, got %s", html)
+	}
+}
+
+func TestApplyHead_WrapsBareRoot(t *testing.T) {
+	root := vdom.Div(vdom.Text("content"))
+
+	got := ApplyHead(root, PageData{Title: "Bare"})
+
+	if got.Kind != vdom.KindFragment || len(got.Children) != 2 || got.Children[1] != root {
+		t.Fatalf("expected fragment wrapping root, got %+v", got)
+	}
+	html := renderHeadTest(t, got)
+	if !strings.HasPrefix(html, "<head><title>Bare</title></head>") {
+		t.Errorf("expected head first, got %s", html)
+	}
+}
+
+func TestApplyHead_NoopWithoutData(t *testing.T) {
+	root := vdom.Div()
+	if got := ApplyHead(root, PageData{}); got != root || len(root.Children) != 0 {
+		t.Error("ApplyHead should not modify the tree when there is nothing to apply")
+	}
+}
+
+func TestDocumentTitle(t *testing.T) {
+	root := vdom.Html(vdom.Head(vdom.Title(vdom.Text("Layout Title"))), vdom.Body())
+	if got := DocumentTitle(root); got != "Layout Title" {
+		t.Errorf("DocumentTitle = %q, want %q", got, "Layout Title")
+	}
+	if got := DocumentTitle(vdom.Div(vdom.Text("no head"))); got != "" {
+		t.Errorf("DocumentTitle without head = %q, want empty", got)
+	}
+}
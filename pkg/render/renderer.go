@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vango-go/vango/pkg/vango"
 	"github.com/vango-go/vango/pkg/vdom"
@@ -30,6 +31,16 @@ type RendererConfig struct {
 
 	// InlineCriticalCSS indicates whether to inline critical CSS.
 	InlineCriticalCSS bool
+
+	// SuspenseTimeout bounds how long StreamingRenderer keeps the response
+	// open for suspended boundaries. When it elapses, unresolved boundaries
+	// keep their fallback. Zero uses DefaultSuspenseTimeout; a negative
+	// value waits until every boundary resolves or the request ends.
+	SuspenseTimeout time.Duration
+
+	// ErrorPage renders the fallback of an ErrorBoundary that has none when
+	// its children panic. If nil, such a boundary renders empty.
+	ErrorPage func(err error) *vdom.VNode
 }
 
 // Renderer handles server-side rendering of VNode trees to HTML.
@@ -37,6 +48,13 @@ type Renderer struct {
 	config     RendererConfig
 	hidCounter uint32
 	handlers   map[string]any
+
+	// Suspense streaming state (see StreamingRenderer)
+	suspend         bool
+	skipHIDs        int
+	boundaryCounter int
+	boundaries      []*suspendedBoundary
+	boundaryPos     []int // pos of the boundary whose content is rendering
 }
 
 // NewRenderer creates a new Renderer with the given configuration.
@@ -94,6 +112,8 @@ func (r *Renderer) renderNode(w io.Writer, node *vdom.VNode, depth int) error {
 		return r.renderComponent(w, node, depth)
 	case vdom.KindRaw:
 		return r.renderRaw(w, node)
+	case vdom.KindPortal:
+		return r.renderPortal(w, node, depth)
 	default:
 		return fmt.Errorf("unknown node kind: %d", node.Kind)
 	}
@@ -122,7 +142,7 @@ func (r *Renderer) renderElement(w io.Writer, node *vdom.VNode, depth int) error
 	}
 
 	// Check if this element needs a hydration ID
-	if r.needsHID(node) {
+	if r.needsHID(node) && r.skipHIDs == 0 {
 		var hid string
 		if node.HID != "" {
 			// Preserve existing HID (set by vdom.AssignHIDs during RebuildHandlers)
@@ -207,10 +227,54 @@ func (r *Renderer) renderFragment(w io.Writer, node *vdom.VNode, depth int) erro
 	return nil
 }
 
+// renderPortal renders a portal's children in place inside a <vango-portal>
+// container. The client moves the container into its target on boot, so the
+// markup stays valid wherever the portal was declared.
+func (r *Renderer) renderPortal(w io.Writer, node *vdom.VNode, depth int) error {
+	if r.config.Pretty && depth > 0 {
+		r.writeIndent(w, depth)
+	}
+
+	if _, err := fmt.Fprintf(w, `<vango-portal data-portal-target="%s"`, escapeAttr(node.Tag)); err != nil {
+		return err
+	}
+	if r.skipHIDs == 0 {
+		if node.HID == "" {
+			node.HID = r.nextHID()
+		}
+		if _, err := fmt.Fprintf(w, ` data-hid="%s"`, node.HID); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write([]byte(` style="display:contents">`)); err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		if err := r.renderNode(w, child, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write([]byte("</vango-portal>")); err != nil {
+		return err
+	}
+	if r.config.Pretty {
+		w.Write([]byte{'\n'})
+	}
+	return nil
+}
+
 // renderComponent renders a component by rendering its output VNode.
 func (r *Renderer) renderComponent(w io.Writer, node *vdom.VNode, depth int) error {
 	// If the component has already been rendered to a VNode, render that
 	if node.Comp != nil {
+		if s, ok := node.Comp.(Suspender); ok && r.suspend {
+			return r.renderSuspense(w, s, depth)
+		}
+		if b, ok := node.Comp.(*vango.ErrorBoundaryComponent); ok {
+			return r.renderErrorBoundary(w, b, depth)
+		}
 		output := node.Comp.Render()
 		return r.renderNode(w, output, depth)
 	}
@@ -484,3 +548,38 @@ func (r *Renderer) writeIndent(w io.Writer, depth int) {
 		w.Write([]byte(r.config.Indent))
 	}
 }
+
+// renderErrorBoundary renders the children of an ErrorBoundary into a
+// buffer, and its fallback instead if they panic. The HID counter is rolled
+// back so the fallback gets the same HIDs as in the live session, where the
+// broken subtree is never assigned any.
+func (r *Renderer) renderErrorBoundary(w io.Writer, b *vango.ErrorBoundaryComponent, depth int) error {
+	var buf bytes.Buffer
+	hidCounter := r.hidCounter
+	panicErr, err := r.renderCatching(&buf, b, depth)
+	if panicErr == nil {
+		if err != nil {
+			return err
+		}
+		_, err = buf.WriteTo(w)
+		return err
+	}
+
+	r.hidCounter = hidCounter
+	return r.renderNode(w, b.RenderFallback(panicErr, func() {}, r.config.ErrorPage), depth)
+}
+
+// renderCatching renders the children of b into w. A panic is recovered and
+// returned as panicErr.
+func (r *Renderer) renderCatching(w io.Writer, b *vango.ErrorBoundaryComponent, depth int) (panicErr, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			if e, ok := p.(error); ok {
+				panicErr = e
+			} else {
+				panicErr = fmt.Errorf("render panic: %v", p)
+			}
+		}
+	}()
+	return nil, r.renderNode(w, b.Render(), depth)
+}
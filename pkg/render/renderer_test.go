@@ -161,6 +161,27 @@ func TestRenderFragment(t *testing.T) {
 	}
 }
 
+func TestRenderPortal(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+
+	node := vdom.Div(
+		vdom.Portal("#modals", vdom.Button(vdom.OnClick(func() {}), vdom.Text("Close"))),
+	)
+	html, err := renderer.RenderToString(node)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `<div data-hid="h1"><vango-portal data-portal-target="#modals" data-hid="h2" style="display:contents">` +
+		`<button data-ve="click" data-hid="h3">Close</button></vango-portal></div>`
+	if html != want {
+		t.Errorf("got %q, want %q", html, want)
+	}
+	if _, ok := renderer.GetHandlers()["h3_onclick"]; !ok {
+		t.Errorf("handler inside portal not registered: %v", renderer.GetHandlers())
+	}
+}
+
 func TestRenderHydrationID(t *testing.T) {
 	renderer := NewRenderer(RendererConfig{})
 
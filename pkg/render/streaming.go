@@ -1,6 +1,7 @@
 package render
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -8,6 +9,11 @@ import (
 
 // StreamingRenderer wraps Renderer with chunked output support.
 // It flushes content incrementally for faster time-to-first-byte.
+//
+// Suspense boundaries (see Suspender) that are still waiting on data stream
+// their fallback in place. The response stays open and each boundary's
+// content is streamed after the body as soon as it resolves, with a small
+// inline script that swaps it into place before the client script runs.
 type StreamingRenderer struct {
 	*Renderer
 	flusher http.Flusher
@@ -28,7 +34,17 @@ func NewStreamingRenderer(w http.ResponseWriter, config RendererConfig) *Streami
 
 // RenderPage renders a complete HTML document with incremental flushing.
 // The head section is flushed immediately for faster first paint.
+//
+// RenderPage does not stop waiting for suspended boundaries when the client
+// goes away; use RenderPageContext with the request context.
 func (s *StreamingRenderer) RenderPage(page PageData) error {
+	return s.RenderPageContext(context.Background(), page)
+}
+
+// RenderPageContext is like RenderPage, but stops waiting for suspended
+// boundaries and returns ctx.Err() when ctx ends, such as when the client
+// of r.Context() disconnects.
+func (s *StreamingRenderer) RenderPageContext(ctx context.Context, page PageData) error {
 	// Set default language
 	lang := page.Lang
 	if lang == "" {
@@ -59,13 +75,20 @@ func (s *StreamingRenderer) RenderPage(page PageData) error {
 	}
 
 	// Main content
+	s.suspend = true
+	defer func() { s.suspend = false }()
 	if err := s.RenderToWriter(s.w, page.Body); err != nil {
 		return err
 	}
 
-	// Flush body content
+	// Flush body content (with any Suspense fallbacks)
 	s.flush()
 
+	// Stream suspended boundaries as they resolve
+	if err := s.streamBoundaries(ctx); err != nil {
+		return err
+	}
+
 	// Inject Vango client script
 	if err := s.renderClientScript(s.w, page); err != nil {
 		return err
@@ -0,0 +1,226 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// Suspender is implemented by boundary components whose content may be
+// waiting on asynchronous data, such as resource.Suspense.
+//
+// StreamingRenderer streams the fallback of a suspended boundary in place,
+// keeps the response open, and streams the resolved content once ready is
+// closed. Renderer.RenderToString and RenderPage call Render instead, so a
+// suspended boundary renders its fallback there.
+type Suspender interface {
+	vdom.Component
+
+	// Suspend renders the boundary. If the content is ready it returns the
+	// content and a nil channel. Otherwise it returns the fallback and a
+	// channel that is closed when Suspend should be called again.
+	Suspend() (node *vdom.VNode, ready <-chan struct{})
+}
+
+// DefaultSuspenseTimeout is how long StreamingRenderer waits for suspended
+// boundaries when RendererConfig.SuspenseTimeout is zero.
+const DefaultSuspenseTimeout = 10 * time.Second
+
+// suspendedBoundary is a boundary whose fallback was streamed in place of its
+// content.
+type suspendedBoundary struct {
+	id       int
+	comp     Suspender
+	fallback *vdom.VNode
+	ready    <-chan struct{}
+
+	// hidBase is the number of HIDs before the boundary in the document as
+	// streamed so far. Its content is numbered from hidBase+1.
+	hidBase uint32
+
+	// pos orders boundaries in the document: the IDs of the enclosing
+	// boundaries followed by its own.
+	pos []int
+}
+
+// before reports whether b comes before o in the document.
+func (b *suspendedBoundary) before(o *suspendedBoundary) bool {
+	for i := 0; i < len(b.pos) && i < len(o.pos); i++ {
+		if b.pos[i] != o.pos[i] {
+			return b.pos[i] < o.pos[i]
+		}
+	}
+	return len(b.pos) < len(o.pos)
+}
+
+// suspenseSwapScript defines the inline function that moves resolved boundary
+// content from its <template> into place. The fallback between the boundary
+// markers is removed. The content already carries its final HIDs; the server
+// passes the HIDs before the boundary (b) and the number in the content (c),
+// and elements numbered after the boundary are shifted up by c to make room.
+const suspenseSwapScript = `<script>window.$vangoSwap=function(i,b,c){var d=document,t=d.getElementById("vango-s:"+i),w=d.createTreeWalker(d.body,128),s,e,n;while((n=w.nextNode())){if(n.data==="vango-s:"+i)s=n;else if(n.data==="/vango-s:"+i){e=n;break}}if(t&&s&&e){if(c)d.querySelectorAll("[data-hid]").forEach(function(el){var k=+el.getAttribute("data-hid").slice(1);if(k>b)el.setAttribute("data-hid","h"+(k+c))});while(s.nextSibling!==e)s.parentNode.removeChild(s.nextSibling);e.parentNode.insertBefore(t.content,e);s.remove();e.remove();t.remove()}if(d.currentScript)d.currentScript.remove()};</script>`
+
+// renderSuspense renders a Suspense boundary while streaming. A suspended
+// boundary writes its fallback between comment markers, without HIDs, and is
+// queued for streamBoundaries.
+func (r *Renderer) renderSuspense(w io.Writer, comp Suspender, depth int) error {
+	node, ready := comp.Suspend()
+	if ready == nil {
+		return r.renderNode(w, node, depth)
+	}
+
+	r.boundaryCounter++
+	b := &suspendedBoundary{
+		id:       r.boundaryCounter,
+		comp:     comp,
+		fallback: node,
+		ready:    ready,
+		hidBase:  r.hidCounter,
+		pos:      append(append([]int(nil), r.boundaryPos...), r.boundaryCounter),
+	}
+	r.boundaries = append(r.boundaries, b)
+
+	if _, err := fmt.Fprintf(w, "<!--vango-s:%d-->", b.id); err != nil {
+		return err
+	}
+	// The fallback is replaced on the client, so it must not consume HIDs
+	// that belong to the final tree.
+	r.suspend = false
+	r.skipHIDs++
+	err := r.renderNode(w, node, depth)
+	r.skipHIDs--
+	r.suspend = true
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "<!--/vango-s:%d-->", b.id)
+	return err
+}
+
+// streamBoundaries waits for the suspended boundaries queued while rendering
+// the body and streams each one as soon as it resolves, in resolution order.
+// Boundaries that suspend again are re-queued, and boundaries nested in
+// resolved content are picked up as they are rendered.
+//
+// If the suspense timeout elapses first, the remaining boundaries are
+// streamed with their fallback, which then keeps HIDs like any other content.
+// If ctx ends first, streaming stops and ctx.Err() is returned.
+func (s *StreamingRenderer) streamBoundaries(ctx context.Context) error {
+	if len(s.boundaries) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(s.w, suspenseSwapScript); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	resolved := make(chan *suspendedBoundary)
+	pending := make(map[int]*suspendedBoundary)
+
+	watch := func() {
+		for _, b := range s.boundaries {
+			pending[b.id] = b
+			go func(b *suspendedBoundary) {
+				select {
+				case <-b.ready:
+					select {
+					case resolved <- b:
+					case <-done:
+					}
+				case <-done:
+				}
+			}(b)
+		}
+		s.boundaries = nil
+	}
+	watch()
+
+	// write streams b with its final content and makes room for its HIDs
+	// in the boundaries still pending after it.
+	write := func(b *suspendedBoundary, node *vdom.VNode) error {
+		delete(pending, b.id)
+		n, err := s.writeBoundary(b, node)
+		if err != nil {
+			return err
+		}
+		for _, o := range pending {
+			if b.before(o) {
+				o.hidBase += n
+			}
+		}
+		return nil
+	}
+
+	timeout := s.config.SuspenseTimeout
+	if timeout == 0 {
+		timeout = DefaultSuspenseTimeout
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for len(pending) > 0 {
+		select {
+		case b := <-resolved:
+			node, ready := b.comp.Suspend()
+			if ready != nil {
+				b.fallback, b.ready = node, ready
+				s.boundaries = append(s.boundaries, b)
+				watch()
+				continue
+			}
+			if err := write(b, node); err != nil {
+				return err
+			}
+			watch()
+
+		case <-expired:
+			s.suspend = false
+			for id := 1; id <= s.boundaryCounter; id++ {
+				if b, ok := pending[id]; ok {
+					if err := write(b, b.fallback); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// writeBoundary streams the final content of b in a template, numbered from
+// b.hidBase, followed by the script that swaps it into place, and flushes.
+// It returns the number of HIDs in the content.
+func (s *StreamingRenderer) writeBoundary(b *suspendedBoundary, node *vdom.VNode) (uint32, error) {
+	if _, err := fmt.Fprintf(s.w, `<template id="vango-s:%d">`, b.id); err != nil {
+		return 0, err
+	}
+
+	total := s.hidCounter
+	s.hidCounter = b.hidBase
+	s.boundaryPos = b.pos
+	err := s.renderNode(s.w, node, 0)
+	s.boundaryPos = nil
+	n := s.hidCounter - b.hidBase
+	s.hidCounter = total + n
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := fmt.Fprintf(s.w, `</template><script>$vangoSwap(%d,%d,%d)</script>`+"\n", b.id, b.hidBase, n); err != nil {
+		return 0, err
+	}
+	s.flush()
+	return n, nil
+}
@@ -0,0 +1,319 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// testSuspender is a boundary that stays suspended until resolve is called.
+type testSuspender struct {
+	mu       sync.Mutex
+	fallback func() *vdom.VNode
+	content  func() *vdom.VNode
+	ready    chan struct{}
+	resolved bool
+}
+
+func newTestSuspender(fallback, content func() *vdom.VNode) *testSuspender {
+	return &testSuspender{fallback: fallback, content: content, ready: make(chan struct{})}
+}
+
+func (s *testSuspender) resolve() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolved = true
+	close(s.ready)
+}
+
+func (s *testSuspender) Render() *vdom.VNode {
+	node, _ := s.Suspend()
+	return node
+}
+
+func (s *testSuspender) Suspend() (*vdom.VNode, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resolved {
+		return s.content(), nil
+	}
+	return s.fallback(), s.ready
+}
+
+// notifyWriter records writes and signals each flush.
+type notifyWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	flushed chan string
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *notifyWriter) Flush() {
+	w.mu.Lock()
+	out := w.buf.String()
+	w.mu.Unlock()
+	select {
+	case w.flushed <- out:
+	default:
+	}
+}
+
+// applySwaps mimics the inline swap script: it shifts the HIDs after each
+// boundary, moves the template's content between its boundary markers and
+// drops the scripts.
+func applySwaps(html string) string {
+	tmpl := regexp.MustCompile(`(?s)<template id="vango-s:(\d+)">(.*?)</template><script>\$vangoSwap\(\d+,(\d+),(\d+)\)</script>\n?`)
+	hid := regexp.MustCompile(`data-hid="h(\d+)"`)
+	for {
+		m := tmpl.FindStringSubmatch(html)
+		if m == nil {
+			break
+		}
+		// Only the document parsed so far, before the template, is shifted.
+		at := strings.Index(html, m[0])
+		base, _ := strconv.Atoi(m[3])
+		shift, _ := strconv.Atoi(m[4])
+		parsed := hid.ReplaceAllStringFunc(html[:at], func(attr string) string {
+			n, _ := strconv.Atoi(hid.FindStringSubmatch(attr)[1])
+			if n > base {
+				n += shift
+			}
+			return fmt.Sprintf(`data-hid="h%d"`, n)
+		})
+		html = parsed + html[at+len(m[0]):]
+		slot := regexp.MustCompile(`(?s)<!--vango-s:` + m[1] + `-->.*?<!--/vango-s:` + m[1] + `-->`)
+		html = slot.ReplaceAllLiteralString(html, m[2])
+	}
+	return strings.Replace(html, suspenseSwapScript, "", 1)
+}
+
+func TestStreamingRendererSuspenseStreamsFallbackFirst(t *testing.T) {
+	boundary := newTestSuspender(
+		func() *vdom.VNode { return vdom.P(vdom.Text("Loading...")) },
+		func() *vdom.VNode { return vdom.Ul(vdom.Li(vdom.Text("Alice")), vdom.Li(vdom.Text("Bob"))) },
+	)
+
+	w := &notifyWriter{flushed: make(chan string, 16)}
+	sr := &StreamingRenderer{Renderer: NewRenderer(RendererConfig{}), flusher: w, w: w}
+
+	page := PageData{
+		Body: vdom.Div(
+			vdom.H1(vdom.Text("Users")),
+			&vdom.VNode{Kind: vdom.KindComponent, Comp: boundary},
+			vdom.Footer(vdom.Text("Footer")),
+		),
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- sr.RenderPage(page) }()
+
+	// The body, with the fallback in place, must be flushed before the
+	// boundary resolves.
+	deadline := time.After(2 * time.Second)
+	for {
+		var out string
+		select {
+		case out = <-w.flushed:
+		case <-deadline:
+			t.Fatal("body was not flushed before the boundary resolved")
+		}
+		if strings.Contains(out, "Footer") {
+			if !strings.Contains(out, "<!--vango-s:1--><p>Loading...</p><!--/vango-s:1-->") {
+				t.Fatalf("fallback should be streamed without HIDs between markers, got %q", out)
+			}
+			if strings.Contains(out, "Alice") || strings.Contains(out, "</html>") {
+				t.Fatalf("content streamed before the boundary resolved: %q", out)
+			}
+			break
+		}
+	}
+
+	boundary.resolve()
+	if err := <-errc; err != nil {
+		t.Fatalf("RenderPage: %v", err)
+	}
+
+	html := w.buf.String()
+	if !strings.Contains(html, "window.$vangoSwap=") {
+		t.Error("expected the swap script to be defined")
+	}
+	tmpl := strings.Index(html, `<template id="vango-s:1">`)
+	client := strings.Index(html, "/_vango/client.js")
+	if tmpl < 0 || !strings.Contains(html, "<script>$vangoSwap(1,2,3)</script>") {
+		t.Fatalf("expected resolved content and swap call, got %q", html)
+	}
+	if client < tmpl {
+		t.Error("resolved content must be streamed before the client script")
+	}
+
+	// After the swap, the document must match a direct render of the final tree.
+	final := vdom.Div(
+		vdom.H1(vdom.Text("Users")),
+		vdom.Ul(vdom.Li(vdom.Text("Alice")), vdom.Li(vdom.Text("Bob"))),
+		vdom.Footer(vdom.Text("Footer")),
+	)
+	want, err := NewRenderer(RendererConfig{}).RenderToString(final)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapped := applySwaps(html)
+	if !strings.Contains(swapped, "<body>\n"+want) {
+		t.Errorf("swapped document does not match the final tree\nwant body: %s\ngot: %s", want, swapped)
+	}
+}
+
+func TestStreamingRendererSuspenseAssignsFinalHIDs(t *testing.T) {
+	list := func(names ...string) func() *vdom.VNode {
+		return func() *vdom.VNode {
+			items := make([]any, len(names))
+			for i, n := range names {
+				items[i] = vdom.Li(vdom.Text(n))
+			}
+			return vdom.Ul(items...)
+		}
+	}
+	loading := func() *vdom.VNode { return vdom.P(vdom.Text("Loading...")) }
+	inner := newTestSuspender(loading, list("Carol"))
+	first := newTestSuspender(loading, func() *vdom.VNode {
+		return vdom.Section(list("Alice", "Bob")(), &vdom.VNode{Kind: vdom.KindComponent, Comp: inner})
+	})
+	second := newTestSuspender(loading, list("Dave"))
+
+	w := &notifyWriter{flushed: make(chan string, 16)}
+	sr := &StreamingRenderer{Renderer: NewRenderer(RendererConfig{}), flusher: w, w: w}
+	page := PageData{Body: vdom.Div(
+		vdom.H1(vdom.Text("Users")),
+		&vdom.VNode{Kind: vdom.KindComponent, Comp: first},
+		vdom.Hr(),
+		&vdom.VNode{Kind: vdom.KindComponent, Comp: second},
+		vdom.Footer(vdom.Text("Footer")),
+	)}
+
+	errc := make(chan error, 1)
+	go func() { errc <- sr.RenderPage(page) }()
+
+	// Resolve out of document order, one at a time.
+	for _, b := range []*testSuspender{second, first, inner} {
+		<-w.flushed
+		b.resolve()
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("RenderPage: %v", err)
+	}
+
+	final := vdom.Div(
+		vdom.H1(vdom.Text("Users")),
+		vdom.Section(list("Alice", "Bob")(), list("Carol")()),
+		vdom.Hr(),
+		list("Dave")(),
+		vdom.Footer(vdom.Text("Footer")),
+	)
+	want, err := NewRenderer(RendererConfig{}).RenderToString(final)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped := applySwaps(w.buf.String()); !strings.Contains(swapped, "<body>\n"+want) {
+		t.Errorf("swapped document does not match the final tree\nwant body: %s\ngot: %s", want, swapped)
+	}
+}
+
+func TestStreamingRendererSuspenseStopsWithContext(t *testing.T) {
+	boundary := newTestSuspender(
+		func() *vdom.VNode { return vdom.P(vdom.Text("Loading...")) },
+		func() *vdom.VNode { return vdom.Span(vdom.Text("Never")) },
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &notifyWriter{flushed: make(chan string, 16)}
+	sr := &StreamingRenderer{Renderer: NewRenderer(RendererConfig{SuspenseTimeout: -1}), flusher: w, w: w}
+
+	errc := make(chan error, 1)
+	go func() { errc <- sr.RenderPageContext(ctx, PageData{Body: &vdom.VNode{Kind: vdom.KindComponent, Comp: boundary}}) }()
+	<-w.flushed
+	cancel()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("RenderPageContext = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RenderPageContext kept waiting after the context ended")
+	}
+}
+
+func TestStreamingRendererSuspenseResolvedImmediately(t *testing.T) {
+	boundary := newTestSuspender(
+		func() *vdom.VNode { return vdom.P(vdom.Text("Loading...")) },
+		func() *vdom.VNode { return vdom.Span(vdom.Text("Ready")) },
+	)
+	boundary.resolve()
+
+	var buf bytes.Buffer
+	sr := &StreamingRenderer{Renderer: NewRenderer(RendererConfig{}), w: &buf}
+	if err := sr.RenderPage(PageData{Body: &vdom.VNode{Kind: vdom.KindComponent, Comp: boundary}}); err != nil {
+		t.Fatal(err)
+	}
+
+	html := buf.String()
+	if !strings.Contains(html, `<span data-hid="h1">Ready</span>`) {
+		t.Errorf("resolved boundary should render inline, got %q", html)
+	}
+	if strings.Contains(html, "vango-s") || strings.Contains(html, "$vangoSwap") {
+		t.Errorf("resolved boundary should not use markers or the swap script, got %q", html)
+	}
+}
+
+func TestStreamingRendererSuspenseTimeoutKeepsFallback(t *testing.T) {
+	boundary := newTestSuspender(
+		func() *vdom.VNode { return vdom.P(vdom.Text("Loading...")) },
+		func() *vdom.VNode { return vdom.Span(vdom.Text("Never")) },
+	)
+
+	var buf bytes.Buffer
+	sr := &StreamingRenderer{
+		Renderer: NewRenderer(RendererConfig{SuspenseTimeout: 20 * time.Millisecond}),
+		w:        &buf,
+	}
+	if err := sr.RenderPage(PageData{Body: vdom.Div(&vdom.VNode{Kind: vdom.KindComponent, Comp: boundary})}); err != nil {
+		t.Fatal(err)
+	}
+
+	html := buf.String()
+	if strings.Contains(html, "Never") {
+		t.Fatalf("unresolved content should not be rendered, got %q", html)
+	}
+	// The fallback is swapped in as final content, so it now has HIDs.
+	if !strings.Contains(html, `<template id="vango-s:1"><p data-hid="h2">Loading...</p></template>`) {
+		t.Errorf("expected the fallback to be streamed as the boundary content, got %q", html)
+	}
+}
+
+func TestRendererRenderToStringUsesSuspenderRender(t *testing.T) {
+	boundary := newTestSuspender(
+		func() *vdom.VNode { return vdom.P(vdom.Text("Loading...")) },
+		func() *vdom.VNode { return vdom.Span(vdom.Text("Ready")) },
+	)
+
+	html, err := NewRenderer(RendererConfig{}).RenderToString(&vdom.VNode{Kind: vdom.KindComponent, Comp: boundary})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != `<p data-hid="h1">Loading...</p>` {
+		t.Errorf("non-streaming render should use the fallback, got %q", html)
+	}
+}
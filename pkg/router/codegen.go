@@ -156,12 +156,18 @@ func (g *Generator) generateMiddlewareRegistration(buf *bytes.Buffer, route Scan
 	buf.WriteString(fmt.Sprintf("\tapp.Middleware(%q, %s...)\n", route.Path, mwExpr))
 }
 
-// generatePageRegistration generates a single app.Page() call.
+// generatePageRegistration generates a single app.Page() call, followed by
+// app.Meta() when the route file exports a Meta function.
 func (g *Generator) generatePageRegistration(buf *bytes.Buffer, route ScannedRoute) {
 	// Determine handler name
 	handlerName := g.getHandlerName(route)
 
 	buf.WriteString(fmt.Sprintf("\tapp.Page(%q, %s)\n", route.Path, handlerName))
+
+	// Register page metadata alongside the page
+	if route.HasMeta {
+		buf.WriteString(fmt.Sprintf("\tapp.Meta(%q, %sMeta)\n", route.Path, g.getPackagePrefix(route)))
+	}
 }
 
 // generateAPIRegistration generates app.API() calls for API routes.
@@ -123,6 +123,12 @@ func TestGeneratorGenerate(t *testing.T) {
 	if !strings.Contains(code, `app.Page("/about", AboutPage)`) {
 		t.Error("missing /about page registration")
 	}
+	if !strings.Contains(code, `app.Meta("/about", Meta)`) {
+		t.Error("missing /about meta registration")
+	}
+	if strings.Contains(code, `app.Meta("/", `) {
+		t.Error("unexpected meta registration for / (no Meta export)")
+	}
 	if strings.Contains(code, `app.Page("/", IndexPage, Layout`) || strings.Contains(code, `app.Page("/about", AboutPage, Layout`) {
 		t.Error("pages should not be registered with explicit layouts (hierarchical layouts come from app.Layout)")
 	}
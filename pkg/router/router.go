@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/vango-go/vango/pkg/server"
+	"github.com/vango-go/vango/pkg/vdom"
 )
 
 // Router manages route matching and handler dispatch.
@@ -83,6 +84,18 @@ func (r *Router) Page(path string, handler PageHandler, opts ...RouteOption) {
 	}
 }
 
+// Meta registers the metadata handler for a page path.
+// It is called after the page handler during SSR and navigation to
+// populate the document title, meta and link tags.
+//
+// Example:
+//
+//	r.Meta("/projects/:id", projects.Meta)
+func (r *Router) Meta(path string, handler MetaHandler) {
+	node := r.root.insertRoute(path)
+	node.metaHandler = handler
+}
+
 // Layout registers a layout handler for a path.
 // This is the spec-compliant alias for AddLayout.
 func (r *Router) Layout(path string, handler LayoutHandler) {
@@ -264,6 +277,7 @@ func (r *Router) Match(method, path string) (*MatchResult, bool) {
 	// Check for page handler
 	if node.pageHandler != nil {
 		result.PageHandler = node.pageHandler
+		result.MetaHandler = node.metaHandler
 		return result, true
 	}
 
@@ -312,6 +326,14 @@ func (a *RouterAdapter) NotFound() server.PageHandler {
 	}
 }
 
+// RenderErrorPage implements server.ErrorPageRenderer.
+func (a *RouterAdapter) RenderErrorPage(ctx server.Ctx, err error) *vdom.VNode {
+	if a.Router.errorPage == nil {
+		return nil
+	}
+	return a.Router.errorPage(ctx, err)
+}
+
 // ServeHTTP implements http.Handler for the router.
 // This provides basic HTTP routing without WebSocket features.
 func (r *Router) ServeHTTP(ctx server.Ctx) (*MatchResult, bool) {
@@ -322,6 +344,7 @@ func (r *Router) ServeHTTP(ctx server.Ctx) (*MatchResult, bool) {
 // The registry maps file paths to handler functions.
 type HandlerRegistry struct {
 	Pages   map[string]PageHandler
+	Metas   map[string]MetaHandler
 	Layouts map[string]LayoutHandler
 	APIs    map[string]map[string]APIHandler // path -> method -> handler
 	MW      map[string][]Middleware
@@ -342,6 +365,12 @@ func (r *Router) BuildFromScanned(routes []ScannedRoute, registry *HandlerRegist
 			}
 		}
 
+		if route.HasMeta && registry.Metas != nil {
+			if handler, ok := registry.Metas[route.Path]; ok {
+				r.Meta(route.Path, handler)
+			}
+		}
+
 		if len(route.Methods) > 0 && registry.APIs != nil {
 			if handlers, ok := registry.APIs[route.Path]; ok {
 				for method, handler := range handlers {
@@ -47,6 +47,39 @@ func TestRouterMatchParams(t *testing.T) {
 	}
 }
 
+func TestRouterMatchMeta(t *testing.T) {
+	r := NewRouter()
+
+	r.AddPage("/projects/:id", func(ctx server.Ctx, params any) vdom.Component {
+		return nil
+	})
+	r.AddPage("/about", func(ctx server.Ctx, params any) vdom.Component {
+		return nil
+	})
+	r.Meta("/projects/:id", func(ctx server.Ctx, params any) PageMeta {
+		return PageMeta{Title: "Project " + params.(map[string]string)["id"]}
+	})
+
+	result, ok := r.Match("GET", "/projects/7")
+	if !ok {
+		t.Fatal("expected match for /projects/7")
+	}
+	if result.GetMetaHandler() == nil {
+		t.Fatal("expected MetaHandler")
+	}
+	if got := result.MetaHandler(nil, result.Params).Title; got != "Project 7" {
+		t.Errorf("Title = %q, want %q", got, "Project 7")
+	}
+
+	result, ok = r.Match("GET", "/about")
+	if !ok {
+		t.Fatal("expected match for /about")
+	}
+	if result.MetaHandler != nil {
+		t.Error("expected no MetaHandler for /about")
+	}
+}
+
 func TestRouterMatchCatchAll(t *testing.T) {
 	r := NewRouter()
 
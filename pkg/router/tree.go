@@ -25,6 +25,7 @@ type RouteNode struct {
 
 	// handlers
 	pageHandler   PageHandler
+	metaHandler   MetaHandler
 	layoutHandler LayoutHandler
 	apiHandlers   map[string]APIHandler // method -> handler
 	middleware    []Middleware
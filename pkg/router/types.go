@@ -23,16 +23,13 @@ type APIHandler func(ctx server.Ctx, params any, body any) (any, error)
 type ErrorHandler func(ctx server.Ctx, err error) *vdom.VNode
 
 // PageMeta contains page metadata for SEO.
-type PageMeta struct {
-	Title       string
-	Description string
-	Keywords    []string
-	OGImage     string
-	OGTitle     string
-	OGDesc      string
-	Canonical   string
-	Robots      string
-}
+// It is rendered into <head> during SSR and applied with head patches
+// during client-side navigation.
+type PageMeta = server.PageMeta
+
+// MetaHandler returns the metadata for a page.
+// The params parameter is the route parameter map.
+type MetaHandler = server.MetaHandler
 
 // ScannedRoute represents a route discovered by the scanner.
 type ScannedRoute struct {
@@ -121,6 +118,9 @@ type MatchResult struct {
 	// Params are the extracted route parameters
 	Params map[string]string
 
+	// MetaHandler returns the page metadata, if the route exports Meta
+	MetaHandler MetaHandler
+
 	// Route is the matched route definition
 	Route *ScannedRoute
 }
@@ -142,6 +142,11 @@ func (m *MatchResult) GetPageHandler() server.PageHandler {
 	}
 }
 
+// GetMetaHandler implements server.RouteMetaMatch.
+func (m *MatchResult) GetMetaHandler() server.MetaHandler {
+	return m.MetaHandler
+}
+
 // GetLayoutHandlers implements server.RouteMatch.
 func (m *MatchResult) GetLayoutHandlers() []server.LayoutHandler {
 	layouts := m.Layouts
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/upload"
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// maxBlobUploads is the number of files a session may stream at once.
+const maxBlobUploads = 4
+
+// errBlobOffset is returned when a blob chunk does not continue where the
+// previous one ended.
+var errBlobOffset = errors.New("server: blob chunk out of order")
+
+// blobUpload is one file streaming in over FrameBlob frames.
+type blobUpload struct {
+	id       uint64
+	hid      string
+	filename string
+	size     int64
+	stream   *upload.Stream
+	onRecv   uint64 // Next expected chunk offset; read loop only
+
+	// progressQueued coalesces progress reports: at most one is waiting on
+	// the session loop at a time, and it reports the latest count.
+	progressQueued atomic.Bool
+}
+
+func (u *blobUpload) progress() upload.Progress {
+	return upload.Progress{
+		Filename:    u.filename,
+		ContentType: u.stream.ContentType(),
+		Size:        u.size,
+		Received:    u.stream.Received(),
+	}
+}
+
+// uploadTarget returns the upload.Live attached to the element with the
+// given HID.
+func (s *Session) uploadTarget(hid string) (upload.Live, bool) {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	live, ok := s.uploads[hid]
+	return live, ok
+}
+
+// handleBlobFrame handles a blob upload frame. It runs on the read loop and
+// never blocks on the store: chunks are buffered in the upload's stream,
+// whose size is bounded by the client's send window.
+func (s *Session) handleBlobFrame(payload []byte) {
+	b, err := protocol.DecodeBlob(payload)
+	if err != nil {
+		s.logger.Error("blob decode error", "error", err)
+		return
+	}
+
+	switch b.Op {
+	case protocol.BlobStart:
+		s.startBlob(b)
+
+	case protocol.BlobChunk:
+		u := s.getBlob(b.ID)
+		if u == nil {
+			// Already failed; the client has been told.
+			return
+		}
+		if b.Offset != u.onRecv {
+			u.stream.Abort(errBlobOffset)
+			return
+		}
+		if _, err := u.stream.Write(b.Data); err != nil {
+			u.stream.Abort(err)
+			return
+		}
+		u.onRecv += uint64(len(b.Data))
+
+	case protocol.BlobEnd:
+		if u := s.getBlob(b.ID); u != nil {
+			u.stream.Close()
+		}
+
+	case protocol.BlobCancel:
+		if u := s.getBlob(b.ID); u != nil {
+			u.stream.Abort(upload.ErrCanceled)
+		}
+	}
+}
+
+// startBlob opens an upload for the element's upload.Live.
+func (s *Session) startBlob(b *protocol.Blob) {
+	fail := func(err error) {
+		s.sendBlobAck(b.ID, 0, protocol.BlobStatusError, err.Error())
+	}
+
+	live, ok := s.uploadTarget(b.HID)
+	if !ok || live.Store == nil {
+		s.logger.Warn("blob upload without handler", "hid", b.HID)
+		fail(errors.New("no upload handler"))
+		return
+	}
+
+	s.blobMu.Lock()
+	if s.blobs == nil {
+		s.blobs = make(map[uint64]*blobUpload)
+	}
+	if _, dup := s.blobs[b.ID]; dup || len(s.blobs) >= maxBlobUploads {
+		s.blobMu.Unlock()
+		fail(errors.New("too many uploads"))
+		return
+	}
+
+	u := &blobUpload{
+		id:       b.ID,
+		hid:      b.HID,
+		filename: b.Filename,
+		size:     int64(b.Size),
+	}
+	stream, err := upload.NewStream(live.Store, live.Config, b.Filename, int64(b.Size), upload.StreamOptions{
+		Window: protocol.BlobWindow,
+		OnRead: func(received int64) {
+			s.sendBlobAck(u.id, uint64(received), protocol.BlobStatusProgress, "")
+			if u.progressQueued.CompareAndSwap(false, true) {
+				s.Dispatch(func() {
+					u.progressQueued.Store(false)
+					s.deliverUploadProgress(u.hid, u.progress())
+				})
+			}
+		},
+	})
+	if err != nil {
+		s.blobMu.Unlock()
+		fail(err)
+		s.Dispatch(func() {
+			s.deliverUploadProgress(b.HID, upload.Progress{
+				Filename: b.Filename,
+				Size:     int64(b.Size),
+				Done:     true,
+				Err:      err,
+			})
+		})
+		return
+	}
+	u.stream = stream
+	s.blobs[b.ID] = u
+	s.blobMu.Unlock()
+
+	go s.finishBlob(u)
+}
+
+// finishBlob waits for the store and reports the result.
+func (s *Session) finishBlob(u *blobUpload) {
+	tempID, _, err := u.stream.Wait()
+
+	s.blobMu.Lock()
+	delete(s.blobs, u.id)
+	s.blobMu.Unlock()
+
+	p := u.progress()
+	p.Done = true
+	p.TempID = tempID
+	p.Err = err
+	if err != nil {
+		s.sendBlobAck(u.id, uint64(p.Received), protocol.BlobStatusError, err.Error())
+	} else {
+		s.sendBlobAck(u.id, uint64(p.Received), protocol.BlobStatusDone, "")
+	}
+	s.Dispatch(func() {
+		s.deliverUploadProgress(u.hid, p)
+	})
+}
+
+func (s *Session) getBlob(id uint64) *blobUpload {
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+	return s.blobs[id]
+}
+
+// abortBlobs cancels every upload in flight. Uploads do not survive a
+// reconnect.
+func (s *Session) abortBlobs() {
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+	for _, u := range s.blobs {
+		u.stream.Abort(upload.ErrCanceled)
+	}
+}
+
+// deliverUploadProgress calls the OnProgress handler of the element's
+// upload.Live. Must be called on the session loop.
+func (s *Session) deliverUploadProgress(hid string, p upload.Progress) {
+	s.stateMu.RLock()
+	handler, ok := s.handlers[hid+"_onupload"]
+	owner := s.owner
+	if instance := s.components[hid]; instance != nil && instance.Owner != nil {
+		owner = instance.Owner
+	}
+	s.stateMu.RUnlock()
+	if !ok {
+		// The input unmounted while the file was in flight.
+		s.logger.Debug("upload progress dropped: no handler", "hid", hid)
+		return
+	}
+
+	vango.WithOwner(owner, func() {
+		s.safeExecute(handler, &Event{HID: hid, Payload: p, Session: s, Time: time.Now()})
+	})
+}
+
+// sendBlobAck reports upload progress to the client. Safe to call from any
+// goroutine.
+func (s *Session) sendBlobAck(id, received uint64, status protocol.BlobStatus, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed.Load() || s.conn == nil {
+		return
+	}
+
+	ct, ack := protocol.NewBlobAck(id, received, status, message)
+	if !s.versionLocked().SupportsControl(ct) {
+		return
+	}
+	frame := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, ack))
+
+	if err := s.writeMessageLocked(frame.EncodeMessage()); err != nil {
+		s.logger.Error("blob ack send error", "error", err)
+	}
+}
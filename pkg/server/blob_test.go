@@ -0,0 +1,230 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/upload"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// newUploadSession starts a session whose root is a file input streaming
+// into store, and returns the client end, the input's HID and the progress
+// reports.
+func newUploadSession(t *testing.T, live upload.Live) (*websocket.Conn, string, chan upload.Progress) {
+	t.Helper()
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+
+	progress := make(chan upload.Progress, 64)
+	live.OnProgress = func(p upload.Progress) { progress <- p }
+	sess.MountRoot(FuncComponent(func() *vdom.VNode {
+		return vdom.Input(vdom.Type("file"), vdom.OnUpload(live))
+	}))
+
+	var hid string
+	for key := range sess.handlers {
+		if strings.HasSuffix(key, "_onupload") {
+			hid = strings.TrimSuffix(key, "_onupload")
+		}
+	}
+	if hid == "" {
+		t.Fatal("onupload handler not registered")
+	}
+
+	// Wait for the loops to exit so they do not outlive the test.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); sess.ReadLoop() }()
+	go func() { defer wg.Done(); sess.EventLoop() }()
+	t.Cleanup(func() {
+		sess.Close()
+		wg.Wait()
+	})
+	return clientConn, hid, progress
+}
+
+func writeBlob(t *testing.T, conn *websocket.Conn, b *protocol.Blob) {
+	t.Helper()
+	frame := protocol.NewFrame(protocol.FrameBlob, protocol.EncodeBlob(b))
+	_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+	if err := conn.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+}
+
+// readBlobAck reads control frames until a BlobAck arrives.
+func readBlobAck(t *testing.T, conn *websocket.Conn) *protocol.BlobAck {
+	t.Helper()
+	for {
+		frame, _ := readMessage(t, conn, protocol.NewReassembler(0))
+		if frame.Type != protocol.FrameControl {
+			continue
+		}
+		ct, data, err := protocol.DecodeControl(frame.Payload)
+		if err != nil {
+			t.Fatalf("DecodeControl failed: %v", err)
+		}
+		if ct == protocol.ControlBlobAck {
+			return data.(*protocol.BlobAck)
+		}
+	}
+}
+
+func TestSession_BlobUploadToStore(t *testing.T) {
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+	conn, hid, progress := newUploadSession(t, upload.Live{Store: store})
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), protocol.BlobWindow/8)
+	writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobStart, ID: 1, HID: hid, Filename: "notes.txt", Size: uint64(len(content))})
+
+	// Send within the window, waiting for acks as the client does.
+	var sent, acked uint64
+	var final *protocol.BlobAck
+	for final == nil {
+		for sent < uint64(len(content)) && sent-acked+protocol.BlobChunkSize <= protocol.BlobWindow {
+			end := min(sent+protocol.BlobChunkSize, uint64(len(content)))
+			writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobChunk, ID: 1, Offset: sent, Data: content[sent:end]})
+			sent = end
+			if sent == uint64(len(content)) {
+				writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobEnd, ID: 1})
+			}
+		}
+		ack := readBlobAck(t, conn)
+		switch ack.Status {
+		case protocol.BlobStatusProgress:
+			acked = ack.Received
+		case protocol.BlobStatusDone:
+			final = ack
+		default:
+			t.Fatalf("upload failed: %s", ack.Message)
+		}
+	}
+	if final.Received != uint64(len(content)) {
+		t.Errorf("final ack received = %d, want %d", final.Received, len(content))
+	}
+
+	var done upload.Progress
+	deadline := time.After(2 * time.Second)
+	for !done.Done {
+		select {
+		case done = <-progress:
+		case <-deadline:
+			t.Fatal("no final progress report")
+		}
+	}
+	if done.Err != nil || done.TempID == "" || done.ContentType != "text/plain; charset=utf-8" {
+		t.Fatalf("final progress = %+v", done)
+	}
+
+	file, err := store.Claim(done.TempID)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	defer file.Close()
+	data, _ := io.ReadAll(file.Reader)
+	if !bytes.Equal(data, content) {
+		t.Error("stored file does not match upload")
+	}
+}
+
+func TestSession_BlobUploadRejectedByConfig(t *testing.T) {
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+	conn, hid, progress := newUploadSession(t, upload.Live{
+		Store:  store,
+		Config: &upload.Config{MaxFileSize: 100},
+	})
+
+	writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobStart, ID: 9, HID: hid, Filename: "big.bin", Size: 101})
+	ack := readBlobAck(t, conn)
+	if ack.ID != 9 || ack.Status != protocol.BlobStatusError {
+		t.Fatalf("ack = %+v, want error for upload 9", ack)
+	}
+
+	select {
+	case p := <-progress:
+		if !p.Done || p.Err != upload.ErrTooLarge {
+			t.Errorf("progress = %+v, want Done with ErrTooLarge", p)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no progress report for rejected upload")
+	}
+}
+
+func TestSession_BlobUploadUnknownTarget(t *testing.T) {
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+	conn, _, _ := newUploadSession(t, upload.Live{Store: store})
+
+	writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobStart, ID: 1, HID: "h999", Filename: "a.txt", Size: 1})
+	if ack := readBlobAck(t, conn); ack.Status != protocol.BlobStatusError {
+		t.Fatalf("ack = %+v, want error", ack)
+	}
+}
+
+func TestSession_BlobUploadAbortedOnDisconnect(t *testing.T) {
+	store := &blockingUploadStore{started: make(chan struct{}), result: make(chan error, 1)}
+	conn, hid, _ := newUploadSession(t, upload.Live{Store: store})
+
+	writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobStart, ID: 1, HID: hid, Filename: "a.txt", Size: 4096})
+	writeBlob(t, conn, &protocol.Blob{Op: protocol.BlobChunk, ID: 1, Data: make([]byte, 1024)})
+	<-store.started
+	conn.Close()
+
+	select {
+	case err := <-store.result:
+		if err != upload.ErrCanceled {
+			t.Errorf("store read error = %v, want ErrCanceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("upload not aborted after disconnect")
+	}
+}
+
+// blockingUploadStore reads the file and reports the read error.
+type blockingUploadStore struct {
+	started chan struct{}
+	result  chan error
+}
+
+func (b *blockingUploadStore) Save(filename, contentType string, size int64, r io.Reader) (string, error) {
+	close(b.started)
+	_, err := io.Copy(io.Discard, r)
+	b.result <- err
+	return "", err
+}
+
+func (b *blockingUploadStore) Claim(string) (*upload.File, error) { return nil, upload.ErrNotFound }
+
+func (b *blockingUploadStore) Cleanup(time.Duration) error { return nil }
+
+func TestSession_UploadTargetTracksMountedInputs(t *testing.T) {
+	sess := newSession(nil, "", DefaultSessionConfig(), slog.Default())
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+	config := upload.DefaultConfig()
+	sess.MountRoot(FuncComponent(func() *vdom.VNode {
+		return vdom.Div(
+			vdom.Button(vdom.OnClick(func() {})),
+			vdom.Input(vdom.Type("file"), vdom.OnUpload(upload.Live{Store: store, Config: config})),
+		)
+	}))
+
+	input := sess.currentTree.Children[1].HID
+	if live, ok := sess.uploadTarget(input); !ok || live.Store != store || live.Config != config {
+		t.Fatalf("uploadTarget(%s) = %+v, %v", input, live, ok)
+	}
+	if _, ok := sess.uploadTarget(sess.currentTree.Children[0].HID); ok {
+		t.Error("button without OnUpload is an upload target")
+	}
+
+	sess.clearComponentHandlers(sess.root)
+	if _, ok := sess.uploadTarget(input); ok {
+		t.Error("upload target kept after its component was cleared")
+	}
+}
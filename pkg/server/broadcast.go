@@ -0,0 +1,370 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vango/pkg/auth"
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// =============================================================================
+// Broadcast Transport
+// =============================================================================
+
+// BroadcastTransport carries broadcast messages between server instances.
+//
+// A transport must deliver every payload published on a topic to every
+// subscriber of that topic on every instance, including the publishing one.
+// Unlike a GlobalSignalBackend, a transport must NOT replay earlier payloads
+// to new subscribers: broadcasts are events, not state.
+//
+// The default transport is in-process (see NewLocalBroadcastTransport).
+// For multi-replica deployments use session.NewRedisBroadcastTransport or an
+// adapter around a NATS-like bus.
+type BroadcastTransport interface {
+	// Publish sends data to all subscribers of topic.
+	Publish(ctx context.Context, topic string, data []byte) error
+
+	// Subscribe registers fn to receive payloads published on topic.
+	// The returned function removes the subscription.
+	Subscribe(topic string, fn func(data []byte)) (unsubscribe func(), err error)
+
+	// Close releases transport resources.
+	Close() error
+}
+
+// ErrBroadcastTransportClosed is returned after a local transport is closed.
+var ErrBroadcastTransportClosed = errors.New("server: broadcast transport closed")
+
+// LocalBroadcastTransport is the default in-process BroadcastTransport.
+type LocalBroadcastTransport struct {
+	mu     sync.RWMutex
+	subs   map[string]map[uint64]func([]byte)
+	nextID uint64
+	closed bool
+}
+
+// NewLocalBroadcastTransport creates an in-process BroadcastTransport.
+func NewLocalBroadcastTransport() *LocalBroadcastTransport {
+	return &LocalBroadcastTransport{
+		subs: make(map[string]map[uint64]func([]byte)),
+	}
+}
+
+// Publish delivers data synchronously to every subscriber of topic.
+func (t *LocalBroadcastTransport) Publish(ctx context.Context, topic string, data []byte) error {
+	t.mu.RLock()
+	if t.closed {
+		t.mu.RUnlock()
+		return ErrBroadcastTransportClosed
+	}
+	fns := make([]func([]byte), 0, len(t.subs[topic]))
+	for _, fn := range t.subs[topic] {
+		fns = append(fns, fn)
+	}
+	t.mu.RUnlock()
+
+	for _, fn := range fns {
+		fn(data)
+	}
+	return nil
+}
+
+// Subscribe registers fn for topic.
+func (t *LocalBroadcastTransport) Subscribe(topic string, fn func(data []byte)) (func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrBroadcastTransportClosed
+	}
+
+	t.nextID++
+	id := t.nextID
+	if t.subs[topic] == nil {
+		t.subs[topic] = make(map[uint64]func([]byte))
+	}
+	t.subs[topic][id] = fn
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.subs[topic], id)
+		if len(t.subs[topic]) == 0 {
+			delete(t.subs, topic)
+		}
+	}, nil
+}
+
+// Close drops all subscriptions.
+func (t *LocalBroadcastTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	t.subs = make(map[string]map[uint64]func([]byte))
+	return nil
+}
+
+// =============================================================================
+// Broadcaster
+// =============================================================================
+
+// broadcastPublishTimeout bounds a single transport Publish call.
+const broadcastPublishTimeout = 5 * time.Second
+
+// broadcastEnvelope is the wire format for broadcast messages.
+// UserID and TenantID, when set, restrict delivery to matching sessions.
+type broadcastEnvelope struct {
+	UserID   string          `json:"u,omitempty"`
+	TenantID string          `json:"n,omitempty"`
+	Payload  json.RawMessage `json:"p"`
+}
+
+// broadcastSub is one session-side topic subscription.
+type broadcastSub struct {
+	session *Session
+	fn      func(json.RawMessage)
+}
+
+// broadcastTopic fans one transport subscription out to local sessions.
+type broadcastTopic struct {
+	unsubscribe func()
+	subs        map[uint64]*broadcastSub
+}
+
+// Broadcaster publishes messages to sessions subscribed to a topic, across
+// all server instances that share the same BroadcastTransport.
+//
+// Sessions subscribe from inside an Effect using Topic:
+//
+//	vango.CreateEffect(func() vango.Cleanup {
+//	    return vango.Subscribe(server.Topic[Notice](ctx, "notices"), func(n Notice) {
+//	        notices.Append(n)
+//	    })
+//	})
+//
+// Messages are delivered on each session's event loop, so handlers may write
+// signals and the affected components re-render as usual.
+type Broadcaster struct {
+	transport BroadcastTransport
+	logger    *slog.Logger
+
+	mu     sync.Mutex
+	topics map[string]*broadcastTopic
+	nextID uint64
+}
+
+// NewBroadcaster creates a Broadcaster on top of transport.
+// If transport is nil, an in-process LocalBroadcastTransport is used.
+func NewBroadcaster(transport BroadcastTransport, logger *slog.Logger) *Broadcaster {
+	if transport == nil {
+		transport = NewLocalBroadcastTransport()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Broadcaster{
+		transport: transport,
+		logger:    logger.With("component", "broadcaster"),
+		topics:    make(map[string]*broadcastTopic),
+	}
+}
+
+// Broadcast sends payload to every session subscribed to topic.
+// The payload is JSON-encoded; subscribers decode it into their Topic type.
+func (b *Broadcaster) Broadcast(topic string, payload any) error {
+	return b.publish(topic, broadcastEnvelope{}, payload)
+}
+
+// BroadcastToUser sends payload to the sessions of userID that are subscribed
+// to topic. A session matches if its UserID or auth principal ID equals userID.
+func (b *Broadcaster) BroadcastToUser(userID, topic string, payload any) error {
+	if userID == "" {
+		return errors.New("server: BroadcastToUser requires a user ID")
+	}
+	return b.publish(topic, broadcastEnvelope{UserID: userID}, payload)
+}
+
+// BroadcastToTenant sends payload to the sessions whose auth principal
+// belongs to tenantID and that are subscribed to topic.
+func (b *Broadcaster) BroadcastToTenant(tenantID, topic string, payload any) error {
+	if tenantID == "" {
+		return errors.New("server: BroadcastToTenant requires a tenant ID")
+	}
+	return b.publish(topic, broadcastEnvelope{TenantID: tenantID}, payload)
+}
+
+// publish encodes payload into env and hands it to the transport.
+func (b *Broadcaster) publish(topic string, env broadcastEnvelope, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	env.Payload = raw
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), broadcastPublishTimeout)
+	defer cancel()
+	return b.transport.Publish(ctx, topic, data)
+}
+
+// subscribe registers fn for messages on topic that target sess.
+// The first local subscriber for a topic opens a transport subscription.
+func (b *Broadcaster) subscribe(sess *Session, topic string, fn func(json.RawMessage)) func() {
+	b.mu.Lock()
+	t, ok := b.topics[topic]
+	if !ok {
+		unsubscribe, err := b.transport.Subscribe(topic, func(data []byte) {
+			b.deliver(topic, data)
+		})
+		if err != nil {
+			b.mu.Unlock()
+			b.logger.Warn("broadcast subscribe failed", "topic", topic, "error", err)
+			return func() {}
+		}
+		t = &broadcastTopic{
+			unsubscribe: unsubscribe,
+			subs:        make(map[uint64]*broadcastSub),
+		}
+		b.topics[topic] = t
+	}
+	b.nextID++
+	id := b.nextID
+	t.subs[id] = &broadcastSub{session: sess, fn: fn}
+	b.mu.Unlock()
+
+	return func() { b.unsubscribe(topic, id) }
+}
+
+// unsubscribe removes one subscription, closing the transport subscription
+// when the last local subscriber for topic is gone.
+func (b *Broadcaster) unsubscribe(topic string, id uint64) {
+	b.mu.Lock()
+	t, ok := b.topics[topic]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+	delete(t.subs, id)
+	var unsubscribe func()
+	if len(t.subs) == 0 {
+		delete(b.topics, topic)
+		unsubscribe = t.unsubscribe
+	}
+	b.mu.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+}
+
+// deliver routes a transport payload to the matching local subscribers.
+func (b *Broadcaster) deliver(topic string, data []byte) {
+	var env broadcastEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		b.logger.Warn("invalid broadcast payload", "topic", topic, "error", err)
+		return
+	}
+
+	b.mu.Lock()
+	t, ok := b.topics[topic]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+	subs := make([]*broadcastSub, 0, len(t.subs))
+	for _, sub := range t.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		if env.matches(sub.session) {
+			sub.fn(env.Payload)
+		}
+	}
+}
+
+// Close removes all transport subscriptions. The transport itself is not
+// closed, as it may be shared with other components.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	topics := b.topics
+	b.topics = make(map[string]*broadcastTopic)
+	b.mu.Unlock()
+
+	for _, t := range topics {
+		t.unsubscribe()
+	}
+}
+
+// matches reports whether a message with this envelope targets sess.
+func (env *broadcastEnvelope) matches(sess *Session) bool {
+	if env.UserID == "" && env.TenantID == "" {
+		return true
+	}
+	if sess == nil {
+		return false
+	}
+
+	principal, hasPrincipal := sess.Get(auth.SessionKeyPrincipal).(auth.Principal)
+	if env.UserID != "" {
+		if sess.UserID != env.UserID && (!hasPrincipal || principal.ID != env.UserID) {
+			return false
+		}
+	}
+	if env.TenantID != "" {
+		if !hasPrincipal || principal.TenantID != env.TenantID {
+			return false
+		}
+	}
+	return true
+}
+
+// =============================================================================
+// Topic Stream
+// =============================================================================
+
+// topicStream adapts a broadcast topic to vango.Stream[T].
+type topicStream[T any] struct {
+	session *Session
+	topic   string
+}
+
+// Topic returns a stream of messages broadcast on topic to the current session.
+// Use it with vango.Subscribe inside an Effect; handlers run on the session loop.
+// Payloads that cannot be decoded into T are logged and dropped.
+//
+// Outside a live session (for example during SSR) the stream never emits.
+func Topic[T any](ctx Ctx, topic string) vango.Stream[T] {
+	var sess *Session
+	if ctx != nil {
+		sess = ctx.Session()
+	}
+	return &topicStream[T]{session: sess, topic: topic}
+}
+
+// Subscribe implements vango.Stream.
+func (s *topicStream[T]) Subscribe(handler func(T)) func() {
+	if s.session == nil || s.session.broadcaster == nil {
+		return func() {}
+	}
+
+	sess := s.session
+	return sess.broadcaster.subscribe(sess, s.topic, func(raw json.RawMessage) {
+		var msg T
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			sess.logger.Warn("broadcast decode failed", "topic", s.topic, "error", err)
+			return
+		}
+		handler(msg)
+	})
+}
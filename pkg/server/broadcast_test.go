@@ -0,0 +1,157 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/auth"
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+type broadcastNotice struct {
+	Text string `json:"text"`
+}
+
+func newBroadcastSession(b *Broadcaster, userID string) *Session {
+	s := NewMockSession()
+	s.UserID = userID
+	s.broadcaster = b
+	return s
+}
+
+// drainDispatch runs queued Dispatch callbacks for a mock session.
+func drainDispatch(s *Session) {
+	for {
+		select {
+		case fn := <-s.dispatchCh:
+			fn()
+		default:
+			return
+		}
+	}
+}
+
+func TestBroadcaster_TopicDeliversOnSessionLoop(t *testing.T) {
+	b := NewBroadcaster(nil, nil)
+	sess := newBroadcastSession(b, "u1")
+	ctx := NewTestContext(sess)
+
+	var got []string
+	var cleanup vango.Cleanup
+	vango.WithCtx(ctx, func() {
+		cleanup = vango.Subscribe(Topic[broadcastNotice](ctx, "notices"), func(n broadcastNotice) {
+			got = append(got, n.Text)
+		})
+	})
+
+	if err := b.Broadcast("notices", broadcastNotice{Text: "hello"}); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatal("handler ran before the session loop processed the dispatch")
+	}
+	drainDispatch(sess)
+	if len(got) != 1 || got[0] != "hello" {
+		t.Fatalf("got %v, want [hello]", got)
+	}
+
+	cleanup()
+	_ = b.Broadcast("notices", broadcastNotice{Text: "after"})
+	drainDispatch(sess)
+	if len(got) != 1 {
+		t.Fatalf("handler ran after unsubscribe: %v", got)
+	}
+	if len(b.topics) != 0 {
+		t.Fatalf("topics not released: %d", len(b.topics))
+	}
+}
+
+func TestBroadcaster_TargetsUserAndTenant(t *testing.T) {
+	b := NewBroadcaster(NewLocalBroadcastTransport(), nil)
+
+	alice := newBroadcastSession(b, "alice")
+	bob := newBroadcastSession(b, "")
+	auth.SetPrincipal(bob, auth.Principal{ID: "bob", TenantID: "acme"})
+	carol := newBroadcastSession(b, "")
+	auth.SetPrincipal(carol, auth.Principal{ID: "carol", TenantID: "globex"})
+
+	received := map[string][]string{}
+	for name, s := range map[string]*Session{"alice": alice, "bob": bob, "carol": carol} {
+		name := name
+		Topic[string](NewTestContext(s), "inbox").Subscribe(func(msg string) {
+			received[name] = append(received[name], msg)
+		})
+	}
+
+	if err := b.BroadcastToUser("alice", "inbox", "for-alice"); err != nil {
+		t.Fatalf("BroadcastToUser() error: %v", err)
+	}
+	if err := b.BroadcastToUser("bob", "inbox", "for-bob"); err != nil {
+		t.Fatalf("BroadcastToUser() error: %v", err)
+	}
+	if err := b.BroadcastToTenant("acme", "inbox", "for-acme"); err != nil {
+		t.Fatalf("BroadcastToTenant() error: %v", err)
+	}
+	if err := b.Broadcast("inbox", "for-all"); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+
+	want := map[string][]string{
+		"alice": {"for-alice", "for-all"},
+		"bob":   {"for-bob", "for-acme", "for-all"},
+		"carol": {"for-all"},
+	}
+	for name, msgs := range want {
+		if len(received[name]) != len(msgs) {
+			t.Fatalf("%s received %v, want %v", name, received[name], msgs)
+		}
+		for i := range msgs {
+			if received[name][i] != msgs[i] {
+				t.Fatalf("%s received %v, want %v", name, received[name], msgs)
+			}
+		}
+	}
+
+	if err := b.BroadcastToUser("", "inbox", "x"); err == nil {
+		t.Fatal("BroadcastToUser with empty user should fail")
+	}
+	if err := b.BroadcastToTenant("", "inbox", "x"); err == nil {
+		t.Fatal("BroadcastToTenant with empty tenant should fail")
+	}
+}
+
+func TestBroadcaster_SharedTransportFansOutAcrossInstances(t *testing.T) {
+	transport := NewLocalBroadcastTransport()
+	nodeA := NewBroadcaster(transport, nil)
+	nodeB := NewBroadcaster(transport, nil)
+
+	got := make(chan string, 1)
+	Topic[string](NewTestContext(newBroadcastSession(nodeB, "u")), "deploys").Subscribe(func(msg string) {
+		got <- msg
+	})
+
+	if err := nodeA.Broadcast("deploys", "v2 rolled out"); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+	select {
+	case msg := <-got:
+		if msg != "v2 rolled out" {
+			t.Fatalf("got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered to the other instance")
+	}
+
+	nodeB.Close()
+	_ = transport.Close()
+	if err := nodeA.Broadcast("deploys", "x"); err != ErrBroadcastTransportClosed {
+		t.Fatalf("Broadcast after Close = %v, want ErrBroadcastTransportClosed", err)
+	}
+}
+
+func TestTopic_WithoutSessionIsNoop(t *testing.T) {
+	unsubscribe := Topic[string](nil, "x").Subscribe(func(string) {
+		t.Fatal("handler should never run")
+	})
+	unsubscribe()
+}
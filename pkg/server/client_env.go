@@ -0,0 +1,262 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// ColorScheme is the user's prefers-color-scheme setting.
+type ColorScheme string
+
+const (
+	ColorSchemeNone  ColorScheme = ""      // No preference reported
+	ColorSchemeLight ColorScheme = "light" // prefers-color-scheme: light
+	ColorSchemeDark  ColorScheme = "dark"  // prefers-color-scheme: dark
+)
+
+// Viewport is the size of the browser viewport in CSS pixels.
+type Viewport struct {
+	Width  int
+	Height int
+}
+
+// Default viewports used when the client has not reported its size.
+var (
+	DefaultViewport       = Viewport{Width: 1280, Height: 800}
+	DefaultMobileViewport = Viewport{Width: 390, Height: 844}
+)
+
+// ClientEnv is a snapshot of the browser environment of a session.
+type ClientEnv struct {
+	Viewport      Viewport
+	TZOffset      int    // Minutes east of UTC
+	Locale        string // BCP 47 tag, e.g. "en-US"; empty if unknown
+	ColorScheme   ColorScheme
+	ReducedMotion bool
+}
+
+// Location returns the client's timezone as a fixed-offset location.
+// Browsers only report the current offset, so the location does not follow
+// daylight saving changes.
+func (e ClientEnv) Location() *time.Location {
+	return fixedZone(e.TZOffset)
+}
+
+// ClientEnvFromRequest derives a ClientEnv from request headers. It is used
+// for SSR, before the client has reported its environment, and as the
+// fallback for values a client does not report.
+//
+// The locale comes from Accept-Language. Viewport and preferences come from
+// the Sec-CH-Viewport-Width, Sec-CH-Viewport-Height,
+// Sec-CH-Prefers-Color-Scheme and Sec-CH-Prefers-Reduced-Motion client
+// hints, which browsers only send to servers that ask for them with an
+// Accept-CH response header. Without them the viewport is
+// DefaultMobileViewport if Sec-CH-UA-Mobile is "?1", otherwise
+// DefaultViewport. The timezone defaults to UTC.
+func ClientEnvFromRequest(r *http.Request) ClientEnv {
+	env := ClientEnv{Viewport: DefaultViewport}
+	if r == nil {
+		return env
+	}
+	h := r.Header
+
+	if h.Get("Sec-CH-UA-Mobile") == "?1" {
+		env.Viewport = DefaultMobileViewport
+	}
+	if w := headerInt(h, "Sec-CH-Viewport-Width", "Viewport-Width"); w > 0 {
+		env.Viewport.Width = w
+	}
+	if hgt := headerInt(h, "Sec-CH-Viewport-Height"); hgt > 0 {
+		env.Viewport.Height = hgt
+	}
+
+	env.Locale = primaryLanguage(h.Get("Accept-Language"))
+
+	switch strings.Trim(h.Get("Sec-CH-Prefers-Color-Scheme"), `"`) {
+	case "dark":
+		env.ColorScheme = ColorSchemeDark
+	case "light":
+		env.ColorScheme = ColorSchemeLight
+	}
+	env.ReducedMotion = strings.Trim(h.Get("Sec-CH-Prefers-Reduced-Motion"), `"`) == "reduce"
+
+	return env
+}
+
+// withHello returns env updated with the values reported in a ClientHello.
+// Locale and preferences are only taken from clients that report them.
+func (e ClientEnv) withHello(hello *protocol.ClientHello) ClientEnv {
+	if hello.ViewportW > 0 && hello.ViewportH > 0 {
+		e.Viewport = Viewport{Width: int(hello.ViewportW), Height: int(hello.ViewportH)}
+	}
+	e.TZOffset = int(hello.TZOffset)
+	if hello.Locale != "" || hello.Prefs != 0 {
+		e = e.withReported(hello.Locale, hello.Prefs)
+	}
+	return e
+}
+
+// withEnv returns env updated from a ControlClientEnv message.
+func (e ClientEnv) withEnv(ce *protocol.ClientEnv) ClientEnv {
+	if ce.ViewportW > 0 && ce.ViewportH > 0 {
+		e.Viewport = Viewport{Width: int(ce.ViewportW), Height: int(ce.ViewportH)}
+	}
+	e.TZOffset = int(ce.TZOffset)
+	return e.withReported(ce.Locale, ce.Prefs)
+}
+
+func (e ClientEnv) withReported(locale string, prefs uint8) ClientEnv {
+	if locale != "" {
+		e.Locale = locale
+	}
+	switch {
+	case prefs&protocol.ClientPrefDark != 0:
+		e.ColorScheme = ColorSchemeDark
+	case prefs&protocol.ClientPrefLight != 0:
+		e.ColorScheme = ColorSchemeLight
+	default:
+		e.ColorScheme = ColorSchemeNone
+	}
+	e.ReducedMotion = prefs&protocol.ClientPrefReducedMotion != 0
+	return e
+}
+
+// Client is the live browser environment of a session, returned by
+// Ctx.Client. Each value is backed by a signal: reading it during render
+// subscribes the component, which re-renders when the client reports a
+// resize, a timezone or locale change, or a preference change. Reading one
+// value does not subscribe to the others.
+//
+// During SSR the values come from ClientEnvFromRequest and never change.
+type Client struct {
+	viewport      *vango.Signal[Viewport]
+	tzOffset      *vango.Signal[int]
+	locale        *vango.Signal[string]
+	colorScheme   *vango.Signal[ColorScheme]
+	reducedMotion *vango.Signal[bool]
+}
+
+// NewClient creates a Client holding env.
+func NewClient(env ClientEnv) *Client {
+	c := &Client{}
+	// The signals belong to the session, not to whichever component first
+	// asks for them, so create them outside any owner.
+	vango.WithOwner(nil, func() {
+		c.viewport = vango.NewSignal(env.Viewport, vango.Transient())
+		c.tzOffset = vango.NewSignal(env.TZOffset, vango.Transient())
+		c.locale = vango.NewSignal(env.Locale, vango.Transient())
+		c.colorScheme = vango.NewSignal(env.ColorScheme, vango.Transient())
+		c.reducedMotion = vango.NewSignal(env.ReducedMotion, vango.Transient())
+	})
+	return c
+}
+
+// Viewport returns the viewport size.
+func (c *Client) Viewport() Viewport { return c.viewport.Get() }
+
+// TZOffset returns the timezone offset in minutes east of UTC.
+func (c *Client) TZOffset() int { return c.tzOffset.Get() }
+
+// Location returns the client's timezone. See ClientEnv.Location.
+func (c *Client) Location() *time.Location { return fixedZone(c.tzOffset.Get()) }
+
+// Locale returns the browser locale, or "" if unknown.
+func (c *Client) Locale() string { return c.locale.Get() }
+
+// ColorScheme returns the prefers-color-scheme setting.
+func (c *Client) ColorScheme() ColorScheme { return c.colorScheme.Get() }
+
+// ReducedMotion reports whether the user prefers reduced motion.
+func (c *Client) ReducedMotion() bool { return c.reducedMotion.Get() }
+
+// Env returns all values at once, subscribing to each of them.
+func (c *Client) Env() ClientEnv {
+	return ClientEnv{
+		Viewport:      c.viewport.Get(),
+		TZOffset:      c.tzOffset.Get(),
+		Locale:        c.locale.Get(),
+		ColorScheme:   c.colorScheme.Get(),
+		ReducedMotion: c.reducedMotion.Get(),
+	}
+}
+
+// peek returns the current values without subscribing.
+func (c *Client) peek() ClientEnv {
+	return ClientEnv{
+		Viewport:      c.viewport.Peek(),
+		TZOffset:      c.tzOffset.Peek(),
+		Locale:        c.locale.Peek(),
+		ColorScheme:   c.colorScheme.Peek(),
+		ReducedMotion: c.reducedMotion.Peek(),
+	}
+}
+
+// set updates the values that changed. Must run on the session loop once
+// components are mounted.
+func (c *Client) set(env ClientEnv) {
+	c.viewport.Set(env.Viewport)
+	c.tzOffset.Set(env.TZOffset)
+	c.locale.Set(env.Locale)
+	c.colorScheme.Set(env.ColorScheme)
+	c.reducedMotion.Set(env.ReducedMotion)
+}
+
+// Client returns the session's browser environment. The values persist
+// across reconnects and are refreshed from each handshake.
+func (s *Session) Client() *Client {
+	s.clientOnce.Do(func() {
+		s.client = NewClient(ClientEnv{Viewport: DefaultViewport})
+	})
+	return s.client
+}
+
+// handleClientEnv applies a ControlClientEnv message on the session loop.
+func (s *Session) handleClientEnv(ce *protocol.ClientEnv) {
+	s.Dispatch(func() {
+		client := s.Client()
+		client.set(client.peek().withEnv(ce))
+	})
+}
+
+func headerInt(h http.Header, keys ...string) int {
+	for _, key := range keys {
+		if v := h.Get(key); v != "" {
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// primaryLanguage returns the first language tag of an Accept-Language
+// header. The header lists languages in preference order in practice, so
+// q-values are not compared.
+func primaryLanguage(header string) string {
+	tag, _, _ := strings.Cut(header, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return ""
+	}
+	return tag
+}
+
+func fixedZone(offsetMinutes int) *time.Location {
+	if offsetMinutes == 0 {
+		return time.UTC
+	}
+	sign := '+'
+	abs := offsetMinutes
+	if abs < 0 {
+		sign = '-'
+		abs = -abs
+	}
+	return time.FixedZone(fmt.Sprintf("UTC%c%02d:%02d", sign, abs/60, abs%60), offsetMinutes*60)
+}
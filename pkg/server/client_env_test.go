@@ -0,0 +1,135 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestClientEnvFromRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := ClientEnvFromRequest(r); got != (ClientEnv{Viewport: DefaultViewport}) {
+		t.Errorf("no headers: got %+v", got)
+	}
+
+	r.Header.Set("Accept-Language", "fr-CA;q=0.9, en;q=0.8")
+	r.Header.Set("Sec-CH-UA-Mobile", "?1")
+	r.Header.Set("Sec-CH-Viewport-Width", "412")
+	r.Header.Set("Sec-CH-Prefers-Color-Scheme", `"dark"`)
+	r.Header.Set("Sec-CH-Prefers-Reduced-Motion", "reduce")
+	want := ClientEnv{
+		Viewport:      Viewport{Width: 412, Height: DefaultMobileViewport.Height},
+		Locale:        "fr-CA",
+		ColorScheme:   ColorSchemeDark,
+		ReducedMotion: true,
+	}
+	if got := ClientEnvFromRequest(r); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestClientEnv_WithHello(t *testing.T) {
+	base := ClientEnv{Viewport: DefaultViewport, Locale: "en-GB", ColorScheme: ColorSchemeLight}
+
+	// A client that does not report locale or preferences keeps the
+	// header-derived ones.
+	legacy := base.withHello(&protocol.ClientHello{ViewportW: 800, ViewportH: 600, TZOffset: -300})
+	want := ClientEnv{Viewport: Viewport{800, 600}, TZOffset: -300, Locale: "en-GB", ColorScheme: ColorSchemeLight}
+	if legacy != want {
+		t.Errorf("legacy hello: got %+v, want %+v", legacy, want)
+	}
+
+	full := base.withHello(&protocol.ClientHello{Locale: "ja-JP", Prefs: protocol.ClientPrefReducedMotion})
+	want = ClientEnv{Viewport: DefaultViewport, Locale: "ja-JP", ReducedMotion: true}
+	if full != want {
+		t.Errorf("full hello: got %+v, want %+v", full, want)
+	}
+}
+
+func TestClientEnv_Location(t *testing.T) {
+	tests := []struct {
+		offset int
+		name   string
+	}{
+		{0, "UTC"},
+		{330, "UTC+05:30"},
+		{-480, "UTC-08:00"},
+	}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range tests {
+		loc := ClientEnv{TZOffset: tc.offset}.Location()
+		name, offset := at.In(loc).Zone()
+		if name != tc.name || offset != tc.offset*60 {
+			t.Errorf("Location(%d) = %s %d, want %s %d", tc.offset, name, offset, tc.name, tc.offset*60)
+		}
+	}
+}
+
+func TestServer_ClientEnvLive(t *testing.T) {
+	s := New(DefaultServerConfig().WithDevMode())
+	s.SetRootComponent(func() Component {
+		return FuncComponent(func() *vdom.VNode {
+			client := vango.UseCtx().(Ctx).Client()
+			return vdom.Div(vdom.Text(fmt.Sprintf("%d %s", client.Viewport().Width, client.ColorScheme())))
+		})
+	})
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { s.Sessions().Shutdown() })
+
+	header := http.Header{"Accept-Language": {"nl-NL,nl;q=0.9"}}
+	conn := dialWS(t, wsURL(t, ts.URL, "/_vango/live?path=/"), header)
+	hello := protocol.NewClientHello("")
+	hello.ViewportW, hello.ViewportH = 1024, 768
+	hello.TZOffset = 60
+	writeHandshake(t, conn, hello)
+	sh := readServerHello(t, conn)
+	if sh.Status != protocol.HandshakeOK {
+		t.Fatalf("status = %v", sh.Status)
+	}
+
+	sess := s.Sessions().Get(sh.SessionID)
+	if sess == nil {
+		t.Fatal("session not found")
+	}
+	want := ClientEnv{Viewport: Viewport{1024, 768}, TZOffset: 60, Locale: "nl-NL"}
+	if got := sess.Client().peek(); got != want {
+		t.Fatalf("env after handshake = %+v, want %+v", got, want)
+	}
+
+	ct, env := protocol.NewClientEnv(&protocol.ClientEnv{
+		ViewportW: 375,
+		ViewportH: 667,
+		TZOffset:  60,
+		Locale:    "nl-NL",
+		Prefs:     protocol.ClientPrefDark,
+	})
+	frame := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, env))
+	if err := conn.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+
+	// The component re-renders with the new values.
+	for {
+		frame, _ := readMessage(t, conn, protocol.NewReassembler(0))
+		if frame.Type != protocol.FramePatches {
+			continue
+		}
+		pf, err := protocol.DecodePatches(frame.Payload)
+		if err != nil {
+			t.Fatalf("DecodePatches failed: %v", err)
+		}
+		for _, p := range pf.Patches {
+			if p.Value == "375 dark" {
+				return
+			}
+		}
+	}
+}
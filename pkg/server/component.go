@@ -55,6 +55,12 @@ type ComponentInstance struct {
 
 	// lastTree is the last rendered VNode tree (for diffing).
 	lastTree *vdom.VNode
+
+	// boundaryErr is the panic an ErrorBoundary instance is showing its
+	// fallback for, and showingFallback whether its last render was the
+	// fallback. Both are only touched on the session loop.
+	boundaryErr     error
+	showingFallback bool
 }
 
 var _ vango.Listener = (*ComponentInstance)(nil)
@@ -95,7 +101,12 @@ func (c *ComponentInstance) Render() *vdom.VNode {
 	if c.Component == nil {
 		return nil
 	}
+	return c.renderWith(c.Component.Render)
+}
 
+// renderWith renders fn in the component's render context, as Render does
+// for the component's own Render method.
+func (c *ComponentInstance) renderWith(fn func() *vdom.VNode) *vdom.VNode {
 	var tree *vdom.VNode
 
 	// Create render context so UseCtx() works during component render
@@ -114,7 +125,7 @@ func (c *ComponentInstance) Render() *vdom.VNode {
 			defer c.Owner.EndRender()
 
 			vango.WithListener(c, func() {
-				tree = c.Component.Render()
+				tree = fn()
 			})
 		})
 	})
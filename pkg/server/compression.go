@@ -0,0 +1,96 @@
+package server
+
+import (
+	"io"
+
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+// compressorMemory approximates the memory held by a DEFLATE compressor
+// (hash chains plus a 32KB window), used for session memory accounting.
+const compressorMemory = 768 * 1024
+
+// negotiateCodec picks the payload codec for a new connection from the
+// codecs offered in ClientHello and resets the compression state. It returns
+// the codec to announce in ServerHello.
+//
+// The compressor's window is shared with the client's decompressor for the
+// lifetime of one WebSocket connection, so every handshake starts a fresh
+// stream.
+func (s *Session) negotiateCodec(offered []protocol.Codec) protocol.Codec {
+	codec := protocol.CodecNone
+	if s.config.EnableCompression {
+		codec = protocol.NegotiateCodec(offered)
+	}
+
+	s.mu.Lock()
+	s.codec = codec
+	s.compressor.Store(nil)
+	s.mu.Unlock()
+	return codec
+}
+
+// encodeFrameLocked encodes frame for the wire with Frame.EncodeMessage,
+// compressing its payload first if a codec was negotiated and the payload
+// is at least CompressionThreshold bytes.
+//
+// The whole message is compressed before it is fragmented, so large
+// messages compress as well as small ones and the client decompresses after
+// reassembly. Messages that only fit in several frames are compressed only
+// for clients that reassemble fragments, since older clients cap the
+// decompressed size at one frame.
+//
+// The compressor is created on first use so sessions that only ever send
+// small patches don't pay for it. Frames must be written in the order they
+// are compressed; the caller must hold s.mu across compression and write.
+func (s *Session) encodeFrameLocked(frame *protocol.Frame) ([]byte, error) {
+	payload := frame.Payload
+	if s.codec == protocol.CodecNone ||
+		len(payload) < s.config.CompressionThreshold ||
+		len(payload) > protocol.MaxCompressiblePayload ||
+		(len(payload) > protocol.MaxPayloadSize && !s.versionLocked().SupportsFragmentation()) {
+		return frame.EncodeMessage(), nil
+	}
+
+	c := s.compressor.Load()
+	if c == nil {
+		var err error
+		c, err = protocol.NewCompressor(s.codec)
+		if err != nil {
+			return nil, err
+		}
+		s.compressor.Store(c)
+	}
+
+	compressed, err := c.Compress(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	out := protocol.NewFrameWithFlags(frame.Type, frame.Flags|protocol.FlagCompressed, compressed)
+	return out.EncodeMessage(), nil
+}
+
+// decodeMessage joins a message encoded by Frame.EncodeMessage, such as a
+// patch history entry, back into one frame.
+func decodeMessage(data []byte) (*protocol.Frame, error) {
+	parts, err := protocol.SplitFrames(data)
+	if err != nil {
+		return nil, err
+	}
+	r := protocol.NewReassembler(len(data))
+	for _, part := range parts {
+		frame, err := protocol.DecodeFrame(part)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := r.Add(frame)
+		if err != nil {
+			return nil, err
+		}
+		if msg != nil {
+			return msg, nil
+		}
+	}
+	return nil, io.ErrUnexpectedEOF
+}
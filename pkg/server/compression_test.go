@@ -0,0 +1,204 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+func readPatchFrame(t *testing.T, conn *websocket.Conn) *protocol.Frame {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage failed: %v", err)
+	}
+	frame, err := protocol.DecodeFrame(msg)
+	if err != nil {
+		t.Fatalf("DecodeFrame failed: %v", err)
+	}
+	if frame.Type != protocol.FramePatches {
+		t.Fatalf("frame type = %v, want %v", frame.Type, protocol.FramePatches)
+	}
+	return frame
+}
+
+func decodePatchFrame(t *testing.T, frame *protocol.Frame, d *protocol.Decompressor) *protocol.PatchesFrame {
+	t.Helper()
+	payload := frame.Payload
+	if frame.Flags.Has(protocol.FlagCompressed) {
+		var err error
+		payload, err = d.Decompress(payload)
+		if err != nil {
+			t.Fatalf("Decompress failed: %v", err)
+		}
+	}
+	pf, err := protocol.DecodePatches(payload)
+	if err != nil {
+		t.Fatalf("DecodePatches failed: %v", err)
+	}
+	return pf
+}
+
+func TestSession_CompressesPatchFramesAboveThreshold(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+
+	if codec := sess.negotiateCodec([]protocol.Codec{protocol.CodecDeflate}); codec != protocol.CodecDeflate {
+		t.Fatalf("negotiateCodec = %v, want deflate", codec)
+	}
+	d, _ := protocol.NewDecompressor(protocol.CodecDeflate)
+
+	sess.SendPatches([]protocol.Patch{protocol.NewSetTextPatch("h1", "small")})
+	small := readPatchFrame(t, clientConn)
+	if small.Flags.Has(protocol.FlagCompressed) {
+		t.Error("frame below threshold was compressed")
+	}
+	if sess.compressor.Load() != nil {
+		t.Error("compressor created before any frame needed it")
+	}
+
+	rows := strings.Repeat("<tr><td>cell</td><td>value</td></tr>", 100)
+	var sizes []int
+	for i := 0; i < 2; i++ {
+		sess.SendPatches([]protocol.Patch{protocol.NewSetTextPatch("h2", rows)})
+		frame := readPatchFrame(t, clientConn)
+		if !frame.Flags.Has(protocol.FlagCompressed) {
+			t.Fatalf("frame %d above threshold was not compressed", i)
+		}
+		pf := decodePatchFrame(t, frame, d)
+		if len(pf.Patches) != 1 || pf.Patches[0].Value != rows {
+			t.Fatalf("frame %d decoded to %+v", i, pf.Patches)
+		}
+		sizes = append(sizes, len(frame.Payload))
+	}
+	if sizes[0] >= len(rows)/4 {
+		t.Errorf("compressed size = %d for %d bytes of markup", sizes[0], len(rows))
+	}
+	if sizes[1] >= sizes[0] {
+		t.Errorf("second frame = %d bytes, first = %d; window not shared", sizes[1], sizes[0])
+	}
+}
+
+func TestSession_ReplayCompressesAgainstCurrentWindow(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	cfg := DefaultSessionConfig()
+	cfg.CompressionThreshold = 0
+	sess := newSession(serverConn, "", cfg, slog.Default())
+	sess.negotiateCodec([]protocol.Codec{protocol.CodecDeflate})
+	d, _ := protocol.NewDecompressor(protocol.CodecDeflate)
+
+	for _, v := range []string{"one", "two"} {
+		sess.sendPatchesWithURL(nil, []protocol.Patch{protocol.NewSetTextPatch("h1", v)})
+		decodePatchFrame(t, readPatchFrame(t, clientConn), d)
+	}
+
+	// History holds uncompressed frames; replaying them must continue the
+	// client's stream rather than resend old compressed bytes.
+	frames := sess.patchHistory.GetFrames(0, 2)
+	if len(frames) != 2 {
+		t.Fatalf("history frames = %d, want 2", len(frames))
+	}
+	for _, f := range frames {
+		if protocol.FrameFlags(f[1]).Has(protocol.FlagCompressed) {
+			t.Fatal("patch history stored a compressed frame")
+		}
+	}
+	sess.replayPatchFrames(frames)
+	for _, want := range []string{"one", "two"} {
+		pf := decodePatchFrame(t, readPatchFrame(t, clientConn), d)
+		if pf.Patches[0].Value != want {
+			t.Errorf("replayed value = %q, want %q", pf.Patches[0].Value, want)
+		}
+	}
+}
+
+func TestSession_CompressesLargeMessagesBeforeFragmenting(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	sess.negotiateCodec([]protocol.Codec{protocol.CodecDeflate})
+	d, _ := protocol.NewDecompressor(protocol.CodecDeflate)
+	r := protocol.NewReassembler(0)
+
+	// Markup well over one frame compresses into a single frame.
+	rows := strings.Repeat("<tr><td>cell</td><td>value</td></tr>", 3*protocol.MaxPayloadSize/36)
+	sess.sendPatchesWithURL(nil, []protocol.Patch{protocol.NewSetTextPatch("h1", rows)})
+	frame, n := readMessage(t, clientConn, r)
+	if n != 1 || !frame.Flags.Has(protocol.FlagCompressed) {
+		t.Fatalf("large markup arrived in %d frames with flags %#x, want one compressed frame", n, frame.Flags)
+	}
+	if pf := decodePatchFrame(t, frame, d); pf.Patches[0].Value != rows {
+		t.Fatal("decompressed patch does not match")
+	}
+
+	// Data that stays large after compression is fragmented, and the
+	// client decompresses the reassembled message.
+	random := make([]byte, 2*protocol.MaxPayloadSize)
+	rand.Read(random)
+	text := base64.StdEncoding.EncodeToString(random)
+	sess.sendPatchesWithURL(nil, []protocol.Patch{protocol.NewSetTextPatch("h2", text)})
+	frame, n = readMessage(t, clientConn, r)
+	if n < 2 || !frame.Flags.Has(protocol.FlagCompressed) {
+		t.Fatalf("large text arrived in %d frames with flags %#x, want compressed fragments", n, frame.Flags)
+	}
+	if pf := decodePatchFrame(t, frame, d); pf.Patches[0].Value != text {
+		t.Fatal("decompressed patch does not match")
+	}
+
+	// Replays are compressed whole as well.
+	frames := sess.patchHistory.GetFrames(0, 2)
+	if len(frames) != 2 {
+		t.Fatalf("history frames = %d, want 2", len(frames))
+	}
+	sess.replayPatchFrames(frames)
+	for _, want := range []string{rows, text} {
+		frame, _ := readMessage(t, clientConn, r)
+		if !frame.Flags.Has(protocol.FlagCompressed) {
+			t.Fatal("replayed message was not compressed")
+		}
+		if pf := decodePatchFrame(t, frame, d); pf.Patches[0].Value != want {
+			t.Fatal("replayed patch does not match")
+		}
+	}
+}
+
+func TestSession_NegotiateCodecDisabled(t *testing.T) {
+	cfg := DefaultSessionConfig()
+	cfg.EnableCompression = false
+	sess := newSession(nil, "", cfg, slog.Default())
+	if codec := sess.negotiateCodec([]protocol.Codec{protocol.CodecDeflate}); codec != protocol.CodecNone {
+		t.Errorf("negotiateCodec = %v, want none when compression is disabled", codec)
+	}
+}
+
+func TestServer_HandshakeNegotiatesCodec(t *testing.T) {
+	s := New(DefaultServerConfig().WithDevMode())
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { s.Sessions().Shutdown() })
+
+	legacy := dialWS(t, wsURL(t, ts.URL, "/_vango/live?path=/"), nil)
+	writeHandshake(t, legacy, protocol.NewClientHello(""))
+	if h := readServerHello(t, legacy); h.Codec() != protocol.CodecNone || h.Flags&protocol.ServerFlagCompression != 0 {
+		t.Errorf("hello without codecs: flags = %#x, want compression off", h.Flags)
+	}
+
+	conn := dialWS(t, wsURL(t, ts.URL, "/_vango/live?path=/"), nil)
+	hello := protocol.NewClientHello("")
+	hello.Codecs = []protocol.Codec{protocol.Codec(0x7F), protocol.CodecDeflate}
+	writeHandshake(t, conn, hello)
+	h := readServerHello(t, conn)
+	if h.Status != protocol.HandshakeOK {
+		t.Fatalf("status = %v, want OK", h.Status)
+	}
+	if h.Codec() != protocol.CodecDeflate {
+		t.Errorf("Codec() = %v, want deflate", h.Codec())
+	}
+}
@@ -8,7 +8,10 @@ import (
 
 	"github.com/vango-go/vango/pkg/assets"
 	"github.com/vango-go/vango/pkg/auth"
+	"github.com/vango-go/vango/pkg/pref"
+	"github.com/vango-go/vango/pkg/protocol"
 	"github.com/vango-go/vango/pkg/session"
+	"github.com/vango-go/vango/pkg/vango"
 )
 
 // SessionConfig holds configuration for individual sessions.
@@ -35,12 +38,24 @@ type SessionConfig struct {
 	// Default: 30 seconds.
 	HeartbeatInterval time.Duration
 
+	// HookCallTimeout is the maximum time CallHook waits for a client hook
+	// to answer, unless the call's context ends sooner.
+	// Default: 10 seconds.
+	HookCallTimeout time.Duration
+
 	// Limits
 
 	// MaxMessageSize is the maximum size of an incoming WebSocket message.
-	// Default: 64KB.
+	// Each message carries one frame, so this should leave room for a full
+	// frame header plus MaxPayloadSize.
+	// Default: 64KB + 4 bytes.
 	MaxMessageSize int64
 
+	// MaxReassemblySize caps the total payload buffered while reassembling
+	// fragmented frames from the client. Messages that exceed it are dropped.
+	// Default: 1MB.
+	MaxReassemblySize int
+
 	// MaxPatchHistory is the number of recent patches to keep for resync.
 	// Default: 100.
 	MaxPatchHistory int
@@ -51,10 +66,17 @@ type SessionConfig struct {
 
 	// Features
 
-	// EnableCompression enables WebSocket compression.
+	// EnableCompression enables per-frame payload compression for clients
+	// that advertise a supported codec in their handshake.
 	// Default: true.
 	EnableCompression bool
 
+	// CompressionThreshold is the minimum patch frame payload size, in bytes,
+	// that is compressed once a codec has been negotiated. Smaller frames are
+	// sent as-is. 0 compresses every patch frame.
+	// Default: 1024.
+	CompressionThreshold int
+
 	// EnableOptimistic enables optimistic updates on the client.
 	// Default: true.
 	EnableOptimistic bool
@@ -249,18 +271,21 @@ func DefaultStormBudgetConfig() *StormBudgetConfig {
 // DefaultSessionConfig returns a SessionConfig with sensible defaults.
 func DefaultSessionConfig() *SessionConfig {
 	return &SessionConfig{
-		ReadTimeout:       60 * time.Second,
-		WriteTimeout:      10 * time.Second,
-		IdleTimeout:       5 * time.Minute,
-		HandshakeTimeout:  10 * time.Second,
-		HeartbeatInterval: 30 * time.Second,
-		MaxMessageSize:    64 * 1024, // 64KB
-		MaxPatchHistory:   100,
-		MaxEventQueue:     256,
-		EnableCompression: true,
-		EnableOptimistic:  true,
-		StormBudget:       DefaultStormBudgetConfig(),
-		AuthCheck:         nil,
+		ReadTimeout:          60 * time.Second,
+		WriteTimeout:         10 * time.Second,
+		IdleTimeout:          5 * time.Minute,
+		HandshakeTimeout:     10 * time.Second,
+		HeartbeatInterval:    30 * time.Second,
+		HookCallTimeout:      10 * time.Second,
+		MaxMessageSize:       protocol.FrameHeaderSize + protocol.MaxPayloadSize,
+		MaxReassemblySize:    1024 * 1024, // 1MB
+		MaxPatchHistory:      100,
+		MaxEventQueue:        256,
+		EnableCompression:    true,
+		CompressionThreshold: 1024,
+		EnableOptimistic:     true,
+		StormBudget:          DefaultStormBudgetConfig(),
+		AuthCheck:            nil,
 	}
 }
 
@@ -301,6 +326,18 @@ type ServerConfig struct {
 	// Default: allows all origins (not recommended for production).
 	CheckOrigin func(r *http.Request) bool
 
+	// HTTPFallback serves the Server-Sent Events + POST transport at
+	// /_vango/sse. The client switches to it when the WebSocket upgrade
+	// fails, e.g. behind proxies that block upgrades.
+	//
+	// SECURITY: the fallback is a second entry point to sessions. Like the
+	// WebSocket endpoint it applies CheckOrigin (which accepts requests
+	// without an Origin header) and the handshake's CSRF check, but POSTs to
+	// an open stream are authorized only by its unguessable connection ID.
+	// Set CSRFSecret before enabling it in production.
+	// Default: false.
+	HTTPFallback bool
+
 	// Session configuration
 
 	// SessionConfig is the configuration for individual sessions.
@@ -453,6 +490,22 @@ type ServerConfig struct {
 	// Default: nil (in-memory only).
 	SessionStore session.SessionStore
 
+	// GlobalSignalBackend synchronizes global signals created with SyncKey
+	// across server instances. The server attaches it when created, and
+	// detaches and closes it on Shutdown.
+	// Default: nil (global signals are process-local).
+	GlobalSignalBackend vango.GlobalSignalBackend
+
+	// BroadcastTransport carries Server.Broadcast messages between instances.
+	// Default: nil (in-process delivery only).
+	BroadcastTransport BroadcastTransport
+
+	// PrefStore persists the preferences (pkg/pref) of logged-in users and
+	// merges them with the client's values at login.
+	// Use pref.NewMemoryStore() or pref.NewSQLStore().
+	// Default: nil (preferences live in the client's localStorage only).
+	PrefStore pref.Store
+
 	// ResumeWindow is how long a detached session remains resumable after disconnect.
 	// After this window, the session is permanently expired.
 	// Default: 5 minutes.
@@ -554,6 +607,7 @@ func DefaultServerConfig() *ServerConfig {
 		ReadBufferSize:      4096,
 		WriteBufferSize:     4096,
 		CheckOrigin:         SameOriginCheck, // SECURE DEFAULT: reject cross-origin
+		HTTPFallback:        false,
 		SessionConfig:       DefaultSessionConfig(),
 		ShutdownTimeout:     30 * time.Second,
 		ReadHeaderTimeout:   5 * time.Second,
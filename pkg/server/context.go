@@ -13,6 +13,7 @@ import (
 
 	"github.com/vango-go/vango/pkg/assets"
 	"github.com/vango-go/vango/pkg/auth"
+	"github.com/vango-go/vango/pkg/features/islands"
 	"github.com/vango-go/vango/pkg/protocol"
 	"github.com/vango-go/vango/pkg/routepath"
 	"github.com/vango-go/vango/pkg/vango"
@@ -348,6 +349,60 @@ type Ctx interface {
 	//     // In dev: "/public/vango.js"
 	//     // In prod: "/public/vango.a1b2c3d4.min.js"
 	Asset(source string) string
+
+	// ==========================================================================
+	// Client Environment
+	// ==========================================================================
+
+	// Client returns the browser environment: viewport, timezone, locale and
+	// color scheme and reduced motion preferences. The values are signals,
+	// so components that read them re-render when the client resizes or
+	// changes a preference.
+	//
+	// During SSR the values are derived from request headers; see
+	// ClientEnvFromRequest.
+	//
+	// Example:
+	//
+	//     client := ctx.Client()
+	//     created := post.CreatedAt.In(client.Location()).Format(time.Kitchen)
+	//     if client.Viewport().Width < 640 {
+	//         return MobileLayout(children)
+	//     }
+	Client() *Client
+
+	// ==========================================================================
+	// Client Hooks
+	// ==========================================================================
+
+	// CallHook calls a method on the client hook at hid and waits for its
+	// result. The call is canceled when StdContext() ends and times out
+	// after SessionConfig.HookCallTimeout. See Session.CallHook.
+	//
+	// Returns ErrNoConnection outside a WebSocket session.
+	//
+	// Example:
+	//
+	//     // From a goroutine, since CallHook blocks
+	//     result, err := ctx.CallHook(editorID, "getSelection", nil)
+	//     if err == nil {
+	//         ctx.Dispatch(func() { selection.Set(result.(string)) })
+	//     }
+	CallHook(hid, method string, args map[string]any) (any, error)
+
+	// CallHookAsync calls a method on the client hook at hid without
+	// blocking and runs fn with the result on the session loop, where it
+	// may set signals.
+	//
+	// Example:
+	//
+	//     ctx.CallHookAsync(canvasID, "toDataURL", map[string]any{"type": "image/png"},
+	//         func(result any, err error) {
+	//             if err == nil {
+	//                 preview.Set(result.(string))
+	//             }
+	//         })
+	CallHookAsync(hid, method string, args map[string]any, fn func(result any, err error))
 }
 
 // ctx is the concrete implementation of Ctx.
@@ -379,6 +434,10 @@ type ctx struct {
 
 	// redirectAllowlist is an optional allowlist for external redirects.
 	redirectAllowlist map[string]struct{}
+
+	// client is the environment for contexts without a session, built from
+	// the request on first use.
+	client *Client
 }
 
 // pendingNav holds a pending navigation request.
@@ -536,6 +595,16 @@ func (c *ctx) Session() *Session {
 	return c.session
 }
 
+// IslandBridge returns the session's JS island bridge, or nil outside a
+// WebSocket session. It lets islands.SendToIsland work in Dispatch callbacks,
+// which run without a component owner.
+func (c *ctx) IslandBridge() *islands.Bridge {
+	if c.session == nil {
+		return nil
+	}
+	return c.session.islands
+}
+
 // AuthSession returns the session as an auth.Session interface.
 func (c *ctx) AuthSession() auth.Session {
 	if c.session == nil {
@@ -1018,6 +1087,49 @@ func (c *ctx) Asset(source string) string {
 	return c.assetResolver.Asset(source)
 }
 
+// =============================================================================
+// Client Environment
+// =============================================================================
+
+// Client returns the session's browser environment, or one derived from the
+// request headers outside a WebSocket session.
+func (c *ctx) Client() *Client {
+	if c.session != nil {
+		return c.session.Client()
+	}
+	if c.client == nil {
+		c.client = NewClient(ClientEnvFromRequest(c.request))
+	}
+	return c.client
+}
+
+// =============================================================================
+// Client Hooks
+// =============================================================================
+
+// CallHook calls a method on a client hook and waits for the result.
+// Returns ErrNoConnection if there is no session.
+func (c *ctx) CallHook(hid, method string, args map[string]any) (any, error) {
+	if c.session == nil {
+		return nil, ErrNoConnection
+	}
+	return c.session.CallHook(c.StdContext(), hid, method, args)
+}
+
+// CallHookAsync calls a method on a client hook in a new goroutine and
+// dispatches fn with the result to the session loop.
+func (c *ctx) CallHookAsync(hid, method string, args map[string]any, fn func(result any, err error)) {
+	if c.session == nil {
+		fn(nil, ErrNoConnection)
+		return
+	}
+	s, stdCtx := c.session, c.StdContext()
+	go func() {
+		result, err := s.CallHook(stdCtx, hid, method, args)
+		s.Dispatch(func() { fn(result, err) })
+	}()
+}
+
 // setAssetResolver sets the asset resolver for this context.
 // This is called internally when creating a context from a server with a configured resolver.
 func (c *ctx) setAssetResolver(r assets.Resolver) {
@@ -57,6 +57,37 @@
 //
 //	server.Run()
 //
+// # Recording and Replay
+//
+// Session.Record captures a session's events, client environment changes and
+// patches, with their timing, to a file. Server.Replay, or `vango replay
+// <file>`, mounts the same route headlessly, feeds it the recorded inputs and
+// reports the first input after which the patches differ.
+//
+// # Inspecting the Reactive Graph
+//
+// Session.Inspect snapshots a session's owners, components, signals, memos
+// and effects with their run counts and subscription edges. In DevMode the
+// server also serves it as JSON at /_vango/inspect?session=<id>, and the
+// client shows it in an overlay toggled with Alt+Shift+G when data-debug is
+// set. Outside DevMode the endpoint responds 404.
+//
+// # Error Boundaries
+//
+// A panic while rendering a component, or in one of its effects, is caught
+// by the nearest vango.ErrorBoundary above it. The boundary disposes its
+// children, logs the panic with the component path and stack, and renders
+// its fallback with a *RenderError until the fallback calls reset. Panics
+// outside any boundary still end the session.
+//
+// # Preferences
+//
+// Each session holds its own values for pkg/pref preferences, starting from
+// the values in the client's localStorage sent with the handshake. Changes
+// travel both ways as PrefSync control messages. For an authenticated user
+// the values are merged with ServerConfig.PrefStore, and changes are saved
+// there and broadcast to the user's other sessions.
+//
 // # Thread Safety
 //
 // The server package is designed for concurrent access:
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// =============================================================================
+// Error Boundaries
+// =============================================================================
+
+// boundaryOf returns the boundary component of c if c is an ErrorBoundary.
+func boundaryOf(c *ComponentInstance) *vango.ErrorBoundaryComponent {
+	if c == nil {
+		return nil
+	}
+	b, _ := c.Component.(*vango.ErrorBoundaryComponent)
+	return b
+}
+
+// nearestBoundary returns the closest ErrorBoundary instance at or above c.
+func nearestBoundary(c *ComponentInstance) *ComponentInstance {
+	for ; c != nil; c = c.Parent {
+		if boundaryOf(c) != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// componentPath identifies c in logs: the instance IDs from the root down,
+// followed by the component type.
+func componentPath(c *ComponentInstance) string {
+	if c == nil {
+		return ""
+	}
+	var ids []string
+	for cur := c; cur != nil; cur = cur.Parent {
+		ids = append(ids, cur.InstanceID)
+	}
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	return fmt.Sprintf("%s (%T)", strings.Join(ids, "/"), c.Component)
+}
+
+// renderBoundary renders an ErrorBoundary instance: its children, or its
+// fallback once something below it has panicked. Switching between the two
+// disposes whatever was mounted before.
+// Caller must hold stateMu.
+func (s *Session) renderBoundary(instance *ComponentInstance, b *vango.ErrorBoundaryComponent) *vdom.VNode {
+	instance.Owner.SetPanicHandler(s.effectPanicHandler(instance))
+
+	if instance.boundaryErr == nil {
+		if instance.showingFallback {
+			s.disposeChildrenLocked(instance)
+			instance.showingFallback = false
+		}
+		if tree, ok := s.renderBoundaryChildren(instance); ok {
+			return tree
+		}
+	}
+
+	if !instance.showingFallback {
+		s.disposeChildrenLocked(instance)
+		instance.showingFallback = true
+	}
+	err := instance.boundaryErr
+	reset := func() { s.resetBoundary(instance) }
+	tree := instance.renderWith(func() *vdom.VNode {
+		return b.RenderFallback(err, reset, s.errorPage())
+	})
+	s.rerenderChildren(tree, instance)
+	return tree
+}
+
+// renderBoundaryChildren renders the children of a boundary, catching panics.
+// Caller must hold stateMu.
+func (s *Session) renderBoundaryChildren(instance *ComponentInstance) (tree *vdom.VNode, ok bool) {
+	s.panicSource = nil
+	defer func() {
+		if r := recover(); r != nil {
+			s.catchPanicLocked(instance, "render", r)
+			tree, ok = nil, false
+		}
+	}()
+	tree = instance.Render()
+	s.rerenderChildren(tree, instance)
+	return tree, true
+}
+
+// renderTreeCaught renders comp's subtree for a re-render of comp alone. If
+// the render panics and an ErrorBoundary above comp catches it, the boundary
+// is returned instead of a tree so the caller can re-render it.
+// Caller must hold stateMu.
+func (s *Session) renderTreeCaught(comp *ComponentInstance) (tree *vdom.VNode, boundary *ComponentInstance) {
+	b := nearestBoundary(comp.Parent)
+	if b == nil {
+		return s.rerenderTree(comp), nil
+	}
+	s.panicSource = nil
+	defer func() {
+		if r := recover(); r != nil {
+			s.catchPanicLocked(b, "render", r)
+			tree, boundary = nil, b
+		}
+	}()
+	return s.rerenderTree(comp), nil
+}
+
+// catchPanicLocked records a panic caught by boundary: it logs the panic with
+// the path of the component that raised it, disposes the boundary's children
+// and stores the error for the fallback. It must be called from the deferred
+// function that recovered the panic, so the stack is still the panic's.
+// Caller must hold stateMu.
+func (s *Session) catchPanicLocked(boundary *ComponentInstance, phase string, r any) {
+	source := s.panicSource
+	s.panicSource = nil
+	if source == nil {
+		source = boundary
+	}
+
+	err := &RenderError{
+		SessionID: s.ID,
+		Component: componentPath(source),
+		Phase:     phase,
+		Panic:     r,
+		Stack:     debug.Stack(),
+	}
+	s.logger.Error("component panic",
+		"phase", phase,
+		"component", err.Component,
+		"boundary", componentPath(boundary),
+		"panic", r,
+		"stack", string(err.Stack))
+
+	s.disposeChildrenLocked(boundary)
+	boundary.boundaryErr = err
+}
+
+// effectPanicHandler returns the handler that routes panics from effects
+// below a boundary instance to the nearest boundary.
+func (s *Session) effectPanicHandler(instance *ComponentInstance) func(*vango.Owner, any) {
+	return func(owner *vango.Owner, r any) {
+		// The slot may since have been reused for another component.
+		b := nearestBoundary(instance)
+		if b == nil {
+			panic(r)
+		}
+		s.stateMu.Lock()
+		s.panicSource = s.instanceForOwnerLocked(owner)
+		s.catchPanicLocked(b, "effect", r)
+		s.stateMu.Unlock()
+		b.MarkDirty()
+	}
+}
+
+// instanceForOwnerLocked returns the component instance owning owner, looking
+// through non-component scopes such as Suspense.
+// Caller must hold stateMu.
+func (s *Session) instanceForOwnerLocked(owner *vango.Owner) *ComponentInstance {
+	for ; owner != nil; owner = owner.Parent() {
+		for comp := range s.allComponents {
+			if comp.Owner == owner {
+				return comp
+			}
+		}
+	}
+	return nil
+}
+
+// resetBoundary clears a boundary's error so its next render mounts the
+// children again. It runs on the session loop, typically from an event
+// handler in the fallback.
+func (s *Session) resetBoundary(instance *ComponentInstance) {
+	if instance.boundaryErr == nil {
+		return
+	}
+	instance.boundaryErr = nil
+	instance.MarkDirty()
+}
+
+// disposeChildrenLocked disposes every instance below instance. Besides
+// instance.Children this includes instances mounted by a render that panicked
+// before it could attach them to their parent.
+// Caller must hold stateMu.
+func (s *Session) disposeChildrenLocked(instance *ComponentInstance) {
+	var below []*ComponentInstance
+	for comp := range s.allComponents {
+		for p := comp.Parent; p != nil; p = p.Parent {
+			if p == instance {
+				below = append(below, comp)
+				break
+			}
+		}
+	}
+
+	children := instance.Children
+	instance.Children = nil
+	for _, ch := range children {
+		s.disposeInstanceTreeLocked(ch)
+	}
+	for _, comp := range below {
+		s.disposeInstanceTreeLocked(comp)
+	}
+}
+
+// errorPage returns the router's error page renderer, or nil.
+func (s *Session) errorPage() func(error) *vdom.VNode {
+	if s.navigator == nil {
+		return nil
+	}
+	ep, ok := s.navigator.router.(ErrorPageRenderer)
+	if !ok {
+		return nil
+	}
+	return func(err error) *vdom.VNode {
+		return ep.RenderErrorPage(s.createRenderContext(), err)
+	}
+}
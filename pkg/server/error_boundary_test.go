@@ -0,0 +1,180 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// boundaryApp renders a button that breaks a child below an ErrorBoundary.
+// The child panics while rendering, or in an effect, once broken is set.
+type boundaryApp struct {
+	broken   *vango.Signal[bool]
+	inEffect bool
+	caught   error
+}
+
+func (a *boundaryApp) Render() *vdom.VNode {
+	child := vdom.Func(func() *vdom.VNode {
+		broken := a.broken.Get()
+		if a.inEffect {
+			vango.CreateEffect(func() vango.Cleanup {
+				if a.broken.Get() {
+					panic("effect broke")
+				}
+				return nil
+			})
+		} else if broken {
+			panic(errors.New("render broke"))
+		}
+		return vdom.P(vdom.Text("child ok"))
+	})
+
+	return vdom.Div(
+		vdom.Button(vdom.ID("break"), vdom.OnClick(func() { a.broken.Set(true) }), vdom.Text("break")),
+		vango.ErrorBoundary(func(err error, reset func()) *vdom.VNode {
+			a.caught = err
+			return vdom.Div(
+				vdom.Text("fallback"),
+				vdom.Button(vdom.ID("reset"), vdom.OnClick(func() {
+					a.broken.Set(false)
+					reset()
+				}), vdom.Text("retry")),
+			)
+		}, child),
+	)
+}
+
+func mountBoundaryApp(t *testing.T, app *boundaryApp) *Session {
+	t.Helper()
+	sess := newSession(nil, "", DefaultSessionConfig(), slog.Default())
+	app.broken = vango.NewSignal(false)
+	sess.MountRoot(app)
+	return sess
+}
+
+func boundaryInstance(t *testing.T, sess *Session) *ComponentInstance {
+	t.Helper()
+	for comp := range sess.allComponents {
+		if boundaryOf(comp) != nil {
+			return comp
+		}
+	}
+	t.Fatal("no error boundary mounted")
+	return nil
+}
+
+func clickID(t *testing.T, sess *Session, tree *vdom.VNode, id string) {
+	t.Helper()
+	var hid string
+	var walk func(*vdom.VNode)
+	walk = func(n *vdom.VNode) {
+		if n == nil {
+			return
+		}
+		if n.Props["id"] == id {
+			hid = n.HID
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(tree)
+	if hid == "" {
+		t.Fatalf("no element #%s", id)
+	}
+	sess.handleEvent(&Event{HID: hid, Type: protocol.EventClick})
+}
+
+func treeText(n *vdom.VNode) string {
+	if n == nil {
+		return ""
+	}
+	if n.Kind == vdom.KindText {
+		return n.Text
+	}
+	var sb strings.Builder
+	for _, c := range n.Children {
+		sb.WriteString(treeText(c))
+	}
+	return sb.String()
+}
+
+func TestErrorBoundary_CatchesRenderPanicAndResets(t *testing.T) {
+	app := &boundaryApp{}
+	sess := mountBoundaryApp(t, app)
+	b := boundaryInstance(t, sess)
+	if got := treeText(b.LastTree()); got != "child ok" {
+		t.Fatalf("boundary renders %q, want the child", got)
+	}
+	child := b.Children[0]
+
+	clickID(t, sess, sess.root.LastTree(), "break")
+
+	if got := treeText(b.LastTree()); !strings.HasPrefix(got, "fallback") {
+		t.Fatalf("boundary renders %q after the panic, want the fallback", got)
+	}
+	var re *RenderError
+	if !errors.As(app.caught, &re) || re.Phase != "render" || !strings.Contains(re.Component, child.InstanceID) {
+		t.Errorf("fallback got %v, want a render error naming %s", app.caught, child.InstanceID)
+	}
+	if app.caught.Error() == "" || errors.Unwrap(app.caught).Error() != "render broke" {
+		t.Errorf("error does not unwrap to the panic: %v", app.caught)
+	}
+	if child.Owner != nil {
+		t.Error("broken child was not disposed")
+	}
+	if _, ok := sess.allComponents[child]; ok {
+		t.Error("broken child is still registered")
+	}
+
+	clickID(t, sess, b.LastTree(), "reset")
+
+	if got := treeText(b.LastTree()); got != "child ok" {
+		t.Fatalf("boundary renders %q after reset, want the child", got)
+	}
+	if len(b.Children) != 1 || b.Children[0] == child {
+		t.Error("reset did not mount a fresh child")
+	}
+}
+
+func TestErrorBoundary_CatchesEffectPanic(t *testing.T) {
+	app := &boundaryApp{inEffect: true}
+	sess := mountBoundaryApp(t, app)
+	b := boundaryInstance(t, sess)
+
+	clickID(t, sess, sess.root.LastTree(), "break")
+
+	if got := treeText(b.LastTree()); !strings.HasPrefix(got, "fallback") {
+		t.Fatalf("boundary renders %q after the effect panic, want the fallback", got)
+	}
+	var re *RenderError
+	if !errors.As(app.caught, &re) || re.Phase != "effect" || re.Panic != "effect broke" {
+		t.Errorf("fallback got %v, want the effect panic", app.caught)
+	}
+}
+
+type errorPageRouter struct{ testRouter }
+
+func (*errorPageRouter) RenderErrorPage(ctx Ctx, err error) *vdom.VNode {
+	return vdom.H1(vdom.Text("error page"))
+}
+
+func TestErrorBoundary_NilFallbackRendersRouterErrorPage(t *testing.T) {
+	sess := newSession(nil, "", DefaultSessionConfig(), slog.Default())
+	sess.SetRouter(&errorPageRouter{})
+	sess.MountRoot(vdom.Func(func() *vdom.VNode {
+		return vdom.Div(vango.ErrorBoundary(nil, vdom.Func(func() *vdom.VNode {
+			panic("boom")
+		})))
+	}))
+
+	if got := treeText(boundaryInstance(t, sess).LastTree()); got != "error page" {
+		t.Errorf("boundary renders %q, want the router error page", got)
+	}
+}
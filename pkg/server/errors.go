@@ -48,6 +48,14 @@ var (
 
 	// ErrNoConnection is returned when attempting to send on a nil connection.
 	ErrNoConnection = errors.New("server: no connection")
+
+	// ErrHookCallTimeout is returned when a client hook does not answer a
+	// CallHook within SessionConfig.HookCallTimeout.
+	ErrHookCallTimeout = errors.New("server: hook call timed out")
+
+	// ErrHookCallUnsupported is returned by CallHook when the client's
+	// protocol version predates hook calls.
+	ErrHookCallUnsupported = errors.New("server: client does not support hook calls")
 )
 
 // SessionError wraps an error with session context for debugging.
@@ -105,6 +113,42 @@ func NewHandlerError(sessionID, hid, eventType string, panicVal any, stack []byt
 	}
 }
 
+// RenderError wraps a panic raised while rendering a component or running
+// one of its effects. It is the error an ErrorBoundary fallback receives.
+type RenderError struct {
+	SessionID string
+	Component string // Path of the component that panicked
+	Phase     string // "render" or "effect"
+	Panic     any
+	Stack     []byte
+}
+
+// Error returns the error message.
+func (e *RenderError) Error() string {
+	return fmt.Sprintf("server: %s panic in session %s, component %s: %v",
+		e.Phase, e.SessionID, e.Component, e.Panic)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *RenderError) Unwrap() error {
+	err, _ := e.Panic.(error)
+	return err
+}
+
+// HookCallError is returned by CallHook when the client reports that the
+// call failed: the hook was not found, has no such method, or the method
+// threw or rejected.
+type HookCallError struct {
+	HID     string
+	Method  string
+	Message string // Error message from the client
+}
+
+// Error returns the error message.
+func (e *HookCallError) Error() string {
+	return fmt.Sprintf("server: hook call %s on %s: %s", e.Method, e.HID, e.Message)
+}
+
 // ProtocolError represents an error in the binary protocol.
 type ProtocolError struct {
 	SessionID string
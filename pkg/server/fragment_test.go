@@ -0,0 +1,206 @@
+package server
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// readMessage reads frames from conn until r completes a message.
+func readMessage(t *testing.T, conn *websocket.Conn, r *protocol.Reassembler) (*protocol.Frame, int) {
+	t.Helper()
+	for frames := 1; ; frames++ {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		if len(msg) > protocol.FrameHeaderSize+protocol.MaxPayloadSize {
+			t.Fatalf("WebSocket message of %d bytes holds more than one frame", len(msg))
+		}
+		frame, err := r.DecodeFrame(msg)
+		if err != nil {
+			t.Fatalf("DecodeFrame failed: %v", err)
+		}
+		if frame != nil {
+			return frame, frames
+		}
+	}
+}
+
+func TestSession_SendPatchesFragmentsLargeFrames(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+
+	big := strings.Repeat("x", 3*protocol.MaxPayloadSize)
+	sess.SendPatches([]protocol.Patch{protocol.NewSetTextPatch("h1", big)})
+
+	frame, n := readMessage(t, clientConn, protocol.NewReassembler(0))
+	if n != 4 {
+		t.Errorf("message arrived in %d frames, want 4", n)
+	}
+	if frame.Type != protocol.FramePatches || frame.Flags.Has(protocol.FlagCompressed) {
+		t.Fatalf("frame type %v flags %#x", frame.Type, frame.Flags)
+	}
+	pf, err := protocol.DecodePatches(frame.Payload)
+	if err != nil {
+		t.Fatalf("DecodePatches failed: %v", err)
+	}
+	if len(pf.Patches) != 1 || pf.Patches[0].Value != big {
+		t.Fatal("reassembled patch does not match")
+	}
+}
+
+func TestSession_ReplayFragmentedHistory(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	r := protocol.NewReassembler(0)
+
+	big := strings.Repeat("y", protocol.MaxPayloadSize+100)
+	sess.sendPatchesWithURL(nil, []protocol.Patch{protocol.NewSetTextPatch("h1", big)})
+	readMessage(t, clientConn, r)
+
+	frames := sess.patchHistory.GetFrames(0, 1)
+	if len(frames) != 1 {
+		t.Fatalf("history frames = %d, want 1", len(frames))
+	}
+	sess.replayPatchFrames(frames)
+
+	frame, n := readMessage(t, clientConn, r)
+	if n != 2 {
+		t.Errorf("replay arrived in %d frames, want 2", n)
+	}
+	pf, err := protocol.DecodePatches(frame.Payload)
+	if err != nil || pf.Patches[0].Value != big {
+		t.Fatalf("replayed patch mismatch: %v", err)
+	}
+}
+
+func TestSession_SendResyncFullFragments(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+
+	text := strings.Repeat("row ", protocol.MaxPayloadSize/2)
+	sess.currentTree = &vdom.VNode{
+		Kind:  vdom.KindElement,
+		Tag:   "div",
+		Props: vdom.Props{},
+		Children: []*vdom.VNode{
+			{Kind: vdom.KindText, Text: text},
+		},
+	}
+
+	if err := sess.SendResyncFull(); err != nil {
+		t.Fatalf("SendResyncFull failed: %v", err)
+	}
+	frame, n := readMessage(t, clientConn, protocol.NewReassembler(0))
+	if n < 2 {
+		t.Errorf("ResyncFull arrived in %d frame, want fragments", n)
+	}
+	ct, data, err := protocol.DecodeControl(frame.Payload)
+	if err != nil || ct != protocol.ControlResyncFull {
+		t.Fatalf("DecodeControl = %v, %v", ct, err)
+	}
+	if html := data.(*protocol.ResyncResponse).HTML; !strings.Contains(html, text) {
+		t.Errorf("ResyncFull HTML is %d bytes, missing page text", len(html))
+	}
+}
+
+func TestSession_ReadLoopReassemblesInterleavedFragments(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	cfg := DefaultSessionConfig()
+	sess := newSession(serverConn, "", cfg, slog.Default())
+	sess.events = make(chan *Event, 1)
+	go sess.ReadLoop()
+
+	value := strings.Repeat("v", 2*protocol.MaxPayloadSize)
+	event := protocol.NewFrame(protocol.FrameEvent, protocol.EncodeEvent(&protocol.Event{
+		Seq:     1,
+		Type:    protocol.EventInput,
+		HID:     "h1",
+		Payload: value,
+	}))
+	frags := event.Fragments()
+	if len(frags) != 3 {
+		t.Fatalf("fragments = %d, want 3", len(frags))
+	}
+
+	ct, pp := protocol.NewPing(7)
+	ping := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, pp))
+
+	write := func(f *protocol.Frame) {
+		t.Helper()
+		_ = clientConn.SetWriteDeadline(time.Now().Add(time.Second))
+		if err := clientConn.WriteMessage(websocket.BinaryMessage, f.Encode()); err != nil {
+			t.Fatalf("WriteMessage failed: %v", err)
+		}
+	}
+
+	write(frags[0])
+	write(ping)
+
+	// The ping is answered while the event is still incomplete.
+	frame, _ := readMessage(t, clientConn, protocol.NewReassembler(0))
+	if gotType, _, err := protocol.DecodeControl(frame.Payload); err != nil || gotType != protocol.ControlPong {
+		t.Fatalf("reply = %v, %v; want pong", gotType, err)
+	}
+	select {
+	case e := <-sess.events:
+		t.Fatalf("event %+v queued before its final fragment", e)
+	default:
+	}
+
+	write(frags[1])
+	write(frags[2])
+
+	select {
+	case e := <-sess.events:
+		if got, _ := e.Payload.(string); e.HID != "h1" || got != value {
+			t.Fatalf("queued event hid=%q value=%d bytes", e.HID, len(got))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("reassembled event was not queued")
+	}
+}
+
+func TestSession_ReadLoopDropsOversizedMessage(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	cfg := DefaultSessionConfig()
+	cfg.MaxReassemblySize = protocol.MaxPayloadSize
+	sess := newSession(serverConn, "", cfg, slog.Default())
+	sess.events = make(chan *Event, 1)
+	go sess.ReadLoop()
+
+	big := protocol.NewFrame(protocol.FrameEvent, protocol.EncodeEvent(&protocol.Event{
+		Seq:     1,
+		Type:    protocol.EventInput,
+		HID:     "h1",
+		Payload: strings.Repeat("v", 2*protocol.MaxPayloadSize),
+	}))
+	small := protocol.NewFrame(protocol.FrameEvent, protocol.EncodeEvent(&protocol.Event{
+		Seq:  2,
+		Type: protocol.EventClick,
+		HID:  "h2",
+	}))
+
+	for _, f := range append(big.Fragments(), small) {
+		_ = clientConn.SetWriteDeadline(time.Now().Add(time.Second))
+		if err := clientConn.WriteMessage(websocket.BinaryMessage, f.Encode()); err != nil {
+			t.Fatalf("WriteMessage failed: %v", err)
+		}
+	}
+
+	select {
+	case e := <-sess.events:
+		if e.HID != "h2" {
+			t.Fatalf("queued event hid=%q, want only the small event", e.HID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event after the oversized message was not queued")
+	}
+}
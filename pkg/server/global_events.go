@@ -0,0 +1,197 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// globalIDPrefix starts the IDs of window and document subscriptions, which
+// the client sends as the HID of their events. Element HIDs start with "h".
+const globalIDPrefix = "g"
+
+// globalEventTypes are the window and document events the client can
+// subscribe to, by DOM event name.
+var globalEventTypes = map[string]protocol.EventType{
+	"click":            protocol.EventClick,
+	"focus":            protocol.EventFocus,
+	"blur":             protocol.EventBlur,
+	"keydown":          protocol.EventKeyDown,
+	"keyup":            protocol.EventKeyUp,
+	"scroll":           protocol.EventScroll,
+	"resize":           protocol.EventResize,
+	"visibilitychange": protocol.EventVisibilityChange,
+	"online":           protocol.EventOnline,
+	"offline":          protocol.EventOffline,
+	"beforeunload":     protocol.EventBeforeUnload,
+}
+
+// globalEvents implements vango.GlobalEvents for a session. Each listener
+// gets an ID; the client is told to subscribe with a GlobalListen control
+// message and sends the events back with that ID as their HID.
+type globalEvents struct {
+	s *Session
+
+	mu        sync.Mutex
+	nextID    uint64
+	listeners map[string]*vango.GlobalListener
+}
+
+// initGlobalEvents creates the session's window and document event
+// registry and stores it on the session owner, where vango.OnWindow and
+// vango.OnDocument find it.
+func (s *Session) initGlobalEvents() {
+	s.globalEvents = &globalEvents{s: s, listeners: make(map[string]*vango.GlobalListener)}
+	s.owner.SetValue(vango.GlobalEventsKey, s.globalEvents)
+}
+
+// Listen subscribes the client to l's event.
+func (g *globalEvents) Listen(l *vango.GlobalListener) func() {
+	event := strings.ToLower(l.Event)
+	if _, ok := globalEventTypes[event]; !ok {
+		g.s.logger.Warn("unsupported global event", "target", l.Target.String(), "event", l.Event)
+		return nil
+	}
+
+	g.mu.Lock()
+	g.nextID++
+	id := globalIDPrefix + strconv.FormatUint(g.nextID, 10)
+	g.listeners[id] = l
+	g.mu.Unlock()
+
+	g.send(protocol.NewGlobalListen(listenMessage(id, l)))
+	return func() { g.remove(id) }
+}
+
+// lookup returns the listener with the given ID, or nil.
+func (g *globalEvents) lookup(id string) *vango.GlobalListener {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.listeners[id]
+}
+
+// remove ends the subscription with the given ID.
+func (g *globalEvents) remove(id string) {
+	g.mu.Lock()
+	_, ok := g.listeners[id]
+	delete(g.listeners, id)
+	g.mu.Unlock()
+
+	if ok {
+		g.send(protocol.NewGlobalUnlisten(id))
+	}
+}
+
+// resend subscribes a reconnected client to every current listener. The
+// client drops its subscriptions at each handshake.
+func (g *globalEvents) resend() {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	messages := make([]*protocol.GlobalListen, 0, len(g.listeners))
+	for id, l := range g.listeners {
+		messages = append(messages, listenMessage(id, l))
+	}
+	g.mu.Unlock()
+
+	for _, gl := range messages {
+		g.send(protocol.NewGlobalListen(gl))
+	}
+}
+
+// send writes a control message to the client, if connected. While the
+// session is detached, resend catches the client up on resume.
+func (g *globalEvents) send(ct protocol.ControlType, payload any) {
+	s := g.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed.Load() || s.conn == nil {
+		return
+	}
+	if !s.versionLocked().SupportsControl(ct) {
+		// Clients older than 2.2 cannot subscribe; the handler never runs.
+		return
+	}
+
+	frame := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, payload))
+	if err := s.writeMessageLocked(frame.EncodeMessage()); err != nil {
+		s.logger.Error("global event subscription send error", "error", err)
+	}
+}
+
+// listenMessage builds the GlobalListen message for a listener. Client-side
+// modifiers, key filters and timing are read from its handler.
+func listenMessage(id string, l *vango.GlobalListener) *protocol.GlobalListen {
+	mh := l.Handler()
+	gl := &protocol.GlobalListen{
+		ID:       id,
+		Target:   protocol.GlobalTargetWindow,
+		Event:    strings.ToLower(l.Event),
+		Debounce: uint32(mh.Debounce.Milliseconds()),
+		Throttle: uint32(mh.Throttle.Milliseconds()),
+		KeyMods:  protocol.Modifiers(mh.KeyModifiers),
+	}
+	if l.Target == vango.TargetDocument {
+		gl.Target = protocol.GlobalTargetDocument
+	}
+	if mh.PreventDefault {
+		gl.Flags |= protocol.ListenPreventDefault
+	}
+	if mh.StopPropagation {
+		gl.Flags |= protocol.ListenStopPropagation
+	}
+	if mh.Once {
+		gl.Flags |= protocol.ListenOnce
+	}
+	if mh.Passive {
+		gl.Flags |= protocol.ListenPassive
+	}
+	if mh.Capture {
+		gl.Flags |= protocol.ListenCapture
+	}
+	if mh.KeyFilter != "" {
+		gl.Keys = []string{mh.KeyFilter}
+	} else if len(mh.KeysFilter) > 0 {
+		gl.Keys = mh.KeysFilter
+	}
+	return gl
+}
+
+// handleEventGlobal runs the handler of a window or document subscription
+// under the owner of the component that made it.
+func (s *Session) handleEventGlobal(event *Event, l *vango.GlobalListener) {
+	mh := l.Handler()
+	// The client filters keys too; check here so that a filtered event
+	// does not use up a Once subscription.
+	if data, ok := event.Payload.(*protocol.KeyboardEventData); ok && !keyMatchesFilter(data, mh) {
+		return
+	}
+
+	handler := wrapHandler(mh)
+	owner := l.Owner()
+	if owner == nil {
+		owner = s.owner
+	}
+
+	ctx := s.createEventContext(event)
+	vango.WithCtx(ctx, func() {
+		vango.WithOwner(owner, func() {
+			s.safeExecute(handler, event)
+
+			if mh.Once {
+				s.globalEvents.remove(event.HID)
+			}
+
+			// Commit render + effects after handler
+			s.flush()
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+// readGlobalControl reads frames until a GlobalListen or GlobalUnlisten
+// control message arrives.
+func readGlobalControl(t *testing.T, conn *websocket.Conn) (protocol.ControlType, any) {
+	t.Helper()
+	r := protocol.NewReassembler(0)
+	for {
+		frame, _ := readMessage(t, conn, r)
+		if frame.Type != protocol.FrameControl {
+			continue
+		}
+		ct, data, err := protocol.DecodeControl(frame.Payload)
+		if err != nil {
+			t.Fatalf("DecodeControl failed: %v", err)
+		}
+		if ct == protocol.ControlGlobalListen || ct == protocol.ControlGlobalUnlisten {
+			return ct, data
+		}
+	}
+}
+
+func TestSession_GlobalEventsSubscribeAndDeliver(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	s := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	t.Cleanup(s.Close)
+
+	var width int
+	var shortcuts int
+	s.MountRoot(FuncComponent(func() *vdom.VNode {
+		vango.OnWindow("resize", vango.Debounce(200*time.Millisecond, func(e vango.ResizeEvent) {
+			width = e.Width
+		}))
+		vango.OnDocument("keydown", vango.KeyWithModifiers("k", vango.Meta, func() {
+			shortcuts++
+		}), vango.PreventDefault, vango.Once)
+		return vdom.Div()
+	}))
+
+	_, data := readGlobalControl(t, clientConn)
+	resize := data.(*protocol.GlobalListen)
+	if resize.Target != protocol.GlobalTargetWindow || resize.Event != "resize" || resize.Debounce != 200 {
+		t.Fatalf("resize listen = %+v", resize)
+	}
+	_, data = readGlobalControl(t, clientConn)
+	keydown := data.(*protocol.GlobalListen)
+	want := &protocol.GlobalListen{
+		ID:      keydown.ID,
+		Target:  protocol.GlobalTargetDocument,
+		Event:   "keydown",
+		Flags:   protocol.ListenPreventDefault | protocol.ListenOnce,
+		Keys:    []string{"k"},
+		KeyMods: protocol.ModMeta,
+	}
+	if keydown.ID == resize.ID || !reflect.DeepEqual(keydown, want) {
+		t.Fatalf("keydown listen = %+v, want %+v", keydown, want)
+	}
+
+	s.handleEvent(&Event{
+		Type:    protocol.EventResize,
+		HID:     resize.ID,
+		Payload: &protocol.ResizeEventData{Width: 800, Height: 600},
+	})
+	if width != 800 {
+		t.Errorf("width = %d, want 800", width)
+	}
+
+	// Key filters still apply on the server.
+	s.handleEvent(&Event{
+		Type:    protocol.EventKeyDown,
+		HID:     keydown.ID,
+		Payload: &protocol.KeyboardEventData{Key: "j", Modifiers: protocol.ModMeta},
+	})
+	s.handleEvent(&Event{
+		Type:    protocol.EventKeyDown,
+		HID:     keydown.ID,
+		Payload: &protocol.KeyboardEventData{Key: "k", Modifiers: protocol.ModMeta},
+	})
+	if shortcuts != 1 {
+		t.Errorf("shortcuts = %d, want 1", shortcuts)
+	}
+
+	// Once ends the subscription after the first delivery.
+	ct, data := readGlobalControl(t, clientConn)
+	if ct != protocol.ControlGlobalUnlisten || data.(*protocol.GlobalUnlisten).ID != keydown.ID {
+		t.Fatalf("got %v %+v, want GlobalUnlisten for %s", ct, data, keydown.ID)
+	}
+	if s.globalEvents.lookup(keydown.ID) != nil {
+		t.Error("once listener still registered")
+	}
+}
+
+func TestSession_GlobalListenerEndsWithComponent(t *testing.T) {
+	s := NewMockSession()
+
+	show := vango.NewSignal(true)
+	var offline bool
+	child := FuncComponent(func() *vdom.VNode {
+		vango.OnWindow("offline", func() { offline = true })
+		return vdom.Span()
+	})
+	s.MountRoot(FuncComponent(func() *vdom.VNode {
+		vango.OnDocument("visibilitychange", func(state string) {
+			show.Set(state == "visible")
+		})
+		vango.OnWindow("unknown-event", func() {})
+		if !show.Get() {
+			return vdom.Div()
+		}
+		return vdom.Div(&vdom.VNode{Kind: vdom.KindComponent, Comp: child})
+	}))
+
+	if n := len(s.globalEvents.listeners); n != 2 {
+		t.Fatalf("listeners = %d, want 2 (unsupported events are not subscribed)", n)
+	}
+	var visibilityID, offlineID string
+	for id, l := range s.globalEvents.listeners {
+		if l.Event == "offline" {
+			offlineID = id
+		} else {
+			visibilityID = id
+		}
+	}
+
+	s.handleEvent(&Event{Type: protocol.EventOffline, HID: offlineID})
+	if !offline {
+		t.Fatal("offline handler did not run")
+	}
+
+	s.handleEvent(&Event{Type: protocol.EventVisibilityChange, HID: visibilityID, Payload: "hidden"})
+	if s.globalEvents.lookup(offlineID) != nil {
+		t.Error("listener remains after its component unmounted")
+	}
+	if s.globalEvents.lookup(visibilityID) == nil {
+		t.Error("root listener was removed")
+	}
+
+	// Events for ended subscriptions are dropped.
+	s.handleEvent(&Event{Type: protocol.EventOffline, HID: offlineID})
+}
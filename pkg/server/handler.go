@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/upload"
 	"github.com/vango-go/vango/pkg/vango"
 )
 
@@ -61,13 +62,48 @@ type KeyboardEvent struct {
 }
 
 // FormData represents submitted form data.
+// A field may carry several values (multi-selects, checkbox groups,
+// repeated inputs); they are kept in document order. A file input submits
+// only the names of its files; use an OnUpload input for their contents.
 type FormData struct {
-	values map[string]string
+	values map[string][]string
 }
 
-// Get returns the value for a form field.
+// Get returns the first value for a form field.
 func (f FormData) Get(key string) string {
-	return f.values[key]
+	if vals := f.values[key]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// GetAll returns all values for a form field.
+func (f FormData) GetAll(key string) []string {
+	vals, ok := f.values[key]
+	if !ok {
+		return nil
+	}
+	result := make([]string, len(vals))
+	copy(result, vals)
+	return result
+}
+
+// GetInt returns the first value for a form field parsed as an int.
+// It behaves like vango.FormData.GetInt.
+func (f FormData) GetInt(key string) int {
+	return vango.NewFormData(f.values).GetInt(key)
+}
+
+// GetFloat returns the first value for a form field parsed as a float64.
+// It behaves like vango.FormData.GetFloat.
+func (f FormData) GetFloat(key string) float64 {
+	return vango.NewFormData(f.values).GetFloat(key)
+}
+
+// GetBool returns the first value for a form field parsed as a bool.
+// It behaves like vango.FormData.GetBool.
+func (f FormData) GetBool(key string) bool {
+	return vango.NewFormData(f.values).GetBool(key)
 }
 
 // Has returns whether a form field exists.
@@ -76,11 +112,32 @@ func (f FormData) Has(key string) bool {
 	return ok
 }
 
+// Keys returns all form field names.
+func (f FormData) Keys() []string {
+	keys := make([]string, 0, len(f.values))
+	for k := range f.values {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 // All returns all form fields.
+// For fields with multiple values, only the first value is returned.
 func (f FormData) All() map[string]string {
 	result := make(map[string]string, len(f.values))
-	for k, v := range f.values {
-		result[k] = v
+	for k, vals := range f.values {
+		if len(vals) > 0 {
+			result[k] = vals[0]
+		}
+	}
+	return result
+}
+
+// Values returns a copy of all form fields with every submitted value.
+func (f FormData) Values() map[string][]string {
+	result := make(map[string][]string, len(f.values))
+	for k, vals := range f.values {
+		result[k] = append([]string(nil), vals...)
 	}
 	return result
 }
@@ -204,7 +261,7 @@ func wrapHandler(value any) Handler {
 	// Mouse event handler
 	case func(MouseEvent):
 		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.MouseEventData); ok {
+			if data, ok := mouseEventData(e.Payload); ok {
 				h(MouseEvent{
 					ClientX:  data.ClientX,
 					ClientY:  data.ClientY,
@@ -307,20 +364,25 @@ func wrapHandler(value any) Handler {
 	// Mouse event handler (vango.MouseEvent)
 	case func(vango.MouseEvent):
 		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.MouseEventData); ok {
-				h(vango.MouseEvent{
-					ClientX:  data.ClientX,
-					ClientY:  data.ClientY,
-					PageX:    data.PageX,
-					PageY:    data.PageY,
-					OffsetX:  data.OffsetX,
-					OffsetY:  data.OffsetY,
-					Button:   int(data.Button),
-					Buttons:  int(data.Buttons),
-					CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
-					ShiftKey: data.Modifiers.Has(protocol.ModShift),
-					AltKey:   data.Modifiers.Has(protocol.ModAlt),
-					MetaKey:  data.Modifiers.Has(protocol.ModMeta),
+			if data, ok := mouseEventData(e.Payload); ok {
+				h(toVangoMouseEvent(data))
+			}
+		}
+
+	// Pointer event handler (vango.PointerEvent)
+	case func(vango.PointerEvent):
+		return func(e *Event) {
+			if data, ok := e.Payload.(*protocol.PointerEventData); ok {
+				h(vango.PointerEvent{
+					MouseEvent:  toVangoMouseEvent(&data.MouseEventData),
+					PointerID:   data.PointerID,
+					PointerType: data.PointerType,
+					Pressure:    data.Pressure,
+					Width:       data.Width,
+					Height:      data.Height,
+					TiltX:       data.TiltX,
+					TiltY:       data.TiltY,
+					IsPrimary:   data.IsPrimary,
 				})
 			}
 		}
@@ -346,12 +408,7 @@ func wrapHandler(value any) Handler {
 	case func(vango.FormData):
 		return func(e *Event) {
 			if data, ok := e.Payload.(*protocol.SubmitEventData); ok {
-				// Convert single-value map to multi-value format
-				multiValues := make(map[string][]string, len(data.Fields))
-				for k, v := range data.Fields {
-					multiValues[k] = []string{v}
-				}
-				h(vango.NewFormData(multiValues))
+				h(vango.NewFormData(data.Fields))
 			}
 		}
 
@@ -497,18 +554,64 @@ func wrapHandler(value any) Handler {
 	// Drag event handler (vango.DragEvent)
 	case func(vango.DragEvent):
 		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.MouseEventData); ok {
-				h(vango.DragEvent{
-					ClientX:  data.ClientX,
-					ClientY:  data.ClientY,
-					CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
-					ShiftKey: data.Modifiers.Has(protocol.ModShift),
-					AltKey:   data.Modifiers.Has(protocol.ModAlt),
-					MetaKey:  data.Modifiers.Has(protocol.ModMeta),
+			data, ok := mouseEventData(e.Payload)
+			if !ok {
+				return
+			}
+			drag := vango.DragEvent{
+				ClientX:  data.ClientX,
+				ClientY:  data.ClientY,
+				CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
+				ShiftKey: data.Modifiers.Has(protocol.ModShift),
+				AltKey:   data.Modifiers.Has(protocol.ModAlt),
+				MetaKey:  data.Modifiers.Has(protocol.ModMeta),
+			}
+			if dt, ok := e.Payload.(*protocol.DragEventData); ok {
+				drag.Types = dt.Types
+				drag.Files = toVangoFiles(dt.Files)
+				for format, value := range dt.Data {
+					drag.SetData(format, value)
+				}
+			}
+			h(drag)
+		}
+
+	// Media event handler (vango.MediaEvent)
+	case func(vango.MediaEvent):
+		return func(e *Event) {
+			if data, ok := e.Payload.(*protocol.MediaEventData); ok {
+				h(vango.MediaEvent{
+					CurrentTime:  data.CurrentTime,
+					Duration:     data.Duration,
+					Paused:       data.Paused,
+					Ended:        data.Ended,
+					Volume:       data.Volume,
+					Muted:        data.Muted,
+					PlaybackRate: data.PlaybackRate,
 				})
 			}
 		}
 
+	// Clipboard event handler (vango.ClipboardEvent)
+	case func(vango.ClipboardEvent):
+		return func(e *Event) {
+			if data, ok := e.Payload.(*protocol.ClipboardEventData); ok {
+				h(vango.ClipboardEvent{
+					Text:  data.Text,
+					Types: data.Types,
+					Files: toVangoFiles(data.Files),
+				})
+			}
+		}
+
+	// Toggle event handler (vango.ToggleEvent)
+	case func(vango.ToggleEvent):
+		return func(e *Event) {
+			if data, ok := e.Payload.(*protocol.ToggleEventData); ok {
+				h(vango.ToggleEvent{Open: data.Open})
+			}
+		}
+
 	// Animation event handler (vango.AnimationEvent)
 	case func(vango.AnimationEvent):
 		return func(e *Event) {
@@ -533,6 +636,15 @@ func wrapHandler(value any) Handler {
 			}
 		}
 
+	// Live upload (el.OnUpload). The handler reports progress; the read loop
+	// finds the store and limits for a new upload in Session.uploads.
+	case upload.Live:
+		return func(e *Event) {
+			if p, ok := e.Payload.(upload.Progress); ok && h.OnProgress != nil {
+				h.OnProgress(p)
+			}
+		}
+
 	default:
 		// Unknown handler type
 		if isDevelopmentMode() {
@@ -543,7 +655,8 @@ func wrapHandler(value any) Handler {
 				"func(vango.FormData), func(vango.HookEvent), func(vango.ScrollEvent), "+
 				"func(vango.ResizeEvent), func(vango.TouchEvent), func(vango.NavigateEvent), "+
 				"func(vango.WheelEvent), func(vango.InputEvent), func(vango.DragEvent), "+
-				"func(vango.AnimationEvent), func(vango.TransitionEvent), "+
+				"func(vango.PointerEvent), func(vango.MediaEvent), func(vango.ClipboardEvent), "+
+				"func(vango.ToggleEvent), func(vango.AnimationEvent), func(vango.TransitionEvent), upload.Live, "+
 				"or vango.ModifiedHandler wrapping any of the above.", value)
 		}
 		// In production, warn and return no-op handler
@@ -553,6 +666,51 @@ func wrapHandler(value any) Handler {
 	}
 }
 
+// mouseEventData returns the mouse data of a mouse, pointer or drag event
+// payload.
+func mouseEventData(payload any) (*protocol.MouseEventData, bool) {
+	switch data := payload.(type) {
+	case *protocol.MouseEventData:
+		return data, true
+	case *protocol.PointerEventData:
+		return &data.MouseEventData, true
+	case *protocol.DragEventData:
+		return &data.MouseEventData, true
+	default:
+		return nil, false
+	}
+}
+
+// toVangoMouseEvent converts protocol mouse data to a vango.MouseEvent.
+func toVangoMouseEvent(data *protocol.MouseEventData) vango.MouseEvent {
+	return vango.MouseEvent{
+		ClientX:  data.ClientX,
+		ClientY:  data.ClientY,
+		PageX:    data.PageX,
+		PageY:    data.PageY,
+		OffsetX:  data.OffsetX,
+		OffsetY:  data.OffsetY,
+		Button:   int(data.Button),
+		Buttons:  int(data.Buttons),
+		CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
+		ShiftKey: data.Modifiers.Has(protocol.ModShift),
+		AltKey:   data.Modifiers.Has(protocol.ModAlt),
+		MetaKey:  data.Modifiers.Has(protocol.ModMeta),
+	}
+}
+
+// toVangoFiles converts protocol file metadata to vango.FileInfo.
+func toVangoFiles(files []protocol.FileInfo) []vango.FileInfo {
+	if len(files) == 0 {
+		return nil
+	}
+	result := make([]vango.FileInfo, len(files))
+	for i, f := range files {
+		result[i] = vango.FileInfo{Name: f.Name, Type: f.Type, Size: f.Size}
+	}
+	return result
+}
+
 // eventFromProtocol converts a protocol.Event to a server.Event.
 func eventFromProtocol(pe *protocol.Event, session *Session) *Event {
 	return &Event{
@@ -591,7 +749,11 @@ func isInputLike(et protocol.EventType) bool {
 func isMouseEvent(et protocol.EventType) bool {
 	switch et {
 	case protocol.EventMouseDown, protocol.EventMouseUp, protocol.EventMouseMove,
-		protocol.EventDragStart, protocol.EventDragEnd, protocol.EventDrop:
+		protocol.EventContextMenu,
+		protocol.EventDragStart, protocol.EventDragEnd, protocol.EventDrop,
+		protocol.EventDragEnter, protocol.EventDragOver, protocol.EventDragLeave, protocol.EventDrag,
+		protocol.EventPointerDown, protocol.EventPointerUp, protocol.EventPointerMove,
+		protocol.EventPointerEnter, protocol.EventPointerLeave, protocol.EventPointerCancel:
 		return true
 	default:
 		return false
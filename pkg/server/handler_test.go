@@ -1,10 +1,12 @@
 package server
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
 )
 
 func TestMouseEvent(t *testing.T) {
@@ -57,9 +59,9 @@ func TestKeyboardEvent(t *testing.T) {
 
 func TestFormDataGet(t *testing.T) {
 	fd := FormData{
-		values: map[string]string{
-			"name":  "John",
-			"email": "john@example.com",
+		values: map[string][]string{
+			"name":  {"John"},
+			"email": {"john@example.com"},
 		},
 	}
 
@@ -76,8 +78,8 @@ func TestFormDataGet(t *testing.T) {
 
 func TestFormDataHas(t *testing.T) {
 	fd := FormData{
-		values: map[string]string{
-			"present": "value",
+		values: map[string][]string{
+			"present": {"value"},
 		},
 	}
 
@@ -91,10 +93,10 @@ func TestFormDataHas(t *testing.T) {
 
 func TestFormDataAll(t *testing.T) {
 	fd := FormData{
-		values: map[string]string{
-			"a": "1",
-			"b": "2",
-			"c": "3",
+		values: map[string][]string{
+			"a": {"1"},
+			"b": {"2"},
+			"c": {"3"},
 		},
 	}
 
@@ -107,6 +109,48 @@ func TestFormDataAll(t *testing.T) {
 	}
 }
 
+func TestFormDataMultiValue(t *testing.T) {
+	fd := FormData{
+		values: map[string][]string{
+			"tags":  {"go", "web"},
+			"count": {"42"},
+			"price": {"9.5"},
+			"agree": {"on"},
+			"bad":   {"x"},
+		},
+	}
+
+	if got := fd.GetAll("tags"); !reflect.DeepEqual(got, []string{"go", "web"}) {
+		t.Errorf("GetAll(tags) = %v, want [go web]", got)
+	}
+	if fd.Get("tags") != "go" {
+		t.Errorf("Get(tags) = %q, want first value", fd.Get("tags"))
+	}
+	if fd.GetAll("missing") != nil {
+		t.Error("GetAll(missing) should return nil")
+	}
+
+	// Returned slices must not alias internal state.
+	fd.GetAll("tags")[0] = "mutated"
+	fd.Values()["tags"][0] = "mutated"
+	if fd.Get("tags") != "go" {
+		t.Error("GetAll/Values should return copies")
+	}
+
+	if fd.GetInt("count") != 42 || fd.GetInt("bad") != 0 {
+		t.Errorf("GetInt = %d/%d, want 42/0", fd.GetInt("count"), fd.GetInt("bad"))
+	}
+	if fd.GetFloat("price") != 9.5 {
+		t.Errorf("GetFloat(price) = %v, want 9.5", fd.GetFloat("price"))
+	}
+	if !fd.GetBool("agree") || fd.GetBool("missing") {
+		t.Error("GetBool should treat checkbox \"on\" as true and missing as false")
+	}
+	if len(fd.Values()) != 5 || len(fd.Keys()) != 5 {
+		t.Errorf("Values/Keys length = %d/%d, want 5", len(fd.Values()), len(fd.Keys()))
+	}
+}
+
 func TestFormDataEmpty(t *testing.T) {
 	fd := FormData{
 		values: nil,
@@ -349,6 +393,90 @@ func TestWrapHandlerWithString(t *testing.T) {
 	}
 }
 
+func TestWrapHandlerPointerEvent(t *testing.T) {
+	payload := &protocol.PointerEventData{
+		MouseEventData: protocol.MouseEventData{ClientX: 10, ClientY: 20, Buttons: 1, Modifiers: protocol.ModShift},
+		PointerID:      3,
+		PointerType:    "pen",
+		Pressure:       0.75,
+		IsPrimary:      true,
+	}
+
+	var got vango.PointerEvent
+	wrapHandler(func(e vango.PointerEvent) { got = e })(&Event{Type: protocol.EventPointerDown, Payload: payload})
+	if got.PointerID != 3 || got.PointerType != "pen" || got.Pressure != 0.75 || !got.IsPrimary {
+		t.Errorf("PointerEvent = %+v", got)
+	}
+	if got.ClientX != 10 || got.ClientY != 20 || got.Buttons != 1 || !got.ShiftKey {
+		t.Errorf("PointerEvent.MouseEvent = %+v", got.MouseEvent)
+	}
+
+	// Mouse handlers also receive pointer events.
+	var mouse vango.MouseEvent
+	wrapHandler(func(e vango.MouseEvent) { mouse = e })(&Event{Type: protocol.EventPointerDown, Payload: payload})
+	if mouse.ClientX != 10 || !mouse.ShiftKey {
+		t.Errorf("MouseEvent = %+v", mouse)
+	}
+}
+
+func TestWrapHandlerDragEvent(t *testing.T) {
+	var got vango.DragEvent
+	handler := wrapHandler(func(e vango.DragEvent) { got = e })
+	handler(&Event{Type: protocol.EventDrop, Payload: &protocol.DragEventData{
+		MouseEventData: protocol.MouseEventData{ClientX: 5, Modifiers: protocol.ModCtrl},
+		Types:          []string{"text/plain", "Files"},
+		Files:          []protocol.FileInfo{{Name: "a.png", Type: "image/png", Size: 42}},
+		Data:           map[string]string{"text/plain": "hello"},
+	}})
+
+	if got.ClientX != 5 || !got.CtrlKey {
+		t.Errorf("DragEvent = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Types, []string{"text/plain", "Files"}) {
+		t.Errorf("Types = %v", got.Types)
+	}
+	if !reflect.DeepEqual(got.Files, []vango.FileInfo{{Name: "a.png", Type: "image/png", Size: 42}}) {
+		t.Errorf("Files = %v", got.Files)
+	}
+	if got.GetData("text/plain") != "hello" {
+		t.Errorf("GetData(text/plain) = %q", got.GetData("text/plain"))
+	}
+}
+
+func TestWrapHandlerMediaClipboardToggle(t *testing.T) {
+	var media vango.MediaEvent
+	wrapHandler(func(e vango.MediaEvent) { media = e })(&Event{
+		Type:    protocol.EventTimeUpdate,
+		Payload: &protocol.MediaEventData{CurrentTime: 12.5, Duration: 60, Paused: true, Volume: 1, PlaybackRate: 2},
+	})
+	want := vango.MediaEvent{CurrentTime: 12.5, Duration: 60, Paused: true, Volume: 1, PlaybackRate: 2}
+	if media != want {
+		t.Errorf("MediaEvent = %+v, want %+v", media, want)
+	}
+
+	var clip vango.ClipboardEvent
+	wrapHandler(func(e vango.ClipboardEvent) { clip = e })(&Event{
+		Type: protocol.EventPaste,
+		Payload: &protocol.ClipboardEventData{
+			Text:  "pasted",
+			Types: []string{"text/plain"},
+			Files: []protocol.FileInfo{{Name: "b.txt", Size: 3}},
+		},
+	})
+	if clip.Text != "pasted" || len(clip.Types) != 1 || len(clip.Files) != 1 || clip.Files[0].Name != "b.txt" {
+		t.Errorf("ClipboardEvent = %+v", clip)
+	}
+
+	var toggle vango.ToggleEvent
+	wrapHandler(func(e vango.ToggleEvent) { toggle = e })(&Event{
+		Type:    protocol.EventToggle,
+		Payload: &protocol.ToggleEventData{Open: true},
+	})
+	if !toggle.Open {
+		t.Error("ToggleEvent.Open = false, want true")
+	}
+}
+
 func TestWrapHandlerUnknownType(t *testing.T) {
 	// Set production mode to test no-op behavior (dev mode panics)
 	t.Setenv("VANGO_ENV", "production")
@@ -441,6 +569,10 @@ func TestIsMouseEvent(t *testing.T) {
 		protocol.EventDragStart,
 		protocol.EventDragEnd,
 		protocol.EventDrop,
+		protocol.EventDragOver,
+		protocol.EventContextMenu,
+		protocol.EventPointerDown,
+		protocol.EventPointerMove,
 	}
 
 	for _, et := range mouseEvents {
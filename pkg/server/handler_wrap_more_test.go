@@ -66,7 +66,7 @@ func TestWrapHandler_EventPayloadConversions(t *testing.T) {
 			called = true
 			got = fd
 		})
-		h(&Event{Payload: &protocol.SubmitEventData{Fields: map[string]string{"name": "n"}}})
+		h(&Event{Payload: &protocol.SubmitEventData{Fields: map[string][]string{"name": {"n"}}}})
 
 		if !called {
 			t.Fatal("handler not called")
@@ -282,11 +282,14 @@ func TestWrapHandler_EventPayloadConversions(t *testing.T) {
 		}
 
 		wrapHandler(func(ev vango.FormData) { form = ev })(&Event{Payload: &protocol.SubmitEventData{
-			Fields: map[string]string{"name": "n"},
+			Fields: map[string][]string{"name": {"n"}, "tags": {"a", "b"}},
 		}})
 		if !form.Has("name") || form.Get("name") != "n" {
 			t.Fatalf("form=%v, want name=n", form.All())
 		}
+		if tags := form.GetAll("tags"); len(tags) != 2 || tags[0] != "a" || tags[1] != "b" {
+			t.Fatalf("tags=%v, want [a b]", tags)
+		}
 	})
 }
 
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+// Built-in hook call methods. The client answers them for any element with a
+// HID, so they work without a hook on the element.
+const (
+	// HookMethodRect returns the element's bounding client rect.
+	HookMethodRect = "vango:rect"
+
+	// HookMethodScroll returns the element's scroll position and size.
+	HookMethodScroll = "vango:scroll"
+
+	// HookMethodSelection returns the selected text within the element.
+	HookMethodSelection = "vango:selection"
+)
+
+// hookCalls tracks the calls waiting for a HookResult from the client.
+type hookCalls struct {
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *protocol.HookResult
+}
+
+// add registers a new call and returns its ID and result channel.
+func (h *hookCalls) add() (uint64, chan *protocol.HookResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.pending == nil {
+		h.pending = make(map[uint64]chan *protocol.HookResult)
+	}
+	h.nextID++
+	ch := make(chan *protocol.HookResult, 1)
+	h.pending[h.nextID] = ch
+	return h.nextID, ch
+}
+
+// remove forgets a call. Results that arrive later are dropped.
+func (h *hookCalls) remove(id uint64) {
+	h.mu.Lock()
+	delete(h.pending, id)
+	h.mu.Unlock()
+}
+
+// deliver passes a result to the waiting call. It reports false if no call
+// with that ID is waiting.
+func (h *hookCalls) deliver(hr *protocol.HookResult) bool {
+	h.mu.Lock()
+	ch, ok := h.pending[hr.ID]
+	delete(h.pending, hr.ID)
+	h.mu.Unlock()
+
+	if ok {
+		ch <- hr
+	}
+	return ok
+}
+
+// CallHook calls a method on the client hook instance at hid and waits for
+// its result. The hook's method receives the element and args; its return
+// value, or the value its promise resolves to, is the result. Results are
+// hook values: nil, bool, int64, float64, string, []any or map[string]any.
+//
+// The call fails with a *HookCallError if the client has no hook at hid,
+// the hook has no such method or the method throws. It fails with
+// ErrHookCallTimeout after SessionConfig.HookCallTimeout, with ctx.Err()
+// if ctx ends first, and with ErrSessionClosed if the session closes.
+//
+// CallHook blocks. It is safe to call on the session loop, since results
+// are read on the connection's read loop, but the session handles no
+// events while it waits. Handlers should usually use Ctx.CallHookAsync.
+func (s *Session) CallHook(ctx context.Context, hid, method string, args map[string]any) (any, error) {
+	id, ch := s.hookCalls.add()
+	defer s.hookCalls.remove(id)
+
+	done, err := s.sendHookCall(id, hid, method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := s.config.HookCallTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case hr := <-ch:
+		if hr.Error != "" {
+			return nil, &HookCallError{HID: hid, Method: method, Message: hr.Error}
+		}
+		return hr.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, ErrHookCallTimeout
+	case <-done:
+		return nil, ErrSessionClosed
+	}
+}
+
+// sendHookCall writes a HookCall control message. It returns the session's
+// done channel, read under the same lock as the connection.
+func (s *Session) sendHookCall(id uint64, hid, method string, args map[string]any) (<-chan struct{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed.Load() {
+		return nil, ErrSessionClosed
+	}
+	if s.conn == nil {
+		return nil, ErrNoConnection
+	}
+
+	ct, hc := protocol.NewHookCall(id, hid, method, args)
+	if !s.versionLocked().SupportsControl(ct) {
+		return nil, ErrHookCallUnsupported
+	}
+	frame := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, hc))
+
+	if err := s.writeMessageLocked(frame.EncodeMessage()); err != nil {
+		s.logger.Error("hook call send error", "error", err)
+		return nil, err
+	}
+	return s.done, nil
+}
+
+// handleHookResult delivers a HookResult from the client to its call.
+// Called from the read loop.
+func (s *Session) handleHookResult(hr *protocol.HookResult) {
+	if !s.hookCalls.deliver(hr) {
+		s.logger.Debug("hook result for unknown call dropped", "id", hr.ID)
+	}
+}
+
+// DOMRect is an element's bounding client rect, in CSS pixels relative to
+// the viewport.
+type DOMRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
+// ScrollPosition is an element's scroll offset and scrollable size.
+type ScrollPosition struct {
+	Top    float64 `json:"top"`
+	Left   float64 `json:"left"`
+	Width  float64 `json:"width"`  // scrollWidth
+	Height float64 `json:"height"` // scrollHeight
+}
+
+// TextSelection is the selected text within an element. Start and End are
+// character offsets into the element's value or text content; they are
+// equal when nothing is selected.
+type TextSelection struct {
+	Text      string `json:"text"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	Direction string `json:"direction"` // "forward", "backward" or "none"
+}
+
+// ElementRect returns the bounding client rect of the element at hid.
+func ElementRect(c Ctx, hid string) (DOMRect, error) {
+	var rect DOMRect
+	err := callHookInto(c, hid, HookMethodRect, &rect)
+	return rect, err
+}
+
+// ElementScroll returns the scroll position of the element at hid.
+func ElementScroll(c Ctx, hid string) (ScrollPosition, error) {
+	var pos ScrollPosition
+	err := callHookInto(c, hid, HookMethodScroll, &pos)
+	return pos, err
+}
+
+// ElementSelection returns the text selection within the element at hid:
+// the selected range of an input or textarea, or the part of the page
+// selection inside any other element.
+func ElementSelection(c Ctx, hid string) (TextSelection, error) {
+	var sel TextSelection
+	err := callHookInto(c, hid, HookMethodSelection, &sel)
+	return sel, err
+}
+
+// callHookInto calls a built-in hook method and decodes its result into v.
+func callHookInto(c Ctx, hid, method string, v any) error {
+	result, err := c.CallHook(hid, method, nil)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("server: decode %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+// readHookCall reads frames until a HookCall control message arrives.
+func readHookCall(t *testing.T, conn *websocket.Conn) *protocol.HookCall {
+	t.Helper()
+	r := protocol.NewReassembler(0)
+	for {
+		frame, _ := readMessage(t, conn, r)
+		if frame.Type != protocol.FrameControl {
+			continue
+		}
+		ct, data, err := protocol.DecodeControl(frame.Payload)
+		if err != nil {
+			t.Fatalf("DecodeControl failed: %v", err)
+		}
+		if ct == protocol.ControlHookCall {
+			return data.(*protocol.HookCall)
+		}
+	}
+}
+
+// writeHookResult answers a hook call as the client would.
+func writeHookResult(t *testing.T, conn *websocket.Conn, id uint64, result any, errMsg string) {
+	t.Helper()
+	payload := protocol.EncodeControl(protocol.NewHookResult(id, result, errMsg))
+	frame := protocol.NewFrame(protocol.FrameControl, payload)
+	if err := conn.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+		t.Fatalf("write hook result: %v", err)
+	}
+}
+
+type hookCallReturn struct {
+	result any
+	err    error
+}
+
+func newHookCallSession(t *testing.T, config *SessionConfig) (*websocket.Conn, *Session) {
+	t.Helper()
+	clientConn, serverConn := newWebSocketPair(t)
+	s := newSession(serverConn, "", config, slog.Default())
+	t.Cleanup(s.Close)
+	go s.ReadLoop()
+	return clientConn, s
+}
+
+func TestSession_CallHook(t *testing.T) {
+	clientConn, s := newHookCallSession(t, DefaultSessionConfig())
+	ctx := NewTestContext(s)
+
+	returned := make(chan hookCallReturn, 1)
+	go func() {
+		result, err := ctx.CallHook("h4", "getSelection", map[string]any{"trim": true})
+		returned <- hookCallReturn{result, err}
+	}()
+
+	call := readHookCall(t, clientConn)
+	if call.HID != "h4" || call.Method != "getSelection" || call.Args["trim"] != true {
+		t.Fatalf("call = %+v", call)
+	}
+	// Results for calls that are not waiting are dropped.
+	writeHookResult(t, clientConn, call.ID+100, "stale", "")
+	writeHookResult(t, clientConn, call.ID, "hello", "")
+
+	got := <-returned
+	if got.err != nil || got.result != "hello" {
+		t.Fatalf("CallHook = %v, %v; want hello, nil", got.result, got.err)
+	}
+
+	go func() {
+		result, err := ctx.CallHook("h4", "missing", nil)
+		returned <- hookCallReturn{result, err}
+	}()
+	call = readHookCall(t, clientConn)
+	writeHookResult(t, clientConn, call.ID, nil, "hook has no method missing")
+
+	got = <-returned
+	var hookErr *HookCallError
+	if !errors.As(got.err, &hookErr) || hookErr.Method != "missing" || hookErr.Message != "hook has no method missing" {
+		t.Fatalf("err = %v, want HookCallError", got.err)
+	}
+}
+
+func TestSession_CallHookTimeoutAndCancel(t *testing.T) {
+	config := DefaultSessionConfig()
+	config.HookCallTimeout = 20 * time.Millisecond
+	_, s := newHookCallSession(t, config)
+
+	if _, err := NewTestContext(s).CallHook("h1", "slow", nil); !errors.Is(err, ErrHookCallTimeout) {
+		t.Errorf("err = %v, want ErrHookCallTimeout", err)
+	}
+
+	stdCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx := NewTestContext(s).WithStdContext(stdCtx)
+	if _, err := ctx.CallHook("h1", "slow", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+
+	if n := len(s.hookCalls.pending); n != 0 {
+		t.Errorf("pending calls = %d, want 0", n)
+	}
+
+	if _, err := NewTestContext(NewMockSession()).CallHook("h1", "slow", nil); !errors.Is(err, ErrNoConnection) {
+		t.Errorf("err = %v, want ErrNoConnection", err)
+	}
+}
+
+func TestSession_CallHookAsync(t *testing.T) {
+	clientConn, s := newHookCallSession(t, DefaultSessionConfig())
+	// The callback is dispatched to the session loop.
+	go s.EventLoop()
+
+	returned := make(chan hookCallReturn, 1)
+	NewTestContext(s).CallHookAsync("h2", HookMethodScroll, nil, func(result any, err error) {
+		returned <- hookCallReturn{result, err}
+	})
+
+	call := readHookCall(t, clientConn)
+	writeHookResult(t, clientConn, call.ID, map[string]any{"top": int64(120)}, "")
+
+	select {
+	case got := <-returned:
+		if got.err != nil || got.result.(map[string]any)["top"] != int64(120) {
+			t.Fatalf("callback got %v, %v", got.result, got.err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback did not run")
+	}
+}
+
+func TestElementRect(t *testing.T) {
+	clientConn, s := newHookCallSession(t, DefaultSessionConfig())
+
+	type rectReturn struct {
+		rect DOMRect
+		err  error
+	}
+	returned := make(chan rectReturn, 1)
+	go func() {
+		rect, err := ElementRect(NewTestContext(s), "h9")
+		returned <- rectReturn{rect, err}
+	}()
+
+	call := readHookCall(t, clientConn)
+	if call.Method != HookMethodRect {
+		t.Fatalf("method = %q, want %q", call.Method, HookMethodRect)
+	}
+	writeHookResult(t, clientConn, call.ID, map[string]any{
+		"x": 10.5, "y": int64(20), "width": int64(300), "height": 40.25,
+		"top": int64(20), "right": 310.5, "bottom": 60.25, "left": 10.5,
+	}, "")
+
+	got := <-returned
+	want := DOMRect{X: 10.5, Y: 20, Width: 300, Height: 40.25, Top: 20, Right: 310.5, Bottom: 60.25, Left: 10.5}
+	if got.err != nil || got.rect != want {
+		t.Fatalf("ElementRect = %+v, %v; want %+v", got.rect, got.err, want)
+	}
+}
+
+func TestSession_CallHookLargeArgs(t *testing.T) {
+	clientConn, s := newHookCallSession(t, DefaultSessionConfig())
+	ctx := NewTestContext(s)
+
+	text := strings.Repeat("x", 2*protocol.MaxPayloadSize)
+	returned := make(chan hookCallReturn, 1)
+	go func() {
+		result, err := ctx.CallHook("h4", "setText", map[string]any{"text": text})
+		returned <- hookCallReturn{result, err}
+	}()
+
+	// readHookCall fails if a WebSocket message holds more than one frame.
+	call := readHookCall(t, clientConn)
+	if call.Args["text"] != text {
+		t.Fatalf("args text has %d bytes, want %d", len(call.Args["text"].(string)), len(text))
+	}
+	writeHookResult(t, clientConn, call.ID, true, "")
+	if got := <-returned; got.err != nil {
+		t.Fatalf("CallHook error: %v", got.err)
+	}
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+// HTTP fallback transport.
+//
+// Clients behind proxies that block WebSocket upgrades connect with
+//
+//	GET  /_vango/sse?path=/current/page     (text/event-stream)
+//	POST /_vango/sse?conn=<id>              (application/octet-stream)
+//
+// The GET opens a Server-Sent Events stream. Its first event names the
+// connection:
+//
+//	event: conn
+//	data: <id>
+//
+// after which every server frame arrives as one base64 message event:
+//
+//	data: <base64 frame>
+//
+// and a graceful close as "event: close". The client sends frames, starting
+// with the ClientHello, by POSTing one or more encoded frames back to back
+// in a single body. The frames are exactly those carried over WebSocket, so
+// sequence numbers, acks, resync and session resume are unchanged.
+
+// errFallbackClosed is returned by reads and writes on a closed fallback
+// connection.
+var errFallbackClosed = errors.New("server: http fallback connection closed")
+
+// fallbackInboundQueue is the number of client frames buffered per
+// connection before POSTs block.
+const fallbackInboundQueue = 64
+
+// fallbackConns tracks open fallback connections by ID so POSTs can find
+// the stream they belong to.
+type fallbackConns struct {
+	mu    sync.Mutex
+	conns map[string]*fallbackConn
+}
+
+func (fc *fallbackConns) add(c *fallbackConn) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.conns == nil {
+		fc.conns = make(map[string]*fallbackConn)
+	}
+	fc.conns[c.id] = c
+}
+
+func (fc *fallbackConns) get(id string) *fallbackConn {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.conns[id]
+}
+
+func (fc *fallbackConns) remove(id string) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	delete(fc.conns, id)
+}
+
+// fallbackConn is one SSE stream plus the POSTs feeding it. It implements
+// Conn.
+type fallbackConn struct {
+	id string
+	w  http.ResponseWriter
+	rc *http.ResponseController
+
+	// writeMu serializes writes to w and guards writeDeadline. The GET
+	// handler takes it once after close so no write outlives the handler.
+	writeMu       sync.Mutex
+	writeDeadline time.Time
+
+	readMu       sync.Mutex
+	readDeadline time.Time
+
+	inbound   chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+	onClose   func()
+}
+
+func newFallbackConn(w http.ResponseWriter, onClose func(*fallbackConn)) *fallbackConn {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	c := &fallbackConn{
+		id:      hex.EncodeToString(b),
+		w:       w,
+		rc:      http.NewResponseController(w),
+		inbound: make(chan []byte, fallbackInboundQueue),
+		closed:  make(chan struct{}),
+	}
+	c.onClose = func() { onClose(c) }
+	return c
+}
+
+// ReadMessage returns the next frame POSTed by the client.
+func (c *fallbackConn) ReadMessage() (int, []byte, error) {
+	c.readMu.Lock()
+	deadline := c.readDeadline
+	c.readMu.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case msg := <-c.inbound:
+		return websocket.BinaryMessage, msg, nil
+	case <-c.closed:
+		return 0, nil, errFallbackClosed
+	case <-timeout:
+		return 0, nil, os.ErrDeadlineExceeded
+	}
+}
+
+// WriteMessage sends data to the client as one SSE message event.
+func (c *fallbackConn) WriteMessage(messageType int, data []byte) error {
+	return c.writeEvent("", base64.StdEncoding.EncodeToString(data))
+}
+
+// WriteControl forwards a close frame as an SSE close event so the client
+// knows not to reconnect. Other control messages have no SSE equivalent.
+func (c *fallbackConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	if messageType != websocket.CloseMessage {
+		return nil
+	}
+	return c.writeEvent("close", "")
+}
+
+// SetReadDeadline sets the deadline for the next ReadMessage.
+func (c *fallbackConn) SetReadDeadline(t time.Time) error {
+	c.readMu.Lock()
+	c.readDeadline = t
+	c.readMu.Unlock()
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for subsequent writes.
+func (c *fallbackConn) SetWriteDeadline(t time.Time) error {
+	c.writeMu.Lock()
+	c.writeDeadline = t
+	c.writeMu.Unlock()
+	return nil
+}
+
+// Close ends the SSE stream. Pending and later POSTs fail with 410 Gone.
+func (c *fallbackConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.onClose()
+	})
+	return nil
+}
+
+func (c *fallbackConn) writeEvent(event, data string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	select {
+	case <-c.closed:
+		return errFallbackClosed
+	default:
+	}
+
+	// Not every ResponseWriter supports deadlines; the write still works.
+	_ = c.rc.SetWriteDeadline(c.writeDeadline)
+
+	var buf []byte
+	if event != "" {
+		buf = append(buf, "event: "...)
+		buf = append(buf, event...)
+		buf = append(buf, '\n')
+	}
+	buf = append(buf, "data: "...)
+	buf = append(buf, data...)
+	buf = append(buf, "\n\n"...)
+
+	if _, err := c.w.Write(buf); err != nil {
+		go c.Close()
+		return err
+	}
+	if err := c.rc.Flush(); err != nil {
+		go c.Close()
+		return err
+	}
+	return nil
+}
+
+// deliver queues a client frame for ReadMessage, waiting while the queue is
+// full.
+func (c *fallbackConn) deliver(r *http.Request, frame []byte) error {
+	select {
+	case c.inbound <- frame:
+		return nil
+	case <-c.closed:
+		return errFallbackClosed
+	case <-r.Context().Done():
+		return r.Context().Err()
+	}
+}
+
+// HandleHTTPFallback serves the SSE + POST transport used by clients that
+// cannot open a WebSocket. GET opens the event stream and runs the
+// handshake; POST delivers client frames to an open stream.
+func (s *Server) HandleHTTPFallback(w http.ResponseWriter, r *http.Request) {
+	if !s.config.HTTPFallback {
+		http.NotFound(w, r)
+		return
+	}
+	if s.config.CheckOrigin != nil && !s.config.CheckOrigin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.serveEventStream(w, r)
+	case http.MethodPost:
+		s.receiveFallbackFrames(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveEventStream opens an SSE stream and serves the session on it until
+// either side closes.
+func (s *Server) serveEventStream(w http.ResponseWriter, r *http.Request) {
+	conn := newFallbackConn(w, func(c *fallbackConn) {
+		s.fallbackConns.remove(c.id)
+	})
+
+	// The stream outlives the HTTP server's request timeouts; the session's
+	// own read and write deadlines apply instead.
+	_ = conn.rc.SetReadDeadline(time.Time{})
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+
+	s.fallbackConns.add(conn)
+	conn.SetWriteDeadline(time.Now().Add(s.config.SessionConfig.WriteTimeout))
+	if err := conn.writeEvent("conn", conn.id); err != nil {
+		s.logger.Error("http fallback open failed", "error", err)
+		conn.Close()
+		return
+	}
+
+	go func() {
+		select {
+		case <-r.Context().Done():
+			conn.Close()
+		case <-conn.closed:
+		}
+	}()
+
+	s.serveConn(conn, r)
+
+	<-conn.closed
+	// Wait out any write in progress; later writes see the closed channel.
+	conn.writeMu.Lock()
+	conn.writeMu.Unlock()
+}
+
+// receiveFallbackFrames handles a POST of back-to-back encoded frames.
+func (s *Server) receiveFallbackFrames(w http.ResponseWriter, r *http.Request) {
+	conn := s.fallbackConns.get(r.URL.Query().Get("conn"))
+	if conn == nil {
+		http.Error(w, "Unknown connection", http.StatusGone)
+		return
+	}
+
+	limit := s.config.SessionConfig.MaxMessageSize + int64(s.config.SessionConfig.MaxReassemblySize)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		http.Error(w, "Request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	frames, err := protocol.SplitFrames(body)
+	if err != nil {
+		http.Error(w, "Invalid frame", http.StatusBadRequest)
+		return
+	}
+
+	for _, frame := range frames {
+		if err := conn.deliver(r, frame); err != nil {
+			http.Error(w, "Connection closed", http.StatusGone)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+type sseEvent struct {
+	event string
+	data  string
+}
+
+// sseClient is a minimal fallback transport client: one event stream plus
+// POSTs for outgoing frames.
+type sseClient struct {
+	t      *testing.T
+	base   string
+	id     string
+	cancel context.CancelFunc
+	events chan sseEvent
+}
+
+func openSSE(t *testing.T, base string) *sseClient {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, base+"/_vango/sse?path=/", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("GET /_vango/sse failed: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		cancel()
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	c := &sseClient{t: t, base: base, cancel: cancel, events: make(chan sseEvent, 16)}
+	t.Cleanup(c.close)
+	go func() {
+		defer resp.Body.Close()
+		defer close(c.events)
+		r := bufio.NewReader(resp.Body)
+		var ev sseEvent
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				c.events <- ev
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "event: "):
+				ev.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	open := c.next()
+	if open.event != "conn" || open.data == "" {
+		t.Fatalf("first event = %+v, want conn with connection id", open)
+	}
+	c.id = open.data
+	return c
+}
+
+func (c *sseClient) close() { c.cancel() }
+
+func (c *sseClient) next() sseEvent {
+	c.t.Helper()
+	select {
+	case ev, ok := <-c.events:
+		if !ok {
+			c.t.Fatal("event stream ended")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		c.t.Fatal("timed out waiting for event")
+	}
+	return sseEvent{}
+}
+
+func (c *sseClient) post(frames ...*protocol.Frame) int {
+	c.t.Helper()
+	var body bytes.Buffer
+	for _, f := range frames {
+		body.Write(f.Encode())
+	}
+	resp, err := http.Post(c.base+"/_vango/sse?conn="+c.id, "application/octet-stream", &body)
+	if err != nil {
+		c.t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func (c *sseClient) readFrame() *protocol.Frame {
+	c.t.Helper()
+	ev := c.next()
+	if ev.event != "" {
+		c.t.Fatalf("event = %q, want a frame message", ev.event)
+	}
+	data, err := base64.StdEncoding.DecodeString(ev.data)
+	if err != nil {
+		c.t.Fatalf("base64 decode failed: %v", err)
+	}
+	frame, err := protocol.DecodeFrame(data)
+	if err != nil {
+		c.t.Fatalf("DecodeFrame failed: %v", err)
+	}
+	return frame
+}
+
+func (c *sseClient) handshake(hello *protocol.ClientHello) *protocol.ServerHello {
+	c.t.Helper()
+	if code := c.post(protocol.NewFrame(protocol.FrameHandshake, protocol.EncodeClientHello(hello))); code != http.StatusNoContent {
+		c.t.Fatalf("POST hello status = %d", code)
+	}
+	frame := c.readFrame()
+	if frame.Type != protocol.FrameHandshake {
+		c.t.Fatalf("frame type = %v, want handshake", frame.Type)
+	}
+	sh, err := protocol.DecodeServerHello(frame.Payload)
+	if err != nil {
+		c.t.Fatalf("DecodeServerHello failed: %v", err)
+	}
+	return sh
+}
+
+func newFallbackTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	cfg := DefaultServerConfig().WithDevMode()
+	cfg.HTTPFallback = true
+	s := New(cfg)
+	s.SetRootComponent(func() Component {
+		return staticComponent{node: vdom.Div(vdom.Text("hello"))}
+	})
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { s.Sessions().Shutdown() })
+	return s, ts.URL
+}
+
+func pingFrame(ts uint64) *protocol.Frame {
+	ct, pp := protocol.NewPing(ts)
+	return protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, pp))
+}
+
+func TestHTTPFallback_HandshakeAndControl(t *testing.T) {
+	s, base := newFallbackTestServer(t)
+	c := openSSE(t, base)
+
+	sh := c.handshake(protocol.NewClientHello(""))
+	if sh.Status != protocol.HandshakeOK || sh.SessionID == "" {
+		t.Fatalf("ServerHello = %+v, want OK with session id", sh)
+	}
+	sess := s.Sessions().Get(sh.SessionID)
+	if sess == nil {
+		t.Fatal("session not registered")
+	}
+	if _, ok := sess.Conn().(*fallbackConn); !ok {
+		t.Fatalf("session conn = %T, want *fallbackConn", sess.Conn())
+	}
+
+	// Two frames batched into one POST are read in order.
+	if code := c.post(pingFrame(1), pingFrame(2)); code != http.StatusNoContent {
+		t.Fatalf("POST status = %d", code)
+	}
+	for _, want := range []uint64{1, 2} {
+		frame := c.readFrame()
+		ct, data, err := protocol.DecodeControl(frame.Payload)
+		if err != nil || ct != protocol.ControlPong || data.(*protocol.PingPong).Timestamp != want {
+			t.Fatalf("reply = %v %+v %v, want pong %d", ct, data, err, want)
+		}
+	}
+
+	// Closing the session ends the stream with a close event.
+	sess.Close()
+	if ev := c.next(); ev.event != "close" {
+		t.Fatalf("event = %+v, want close", ev)
+	}
+	if code := c.post(pingFrame(3)); code != http.StatusGone {
+		t.Errorf("POST after close status = %d, want 410", code)
+	}
+}
+
+func TestHTTPFallback_ResumeAfterStreamDrops(t *testing.T) {
+	s, base := newFallbackTestServer(t)
+	first := openSSE(t, base)
+	sh := first.handshake(protocol.NewClientHello(""))
+	sess := s.Sessions().Get(sh.SessionID)
+
+	first.close()
+	deadline := time.Now().Add(2 * time.Second)
+	for !sess.IsDetached() {
+		if time.Now().After(deadline) {
+			t.Fatal("session did not detach after the stream closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	second := openSSE(t, base)
+	hello := protocol.NewClientHello("")
+	hello.SessionID = sh.SessionID
+	resumed := second.handshake(hello)
+	if resumed.Status != protocol.HandshakeOK || resumed.SessionID != sh.SessionID {
+		t.Fatalf("resume ServerHello = %+v, want OK for %s", resumed, sh.SessionID)
+	}
+
+	// The resumed session resyncs the page over the new stream.
+	frame := second.readFrame()
+	if ct, _, err := protocol.DecodeControl(frame.Payload); err != nil || ct != protocol.ControlResyncFull {
+		t.Fatalf("frame after resume = %v, %v; want ResyncFull", ct, err)
+	}
+	if sess.IsDetached() {
+		t.Error("session still detached after resume")
+	}
+}
+
+func TestHTTPFallback_RejectsBadRequests(t *testing.T) {
+	_, base := newFallbackTestServer(t)
+
+	resp, err := http.Post(base+"/_vango/sse?conn=unknown", "application/octet-stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGone {
+		t.Errorf("unknown conn status = %d, want 410", resp.StatusCode)
+	}
+
+	c := openSSE(t, base)
+	resp, err = http.Post(base+"/_vango/sse?conn="+c.id, "application/octet-stream", strings.NewReader("\x01\x00\x00\x10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("truncated frame status = %d, want 400", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, base+"/_vango/sse", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("PUT status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestHTTPFallback_DisabledByDefault(t *testing.T) {
+	s := New(DefaultServerConfig().WithDevMode())
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_vango/sse?path=/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 when the fallback is disabled", rec.Code)
+	}
+}
+
+func TestHTTPFallback_RejectsCrossOrigin(t *testing.T) {
+	cfg := DefaultServerConfig()
+	cfg.HTTPFallback = true
+	s := New(cfg)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		req := httptest.NewRequest(method, "http://example.com/_vango/sse?path=/", nil)
+		req.Header.Set("Origin", "https://evil.example")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("cross-origin %s status = %d, want 403", method, rec.Code)
+		}
+	}
+}
+
+func TestHTTPFallback_HandshakeChecksCSRF(t *testing.T) {
+	cfg := DefaultServerConfig().WithDevMode()
+	cfg.HTTPFallback = true
+	cfg.CSRFSecret = []byte("test-secret-key-32-bytes-long!!!")
+	s := New(cfg)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { s.Sessions().Shutdown() })
+
+	c := openSSE(t, ts.URL)
+	if sh := c.handshake(protocol.NewClientHello("")); sh.Status != protocol.HandshakeInvalidCSRF {
+		t.Errorf("handshake without token: status = %v, want InvalidCSRF", sh.Status)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// inspectTimeout bounds how long Inspect waits for a busy session loop.
+const inspectTimeout = 2 * time.Second
+
+var errInspectTimeout = errors.New("server: session did not respond to inspect")
+
+// Inspect returns a snapshot of the session's reactive graph: its owners and
+// components, their signals, memos and effects, the session's shared signals
+// and the global signals it subscribes to, plus the subscription edges
+// between them. Component owners are reported with Kind NodeComponent, the
+// component type as Label and the root element's HID.
+//
+// The snapshot is taken on the session loop, so it does not race with
+// rendering; Inspect blocks until the loop gets to it.
+func (s *Session) Inspect() (*vango.Graph, error) {
+	result := make(chan *vango.Graph, 1)
+	if !s.dispatchWithResult(func() { result <- s.inspect() }) {
+		return nil, ErrSessionClosed
+	}
+
+	select {
+	case g := <-result:
+		return g, nil
+	case <-s.done:
+		return nil, ErrSessionClosed
+	case <-time.After(inspectTimeout):
+		return nil, errInspectTimeout
+	}
+}
+
+// inspect builds the graph. It must run on the session loop.
+func (s *Session) inspect() *vango.Graph {
+	g := vango.InspectOwner(s.owner)
+
+	s.stateMu.Lock()
+	for comp := range s.allComponents {
+		if comp.Owner == nil {
+			continue
+		}
+		if n := g.Node(comp.Owner.ID()); n != nil {
+			n.Kind = vango.NodeComponent
+			n.Label = fmt.Sprintf("%T", comp.Component)
+			n.HID = comp.HID
+		}
+	}
+	s.stateMu.Unlock()
+
+	return g
+}
+
+// inspectSession is one entry in the inspector's session list.
+type inspectSession struct {
+	ID         string `json:"id"`
+	Path       string `json:"path"`
+	Components int    `json:"components"`
+}
+
+// serveInspector serves the reactive graph inspector at /_vango/inspect.
+// Without parameters it lists the live sessions; with ?session=<id> it
+// returns that session's graph. The endpoint exposes session state, so it
+// only exists in DevMode.
+func (s *Server) serveInspector(w http.ResponseWriter, r *http.Request) {
+	if s.config == nil || !s.config.DevMode {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	id := r.URL.Query().Get("session")
+	if id == "" {
+		list := []inspectSession{}
+		s.sessions.ForEach(func(sess *Session) bool {
+			stats := sess.Stats()
+			list = append(list, inspectSession{
+				ID:         sess.ID,
+				Path:       sess.CurrentRoute,
+				Components: stats.ComponentCount,
+			})
+			return true
+		})
+		_ = json.NewEncoder(w).Encode(list)
+		return
+	}
+
+	sess := s.sessions.Get(id)
+	if sess == nil {
+		http.Error(w, ErrSessionNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	g, err := sess.Inspect()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(g)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+func TestSession_Inspect(t *testing.T) {
+	srv := counterServer(1)
+	_, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	if err := srv.mountSession(sess, "/"); err != nil {
+		t.Fatal(err)
+	}
+	sess.Start()
+	t.Cleanup(sess.Close)
+
+	g, err := sess.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+
+	var comp, count *vango.GraphNode
+	for i := range g.Nodes {
+		switch n := &g.Nodes[i]; n.Kind {
+		case vango.NodeComponent:
+			comp = n
+		case vango.NodeSignal:
+			if n.Label == "int" {
+				count = n
+			}
+		}
+	}
+	if comp == nil || comp.HID == "" || comp.Label != "server.FuncComponent" || comp.Runs != 1 {
+		t.Fatalf("component node = %+v", comp)
+	}
+	if count == nil || count.Owner != comp.ID || count.Value != "0" {
+		t.Fatalf("signal node = %+v", count)
+	}
+	found := false
+	for _, e := range g.Edges {
+		if e.From == count.ID && e.To == comp.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no edge from signal to component in %+v", g.Edges)
+	}
+}
+
+func TestServer_InspectorRequiresDevMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ServerConfig
+		target string
+		want   int
+	}{
+		{"production", DefaultServerConfig(), "/_vango/inspect", http.StatusNotFound},
+		{"dev list", DefaultServerConfig().WithDevMode(), "/_vango/inspect", http.StatusOK},
+		{"dev unknown session", DefaultServerConfig().WithDevMode(), "/_vango/inspect?session=nope", http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(tc.config)
+			t.Cleanup(func() { s.Sessions().Shutdown() })
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
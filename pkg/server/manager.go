@@ -9,9 +9,11 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/features/islands"
 	"github.com/vango-go/vango/pkg/features/store"
+	"github.com/vango-go/vango/pkg/pref"
 	"github.com/vango-go/vango/pkg/session"
+	"github.com/vango-go/vango/pkg/upload"
 	"github.com/vango-go/vango/pkg/urlparam"
 	"github.com/vango-go/vango/pkg/vango"
 	"github.com/vango-go/vango/pkg/vdom"
@@ -61,6 +63,12 @@ type SessionManager struct {
 	persistenceManager *session.Manager
 	sessionStore       session.SessionStore
 	resumeWindow       time.Duration
+
+	// broadcaster fans out topic broadcasts to sessions (see Topic).
+	broadcaster *Broadcaster
+
+	// prefStore persists the preferences of logged-in users (see pkg/pref).
+	prefStore pref.Store
 }
 
 // SessionManagerOptions contains optional Phase 12 configuration.
@@ -113,6 +121,7 @@ func NewSessionManagerWithOptions(config *SessionConfig, limits *SessionLimits,
 		logger:          logger.With("component", "session_manager"),
 		resumeWindow:    5 * time.Minute, // Default
 		evictOnIPLimit:  true,
+		broadcaster:     NewBroadcaster(nil, logger),
 	}
 
 	// Phase 12: Configure persistence if options provided
@@ -153,7 +162,7 @@ func NewSessionManagerWithOptions(config *SessionConfig, limits *SessionLimits,
 }
 
 // Create creates a new session for the given WebSocket connection.
-func (sm *SessionManager) Create(conn *websocket.Conn, userID, ip string) (*Session, error) {
+func (sm *SessionManager) Create(conn Conn, userID, ip string) (*Session, error) {
 	sm.mu.Lock()
 
 	// Check session limit
@@ -172,6 +181,8 @@ func (sm *SessionManager) Create(conn *websocket.Conn, userID, ip string) (*Sess
 	// Create session
 	session := newSession(conn, userID, sm.config, sm.logger)
 	session.IP = ip
+	session.broadcaster = sm.broadcaster
+	session.prefStore = sm.prefStore
 	session.setOnDetach(sm.OnSessionDisconnect)
 
 	// Register session
@@ -596,6 +607,34 @@ func (sm *SessionManager) SetOnSessionCreate(fn func(*Session)) {
 	sm.onSessionCreate = fn
 }
 
+// SetBroadcaster replaces the broadcaster used by sessions created afterwards.
+// The previous broadcaster is closed.
+func (sm *SessionManager) SetBroadcaster(b *Broadcaster) {
+	sm.mu.Lock()
+	prev := sm.broadcaster
+	sm.broadcaster = b
+	sm.mu.Unlock()
+
+	if prev != nil && prev != b {
+		prev.Close()
+	}
+}
+
+// Broadcaster returns the broadcaster shared by this manager's sessions.
+func (sm *SessionManager) Broadcaster() *Broadcaster {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.broadcaster
+}
+
+// SetPrefStore sets the store for the preferences of sessions created
+// afterwards.
+func (sm *SessionManager) SetPrefStore(store pref.Store) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.prefStore = store
+}
+
 // SetOnSessionClose sets the callback for session close.
 func (sm *SessionManager) SetOnSessionClose(fn func(*Session)) {
 	sm.onSessionClose = fn
@@ -790,6 +829,7 @@ func (sm *SessionManager) serializeSessionForPersistence(sess *Session) []byte {
 		UserID:     sess.UserID,
 		CreatedAt:  sess.CreatedAt,
 		LastActive: sess.LastActive,
+		Signals:    sess.signalPersistStore().Snapshot(),
 		Route:      sess.CurrentRoute,
 	}
 
@@ -814,6 +854,11 @@ func (sm *SessionManager) restoreSessionFromPersistence(sessionID string, data [
 		return nil
 	}
 
+	// SetBroadcaster and SetPrefStore may run concurrently.
+	sm.mu.RLock()
+	broadcaster, prefStore := sm.broadcaster, sm.prefStore
+	sm.mu.RUnlock()
+
 	// Create fully initialized session
 	sess := &Session{
 		ID:           ss.ID,
@@ -825,6 +870,7 @@ func (sm *SessionManager) restoreSessionFromPersistence(sessionID string, data [
 		// Initialize component tracking (populated on RebuildHandlers)
 		allComponents: make(map[*ComponentInstance]struct{}),
 		handlers:      make(map[string]Handler),
+		uploads:       make(map[string]upload.Live),
 		components:    make(map[string]*ComponentInstance),
 
 		// Create fresh owner and HID generator
@@ -837,9 +883,10 @@ func (sm *SessionManager) restoreSessionFromPersistence(sessionID string, data [
 		dispatchCh: make(chan func(), sm.config.MaxEventQueue),
 		done:       make(chan struct{}),
 
-		config: sm.config,
-		logger: sm.logger.With("session_id", ss.ID),
-		data:   make(map[string]any),
+		config:      sm.config,
+		logger:      sm.logger.With("session_id", ss.ID),
+		data:        make(map[string]any),
+		broadcaster: broadcaster,
 	}
 
 	// Initialize session-scoped store for SharedSignal support
@@ -848,10 +895,27 @@ func (sm *SessionManager) restoreSessionFromPersistence(sessionID string, data [
 	// Also set vango.SessionSignalStoreKey for vango.NewSharedSignal support.
 	sess.owner.SetValue(vango.SessionSignalStoreKey, sessionStore)
 
+	// Restore persisted signal values; they are applied when components
+	// create signals with matching PersistKey options.
+	persistStore := vango.NewSignalPersistStore()
+	persistStore.Restore(ss.Signals)
+	sess.owner.SetValue(vango.SignalPersistStoreKey, persistStore)
+
 	// Initialize URL navigator for URLParam support
 	navigator := urlparam.NewNavigator(sess.queueURLPatch)
 	sess.owner.SetValue(urlparam.NavigatorKey, navigator)
 
+	// Initialize island bridge for JS island messaging
+	sess.islands = islands.NewBridge(sess.queueURLPatch)
+	sess.owner.SetValue(islands.BridgeKey, sess.islands)
+
+	// Initialize window and document event subscriptions
+	sess.initGlobalEvents()
+
+	// Initialize the preference manager
+	sess.initPrefs()
+	sess.prefStore = prefStore
+
 	// Restore session data values
 	if ss.Values != nil {
 		values := make(map[string]any, len(ss.Values))
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/vango-go/vango/pkg/session"
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
 )
 
 func TestSessionManager_restoreSessionFromPersistence_RestoresValues(t *testing.T) {
@@ -42,3 +44,51 @@ func TestSessionManager_restoreSessionFromPersistence_RestoresValues(t *testing.
 	}
 }
 
+func TestSessionManager_restoreSessionFromPersistence_GlobalEvents(t *testing.T) {
+	sm := NewSessionManager(DefaultSessionConfig(), DefaultSessionLimits(), slog.Default())
+	t.Cleanup(func() { sm.Shutdown() })
+
+	data, err := session.Serialize(&session.SerializableSession{ID: "id", Route: "/"})
+	if err != nil {
+		t.Fatalf("session.Serialize error: %v", err)
+	}
+	restored := sm.restoreSessionFromPersistence("id", data)
+	if restored == nil {
+		t.Fatal("restoreSessionFromPersistence returned nil")
+	}
+
+	restored.MountRoot(FuncComponent(func() *vdom.VNode {
+		vango.OnWindow("resize", func() {})
+		return vdom.Div()
+	}))
+	restored.globalEvents.mu.Lock()
+	n := len(restored.globalEvents.listeners)
+	restored.globalEvents.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("listeners = %d, want the OnWindow subscription", n)
+	}
+}
+
+func TestSessionManager_restoreSessionFromPersistence_ConcurrentSetBroadcaster(t *testing.T) {
+	sm := NewSessionManager(DefaultSessionConfig(), DefaultSessionLimits(), slog.Default())
+	t.Cleanup(func() { sm.Shutdown() })
+
+	data, err := session.Serialize(&session.SerializableSession{ID: "id", Route: "/"})
+	if err != nil {
+		t.Fatalf("session.Serialize error: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			sm.SetBroadcaster(NewBroadcaster(nil, slog.Default()))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if sm.restoreSessionFromPersistence("id", data) == nil {
+			t.Fatal("restoreSessionFromPersistence returned nil")
+		}
+	}
+	<-done
+}
@@ -7,7 +7,9 @@ import (
 	"net/url"
 
 	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/render"
 	"github.com/vango-go/vango/pkg/routepath"
+	"github.com/vango-go/vango/pkg/upload"
 	"github.com/vango-go/vango/pkg/vango"
 	"github.com/vango-go/vango/pkg/vdom"
 )
@@ -55,6 +57,14 @@ type Router interface {
 	NotFound() PageHandler
 }
 
+// ErrorPageRenderer is implemented by routers with an error page.
+// ErrorBoundary components without a fallback render it in a live session.
+type ErrorPageRenderer interface {
+	// RenderErrorPage renders the error page for err, or returns nil if no
+	// error page is configured.
+	RenderErrorPage(ctx Ctx, err error) *vdom.VNode
+}
+
 // =============================================================================
 // Path Canonicalization
 // =============================================================================
@@ -123,6 +133,10 @@ type NavigateResult struct {
 	// NavPatch is the NAV_PUSH or NAV_REPLACE patch
 	NavPatch protocol.Patch
 
+	// HeadPatches update the document title and meta tags from the
+	// route's Meta handler. Empty if the route has none.
+	HeadPatches []protocol.Patch
+
 	// Error contains any error that occurred
 	Error error
 }
@@ -235,6 +249,7 @@ func (rn *RouteNavigator) Navigate(path string, replace bool) *NavigateResult {
 			result.NavPatch = protocol.NewNavPushPatch(fullPath)
 		}
 		result.Patches = patches
+		result.HeadPatches = rn.headPatches(match)
 		return result
 	}
 
@@ -273,19 +288,7 @@ func (rn *RouteNavigator) renderRoute(match RouteMatch) ([]vdom.Patch, error) {
 	// Create a render context for route middleware + page component construction.
 	// Note: The page handler returns a Component that captures this ctx and calls the
 	// user page function during render, so params/request MUST be correct here.
-	renderCtx := rn.session.createRenderContext()
-	if ctxImpl, ok := renderCtx.(*ctx); ok {
-		ctxImpl.setParams(match.GetParams())
-		if ctxImpl.request == nil {
-			ctxImpl.request = &http.Request{
-				Method: http.MethodGet,
-				URL: &url.URL{
-					Path:     rn.currentPath,
-					RawQuery: rn.currentQuery,
-				},
-			}
-		}
-	}
+	renderCtx := rn.routeContext(match)
 
 	var page Component
 	var ranFinal bool
@@ -329,6 +332,7 @@ func (rn *RouteNavigator) renderRoute(match RouteMatch) ([]vdom.Patch, error) {
 		session:   rn.session,
 		page:      page,
 		layouts:   match.GetLayoutHandlers(),
+		meta:      metaHandlerFor(match),
 		canonPath: rn.currentPath,
 		query:     rn.currentQuery,
 		params:    match.GetParams(),
@@ -361,6 +365,7 @@ func (rn *RouteNavigator) renderRoute(match RouteMatch) ([]vdom.Patch, error) {
 
 	// Rebuild handler and ownership maps from the mounted component instances.
 	rn.session.handlers = make(map[string]Handler)
+	rn.session.uploads = make(map[string]upload.Live)
 	rn.session.components = make(map[string]*ComponentInstance)
 	rn.session.collectHandlersFromInstancesLocked(newRoot)
 
@@ -372,6 +377,48 @@ func (rn *RouteNavigator) renderRoute(match RouteMatch) ([]vdom.Patch, error) {
 	return patches, nil
 }
 
+// routeContext creates a render context carrying the params and request of
+// the route being navigated to.
+func (rn *RouteNavigator) routeContext(match RouteMatch) Ctx {
+	renderCtx := rn.session.createRenderContext()
+	if ctxImpl, ok := renderCtx.(*ctx); ok {
+		ctxImpl.setParams(match.GetParams())
+		if ctxImpl.request == nil {
+			ctxImpl.request = &http.Request{
+				Method: http.MethodGet,
+				URL: &url.URL{
+					Path:     rn.currentPath,
+					RawQuery: rn.currentQuery,
+				},
+			}
+		}
+	}
+	return renderCtx
+}
+
+// headPatches evaluates the route's Meta handler and returns the patches
+// that update the document title, meta and link tags.
+// Routes without a Meta handler, or without a Meta title, get the title of
+// the rendered layout, and their managed tags are removed so nothing from
+// the previous page is left behind.
+func (rn *RouteNavigator) headPatches(match RouteMatch) []protocol.Patch {
+	var meta PageMeta
+	if metaHandler := metaHandlerFor(match); metaHandler != nil {
+		renderCtx := rn.routeContext(match)
+		vango.WithCtx(renderCtx, func() {
+			vango.WithOwner(rn.session.owner, func() {
+				meta = metaHandler(renderCtx, match.GetParams())
+			})
+		})
+	}
+	if meta.Title == "" {
+		rn.session.stateMu.Lock()
+		meta.Title = render.DocumentTitle(rn.session.currentTree)
+		rn.session.stateMu.Unlock()
+	}
+	return meta.HeadPatches()
+}
+
 // useCachedTree uses a prefetched tree for navigation (cache hit path).
 // Per Section 8.4: "If hit and not stale → reuse rendered tree and cached data"
 // This skips the page handler execution since the tree is already rendered.
@@ -437,7 +484,7 @@ func expandComponents(node *vdom.VNode) *vdom.VNode {
 			return nil
 		}
 		return expandComponents(node.Comp.Render())
-	case vdom.KindElement, vdom.KindFragment:
+	case vdom.KindElement, vdom.KindFragment, vdom.KindPortal:
 		if len(node.Children) > 0 {
 			expanded := make([]*vdom.VNode, 0, len(node.Children))
 			for _, child := range node.Children {
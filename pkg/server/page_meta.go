@@ -0,0 +1,113 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/render"
+)
+
+// =============================================================================
+// Page Metadata
+// =============================================================================
+
+// PageMeta contains page metadata for SEO and the document head.
+//
+// Route files provide it by exporting a Meta function:
+//
+//	func Meta(ctx vango.Ctx, p ShowParams) vango.PageMeta {
+//	    return vango.PageMeta{Title: "Project " + p.ID}
+//	}
+//
+// During SSR the metadata is rendered into <head>. During WebSocket
+// navigation it is applied with SetTitle/SetMeta/SetLink patches.
+type PageMeta struct {
+	Title       string
+	Description string
+	Keywords    []string
+	OGImage     string
+	OGTitle     string
+	OGDesc      string
+	Canonical   string
+	Robots      string
+}
+
+// MetaHandler returns the metadata for a matched page.
+// params is the route parameter map, as passed to PageHandler.
+type MetaHandler func(ctx Ctx, params any) PageMeta
+
+// RouteMetaMatch is implemented by route matches that carry a Meta handler.
+// It is optional so that existing RouteMatch implementations keep working.
+type RouteMetaMatch interface {
+	// GetMetaHandler returns the page's Meta handler, or nil.
+	GetMetaHandler() MetaHandler
+}
+
+// metaHandlerFor returns the Meta handler of match, if it has one.
+func metaHandlerFor(match RouteMatch) MetaHandler {
+	if mm, ok := match.(RouteMetaMatch); ok {
+		return mm.GetMetaHandler()
+	}
+	return nil
+}
+
+// pageMetaTag is a <meta> tag managed by PageMeta.
+type pageMetaTag struct {
+	attr    string // "name" or "property"
+	key     string
+	content string
+}
+
+// tags returns every <meta> tag PageMeta manages, including empty ones.
+// Empty tags are skipped during SSR and removed during navigation.
+func (m PageMeta) tags() []pageMetaTag {
+	return []pageMetaTag{
+		{"name", "description", m.Description},
+		{"name", "keywords", strings.Join(m.Keywords, ", ")},
+		{"name", "robots", m.Robots},
+		{"property", "og:title", m.OGTitle},
+		{"property", "og:description", m.OGDesc},
+		{"property", "og:image", m.OGImage},
+	}
+}
+
+// ApplyTo copies the metadata into the head fields of page.
+// Tags already present in page are kept; Title is overwritten when set.
+func (m PageMeta) ApplyTo(page *render.PageData) {
+	if page == nil {
+		return
+	}
+	if m.Title != "" {
+		page.Title = m.Title
+	}
+	for _, tag := range m.tags() {
+		if tag.content == "" {
+			continue
+		}
+		meta := render.MetaTag{Content: tag.content}
+		if tag.attr == "property" {
+			meta.Property = tag.key
+		} else {
+			meta.Name = tag.key
+		}
+		page.Meta = append(page.Meta, meta)
+	}
+	if m.Canonical != "" {
+		page.Links = append(page.Links, render.LinkTag{Rel: "canonical", Href: m.Canonical})
+	}
+}
+
+// HeadPatches returns the patches that bring the client's document head in
+// line with the metadata. The title and every managed tag are included so
+// that nothing left over from the previous page survives; an empty Title
+// clears the document title.
+func (m PageMeta) HeadPatches() []protocol.Patch {
+	tags := m.tags()
+	patches := make([]protocol.Patch, 0, len(tags)+2)
+	patches = append(patches, protocol.NewSetTitlePatch(m.Title))
+	for _, tag := range tags {
+		patches = append(patches, protocol.NewSetMetaPatch(tag.attr, tag.key, tag.content))
+	}
+	patches = append(patches, protocol.NewSetLinkPatch("canonical", m.Canonical))
+	return patches
+}
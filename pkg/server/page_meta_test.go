@@ -0,0 +1,195 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/render"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+type testMetaRouteMatch struct {
+	testRouteMatch
+	meta MetaHandler
+}
+
+func (m *testMetaRouteMatch) GetMetaHandler() MetaHandler { return m.meta }
+
+func TestPageMeta_ApplyTo(t *testing.T) {
+	page := render.PageData{Title: "Default"}
+	PageMeta{
+		Title:       "Project",
+		Description: "A project",
+		Keywords:    []string{"a", "b"},
+		OGTitle:     "OG Project",
+		Canonical:   "https://example.com/p",
+	}.ApplyTo(&page)
+
+	if page.Title != "Project" {
+		t.Errorf("Title = %q, want %q", page.Title, "Project")
+	}
+	want := []render.MetaTag{
+		{Name: "description", Content: "A project"},
+		{Name: "keywords", Content: "a, b"},
+		{Property: "og:title", Content: "OG Project"},
+	}
+	if len(page.Meta) != len(want) {
+		t.Fatalf("Meta = %+v, want %+v", page.Meta, want)
+	}
+	for i := range want {
+		if page.Meta[i] != want[i] {
+			t.Errorf("Meta[%d] = %+v, want %+v", i, page.Meta[i], want[i])
+		}
+	}
+	if len(page.Links) != 1 || page.Links[0].Rel != "canonical" || page.Links[0].Href != "https://example.com/p" {
+		t.Errorf("Links = %+v, want canonical link", page.Links)
+	}
+}
+
+func TestPageMeta_HeadPatches(t *testing.T) {
+	patches := PageMeta{Title: "Project", Description: "A project"}.HeadPatches()
+
+	if patches[0].Op != protocol.PatchSetTitle || patches[0].Value != "Project" {
+		t.Fatalf("patches[0] = %+v, want SetTitle(Project)", patches[0])
+	}
+
+	metas := make(map[string]string)
+	var canonical *protocol.Patch
+	for i, p := range patches[1:] {
+		switch p.Op {
+		case protocol.PatchSetMeta:
+			metas[p.Attr+":"+p.Key] = p.Value
+		case protocol.PatchSetLink:
+			canonical = &patches[i+1]
+		default:
+			t.Errorf("unexpected patch %+v", p)
+		}
+	}
+	if metas["name:description"] != "A project" {
+		t.Errorf("description = %q, want %q", metas["name:description"], "A project")
+	}
+	// Unset tags are sent empty so the client removes stale values.
+	if v, ok := metas["property:og:title"]; !ok || v != "" {
+		t.Errorf("og:title = %q (present=%v), want empty removal patch", v, ok)
+	}
+	if canonical == nil || canonical.Key != "canonical" || canonical.Value != "" {
+		t.Errorf("canonical = %+v, want empty canonical removal patch", canonical)
+	}
+}
+
+func TestPageMeta_HeadPatchesWithoutTitle(t *testing.T) {
+	patches := (PageMeta{}).HeadPatches()
+	if patches[0].Op != protocol.PatchSetTitle || patches[0].Value != "" {
+		t.Fatalf("patches[0] = %+v, want SetTitle(\"\")", patches[0])
+	}
+}
+
+// layoutWithTitle renders children inside a document whose head has a title.
+func layoutWithTitle(title string) LayoutHandler {
+	return func(c Ctx, children *vdom.VNode) *vdom.VNode {
+		return vdom.Html(
+			vdom.Head(vdom.Title(vdom.Text(title))),
+			vdom.Body(children),
+		)
+	}
+}
+
+func TestRouteRoot_MetaHIDsMatchSSR(t *testing.T) {
+	page := func(c Ctx, params any) Component {
+		return staticComponent{node: vdom.Div(vdom.Button(vdom.Text("save")))}
+	}
+	meta := func(c Ctx, params any) PageMeta {
+		return PageMeta{Title: "Project", Description: "A project", Canonical: "https://example.com/p"}
+	}
+	layout := layoutWithTitle("Layout Title")
+
+	// SSR: layouts, then the Meta head, then render (as App.renderPage does).
+	ssrTree := layout(nil, page(nil, nil).Render())
+	var head render.PageData
+	meta(nil, nil).ApplyTo(&head)
+	ssrTree = render.ApplyHead(ssrTree, head)
+	ssrHTML, err := render.NewRenderer(render.RendererConfig{}).RenderToString(ssrTree)
+	if err != nil {
+		t.Fatalf("SSR render: %v", err)
+	}
+
+	// WebSocket mount of the same route.
+	r := &testRouter{routes: map[string]RouteMatch{
+		"/p": &testMetaRouteMatch{
+			testRouteMatch: testRouteMatch{
+				params:  map[string]string{},
+				page:    page,
+				layouts: []LayoutHandler{layout},
+			},
+			meta: meta,
+		},
+	}}
+	sess := NewMockSession()
+	root, _, err := newRouteRootComponent(sess, r, "/p")
+	if err != nil {
+		t.Fatalf("newRouteRootComponent: %v", err)
+	}
+	sess.MountRoot(root)
+	mountHTML, err := render.NewRenderer(render.RendererConfig{}).RenderToString(sess.currentTree)
+	if err != nil {
+		t.Fatalf("mount render: %v", err)
+	}
+
+	if mountHTML != ssrHTML {
+		t.Errorf("mounted tree does not match SSR:\n ssr: %s\nlive: %s", ssrHTML, mountHTML)
+	}
+}
+
+func TestRouteNavigator_Navigate_HeadPatchesFromMeta(t *testing.T) {
+	sess := NewMockSession()
+	var gotParams any
+	r := &testRouter{
+		routes: map[string]RouteMatch{
+			"/projects/1": &testMetaRouteMatch{
+				testRouteMatch: testRouteMatch{
+					params: map[string]string{"id": "1"},
+					page: func(c Ctx, params any) Component {
+						return staticComponent{node: &vdom.VNode{Kind: vdom.KindElement, Tag: "div"}}
+					},
+				},
+				meta: func(c Ctx, params any) PageMeta {
+					gotParams = params
+					return PageMeta{Title: "Project 1"}
+				},
+			},
+			"/plain": &testRouteMatch{
+				params: map[string]string{},
+				page: func(c Ctx, params any) Component {
+					return staticComponent{node: &vdom.VNode{Kind: vdom.KindElement, Tag: "div"}}
+				},
+				layouts: []LayoutHandler{layoutWithTitle("Plain Layout")},
+			},
+		},
+	}
+
+	nav := NewRouteNavigator(sess, r)
+	res := nav.Navigate("/projects/1", false)
+	if res.Error != nil {
+		t.Fatalf("Navigate error: %v", res.Error)
+	}
+	if len(res.HeadPatches) == 0 || res.HeadPatches[0].Op != protocol.PatchSetTitle || res.HeadPatches[0].Value != "Project 1" {
+		t.Fatalf("HeadPatches = %+v, want SetTitle(Project 1) first", res.HeadPatches)
+	}
+	if params, ok := gotParams.(map[string]string); !ok || params["id"] != "1" {
+		t.Errorf("meta params = %#v, want id=1", gotParams)
+	}
+
+	// Routes without Meta restore the layout title and clear the tags.
+	res = nav.Navigate("/plain", false)
+	if res.Error != nil {
+		t.Fatalf("Navigate error: %v", res.Error)
+	}
+	if len(res.HeadPatches) == 0 || res.HeadPatches[0].Op != protocol.PatchSetTitle || res.HeadPatches[0].Value != "Plain Layout" {
+		t.Fatalf("HeadPatches = %+v, want SetTitle(Plain Layout) first", res.HeadPatches)
+	}
+	for _, p := range res.HeadPatches[1:] {
+		if p.Value != "" {
+			t.Errorf("patch %+v, want removal", p)
+		}
+	}
+}
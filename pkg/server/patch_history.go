@@ -8,7 +8,7 @@ import (
 // PatchHistoryEntry stores a sent patch frame for potential replay.
 type PatchHistoryEntry struct {
 	Seq    uint64    // Patch sequence number
-	Frame  []byte    // Pre-encoded FramePatches for fast replay
+	Frame  []byte    // Pre-encoded FramePatches (all fragments if large) for fast replay
 	SentAt time.Time // When the frame was sent
 }
 
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vango-go/vango/pkg/auth"
+	"github.com/vango-go/vango/pkg/pref"
+	"github.com/vango-go/vango/pkg/protocol"
+)
+
+// prefStoreTimeout bounds a single preference Store load or save.
+const prefStoreTimeout = 5 * time.Second
+
+// prefTopic is the broadcast topic carrying a user's preference changes
+// between their sessions.
+func prefTopic(userID string) string {
+	return "vango:pref:" + userID
+}
+
+// prefMessage is a preference change broadcast to a user's sessions.
+type prefMessage struct {
+	// Origin is the ID of the session that made the change; it ignores its
+	// own message.
+	Origin string     `json:"origin"`
+	Entry  pref.Entry `json:"entry"`
+}
+
+// initPrefs creates the session's preference manager and stores it on the
+// session owner, where pref.New finds it.
+func (s *Session) initPrefs() {
+	s.prefs = pref.NewManager(prefSyncer{s})
+	s.owner.SetValue(pref.ManagerKey, s.prefs)
+}
+
+// Prefs returns the session's preference manager.
+func (s *Session) Prefs() *pref.Manager {
+	return s.prefs
+}
+
+// prefUserID returns the ID preferences are synced for at handshake: the
+// auth principal's ID, or the UserID returned by the auth function.
+func (s *Session) prefUserID() string {
+	if principal, ok := s.Get(auth.SessionKeyPrincipal).(auth.Principal); ok && principal.ID != "" {
+		return principal.ID
+	}
+	return s.UserID
+}
+
+// startPrefs applies the preferences the client reported in its hello and,
+// for a known user, syncs them with the Store. It runs before the session
+// loop starts or on the loop.
+func (s *Session) startPrefs(values []protocol.PrefValue) {
+	if s.prefs == nil {
+		return
+	}
+	if len(values) > 0 {
+		s.prefs.ApplyLocal(prefEntries(values))
+	}
+	if userID := s.prefUserID(); userID != "" && userID != s.prefs.UserID() {
+		prefSyncer{s}.LoadRemote(userID)
+	}
+}
+
+// handlePrefSync applies preferences changed in one of the client's tabs.
+func (s *Session) handlePrefSync(ps *protocol.PrefSync) {
+	entries := prefEntries(ps.Values)
+	s.Dispatch(func() {
+		s.prefs.ApplyLocal(entries)
+	})
+}
+
+// subscribePrefs subscribes the session to the preference changes of
+// userID, replacing any previous subscription.
+func (s *Session) subscribePrefs(userID string) {
+	s.prefMu.Lock()
+	defer s.prefMu.Unlock()
+
+	if s.prefTopicUser == userID || s.broadcaster == nil {
+		return
+	}
+	if s.prefUnsubscribe != nil {
+		s.prefUnsubscribe()
+	}
+	s.prefTopicUser = userID
+	s.prefUnsubscribe = s.broadcaster.subscribe(s, prefTopic(userID), func(raw json.RawMessage) {
+		var msg prefMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			s.logger.Warn("pref broadcast decode failed", "error", err)
+			return
+		}
+		if msg.Origin == s.ID {
+			return
+		}
+		s.Dispatch(func() {
+			// Messages for a previous user may still be in flight.
+			if s.prefs.UserID() == userID {
+				s.prefs.ApplyRemote(msg.Entry)
+			}
+		})
+	})
+}
+
+// unsubscribePrefs ends the session's preference subscription.
+func (s *Session) unsubscribePrefs() {
+	s.prefMu.Lock()
+	defer s.prefMu.Unlock()
+
+	if s.prefUnsubscribe != nil {
+		s.prefUnsubscribe()
+		s.prefUnsubscribe = nil
+	}
+	s.prefTopicUser = ""
+}
+
+// prefSyncer implements pref.Syncer for a session.
+type prefSyncer struct {
+	s *Session
+}
+
+// SaveLocal sends entries to the client in a PrefSync control message.
+func (p prefSyncer) SaveLocal(entries []pref.Entry) {
+	s := p.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed.Load() || s.conn == nil {
+		return
+	}
+
+	values := make([]protocol.PrefValue, len(entries))
+	for i, e := range entries {
+		values[i] = protocol.PrefValue{
+			Key:       e.Key,
+			Value:     string(e.Value),
+			UpdatedAt: uint64(e.UpdatedAt.UnixMilli()),
+		}
+	}
+	ct, ps := protocol.NewPrefSync(values)
+	if !s.versionLocked().SupportsControl(ct) {
+		return
+	}
+	frame := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, ps))
+
+	if err := s.writeMessageLocked(frame.EncodeMessage()); err != nil {
+		s.logger.Error("pref sync send error", "error", err)
+	}
+}
+
+// SaveRemote saves e to the Store in the background and broadcasts it to
+// the user's other sessions.
+func (p prefSyncer) SaveRemote(userID string, e pref.Entry) {
+	s := p.s
+	if s.broadcaster != nil {
+		if err := s.broadcaster.Broadcast(prefTopic(userID), prefMessage{Origin: s.ID, Entry: e}); err != nil {
+			s.logger.Warn("pref broadcast failed", "key", e.Key, "error", err)
+		}
+	}
+	if s.prefStore == nil {
+		return
+	}
+	store := s.prefStore
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), prefStoreTimeout)
+		defer cancel()
+		if err := store.Save(ctx, userID, e); err != nil {
+			s.logger.Error("pref save failed", "key", e.Key, "error", err)
+		}
+	}()
+}
+
+// LoadRemote subscribes to the user's preference changes and loads their
+// stored preferences in the background, merging them on the session loop.
+// If loading fails the session stays logged out, so that a failing Store
+// cannot overwrite stored values with the client's.
+func (p prefSyncer) LoadRemote(userID string) {
+	s := p.s
+	s.subscribePrefs(userID)
+	if s.prefStore == nil {
+		s.prefs.Login(userID, nil)
+		return
+	}
+
+	store := s.prefStore
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), prefStoreTimeout)
+		defer cancel()
+		entries, err := store.Load(ctx, userID)
+		if err != nil {
+			s.logger.Error("pref load failed", "user_id", userID, "error", err)
+			return
+		}
+		s.Dispatch(func() {
+			s.prefs.Login(userID, entries)
+		})
+	}()
+}
+
+// prefEntries converts protocol values into preference entries.
+func prefEntries(values []protocol.PrefValue) []pref.Entry {
+	entries := make([]pref.Entry, len(values))
+	for i, v := range values {
+		entries[i] = pref.Entry{
+			Key:       v.Key,
+			Value:     json.RawMessage(v.Value),
+			UpdatedAt: time.UnixMilli(int64(v.UpdatedAt)),
+		}
+	}
+	return entries
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/pref"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+var testThemePref = pref.New("server-test-theme", "light")
+
+// readPrefSync reads frames until a PrefSync control message arrives.
+func readPrefSync(t *testing.T, conn *websocket.Conn) *protocol.PrefSync {
+	t.Helper()
+	r := protocol.NewReassembler(0)
+	for {
+		frame, _ := readMessage(t, conn, r)
+		if frame.Type != protocol.FrameControl {
+			continue
+		}
+		ct, data, err := protocol.DecodeControl(frame.Payload)
+		if err != nil {
+			t.Fatalf("DecodeControl failed: %v", err)
+		}
+		if ct == protocol.ControlPrefSync {
+			return data.(*protocol.PrefSync)
+		}
+	}
+}
+
+func TestServer_PrefsSyncWithStoreAndSessions(t *testing.T) {
+	store := pref.NewMemoryStore()
+	now := time.Now()
+	store.Save(context.Background(), "u1", pref.Entry{
+		Key:       "server-test-theme",
+		Value:     json.RawMessage(`"dark"`),
+		UpdatedAt: now,
+	})
+
+	config := DefaultServerConfig()
+	config.PrefStore = store
+	s := New(config)
+	s.SetAuthFunc(func(r *http.Request) (any, error) { return "u1", nil })
+	s.SetRootComponent(func() Component {
+		return FuncComponent(func() *vdom.VNode {
+			return vdom.Div(vdom.Text(testThemePref.Get()))
+		})
+	})
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { s.Sessions().Shutdown() })
+
+	// The client's older value loses to the stored one.
+	c1 := dialWS(t, wsURL(t, ts.URL, "/_vango/live?path=/"), nil)
+	hello := protocol.NewClientHello("")
+	hello.PrefValues = []protocol.PrefValue{
+		{Key: "server-test-theme", Value: `"light"`, UpdatedAt: uint64(now.Add(-time.Hour).UnixMilli())},
+		{Key: "server-test-font", Value: `"big"`, UpdatedAt: uint64(now.UnixMilli())},
+	}
+	writeHandshake(t, c1, hello)
+	if sh := readServerHello(t, c1); sh.Status != protocol.HandshakeOK {
+		t.Fatalf("status = %v", sh.Status)
+	}
+	ps := readPrefSync(t, c1)
+	if len(ps.Values) != 1 || ps.Values[0].Value != `"dark"` {
+		t.Fatalf("PrefSync = %+v, want the stored theme", ps.Values)
+	}
+
+	// The client-only value is saved for the user.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entries, _ := store.Load(context.Background(), "u1")
+		if len(entries) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("store has %v, want the client's font saved", entries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A change in the first session reaches a second session of the user.
+	c2 := dialWS(t, wsURL(t, ts.URL, "/_vango/live?path=/"), nil)
+	writeHandshake(t, c2, protocol.NewClientHello(""))
+	readServerHello(t, c2)
+	readPrefSync(t, c2) // the stored values
+
+	ct, sync := protocol.NewPrefSync([]protocol.PrefValue{
+		{Key: "server-test-font", Value: `"small"`, UpdatedAt: uint64(time.Now().Add(time.Second).UnixMilli())},
+	})
+	frame := protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, sync))
+	if err := c1.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+
+	ps = readPrefSync(t, c2)
+	if len(ps.Values) != 1 || ps.Values[0].Key != "server-test-font" || ps.Values[0].Value != `"small"` {
+		t.Fatalf("second session got %+v, want the new font", ps.Values)
+	}
+}
+
+func TestSession_PrefSyncLargeValue(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	s := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	t.Cleanup(s.Close)
+
+	value := `"` + strings.Repeat("x", 2*protocol.MaxPayloadSize) + `"`
+	prefSyncer{s}.SaveLocal([]pref.Entry{
+		{Key: "server-test-draft", Value: json.RawMessage(value), UpdatedAt: time.Now()},
+	})
+
+	// readPrefSync fails if a WebSocket message holds more than one frame.
+	ps := readPrefSync(t, clientConn)
+	if len(ps.Values) != 1 || ps.Values[0].Value != value {
+		t.Fatal("PrefSync value does not match")
+	}
+}
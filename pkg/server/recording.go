@@ -0,0 +1,526 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+)
+
+// Session recordings capture what a session received and sent, so a
+// misbehaving session can be replayed against the same code later.
+//
+// File format:
+//
+//	"VREC" [version:1]
+//	records: [kind:1][at:uvarint microseconds since start][len:uvarint][data:len]
+//
+// The first record is RecordStart. Event, ClientEnv and Patches records carry
+// the uncompressed payloads of the corresponding frames.
+
+// recordingMagic starts every recording file.
+const recordingMagic = "VREC"
+
+// recordingVersion is the current recording file format version.
+const recordingVersion = 1
+
+// ErrInvalidRecording is returned by ReadRecording for files that are not
+// session recordings.
+var ErrInvalidRecording = errors.New("server: invalid session recording")
+
+// RecordKind identifies a record in a session recording.
+type RecordKind uint8
+
+const (
+	// RecordStart holds the initial path and client environment.
+	RecordStart RecordKind = 0x01
+	// RecordEvent holds an event frame payload received from the client.
+	RecordEvent RecordKind = 0x02
+	// RecordClientEnv holds a ControlClientEnv control payload.
+	RecordClientEnv RecordKind = 0x03
+	// RecordPatches holds a patches frame payload sent to the client.
+	RecordPatches RecordKind = 0x04
+)
+
+// String returns the record kind name.
+func (k RecordKind) String() string {
+	switch k {
+	case RecordStart:
+		return "Start"
+	case RecordEvent:
+		return "Event"
+	case RecordClientEnv:
+		return "ClientEnv"
+	case RecordPatches:
+		return "Patches"
+	default:
+		return fmt.Sprintf("Unknown(%d)", k)
+	}
+}
+
+// Record is one entry of a session recording.
+type Record struct {
+	Kind RecordKind
+	At   time.Duration // Time since the recording started
+	Data []byte
+}
+
+// Recording is a decoded session recording.
+type Recording struct {
+	Path    string    // Route the session was mounted on
+	Env     ClientEnv // Client environment at the start
+	Records []Record  // All records after RecordStart, in order
+}
+
+// Recorder writes a session recording. It is safe for concurrent use.
+// Write errors stop the recording; they never affect the session.
+type Recorder struct {
+	mu    sync.Mutex
+	w     *bufio.Writer
+	c     io.Closer
+	start time.Time
+	err   error
+	buf   [2 * binary.MaxVarintLen64]byte
+}
+
+// NewRecorder creates a Recorder writing to w, which is closed with the
+// recorder if it is an io.Closer.
+func NewRecorder(w io.Writer) *Recorder {
+	r := &Recorder{w: bufio.NewWriter(w), start: time.Now()}
+	if c, ok := w.(io.Closer); ok {
+		r.c = c
+	}
+	r.w.WriteString(recordingMagic)
+	r.err = r.w.WriteByte(recordingVersion)
+	return r
+}
+
+// recordStart writes the RecordStart record.
+func (r *Recorder) recordStart(path string, env ClientEnv) {
+	ct, ce := protocol.NewClientEnv(&protocol.ClientEnv{
+		ViewportW: uint16(env.Viewport.Width),
+		ViewportH: uint16(env.Viewport.Height),
+		TZOffset:  int16(env.TZOffset),
+		Locale:    env.Locale,
+		Prefs:     envPrefs(env),
+	})
+	e := protocol.NewEncoder()
+	e.WriteString(path)
+	e.WriteBytes(protocol.EncodeControl(ct, ce))
+	r.record(RecordStart, e.Bytes())
+}
+
+// record appends a record.
+func (r *Recorder) record(kind RecordKind, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	n := binary.PutUvarint(r.buf[:], uint64(time.Since(r.start)/time.Microsecond))
+	n += binary.PutUvarint(r.buf[n:], uint64(len(data)))
+	r.w.WriteByte(byte(kind))
+	r.w.Write(r.buf[:n])
+	_, r.err = r.w.Write(data)
+}
+
+// Flush writes buffered records to the underlying writer.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.err = r.w.Flush()
+	return r.err
+}
+
+// Close flushes the recording and closes the underlying writer.
+func (r *Recorder) Close() error {
+	err := r.Flush()
+	if r.c != nil {
+		if cerr := r.c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// ReadRecording decodes a session recording.
+func ReadRecording(rd io.Reader) (*Recording, error) {
+	br := bufio.NewReader(rd)
+	header := make([]byte, len(recordingMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(recordingMagic)]) != recordingMagic {
+		return nil, ErrInvalidRecording
+	}
+	if header[len(recordingMagic)] != recordingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidRecording, header[len(recordingMagic)])
+	}
+
+	rec := &Recording{}
+	started := false
+	for {
+		kind, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		at, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, truncated(err)
+		}
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, truncated(err)
+		}
+		if n > protocol.HardMaxAllocation {
+			return nil, protocol.ErrAllocationTooLarge
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return nil, truncated(err)
+		}
+
+		if RecordKind(kind) == RecordStart {
+			if err := rec.decodeStart(data); err != nil {
+				return nil, err
+			}
+			started = true
+			continue
+		}
+		rec.Records = append(rec.Records, Record{
+			Kind: RecordKind(kind),
+			At:   time.Duration(at) * time.Microsecond,
+			Data: data,
+		})
+	}
+	if !started {
+		return nil, fmt.Errorf("%w: missing start record", ErrInvalidRecording)
+	}
+	return rec, nil
+}
+
+func (rec *Recording) decodeStart(data []byte) error {
+	d := protocol.NewDecoder(data)
+	path, err := d.ReadString()
+	if err != nil {
+		return err
+	}
+	rest, err := d.ReadBytes(d.Remaining())
+	if err != nil {
+		return err
+	}
+	ct, payload, err := protocol.DecodeControl(rest)
+	if err != nil {
+		return err
+	}
+	ce, ok := payload.(*protocol.ClientEnv)
+	if ct != protocol.ControlClientEnv || !ok {
+		return fmt.Errorf("%w: bad start record", ErrInvalidRecording)
+	}
+	rec.Path = path
+	rec.Env = ClientEnv{Viewport: DefaultViewport}.withEnv(ce)
+	return nil
+}
+
+func truncated(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func envPrefs(env ClientEnv) uint8 {
+	var prefs uint8
+	switch env.ColorScheme {
+	case ColorSchemeDark:
+		prefs |= protocol.ClientPrefDark
+	case ColorSchemeLight:
+		prefs |= protocol.ClientPrefLight
+	}
+	if env.ReducedMotion {
+		prefs |= protocol.ClientPrefReducedMotion
+	}
+	return prefs
+}
+
+// =============================================================================
+// Session Recording
+// =============================================================================
+
+// Record starts recording the session to w: the events and client
+// environment changes it receives, and the patches it sends, with their
+// timing. The recording is closed when the session closes. Call it from
+// ServerConfig.OnSessionStart, before the root is mounted, so the recording
+// can be replayed with Server.Replay:
+//
+//	cfg.OnSessionStart = func(ctx context.Context, s *server.Session) {
+//	    if f, err := os.Create("recordings/" + s.ID + ".vrec"); err == nil {
+//	        s.Record(f)
+//	    }
+//	}
+//
+// Recordings contain everything the user typed, so treat them like logs
+// containing personal data.
+func (s *Session) Record(w io.Writer) {
+	s.recorder.Store(NewRecorder(w))
+}
+
+// record appends a record if the session is being recorded.
+func (s *Session) record(kind RecordKind, data []byte) {
+	if r := s.recorder.Load(); r != nil {
+		r.record(kind, data)
+	}
+}
+
+// closeRecording flushes and closes the session's recording, if any.
+func (s *Session) closeRecording() {
+	if r := s.recorder.Swap(nil); r != nil {
+		if err := r.Close(); err != nil {
+			s.logger.Warn("session recording error", "error", err)
+		}
+	}
+}
+
+// =============================================================================
+// Replay
+// =============================================================================
+
+// ReplayReport is the result of replaying a recording.
+type ReplayReport struct {
+	// Inputs is the number of recorded inputs (events and client
+	// environment changes) that were replayed.
+	Inputs int
+
+	// Divergence describes the first input after which the replayed
+	// session sent different patches than the recorded one; nil if the
+	// replay matched.
+	Divergence *Divergence
+}
+
+// Divergence describes where a replay stopped matching its recording.
+type Divergence struct {
+	// Step is the index of the input after which patches differed; -1 for
+	// patches sent while mounting, before any input.
+	Step int
+
+	// Input is the recorded input, or nil for Step -1.
+	Input *Record
+
+	// Want holds the recorded patches, Got the replayed ones.
+	Want []protocol.Patch
+	Got  []protocol.Patch
+}
+
+// String summarizes the report.
+func (r *ReplayReport) String() string {
+	if r.Divergence == nil {
+		return fmt.Sprintf("replayed %d inputs, patches match the recording\n", r.Inputs)
+	}
+	return r.Divergence.String()
+}
+
+// String describes the divergence.
+func (d *Divergence) String() string {
+	var b strings.Builder
+	if d.Input == nil {
+		b.WriteString("patches diverge while mounting\n")
+	} else {
+		fmt.Fprintf(&b, "patches diverge after input %d at %s: %s\n", d.Step, d.Input.At, describeInput(d.Input))
+	}
+	fmt.Fprintf(&b, "  recorded (%d):\n", len(d.Want))
+	writePatches(&b, d.Want)
+	fmt.Fprintf(&b, "  replayed (%d):\n", len(d.Got))
+	writePatches(&b, d.Got)
+	return b.String()
+}
+
+func writePatches(b *strings.Builder, patches []protocol.Patch) {
+	for _, p := range patches {
+		fmt.Fprintf(b, "    %s hid=%s key=%q value=%q\n", p.Op, p.HID, p.Key, p.Value)
+	}
+}
+
+func describeInput(r *Record) string {
+	if r.Kind == RecordEvent {
+		if pe, err := protocol.DecodeEvent(r.Data); err == nil {
+			return fmt.Sprintf("%s on %s", pe.Type, pe.HID)
+		}
+	}
+	return r.Kind.String()
+}
+
+// replaySettle is how long a replay waits for asynchronous work (dispatches
+// and renders) after each input before comparing patches.
+const replaySettle = 20 * time.Millisecond
+
+// Replay mounts the server's root component or route in a headless session,
+// as the recorded session was, feeds it the recorded inputs one at a time,
+// and compares the patches it sends after each input with the recorded ones.
+//
+// Replay is deterministic as far as the application is: work that depends on
+// wall-clock time, randomness or external services may diverge. Session data
+// set by OnSessionStart is not restored, since it runs against the original
+// HTTP request.
+func (s *Server) Replay(rec *Recording) (*ReplayReport, error) {
+	var out strings.Builder
+	recorder := NewRecorder(&out)
+
+	session := newSession(discardConn{}, "", s.config.SessionConfig, s.logger)
+	session.recorder.Store(recorder)
+	if s.router != nil {
+		session.SetRouter(s.router)
+	}
+	if s.config.AssetResolver != nil {
+		session.SetAssetResolver(s.config.AssetResolver)
+	}
+	session.Client().set(rec.Env)
+	defer session.Close()
+
+	if err := s.mountSession(session, rec.Path); err != nil {
+		return nil, err
+	}
+
+	want := patchSteps(rec.Records)
+	report := &ReplayReport{}
+	step := -1
+	check := func() (bool, error) {
+		recorder.Flush()
+		got, err := ReadRecording(strings.NewReader(out.String()))
+		if err != nil {
+			return false, err
+		}
+		steps := patchSteps(got.Records)
+		if !reflect.DeepEqual(want[step+1], steps[step+1]) {
+			d := &Divergence{Step: step, Want: want[step+1], Got: steps[step+1]}
+			if step >= 0 {
+				d.Input = inputAt(rec.Records, step)
+			}
+			report.Divergence = d
+			return false, nil
+		}
+		return true, nil
+	}
+
+	session.settle(replaySettle)
+	if ok, err := check(); !ok || err != nil {
+		return report, err
+	}
+
+	for i := range rec.Records {
+		r := &rec.Records[i]
+		switch r.Kind {
+		case RecordEvent:
+			pe, err := protocol.DecodeEvent(r.Data)
+			if err != nil {
+				return nil, fmt.Errorf("replay: input %d: %w", step+1, err)
+			}
+			session.record(RecordEvent, r.Data)
+			event := eventFromProtocol(pe, session)
+			session.beginWork()
+			session.handleEvent(event)
+			session.endWork()
+		case RecordClientEnv:
+			_, payload, err := protocol.DecodeControl(r.Data)
+			ce, ok := payload.(*protocol.ClientEnv)
+			if err != nil || !ok {
+				return nil, fmt.Errorf("replay: input %d: bad client env", step+1)
+			}
+			session.record(RecordClientEnv, r.Data)
+			session.beginWork()
+			session.executeDispatch(func() {
+				client := session.Client()
+				client.set(client.peek().withEnv(ce))
+			})
+			session.endWork()
+		default:
+			continue
+		}
+		step++
+		report.Inputs++
+		session.settle(replaySettle)
+		if ok, err := check(); !ok || err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// settle runs queued dispatches and renders on the calling goroutine until
+// none arrive for quiet.
+func (s *Session) settle(quiet time.Duration) {
+	timer := time.NewTimer(quiet)
+	defer timer.Stop()
+	for {
+		select {
+		case fn := <-s.dispatchCh:
+			s.beginWork()
+			s.executeDispatch(fn)
+			s.endWork()
+		case <-s.renderCh:
+			s.beginWork()
+			vango.WithCtx(s.createRenderContext(), s.flush)
+			s.endWork()
+		case <-timer.C:
+			return
+		}
+		if !timer.Stop() {
+			<-timer.C
+		}
+		timer.Reset(quiet)
+	}
+}
+
+// patchSteps groups the patches of records by the input they followed.
+// Element 0 holds patches sent before the first input.
+func patchSteps(records []Record) [][]protocol.Patch {
+	steps := [][]protocol.Patch{nil}
+	for _, r := range records {
+		switch r.Kind {
+		case RecordEvent, RecordClientEnv:
+			steps = append(steps, nil)
+		case RecordPatches:
+			pf, err := protocol.DecodePatches(r.Data)
+			if err != nil {
+				continue
+			}
+			last := len(steps) - 1
+			steps[last] = append(steps[last], pf.Patches...)
+		}
+	}
+	return steps
+}
+
+// inputAt returns the n-th input record.
+func inputAt(records []Record, n int) *Record {
+	for i := range records {
+		if records[i].Kind == RecordEvent || records[i].Kind == RecordClientEnv {
+			if n == 0 {
+				return &records[i]
+			}
+			n--
+		}
+	}
+	return nil
+}
+
+// discardConn is the connection of a replayed session. Everything written
+// to it is dropped; the session's recorder captures the patches.
+type discardConn struct{}
+
+func (discardConn) ReadMessage() (int, []byte, error)         { return 0, nil, io.EOF }
+func (discardConn) WriteMessage(int, []byte) error            { return nil }
+func (discardConn) WriteControl(int, []byte, time.Time) error { return nil }
+func (discardConn) SetReadDeadline(time.Time) error           { return nil }
+func (discardConn) SetWriteDeadline(time.Time) error          { return nil }
+func (discardConn) Close() error                              { return nil }
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func counterServer(step int) *Server {
+	s := New(DefaultServerConfig().WithDevMode())
+	s.SetRootComponent(func() Component {
+		return FuncComponent(func() *vdom.VNode {
+			count := vango.NewSignal(0)
+			return vdom.Div(
+				vdom.Button(vdom.OnClick(func() { count.Set(count.Get() + step) }), vdom.Text("inc")),
+				vdom.Span(vdom.Textf("%d", count.Get())),
+			)
+		})
+	})
+	return s
+}
+
+func clickHID(t *testing.T, s *Session) string {
+	t.Helper()
+	for k := range s.handlers {
+		if strings.HasSuffix(k, "_onclick") {
+			return strings.TrimSuffix(k, "_onclick")
+		}
+	}
+	t.Fatal("no onclick handler registered")
+	return ""
+}
+
+func TestRecording_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRecorder(&buf)
+	env := ClientEnv{Viewport: Viewport{800, 600}, TZOffset: -300, Locale: "de-DE", ColorScheme: ColorSchemeDark}
+	r.recordStart("/inbox", env)
+	r.record(RecordEvent, []byte{1, 2, 3})
+	r.record(RecordPatches, []byte{4})
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("ReadRecording failed: %v", err)
+	}
+	if rec.Path != "/inbox" || rec.Env != env {
+		t.Errorf("start = %q %+v, want /inbox %+v", rec.Path, rec.Env, env)
+	}
+	if len(rec.Records) != 2 || rec.Records[0].Kind != RecordEvent || !bytes.Equal(rec.Records[1].Data, []byte{4}) {
+		t.Errorf("records = %+v", rec.Records)
+	}
+}
+
+func TestReadRecording_Invalid(t *testing.T) {
+	if _, err := ReadRecording(strings.NewReader("nope")); !errors.Is(err, ErrInvalidRecording) {
+		t.Errorf("err = %v, want ErrInvalidRecording", err)
+	}
+	if _, err := ReadRecording(strings.NewReader("VREC\x01")); !errors.Is(err, ErrInvalidRecording) {
+		t.Errorf("missing start: err = %v, want ErrInvalidRecording", err)
+	}
+}
+
+func TestSession_RecordAndReplay(t *testing.T) {
+	srv := counterServer(1)
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	var buf bytes.Buffer
+	sess.Record(&buf)
+	if err := srv.mountSession(sess, "/"); err != nil {
+		t.Fatal(err)
+	}
+	hid := clickHID(t, sess)
+	sess.Start()
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		frame := protocol.NewFrame(protocol.FrameEvent, protocol.EncodeEvent(&protocol.Event{
+			Seq:  seq,
+			Type: protocol.EventClick,
+			HID:  hid,
+		}))
+		if err := clientConn.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+			t.Fatalf("WriteMessage failed: %v", err)
+		}
+		readMessage(t, clientConn, protocol.NewReassembler(0))
+	}
+	sess.Close()
+	// The recording is closed once the event loop lets go of the session.
+	deadline := time.Now().Add(2 * time.Second)
+	for sess.recorder.Load() != nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	rec, err := ReadRecording(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadRecording failed: %v", err)
+	}
+	if rec.Path != "/" || len(rec.Records) != 6 {
+		t.Fatalf("recording has path %q and %d records, want / and 6", rec.Path, len(rec.Records))
+	}
+
+	report, err := counterServer(1).Replay(rec)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if report.Divergence != nil || report.Inputs != 3 {
+		t.Fatalf("replay of the same code: %s", report)
+	}
+
+	report, err = counterServer(2).Replay(rec)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	d := report.Divergence
+	if d == nil {
+		t.Fatal("replay of changed code matched the recording")
+	}
+	if d.Step != 0 || d.Want[0].Value != "1" || d.Got[0].Value != "2" {
+		t.Errorf("divergence = %s", d)
+	}
+	if !strings.Contains(d.String(), "after input 0") {
+		t.Errorf("report does not name the input:\n%s", d)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/vango-go/vango/pkg/render"
 	"github.com/vango-go/vango/pkg/routepath"
 	"github.com/vango-go/vango/pkg/vango"
 	"github.com/vango-go/vango/pkg/vdom"
@@ -14,6 +15,7 @@ type routeRootComponent struct {
 	session   *Session
 	page      Component
 	layouts   []LayoutHandler
+	meta      MetaHandler
 	canonPath string
 	query     string
 	params    map[string]string
@@ -54,6 +56,14 @@ func (c *routeRootComponent) Render() *vdom.VNode {
 		result = c.layouts[i](renderCtx, result)
 	}
 
+	// Populate <head> from the route's Meta handler, as SSR does, so the
+	// mounted tree numbers its HIDs the same way as the server-rendered page.
+	if c.meta != nil && result != nil {
+		var head render.PageData
+		c.meta(renderCtx, c.params).ApplyTo(&head)
+		result = render.ApplyHead(result, head)
+	}
+
 	return result
 }
 
@@ -111,6 +121,7 @@ func newRouteRootComponent(session *Session, router Router, fullPath string) (Co
 		session:   session,
 		page:      page,
 		layouts:   match.GetLayoutHandlers(),
+		meta:      metaHandlerFor(match),
 		canonPath: canonPath,
 		query:     query,
 		params:    params,
@@ -19,6 +19,7 @@ import (
 	"github.com/vango-go/vango/pkg/auth"
 	"github.com/vango-go/vango/pkg/protocol"
 	"github.com/vango-go/vango/pkg/routepath"
+	"github.com/vango-go/vango/pkg/vango"
 )
 
 // Server is the main HTTP/WebSocket server for Vango.
@@ -49,6 +50,12 @@ type Server struct {
 	// WebSocket upgrader
 	upgrader websocket.Upgrader
 
+	// Open HTTP fallback (SSE + POST) connections
+	fallbackConns fallbackConns
+
+	// Detaches ServerConfig.GlobalSignalBackend from the global signals
+	detachGlobalSignals func()
+
 	// Middleware
 	middleware []Middleware
 
@@ -167,6 +174,16 @@ func New(config *ServerConfig) *Server {
 		logger:       logger,
 	}
 
+	if config.BroadcastTransport != nil {
+		s.sessions.SetBroadcaster(NewBroadcaster(config.BroadcastTransport, logger))
+	}
+	if config.PrefStore != nil {
+		s.sessions.SetPrefStore(config.PrefStore)
+	}
+	if config.GlobalSignalBackend != nil {
+		s.detachGlobalSignals = vango.AttachGlobalSignalBackend(config.GlobalSignalBackend)
+	}
+
 	return s
 }
 
@@ -266,6 +283,12 @@ func (s *Server) WebSocketHandler() http.Handler {
 	return http.HandlerFunc(s.HandleWebSocket)
 }
 
+// HTTPFallbackHandler returns an http.Handler for the SSE + POST fallback
+// transport only. Mount it at /_vango/sse next to WebSocketHandler.
+func (s *Server) HTTPFallbackHandler() http.Handler {
+	return http.HandlerFunc(s.HandleHTTPFallback)
+}
+
 // ServeHTTP implements http.Handler.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Check for WebSocket upgrade
@@ -274,12 +297,24 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// HTTP fallback transport for clients that cannot upgrade
+	if r.URL.Path == "/_vango/sse" {
+		s.HandleHTTPFallback(w, r)
+		return
+	}
+
 	// Internal assets
 	if r.URL.Path == "/_vango/client.js" {
 		s.serveThinClient(w, r)
 		return
 	}
 
+	// Reactive graph inspector (DevMode only)
+	if r.URL.Path == "/_vango/inspect" {
+		s.serveInspector(w, r)
+		return
+	}
+
 	// Per Section 1.2.4 (Path Canonicalization):
 	// HTTP requests with non-canonical paths should redirect with 308 Permanent Redirect.
 	// This ensures consistent URL handling and prevents duplicate content issues.
@@ -330,6 +365,15 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// Set connection options
 	conn.SetReadLimit(s.config.SessionConfig.MaxMessageSize)
+
+	s.serveConn(conn, r)
+}
+
+// serveConn runs the handshake on a new connection and attaches it to a new
+// or resumed session. r is the request that opened the connection; it
+// carries the cookies, path and client address used by the handshake.
+// Both the WebSocket and the HTTP fallback transport end up here.
+func (s *Server) serveConn(conn Conn, r *http.Request) {
 	conn.SetReadDeadline(time.Now().Add(s.config.SessionConfig.HandshakeTimeout))
 
 	// Wait for handshake
@@ -370,6 +414,20 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Speak the newest protocol version both sides know, so tabs running an
+	// older thin client keep working during a rolling deploy. Clients outside
+	// the supported range reload to fetch a matching thin client.
+	version, status := protocol.NegotiateVersion(hello.Version, protocol.MinSupportedVersion, protocol.CurrentVersion)
+	if status != protocol.HandshakeOK {
+		s.logger.Warn("protocol version not supported",
+			"client_version", hello.Version.String(),
+			"min_version", protocol.MinSupportedVersion.String(),
+			"max_version", protocol.CurrentVersion.String())
+		s.sendHandshakeVersionError(conn, status)
+		conn.Close()
+		return
+	}
+
 	// Validate CSRF if configured
 	if s.csrfSecret != nil && !s.validateCSRF(r, hello.CSRFToken) {
 		s.sendHandshakeError(conn, protocol.HandshakeInvalidCSRF)
@@ -508,6 +566,15 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 		// Resume existing session with soft remount
 		session.Resume(conn, uint64(hello.LastSeq))
+		session.setProtocolVersion(version)
+
+		// Components keep their Client signals; refresh the values on the
+		// session loop since it may still be running.
+		session.Dispatch(func() {
+			client := session.Client()
+			client.set(client.peek().withHello(hello))
+			session.startPrefs(hello.PrefValues)
+		})
 
 		// Set asset resolver if configured
 		if s.config.AssetResolver != nil {
@@ -523,7 +590,11 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		s.sendServerHello(conn, session)
+		s.sendServerHello(conn, session, session.negotiateCodec(hello.Codecs))
+
+		// The client dropped its window and document subscriptions at the
+		// handshake; components keep theirs, so subscribe it again.
+		session.globalEvents.resend()
 
 		// Send ResyncFull to ensure client DOM matches (fallback for HID mismatch)
 		if err := session.SendResyncFull(); err != nil {
@@ -575,6 +646,8 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	session.setProtocolVersion(version)
+
 	// Wire router to session for route-based navigation (Phase 7: Routing)
 	// This enables EventNavigate and ctx.Navigate() to work
 	if s.router != nil {
@@ -587,6 +660,9 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		session.SetAssetResolver(s.config.AssetResolver)
 	}
 
+	// Nothing is mounted yet, so the environment can be set directly.
+	session.Client().set(ClientEnvFromRequest(r).withHello(hello))
+
 	// ═══════════════════════════════════════════════════════════════════════════
 	// THE CONTEXT BRIDGE (Phase 10)
 	// Copy data from dying HTTP context to living session.
@@ -602,36 +678,48 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send server hello
-	s.sendServerHello(conn, session)
+	s.sendServerHello(conn, session, session.negotiateCodec(hello.Codecs))
+
+	// Load preferences before the first render; the loop is not running yet.
+	session.startPrefs(hello.PrefValues)
+
+	if err := s.mountSession(session, r.URL.Query().Get("path")); err != nil {
+		s.logger.Warn("initial route mount failed", "path", r.URL.Query().Get("path"), "error", err)
+	}
+
+	// Start session loops
+	session.Start()
+}
+
+// mountSession mounts the root component of a new session. Prefer an
+// explicit root factory, otherwise mount the route for initialPath.
+func (s *Server) mountSession(session *Session, initialPath string) error {
+	if initialPath == "" {
+		initialPath = "/"
+	}
+	// Never treat internal endpoints as page routes.
+	if strings.HasPrefix(initialPath, "/_vango/") {
+		initialPath = "/"
+	}
+	if r := session.recorder.Load(); r != nil {
+		r.recordStart(initialPath, session.Client().peek())
+	}
 
-	// Mount root component. Prefer an explicit root factory, otherwise mount the current route.
 	if s.rootComponent != nil {
 		session.MountRoot(s.rootComponent())
 	} else if s.router != nil {
-		initialPath := r.URL.Query().Get("path")
-		if initialPath == "" {
-			initialPath = "/"
-		}
-		// Never treat internal endpoints as page routes.
-		if strings.HasPrefix(initialPath, "/_vango/") {
-			initialPath = "/"
-		}
-
 		root, canonicalPath, err := newRouteRootComponent(session, s.router, initialPath)
 		if err != nil {
-			s.logger.Warn("initial route mount failed", "path", initialPath, "error", err)
-		} else {
-			session.CurrentRoute = canonicalPath
-			session.MountRoot(root)
+			return err
 		}
+		session.CurrentRoute = canonicalPath
+		session.MountRoot(root)
 	}
-
-	// Start session loops
-	session.Start()
+	return nil
 }
 
 // sendHandshakeError sends a handshake error response.
-func (s *Server) sendHandshakeError(conn *websocket.Conn, status protocol.HandshakeStatus) {
+func (s *Server) sendHandshakeError(conn Conn, status protocol.HandshakeStatus) {
 	hello := protocol.NewServerHelloError(status)
 	payload := protocol.EncodeServerHello(hello)
 	frame := protocol.NewFrame(protocol.FrameHandshake, payload)
@@ -640,7 +728,18 @@ func (s *Server) sendHandshakeError(conn *websocket.Conn, status protocol.Handsh
 	conn.WriteMessage(websocket.BinaryMessage, frame.Encode())
 }
 
-func (s *Server) sendHandshakeErrorWithReason(conn *websocket.Conn, status protocol.HandshakeStatus, reason AuthExpiredReason) {
+// sendHandshakeVersionError rejects a client's protocol version, telling it
+// the range of versions the server speaks.
+func (s *Server) sendHandshakeVersionError(conn Conn, status protocol.HandshakeStatus) {
+	hello := protocol.NewServerHelloVersionError(status)
+	payload := protocol.EncodeServerHello(hello)
+	frame := protocol.NewFrame(protocol.FrameHandshake, payload)
+
+	conn.SetWriteDeadline(time.Now().Add(s.config.SessionConfig.WriteTimeout))
+	conn.WriteMessage(websocket.BinaryMessage, frame.Encode())
+}
+
+func (s *Server) sendHandshakeErrorWithReason(conn Conn, status protocol.HandshakeStatus, reason AuthExpiredReason) {
 	hello := protocol.NewServerHelloErrorWithReason(status, uint8(reason))
 	payload := protocol.EncodeServerHello(hello)
 	frame := protocol.NewFrame(protocol.FrameHandshake, payload)
@@ -649,13 +748,17 @@ func (s *Server) sendHandshakeErrorWithReason(conn *websocket.Conn, status proto
 	conn.WriteMessage(websocket.BinaryMessage, frame.Encode())
 }
 
-// sendServerHello sends a successful handshake response.
-func (s *Server) sendServerHello(conn *websocket.Conn, session *Session) {
+// sendServerHello sends a successful handshake response announcing the
+// negotiated payload codec.
+func (s *Server) sendServerHello(conn Conn, session *Session, codec protocol.Codec) {
 	hello := protocol.NewServerHello(
 		session.ID,
 		uint32(session.sendSeq.Load()),
 		uint64(time.Now().UnixMilli()),
 	)
+	hello.Version = session.ProtocolVersion()
+	hello.SetCodec(codec)
+	hello.Flags |= protocol.ServerFlagBinaryBlobs
 	payload := protocol.EncodeServerHello(hello)
 	frame := protocol.NewFrame(protocol.FrameHandshake, payload)
 
@@ -810,6 +913,15 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 	// Close all sessions first
 	s.sessions.Shutdown()
+	s.sessions.Broadcaster().Close()
+
+	// Stop synchronizing global signals through this server's backend
+	if s.detachGlobalSignals != nil {
+		s.detachGlobalSignals()
+		if err := s.config.GlobalSignalBackend.Close(); err != nil {
+			s.logger.Error("global signal backend close error", "error", err)
+		}
+	}
 
 	// Shutdown HTTP server
 	if s.httpServer != nil {
@@ -823,6 +935,22 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// Broadcast sends payload to every session subscribed to topic on every
+// instance sharing the configured BroadcastTransport. See Topic.
+func (s *Server) Broadcast(topic string, payload any) error {
+	return s.sessions.Broadcaster().Broadcast(topic, payload)
+}
+
+// BroadcastToUser sends payload to the sessions of userID subscribed to topic.
+func (s *Server) BroadcastToUser(userID, topic string, payload any) error {
+	return s.sessions.Broadcaster().BroadcastToUser(userID, topic, payload)
+}
+
+// BroadcastToTenant sends payload to the sessions of tenantID subscribed to topic.
+func (s *Server) BroadcastToTenant(tenantID, topic string, payload any) error {
+	return s.sessions.Broadcaster().BroadcastToTenant(tenantID, topic, payload)
+}
+
 // Sessions returns the session manager.
 func (s *Server) Sessions() *SessionManager {
 	return s.sessions
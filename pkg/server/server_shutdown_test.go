@@ -7,6 +7,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/vango-go/vango/pkg/vango"
 )
 
 func TestServer_WebSocketHandler_UpgradeErrorDoesNotPanic(t *testing.T) {
@@ -49,3 +51,28 @@ func TestServer_Shutdown_StopsHTTPServerAndSessions(t *testing.T) {
 		t.Fatal("timeout waiting for Serve() to return")
 	}
 }
+
+func TestServer_Shutdown_DetachesGlobalSignalBackend(t *testing.T) {
+	backend := vango.NewMemoryGlobalSignalBackend()
+	config := DefaultServerConfig().WithDevMode()
+	config.GlobalSignalBackend = backend
+	s := New(config)
+
+	sig := vango.NewGlobalSignal(0, vango.SyncKey("test_server_shutdown"))
+	other := vango.NewGlobalSignal(0, vango.SyncKey("test_server_shutdown"))
+	sig.Set(3)
+	deadline := time.Now().Add(2 * time.Second)
+	for other.Peek() != 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("value not synchronized through the server's backend")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error: %v", err)
+	}
+	if _, err := backend.Subscribe("k", func([]byte) {}); err != vango.ErrGlobalSignalBackendClosed {
+		t.Errorf("Subscribe after Shutdown = %v, want ErrGlobalSignalBackendClosed", err)
+	}
+}
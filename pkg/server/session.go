@@ -20,11 +20,14 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/vango-go/vango/pkg/assets"
 	"github.com/vango-go/vango/pkg/auth"
+	"github.com/vango-go/vango/pkg/features/islands"
 	"github.com/vango-go/vango/pkg/features/store"
+	"github.com/vango-go/vango/pkg/pref"
 	"github.com/vango-go/vango/pkg/protocol"
 	"github.com/vango-go/vango/pkg/render"
 	"github.com/vango-go/vango/pkg/routepath"
 	"github.com/vango-go/vango/pkg/session"
+	"github.com/vango-go/vango/pkg/upload"
 	"github.com/vango-go/vango/pkg/urlparam"
 	"github.com/vango-go/vango/pkg/vango"
 	"github.com/vango-go/vango/pkg/vdom"
@@ -66,7 +69,7 @@ type Session struct {
 	CurrentRoute string // Current page route for restoration
 
 	// Connection
-	conn   *websocket.Conn
+	conn   Conn
 	mu     sync.Mutex // Protects conn writes
 	closed atomic.Bool
 	// stateMu guards component/handler maps and the component tree.
@@ -92,11 +95,21 @@ type Session struct {
 	// Stores recently sent patch frames for replay on client reconnection.
 	patchHistory *PatchHistory
 
+	// Frame compression negotiated in the handshake. codec is guarded by mu;
+	// the compressor is created lazily and replaced on every handshake.
+	codec      protocol.Codec
+	compressor atomic.Pointer[protocol.Compressor]
+
 	// Component state
 	root          *ComponentInstance              // Root component
 	allComponents map[*ComponentInstance]struct{} // ALL mounted components (for dirty checking)
 	components    map[string]*ComponentInstance   // HID -> component that owns element
 	handlers      map[string]Handler              // HID_eventType -> event handler
+	uploads       map[string]upload.Live          // HID -> el.OnUpload target, read by the read loop
+
+	// panicSource is the innermost component whose render panicked, for the
+	// ErrorBoundary that catches the panic. Only used on the session loop.
+	panicSource *ComponentInstance
 
 	// Reactive ownership
 	owner *vango.Owner
@@ -148,6 +161,32 @@ type Session struct {
 	pendingURLPatches []protocol.Patch
 	urlPatchMu        sync.Mutex
 
+	// JS island messaging. Outgoing messages are queued with the URL patches;
+	// incoming Island events are delivered to registered handlers.
+	islands *islands.Bridge
+
+	// Window and document event subscriptions made with vango.OnWindow and
+	// vango.OnDocument.
+	globalEvents *globalEvents
+
+	// Calls to client hook methods waiting for a result; see CallHook.
+	hookCalls hookCalls
+
+	// Files streaming in over FrameBlob frames, by client upload ID.
+	blobs  map[uint64]*blobUpload
+	blobMu sync.Mutex
+
+	// Browser environment reported by the client; see Client().
+	client     *Client
+	clientOnce sync.Once
+
+	// Recording of the session's traffic, if enabled with Record.
+	recorder atomic.Pointer[Recorder]
+
+	// Protocol version negotiated with the current connection; guarded by
+	// mu. Zero until the first handshake, which means CurrentVersion.
+	version protocol.ProtocolVersion
+
 	// Storm budget tracker (Phase 16)
 	stormBudget *vango.StormBudgetTracker
 
@@ -168,6 +207,18 @@ type Session struct {
 
 	// Asset resolver for fingerprinted asset paths (DX Improvements)
 	assetResolver assets.Resolver
+
+	// broadcaster delivers topic broadcasts to this session (see Topic).
+	broadcaster *Broadcaster
+
+	// prefs holds the session's preference values (see pkg/pref).
+	prefs     *pref.Manager
+	prefStore pref.Store
+
+	// prefMu guards the subscription to the user's preference changes.
+	prefMu          sync.Mutex
+	prefTopicUser   string
+	prefUnsubscribe func()
 }
 
 // IsDetached reports whether the session currently has no active WebSocket
@@ -191,7 +242,7 @@ func generateSessionID() string {
 }
 
 // newSession creates a new session with the given connection.
-func newSession(conn *websocket.Conn, userID string, config *SessionConfig, logger *slog.Logger) *Session {
+func newSession(conn Conn, userID string, config *SessionConfig, logger *slog.Logger) *Session {
 	now := time.Now()
 	id := generateSessionID()
 
@@ -203,6 +254,7 @@ func newSession(conn *websocket.Conn, userID string, config *SessionConfig, logg
 		conn:          conn,
 		allComponents: make(map[*ComponentInstance]struct{}),
 		handlers:      make(map[string]Handler),
+		uploads:       make(map[string]upload.Live),
 		components:    make(map[string]*ComponentInstance),
 		owner:         vango.NewOwner(nil),
 		hidGen:        vdom.NewHIDGenerator(),
@@ -223,12 +275,26 @@ func newSession(conn *websocket.Conn, userID string, config *SessionConfig, logg
 	// Also set vango.SessionSignalStoreKey for vango.NewSharedSignal support.
 	s.owner.SetValue(vango.SessionSignalStoreKey, sessionStore)
 
+	// Initialize persist store for signals created with vango.PersistKey.
+	// Their values are included in Serialize and restored by Deserialize.
+	s.owner.SetValue(vango.SignalPersistStoreKey, vango.NewSignalPersistStore())
+
 	// Initialize URL navigator for URLParam support.
 	// URLParam.Set() will queue patches via queueURLPatch, which are then
 	// sent along with DOM patches in renderDirty().
 	navigator := urlparam.NewNavigator(s.queueURLPatch)
 	s.owner.SetValue(urlparam.NavigatorKey, navigator)
 
+	// Initialize the island bridge for SendToIsland/OnIslandMessage.
+	s.islands = islands.NewBridge(s.queueURLPatch)
+	s.owner.SetValue(islands.BridgeKey, s.islands)
+
+	// Initialize the registry for OnWindow/OnDocument subscriptions.
+	s.initGlobalEvents()
+
+	// Initialize the preference manager for pref.Pref values.
+	s.initPrefs()
+
 	// Initialize prefetch system (Phase 7: Routing, Section 8)
 	// Per Section 8.2: Cache result per session with TTL and LRU eviction
 	// Per Section 8.5: Rate limit 5 requests/second per session
@@ -267,6 +333,7 @@ func (s *Session) MountRoot(component Component) {
 
 	// Collect handlers from all mounted component instances.
 	s.handlers = make(map[string]Handler)
+	s.uploads = make(map[string]upload.Live)
 	s.components = make(map[string]*ComponentInstance)
 	s.collectHandlersFromInstancesLocked(s.root)
 
@@ -372,6 +439,9 @@ func (s *Session) collectHandlersNoMountLocked(node *vdom.VNode, instance *Compo
 				eventType := strings.ToLower(key)
 				handlerKey := node.HID + "_" + eventType
 				s.handlers[handlerKey] = handler
+				if live, ok := value.(upload.Live); ok {
+					s.uploads[node.HID] = live
+				}
 				continue
 			}
 
@@ -449,6 +519,9 @@ func (s *Session) collectHandlersLocked(node *vdom.VNode, instance *ComponentIns
 				eventType := strings.ToLower(key) // "onclick", "onmouseenter"
 				handlerKey := node.HID + "_" + eventType
 				s.handlers[handlerKey] = handler
+				if live, ok := value.(upload.Live); ok {
+					s.uploads[node.HID] = live
+				}
 				if DebugMode {
 					fmt.Printf("[HANDLER] Registered %s on %s (%s) -> key=%s\n", key, node.HID, node.Tag, handlerKey)
 				}
@@ -512,6 +585,23 @@ func (s *Session) handleEvent(event *Event) {
 		return
 	}
 
+	// Island messages are routed by island ID rather than by HID handler key.
+	if event.Type == protocol.EventIsland {
+		s.handleEventIsland(event)
+		return
+	}
+
+	// Window and document events are routed by subscription ID.
+	if strings.HasPrefix(event.HID, globalIDPrefix) {
+		if l := s.globalEvents.lookup(event.HID); l != nil {
+			s.handleEventGlobal(event, l)
+		} else {
+			// The subscription may have ended while the event was in flight.
+			s.logger.Debug("global event dropped: no listener", "id", event.HID)
+		}
+		return
+	}
+
 	// Special handling for EventCustom (0xFF) - check for prefetch events
 	// Per Section 8.1, prefetch events come as CUSTOM with name="prefetch"
 	if event.Type == protocol.EventCustom {
@@ -608,6 +698,35 @@ func (s *Session) handleEventNavigate(event *Event) {
 	})
 }
 
+// handleEventIsland delivers a message from a JS island to the handler
+// registered with islands.OnIslandMessage for the island's ID.
+func (s *Session) handleEventIsland(event *Event) {
+	data, ok := event.Payload.(*protocol.IslandEventData)
+	if !ok || data == nil {
+		s.logger.Warn("invalid island event payload")
+		s.sendErrorMessage(protocol.ErrInvalidEvent, "Invalid island event")
+		return
+	}
+
+	ctx := s.createEventContext(event)
+	vango.WithCtx(ctx, func() {
+		vango.WithOwner(s.owner, func() {
+			var delivered bool
+			s.safeExecute(func(*Event) {
+				delivered = s.islands.Deliver(event.HID, data.Data)
+			}, event)
+			if !delivered {
+				// The island may have unmounted while the message was in flight.
+				s.logger.Debug("island message dropped: no handler", "island", event.HID)
+				return
+			}
+
+			// Commit render + effects after handler
+			s.flush()
+		})
+	})
+}
+
 // safeExecute runs a handler with panic recovery.
 func (s *Session) safeExecute(handler Handler, event *Event) {
 	defer func() {
@@ -673,6 +792,10 @@ func (s *Session) renderDirty() {
 
 	var dirty []*ComponentInstance
 	for _, comp := range allComponents {
+		if comp.Owner == nil {
+			// Disposed by an ErrorBoundary earlier in this pass.
+			continue
+		}
 		if comp.IsDirty() {
 			dirty = append(dirty, comp)
 			comp.ClearDirty()
@@ -803,19 +926,25 @@ func (s *Session) processPendingNavigation() {
 func (s *Session) renderComponent(comp *ComponentInstance) []vdom.Patch {
 	s.stateMu.Lock()
 	defer s.stateMu.Unlock()
+	return s.renderComponentLocked(comp)
+}
 
+// renderComponentLocked requires stateMu to be held.
+func (s *Session) renderComponentLocked(comp *ComponentInstance) []vdom.Patch {
 	// Get old tree
 	oldTree := comp.LastTree()
 
-	// Render new tree
-	newTree := comp.Render()
-
-	// Expand nested component nodes (KindComponent) into their rendered output.
-	// SSR does this inline during HTML generation; WS must do the same so:
+	// Render new tree, expanding nested component nodes (KindComponent) into
+	// their rendered output. SSR does this inline during HTML generation; WS
+	// must do the same so:
 	//   - HIDs are assigned in the same structural order
 	//   - diffing operates on the real element tree
 	//   - event handlers are collected for nested components
-	s.rerenderChildren(newTree, comp)
+	newTree, boundary := s.renderTreeCaught(comp)
+	if boundary != nil {
+		// An ErrorBoundary caught a panic below it; it now renders its fallback.
+		return s.renderComponentLocked(boundary)
+	}
 
 	// Try to copy HIDs from old tree to preserve them
 	// If structure changed significantly, this will return false for some nodes
@@ -874,6 +1003,7 @@ func (s *Session) clearSubtreeHandlersLocked(root *ComponentInstance) {
 			continue
 		}
 		delete(s.components, hid)
+		delete(s.uploads, hid)
 		prefix := hid + "_"
 		for key := range s.handlers {
 			if strings.HasPrefix(key, prefix) {
@@ -904,6 +1034,7 @@ func (s *Session) clearComponentHandlersLocked(comp *ComponentInstance) {
 	// Then remove the HIDs and all associated handlers
 	for _, hid := range hidsToRemove {
 		delete(s.components, hid)
+		delete(s.uploads, hid)
 		// Delete ALL handlers with this HID as prefix (e.g., "h1_onclick", "h1_hook_reorder")
 		prefix := hid + "_"
 		for key := range s.handlers {
@@ -939,6 +1070,7 @@ func (s *Session) RebuildHandlers() error {
 
 	// 1. Clear handlers and component mappings (NOT owner!)
 	s.handlers = make(map[string]Handler)
+	s.uploads = make(map[string]upload.Live)
 	s.components = make(map[string]*ComponentInstance)
 
 	// 2. Reset HID generator to 0 (will produce h1, h2... matching SSR)
@@ -970,10 +1102,24 @@ func (s *Session) RebuildHandlers() error {
 // This uses existing component instances (preserving their state).
 // Caller must hold stateMu.
 func (s *Session) rerenderTree(instance *ComponentInstance) *vdom.VNode {
+	if b := boundaryOf(instance); b != nil {
+		return s.renderBoundary(instance, b)
+	}
+
+	// If this render panics, remember the innermost component that did so
+	// for the ErrorBoundary that catches it.
+	rendered := false
+	defer func() {
+		if !rendered && s.panicSource == nil {
+			s.panicSource = instance
+		}
+	}()
+
 	tree := instance.Render()
 
 	// Recursively handle child components in the rendered tree
 	s.rerenderChildren(tree, instance)
+	rendered = true
 
 	return tree
 }
@@ -1116,6 +1262,9 @@ func (s *Session) collectHandlersPreservingLocked(node *vdom.VNode, instance *Co
 				eventType := strings.ToLower(key)
 				handlerKey := node.HID + "_" + eventType
 				s.handlers[handlerKey] = handler
+				if live, ok := value.(upload.Live); ok {
+					s.uploads[node.HID] = live
+				}
 				continue
 			}
 
@@ -1179,14 +1328,15 @@ func (s *Session) SendResyncFull() error {
 		return ErrSessionClosed
 	}
 
-	s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
-	if err := s.conn.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+	// Large pages exceed one frame and go out as fragments
+	frameData := frame.EncodeMessage()
+	if err := s.writeMessageLocked(frameData); err != nil {
 		return fmt.Errorf("write resync full: %w", err)
 	}
 
 	s.logger.Debug("sent ResyncFull",
 		"html_size", len(html),
-		"frame_size", len(frame.Encode()))
+		"frame_size", len(frameData))
 
 	return nil
 }
@@ -1238,6 +1388,9 @@ func (s *Session) sendPatchesWithURL(vdomPatches []vdom.Patch, urlPatches []prot
 		protocolPatches = append(protocolPatches, urlPatches...)
 	}
 
+	// Leave out operations an older client does not understand
+	protocolPatches = protocol.DowngradePatches(protocolPatches, s.versionLocked())
+
 	// Create patches frame
 	pf := &protocol.PatchesFrame{
 		Seq:     seq,
@@ -1246,18 +1399,35 @@ func (s *Session) sendPatchesWithURL(vdomPatches []vdom.Patch, urlPatches []prot
 
 	// Encode payload
 	payload := protocol.EncodePatches(pf)
+	s.record(RecordPatches, payload)
 
 	// Create frame
 	frame := protocol.NewFrame(protocol.FramePatches, payload)
 
-	// Encode once for sending
-	frameData := frame.Encode()
+	// Encode for history, fragmenting payloads over MaxPayloadSize
+	frameData := frame.EncodeMessage()
 
-	// Set write deadline
-	s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
+	// Compress and fragment for the wire. History keeps the uncompressed
+	// frame so replays can be compressed against the window current at
+	// that time.
+	wireData, err := s.encodeFrameLocked(frame)
+	if err != nil {
+		s.logger.Error("compress error", "error", err)
+		s.mu.Unlock()
+		s.Close()
+		return
+	}
+
+	// Nodes the client cannot decode, such as portals for clients older
+	// than 2.2, cannot be left out like head patches can.
+	if v := s.versionLocked(); !v.CanDecodePatches(protocolPatches) {
+		s.logger.Warn("patches need a newer client protocol version, forcing reload",
+			"client_version", v.String())
+		wireData = versionReloadFrame()
+	}
 
 	// Write to WebSocket
-	err := s.conn.WriteMessage(websocket.BinaryMessage, frameData)
+	err = s.writeMessageLocked(wireData)
 	if err != nil {
 		s.logger.Error("write error", "error", err)
 		s.mu.Unlock()
@@ -1272,7 +1442,7 @@ func (s *Session) sendPatchesWithURL(vdomPatches []vdom.Patch, urlPatches []prot
 	}
 
 	// Update metrics
-	s.bytesSent.Add(uint64(len(frameData)))
+	s.bytesSent.Add(uint64(len(wireData)))
 	s.patchCount.Add(uint64(len(protocolPatches)))
 
 	s.logger.Debug("sent patches",
@@ -1405,6 +1575,29 @@ func (s *Session) sendAuthCommand(cmd *protocol.AuthCommand) {
 	}
 }
 
+// ProtocolVersion returns the protocol version spoken with the client.
+func (s *Session) ProtocolVersion() protocol.ProtocolVersion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versionLocked()
+}
+
+// setProtocolVersion records the version negotiated in a handshake.
+func (s *Session) setProtocolVersion(v protocol.ProtocolVersion) {
+	s.mu.Lock()
+	s.version = v
+	s.mu.Unlock()
+}
+
+// versionLocked returns the negotiated version, or CurrentVersion before
+// the first handshake. The caller must hold s.mu.
+func (s *Session) versionLocked() protocol.ProtocolVersion {
+	if s.version == (protocol.ProtocolVersion{}) {
+		return protocol.CurrentVersion
+	}
+	return s.version
+}
+
 // Close gracefully closes the session.
 func (s *Session) Close() {
 	if s.closed.Swap(true) {
@@ -1485,6 +1678,9 @@ func (s *Session) finalizeClose() {
 		s.components = nil
 		s.allComponents = nil
 
+		s.closeRecording()
+		s.unsubscribePrefs()
+
 		s.logger.Info("session closed",
 			"events", s.eventCount.Load(),
 			"patches", s.patchCount.Load(),
@@ -1676,6 +1872,10 @@ func (s *Session) MemoryUsage() int64 {
 		size += s.patchHistory.MemoryUsage()
 	}
 
+	if s.compressor.Load() != nil {
+		size += compressorMemory
+	}
+
 	if s.prefetchCache != nil {
 		size += s.prefetchCache.MemoryUsage()
 	}
@@ -1706,9 +1906,10 @@ func (s *Session) MemoryUsage() int64 {
 	return size
 }
 
-// Conn returns the underlying WebSocket connection.
+// Conn returns the underlying connection: a *websocket.Conn, or the HTTP
+// fallback transport's connection.
 // Use with caution - prefer session methods when possible.
-func (s *Session) Conn() *websocket.Conn {
+func (s *Session) Conn() Conn {
 	return s.conn
 }
 
@@ -2085,9 +2286,7 @@ func (s *Session) RestoreData(values map[string]any) {
 //   - Creation and last active timestamps
 //   - Current route for page restoration
 //   - All session data values (from Get/Set)
-//
-// Note: Signals are persisted separately when they have PersistKey options.
-// Transient signals are not serialized.
+//   - Signals created with a PersistKey option (Transient signals are skipped)
 func (s *Session) Serialize() ([]byte, error) {
 	// Convert session data to JSON-friendly format
 	var values map[string]json.RawMessage
@@ -2123,6 +2322,7 @@ func (s *Session) Serialize() ([]byte, error) {
 		CreatedAt:  s.CreatedAt,
 		LastActive: s.LastActive,
 		Values:     values,
+		Signals:    s.signalPersistStore().Snapshot(),
 		Route:      s.CurrentRoute,
 	}
 
@@ -2138,10 +2338,10 @@ func (s *Session) Serialize() ([]byte, error) {
 //   - Timestamps are restored
 //   - Current route is restored for page navigation
 //   - All session data values are restored
+//   - Persisted signal values are queued for restoration
 //
-// Note: Signals are restored on-demand when components re-render and
-// call NewSignal with PersistKey options. The restored signal values
-// are matched by their persist keys.
+// Signals are restored on-demand when components render and call NewSignal
+// with a matching PersistKey. Already-mounted signals are updated in place.
 func (s *Session) Deserialize(data []byte) error {
 	ss, err := session.Deserialize(data)
 	if err != nil {
@@ -2167,9 +2367,21 @@ func (s *Session) Deserialize(data []byte) error {
 		s.RestoreData(values)
 	}
 
+	s.signalPersistStore().Restore(ss.Signals)
+
 	return nil
 }
 
+// signalPersistStore returns the session's persisted signal registry.
+// Returns nil if the session was constructed without one.
+func (s *Session) signalPersistStore() *vango.SignalPersistStore {
+	if s.owner == nil {
+		return nil
+	}
+	ps, _ := s.owner.GetValueLocal(vango.SignalPersistStoreKey).(*vango.SignalPersistStore)
+	return ps
+}
+
 // =============================================================================
 // Route Navigation (Phase 7: Routing)
 // =============================================================================
@@ -2235,9 +2447,9 @@ func (s *Session) HandleNavigate(path string, replace bool) error {
 		return errors.New("route not found")
 	}
 
-	// Send NAV_* patch + DOM patches in one frame
+	// Send NAV_* patch + DOM patches + head patches in one frame
 	// Convert vdom.Patch to protocol.Patch
-	allPatches := make([]protocol.Patch, 0, len(result.Patches)+1)
+	allPatches := make([]protocol.Patch, 0, len(result.Patches)+len(result.HeadPatches)+1)
 
 	// NAV patch first
 	allPatches = append(allPatches, result.NavPatch)
@@ -2257,6 +2469,9 @@ func (s *Session) HandleNavigate(path string, replace bool) error {
 		}
 	}
 
+	// Then document head updates from the route's Meta handler
+	allPatches = append(allPatches, result.HeadPatches...)
+
 	s.SendPatches(allPatches)
 
 	s.logger.Debug("navigation completed",
@@ -2482,13 +2697,14 @@ func (s *Session) PrefetchCache() *PrefetchCache {
 // The session has all fields initialized except conn.
 func NewMockSession() *Session {
 	prefetchConfig := DefaultPrefetchConfig()
-	return &Session{
+	s := &Session{
 		ID:                "test-session-id",
 		UserID:            "",
 		CreatedAt:         time.Now(),
 		LastActive:        time.Now(),
 		allComponents:     make(map[*ComponentInstance]struct{}),
 		handlers:          make(map[string]Handler),
+		uploads:           make(map[string]upload.Live),
 		components:        make(map[string]*ComponentInstance),
 		owner:             vango.NewOwner(nil),
 		hidGen:            vdom.NewHIDGenerator(),
@@ -2504,4 +2720,10 @@ func NewMockSession() *Session {
 		prefetchLimiter:   NewPrefetchRateLimiter(prefetchConfig.RateLimit),
 		prefetchSemaphore: NewPrefetchSemaphore(prefetchConfig.SessionConcurrency),
 	}
+	s.owner.SetValue(vango.SignalPersistStoreKey, vango.NewSignalPersistStore())
+	s.islands = islands.NewBridge(s.queueURLPatch)
+	s.owner.SetValue(islands.BridgeKey, s.islands)
+	s.initGlobalEvents()
+	s.initPrefs()
+	return s
 }
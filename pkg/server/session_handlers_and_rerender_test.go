@@ -94,3 +94,52 @@ func TestSession_rerenderChildren_CreatesReusesAndDisposesInstances(t *testing.T
 	}
 }
 
+
+type portalComponent struct {
+	open *bool
+}
+
+func (c portalComponent) Render() *vdom.VNode {
+	return vdom.Div(
+		vdom.If(*c.open, vdom.Portal("#modals",
+			vdom.Button(vdom.OnClick(func() {}), vdom.Text("Close")),
+			&vdom.VNode{Kind: vdom.KindComponent, Comp: onClickComponent{}},
+		)),
+	)
+}
+
+func TestSession_PortalHandlersCollectedAndCleared(t *testing.T) {
+	open := true
+	s := NewMockSession()
+	s.MountRoot(portalComponent{open: &open})
+
+	portal := s.root.LastTree().Children[0]
+	if portal.Kind != vdom.KindPortal || portal.HID == "" {
+		t.Fatalf("portal = %+v, want a portal with a HID", portal)
+	}
+	button := portal.Children[0]
+	if _, ok := s.handlers[button.HID+"_onclick"]; !ok {
+		t.Fatalf("missing handler for button inside portal; handlers=%v", s.handlers)
+	}
+	if len(s.root.Children) != 1 {
+		t.Fatalf("children=%d, want the component inside the portal mounted", len(s.root.Children))
+	}
+	child := s.root.Children[0]
+	childHID := child.LastTree().HID
+	if _, ok := s.handlers[childHID+"_onclick"]; !ok {
+		t.Fatal("missing handler for component inside portal")
+	}
+
+	open = false
+	_ = s.renderComponent(s.root)
+
+	if _, ok := s.handlers[button.HID+"_onclick"]; ok {
+		t.Error("button handler remains after the portal unmounted")
+	}
+	if _, ok := s.handlers[childHID+"_onclick"]; ok {
+		t.Error("component handler remains after the portal unmounted")
+	}
+	if child.Owner != nil {
+		t.Error("component inside the portal was not disposed")
+	}
+}
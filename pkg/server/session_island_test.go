@@ -0,0 +1,89 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/vango-go/vango/pkg/features/islands"
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vango"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestSession_IslandEventDeliveredToHandler(t *testing.T) {
+	s := NewMockSession()
+
+	var got map[string]any
+	var sawCtx bool
+	var replyErr error
+	unregister := s.islands.Handle("chart", func(m map[string]any) {
+		got = m
+		sawCtx = vango.UseCtx() != nil
+		replyErr = islands.SendToIsland("chart", map[string]any{"ack": true})
+	})
+	defer unregister()
+
+	s.handleEvent(&Event{
+		Type:    protocol.EventIsland,
+		HID:     "chart",
+		Payload: &protocol.IslandEventData{Data: map[string]any{"point": int64(3)}},
+	})
+
+	if got["point"] != int64(3) {
+		t.Fatalf("handler got %#v, want point=3", got)
+	}
+	if !sawCtx {
+		t.Error("expected a runtime context while handling an island message")
+	}
+	if replyErr != nil {
+		t.Errorf("SendToIsland from handler: %v", replyErr)
+	}
+}
+
+func TestSession_IslandEventWithoutHandlerIsDropped(t *testing.T) {
+	s := NewMockSession()
+
+	// Must not panic or reach the HID handler lookup.
+	s.handleEvent(&Event{
+		Type:    protocol.EventIsland,
+		HID:     "gone",
+		Payload: &protocol.IslandEventData{},
+	})
+}
+
+func TestSession_SendToIslandQueuesPatch(t *testing.T) {
+	s := NewMockSession()
+
+	var err error
+	vango.WithOwner(s.owner, func() {
+		err = islands.SendToIsland("chart", map[string]any{"series": []any{int64(1), int64(2)}})
+	})
+	if err != nil {
+		t.Fatalf("SendToIsland: %v", err)
+	}
+
+	// Island messages share the URL patch buffer, so they are sent after the
+	// DOM patches of the same tick.
+	patches := s.drainURLPatches()
+	if len(patches) != 1 || patches[0].Op != protocol.PatchIslandMessage || patches[0].HID != "chart" {
+		t.Fatalf("queued patches = %+v, want one IslandMessage for chart", patches)
+	}
+}
+
+func TestSession_OnIslandMessageRegisteredOnMount(t *testing.T) {
+	s := NewMockSession()
+
+	var sendErr error
+	root := FuncComponent(func() *vdom.VNode {
+		islands.OnIslandMessage("chart", func(map[string]any) {})
+		sendErr = islands.SendToIsland("chart", map[string]any{"ready": true})
+		return islands.JSIsland("chart", "/js/chart.js", nil)
+	})
+	s.MountRoot(root)
+
+	if sendErr != nil {
+		t.Fatalf("SendToIsland during render: %v", sendErr)
+	}
+	if !s.islands.Deliver("chart", nil) {
+		t.Error("expected OnIslandMessage handler to be registered after mount")
+	}
+}
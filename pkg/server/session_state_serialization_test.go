@@ -7,6 +7,7 @@ import (
 	"github.com/vango-go/vango/pkg/protocol"
 	"github.com/vango-go/vango/pkg/session"
 	"github.com/vango-go/vango/pkg/urlparam"
+	"github.com/vango-go/vango/pkg/vango"
 )
 
 func TestSession_DataAccessorsAndURLPatches(t *testing.T) {
@@ -93,3 +94,42 @@ func TestSession_SerializeDeserialize_RoundTripMergesData(t *testing.T) {
 		t.Fatalf("restored data mismatch: a=%q n=%d", s2.GetString("a"), s2.GetInt("n"))
 	}
 }
+
+func TestSession_SerializeDeserialize_PersistedSignals(t *testing.T) {
+	s := NewMockSession()
+
+	var count, cursor *vango.Signal[int]
+	vango.WithOwner(s.owner, func() {
+		count = vango.NewSignal(0, vango.PersistKey("count"))
+		cursor = vango.NewSignal(0, vango.PersistKey("cursor"), vango.Transient())
+	})
+	count.Set(7)
+	cursor.Set(3)
+
+	data, err := s.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error: %v", err)
+	}
+	ss, err := session.Deserialize(data)
+	if err != nil {
+		t.Fatalf("session.Deserialize() error: %v", err)
+	}
+	if string(ss.Signals["count"]) != "7" {
+		t.Fatalf("Signals[count] = %s, want 7", ss.Signals["count"])
+	}
+	if _, ok := ss.Signals["cursor"]; ok {
+		t.Fatal("transient signal should not be serialized")
+	}
+
+	s2 := NewMockSession()
+	if err := s2.Deserialize(data); err != nil {
+		t.Fatalf("Deserialize() error: %v", err)
+	}
+	var restored *vango.Signal[int]
+	vango.WithOwner(s2.owner, func() {
+		restored = vango.NewSignal(0, vango.PersistKey("count"))
+	})
+	if got := restored.Peek(); got != 7 {
+		t.Fatalf("restored signal = %d, want 7", got)
+	}
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn is the message transport between a session and its client. Each
+// message carries one encoded protocol frame.
+//
+// *websocket.Conn implements Conn. When WebSocket upgrades are blocked the
+// client falls back to Server-Sent Events plus HTTP POST, which implements
+// Conn as well (see HandleHTTPFallback), so handshake, resume, acks and
+// resync work the same on both.
+type Conn interface {
+	// ReadMessage blocks until the next client message arrives.
+	ReadMessage() (messageType int, data []byte, err error)
+
+	// WriteMessage sends one message to the client.
+	WriteMessage(messageType int, data []byte) error
+
+	// WriteControl sends a WebSocket control message such as a close frame.
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+
+	// SetReadDeadline sets the deadline for the next ReadMessage.
+	SetReadDeadline(t time.Time) error
+
+	// SetWriteDeadline sets the deadline for subsequent writes.
+	SetWriteDeadline(t time.Time) error
+
+	// Close closes the connection.
+	Close() error
+}
+
+var _ Conn = (*websocket.Conn)(nil)
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/protocol"
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestServer_HandshakeNegotiatesVersion(t *testing.T) {
+	s := New(DefaultServerConfig().WithDevMode())
+	s.SetRootComponent(func() Component {
+		return staticComponent{node: vdom.Div(vdom.Text("hi"))}
+	})
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { s.Sessions().Shutdown() })
+
+	tests := []struct {
+		name       string
+		client     protocol.ProtocolVersion
+		wantStatus protocol.HandshakeStatus
+		want       protocol.ProtocolVersion
+	}{
+		{"old client", protocol.Version20, protocol.HandshakeOK, protocol.Version20},
+		{"2.1 client", protocol.Version21, protocol.HandshakeOK, protocol.Version21},
+		{"current client", protocol.Version22, protocol.HandshakeOK, protocol.Version22},
+		{"newer minor client", protocol.ProtocolVersion{Major: 2, Minor: 9}, protocol.HandshakeOK, protocol.CurrentVersion},
+		{"newer major client", protocol.ProtocolVersion{Major: 3, Minor: 0}, protocol.HandshakeVersionMismatch, protocol.CurrentVersion},
+		{"older major client", protocol.ProtocolVersion{Major: 1, Minor: 0}, protocol.HandshakeUpgradeRequired, protocol.CurrentVersion},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := dialWS(t, wsURL(t, ts.URL, "/_vango/live?path=/"), nil)
+			hello := protocol.NewClientHello("")
+			hello.Version = tc.client
+			writeHandshake(t, conn, hello)
+
+			sh := readServerHello(t, conn)
+			if sh.Status != tc.wantStatus {
+				t.Fatalf("status = %v, want %v", sh.Status, tc.wantStatus)
+			}
+			if sh.Version != tc.want {
+				t.Errorf("version = %s, want %s", sh.Version, tc.want)
+			}
+			if sh.Status != protocol.HandshakeOK {
+				if sh.MinVersion != protocol.MinSupportedVersion {
+					t.Errorf("min version = %s, want %s", sh.MinVersion, protocol.MinSupportedVersion)
+				}
+				return
+			}
+			sess := s.Sessions().Get(sh.SessionID)
+			if sess == nil {
+				t.Fatal("session not found")
+			}
+			if got := sess.ProtocolVersion(); got != tc.want {
+				t.Errorf("session version = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSession_DowngradesPatchesForOldClient(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	sess.setProtocolVersion(protocol.Version20)
+
+	sess.SendPatches([]protocol.Patch{
+		protocol.NewSetTitlePatch("Inbox"),
+		protocol.NewSetTextPatch("h1", "hello"),
+	})
+
+	frame, _ := readMessage(t, clientConn, protocol.NewReassembler(0))
+	pf, err := protocol.DecodePatches(frame.Payload)
+	if err != nil {
+		t.Fatalf("DecodePatches failed: %v", err)
+	}
+	if len(pf.Patches) != 1 || pf.Patches[0].Op != protocol.PatchSetText {
+		t.Errorf("patches = %+v, want only the SetText patch", pf.Patches)
+	}
+}
+
+func TestSession_ForcesReloadInsteadOfFragmentingForOldClient(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	sess.setProtocolVersion(protocol.Version20)
+
+	big := strings.Repeat("x", 2*protocol.MaxPayloadSize)
+	sess.SendPatches([]protocol.Patch{protocol.NewSetTextPatch("h1", big)})
+
+	frame, n := readMessage(t, clientConn, protocol.NewReassembler(0))
+	if n != 1 || frame.Type != protocol.FrameControl {
+		t.Fatalf("got %d frame(s) of type %v, want one control frame", n, frame.Type)
+	}
+	ct, data, err := protocol.DecodeControl(frame.Payload)
+	if err != nil || ct != protocol.ControlAuthCommand {
+		t.Fatalf("DecodeControl = %v, %v; want AuthCommand", ct, err)
+	}
+	if ac := data.(*protocol.AuthCommand); ac.Action != protocol.AuthActionForceReload {
+		t.Errorf("action = %v, want ForceReload", ac.Action)
+	}
+}
+
+func TestSession_ForcesReloadForPortalOnOldClient(t *testing.T) {
+	clientConn, serverConn := newWebSocketPair(t)
+	sess := newSession(serverConn, "", DefaultSessionConfig(), slog.Default())
+	sess.setProtocolVersion(protocol.Version21)
+
+	sess.SendPatches([]protocol.Patch{
+		protocol.NewInsertNodePatch("h2", "h1", 0, protocol.NewPortalWire("body", "h2")),
+	})
+
+	frame, _ := readMessage(t, clientConn, protocol.NewReassembler(0))
+	ct, data, err := protocol.DecodeControl(frame.Payload)
+	if err != nil || ct != protocol.ControlAuthCommand {
+		t.Fatalf("DecodeControl = %v, %v; want AuthCommand", ct, err)
+	}
+	if ac := data.(*protocol.AuthCommand); ac.Action != protocol.AuthActionForceReload {
+		t.Errorf("action = %v, want ForceReload", ac.Action)
+	}
+}
+
+func TestSession_HookCallUnsupportedOnOldClient(t *testing.T) {
+	_, s := newHookCallSession(t, DefaultSessionConfig())
+	s.setProtocolVersion(protocol.Version21)
+
+	if _, err := NewTestContext(s).CallHook("h1", HookMethodRect, nil); !errors.Is(err, ErrHookCallUnsupported) {
+		t.Errorf("err = %v, want ErrHookCallUnsupported", err)
+	}
+}
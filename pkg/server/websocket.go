@@ -20,6 +20,10 @@ func (s *Session) ReadLoop() {
 	}
 	defer s.readLoopRunning.Store(false)
 
+	// Partial messages never span connections; each ReadLoop starts empty.
+	reassembler := protocol.NewReassembler(s.config.MaxReassemblySize)
+	defer s.abortBlobs()
+
 	for {
 		// If the session is fully closed, exit.
 		if s.closed.Load() {
@@ -54,12 +58,16 @@ func (s *Session) ReadLoop() {
 		s.detached.Store(false)
 		s.BytesReceived(len(msg))
 
-		// Decode frame
-		frame, err := protocol.DecodeFrame(msg)
+		// Decode frame, joining fragments of large messages
+		frame, err := reassembler.DecodeFrame(msg)
 		if err != nil {
 			s.logger.Error("frame decode error", "error", err)
 			continue
 		}
+		if frame == nil {
+			// Waiting for more fragments
+			continue
+		}
 
 		// Handle based on frame type
 		switch frame.Type {
@@ -72,6 +80,9 @@ func (s *Session) ReadLoop() {
 		case protocol.FrameAck:
 			s.handleAckFrame(frame.Payload)
 
+		case protocol.FrameBlob:
+			s.handleBlobFrame(frame.Payload)
+
 		default:
 			s.logger.Warn("unknown frame type", "type", frame.Type)
 		}
@@ -96,6 +107,8 @@ func (s *Session) handleEventFrame(payload []byte) {
 		fmt.Printf("[WS] Decoded event: HID=%s Type=%v\n", pe.HID, pe.Type)
 	}
 
+	s.record(RecordEvent, payload)
+
 	// Convert to server event
 	event := eventFromProtocol(pe, s)
 
@@ -130,6 +143,25 @@ func (s *Session) handleControlFrame(payload []byte) {
 			s.handleResyncRequest(rr.LastSeq)
 		}
 
+	case protocol.ControlClientEnv:
+		// Client resized or changed a preference
+		if ce, ok := data.(*protocol.ClientEnv); ok {
+			s.record(RecordClientEnv, payload)
+			s.handleClientEnv(ce)
+		}
+
+	case protocol.ControlPrefSync:
+		// A client tab changed preferences
+		if ps, ok := data.(*protocol.PrefSync); ok {
+			s.handlePrefSync(ps)
+		}
+
+	case protocol.ControlHookResult:
+		// A client hook answered a CallHook
+		if hr, ok := data.(*protocol.HookResult); ok {
+			s.handleHookResult(hr)
+		}
+
 	case protocol.ControlClose:
 		// Client is closing
 		if cm, ok := data.(*protocol.CloseMessage); ok {
@@ -220,9 +252,24 @@ func (s *Session) replayPatchFrames(frames [][]byte) {
 		return
 	}
 
-	for i, frame := range frames {
-		s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
-		if err := s.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+	for i, frameData := range frames {
+		frame, err := decodeMessage(frameData)
+		if err != nil {
+			s.logger.Error("replay patch frame failed",
+				"frame_index", i,
+				"total_frames", len(frames),
+				"error", err)
+			return
+		}
+		wireData, err := s.encodeFrameLocked(frame)
+		if err != nil {
+			s.logger.Error("replay patch frame failed",
+				"frame_index", i,
+				"total_frames", len(frames),
+				"error", err)
+			return
+		}
+		if err := s.writeMessageLocked(wireData); err != nil {
 			s.logger.Error("replay patch frame failed",
 				"frame_index", i,
 				"total_frames", len(frames),
@@ -234,6 +281,44 @@ func (s *Session) replayPatchFrames(frames [][]byte) {
 	s.logger.Info("replayed patch frames", "count", len(frames))
 }
 
+// versionReloadFrame returns a force-reload command for clients whose
+// protocol version cannot express a message, so they reload into the
+// current thin client rather than fall out of sync.
+func versionReloadFrame() []byte {
+	ct, ac := protocol.NewAuthCommand(&protocol.AuthCommand{
+		Action:  protocol.AuthActionForceReload,
+		Channel: "vango:version",
+		Type:    "version",
+	})
+	return protocol.NewFrame(protocol.FrameControl, protocol.EncodeControl(ct, ac)).Encode()
+}
+
+// writeMessageLocked writes data, as produced by Frame.EncodeMessage, with
+// one WebSocket message per frame so fragments of a large message fit the
+// client's frame parser. The caller must hold s.mu, which also keeps the
+// fragments of one message contiguous.
+func (s *Session) writeMessageLocked(data []byte) error {
+	frames, err := protocol.SplitFrames(data)
+	if err != nil {
+		return err
+	}
+	if len(frames) > 1 && !s.versionLocked().SupportsFragmentation() {
+		// The client predates fragmentation and would drop the message,
+		// leaving its DOM out of sync. Have it reload into the current
+		// thin client instead.
+		s.logger.Warn("message too large for client protocol version, forcing reload",
+			"size", len(data), "client_version", s.versionLocked().String())
+		frames = [][]byte{versionReloadFrame()}
+	}
+	for _, frame := range frames {
+		s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
+		if err := s.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // sendPong sends a pong response.
 func (s *Session) sendPong(timestamp uint64) {
 	s.mu.Lock()
@@ -391,7 +476,7 @@ func (s *Session) Start() {
 // It swaps the WebSocket connection, resets sequence numbers, and reinitializes
 // channels if the session was previously closed. Call NeedsRestart() after
 // Resume() to check if Start() should be called to restart goroutines.
-func (s *Session) Resume(conn *websocket.Conn, lastSeq uint64) {
+func (s *Session) Resume(conn Conn, lastSeq uint64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -504,16 +589,26 @@ func (s *Session) SendPatches(patches []protocol.Patch) {
 
 	pf := &protocol.PatchesFrame{
 		Seq:     seq,
-		Patches: patches,
+		Patches: protocol.DowngradePatches(patches, s.versionLocked()),
 	}
 
 	payload := protocol.EncodePatches(pf)
+	s.record(RecordPatches, payload)
 	frame := protocol.NewFrame(protocol.FramePatches, payload)
-	frameData := frame.Encode()
-
-	s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
+	frameData, err := s.encodeFrameLocked(frame)
+	if err != nil {
+		s.logger.Error("compress error", "error", err)
+		s.mu.Unlock()
+		s.Close()
+		return
+	}
+	if v := s.versionLocked(); !v.CanDecodePatches(pf.Patches) {
+		s.logger.Warn("patches need a newer client protocol version, forcing reload",
+			"client_version", v.String())
+		frameData = versionReloadFrame()
+	}
 
-	if err := s.conn.WriteMessage(websocket.BinaryMessage, frameData); err != nil {
+	if err := s.writeMessageLocked(frameData); err != nil {
 		s.logger.Error("write error", "error", err)
 		s.mu.Unlock()
 		s.Close()
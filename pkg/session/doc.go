@@ -34,9 +34,11 @@
 //
 // # Signal Persistence Options
 //
-// Signals can be configured for persistence behavior:
+// Signals created with an explicit persist key are saved in
+// SerializableSession.Signals and restored when a component next creates a
+// signal with the same key:
 //
-//	cursor := vango.Signal(Point{0, 0}, session.Transient())     // Not persisted
-//	userID := vango.Signal(0, session.PersistKey("user_id"))    // Persisted with key
-//	formData := vango.Signal(Form{})                             // Persisted with auto-key
+//	userID := vango.NewSignal(0, vango.PersistKey("user_id"))                  // Persisted with key
+//	cursor := vango.NewSignal(Point{}, vango.PersistKey("cur"), vango.Transient()) // Not persisted
+//	formData := vango.NewSignal(Form{})                                         // Not persisted (no key)
 package session
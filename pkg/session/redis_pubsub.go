@@ -0,0 +1,322 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisPubSubClient extends RedisClient with publish/subscribe operations.
+// Like RedisClient, it mirrors github.com/redis/go-redis/v9 and is normally
+// satisfied by a thin adapter around *redis.Client.
+//
+// Subscribe should return only once Redis has confirmed the subscription
+// (in go-redis, after the first PubSub.Receive), so that nothing published
+// after it returns is missed.
+type RedisPubSubClient interface {
+	RedisClient
+	Publish(ctx context.Context, channel string, message interface{}) RedisIntCmd
+	Subscribe(ctx context.Context, channels ...string) RedisPubSub
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) RedisCmd
+}
+
+// RedisCmd represents the result of a Redis command with an arbitrary reply.
+type RedisCmd interface {
+	Err() error
+}
+
+// RedisPubSub represents an active Redis subscription.
+type RedisPubSub interface {
+	// ReceiveMessage blocks until a message arrives, ctx is done,
+	// or the subscription is closed.
+	ReceiveMessage(ctx context.Context) (*RedisMessage, error)
+	Close() error
+}
+
+// RedisMessage is a message received on a Redis channel.
+type RedisMessage struct {
+	Channel string
+	Payload string
+}
+
+// RedisSignalBackend synchronizes values between server instances over
+// Redis pub/sub. It satisfies vango.GlobalSignalBackend.
+//
+// Every publish is also stored under a "last" key so that instances that
+// subscribe later start from the most recent value. Payloads carry a
+// version from a per-key counter, so a handler never applies a payload
+// older than one it has already seen.
+type RedisSignalBackend struct {
+	client RedisPubSubClient
+	prefix string
+
+	// retainLast stores each payload under a "last" key and replays it to
+	// new subscribers. Disabled for broadcast transports.
+	retainLast bool
+
+	mu     sync.Mutex
+	subs   map[string]*redisSignalChannel
+	nextID uint64
+	closed bool
+}
+
+// redisSignalChannel fans one Redis subscription out to local handlers.
+type redisSignalChannel struct {
+	pubsub   RedisPubSub
+	cancel   context.CancelFunc
+	handlers map[uint64]*redisSignalHandler
+}
+
+// redisSignalHandler is one local subscriber. version is the version of
+// the last payload delivered to fn.
+type redisSignalHandler struct {
+	mu      sync.Mutex
+	fn      func([]byte)
+	version uint64
+}
+
+// deliver calls fn with data unless a payload at least as new was already
+// delivered. Unversioned payloads (version 0) are always delivered.
+func (h *redisSignalHandler) deliver(version uint64, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if version != 0 {
+		if version <= h.version {
+			return
+		}
+		h.version = version
+	}
+	h.fn(data)
+}
+
+// NewRedisSignalBackend creates a Redis pub/sub backend for global signals.
+// Channel and key names are prefixed with "vango:signal:" unless overridden
+// with WithRedisPrefix.
+func NewRedisSignalBackend(client RedisPubSubClient, opts ...RedisStoreOption) *RedisSignalBackend {
+	cfg := &redisStoreConfig{
+		prefix: "vango:signal:",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &RedisSignalBackend{
+		client:     client,
+		prefix:     cfg.prefix,
+		retainLast: true,
+		subs:       make(map[string]*redisSignalChannel),
+	}
+}
+
+// RedisBroadcastTransport carries server broadcasts over Redis pub/sub.
+// It satisfies server.BroadcastTransport. Unlike RedisSignalBackend, payloads
+// are not retained or replayed to new subscribers.
+type RedisBroadcastTransport struct {
+	*RedisSignalBackend
+}
+
+// NewRedisBroadcastTransport creates a Redis pub/sub broadcast transport.
+// Channel names are prefixed with "vango:broadcast:" unless overridden
+// with WithRedisPrefix.
+func NewRedisBroadcastTransport(client RedisPubSubClient, opts ...RedisStoreOption) *RedisBroadcastTransport {
+	cfg := &redisStoreConfig{
+		prefix: "vango:broadcast:",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &RedisBroadcastTransport{
+		RedisSignalBackend: &RedisSignalBackend{
+			client: client,
+			prefix: cfg.prefix,
+			subs:   make(map[string]*redisSignalChannel),
+		},
+	}
+}
+
+// channel returns the Redis channel name for a signal key.
+func (r *RedisSignalBackend) channel(key string) string {
+	return r.prefix + key
+}
+
+// lastKey returns the Redis key holding the latest value for a signal key.
+func (r *RedisSignalBackend) lastKey(key string) string {
+	return r.prefix + "last:" + key
+}
+
+// versionKey returns the Redis key counting publishes for a signal key.
+func (r *RedisSignalBackend) versionKey(key string) string {
+	return r.prefix + "version:" + key
+}
+
+// publishScript numbers a payload, stores it as the latest value and
+// publishes it in one step, so the stored value and the order of
+// publishes always agree.
+//
+// KEYS: version counter, last value, channel. ARGV: payload.
+const publishScript = `
+local v = redis.call('INCR', KEYS[1])
+local msg = v .. ':' .. ARGV[1]
+redis.call('SET', KEYS[2], msg)
+redis.call('PUBLISH', KEYS[3], msg)
+return v
+`
+
+// parseVersioned splits a "<version>:<data>" payload written by
+// publishScript.
+func parseVersioned(payload []byte) (uint64, []byte, bool) {
+	i := bytes.IndexByte(payload, ':')
+	if i <= 0 {
+		return 0, nil, false
+	}
+	version, err := strconv.ParseUint(string(payload[:i]), 10, 64)
+	if err != nil || version == 0 {
+		return 0, nil, false
+	}
+	return version, payload[i+1:], true
+}
+
+// Publish publishes data to all instances. Signal backends also store data as
+// the latest value for key.
+func (r *RedisSignalBackend) Publish(ctx context.Context, key string, data []byte) error {
+	r.mu.Lock()
+	closed := r.closed
+	r.mu.Unlock()
+	if closed {
+		return ErrStoreClosed{}
+	}
+
+	if r.retainLast {
+		keys := []string{r.versionKey(key), r.lastKey(key), r.channel(key)}
+		return r.client.Eval(ctx, publishScript, keys, data).Err()
+	}
+	return r.client.Publish(ctx, r.channel(key), data).Err()
+}
+
+// Subscribe registers fn for key. The first local subscriber for a key opens
+// a Redis subscription. For signal backends, the latest stored value, if any,
+// is delivered to fn before Subscribe returns, unless a newer payload
+// arrived first.
+func (r *RedisSignalBackend) Subscribe(key string, fn func(data []byte)) (func(), error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, ErrStoreClosed{}
+	}
+
+	ch, ok := r.subs[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch = &redisSignalChannel{
+			pubsub:   r.client.Subscribe(ctx, r.channel(key)),
+			cancel:   cancel,
+			handlers: make(map[uint64]*redisSignalHandler),
+		}
+		r.subs[key] = ch
+		go r.receiveLoop(ctx, key, ch)
+	}
+	r.nextID++
+	id := r.nextID
+	h := &redisSignalHandler{fn: fn}
+	ch.handlers[id] = h
+	r.mu.Unlock()
+
+	if !r.retainLast {
+		return func() { r.unsubscribe(key, id) }, nil
+	}
+
+	// The subscription is already open, so nothing published after the GET
+	// is missed; a payload that overtakes the GET result wins by version.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	last, err := r.client.Get(ctx, r.lastKey(key)).Bytes()
+	cancel()
+	if err == nil {
+		if version, data, ok := parseVersioned(last); ok {
+			h.deliver(version, data)
+		}
+	}
+
+	return func() { r.unsubscribe(key, id) }, nil
+}
+
+// unsubscribe removes one handler, closing the Redis subscription when the
+// last local handler for key is gone.
+func (r *RedisSignalBackend) unsubscribe(key string, id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch, ok := r.subs[key]
+	if !ok {
+		return
+	}
+	delete(ch.handlers, id)
+	if len(ch.handlers) == 0 {
+		delete(r.subs, key)
+		ch.cancel()
+		_ = ch.pubsub.Close()
+	}
+}
+
+// receiveLoop dispatches messages from one Redis subscription until it closes.
+func (r *RedisSignalBackend) receiveLoop(ctx context.Context, key string, ch *redisSignalChannel) {
+	for {
+		msg, err := ch.pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+				return
+			}
+			// Transient receive errors: back off briefly and retry.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+			continue
+		}
+
+		var version uint64
+		data := []byte(msg.Payload)
+		if r.retainLast {
+			var ok bool
+			if version, data, ok = parseVersioned(data); !ok {
+				continue
+			}
+		}
+
+		r.mu.Lock()
+		hs := make([]*redisSignalHandler, 0, len(ch.handlers))
+		for _, h := range ch.handlers {
+			hs = append(hs, h)
+		}
+		r.mu.Unlock()
+
+		for _, h := range hs {
+			h.deliver(version, data)
+		}
+	}
+}
+
+// Close cancels all subscriptions. The underlying Redis client is not closed,
+// as it may be shared with other components.
+func (r *RedisSignalBackend) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	for key, ch := range r.subs {
+		ch.cancel()
+		_ = ch.pubsub.Close()
+		delete(r.subs, key)
+	}
+	return nil
+}
+
+// Prefix returns the current channel prefix.
+// This is for testing/debugging purposes.
+func (r *RedisSignalBackend) Prefix() string {
+	return r.prefix
+}
@@ -0,0 +1,285 @@
+package session
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockRedisBus is an in-memory pub/sub bus shared by mockRedisPubSubClients.
+type mockRedisBus struct {
+	mu   sync.Mutex
+	subs map[string][]*mockRedisPubSub
+}
+
+type mockRedisPubSub struct {
+	bus      *mockRedisBus
+	channels []string
+	msgs     chan *RedisMessage
+	once     sync.Once
+	done     chan struct{}
+}
+
+func (p *mockRedisPubSub) ReceiveMessage(ctx context.Context) (*RedisMessage, error) {
+	select {
+	case msg := <-p.msgs:
+		return msg, nil
+	case <-p.done:
+		return nil, context.Canceled
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *mockRedisPubSub) Close() error {
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
+
+type mockRedisPubSubClient struct {
+	mockRedisClient
+	bus      *mockRedisBus
+	versions map[string]int64
+
+	// afterGet, if set, runs after Get has read its value but before it
+	// returns, to simulate a publish racing a subscriber's replay.
+	afterGet func()
+}
+
+func (c *mockRedisPubSubClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) RedisStatusCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.getResp == nil {
+		c.getResp = make(map[string]mockRedisStringCmd)
+	}
+	c.getResp[key] = mockRedisStringCmd{data: value.([]byte)}
+	return mockRedisStatusCmd{}
+}
+
+func (c *mockRedisPubSubClient) Get(ctx context.Context, key string) RedisStringCmd {
+	cmd := c.mockRedisClient.Get(ctx, key)
+	if c.afterGet != nil {
+		c.afterGet()
+	}
+	return cmd
+}
+
+// Eval runs publishScript: it versions the payload, stores it and publishes
+// it as one atomic step.
+func (c *mockRedisPubSubClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) RedisCmd {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+
+	c.mu.Lock()
+	if c.versions == nil {
+		c.versions = make(map[string]int64)
+	}
+	c.versions[keys[0]]++
+	msg := strconv.FormatInt(c.versions[keys[0]], 10) + ":" + string(args[0].([]byte))
+	if c.getResp == nil {
+		c.getResp = make(map[string]mockRedisStringCmd)
+	}
+	c.getResp[keys[1]] = mockRedisStringCmd{data: []byte(msg)}
+	c.mu.Unlock()
+
+	for _, s := range c.bus.subs[keys[2]] {
+		s.msgs <- &RedisMessage{Channel: keys[2], Payload: msg}
+	}
+	return mockRedisStatusCmd{}
+}
+
+func (c *mockRedisPubSubClient) Publish(ctx context.Context, channel string, message interface{}) RedisIntCmd {
+	c.bus.mu.Lock()
+	subs := append([]*mockRedisPubSub(nil), c.bus.subs[channel]...)
+	c.bus.mu.Unlock()
+	for _, s := range subs {
+		s.msgs <- &RedisMessage{Channel: channel, Payload: string(message.([]byte))}
+	}
+	return mockRedisIntCmd{}
+}
+
+func (c *mockRedisPubSubClient) Subscribe(ctx context.Context, channels ...string) RedisPubSub {
+	p := &mockRedisPubSub{bus: c.bus, channels: channels, msgs: make(chan *RedisMessage, 16), done: make(chan struct{})}
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	if c.bus.subs == nil {
+		c.bus.subs = make(map[string][]*mockRedisPubSub)
+	}
+	for _, ch := range channels {
+		c.bus.subs[ch] = append(c.bus.subs[ch], p)
+	}
+	return p
+}
+
+func TestRedisSignalBackend_PublishSubscribe(t *testing.T) {
+	client := &mockRedisPubSubClient{bus: &mockRedisBus{}}
+	backend := NewRedisSignalBackend(client, WithRedisPrefix("test:"))
+	t.Cleanup(func() { _ = backend.Close() })
+
+	got := make(chan string, 4)
+	unsubscribe, err := backend.Subscribe("status", func(data []byte) { got <- string(data) })
+	if err != nil {
+		t.Fatalf("Subscribe() error: %v", err)
+	}
+
+	if err := backend.Publish(context.Background(), "status", []byte("online")); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+	select {
+	case v := <-got:
+		if v != "online" {
+			t.Fatalf("received %q, want %q", v, "online")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for published message")
+	}
+
+	// A later subscriber receives the stored last value immediately.
+	late := make(chan string, 1)
+	unsubscribeLate, err := backend.Subscribe("status", func(data []byte) { late <- string(data) })
+	if err != nil {
+		t.Fatalf("Subscribe(late) error: %v", err)
+	}
+	select {
+	case v := <-late:
+		if v != "online" {
+			t.Fatalf("late subscriber received %q, want %q", v, "online")
+		}
+	default:
+		t.Fatal("late subscriber did not receive last value")
+	}
+
+	unsubscribe()
+	unsubscribeLate()
+	backend.mu.Lock()
+	remaining := len(backend.subs)
+	backend.mu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("subscriptions remaining after unsubscribe: %d", remaining)
+	}
+}
+
+func TestRedisSignalBackend_ClosedErrors(t *testing.T) {
+	backend := NewRedisSignalBackend(&mockRedisPubSubClient{bus: &mockRedisBus{}})
+	_ = backend.Close()
+
+	if err := backend.Publish(context.Background(), "k", []byte("v")); err == nil {
+		t.Fatal("Publish after Close should fail")
+	}
+	if _, err := backend.Subscribe("k", func([]byte) {}); err == nil {
+		t.Fatal("Subscribe after Close should fail")
+	}
+}
+
+func TestRedisBroadcastTransport_DoesNotRetain(t *testing.T) {
+	client := &mockRedisPubSubClient{bus: &mockRedisBus{}}
+	transport := NewRedisBroadcastTransport(client)
+	t.Cleanup(func() { _ = transport.Close() })
+
+	if err := transport.Publish(context.Background(), "news", []byte("first")); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+
+	got := make(chan string, 1)
+	unsubscribe, err := transport.Subscribe("news", func(data []byte) { got <- string(data) })
+	if err != nil {
+		t.Fatalf("Subscribe() error: %v", err)
+	}
+	defer unsubscribe()
+
+	select {
+	case v := <-got:
+		t.Fatalf("new subscriber received replayed payload %q", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if len(client.getResp) != 0 {
+		t.Fatalf("broadcast transport stored last value: %v", client.getResp)
+	}
+	if transport.Prefix() != "vango:broadcast:" {
+		t.Fatalf("prefix = %q", transport.Prefix())
+	}
+}
+
+func TestRedisSignalBackend_ReplayDoesNotOverwriteNewerPublish(t *testing.T) {
+	client := &mockRedisPubSubClient{bus: &mockRedisBus{}}
+	backend := NewRedisSignalBackend(client)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	if err := backend.Publish(context.Background(), "status", []byte("old")); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var received []string
+	got := make(chan struct{}, 4)
+
+	// Publish "new" after the subscriber has read "old" from the last key,
+	// and wait for it to arrive over the subscription before the replay.
+	client.afterGet = func() {
+		client.afterGet = nil
+		if err := backend.Publish(context.Background(), "status", []byte("new")); err != nil {
+			t.Errorf("Publish() error: %v", err)
+		}
+		select {
+		case <-got:
+		case <-time.After(2 * time.Second):
+			t.Error("timed out waiting for published message")
+		}
+	}
+
+	unsubscribe, err := backend.Subscribe("status", func(data []byte) {
+		mu.Lock()
+		received = append(received, string(data))
+		mu.Unlock()
+		got <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error: %v", err)
+	}
+	defer unsubscribe()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 || received[0] != "new" {
+		t.Fatalf("received %q, want only the newer payload", received)
+	}
+}
+
+func TestRedisSignalBackend_DropsStaleMessages(t *testing.T) {
+	client := &mockRedisPubSubClient{bus: &mockRedisBus{}}
+	backend := NewRedisSignalBackend(client, WithRedisPrefix("test:"))
+	t.Cleanup(func() { _ = backend.Close() })
+
+	got := make(chan string, 4)
+	unsubscribe, err := backend.Subscribe("status", func(data []byte) { got <- string(data) })
+	if err != nil {
+		t.Fatalf("Subscribe() error: %v", err)
+	}
+	defer unsubscribe()
+
+	// Deliver version 2 before version 1, as two racing publishers could.
+	backend.mu.Lock()
+	sub := backend.subs["status"].pubsub.(*mockRedisPubSub)
+	backend.mu.Unlock()
+	sub.msgs <- &RedisMessage{Channel: "test:status", Payload: "2:second"}
+	sub.msgs <- &RedisMessage{Channel: "test:status", Payload: "1:first"}
+	sub.msgs <- &RedisMessage{Channel: "test:status", Payload: "3:third"}
+
+	for _, want := range []string{"second", "third"} {
+		select {
+		case v := <-got:
+			if v != want {
+				t.Fatalf("received %q, want %q", v, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+	select {
+	case v := <-got:
+		t.Fatalf("unexpected payload %q", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
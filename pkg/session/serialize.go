@@ -2,6 +2,7 @@ package session
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -23,8 +24,9 @@ type SerializableSession struct {
 	// Values contains Session.Get/Set values.
 	Values map[string]json.RawMessage `json:"values,omitempty"`
 
-	// Signals contains persisted signal values by key.
-	// Transient signals are excluded.
+	// Signals contains persisted signal values by PersistKey.
+	// Transient signals and signals without an explicit key are excluded.
+	// Populated since format version 2.
 	Signals map[string]json.RawMessage `json:"signals,omitempty"`
 
 	// Route is the current page route.
@@ -39,7 +41,21 @@ type SerializableSession struct {
 
 // CurrentSerializationVersion is the current version of the serialization format.
 // Increment when making breaking changes to the format.
-const CurrentSerializationVersion = 1
+//
+// Version history:
+//   - 1: identity, route and session values. Signals was reserved but never written.
+//   - 2: Signals holds the JSON value of every signal created with PersistKey.
+const CurrentSerializationVersion = 2
+
+// UnsupportedVersionError is returned by Deserialize when the data was written
+// by a newer, incompatible serialization format.
+type UnsupportedVersionError struct {
+	Version int
+}
+
+func (e UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("unsupported session serialization version: %d (max %d)", e.Version, CurrentSerializationVersion)
+}
 
 // Serialize converts a SerializableSession to bytes.
 func Serialize(ss *SerializableSession) ([]byte, error) {
@@ -48,14 +64,30 @@ func Serialize(ss *SerializableSession) ([]byte, error) {
 }
 
 // Deserialize converts bytes back to a SerializableSession.
+// Data written by older format versions is migrated to the current version.
+// Data written by a newer version returns UnsupportedVersionError.
 func Deserialize(data []byte) (*SerializableSession, error) {
 	var ss SerializableSession
 	if err := json.Unmarshal(data, &ss); err != nil {
 		return nil, err
 	}
+	if ss.Version > CurrentSerializationVersion {
+		return nil, UnsupportedVersionError{Version: ss.Version}
+	}
+	migrate(&ss)
 	return &ss, nil
 }
 
+// migrate upgrades ss in place to CurrentSerializationVersion.
+func migrate(ss *SerializableSession) {
+	if ss.Version < 2 {
+		// Version 1 never defined a key scheme for Signals, so any entries
+		// cannot be matched to PersistKey signals safely.
+		ss.Signals = nil
+	}
+	ss.Version = CurrentSerializationVersion
+}
+
 // SignalConfig holds configuration for signal persistence.
 // This is used by the vango.Signal to track persistence options.
 type SignalConfig struct {
@@ -79,3 +79,31 @@ func TestNewSignalConfig_Defaults(t *testing.T) {
 	}
 }
 
+func TestDeserialize_MigratesVersion1DropsSignals(t *testing.T) {
+	data := []byte(`{"id":"s","version":1,"signals":{"0:1":"stale"},"values":{"k":"v"}}`)
+
+	ss, err := Deserialize(data)
+	if err != nil {
+		t.Fatalf("Deserialize() error: %v", err)
+	}
+	if ss.Version != CurrentSerializationVersion {
+		t.Fatalf("Version = %d, want %d", ss.Version, CurrentSerializationVersion)
+	}
+	if ss.Signals != nil {
+		t.Fatalf("Signals = %v, want nil for version 1 data", ss.Signals)
+	}
+	if string(ss.Values["k"]) != `"v"` {
+		t.Fatalf("Values[k] = %s, want %q", ss.Values["k"], `"v"`)
+	}
+}
+
+func TestDeserialize_RejectsNewerVersion(t *testing.T) {
+	_, err := Deserialize([]byte(`{"id":"s","version":99}`))
+	verr, ok := err.(UnsupportedVersionError)
+	if !ok {
+		t.Fatalf("Deserialize() error = %T(%v), want UnsupportedVersionError", err, err)
+	}
+	if verr.Version != 99 || verr.Error() == "" {
+		t.Fatalf("UnsupportedVersionError = %+v", verr)
+	}
+}
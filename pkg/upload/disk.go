@@ -162,6 +162,15 @@ func (s *DiskStore) Claim(tempID string) (*File, error) {
 	}, nil
 }
 
+// ClaimTo streams a temp file to w and deletes it.
+func (s *DiskStore) ClaimTo(tempID string, w io.Writer) (*File, error) {
+	file, err := s.Claim(tempID)
+	if err != nil {
+		return nil, err
+	}
+	return copyAndClose(file, w)
+}
+
 // Cleanup removes expired temp files.
 func (s *DiskStore) Cleanup(maxAge time.Duration) error {
 	now := time.Now()
@@ -24,6 +24,48 @@
 //
 //	r.Post("/upload", upload.Handler(uploadStore))
 //
+// # Live Uploads
+//
+// Alternatively, attach a Live to the input with el.OnUpload and the client
+// streams picked or dropped files over the session connection itself, in
+// flow-controlled chunks that interleave with events rather than blocking
+// them. OnProgress runs on the session loop as the file arrives, so it can
+// drive a progress bar and receives the temp ID when the file is stored:
+//
+//	Input(Type("file"), OnUpload(upload.Live{
+//	    Store:  uploadStore,
+//	    Config: &upload.Config{MaxFileSize: 50 << 20, AllowedTypes: []string{"image/png"}},
+//	    OnProgress: func(p upload.Progress) {
+//	        progress.Set(p.Received)
+//	    },
+//	}))
+//
+// Live uploads apply the same Config checks as the HTTP handler.
+//
+// # Storage Backends
+//
+// DiskStore keeps temp files on the local filesystem. For deployments with
+// more than one server, use an object store:
+//
+//   - S3Store: any S3-compatible service through the small S3Client
+//     interface, with multipart upload for files larger than
+//     S3Config.PartSize
+//   - GCSStore: Google Cloud Storage through the small GCSClient interface
+//
+// Both stores implement ContextSaver, so the upload handler stops writing
+// to the bucket when the request is cancelled.
+//
+// All stores share the same semantics, checked by uploadtest.TestStore:
+// Claim hands out the file and removes it once the reader is closed,
+// ClaimTo streams it to a writer and removes it, and Cleanup(maxAge)
+// removes unclaimed files older than maxAge:
+//
+//	go func() {
+//	    for range time.Tick(5 * time.Minute) {
+//	        uploadStore.Cleanup(time.Hour)
+//	    }
+//	}()
+//
 // # Security
 //
 // The upload handler enforces Config.AllowedTypes against a server-side detected MIME type
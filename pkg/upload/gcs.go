@@ -0,0 +1,85 @@
+package upload
+
+import (
+	"context"
+	"io"
+)
+
+// GCSClient is the object API used by GCSStore. It mirrors the shape of a
+// Google Cloud Storage bucket handle, so a thin adapter over
+// cloud.google.com/go/storage satisfies it without this package depending
+// on the SDK:
+//
+//	func (b bucket) NewWriter(ctx context.Context, attrs upload.ObjectAttrs) io.WriteCloser {
+//	    w := b.Object(attrs.Key).NewWriter(ctx)
+//	    w.ContentType = attrs.ContentType
+//	    w.Metadata = map[string]string{"filename": attrs.Filename}
+//	    return w
+//	}
+type GCSClient interface {
+	// NewWriter returns a writer for a new object. The object is committed
+	// when the writer is closed; cancelling ctx before Close abandons it.
+	NewWriter(ctx context.Context, attrs ObjectAttrs) io.WriteCloser
+
+	// NewReader opens an object. It returns ErrNotFound (or an error
+	// wrapping it) if the object does not exist.
+	NewReader(ctx context.Context, key string) (io.ReadCloser, ObjectAttrs, error)
+
+	// Delete removes an object.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the objects whose keys start with prefix, including
+	// their creation time.
+	List(ctx context.Context, prefix string) ([]ObjectAttrs, error)
+}
+
+// GCSStore stores uploads in Google Cloud Storage (or any store behind a
+// GCSClient). Files are streamed to the object writer as they are read.
+type GCSStore struct {
+	objectStore
+}
+
+// NewGCSStore creates a new GCS upload store.
+//
+// Parameters:
+//   - client: Bucket access, usually an adapter over the GCS SDK
+//   - prefix: Object name prefix for uploads (e.g., "uploads/temp/")
+//   - maxSize: Maximum file size in bytes (0 = no limit)
+func NewGCSStore(client GCSClient, prefix string, maxSize int64) *GCSStore {
+	return &GCSStore{objectStore{
+		backend: gcsBackend{client},
+		prefix:  prefix,
+		maxSize: maxSize,
+	}}
+}
+
+// gcsBackend adapts a GCSClient to objectBackend.
+type gcsBackend struct {
+	client GCSClient
+}
+
+func (b gcsBackend) put(ctx context.Context, attrs ObjectAttrs, r io.Reader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := b.client.NewWriter(ctx, attrs)
+	if _, err := io.Copy(w, r); err != nil {
+		// Cancel before Close so the partial object is not committed.
+		cancel()
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (b gcsBackend) get(ctx context.Context, key string) (io.ReadCloser, ObjectAttrs, error) {
+	return b.client.NewReader(ctx, key)
+}
+
+func (b gcsBackend) delete(ctx context.Context, key string) error {
+	return b.client.Delete(ctx, key)
+}
+
+func (b gcsBackend) list(ctx context.Context, prefix string) ([]ObjectAttrs, error) {
+	return b.client.List(ctx, prefix)
+}
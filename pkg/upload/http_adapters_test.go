@@ -0,0 +1,380 @@
+package upload_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vango-go/vango/pkg/upload"
+	"github.com/vango-go/vango/pkg/upload/uploadtest"
+)
+
+// s3HTTPClient is an upload.S3Client over the S3 REST API with path-style
+// addressing, standing in for an adapter over an S3 SDK so the store is
+// exercised over the wire against uploadtest.FakeS3.
+type s3HTTPClient struct {
+	endpoint string
+	bucket   string
+}
+
+func (c s3HTTPClient) do(ctx context.Context, method, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := c.endpoint + "/" + c.bucket + "/" + escapeKey(key)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	if body == nil || size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var e struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		xml.NewDecoder(resp.Body).Decode(&e)
+		if e.Code == "NoSuchKey" {
+			return nil, upload.ErrNotFound
+		}
+		return nil, fmt.Errorf("s3: %s %s: %d %s: %s", method, key, resp.StatusCode, e.Code, e.Message)
+	}
+	return resp, nil
+}
+
+// escapeKey escapes each segment of an object key for a URL path.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// objectHeader carries the content type and the filename as user metadata,
+// which S3 requires to be ASCII.
+func objectHeader(attrs upload.ObjectAttrs) http.Header {
+	h := http.Header{}
+	h.Set("Content-Type", attrs.ContentType)
+	h.Set("X-Amz-Meta-Filename", url.QueryEscape(attrs.Filename))
+	return h
+}
+
+func (c s3HTTPClient) PutObject(ctx context.Context, attrs upload.ObjectAttrs, body io.Reader, size int64) error {
+	resp, err := c.do(ctx, http.MethodPut, attrs.Key, nil, objectHeader(attrs), body, size)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c s3HTTPClient) CreateMultipartUpload(ctx context.Context, attrs upload.ObjectAttrs) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, attrs.Key, url.Values{"uploads": {""}}, objectHeader(attrs), nil, 0)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.UploadID, nil
+}
+
+func (c s3HTTPClient) UploadPart(ctx context.Context, key, uploadID string, number int, body io.Reader, size int64) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	resp, err := c.do(ctx, http.MethodPut, key, query, nil, body, size)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+func (c s3HTTPClient) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []upload.S3Part) error {
+	type part struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	req := struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{}
+	for _, p := range parts {
+		req.Parts = append(req.Parts, part{PartNumber: p.Number, ETag: p.ETag})
+	}
+	body, err := xml.Marshal(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c s3HTTPClient) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c s3HTTPClient) GetObject(ctx context.Context, key string) (io.ReadCloser, upload.ObjectAttrs, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, upload.ObjectAttrs{}, err
+	}
+	filename, _ := url.QueryUnescape(resp.Header.Get("X-Amz-Meta-Filename"))
+	created, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return resp.Body, upload.ObjectAttrs{
+		Key:         key,
+		Filename:    filename,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+		Created:     created,
+	}, nil
+}
+
+func (c s3HTTPClient) DeleteObject(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c s3HTTPClient) ListObjects(ctx context.Context, prefix string) ([]upload.ObjectAttrs, error) {
+	var out []upload.ObjectAttrs
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+			Contents              []struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+				Size         int64     `xml:"Size"`
+			} `xml:"Contents"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			out = append(out, upload.ObjectAttrs{Key: obj.Key, Size: obj.Size, Created: obj.LastModified})
+		}
+		if !page.IsTruncated {
+			return out, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// gcsHTTPClient is an upload.GCSClient over the GCS JSON API, standing in
+// for an adapter over the GCS SDK so the store is exercised over the wire
+// against uploadtest.FakeGCS.
+type gcsHTTPClient struct {
+	endpoint string
+	bucket   string
+}
+
+func (c gcsHTTPClient) do(ctx context.Context, method, u string, header http.Header, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, upload.ErrNotFound
+		}
+		return nil, fmt.Errorf("gcs: %s: %d %s", method, resp.StatusCode, e.Error.Message)
+	}
+	return resp, nil
+}
+
+func (c gcsHTTPClient) objectURL(key string) string {
+	return c.endpoint + "/storage/v1/b/" + c.bucket + "/o/" + url.PathEscape(key)
+}
+
+// NewWriter streams a multipart upload through a pipe. The object is
+// committed by the server only if the whole body arrives.
+func (c gcsHTTPClient) NewWriter(ctx context.Context, attrs upload.ObjectAttrs) io.WriteCloser {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	w := &gcsHTTPWriter{ctx: ctx, pw: pw, mw: mw, done: make(chan error, 1)}
+
+	u := c.endpoint + "/upload/storage/v1/b/" + c.bucket + "/o?uploadType=multipart"
+	header := http.Header{"Content-Type": {"multipart/related; boundary=" + mw.Boundary()}}
+	go func() {
+		resp, err := c.do(ctx, http.MethodPost, u, header, pr)
+		if err == nil {
+			resp.Body.Close()
+		}
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+
+	meta, _ := json.Marshal(uploadtest.GCSObject{
+		Name:        attrs.Key,
+		ContentType: attrs.ContentType,
+		Metadata:    map[string]string{"filename": attrs.Filename},
+	})
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err == nil {
+		_, err = part.Write(meta)
+	}
+	if err == nil {
+		w.media, err = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {attrs.ContentType}})
+	}
+	w.err = err
+	return w
+}
+
+type gcsHTTPWriter struct {
+	ctx   context.Context
+	pw    *io.PipeWriter
+	mw    *multipart.Writer
+	media io.Writer
+	err   error
+	done  chan error
+}
+
+func (w *gcsHTTPWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.media.Write(p)
+}
+
+// Close finishes the upload, or abandons it if ctx is done.
+func (w *gcsHTTPWriter) Close() error {
+	err := w.err
+	if err == nil {
+		err = w.ctx.Err()
+	}
+	if err == nil {
+		err = w.mw.Close()
+	}
+	w.pw.CloseWithError(err)
+	if reqErr := <-w.done; reqErr != nil {
+		return reqErr
+	}
+	return err
+}
+
+func (c gcsHTTPClient) NewReader(ctx context.Context, key string) (io.ReadCloser, upload.ObjectAttrs, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.objectURL(key), nil, nil)
+	if err != nil {
+		return nil, upload.ObjectAttrs{}, err
+	}
+	var obj uploadtest.GCSObject
+	err = json.NewDecoder(resp.Body).Decode(&obj)
+	resp.Body.Close()
+	if err != nil {
+		return nil, upload.ObjectAttrs{}, err
+	}
+
+	resp, err = c.do(ctx, http.MethodGet, c.objectURL(key)+"?alt=media", nil, nil)
+	if err != nil {
+		return nil, upload.ObjectAttrs{}, err
+	}
+	return resp.Body, gcsAttrs(obj), nil
+}
+
+func gcsAttrs(obj uploadtest.GCSObject) upload.ObjectAttrs {
+	size, _ := strconv.ParseInt(obj.Size, 10, 64)
+	return upload.ObjectAttrs{
+		Key:         obj.Name,
+		Filename:    obj.Metadata["filename"],
+		ContentType: obj.ContentType,
+		Size:        size,
+		Created:     obj.TimeCreated,
+	}
+}
+
+func (c gcsHTTPClient) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, c.objectURL(key), nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c gcsHTTPClient) List(ctx context.Context, prefix string) ([]upload.ObjectAttrs, error) {
+	var out []upload.ObjectAttrs
+	token := ""
+	for {
+		query := url.Values{"prefix": {prefix}}
+		if token != "" {
+			query.Set("pageToken", token)
+		}
+		resp, err := c.do(ctx, http.MethodGet, c.endpoint+"/storage/v1/b/"+c.bucket+"/o?"+query.Encode(), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Items         []uploadtest.GCSObject `json:"items"`
+			NextPageToken string                 `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Items {
+			out = append(out, gcsAttrs(obj))
+		}
+		if page.NextPageToken == "" {
+			return out, nil
+		}
+		token = page.NextPageToken
+	}
+}
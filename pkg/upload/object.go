@@ -0,0 +1,176 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ObjectAttrs describes a stored upload object.
+type ObjectAttrs struct {
+	// Key is the full object key (or object name), including the store prefix.
+	Key string
+
+	// Filename is the original filename from the client.
+	Filename string
+
+	// ContentType is the MIME type of the file.
+	ContentType string
+
+	// Size is the object size in bytes.
+	Size int64
+
+	// Created is when the object was written. Cleanup compares it against
+	// maxAge.
+	Created time.Time
+}
+
+// objectBackend is the object storage API shared by the remote stores.
+// Implementations return ErrNotFound for missing objects.
+type objectBackend interface {
+	put(ctx context.Context, attrs ObjectAttrs, r io.Reader) error
+	get(ctx context.Context, key string) (io.ReadCloser, ObjectAttrs, error)
+	delete(ctx context.Context, key string) error
+	list(ctx context.Context, prefix string) ([]ObjectAttrs, error)
+}
+
+// objectStore implements Store on top of an objectBackend.
+//
+// Temp IDs map to object keys under prefix. Claimed objects are deleted once
+// their reader is closed (or once ClaimTo has copied them), and Cleanup
+// deletes objects older than maxAge by their creation time.
+type objectStore struct {
+	backend objectBackend
+	prefix  string
+	maxSize int64
+}
+
+// Save streams the file to the backend and returns a temp ID.
+func (s *objectStore) Save(filename, contentType string, size int64, r io.Reader) (string, error) {
+	return s.SaveContext(context.Background(), filename, contentType, size, r)
+}
+
+// SaveContext is like Save, but stops the upload when ctx ends.
+func (s *objectStore) SaveContext(ctx context.Context, filename, contentType string, size int64, r io.Reader) (string, error) {
+	if s.maxSize > 0 && size > s.maxSize {
+		return "", ErrTooLarge
+	}
+
+	tempID := generateTempID()
+	attrs := ObjectAttrs{
+		Key:         s.prefix + tempID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+	}
+
+	// Enforce the limit on the bytes actually read, not the declared size.
+	if s.maxSize > 0 {
+		r = &maxSizeReader{r: r, remaining: s.maxSize}
+	}
+
+	if err := s.backend.put(ctx, attrs, r); err != nil {
+		// Best effort: don't leave a partial object behind.
+		s.backend.delete(context.WithoutCancel(ctx), attrs.Key)
+		if errors.Is(err, ErrTooLarge) {
+			return "", ErrTooLarge
+		}
+		return "", fmt.Errorf("upload: store %s: %w", attrs.Key, err)
+	}
+	return tempID, nil
+}
+
+// Claim opens a temp file. The object is deleted when the returned
+// File is closed.
+func (s *objectStore) Claim(tempID string) (*File, error) {
+	if !isValidTempID(tempID) {
+		return nil, ErrNotFound
+	}
+
+	key := s.prefix + tempID
+	rc, attrs, err := s.backend.get(context.Background(), key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		ID:          tempID,
+		Filename:    attrs.Filename,
+		ContentType: attrs.ContentType,
+		Size:        attrs.Size,
+		Reader: &deleteOnCloseObject{ReadCloser: rc, remove: func() error {
+			return s.backend.delete(context.Background(), key)
+		}},
+	}, nil
+}
+
+// ClaimTo streams a temp file to w and deletes it.
+func (s *objectStore) ClaimTo(tempID string, w io.Writer) (*File, error) {
+	file, err := s.Claim(tempID)
+	if err != nil {
+		return nil, err
+	}
+	return copyAndClose(file, w)
+}
+
+// Cleanup deletes temp objects created more than maxAge ago.
+func (s *objectStore) Cleanup(maxAge time.Duration) error {
+	cutoff := time.Now().Add(-maxAge)
+	ctx := context.Background()
+
+	objects, err := s.backend.list(ctx, s.prefix)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, obj := range objects {
+		if obj.Created.Before(cutoff) {
+			if err := s.backend.delete(ctx, obj.Key); err != nil && !errors.Is(err, ErrNotFound) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// maxSizeReader fails with ErrTooLarge once more than remaining bytes have
+// been read.
+type maxSizeReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// deleteOnCloseObject deletes the claimed object when its reader is closed.
+type deleteOnCloseObject struct {
+	io.ReadCloser
+	remove func() error
+}
+
+func (r *deleteOnCloseObject) Close() error {
+	err := r.ReadCloser.Close()
+	if rmErr := r.remove(); rmErr != nil && !errors.Is(rmErr, ErrNotFound) && err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// DefaultS3PartSize is the default multipart part size for S3Store.
+// Files larger than one part are uploaded with multipart upload.
+const DefaultS3PartSize = 8 << 20
+
+// S3Part identifies an uploaded part of a multipart upload.
+type S3Part struct {
+	// Number is the part number, starting at 1.
+	Number int
+
+	// ETag is the ETag returned by UploadPart.
+	ETag string
+}
+
+// S3Client is the object API used by S3Store, scoped to one bucket. It
+// mirrors the object and multipart calls of an S3 client, so a thin adapter
+// over github.com/aws/aws-sdk-go-v2/service/s3 (or any S3-compatible SDK)
+// satisfies it without this package depending on one:
+//
+//	func (b bucket) UploadPart(ctx context.Context, key, uploadID string, number int, body io.Reader, size int64) (string, error) {
+//	    out, err := b.client.UploadPart(ctx, &s3.UploadPartInput{
+//	        Bucket:        &b.name,
+//	        Key:           &key,
+//	        UploadId:      &uploadID,
+//	        PartNumber:    aws.Int32(int32(number)),
+//	        Body:          body,
+//	        ContentLength: &size,
+//	    })
+//	    if err != nil {
+//	        return "", err
+//	    }
+//	    return aws.ToString(out.ETag), nil
+//	}
+//
+// Bodies passed to PutObject and UploadPart must be fully read before the
+// call returns; S3Store reuses their buffers.
+type S3Client interface {
+	// PutObject uploads an object in one request. size is the length of body.
+	PutObject(ctx context.Context, attrs ObjectAttrs, body io.Reader, size int64) error
+
+	// CreateMultipartUpload starts a multipart upload and returns its ID.
+	CreateMultipartUpload(ctx context.Context, attrs ObjectAttrs) (uploadID string, err error)
+
+	// UploadPart uploads one part of a multipart upload and returns its
+	// ETag. size is the length of body.
+	UploadPart(ctx context.Context, key, uploadID string, number int, body io.Reader, size int64) (etag string, err error)
+
+	// CompleteMultipartUpload assembles the parts, in order, into the object.
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []S3Part) error
+
+	// AbortMultipartUpload discards a multipart upload and its parts.
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+
+	// GetObject opens an object. It returns ErrNotFound (or an error
+	// wrapping it) if the object does not exist.
+	GetObject(ctx context.Context, key string) (io.ReadCloser, ObjectAttrs, error)
+
+	// DeleteObject removes an object.
+	DeleteObject(ctx context.Context, key string) error
+
+	// ListObjects returns every object whose key starts with prefix,
+	// including its last-modified time as Created.
+	ListObjects(ctx context.Context, prefix string) ([]ObjectAttrs, error)
+}
+
+// S3Config configures an S3Store.
+type S3Config struct {
+	// Prefix is the key prefix for uploads (e.g., "uploads/temp/").
+	Prefix string
+
+	// MaxSize is the maximum file size in bytes (0 = no limit).
+	MaxSize int64
+
+	// PartSize is the multipart upload part size in bytes.
+	// Default: DefaultS3PartSize. AWS requires at least 5 MiB.
+	PartSize int64
+}
+
+// S3Store stores uploads in an S3-compatible object store behind an
+// S3Client.
+//
+// Files are streamed to the bucket: anything larger than PartSize is sent
+// with a multipart upload, so at most one part is held in memory.
+//
+// Example usage:
+//
+//	store := upload.NewS3Store(bucket{client: s3.NewFromConfig(cfg), name: "my-bucket"}, upload.S3Config{
+//	    Prefix:  "uploads/",
+//	    MaxSize: 50 << 20,
+//	})
+//
+//	r.Post("/upload", upload.Handler(store))
+type S3Store struct {
+	objectStore
+}
+
+// NewS3Store creates a new S3 upload store.
+func NewS3Store(client S3Client, config S3Config) *S3Store {
+	if config.PartSize <= 0 {
+		config.PartSize = DefaultS3PartSize
+	}
+	return &S3Store{objectStore{
+		backend: s3Backend{client: client, partSize: config.PartSize},
+		prefix:  config.Prefix,
+		maxSize: config.MaxSize,
+	}}
+}
+
+// s3Backend adapts an S3Client to objectBackend.
+type s3Backend struct {
+	client   S3Client
+	partSize int64
+}
+
+// put uploads r in a single PutObject if it fits in one part, and with a
+// multipart upload otherwise.
+func (b s3Backend) put(ctx context.Context, attrs ObjectAttrs, r io.Reader) error {
+	// Buffer at most one part. The declared size, when known, keeps small
+	// files from allocating a whole part.
+	var first bytes.Buffer
+	if attrs.Size > 0 {
+		first.Grow(int(min(attrs.Size, b.partSize)) + bytes.MinRead)
+	}
+	n, err := first.ReadFrom(io.LimitReader(r, b.partSize))
+	if err != nil {
+		return err
+	}
+	if n < b.partSize {
+		return b.client.PutObject(ctx, attrs, bytes.NewReader(first.Bytes()), n)
+	}
+	return b.putMultipart(ctx, attrs, first.Bytes(), r)
+}
+
+// putMultipart uploads part and the rest of r as a multipart upload,
+// aborting the upload on failure.
+func (b s3Backend) putMultipart(ctx context.Context, attrs ObjectAttrs, part []byte, r io.Reader) error {
+	uploadID, err := b.client.CreateMultipartUpload(ctx, attrs)
+	if err != nil {
+		return err
+	}
+	if err := b.uploadParts(ctx, attrs.Key, uploadID, part, r); err != nil {
+		// Abort even if ctx was cancelled, so no parts are left behind.
+		b.client.AbortMultipartUpload(context.WithoutCancel(ctx), attrs.Key, uploadID)
+		return err
+	}
+	return nil
+}
+
+// uploadParts uploads buf, then reads each following part from r into the
+// same buffer.
+func (b s3Backend) uploadParts(ctx context.Context, key, uploadID string, buf []byte, r io.Reader) error {
+	var parts []S3Part
+	part := buf
+	for number := 1; len(part) > 0; number++ {
+		etag, err := b.client.UploadPart(ctx, key, uploadID, number, bytes.NewReader(part), int64(len(part)))
+		if err != nil {
+			return err
+		}
+		parts = append(parts, S3Part{Number: number, ETag: etag})
+
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		part = buf[:n]
+	}
+	return b.client.CompleteMultipartUpload(ctx, key, uploadID, parts)
+}
+
+func (b s3Backend) get(ctx context.Context, key string) (io.ReadCloser, ObjectAttrs, error) {
+	return b.client.GetObject(ctx, key)
+}
+
+func (b s3Backend) delete(ctx context.Context, key string) error {
+	return b.client.DeleteObject(ctx, key)
+}
+
+func (b s3Backend) list(ctx context.Context, prefix string) ([]ObjectAttrs, error) {
+	return b.client.ListObjects(ctx, prefix)
+}
@@ -0,0 +1,154 @@
+package upload_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/upload"
+	"github.com/vango-go/vango/pkg/upload/uploadtest"
+)
+
+func TestDiskStore_Conformance(t *testing.T) {
+	uploadtest.TestStore(t, func(t *testing.T, maxSize int64) upload.Store {
+		store, err := upload.NewDiskStore(t.TempDir(), maxSize)
+		if err != nil {
+			t.Fatalf("NewDiskStore: %v", err)
+		}
+		return store
+	})
+}
+
+func newFakeS3Store(t *testing.T, fake *uploadtest.FakeS3, maxSize int64) *upload.S3Store {
+	return upload.NewS3Store(s3HTTPClient{endpoint: fake.URL, bucket: "uploads"}, upload.S3Config{
+		Prefix:   "tmp/",
+		MaxSize:  maxSize,
+		PartSize: 1 << 20,
+	})
+}
+
+func TestS3Store_Conformance(t *testing.T) {
+	uploadtest.TestStore(t, func(t *testing.T, maxSize int64) upload.Store {
+		fake := uploadtest.NewFakeS3(t)
+		fake.MaxKeys = 1 // exercise ListObjectsV2 pagination in Cleanup
+		return newFakeS3Store(t, fake, maxSize)
+	})
+}
+
+func TestS3Store_MemoryConformance(t *testing.T) {
+	uploadtest.TestStore(t, func(t *testing.T, maxSize int64) upload.Store {
+		return upload.NewS3Store(uploadtest.NewMemoryS3(), upload.S3Config{
+			Prefix:   "tmp/",
+			MaxSize:  maxSize,
+			PartSize: 1 << 20,
+		})
+	})
+}
+
+func TestGCSStore_Conformance(t *testing.T) {
+	uploadtest.TestStore(t, func(t *testing.T, maxSize int64) upload.Store {
+		fake := uploadtest.NewFakeGCS(t)
+		fake.MaxResults = 1 // exercise list pagination in Cleanup
+		return upload.NewGCSStore(gcsHTTPClient{endpoint: fake.URL, bucket: "uploads"}, "tmp/", maxSize)
+	})
+}
+
+func TestGCSStore_MemoryConformance(t *testing.T) {
+	uploadtest.TestStore(t, func(t *testing.T, maxSize int64) upload.Store {
+		return upload.NewGCSStore(uploadtest.NewMemoryGCS(), "tmp/", maxSize)
+	})
+}
+
+func TestS3Store_LargeFilesUseMultipartUpload(t *testing.T) {
+	client := uploadtest.NewFakeS3(t)
+	store := newFakeS3Store(t, client, 0)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (5<<20)/16+1)
+	id, err := store.Save("big.bin", "application/octet-stream", int64(len(data)), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := client.CompletedMultipartUploads(); got != 1 {
+		t.Errorf("completed multipart uploads = %d, want 1", got)
+	}
+	if keys := client.Objects("uploads"); len(keys) != 1 || keys[0] != "tmp/"+id {
+		t.Errorf("objects = %v, want [tmp/%s]", keys, id)
+	}
+	file, err := store.Claim(id)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	got, _ := io.ReadAll(file.Reader)
+	file.Reader.Close()
+	if !bytes.Equal(got, data) {
+		t.Errorf("claimed %d bytes, want the %d bytes saved", len(got), len(data))
+	}
+
+	// Small files use a single PutObject.
+	if _, err := store.Save("small.txt", "text/plain", 5, bytes.NewReader([]byte("small"))); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := client.CompletedMultipartUploads(); got != 1 {
+		t.Errorf("completed multipart uploads = %d, want 1", got)
+	}
+}
+
+func TestS3Store_TooLargeAbortsMultipartUpload(t *testing.T) {
+	client := uploadtest.NewFakeS3(t)
+	store := newFakeS3Store(t, client, 3<<20)
+
+	data := make([]byte, 4<<20)
+	_, err := store.Save("big.bin", "application/octet-stream", 1, bytes.NewReader(data))
+	if !errors.Is(err, upload.ErrTooLarge) {
+		t.Fatalf("err = %v, want ErrTooLarge", err)
+	}
+	if got := client.PendingMultipartUploads(); got != 0 {
+		t.Errorf("pending multipart uploads = %d, want 0 (aborted)", got)
+	}
+	if keys := client.Objects("uploads"); len(keys) != 0 {
+		t.Errorf("objects = %v, want none", keys)
+	}
+}
+
+func TestS3Store_CancelledSaveAbortsMultipartUpload(t *testing.T) {
+	client := uploadtest.NewFakeS3(t)
+	store := newFakeS3Store(t, client, 0)
+
+	// Cancel once the first part has been read.
+	ctx, cancel := context.WithCancel(context.Background())
+	data := io.MultiReader(bytes.NewReader(make([]byte, 1<<20)), cancelReader{cancel}, bytes.NewReader(make([]byte, 1<<20)))
+	_, err := store.SaveContext(ctx, "big.bin", "application/octet-stream", 2<<20, data)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if got := client.PendingMultipartUploads(); got != 0 {
+		t.Errorf("pending multipart uploads = %d, want 0 (aborted)", got)
+	}
+	if keys := client.Objects("uploads"); len(keys) != 0 {
+		t.Errorf("objects = %v, want none", keys)
+	}
+}
+
+// cancelReader cancels a context when it is read, then reports EOF.
+type cancelReader struct{ cancel context.CancelFunc }
+
+func (r cancelReader) Read([]byte) (int, error) {
+	r.cancel()
+	return 0, io.EOF
+}
+
+func TestGCSStore_TooLargeAbandonsUpload(t *testing.T) {
+	fake := uploadtest.NewFakeGCS(t)
+	store := upload.NewGCSStore(gcsHTTPClient{endpoint: fake.URL, bucket: "uploads"}, "tmp/", 1<<20)
+
+	// The declared size understates the body, so the limit trips mid-stream.
+	_, err := store.Save("big.bin", "application/octet-stream", 1, bytes.NewReader(make([]byte, 2<<20)))
+	if !errors.Is(err, upload.ErrTooLarge) {
+		t.Fatalf("err = %v, want ErrTooLarge", err)
+	}
+	if names := fake.Objects("uploads"); len(names) != 0 {
+		t.Errorf("objects = %v, want none", names)
+	}
+}
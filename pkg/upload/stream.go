@@ -0,0 +1,318 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrCanceled is returned when the client abandons a streamed upload or its
+// connection drops.
+var ErrCanceled = errors.New("upload: canceled")
+
+// ErrStreamOverrun is returned by Stream.Write when the sender writes more
+// than the stream's window ahead of what the store has consumed.
+var ErrStreamOverrun = errors.New("upload: stream window exceeded")
+
+// ErrIncomplete is returned when a streamed upload ends before its
+// declared size.
+var ErrIncomplete = errors.New("upload: incomplete file")
+
+// Live streams the files picked in (or dropped on) a file input over the
+// live session connection, instead of through a separate Handler endpoint.
+// Attach it to the input with el.OnUpload:
+//
+//	Input(Type("file"), OnUpload(upload.Live{
+//	    Store: uploadStore,
+//	    OnProgress: func(p upload.Progress) {
+//	        if p.Done && p.Err == nil {
+//	            attachment.Set(p.TempID)
+//	        }
+//	    },
+//	}))
+//
+// Each file lands in Store and is claimed by its temp ID as with Handler.
+type Live struct {
+	// Store receives the uploaded files.
+	Store Store
+
+	// Config limits size and type exactly as for HandlerWithConfig.
+	// Nil means DefaultConfig().
+	Config *Config
+
+	// OnProgress is called on the session loop as the file arrives, and
+	// once more with Done set when it is stored or rejected. It may update
+	// signals directly.
+	OnProgress func(Progress)
+}
+
+// Progress reports the state of one streamed file.
+type Progress struct {
+	// Filename is the original filename from the client.
+	Filename string
+
+	// ContentType is the MIME type detected from the first bytes of the
+	// file. It is empty until they arrive.
+	ContentType string
+
+	// Size is the file size declared by the client.
+	Size int64
+
+	// Received is the number of bytes stored so far.
+	Received int64
+
+	// Done is set on the final report.
+	Done bool
+
+	// TempID identifies the stored file for Claim once Done is set and Err
+	// is nil.
+	TempID string
+
+	// Err is why the upload failed: ErrTooLarge, ErrTypeNotAllowed,
+	// ErrCanceled, ErrIncomplete, or a store error.
+	Err error
+}
+
+// StreamOptions configures a Stream.
+type StreamOptions struct {
+	// Window is the most written but unconsumed data the stream buffers.
+	// Writes beyond it fail with ErrStreamOverrun, so the sender must wait
+	// for OnRead before sending more. Zero means no limit.
+	Window int64
+
+	// OnRead is called from the saving goroutine each time the store
+	// consumes data, with the total consumed so far.
+	OnRead func(received int64)
+}
+
+// Stream saves a file that arrives in pieces, such as a file streamed over
+// the live session. Writes are buffered and never block on the store; the
+// file is checked against the Config and handed to Store.Save from a
+// separate goroutine.
+type Stream struct {
+	store    Store
+	config   *Config
+	filename string
+	size     int64
+	opts     StreamOptions
+
+	mu       sync.Mutex
+	queue    [][]byte
+	queued   int64
+	received int64
+	eof      bool
+	err      error
+	wake     chan struct{}
+
+	done        chan struct{}
+	contentType string
+	tempID      string
+	result      error
+}
+
+// NewStream starts saving a file of the given declared size to store.
+// It returns ErrTooLarge if size exceeds the Config's MaxFileSize. A nil
+// config means DefaultConfig().
+func NewStream(store Store, config *Config, filename string, size int64, opts StreamOptions) (*Stream, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if size < 0 || size > config.maxFileSize() {
+		return nil, ErrTooLarge
+	}
+
+	s := &Stream{
+		store:    store,
+		config:   config,
+		filename: filename,
+		size:     size,
+		opts:     opts,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Write queues p for the store. It copies p and returns without waiting.
+func (s *Stream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return 0, s.err
+	}
+	if s.eof {
+		return 0, io.ErrClosedPipe
+	}
+	if s.opts.Window > 0 && s.queued+int64(len(p)) > s.opts.Window {
+		return 0, ErrStreamOverrun
+	}
+	s.queue = append(s.queue, bytes.Clone(p))
+	s.queued += int64(len(p))
+	s.signal()
+	return len(p), nil
+}
+
+// Close marks the end of the file. Use Wait for the result.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eof = true
+	s.signal()
+	return nil
+}
+
+// Abort stops the upload with err. Data already passed to the store may
+// remain until Cleanup removes it.
+func (s *Stream) Abort(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+		s.queue = nil
+		s.queued = 0
+	}
+	s.signal()
+}
+
+// Done is closed once the store has finished with the file.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait blocks until the file is stored or rejected, and returns its temp ID
+// and detected MIME type.
+func (s *Stream) Wait() (tempID, contentType string, err error) {
+	<-s.done
+	return s.tempID, s.contentType, s.result
+}
+
+// Received returns the number of bytes the store has consumed so far.
+func (s *Stream) Received() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received
+}
+
+// ContentType returns the detected MIME type, or "" until the first bytes
+// have been read.
+func (s *Stream) ContentType() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.contentType
+}
+
+// signal wakes the reader. Requires mu.
+func (s *Stream) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Stream) run() {
+	defer close(s.done)
+
+	r := &streamReader{s: s}
+
+	// SECURITY: Detect the type from the bytes, as HandlerWithConfig does.
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(r, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		s.fail(err)
+		return
+	}
+	sniff = sniff[:n]
+	contentType := http.DetectContentType(sniff)
+
+	s.mu.Lock()
+	s.contentType = contentType
+	s.mu.Unlock()
+
+	if err := s.config.checkType(s.filename, contentType); err != nil {
+		s.fail(err)
+		return
+	}
+
+	tempID, err := s.store.Save(s.filename, contentType, s.size, io.MultiReader(bytes.NewReader(sniff), r))
+	if err == nil && r.err != nil && r.err != io.EOF {
+		// The store may not report read errors; size and cancel errors
+		// still reject the file.
+		err = r.err
+	}
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	s.tempID = tempID
+}
+
+// fail records the result error and stops accepting writes.
+func (s *Stream) fail(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.queue = nil
+	s.queued = 0
+	s.mu.Unlock()
+	s.result = err
+}
+
+// streamReader reads the queued chunks of a Stream, enforcing its declared
+// size.
+type streamReader struct {
+	s   *Stream
+	err error
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	s := r.s
+	for {
+		s.mu.Lock()
+		if s.err != nil {
+			s.mu.Unlock()
+			r.err = s.err
+			return 0, r.err
+		}
+		if len(s.queue) > 0 {
+			n := copy(p, s.queue[0])
+			if n == len(s.queue[0]) {
+				s.queue = s.queue[1:]
+			} else {
+				s.queue[0] = s.queue[0][n:]
+			}
+			s.queued -= int64(n)
+			s.received += int64(n)
+			received := s.received
+			s.mu.Unlock()
+
+			if received > s.size {
+				r.err = ErrTooLarge
+				return 0, r.err
+			}
+			if s.opts.OnRead != nil {
+				s.opts.OnRead(received)
+			}
+			return n, nil
+		}
+		if s.eof {
+			received := s.received
+			s.mu.Unlock()
+			if received < s.size {
+				r.err = ErrIncomplete
+			} else {
+				r.err = io.EOF
+			}
+			return 0, r.err
+		}
+		s.mu.Unlock()
+		<-s.wake
+	}
+}
@@ -0,0 +1,135 @@
+package upload_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/upload"
+)
+
+func pngBytes(n int) []byte {
+	b := make([]byte, n)
+	copy(b, "\x89PNG\r\n\x1a\n")
+	return b
+}
+
+func TestStream_SavesChunks(t *testing.T) {
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+	content := pngBytes(100_000)
+
+	var reported atomic.Int64
+	s, err := upload.NewStream(store, nil, "photo.png", int64(len(content)), upload.StreamOptions{
+		OnRead: func(received int64) { reported.Store(received) },
+	})
+	if err != nil {
+		t.Fatalf("NewStream: %v", err)
+	}
+	for off := 0; off < len(content); off += 30_000 {
+		end := min(off+30_000, len(content))
+		if _, err := s.Write(content[off:end]); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	s.Close()
+
+	tempID, contentType, err := s.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if contentType != "image/png" {
+		t.Errorf("contentType = %q, want image/png", contentType)
+	}
+	if got := reported.Load(); got != int64(len(content)) {
+		t.Errorf("OnRead reported %d, want %d", got, len(content))
+	}
+
+	file, err := store.Claim(tempID)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	defer file.Close()
+	data, _ := io.ReadAll(file.Reader)
+	if !bytes.Equal(data, content) {
+		t.Error("stored content mismatch")
+	}
+}
+
+func TestStream_EnforcesConfig(t *testing.T) {
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+
+	if _, err := upload.NewStream(store, &upload.Config{MaxFileSize: 10}, "a.png", 11, upload.StreamOptions{}); !errors.Is(err, upload.ErrTooLarge) {
+		t.Errorf("oversized declaration error = %v, want ErrTooLarge", err)
+	}
+
+	s, _ := upload.NewStream(store, &upload.Config{AllowedTypes: []string{"image/png"}}, "a.txt", 5, upload.StreamOptions{})
+	s.Write([]byte("hello"))
+	s.Close()
+	if _, _, err := s.Wait(); !errors.Is(err, upload.ErrTypeNotAllowed) {
+		t.Errorf("disallowed type error = %v, want ErrTypeNotAllowed", err)
+	}
+
+	s, _ = upload.NewStream(store, nil, "a.png", 5, upload.StreamOptions{})
+	s.Write(pngBytes(8))
+	s.Close()
+	if _, _, err := s.Wait(); !errors.Is(err, upload.ErrTooLarge) {
+		t.Errorf("more data than declared error = %v, want ErrTooLarge", err)
+	}
+
+	s, _ = upload.NewStream(store, nil, "a.png", 1000, upload.StreamOptions{})
+	s.Write(pngBytes(600))
+	s.Close()
+	if _, _, err := s.Wait(); !errors.Is(err, upload.ErrIncomplete) {
+		t.Errorf("short file error = %v, want ErrIncomplete", err)
+	}
+}
+
+func TestStream_WindowAndAbort(t *testing.T) {
+	store, _ := upload.NewDiskStore(t.TempDir(), 0)
+
+	// Abort before any data so the window is never drained.
+	s, _ := upload.NewStream(store, nil, "a.png", 1<<20, upload.StreamOptions{Window: 64})
+	s.Abort(upload.ErrCanceled)
+	if _, _, err := s.Wait(); !errors.Is(err, upload.ErrCanceled) {
+		t.Errorf("aborted stream error = %v, want ErrCanceled", err)
+	}
+	if _, err := s.Write([]byte("x")); !errors.Is(err, upload.ErrCanceled) {
+		t.Errorf("Write after abort error = %v, want ErrCanceled", err)
+	}
+
+	blocked := &blockingStore{started: make(chan struct{}), release: make(chan struct{})}
+	s, _ = upload.NewStream(blocked, nil, "a.png", 1<<20, upload.StreamOptions{Window: 1024})
+	if _, err := s.Write(pngBytes(512)); err != nil {
+		t.Fatalf("Write within window: %v", err)
+	}
+	<-blocked.started
+	if _, err := s.Write(make([]byte, 1024)); err != nil {
+		t.Fatalf("Write after sniff drained: %v", err)
+	}
+	if _, err := s.Write([]byte{1}); !errors.Is(err, upload.ErrStreamOverrun) {
+		t.Errorf("Write past window error = %v, want ErrStreamOverrun", err)
+	}
+	s.Abort(upload.ErrCanceled)
+	close(blocked.release)
+	s.Wait()
+}
+
+// blockingStore waits for release before reading the file.
+type blockingStore struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingStore) Save(filename, contentType string, size int64, r io.Reader) (string, error) {
+	close(b.started)
+	<-b.release
+	_, err := io.Copy(io.Discard, r)
+	return "id", err
+}
+
+func (b *blockingStore) Claim(string) (*upload.File, error) { return nil, upload.ErrNotFound }
+
+func (b *blockingStore) Cleanup(time.Duration) error { return nil }
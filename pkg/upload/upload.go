@@ -1,6 +1,7 @@
 package upload
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -25,7 +26,8 @@ var ErrTooLarge = errors.New("upload: file too large")
 var ErrTypeNotAllowed = errors.New("upload: file type not allowed")
 
 // Store is the interface for upload storage backends.
-// Implement this interface to use S3, GCS, or other storage.
+// DiskStore, S3Store and GCSStore are provided; implement this interface for
+// other storage. Use uploadtest.TestStore to check an implementation.
 type Store interface {
 	// Save stores the uploaded file and returns a temp ID.
 	// The file is stored temporarily until Claim is called.
@@ -40,6 +42,23 @@ type Store interface {
 	Cleanup(maxAge time.Duration) error
 }
 
+// StreamClaimer is implemented by stores that can claim a temp file by
+// streaming it to a writer, without handing out an open reader.
+// All stores in this package implement it.
+type StreamClaimer interface {
+	// ClaimTo copies the temp file to w and removes it. The returned File
+	// has no Reader.
+	ClaimTo(tempID string, w io.Writer) (*File, error)
+}
+
+// ContextSaver is implemented by stores whose Save can be cancelled.
+// HandlerWithConfig saves through it with the request context, so an upload
+// stops when the client goes away. S3Store and GCSStore implement it.
+type ContextSaver interface {
+	// SaveContext is like Store.Save, but stops when ctx ends.
+	SaveContext(ctx context.Context, filename string, contentType string, size int64, r io.Reader) (tempID string, err error)
+}
+
 // File represents an uploaded file.
 type File struct {
 	// ID is the unique identifier for this upload.
@@ -86,10 +105,7 @@ func Handler(store Store) http.Handler {
 
 // HandlerWithConfig returns an upload handler with custom configuration.
 func HandlerWithConfig(store Store, config *Config) http.Handler {
-	maxSize := config.MaxFileSize
-	if maxSize <= 0 {
-		maxSize = 10 * 1024 * 1024 // 10MB default
-	}
+	maxSize := config.maxFileSize()
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -135,31 +151,13 @@ func HandlerWithConfig(store Store, config *Config) http.Handler {
 			return
 		}
 
-		// Check MIME type if AllowedTypes is configured
-		if len(config.AllowedTypes) > 0 && !isTypeAllowed(contentType, config.AllowedTypes) {
-			http.Error(w, "File type not allowed", http.StatusUnsupportedMediaType)
-			return
-		}
-
-		// Optional extension allowlist.
-		if len(config.AllowedExtensions) > 0 && !isExtensionAllowed(header.Filename, config.AllowedExtensions) {
-			http.Error(w, "File type not allowed", http.StatusUnsupportedMediaType)
-			return
-		}
-
-		// Optional extension-to-mime match enforcement.
-		if config.RequireExtensionMatch && !extensionMatchesType(header.Filename, contentType) {
+		if err := config.checkType(header.Filename, contentType); err != nil {
 			http.Error(w, "File type not allowed", http.StatusUnsupportedMediaType)
 			return
 		}
 
 		// Store the file
-		tempID, err := store.Save(
-			header.Filename,
-			contentType,
-			header.Size,
-			file,
-		)
+		tempID, err := save(r.Context(), store, header.Filename, contentType, header.Size, file)
 		if err != nil {
 			if errors.Is(err, ErrTooLarge) {
 				http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
@@ -177,6 +175,14 @@ func HandlerWithConfig(store Store, config *Config) http.Handler {
 	})
 }
 
+// save stores a file through SaveContext if store implements ContextSaver.
+func save(ctx context.Context, store Store, filename, contentType string, size int64, r io.Reader) (string, error) {
+	if cs, ok := store.(ContextSaver); ok {
+		return cs.SaveContext(ctx, filename, contentType, size, r)
+	}
+	return store.Save(filename, contentType, size, r)
+}
+
 // Claim retrieves a temp file by ID.
 // Call this in your Vango handler after receiving the temp_id.
 //
@@ -192,6 +198,40 @@ func Claim(store Store, tempID string) (*File, error) {
 	return store.Claim(tempID)
 }
 
+// ClaimTo claims a temp file by streaming its contents to w.
+// The temp file is removed once it has been copied.
+//
+// Example:
+//
+//	dst, _ := os.Create(filepath.Join("attachments", name))
+//	defer dst.Close()
+//	file, err := upload.ClaimTo(store, tempID, dst)
+func ClaimTo(store Store, tempID string, w io.Writer) (*File, error) {
+	if sc, ok := store.(StreamClaimer); ok {
+		return sc.ClaimTo(tempID, w)
+	}
+	file, err := store.Claim(tempID)
+	if err != nil {
+		return nil, err
+	}
+	return copyAndClose(file, w)
+}
+
+// copyAndClose copies a claimed file to w and closes it, which removes the
+// temp file.
+func copyAndClose(file *File, w io.Writer) (*File, error) {
+	_, err := io.Copy(w, file.Reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	file.Reader = nil
+	file.Path = ""
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
 // Config holds configuration for the upload handler.
 type Config struct {
 	// MaxFileSize is the maximum allowed file size in bytes.
@@ -223,6 +263,29 @@ func DefaultConfig() *Config {
 	}
 }
 
+// checkType applies AllowedTypes, AllowedExtensions and
+// RequireExtensionMatch to a file with the given name and detected MIME type.
+func (c *Config) checkType(filename, contentType string) error {
+	if len(c.AllowedTypes) > 0 && !isTypeAllowed(contentType, c.AllowedTypes) {
+		return ErrTypeNotAllowed
+	}
+	if len(c.AllowedExtensions) > 0 && !isExtensionAllowed(filename, c.AllowedExtensions) {
+		return ErrTypeNotAllowed
+	}
+	if c.RequireExtensionMatch && !extensionMatchesType(filename, contentType) {
+		return ErrTypeNotAllowed
+	}
+	return nil
+}
+
+// maxFileSize returns MaxFileSize, or the 10MB default if it is unset.
+func (c *Config) maxFileSize() int64 {
+	if c.MaxFileSize <= 0 {
+		return 10 * 1024 * 1024
+	}
+	return c.MaxFileSize
+}
+
 func normalizeMIMEType(contentType string) string {
 	if idx := strings.Index(contentType, ";"); idx != -1 {
 		contentType = contentType[:idx]
@@ -0,0 +1,231 @@
+package uploadtest
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// FakeGCS is an in-process Google Cloud Storage HTTP server for tests.
+//
+// It implements the subset of the GCS JSON API behind upload.GCSClient:
+// multipart media uploads, object metadata and media downloads, deletes
+// and paginated listing. Unlike MemoryGCS, it lets a GCSClient adapter be
+// tested over the wire. Requests are not authenticated.
+type FakeGCS struct {
+	// URL is the server's endpoint.
+	URL string
+
+	// MaxResults limits list pages, to exercise pagination.
+	// Default: 1000.
+	MaxResults int
+
+	mu      sync.Mutex
+	objects map[string]*fakeGCSObject // "bucket/name" -> object
+}
+
+type fakeGCSObject struct {
+	resource GCSObject
+	data     []byte
+}
+
+// GCSObject is the JSON object resource served by FakeGCS.
+type GCSObject struct {
+	Bucket      string            `json:"bucket"`
+	Name        string            `json:"name"`
+	ContentType string            `json:"contentType,omitempty"`
+	Size        string            `json:"size,omitempty"`
+	TimeCreated time.Time         `json:"timeCreated,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// NewFakeGCS starts a fake GCS server that is closed when the test ends.
+func NewFakeGCS(t testing.TB) *FakeGCS {
+	t.Helper()
+	f := &FakeGCS{objects: make(map[string]*fakeGCSObject)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	f.URL = srv.URL
+	return f
+}
+
+// Objects returns the names of the stored objects in bucket, sorted.
+func (f *FakeGCS) Objects(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for k := range f.objects {
+		if rest, ok := strings.CutPrefix(k, bucket+"/"); ok {
+			names = append(names, rest)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeHTTP implements http.Handler.
+func (f *FakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Object names are escaped as a single path segment, so split the
+	// escaped path before unescaping.
+	path := r.URL.EscapedPath()
+	isUpload := strings.HasPrefix(path, "/upload/")
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/upload"), "/storage/v1/b/")
+	bucket, rest, _ := strings.Cut(path, "/")
+	if bucket == "" || (rest != "o" && !strings.HasPrefix(rest, "o/")) {
+		writeGCSError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(strings.TrimPrefix(rest, "o"), "/"))
+	if err != nil {
+		writeGCSError(w, http.StatusBadRequest, "invalid object name")
+		return
+	}
+	q := r.URL.Query()
+
+	switch {
+	case isUpload && r.Method == http.MethodPost && name == "":
+		if q.Get("uploadType") != "multipart" {
+			writeGCSError(w, http.StatusBadRequest, "unsupported uploadType")
+			return
+		}
+		f.insert(w, r, bucket)
+	case name == "" && r.Method == http.MethodGet:
+		f.list(w, bucket, q.Get("prefix"), q.Get("pageToken"))
+	case name == "":
+		writeGCSError(w, http.StatusMethodNotAllowed, r.Method)
+	case r.Method == http.MethodGet:
+		f.get(w, bucket, name, q.Get("alt") == "media")
+	case r.Method == http.MethodDelete:
+		f.mu.Lock()
+		_, ok := f.objects[bucket+"/"+name]
+		delete(f.objects, bucket+"/"+name)
+		f.mu.Unlock()
+		if !ok {
+			writeGCSError(w, http.StatusNotFound, "No such object: "+bucket+"/"+name)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeGCSError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+// insert stores an object from a multipart/related upload: a JSON resource
+// followed by the media. Nothing is stored unless the whole body arrives.
+func (f *FakeGCS) insert(w http.ResponseWriter, r *http.Request, bucket string) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/related" {
+		writeGCSError(w, http.StatusBadRequest, "expected multipart/related body")
+		return
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+
+	part, err := mr.NextPart()
+	if err != nil {
+		writeGCSError(w, http.StatusBadRequest, "missing metadata part")
+		return
+	}
+	var resource GCSObject
+	if err := json.NewDecoder(part).Decode(&resource); err != nil || resource.Name == "" {
+		writeGCSError(w, http.StatusBadRequest, "invalid metadata part")
+		return
+	}
+
+	part, err = mr.NextPart()
+	if err != nil {
+		writeGCSError(w, http.StatusBadRequest, "missing media part")
+		return
+	}
+	if resource.ContentType == "" {
+		resource.ContentType = part.Header.Get("Content-Type")
+	}
+	data, err := io.ReadAll(part)
+	if err != nil {
+		writeGCSError(w, http.StatusBadRequest, "incomplete media: "+err.Error())
+		return
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		writeGCSError(w, http.StatusBadRequest, "incomplete multipart body")
+		return
+	}
+
+	resource.Bucket = bucket
+	resource.Size = strconv.Itoa(len(data))
+	resource.TimeCreated = time.Now().UTC()
+	f.mu.Lock()
+	f.objects[bucket+"/"+resource.Name] = &fakeGCSObject{resource: resource, data: data}
+	f.mu.Unlock()
+
+	writeJSON(w, resource)
+}
+
+func (f *FakeGCS) get(w http.ResponseWriter, bucket, name string, media bool) {
+	f.mu.Lock()
+	obj, ok := f.objects[bucket+"/"+name]
+	f.mu.Unlock()
+	if !ok {
+		writeGCSError(w, http.StatusNotFound, "No such object: "+bucket+"/"+name)
+		return
+	}
+	if !media {
+		writeJSON(w, obj.resource)
+		return
+	}
+	w.Header().Set("Content-Type", obj.resource.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	w.Write(obj.data)
+}
+
+func (f *FakeGCS) list(w http.ResponseWriter, bucket, prefix, token string) {
+	maxResults := f.MaxResults
+	if maxResults <= 0 {
+		maxResults = 1000
+	}
+
+	result := struct {
+		Items         []GCSObject `json:"items,omitempty"`
+		NextPageToken string      `json:"nextPageToken,omitempty"`
+	}{}
+
+	f.mu.Lock()
+	var names []string
+	for k := range f.objects {
+		if rest, ok := strings.CutPrefix(k, bucket+"/"); ok && strings.HasPrefix(rest, prefix) && rest > token {
+			names = append(names, rest)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > maxResults {
+		names = names[:maxResults]
+		result.NextPageToken = names[len(names)-1]
+	}
+	for _, name := range names {
+		result.Items = append(result.Items, f.objects[bucket+"/"+name].resource)
+	}
+	f.mu.Unlock()
+
+	writeJSON(w, result)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeGCSError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"code": status, "message": message},
+	})
+}
@@ -0,0 +1,332 @@
+package uploadtest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// FakeS3 is an in-process S3-compatible HTTP server for tests.
+//
+// It implements the subset of the S3 REST API behind upload.S3Client with
+// path-style addressing: PutObject, GetObject, DeleteObject, ListObjectsV2
+// and multipart uploads. Unlike MemoryS3, it lets an S3Client adapter be
+// tested over the wire. Requests are not authenticated.
+type FakeS3 struct {
+	// URL is the server's endpoint.
+	URL string
+
+	// MaxKeys limits ListObjectsV2 pages, to exercise pagination.
+	// Default: 1000.
+	MaxKeys int
+
+	mu        sync.Mutex
+	objects   map[string]*fakeObject // "bucket/key" -> object
+	uploads   map[string]*fakeUpload // upload ID -> in-progress multipart upload
+	completed int
+	nextID    int
+}
+
+type fakeObject struct {
+	data     []byte
+	header   http.Header // Content-Type and x-amz-meta-* headers
+	modified time.Time
+}
+
+type fakeUpload struct {
+	bucket, key string
+	header      http.Header
+	parts       map[int][]byte
+}
+
+// NewFakeS3 starts a fake S3 server that is closed when the test ends.
+func NewFakeS3(t testing.TB) *FakeS3 {
+	t.Helper()
+	f := &FakeS3{
+		objects: make(map[string]*fakeObject),
+		uploads: make(map[string]*fakeUpload),
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	f.URL = srv.URL
+	return f
+}
+
+// Objects returns the keys of the stored objects in bucket, sorted.
+func (f *FakeS3) Objects(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for k := range f.objects {
+		if rest, ok := strings.CutPrefix(k, bucket+"/"); ok {
+			keys = append(keys, rest)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CompletedMultipartUploads returns the number of completed multipart uploads.
+func (f *FakeS3) CompletedMultipartUploads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.completed
+}
+
+// PendingMultipartUploads returns the number of multipart uploads that were
+// neither completed nor aborted.
+func (f *FakeS3) PendingMultipartUploads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.uploads)
+}
+
+// ServeHTTP implements http.Handler.
+func (f *FakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket == "" {
+		writeS3Error(w, http.StatusBadRequest, "InvalidRequest", "bucket required")
+		return
+	}
+	q := r.URL.Query()
+
+	switch {
+	case key == "" && r.Method == http.MethodGet && q.Get("list-type") == "2":
+		f.list(w, bucket, q.Get("prefix"), q.Get("continuation-token"))
+	case key == "":
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented", "unsupported bucket operation")
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		f.createMultipart(w, r, bucket, key)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		f.uploadPart(w, r, q.Get("uploadId"), q.Get("partNumber"))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		f.completeMultipart(w, r, q.Get("uploadId"))
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		f.abortMultipart(w, q.Get("uploadId"))
+	case r.Method == http.MethodPut:
+		f.putObject(w, r, bucket, key)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		f.getObject(w, r, bucket, key)
+	case r.Method == http.MethodDelete:
+		f.mu.Lock()
+		delete(f.objects, bucket+"/"+key)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+// objectHeader returns the headers stored with an object.
+func objectHeader(r *http.Request) http.Header {
+	h := http.Header{}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		h.Set("Content-Type", ct)
+	}
+	for name, values := range r.Header {
+		if strings.HasPrefix(strings.ToLower(name), "x-amz-meta-") {
+			h[name] = values
+		}
+	}
+	return h
+}
+
+func (f *FakeS3) putObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	if r.ContentLength < 0 {
+		writeS3Error(w, http.StatusLengthRequired, "MissingContentLength", "Content-Length required")
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	f.mu.Lock()
+	f.objects[bucket+"/"+key] = &fakeObject{data: data, header: objectHeader(r), modified: time.Now()}
+	f.mu.Unlock()
+	w.Header().Set("ETag", etag(data))
+}
+
+func (f *FakeS3) getObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	f.mu.Lock()
+	obj, ok := f.objects[bucket+"/"+key]
+	f.mu.Unlock()
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+	for name, values := range obj.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	w.Header().Set("Last-Modified", obj.modified.UTC().Format(http.TimeFormat))
+	w.Header().Set("ETag", etag(obj.data))
+	if r.Method == http.MethodGet {
+		w.Write(obj.data)
+	}
+}
+
+func (f *FakeS3) list(w http.ResponseWriter, bucket, prefix, token string) {
+	maxKeys := f.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	type content struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int       `xml:"Size"`
+	}
+	result := struct {
+		XMLName               xml.Name  `xml:"ListBucketResult"`
+		Name                  string    `xml:"Name"`
+		Prefix                string    `xml:"Prefix"`
+		KeyCount              int       `xml:"KeyCount"`
+		IsTruncated           bool      `xml:"IsTruncated"`
+		NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		Contents              []content `xml:"Contents"`
+	}{Name: bucket, Prefix: prefix}
+
+	f.mu.Lock()
+	var keys []string
+	for k := range f.objects {
+		if rest, ok := strings.CutPrefix(k, bucket+"/"); ok && strings.HasPrefix(rest, prefix) && rest > token {
+			keys = append(keys, rest)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+	for _, k := range keys {
+		obj := f.objects[bucket+"/"+k]
+		result.Contents = append(result.Contents, content{Key: k, LastModified: obj.modified.UTC(), Size: len(obj.data)})
+	}
+	f.mu.Unlock()
+	result.KeyCount = len(result.Contents)
+
+	writeXML(w, result)
+}
+
+func (f *FakeS3) createMultipart(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	f.mu.Lock()
+	f.nextID++
+	id := fmt.Sprintf("upload-%d", f.nextID)
+	f.uploads[id] = &fakeUpload{bucket: bucket, key: key, header: objectHeader(r), parts: make(map[int][]byte)}
+	f.mu.Unlock()
+
+	writeXML(w, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}{Bucket: bucket, Key: key, UploadID: id})
+}
+
+func (f *FakeS3) uploadPart(w http.ResponseWriter, r *http.Request, uploadID, partNumber string) {
+	number, err := strconv.Atoi(partNumber)
+	if err != nil || number < 1 || number > 10000 {
+		writeS3Error(w, http.StatusBadRequest, "InvalidArgument", "invalid part number")
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	up, ok := f.uploads[uploadID]
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+	up.parts[number] = data
+	w.Header().Set("ETag", etag(data))
+}
+
+func (f *FakeS3) completeMultipart(w http.ResponseWriter, r *http.Request, uploadID string) {
+	var req struct {
+		Parts []struct {
+			PartNumber int    `xml:"PartNumber"`
+			ETag       string `xml:"ETag"`
+		} `xml:"Part"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeS3Error(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	up, ok := f.uploads[uploadID]
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+
+	var data bytes.Buffer
+	for i, p := range req.Parts {
+		part, ok := up.parts[p.PartNumber]
+		if !ok || etag(part) != p.ETag {
+			writeS3Error(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d not found", p.PartNumber))
+			return
+		}
+		if i > 0 && p.PartNumber <= req.Parts[i-1].PartNumber {
+			writeS3Error(w, http.StatusBadRequest, "InvalidPartOrder", "parts must be in ascending order")
+			return
+		}
+		data.Write(part)
+	}
+
+	f.objects[up.bucket+"/"+up.key] = &fakeObject{data: data.Bytes(), header: up.header, modified: time.Now()}
+	delete(f.uploads, uploadID)
+	f.completed++
+
+	writeXML(w, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+	}{Bucket: up.bucket, Key: up.key})
+}
+
+func (f *FakeS3) abortMultipart(w http.ResponseWriter, uploadID string) {
+	f.mu.Lock()
+	_, ok := f.uploads[uploadID]
+	delete(f.uploads, uploadID)
+	f.mu.Unlock()
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(v)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
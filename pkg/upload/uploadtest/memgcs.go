@@ -0,0 +1,112 @@
+package uploadtest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vango/pkg/upload"
+)
+
+// MemoryGCS is an in-memory upload.GCSClient with GCS writer semantics:
+// objects become visible when the writer is closed, and a writer whose
+// context was cancelled before Close is discarded.
+type MemoryGCS struct {
+	mu      sync.Mutex
+	objects map[string]*memObject
+}
+
+type memObject struct {
+	attrs upload.ObjectAttrs
+	data  []byte
+}
+
+// NewMemoryGCS creates an empty MemoryGCS.
+func NewMemoryGCS() *MemoryGCS {
+	return &MemoryGCS{objects: make(map[string]*memObject)}
+}
+
+// Keys returns the keys of the stored objects, sorted.
+func (m *MemoryGCS) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.objects))
+	for k := range m.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewWriter implements upload.GCSClient.
+func (m *MemoryGCS) NewWriter(ctx context.Context, attrs upload.ObjectAttrs) io.WriteCloser {
+	return &memWriter{ctx: ctx, gcs: m, attrs: attrs}
+}
+
+// NewReader implements upload.GCSClient.
+func (m *MemoryGCS) NewReader(ctx context.Context, key string) (io.ReadCloser, upload.ObjectAttrs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return nil, upload.ObjectAttrs{}, upload.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), obj.attrs, nil
+}
+
+// Delete implements upload.GCSClient.
+func (m *MemoryGCS) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[key]; !ok {
+		return upload.ErrNotFound
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+// List implements upload.GCSClient.
+func (m *MemoryGCS) List(ctx context.Context, prefix string) ([]upload.ObjectAttrs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []upload.ObjectAttrs
+	for k, obj := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, obj.attrs)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+type memWriter struct {
+	ctx   context.Context
+	gcs   *MemoryGCS
+	attrs upload.ObjectAttrs
+	buf   bytes.Buffer
+}
+
+func (w *memWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.buf.Write(p)
+}
+
+func (w *memWriter) Close() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	attrs := w.attrs
+	attrs.Size = int64(w.buf.Len())
+	attrs.Created = time.Now()
+
+	w.gcs.mu.Lock()
+	w.gcs.objects[attrs.Key] = &memObject{attrs: attrs, data: w.buf.Bytes()}
+	w.gcs.mu.Unlock()
+	return nil
+}
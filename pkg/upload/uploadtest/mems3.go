@@ -0,0 +1,216 @@
+package uploadtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vango-go/vango/pkg/upload"
+)
+
+// MemoryS3 is an in-memory upload.S3Client with S3 multipart semantics:
+// parts are invisible until CompleteMultipartUpload assembles them in part
+// number order, and their ETags must match.
+type MemoryS3 struct {
+	mu        sync.Mutex
+	objects   map[string]*memObject
+	uploads   map[string]*memUpload // upload ID -> in-progress multipart upload
+	nextID    int
+	completed int
+}
+
+type memUpload struct {
+	attrs upload.ObjectAttrs
+	parts map[int][]byte
+}
+
+// NewMemoryS3 creates an empty MemoryS3.
+func NewMemoryS3() *MemoryS3 {
+	return &MemoryS3{
+		objects: make(map[string]*memObject),
+		uploads: make(map[string]*memUpload),
+	}
+}
+
+// Keys returns the keys of the stored objects, sorted.
+func (m *MemoryS3) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.objects))
+	for k := range m.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CompletedMultipartUploads returns the number of completed multipart uploads.
+func (m *MemoryS3) CompletedMultipartUploads() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.completed
+}
+
+// PendingMultipartUploads returns the number of multipart uploads that were
+// neither completed nor aborted.
+func (m *MemoryS3) PendingMultipartUploads() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.uploads)
+}
+
+// readBody reads exactly size bytes of body.
+func readBody(ctx context.Context, body io.Reader, size int64) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != size {
+		return nil, fmt.Errorf("uploadtest: body is %d bytes, size says %d", len(data), size)
+	}
+	return data, nil
+}
+
+// PutObject implements upload.S3Client.
+func (m *MemoryS3) PutObject(ctx context.Context, attrs upload.ObjectAttrs, body io.Reader, size int64) error {
+	data, err := readBody(ctx, body, size)
+	if err != nil {
+		return err
+	}
+	m.store(attrs, data)
+	return nil
+}
+
+func (m *MemoryS3) store(attrs upload.ObjectAttrs, data []byte) {
+	attrs.Size = int64(len(data))
+	attrs.Created = time.Now()
+	m.mu.Lock()
+	m.objects[attrs.Key] = &memObject{attrs: attrs, data: data}
+	m.mu.Unlock()
+}
+
+// CreateMultipartUpload implements upload.S3Client.
+func (m *MemoryS3) CreateMultipartUpload(ctx context.Context, attrs upload.ObjectAttrs) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	id := fmt.Sprintf("upload-%d", m.nextID)
+	m.uploads[id] = &memUpload{attrs: attrs, parts: make(map[int][]byte)}
+	return id, nil
+}
+
+// UploadPart implements upload.S3Client.
+func (m *MemoryS3) UploadPart(ctx context.Context, key, uploadID string, number int, body io.Reader, size int64) (string, error) {
+	if number < 1 || number > 10000 {
+		return "", fmt.Errorf("uploadtest: invalid part number %d", number)
+	}
+	data, err := readBody(ctx, body, size)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	up, ok := m.uploads[uploadID]
+	if !ok || up.attrs.Key != key {
+		return "", fmt.Errorf("uploadtest: no multipart upload %s for %s", uploadID, key)
+	}
+	up.parts[number] = data
+	return etag(data), nil
+}
+
+// CompleteMultipartUpload implements upload.S3Client.
+func (m *MemoryS3) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []upload.S3Part) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	up, ok := m.uploads[uploadID]
+	if !ok || up.attrs.Key != key {
+		m.mu.Unlock()
+		return fmt.Errorf("uploadtest: no multipart upload %s for %s", uploadID, key)
+	}
+	var data bytes.Buffer
+	for i, p := range parts {
+		part, ok := up.parts[p.Number]
+		if !ok || etag(part) != p.ETag {
+			m.mu.Unlock()
+			return fmt.Errorf("uploadtest: part %d not found", p.Number)
+		}
+		if i > 0 && p.Number <= parts[i-1].Number {
+			m.mu.Unlock()
+			return fmt.Errorf("uploadtest: parts must be in ascending order")
+		}
+		data.Write(part)
+	}
+	delete(m.uploads, uploadID)
+	m.completed++
+	m.mu.Unlock()
+
+	m.store(up.attrs, data.Bytes())
+	return nil
+}
+
+// AbortMultipartUpload implements upload.S3Client.
+func (m *MemoryS3) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.uploads[uploadID]; !ok {
+		return fmt.Errorf("uploadtest: no multipart upload %s", uploadID)
+	}
+	delete(m.uploads, uploadID)
+	return nil
+}
+
+// GetObject implements upload.S3Client.
+func (m *MemoryS3) GetObject(ctx context.Context, key string) (io.ReadCloser, upload.ObjectAttrs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return nil, upload.ObjectAttrs{}, upload.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), obj.attrs, nil
+}
+
+// DeleteObject implements upload.S3Client. Like S3, deleting a missing
+// object succeeds.
+func (m *MemoryS3) DeleteObject(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+// ListObjects implements upload.S3Client.
+func (m *MemoryS3) ListObjects(ctx context.Context, prefix string) ([]upload.ObjectAttrs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []upload.ObjectAttrs
+	for k, obj := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, obj.attrs)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
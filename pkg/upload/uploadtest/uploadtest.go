@@ -0,0 +1,261 @@
+// Package uploadtest provides a conformance suite for upload.Store
+// implementations and in-process fakes of the remote object stores.
+//
+// Every store shipped in package upload runs the same suite, so the
+// Save/Claim/ClaimTo/Cleanup semantics are identical across DiskStore,
+// S3Store and GCSStore. Custom stores can run it too:
+//
+//	func TestMyStore(t *testing.T) {
+//	    uploadtest.TestStore(t, func(t *testing.T, maxSize int64) upload.Store {
+//	        return mystore.New(t.TempDir(), maxSize)
+//	    })
+//	}
+package uploadtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-go/vango/pkg/upload"
+)
+
+// TestStore runs the upload.Store conformance tests. newStore is called once
+// per subtest and must return an empty store that rejects files larger than
+// maxSize bytes (0 = no limit).
+func TestStore(t *testing.T, newStore func(t *testing.T, maxSize int64) upload.Store) {
+	t.Helper()
+
+	t.Run("SaveAndClaim", func(t *testing.T) {
+		store := newStore(t, 0)
+		data := []byte("hello, upload")
+
+		id := save(t, store, "résumé final.txt", "text/plain", data)
+		file, err := store.Claim(id)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		defer file.Close()
+
+		if file.ID != id {
+			t.Errorf("ID = %q, want %q", file.ID, id)
+		}
+		if file.Filename != "résumé final.txt" {
+			t.Errorf("Filename = %q, want %q", file.Filename, "résumé final.txt")
+		}
+		if file.ContentType != "text/plain" {
+			t.Errorf("ContentType = %q, want %q", file.ContentType, "text/plain")
+		}
+		if file.Size != int64(len(data)) {
+			t.Errorf("Size = %d, want %d", file.Size, len(data))
+		}
+		if got := read(t, file); !bytes.Equal(got, data) {
+			t.Errorf("contents = %q, want %q", got, data)
+		}
+	})
+
+	t.Run("LargeFile", func(t *testing.T) {
+		store := newStore(t, 0)
+		data := make([]byte, 3<<20+17)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+
+		// Declared size unknown, as for a streamed request body.
+		id, err := store.Save("large.bin", "application/octet-stream", -1, bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		file, err := store.Claim(id)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		defer file.Close()
+		if got := read(t, file); !bytes.Equal(got, data) {
+			t.Errorf("contents differ: got %d bytes, want %d", len(got), len(data))
+		}
+	})
+
+	t.Run("EmptyFile", func(t *testing.T) {
+		store := newStore(t, 0)
+		id := save(t, store, "empty.txt", "text/plain", nil)
+		file, err := store.Claim(id)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		defer file.Close()
+		if got := read(t, file); len(got) != 0 {
+			t.Errorf("contents = %q, want empty", got)
+		}
+	})
+
+	t.Run("ClaimConsumes", func(t *testing.T) {
+		store := newStore(t, 0)
+		id := save(t, store, "a.txt", "text/plain", []byte("once"))
+
+		file, err := store.Claim(id)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		read(t, file)
+		if err := file.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+
+		if _, err := store.Claim(id); !errors.Is(err, upload.ErrNotFound) {
+			t.Errorf("second Claim err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("ClaimUnknown", func(t *testing.T) {
+		store := newStore(t, 0)
+		for _, id := range []string{
+			strings.Repeat("a", 32),
+			"",
+			"../../etc/passwd",
+			strings.Repeat("A", 32),
+		} {
+			if _, err := store.Claim(id); !errors.Is(err, upload.ErrNotFound) {
+				t.Errorf("Claim(%q) err = %v, want ErrNotFound", id, err)
+			}
+		}
+	})
+
+	t.Run("ClaimTo", func(t *testing.T) {
+		store := newStore(t, 0)
+		data := []byte("streamed contents")
+		id := save(t, store, "s.txt", "text/plain", data)
+
+		var buf bytes.Buffer
+		file, err := upload.ClaimTo(store, id, &buf)
+		if err != nil {
+			t.Fatalf("ClaimTo: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("contents = %q, want %q", buf.Bytes(), data)
+		}
+		if file.Filename != "s.txt" || file.Size != int64(len(data)) {
+			t.Errorf("file = %+v, want metadata of the saved file", file)
+		}
+		if file.Reader != nil {
+			t.Error("ClaimTo should not return an open reader")
+		}
+
+		if _, err := store.Claim(id); !errors.Is(err, upload.ErrNotFound) {
+			t.Errorf("Claim after ClaimTo err = %v, want ErrNotFound", err)
+		}
+		if _, err := upload.ClaimTo(store, id, io.Discard); !errors.Is(err, upload.ErrNotFound) {
+			t.Errorf("second ClaimTo err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("TooLargeDeclared", func(t *testing.T) {
+		store := newStore(t, 8)
+		_, err := store.Save("big.txt", "text/plain", 9, bytes.NewReader([]byte("123456789")))
+		if !errors.Is(err, upload.ErrTooLarge) {
+			t.Errorf("err = %v, want ErrTooLarge", err)
+		}
+	})
+
+	t.Run("TooLargeStreamed", func(t *testing.T) {
+		store := newStore(t, 8)
+		// The declared size understates the body.
+		_, err := store.Save("big.txt", "text/plain", 4, bytes.NewReader([]byte("123456789")))
+		if !errors.Is(err, upload.ErrTooLarge) {
+			t.Errorf("err = %v, want ErrTooLarge", err)
+		}
+		if err := store.Cleanup(0); err != nil {
+			t.Errorf("Cleanup: %v", err)
+		}
+	})
+
+	t.Run("AtSizeLimit", func(t *testing.T) {
+		store := newStore(t, 8)
+		id := save(t, store, "ok.txt", "text/plain", []byte("12345678"))
+		file, err := store.Claim(id)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		defer file.Close()
+		if got := read(t, file); string(got) != "12345678" {
+			t.Errorf("contents = %q", got)
+		}
+	})
+
+	t.Run("CleanupExpired", func(t *testing.T) {
+		store := newStore(t, 0)
+		old := save(t, store, "old.txt", "text/plain", []byte("old"))
+		time.Sleep(300 * time.Millisecond)
+		fresh := save(t, store, "fresh.txt", "text/plain", []byte("fresh"))
+
+		if err := store.Cleanup(150 * time.Millisecond); err != nil {
+			t.Fatalf("Cleanup: %v", err)
+		}
+
+		if _, err := store.Claim(old); !errors.Is(err, upload.ErrNotFound) {
+			t.Errorf("Claim(expired) err = %v, want ErrNotFound", err)
+		}
+		file, err := store.Claim(fresh)
+		if err != nil {
+			t.Fatalf("Claim(fresh): %v", err)
+		}
+		defer file.Close()
+		if got := read(t, file); string(got) != "fresh" {
+			t.Errorf("contents = %q, want %q", got, "fresh")
+		}
+	})
+
+	t.Run("CleanupEmpty", func(t *testing.T) {
+		store := newStore(t, 0)
+		if err := store.Cleanup(time.Hour); err != nil {
+			t.Errorf("Cleanup: %v", err)
+		}
+	})
+
+	t.Run("DistinctIDs", func(t *testing.T) {
+		store := newStore(t, 0)
+		a := save(t, store, "a.txt", "text/plain", []byte("a"))
+		b := save(t, store, "b.txt", "text/plain", []byte("b"))
+		if a == b {
+			t.Fatalf("Save returned the same ID twice: %q", a)
+		}
+
+		file, err := store.Claim(b)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		defer file.Close()
+		if got := read(t, file); string(got) != "b" {
+			t.Errorf("contents = %q, want %q", got, "b")
+		}
+	})
+}
+
+func save(t *testing.T, store upload.Store, filename, contentType string, data []byte) string {
+	t.Helper()
+	id, err := store.Save(filename, contentType, int64(len(data)), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Save(%q): %v", filename, err)
+	}
+	if id == "" {
+		t.Fatalf("Save(%q) returned an empty ID", filename)
+	}
+	return id
+}
+
+// read returns the contents of a claimed file.
+func read(t *testing.T, file *upload.File) []byte {
+	t.Helper()
+	if file.Reader == nil {
+		t.Fatal("claimed file has no Reader")
+	}
+	data, err := io.ReadAll(file.Reader)
+	if err != nil {
+		t.Fatalf("read claimed file: %v", err)
+	}
+	return data
+}
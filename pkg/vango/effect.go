@@ -35,6 +35,9 @@ type Effect struct {
 	// disposed indicates the effect has been disposed.
 	disposed atomic.Bool
 
+	// runs counts executions, for the graph inspector.
+	runs atomic.Uint64
+
 	// ==========================================================================
 	// Phase 16: Effect-local call-site state for helpers like GoLatest
 	// ==========================================================================
@@ -82,6 +85,7 @@ func (e *Effect) run() {
 
 	// Clear pending flag
 	e.pending.Store(false)
+	e.runs.Add(1)
 
 	// Run cleanup from previous run
 	if e.cleanup != nil {
@@ -107,16 +111,17 @@ func (e *Effect) run() {
 	oldInBody := setInEffectBody(true)
 	oldAllowWrites := setEffectAllowWrites(e.allowWrites)
 
+	// Restore tracking context when the effect body is done, even if it
+	// panics, so a recovered panic does not leave this effect tracking.
+	defer func() {
+		setEffectAllowWrites(oldAllowWrites)
+		setInEffectBody(oldInBody)
+		setCurrentEffect(oldEffect)
+		setCurrentListener(oldListener)
+	}()
+
 	// Run the effect function
 	e.cleanup = e.fn()
-
-	// Restore tracking context (effect body is done)
-	setEffectAllowWrites(oldAllowWrites)
-	setInEffectBody(oldInBody)
-	setCurrentEffect(oldEffect)
-
-	// Restore previous listener
-	setCurrentListener(oldListener)
 }
 
 // GetCallSiteData retrieves stored state for a specific call-site index.
@@ -0,0 +1,110 @@
+package vango
+
+import "github.com/vango-go/vango/pkg/vdom"
+
+// =============================================================================
+// Error Boundaries
+// =============================================================================
+
+// ErrorBoundary catches panics raised while rendering children (nodes or
+// components, as accepted by element functions such as Div), or while
+// running the effects of components inside them. The broken components are
+// disposed and fallback is rendered in their place with the recovered error
+// and a reset function; calling reset (typically from an event handler)
+// mounts children again from scratch.
+//
+// A nil fallback renders the router's error page, if one is configured.
+//
+//	return Div(
+//	    Header(),
+//	    vango.ErrorBoundary(func(err error, reset func()) *vango.VNode {
+//	        return Div(
+//	            P(Text("Something went wrong.")),
+//	            Button(OnClick(reset), Text("Try again")),
+//	        )
+//	    }, Feed()),
+//	)
+//
+// The boundary renders a <div style="display:contents"> around its content so
+// that switching between children and fallback patches a stable element.
+// Panics in the fallback itself propagate to the next boundary up.
+func ErrorBoundary(fallback func(err error, reset func()) *vdom.VNode, children ...any) *vdom.VNode {
+	return &vdom.VNode{
+		Kind: vdom.KindComponent,
+		Comp: &ErrorBoundaryComponent{fallback: fallback, children: children},
+	}
+}
+
+// ErrorBoundaryComponent is the component created by ErrorBoundary.
+// The server runtime and the SSR renderer recognize it and call Render or
+// RenderFallback depending on whether its subtree has panicked.
+type ErrorBoundaryComponent struct {
+	fallback func(err error, reset func()) *vdom.VNode
+	children []any
+}
+
+// Render implements vdom.Component and renders the children.
+func (b *ErrorBoundaryComponent) Render() *vdom.VNode {
+	return boundaryWrapper(b.children)
+}
+
+// RenderFallback renders the fallback for err. If the boundary has no
+// fallback, errorPage renders it instead; errorPage may be nil.
+func (b *ErrorBoundaryComponent) RenderFallback(err error, reset func(), errorPage func(error) *vdom.VNode) *vdom.VNode {
+	var node *vdom.VNode
+	switch {
+	case b.fallback != nil:
+		node = b.fallback(err, reset)
+	case errorPage != nil:
+		node = errorPage(err)
+	}
+	if node == nil {
+		return boundaryWrapper(nil)
+	}
+	return boundaryWrapper([]any{node})
+}
+
+func boundaryWrapper(children []any) *vdom.VNode {
+	args := append([]any{vdom.Data("error-boundary", "true"), vdom.StyleAttr("display:contents")}, children...)
+	return vdom.Div(args...)
+}
+
+// =============================================================================
+// Effect Panics
+// =============================================================================
+
+// SetPanicHandler installs fn to receive panics from effects run by
+// RunPendingEffects on this Owner or its descendants. fn is called with the
+// Owner of the effect that panicked, after the effect's tracking state has
+// been restored. Without a handler the panic propagates to the caller.
+//
+// The server uses it to route effect panics to the nearest ErrorBoundary.
+func (o *Owner) SetPanicHandler(fn func(owner *Owner, recovered any)) {
+	o.panicHandler.Store(&fn)
+}
+
+// findPanicHandler returns the closest panic handler at or above o.
+func (o *Owner) findPanicHandler() func(*Owner, any) {
+	for cur := o; cur != nil; cur = cur.parent {
+		if fn := cur.panicHandler.Load(); fn != nil {
+			return *fn
+		}
+	}
+	return nil
+}
+
+// runEffect runs a pending effect, passing a panic to the nearest panic
+// handler if there is one.
+func (o *Owner) runEffect(e *Effect) {
+	handler := o.findPanicHandler()
+	if handler == nil {
+		e.run()
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			handler(o, r)
+		}
+	}()
+	e.run()
+}
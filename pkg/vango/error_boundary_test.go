@@ -0,0 +1,68 @@
+package vango
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vango-go/vango/pkg/vdom"
+)
+
+func TestSetPanicHandlerReceivesEffectPanics(t *testing.T) {
+	root := NewOwner(nil)
+	defer root.Dispose()
+	child := NewOwner(root)
+
+	var gotOwner *Owner
+	var gotPanic any
+	root.SetPanicHandler(func(owner *Owner, recovered any) {
+		gotOwner, gotPanic = owner, recovered
+	})
+
+	trigger := NewSignal(0)
+	ranAfter := false
+	WithOwner(child, func() {
+		CreateEffect(func() Cleanup {
+			if trigger.Get() == 1 {
+				panic("effect broke")
+			}
+			return nil
+		})
+		CreateEffect(func() Cleanup {
+			trigger.Get()
+			ranAfter = true
+			return nil
+		})
+	})
+	child.RunPendingEffects(nil)
+
+	ranAfter = false
+	trigger.Set(1)
+	child.RunPendingEffects(nil)
+
+	if gotOwner != child || gotPanic != "effect broke" {
+		t.Errorf("handler got (%v, %v), want the child owner and the panic", gotOwner, gotPanic)
+	}
+	if !ranAfter {
+		t.Error("effects after the panicking one did not run")
+	}
+	if getCurrentListener() != nil || getCurrentOwner() != nil {
+		t.Error("tracking context leaked from the panicking effect")
+	}
+}
+
+func TestErrorBoundaryRenderFallback(t *testing.T) {
+	boom := errors.New("boom")
+	node := ErrorBoundary(nil, vdom.Text("child"))
+	b := node.Comp.(*ErrorBoundaryComponent)
+
+	if got := b.Render(); len(got.Children) != 1 || got.Children[0].Text != "child" {
+		t.Errorf("Render() = %+v, want the wrapped children", got)
+	}
+	if got := b.RenderFallback(boom, func() {}, nil); len(got.Children) != 0 {
+		t.Errorf("RenderFallback without fallback or error page = %+v, want an empty wrapper", got)
+	}
+	got := b.RenderFallback(boom, func() {}, func(err error) *vdom.VNode { return vdom.Text(err.Error()) })
+	if len(got.Children) != 1 || got.Children[0].Text != "boom" {
+		t.Errorf("RenderFallback() = %+v, want the error page", got)
+	}
+}
@@ -3,6 +3,7 @@ package vango
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // =============================================================================
@@ -122,6 +123,12 @@ type DragEvent struct {
 	AltKey   bool
 	MetaKey  bool
 
+	// Formats available in the data transfer (e.g., "text/plain", "Files")
+	Types []string
+
+	// Files being dragged; only populated on drop
+	Files []FileInfo
+
 	// Internal data transfer storage
 	dataTransfer map[string]string
 }
@@ -155,6 +162,82 @@ func (d *DragEvent) HasData(format string) bool {
 // Per spec section 3.9.3, lines 1197-1199.
 type DropEvent = DragEvent
 
+// FileInfo describes a file carried by a clipboard or drag event.
+// File contents are not sent with events; use el.OnUpload to receive them.
+type FileInfo struct {
+	// File name without path
+	Name string
+
+	// MIME type (e.g., "image/png")
+	Type string
+
+	// Size in bytes
+	Size int64
+}
+
+// PointerEvent represents a pointer event from a mouse, pen or touch contact.
+type PointerEvent struct {
+	MouseEvent
+
+	// Unique identifier of the pointer
+	PointerID int
+
+	// Device type: "mouse", "pen" or "touch"
+	PointerType string
+
+	// Normalized pressure from 0 to 1
+	Pressure float64
+
+	// Contact geometry in pixels
+	Width  float64
+	Height float64
+
+	// Pen tilt in degrees from -90 to 90
+	TiltX int
+	TiltY int
+
+	// True for the primary pointer of its type
+	IsPrimary bool
+}
+
+// MediaEvent represents an event from an audio or video element.
+type MediaEvent struct {
+	// Playback position in seconds
+	CurrentTime float64
+
+	// Media length in seconds (0 if unknown, +Inf for live streams)
+	Duration float64
+
+	// Playback state
+	Paused bool
+	Ended  bool
+
+	// Volume from 0 to 1
+	Volume float64
+	Muted  bool
+
+	// Playback speed (1 is normal)
+	PlaybackRate float64
+}
+
+// ClipboardEvent represents a copy, cut or paste event.
+type ClipboardEvent struct {
+	// Plain text on the clipboard; only populated on paste
+	Text string
+
+	// Formats available on the clipboard
+	Types []string
+
+	// Pasted files
+	Files []FileInfo
+}
+
+// ToggleEvent represents a toggle event from a details or popover element.
+type ToggleEvent struct {
+	// True if the element is now open
+	Open bool
+}
+
 // Touch represents a single touch point.
 // Per spec section 3.9.3, lines 1219-1225.
 type Touch struct {
@@ -231,6 +314,9 @@ type ResizeEvent struct {
 
 // FormData represents submitted form data.
 // Per spec section 3.9.3, lines 1169-1179.
+//
+// A file input submits only the name of each chosen file. File contents
+// never travel with the submit event; use an OnUpload input to receive them.
 type FormData struct {
 	values map[string][]string
 }
@@ -299,6 +385,40 @@ func (f FormData) All() map[string]string {
 	return result
 }
 
+// Values returns a copy of all form fields with every submitted value.
+func (f FormData) Values() map[string][]string {
+	result := make(map[string][]string, len(f.values))
+	for k, vals := range f.values {
+		result[k] = append([]string(nil), vals...)
+	}
+	return result
+}
+
+// GetInt returns the first value for a form field parsed as an int.
+// Returns 0 if the field is missing or not a valid integer.
+func (f FormData) GetInt(key string) int {
+	i, _ := strconv.Atoi(strings.TrimSpace(f.Get(key)))
+	return i
+}
+
+// GetFloat returns the first value for a form field parsed as a float64.
+// Returns 0 if the field is missing or not a valid number.
+func (f FormData) GetFloat(key string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(f.Get(key)), 64)
+	return v
+}
+
+// GetBool returns the first value for a form field parsed as a bool.
+// A checked checkbox without an explicit value submits "on", which is true.
+func (f FormData) GetBool(key string) bool {
+	v := strings.TrimSpace(f.Get(key))
+	if v == "on" {
+		return true
+	}
+	b, _ := strconv.ParseBool(v)
+	return b
+}
+
 // HookEvent represents a client hook event.
 // Per spec section 3.9.3, line 1018.
 type HookEvent struct {
@@ -436,6 +556,12 @@ func (h HookEvent) Revert() {
 	}
 }
 
+// HID returns the hydration ID of the hook's element. Pass it to
+// Ctx.CallHook to call back into the hook.
+func (h HookEvent) HID() string {
+	return h.hid
+}
+
 // SetContext sets the internal context for the hook event.
 // This is called by the runtime when dispatching hook events.
 func (h *HookEvent) SetContext(hid string, dispatch func(name string, payload any)) {
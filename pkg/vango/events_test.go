@@ -77,6 +77,34 @@ func TestFormData_BehaviorAndCopies(t *testing.T) {
 	}
 }
 
+func TestFormData_TypedGettersAndValues(t *testing.T) {
+	f := NewFormData(map[string][]string{
+		"n":     {" 7 "},
+		"f":     {"1.25"},
+		"agree": {"on"},
+		"off":   {"false"},
+		"tags":  {"a", "b"},
+	})
+	if f.GetInt("n") != 7 || f.GetInt("tags") != 0 {
+		t.Fatalf("GetInt = %d/%d, want 7/0", f.GetInt("n"), f.GetInt("tags"))
+	}
+	if f.GetFloat("f") != 1.25 {
+		t.Fatalf("GetFloat(f) = %v, want 1.25", f.GetFloat("f"))
+	}
+	if !f.GetBool("agree") || f.GetBool("off") || f.GetBool("missing") {
+		t.Fatalf("GetBool results unexpected")
+	}
+
+	values := f.Values()
+	if !reflect.DeepEqual(values["tags"], []string{"a", "b"}) {
+		t.Fatalf("Values()[tags] = %v, want [a b]", values["tags"])
+	}
+	values["tags"][0] = "mutated"
+	if f.Get("tags") != "a" {
+		t.Fatalf("Values() should return copies")
+	}
+}
+
 func TestHookEvent_TypedGetters_Revert_SetContext(t *testing.T) {
 	var called struct {
 		name string
@@ -59,4 +59,5 @@ const (
 	KindFragment  = vdom.KindFragment
 	KindComponent = vdom.KindComponent
 	KindRaw       = vdom.KindRaw
+	KindPortal    = vdom.KindPortal
 )
@@ -0,0 +1,139 @@
+package vango
+
+// GlobalTarget identifies the object a global event listener is attached to.
+type GlobalTarget uint8
+
+const (
+	// TargetWindow listens on window (resize, scroll, online, offline,
+	// beforeunload, focus, blur, keydown, ...).
+	TargetWindow GlobalTarget = iota + 1
+
+	// TargetDocument listens on document (visibilitychange, keydown, keyup,
+	// click, ...).
+	TargetDocument
+)
+
+// String returns "window" or "document".
+func (t GlobalTarget) String() string {
+	switch t {
+	case TargetWindow:
+		return "window"
+	case TargetDocument:
+		return "document"
+	default:
+		return "unknown"
+	}
+}
+
+// GlobalListener is a window or document event subscription made with
+// OnWindow or OnDocument. The runtime reads it to subscribe the client and
+// to deliver events.
+type GlobalListener struct {
+	Target GlobalTarget
+	Event  string
+
+	handler ModifiedHandler
+	owner   *Owner
+}
+
+// Handler returns the latest handler passed to OnWindow or OnDocument,
+// with its modifiers.
+func (l *GlobalListener) Handler() ModifiedHandler {
+	return l.handler
+}
+
+// Owner returns the owner of the component that subscribed. Handlers run
+// with it as the current owner.
+func (l *GlobalListener) Owner() *Owner {
+	return l.owner
+}
+
+// GlobalEvents subscribes listeners to window and document events. The
+// server runtime provides one per session under GlobalEventsKey.
+type GlobalEvents interface {
+	// Listen subscribes l and returns a function that ends the subscription.
+	Listen(l *GlobalListener) (unsubscribe func())
+}
+
+// GlobalEventsKey is the owner value key of the session's GlobalEvents.
+var GlobalEventsKey = &struct{ name string }{"GlobalEvents"}
+
+// OnWindow subscribes to a window event for the lifetime of the component.
+//
+// The handler accepts the same signatures as element handlers: func(),
+// func(ResizeEvent) for "resize", func(ScrollEvent) for "scroll",
+// func(KeyboardEvent) for keyboard events, and so on. It may be wrapped in
+// Debounce, Throttle, Hotkey, Keys or KeyWithModifiers, and modifiers such
+// as PreventDefault or Once may be passed after it. Key filters, debounce
+// and throttle are applied on the client, so filtered events never reach
+// the server.
+//
+// The client keeps one DOM listener per event however many components
+// subscribe. Modifiers are read when the component mounts; the handler is
+// always the one from the latest render.
+//
+// This is a hook-like API and MUST be called unconditionally during render.
+//
+// Example:
+//
+//	vango.OnWindow("resize", vango.Debounce(200*time.Millisecond, func(e vango.ResizeEvent) {
+//	    width.Set(e.Width)
+//	}))
+//
+//	vango.OnWindow("online", func() { online.Set(true) })
+//	vango.OnWindow("offline", func() { online.Set(false) })
+func OnWindow(event string, handler any, modifiers ...func(any) ModifiedHandler) {
+	listenGlobal(TargetWindow, event, handler, modifiers)
+}
+
+// OnDocument subscribes to a document event for the lifetime of the
+// component. It works like OnWindow.
+//
+// Example:
+//
+//	// Cmd+K anywhere on the page
+//	vango.OnDocument("keydown", vango.KeyWithModifiers("k", vango.Meta, func() {
+//	    palette.Set(true)
+//	}), vango.PreventDefault)
+//
+//	vango.OnDocument("visibilitychange", func(state string) {
+//	    visible.Set(state == "visible")
+//	})
+func OnDocument(event string, handler any, modifiers ...func(any) ModifiedHandler) {
+	listenGlobal(TargetDocument, event, handler, modifiers)
+}
+
+// listenGlobal implements OnWindow and OnDocument.
+func listenGlobal(target GlobalTarget, event string, handler any, modifiers []func(any) ModifiedHandler) {
+	// Keep a stable listener across renders so the latest handler closure
+	// is always used without re-subscribing.
+	slot := UseHookSlot()
+	var l *GlobalListener
+	if slot != nil {
+		existing, ok := slot.(*GlobalListener)
+		if !ok {
+			panic("vango: hook slot type mismatch for OnWindow/OnDocument")
+		}
+		l = existing
+	} else {
+		l = &GlobalListener{Target: target, Event: event, owner: getCurrentOwner()}
+		SetHookSlot(l)
+	}
+
+	mh, ok := handler.(ModifiedHandler)
+	if !ok {
+		mh = ModifiedHandler{Handler: handler}
+	}
+	for _, modify := range modifiers {
+		mh = modify(mh)
+	}
+	l.handler = mh
+
+	events, _ := GetContext(GlobalEventsKey).(GlobalEvents)
+	CreateEffect(func() Cleanup {
+		if events == nil {
+			return nil
+		}
+		return events.Listen(l)
+	})
+}
@@ -0,0 +1,311 @@
+package vango
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// =============================================================================
+// Reactive Graph Inspection
+// =============================================================================
+
+// NodeKind identifies the kind of a node in an inspected reactive graph.
+type NodeKind string
+
+const (
+	NodeOwner     NodeKind = "owner"
+	NodeComponent NodeKind = "component"
+	NodeSignal    NodeKind = "signal"
+	NodeMemo      NodeKind = "memo"
+	NodeEffect    NodeKind = "effect"
+)
+
+// Signal scopes reported by the inspector.
+const (
+	ScopeShared = "shared"
+	ScopeGlobal = "global"
+)
+
+// maxInspectValue is the length at which inspected values are truncated.
+const maxInspectValue = 120
+
+// Graph is a point-in-time snapshot of the reactive graph below an Owner.
+// It is meant for debugging tools and is safe to encode as JSON.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode describes one owner, signal, memo or effect.
+type GraphNode struct {
+	ID   uint64   `json:"id"`
+	Kind NodeKind `json:"kind"`
+
+	// Owner is the ID of the owner the node belongs to: the parent for
+	// owners, the owning scope for everything else. Zero for roots,
+	// shared and global signals.
+	Owner uint64 `json:"owner,omitempty"`
+
+	// Label is a human-readable name: the value type for signals and memos,
+	// the transaction name for effects. The server sets it to the component
+	// type for component owners.
+	Label string `json:"label,omitempty"`
+
+	// HID is the hydration ID of a component's root element, set by the server.
+	HID string `json:"hid,omitempty"`
+
+	// Scope is ScopeShared or ScopeGlobal for signals not owned by a component.
+	Scope string `json:"scope,omitempty"`
+
+	PersistKey string `json:"persistKey,omitempty"`
+
+	// Value is the current value formatted with %v and truncated.
+	// Memos report their cached value without recomputing.
+	Value string `json:"value,omitempty"`
+
+	// Runs counts renders for owners, writes for signals, recomputations
+	// for memos and executions for effects.
+	Runs uint64 `json:"runs"`
+
+	// Subscribers is the number of listeners of a signal or memo, including
+	// listeners outside the graph (other sessions, for global signals).
+	Subscribers int `json:"subscribers,omitempty"`
+
+	Disposed bool `json:"disposed,omitempty"`
+}
+
+// GraphEdge is a subscription: To re-runs when From changes.
+type GraphEdge struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// Node returns the node with the given ID, or nil.
+func (g *Graph) Node(id uint64) *GraphNode {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// inspectable is implemented by signals and memos so the inspector can read
+// them without knowing their value type.
+type inspectable interface {
+	inspectBase() *signalBase
+	inspectNode() GraphNode
+}
+
+func (s *Signal[T]) inspectBase() *signalBase { return &s.base }
+
+func (s *Signal[T]) inspectNode() GraphNode {
+	return GraphNode{
+		ID:         s.base.id,
+		Kind:       NodeSignal,
+		Label:      reflect.TypeFor[T]().String(),
+		PersistKey: s.persistKey,
+		Value:      formatInspectValue(s.Peek()),
+		Runs:       s.base.writes.Load(),
+	}
+}
+
+func (m *Memo[T]) inspectBase() *signalBase { return &m.base }
+
+func (m *Memo[T]) inspectNode() GraphNode {
+	m.valueMu.RLock()
+	value := m.value
+	m.valueMu.RUnlock()
+	return GraphNode{
+		ID:    m.base.id,
+		Kind:  NodeMemo,
+		Label: reflect.TypeFor[T]().String(),
+		Value: formatInspectValue(value),
+		Runs:  m.runs.Load(),
+	}
+}
+
+func formatInspectValue(v any) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) > maxInspectValue {
+		s = s[:maxInspectValue] + "…"
+	}
+	return s
+}
+
+// signalRanger is implemented by session signal stores that can list their
+// signals.
+type signalRanger interface {
+	Range(fn func(id uint64, signal any) bool)
+}
+
+// InspectOwner returns a snapshot of the reactive graph rooted at root:
+// its owner tree, the signals, memos and effects they hold, the session's
+// shared signals, global signals subscribed to from inside the graph, and
+// the subscription edges between them.
+//
+// Hook slots are read without locking, so InspectOwner must run on the
+// goroutine that renders the tree (the session loop on the server).
+func InspectOwner(root *Owner) *Graph {
+	in := &inspector{
+		graph: &Graph{},
+		seen:  make(map[uint64]bool),
+	}
+	if root == nil {
+		return in.graph
+	}
+	in.walk(root)
+
+	if store, ok := root.GetValue(SessionSignalStoreKey).(signalRanger); ok {
+		store.Range(func(_ uint64, sig any) bool {
+			if insp, ok := sig.(inspectable); ok {
+				in.addSignal(insp, 0, ScopeShared)
+			}
+			return true
+		})
+	}
+
+	// Global signals are only included when something in this graph
+	// subscribes to them; otherwise every session would list every global.
+	for _, insp := range registeredGlobalSignals() {
+		for _, id := range insp.inspectBase().subscriberIDs() {
+			if in.seen[id] {
+				in.addSignal(insp, 0, ScopeGlobal)
+				break
+			}
+		}
+	}
+
+	in.link()
+	return in.graph
+}
+
+type inspector struct {
+	graph   *Graph
+	seen    map[uint64]bool
+	sources []*signalBase
+}
+
+func (in *inspector) walk(o *Owner) {
+	if in.seen[o.id] {
+		return
+	}
+	in.seen[o.id] = true
+
+	node := GraphNode{
+		ID:       o.id,
+		Kind:     NodeOwner,
+		Runs:     o.renders.Load(),
+		Disposed: o.disposed.Load(),
+	}
+	if o.parent != nil {
+		node.Owner = o.parent.id
+	}
+	in.graph.Nodes = append(in.graph.Nodes, node)
+
+	for _, slot := range o.hookSlots {
+		switch v := slot.(type) {
+		case inspectable:
+			in.addSignal(v, o.id, "")
+		case *Effect:
+			in.addEffect(v, o.id)
+		}
+	}
+
+	o.effectsMu.Lock()
+	effects := append([]*Effect(nil), o.effects...)
+	o.effectsMu.Unlock()
+	for _, e := range effects {
+		in.addEffect(e, o.id)
+	}
+
+	o.childrenMu.Lock()
+	children := append([]*Owner(nil), o.children...)
+	o.childrenMu.Unlock()
+	for _, child := range children {
+		in.walk(child)
+	}
+}
+
+func (in *inspector) addSignal(insp inspectable, owner uint64, scope string) {
+	base := insp.inspectBase()
+	if in.seen[base.id] {
+		return
+	}
+	in.seen[base.id] = true
+
+	node := insp.inspectNode()
+	node.Owner = owner
+	node.Scope = scope
+	base.subMu.RLock()
+	node.Subscribers = len(base.subs)
+	base.subMu.RUnlock()
+	in.graph.Nodes = append(in.graph.Nodes, node)
+	in.sources = append(in.sources, base)
+}
+
+func (in *inspector) addEffect(e *Effect, owner uint64) {
+	if in.seen[e.id] {
+		return
+	}
+	in.seen[e.id] = true
+
+	in.graph.Nodes = append(in.graph.Nodes, GraphNode{
+		ID:       e.id,
+		Kind:     NodeEffect,
+		Owner:    owner,
+		Label:    e.txName,
+		Runs:     e.runs.Load(),
+		Disposed: e.disposed.Load(),
+	})
+}
+
+// link adds an edge for every subscription between two nodes in the graph.
+func (in *inspector) link() {
+	for _, base := range in.sources {
+		for _, id := range base.subscriberIDs() {
+			if in.seen[id] {
+				in.graph.Edges = append(in.graph.Edges, GraphEdge{From: base.id, To: id})
+			}
+		}
+	}
+	sort.Slice(in.graph.Edges, func(i, j int) bool {
+		a, b := in.graph.Edges[i], in.graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+}
+
+// subscriberIDs returns the IDs of the signal's current listeners.
+func (s *signalBase) subscriberIDs() []uint64 {
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+	ids := make([]uint64, len(s.subs))
+	for i, l := range s.subs {
+		ids[i] = l.ID()
+	}
+	return ids
+}
+
+// globalSignals lists every GlobalSignal so the inspector can find them.
+// Globals are package-level values, so the list stays small.
+var (
+	globalSignals   []inspectable
+	globalSignalsMu sync.Mutex
+)
+
+func registerGlobalSignal(insp inspectable) {
+	globalSignalsMu.Lock()
+	globalSignals = append(globalSignals, insp)
+	globalSignalsMu.Unlock()
+}
+
+func registeredGlobalSignals() []inspectable {
+	globalSignalsMu.Lock()
+	defer globalSignalsMu.Unlock()
+	return append([]inspectable(nil), globalSignals...)
+}
@@ -0,0 +1,87 @@
+package vango
+
+import "testing"
+
+func TestInspectOwner(t *testing.T) {
+	root := NewOwner(nil)
+	defer root.Dispose()
+	child := NewOwner(root)
+
+	global := NewGlobalSignal(7)
+	unused := NewGlobalSignal(0)
+
+	var count *Signal[int]
+	var doubled *Memo[int]
+	var eff *Effect
+	WithOwner(child, func() {
+		child.StartRender()
+		defer child.EndRender()
+		count = NewSignal(1, PersistKey("count"))
+		doubled = NewMemo(func() int { return count.Get() * 2 })
+		eff = CreateEffect(func() Cleanup {
+			_ = doubled.Get()
+			_ = global.Get()
+			return nil
+		}, EffectTxName("log"))
+	})
+	child.RunPendingEffects(nil)
+	count.Set(5)
+	child.RunPendingEffects(nil)
+
+	g := InspectOwner(root)
+
+	if n := g.Node(child.ID()); n == nil || n.Kind != NodeOwner || n.Owner != root.ID() || n.Runs != 1 {
+		t.Errorf("child owner node = %+v", n)
+	}
+	n := g.Node(count.ID())
+	if n == nil || n.Kind != NodeSignal || n.Owner != child.ID() {
+		t.Fatalf("signal node = %+v", n)
+	}
+	if n.PersistKey != "count" || n.Value != "5" || n.Label != "int" || n.Runs != 1 || n.Subscribers != 1 {
+		t.Errorf("signal node = %+v", n)
+	}
+	if n := g.Node(doubled.ID()); n == nil || n.Kind != NodeMemo || n.Value != "10" || n.Runs != 2 {
+		t.Errorf("memo node = %+v", n)
+	}
+	if n := g.Node(eff.ID()); n == nil || n.Kind != NodeEffect || n.Label != "log" || n.Runs != 2 {
+		t.Errorf("effect node = %+v", n)
+	}
+	if n := g.Node(global.ID()); n == nil || n.Scope != ScopeGlobal || n.Value != "7" {
+		t.Errorf("global node = %+v", n)
+	}
+	if n := g.Node(unused.ID()); n != nil {
+		t.Errorf("unsubscribed global listed: %+v", n)
+	}
+
+	want := map[GraphEdge]bool{
+		{From: count.ID(), To: doubled.ID()}: true,
+		{From: doubled.ID(), To: eff.ID()}:   true,
+		{From: global.ID(), To: eff.ID()}:    true,
+	}
+	for _, e := range g.Edges {
+		if !want[e] {
+			t.Errorf("unexpected edge %+v", e)
+		}
+		delete(want, e)
+	}
+	for e := range want {
+		t.Errorf("missing edge %+v", e)
+	}
+}
+
+func TestInspectOwner_SharedSignals(t *testing.T) {
+	root := NewOwner(nil)
+	defer root.Dispose()
+	root.SetValue(SessionSignalStoreKey, NewSimpleSessionSignalStore())
+
+	theme := NewSharedSignal("dark")
+	var sig *Signal[string]
+	WithOwner(root, func() {
+		sig = theme.Signal()
+	})
+
+	n := InspectOwner(root).Node(sig.ID())
+	if n == nil || n.Scope != ScopeShared || n.Value != "dark" || n.Owner != 0 {
+		t.Errorf("shared node = %+v", n)
+	}
+}
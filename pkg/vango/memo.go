@@ -40,6 +40,9 @@ type Memo[T any] struct {
 
 	// computing prevents infinite recursion in circular dependencies.
 	computing atomic.Bool
+
+	// runs counts recomputations, for the graph inspector.
+	runs atomic.Uint64
 }
 
 // NewMemo creates a new memo with the given computation function.
@@ -163,6 +166,7 @@ func (m *Memo[T]) recompute() {
 		return
 	}
 	defer m.computing.Store(false)
+	m.runs.Add(1)
 
 	// Unsubscribe from old sources
 	m.sourcesMu.Lock()
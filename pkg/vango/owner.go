@@ -103,6 +103,12 @@ type Owner struct {
 	// URLParam and Resource need stable identity for correctness.
 	hookSlots   []any // Stored hook state values (one per hook)
 	hookSlotIdx int   // Current slot index during render
+
+	// renders counts StartRender calls, for the graph inspector.
+	renders atomic.Uint64
+
+	// panicHandler receives effect panics from this subtree (see SetPanicHandler).
+	panicHandler atomic.Pointer[func(*Owner, any)]
 }
 
 // NewOwner creates a new Owner with the given parent.
@@ -349,7 +355,12 @@ func (o *Owner) RunPendingEffects(budget StormBudgetChecker) {
 					continue
 				}
 			}
-			e.run()
+			o.runEffect(e)
+			if o.disposed.Load() {
+				// A panic handler disposed this scope; its remaining
+				// effects and children are gone.
+				return
+			}
 		}
 	}
 
@@ -456,6 +467,7 @@ func (o *Owner) StartRender() {
 
 	// Always reset slot index for stable hook identity
 	o.hookSlotIdx = 0
+	o.renders.Add(1)
 
 	// Debug mode: also reset order validation index
 	if DebugMode {
@@ -1,8 +1,10 @@
 package vango
 
 import (
+	"encoding/json"
 	"reflect"
 	"sync"
+	"sync/atomic"
 )
 
 // signalBase provides type-erased subscriber management.
@@ -15,6 +17,14 @@ type signalBase struct {
 
 	// subMu protects the subs slice.
 	subMu sync.RWMutex
+
+	// onWrite, if set, is called after subscribers are notified of a local
+	// write. GlobalSignal uses it to publish values to other instances.
+	// It must be set before the signal is shared and must not block.
+	onWrite func()
+
+	// writes counts change notifications, for the graph inspector.
+	writes atomic.Uint64
 }
 
 // subscribe adds a listener to this signal's subscribers.
@@ -58,9 +68,19 @@ func (s *signalBase) unsubscribe(l Listener) {
 	}
 }
 
-// notifySubscribers notifies all subscribers that this signal changed.
-// Uses copy-before-notify pattern to avoid holding locks during notification.
+// notifySubscribers notifies all subscribers that this signal changed
+// and then runs the write hook, if any.
 func (s *signalBase) notifySubscribers() {
+	s.writes.Add(1)
+	s.notifyListeners()
+	if s.onWrite != nil {
+		s.onWrite()
+	}
+}
+
+// notifyListeners notifies all subscribers that this signal changed.
+// Uses copy-before-notify pattern to avoid holding locks during notification.
+func (s *signalBase) notifyListeners() {
 	// Copy subscribers while holding lock
 	s.subMu.RLock()
 	subs := make([]Listener, len(s.subs))
@@ -148,6 +168,10 @@ func NewSignal[T any](initial T, opts ...SignalOption) *Signal[T] {
 		owner.SetHookSlot(sig)
 	}
 
+	// Attach to the session's persist store so the value is serialized and
+	// any value restored from a previous session is applied.
+	registerPersisted(owner, inRender, sig)
+
 	return sig
 }
 
@@ -353,6 +377,34 @@ func (s *Signal[T]) SetAny(value any) error {
 	return nil
 }
 
+// valueJSON encodes the current value for persistence or synchronization.
+func (s *Signal[T]) valueJSON() ([]byte, error) {
+	return json.Marshal(s.GetAny())
+}
+
+// setJSON decodes a value produced by valueJSON and applies it.
+// Unlike Set, this bypasses prefetch and effect-time write checks and does
+// not invoke the write hook, because restored or remotely-synchronized state
+// is not a local user write.
+func (s *Signal[T]) setJSON(data []byte) error {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	changed := !s.equals(s.value, value)
+	if changed {
+		s.value = value
+	}
+	s.mu.Unlock()
+
+	if changed {
+		s.base.notifyListeners()
+	}
+	return nil
+}
+
 // TypeMismatchError is returned when SetAny receives a value of the wrong type.
 type TypeMismatchError struct {
 	Expected string
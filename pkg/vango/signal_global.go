@@ -20,6 +20,12 @@ package vango
 // WARNING: Global signals are shared across all users. Be careful with
 // sensitive data and ensure thread-safe access patterns.
 //
+// By default a global signal is local to the process. When the app runs on
+// several replicas, give the signal a SyncKey and configure a
+// GlobalSignalBackend on the server: local writes are then published to other
+// instances, and writes from other instances re-render every session reading
+// the signal.
+//
 // Example:
 //
 //	// Package-level definition
@@ -50,9 +56,17 @@ type GlobalSignal[T any] struct {
 //	var ServerStatus = vango.NewGlobalSignal("online")
 //	var GlobalCounter = vango.NewGlobalSignal(0)
 //	var AppConfig = vango.NewGlobalSignal(Config{Debug: false})
+//
+//	// Synchronized across replicas via the GlobalSignalBackend
+//	var OnlineUsers = vango.NewGlobalSignal(0, vango.SyncKey("online_users"))
 func NewGlobalSignal[T any](initial T, opts ...SignalOption) *GlobalSignal[T] {
+	sig := NewSignal(initial, opts...)
+	if key := applyOptions(opts).syncKey; key != "" {
+		registerGlobalSync(key, &sig.base, sig)
+	}
+	registerGlobalSignal(sig)
 	return &GlobalSignal[T]{
-		Signal: NewSignal(initial, opts...),
+		Signal: sig,
 	}
 }
 
@@ -0,0 +1,334 @@
+package vango
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Global Signal Synchronization
+// =============================================================================
+
+// GlobalSignalBackend propagates GlobalSignal writes between server instances.
+//
+// Values are opaque, already-serialized payloads. A backend only needs to
+// deliver every payload published on a key to every subscriber of that key,
+// on any instance (including the publishing one; echoes are filtered by the
+// caller). Implementations may additionally deliver the most recently
+// published payload to new subscribers so that freshly started instances
+// converge without waiting for the next write.
+//
+// Use NewMemoryGlobalSignalBackend for tests and single-process setups, or
+// session.NewRedisSignalBackend for multi-replica deployments.
+type GlobalSignalBackend interface {
+	// Publish sends data to all subscribers of key.
+	Publish(ctx context.Context, key string, data []byte) error
+
+	// Subscribe registers fn to receive payloads published on key.
+	// fn may be called from any goroutine and must not block for long.
+	// The returned function removes the subscription.
+	Subscribe(key string, fn func(data []byte)) (unsubscribe func(), err error)
+
+	// Close releases backend resources. Subscriptions stop receiving payloads.
+	Close() error
+}
+
+// ErrGlobalSignalBackendClosed is returned by GlobalSignalBackend operations
+// after Close has been called.
+var ErrGlobalSignalBackendClosed = errors.New("vango: global signal backend closed")
+
+// globalPublishTimeout bounds a single backend Publish call.
+const globalPublishTimeout = 5 * time.Second
+
+// globalEnvelope is the wire format for synchronized values.
+// Origin identifies the publishing GlobalSignal so it can ignore its own echo.
+type globalEnvelope struct {
+	Origin string          `json:"o"`
+	Value  json.RawMessage `json:"v"`
+}
+
+// globalSyncSignal is the type-erased view of a Signal[T] used for sync.
+type globalSyncSignal interface {
+	valueJSON() ([]byte, error)
+	setJSON(data []byte) error
+}
+
+// globalSync connects one GlobalSignal to the attached backends.
+type globalSync struct {
+	key    string
+	origin string
+	sig    globalSyncSignal
+
+	// pending coalesces local writes: the publisher always sends the latest
+	// value, so bursts of writes produce at most one queued publish.
+	pending chan struct{}
+
+	mu sync.Mutex
+	// unsubscribe functions of the attached backends
+	backends map[GlobalSignalBackend]func()
+	// stop ends the publisher; nil while no backend is attached
+	stop chan struct{}
+}
+
+// globalSyncRegistry tracks all synchronized GlobalSignals and the attached
+// backends.
+var globalSyncRegistry = struct {
+	mu       sync.Mutex
+	backends []GlobalSignalBackend
+	syncs    []*globalSync
+}{}
+
+// AttachGlobalSignalBackend connects the global signals created with SyncKey
+// to backend: local writes are published to it, and payloads received from
+// it are applied. It returns a function that detaches the backend again;
+// the backend is not closed.
+//
+// Global signals are process-wide, so every server in the process shares
+// them. Each server attaches the backend from its own
+// ServerConfig.GlobalSignalBackend and detaches it on Shutdown; several
+// backends may be attached at once. Signals declared at package level are
+// connected when a backend is attached.
+func AttachGlobalSignalBackend(backend GlobalSignalBackend) (detach func()) {
+	globalSyncRegistry.mu.Lock()
+	defer globalSyncRegistry.mu.Unlock()
+
+	globalSyncRegistry.backends = append(globalSyncRegistry.backends, backend)
+	for _, gs := range globalSyncRegistry.syncs {
+		gs.attach(backend)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { detachGlobalSignalBackend(backend) })
+	}
+}
+
+// detachGlobalSignalBackend disconnects every synchronized signal from
+// backend.
+func detachGlobalSignalBackend(backend GlobalSignalBackend) {
+	globalSyncRegistry.mu.Lock()
+	defer globalSyncRegistry.mu.Unlock()
+
+	backends := globalSyncRegistry.backends
+	for i, b := range backends {
+		if b == backend {
+			globalSyncRegistry.backends = append(backends[:i:i], backends[i+1:]...)
+			break
+		}
+	}
+	for _, gs := range globalSyncRegistry.syncs {
+		gs.detach(backend)
+	}
+}
+
+// registerGlobalSync enables synchronization for sig under key.
+func registerGlobalSync(key string, base *signalBase, sig globalSyncSignal) *globalSync {
+	gs := &globalSync{
+		key:      key,
+		origin:   newSyncOrigin(),
+		sig:      sig,
+		pending:  make(chan struct{}, 1),
+		backends: make(map[GlobalSignalBackend]func()),
+	}
+	base.onWrite = gs.markDirty
+
+	globalSyncRegistry.mu.Lock()
+	globalSyncRegistry.syncs = append(globalSyncRegistry.syncs, gs)
+	for _, backend := range globalSyncRegistry.backends {
+		gs.attach(backend)
+	}
+	globalSyncRegistry.mu.Unlock()
+
+	return gs
+}
+
+// newSyncOrigin returns a random identifier for a synchronized signal.
+func newSyncOrigin() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic("vango: crypto/rand failed: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+// markDirty queues a publish of the current value. It never blocks.
+func (gs *globalSync) markDirty() {
+	select {
+	case gs.pending <- struct{}{}:
+	default:
+	}
+}
+
+// attach subscribes to backend and starts the publisher if it is the first
+// attached backend.
+func (gs *globalSync) attach(backend GlobalSignalBackend) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if _, ok := gs.backends[backend]; ok {
+		return
+	}
+	unsubscribe, err := backend.Subscribe(gs.key, gs.receive)
+	if err != nil {
+		return
+	}
+	gs.backends[backend] = unsubscribe
+	if gs.stop == nil {
+		gs.stop = make(chan struct{})
+		go gs.publishLoop(gs.stop)
+	}
+}
+
+// detach removes the subscription to backend and stops the publisher with
+// the last backend.
+func (gs *globalSync) detach(backend GlobalSignalBackend) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	unsubscribe, ok := gs.backends[backend]
+	if !ok {
+		return
+	}
+	unsubscribe()
+	delete(gs.backends, backend)
+	if len(gs.backends) == 0 && gs.stop != nil {
+		close(gs.stop)
+		gs.stop = nil
+	}
+}
+
+// publishLoop publishes the latest value to every attached backend each
+// time a local write is queued.
+func (gs *globalSync) publishLoop(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-gs.pending:
+		}
+
+		value, err := gs.sig.valueJSON()
+		if err != nil {
+			continue
+		}
+		data, err := json.Marshal(globalEnvelope{Origin: gs.origin, Value: value})
+		if err != nil {
+			continue
+		}
+
+		gs.mu.Lock()
+		backends := make([]GlobalSignalBackend, 0, len(gs.backends))
+		for b := range gs.backends {
+			backends = append(backends, b)
+		}
+		gs.mu.Unlock()
+
+		for _, backend := range backends {
+			ctx, cancel := context.WithTimeout(context.Background(), globalPublishTimeout)
+			_ = backend.Publish(ctx, gs.key, data)
+			cancel()
+		}
+	}
+}
+
+// receive applies a payload published by another instance.
+// The value is set without triggering a publish, and subscribers are
+// notified exactly as for a local write, so every session reading the
+// signal schedules a re-render.
+func (gs *globalSync) receive(data []byte) {
+	var env globalEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return
+	}
+	if env.Origin == gs.origin || len(env.Value) == 0 {
+		return
+	}
+	_ = gs.sig.setJSON(env.Value)
+}
+
+// =============================================================================
+// In-Memory Backend
+// =============================================================================
+
+// MemoryGlobalSignalBackend is an in-process GlobalSignalBackend.
+// It is useful for tests and for running several app instances in one process.
+// New subscribers receive the most recently published payload for their key.
+type MemoryGlobalSignalBackend struct {
+	mu     sync.RWMutex
+	subs   map[string]map[uint64]func([]byte)
+	last   map[string][]byte
+	nextID uint64
+	closed bool
+}
+
+// NewMemoryGlobalSignalBackend creates an empty in-memory backend.
+func NewMemoryGlobalSignalBackend() *MemoryGlobalSignalBackend {
+	return &MemoryGlobalSignalBackend{
+		subs: make(map[string]map[uint64]func([]byte)),
+		last: make(map[string][]byte),
+	}
+}
+
+// Publish delivers data synchronously to every subscriber of key.
+func (b *MemoryGlobalSignalBackend) Publish(ctx context.Context, key string, data []byte) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrGlobalSignalBackendClosed
+	}
+	b.last[key] = append([]byte(nil), data...)
+	fns := make([]func([]byte), 0, len(b.subs[key]))
+	for _, fn := range b.subs[key] {
+		fns = append(fns, fn)
+	}
+	b.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(append([]byte(nil), data...))
+	}
+	return nil
+}
+
+// Subscribe registers fn for key and replays the last payload, if any.
+func (b *MemoryGlobalSignalBackend) Subscribe(key string, fn func(data []byte)) (func(), error) {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrGlobalSignalBackendClosed
+	}
+	b.nextID++
+	id := b.nextID
+	if b.subs[key] == nil {
+		b.subs[key] = make(map[uint64]func([]byte))
+	}
+	b.subs[key][id] = fn
+	last := b.last[key]
+	b.mu.Unlock()
+
+	if last != nil {
+		fn(append([]byte(nil), last...))
+	}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs[key], id)
+		if len(b.subs[key]) == 0 {
+			delete(b.subs, key)
+		}
+	}, nil
+}
+
+// Close drops all subscriptions.
+func (b *MemoryGlobalSignalBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.subs = make(map[string]map[uint64]func([]byte))
+	return nil
+}
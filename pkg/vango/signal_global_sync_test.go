@@ -0,0 +1,126 @@
+package vango
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingListener struct {
+	id    uint64
+	dirty atomic.Int32
+}
+
+func (l *countingListener) MarkDirty() { l.dirty.Add(1) }
+func (l *countingListener) ID() uint64 { return l.id }
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func TestGlobalSignal_SyncsAcrossInstances(t *testing.T) {
+	backend := NewMemoryGlobalSignalBackend()
+	t.Cleanup(AttachGlobalSignalBackend(backend))
+
+	// Two signals with the same key stand in for two replicas.
+	nodeA := NewGlobalSignal(0, SyncKey("test_online_users"))
+	nodeB := NewGlobalSignal(0, SyncKey("test_online_users"))
+
+	reader := &countingListener{id: nextID()}
+	WithListener(reader, func() { _ = nodeB.Get() })
+
+	nodeA.Set(5)
+	waitFor(t, func() bool { return nodeB.Peek() == 5 })
+
+	if reader.dirty.Load() == 0 {
+		t.Fatal("remote update did not notify readers on the other instance")
+	}
+
+	nodeB.Inc()
+	waitFor(t, func() bool { return nodeA.Peek() == 6 })
+}
+
+func TestGlobalSignal_LateSubscriberReceivesLastValue(t *testing.T) {
+	backend := NewMemoryGlobalSignalBackend()
+	t.Cleanup(AttachGlobalSignalBackend(backend))
+
+	first := NewGlobalSignal("online", SyncKey("test_status"))
+	first.Set("degraded")
+	waitFor(t, func() bool {
+		backend.mu.RLock()
+		defer backend.mu.RUnlock()
+		return backend.last["test_status"] != nil
+	})
+
+	late := NewGlobalSignal("online", SyncKey("test_status"))
+	if got := late.Peek(); got != "degraded" {
+		t.Fatalf("late instance value = %q, want %q", got, "degraded")
+	}
+}
+
+func TestGlobalSignal_UnkeyedStaysLocal(t *testing.T) {
+	backend := NewMemoryGlobalSignalBackend()
+	t.Cleanup(AttachGlobalSignalBackend(backend))
+
+	sig := NewGlobalSignal(1)
+	sig.Set(2)
+	// PersistKey names session state; it does not opt in to sync.
+	persisted := NewGlobalSignal(1, PersistKey("test_persisted"))
+	persisted.Set(2)
+	time.Sleep(10 * time.Millisecond)
+
+	backend.mu.RLock()
+	defer backend.mu.RUnlock()
+	if len(backend.last) != 0 {
+		t.Fatalf("unkeyed global signal published %v", backend.last)
+	}
+}
+
+func TestGlobalSignal_BackendsAttachPerServer(t *testing.T) {
+	first := NewMemoryGlobalSignalBackend()
+	second := NewMemoryGlobalSignalBackend()
+	detachFirst := AttachGlobalSignalBackend(first)
+	t.Cleanup(AttachGlobalSignalBackend(second))
+
+	sig := NewGlobalSignal(0, SyncKey("test_two_servers"))
+	sig.Set(1)
+	published := func(b *MemoryGlobalSignalBackend) bool {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		return b.last["test_two_servers"] != nil
+	}
+	waitFor(t, func() bool { return published(first) && published(second) })
+
+	// Detaching one server's backend leaves the other connected.
+	detachFirst()
+	detachFirst()
+	first.mu.RLock()
+	subs := len(first.subs)
+	first.mu.RUnlock()
+	if subs != 0 {
+		t.Fatalf("detached backend has %d subscriptions", subs)
+	}
+
+	remote := NewGlobalSignal(0, SyncKey("test_two_servers"))
+	remote.Set(7)
+	waitFor(t, func() bool { return sig.Peek() == 7 })
+}
+
+func TestMemoryGlobalSignalBackend_Close(t *testing.T) {
+	backend := NewMemoryGlobalSignalBackend()
+	_ = backend.Close()
+	if err := backend.Publish(context.Background(), "k", []byte("x")); err != ErrGlobalSignalBackendClosed {
+		t.Fatalf("Publish after Close = %v, want ErrGlobalSignalBackendClosed", err)
+	}
+	if _, err := backend.Subscribe("k", func([]byte) {}); err != ErrGlobalSignalBackendClosed {
+		t.Fatalf("Subscribe after Close = %v, want ErrGlobalSignalBackendClosed", err)
+	}
+}
@@ -11,6 +11,9 @@ type signalOptions struct {
 	// persistKey is the explicit key for serialization.
 	// If empty, an auto-generated key is used based on component/position.
 	persistKey string
+
+	// syncKey identifies a global signal across server instances.
+	syncKey string
 }
 
 // Transient marks a signal as non-persistent.
@@ -43,6 +46,22 @@ func PersistKey(key string) SignalOption {
 	}
 }
 
+// SyncKey synchronizes a global signal across server instances under key.
+// Writes on one instance are published through the GlobalSignalBackend of
+// each server in the process and applied on every instance that declares a
+// global signal with the same key. It has no effect on other signals, and
+// it is independent of PersistKey, which names the signal in persisted
+// session state.
+//
+// Example:
+//
+//	var OnlineUsers = vango.NewGlobalSignal(0, vango.SyncKey("online_users"))
+func SyncKey(key string) SignalOption {
+	return func(o *signalOptions) {
+		o.syncKey = key
+	}
+}
+
 // applyOptions applies the given options and returns the resulting config.
 func applyOptions(opts []SignalOption) signalOptions {
 	var options signalOptions
@@ -0,0 +1,172 @@
+package vango
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// =============================================================================
+// Signal Persistence Store (Phase 12)
+// =============================================================================
+
+// SignalPersistStoreKey is the context key for the session's SignalPersistStore.
+// The server package sets this on the session root owner so that signals
+// created with PersistKey can register themselves and pick up restored values.
+var SignalPersistStoreKey = &struct{ name string }{"SignalPersistStore"}
+
+// persistedSignal is implemented by Signal[T] to move values to and from JSON
+// without the store knowing the concrete type.
+type persistedSignal interface {
+	PersistableSignal
+	valueJSON() ([]byte, error)
+	setJSON(data []byte) error
+}
+
+// SignalPersistStore tracks the persistable signals of a single session.
+//
+// Signals created with PersistKey (and without Transient) register themselves
+// with the store found in their owner hierarchy. At serialize time the session
+// calls Snapshot to collect their current values. After a restart or resume,
+// the session calls Restore with the previously saved values; each value is
+// applied once, when a signal with the matching key is next created.
+//
+// Signals without an explicit PersistKey are not persisted.
+type SignalPersistStore struct {
+	mu sync.Mutex
+
+	// signals holds the live signal for each persist key.
+	// If several live signals share a key, the most recently created one wins.
+	signals map[string]persistedSignal
+
+	// pending holds restored values that have not yet been claimed by a signal.
+	pending map[string]json.RawMessage
+}
+
+// NewSignalPersistStore creates an empty SignalPersistStore.
+func NewSignalPersistStore() *SignalPersistStore {
+	return &SignalPersistStore{
+		signals: make(map[string]persistedSignal),
+		pending: make(map[string]json.RawMessage),
+	}
+}
+
+// Restore queues previously serialized signal values.
+// Values are applied lazily when NewSignal is called with the matching key.
+// If a live signal with the key is already registered, its value is updated
+// immediately.
+func (ps *SignalPersistStore) Restore(values map[string]json.RawMessage) {
+	if ps == nil || len(values) == 0 {
+		return
+	}
+
+	ps.mu.Lock()
+	live := make(map[string]persistedSignal)
+	for key, raw := range values {
+		if sig, ok := ps.signals[key]; ok {
+			live[key] = sig
+			continue
+		}
+		ps.pending[key] = raw
+	}
+	ps.mu.Unlock()
+
+	// Apply to live signals outside the lock: setJSON notifies subscribers.
+	for key, sig := range live {
+		_ = sig.setJSON(values[key])
+	}
+}
+
+// Snapshot returns the current JSON value of every registered signal, keyed by
+// persist key. Values that fail to marshal are skipped. Restored values that
+// have not been claimed yet are carried over so that state survives a
+// resume in which the owning component is never rendered.
+func (ps *SignalPersistStore) Snapshot() map[string]json.RawMessage {
+	if ps == nil {
+		return nil
+	}
+
+	ps.mu.Lock()
+	signals := make(map[string]persistedSignal, len(ps.signals))
+	for key, sig := range ps.signals {
+		signals[key] = sig
+	}
+	out := make(map[string]json.RawMessage, len(ps.signals)+len(ps.pending))
+	for key, raw := range ps.pending {
+		out[key] = raw
+	}
+	ps.mu.Unlock()
+
+	for key, sig := range signals {
+		data, err := sig.valueJSON()
+		if err != nil {
+			continue
+		}
+		out[key] = data
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// Keys returns the sorted persist keys of all registered signals.
+func (ps *SignalPersistStore) Keys() []string {
+	if ps == nil {
+		return nil
+	}
+
+	ps.mu.Lock()
+	keys := make([]string, 0, len(ps.signals))
+	for key := range ps.signals {
+		keys = append(keys, key)
+	}
+	ps.mu.Unlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// register adds sig under key and applies any pending restored value.
+// Returns a function that removes the registration if sig is still the
+// signal registered under key.
+func (ps *SignalPersistStore) register(key string, sig persistedSignal) func() {
+	ps.mu.Lock()
+	ps.signals[key] = sig
+	raw, restored := ps.pending[key]
+	if restored {
+		delete(ps.pending, key)
+	}
+	ps.mu.Unlock()
+
+	if restored {
+		_ = sig.setJSON(raw)
+	}
+
+	return func() {
+		ps.mu.Lock()
+		if ps.signals[key] == sig {
+			delete(ps.signals, key)
+		}
+		ps.mu.Unlock()
+	}
+}
+
+// registerPersisted attaches a newly created signal to the persist store of
+// the given owner hierarchy, if any. Signals created during a component
+// render are unregistered when the component's owner is disposed.
+func registerPersisted(owner *Owner, inRender bool, sig persistedSignal) {
+	if owner == nil || sig.IsTransient() || sig.PersistKey() == "" {
+		return
+	}
+	ps, ok := owner.GetValue(SignalPersistStoreKey).(*SignalPersistStore)
+	if !ok || ps == nil {
+		return
+	}
+
+	unregister := ps.register(sig.PersistKey(), sig)
+	if inRender {
+		owner.OnCleanup(unregister)
+	}
+}
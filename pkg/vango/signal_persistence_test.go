@@ -1,6 +1,9 @@
 package vango
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestSignalPersistenceOptionsAndSetAny(t *testing.T) {
 	s := NewSignal(123, Transient(), PersistKey("user_id"))
@@ -34,3 +37,94 @@ func TestSignalPersistenceOptionsAndSetAny(t *testing.T) {
 	}
 }
 
+func TestSignalPersistStore_SnapshotAndRestore(t *testing.T) {
+	root := NewOwner(nil)
+	store := NewSignalPersistStore()
+	root.SetValue(SignalPersistStoreKey, store)
+
+	var count *Signal[int]
+	var cursor *Signal[int]
+	var plain *Signal[string]
+	WithOwner(root, func() {
+		count = NewSignal(1, PersistKey("count"))
+		cursor = NewSignal(5, PersistKey("cursor"), Transient())
+		plain = NewSignal("x")
+	})
+	count.Set(42)
+	cursor.Set(9)
+	plain.Set("y")
+
+	snap := store.Snapshot()
+	if len(snap) != 1 {
+		t.Fatalf("Snapshot() = %v, want only the count key", snap)
+	}
+	if got := string(snap["count"]); got != "42" {
+		t.Fatalf("Snapshot()[count] = %s, want 42", got)
+	}
+
+	// Restore into a fresh store: the value is applied when the signal is created.
+	root2 := NewOwner(nil)
+	store2 := NewSignalPersistStore()
+	store2.Restore(snap)
+	root2.SetValue(SignalPersistStoreKey, store2)
+
+	var restored *Signal[int]
+	WithOwner(root2, func() {
+		restored = NewSignal(1, PersistKey("count"))
+	})
+	if got := restored.Get(); got != 42 {
+		t.Fatalf("restored signal = %d, want 42", got)
+	}
+
+	// Pending values are consumed once.
+	var second *Signal[int]
+	WithOwner(root2, func() {
+		second = NewSignal(1, PersistKey("count"))
+	})
+	if got := second.Get(); got != 1 {
+		t.Fatalf("second signal = %d, want initial value 1", got)
+	}
+}
+
+func TestSignalPersistStore_RestoreUpdatesLiveSignal(t *testing.T) {
+	root := NewOwner(nil)
+	store := NewSignalPersistStore()
+	root.SetValue(SignalPersistStoreKey, store)
+
+	var sig *Signal[[]string]
+	WithOwner(root, func() {
+		sig = NewSignal([]string{"a"}, PersistKey("tags"))
+	})
+
+	store.Restore(map[string]json.RawMessage{"tags": json.RawMessage(`["b","c"]`)})
+	if got := sig.Peek(); len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Fatalf("live signal after Restore = %v, want [b c]", got)
+	}
+
+	// Mismatched JSON leaves the value untouched.
+	store.Restore(map[string]json.RawMessage{"tags": json.RawMessage(`42`)})
+	if got := sig.Peek(); len(got) != 2 {
+		t.Fatalf("live signal after bad Restore = %v, want unchanged", got)
+	}
+}
+
+func TestSignalPersistStore_UnregistersOnOwnerDispose(t *testing.T) {
+	root := NewOwner(nil)
+	store := NewSignalPersistStore()
+	root.SetValue(SignalPersistStoreKey, store)
+
+	child := NewOwner(root)
+	WithOwner(child, func() {
+		child.StartRender()
+		_ = NewSignal(3, PersistKey("draft"))
+		child.EndRender()
+	})
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "draft" {
+		t.Fatalf("Keys() = %v, want [draft]", keys)
+	}
+
+	child.Dispose()
+	if keys := store.Keys(); len(keys) != 0 {
+		t.Fatalf("Keys() after Dispose = %v, want empty", keys)
+	}
+}
@@ -457,3 +457,10 @@ func (s *SimpleSessionSignalStore) GetOrCreateSignal(id uint64, createFn func()
 	actual, _ := s.signals.LoadOrStore(id, newVal)
 	return actual
 }
+
+// Range calls fn for each signal in the store until fn returns false.
+func (s *SimpleSessionSignalStore) Range(fn func(id uint64, signal any) bool) {
+	s.signals.Range(func(k, v any) bool {
+		return fn(k.(uint64), v)
+	})
+}
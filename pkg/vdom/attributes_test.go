@@ -1,6 +1,9 @@
 package vdom
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestGlobalAttributes(t *testing.T) {
 	tests := []struct {
@@ -25,6 +28,8 @@ func TestGlobalAttributes(t *testing.T) {
 		{"TitleAttr", TitleAttr("Tooltip"), "title", "Tooltip"},
 		{"Lang", Lang("en"), "lang", "en"},
 		{"Dir", Dir("ltr"), "dir", "ltr"},
+		{"Transition", Transition("fade"), "data-transition", "fade"},
+		{"TransitionTimeout", TransitionTimeout(300 * time.Millisecond), "data-transition-timeout", int64(300)},
 	}
 
 	for _, tt := range tests {
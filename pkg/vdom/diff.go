@@ -58,6 +58,8 @@ func diff(prev, next *VNode, parentHID string, patches *[]Patch) {
 		diffComponent(prev, next, parentHID, patches)
 	case KindRaw:
 		diffRaw(prev, next, parentHID, patches)
+	case KindPortal:
+		diffPortal(prev, next, patches)
 	}
 }
 
@@ -115,6 +117,25 @@ func diffFragment(prev, next *VNode, parentHID string, patches *[]Patch) {
 	diffChildren(prev, next, parentHID, patches)
 }
 
+// diffPortal compares portal nodes.
+func diffPortal(prev, next *VNode, patches *[]Patch) {
+	// Different target - remount the portal in the new container
+	if prev.Tag != next.Tag {
+		*patches = append(*patches, Patch{
+			Op:   PatchReplaceNode,
+			HID:  prev.HID,
+			Node: next,
+		})
+		return
+	}
+
+	// Copy HID from prev to next
+	next.HID = prev.HID
+
+	// Diff children - the portal's container is their parent
+	diffChildren(prev, next, prev.HID, patches)
+}
+
 // diffComponent compares component nodes.
 func diffComponent(prev, next *VNode, parentHID string, patches *[]Patch) {
 	// Copy HID
@@ -201,8 +222,10 @@ func diffChildren(prev, next *VNode, parentHID string, patches *[]Patch) {
 	prevChildren := prev.Children
 	nextChildren := next.Children
 
-	// Check if children are keyed
-	if hasKeys(prevChildren) || hasKeys(nextChildren) {
+	// Check if children are keyed. Text nodes cannot be moved, so keyed
+	// children mixed with text are patched in place like unkeyed ones.
+	keyed := hasKeys(prevChildren) || hasKeys(nextChildren)
+	if keyed && !hasTextChild(prev) && !hasTextChild(next) {
 		diffKeyedChildren(prev, prevChildren, nextChildren, parentHID, patches)
 	} else {
 		diffUnkeyedChildren(prev, prevChildren, nextChildren, parentHID, patches)
@@ -248,76 +271,199 @@ func diffUnkeyedChildren(parent *VNode, prev, next []*VNode, parentHID string, p
 }
 
 // diffKeyedChildren handles children with keys for efficient reordering.
+//
+// Children are matched by key; unkeyed children are matched positionally
+// against the unkeyed children of the other list. Patches are emitted in
+// three phases so that the client can apply them in order:
+//
+//  1. Unmatched previous children are removed.
+//  2. Matched children that are not part of the longest increasing
+//     subsequence of their new positions are moved, and new children are
+//     inserted, walking the new list from right to left so that each node is
+//     placed before an already-positioned anchor.
+//  3. Matched pairs are diffed recursively.
+//
+// Prepending one row to a long list therefore yields a single insert and no
+// moves, and reversing n rows yields n-1 moves.
+//
+// Move and insert indexes count element children only, matching the client's
+// use of parent.children. The children must not include text nodes, which the
+// client cannot address; diffChildren falls back to diffUnkeyedChildren then.
 func diffKeyedChildren(parent *VNode, prev, next []*VNode, parentHID string, patches *[]Patch) {
-	// Build key maps: key -> index
-	prevKeyMap := make(map[string]int)
-	nextKeyMap := make(map[string]int)
-
+	// Map each next child to its matching prev index (-1 if new).
+	prevKeyMap := make(map[string]int, len(prev))
+	var prevUnkeyed []int
 	for i, child := range prev {
-		if key := getKey(child); key != "" {
+		key := getKey(child)
+		if key == "" {
+			prevUnkeyed = append(prevUnkeyed, i)
+			continue
+		}
+		if _, dup := prevKeyMap[key]; !dup {
 			prevKeyMap[key] = i
 		}
 	}
+
+	sources := make([]int, len(next))
+	matched := make([]bool, len(prev))
 	for i, child := range next {
+		sources[i] = -1
 		if key := getKey(child); key != "" {
-			nextKeyMap[key] = i
+			if prevIdx, ok := prevKeyMap[key]; ok && !matched[prevIdx] {
+				sources[i] = prevIdx
+				matched[prevIdx] = true
+			}
+			continue
+		}
+		if len(prevUnkeyed) > 0 {
+			sources[i] = prevUnkeyed[0]
+			matched[prevUnkeyed[0]] = true
+			prevUnkeyed = prevUnkeyed[1:]
 		}
 	}
 
-	// Track which prev nodes have been matched
-	matched := make(map[int]bool)
+	// Phase 1: remove unmatched prev nodes.
+	for i, prevChild := range prev {
+		if !matched[i] {
+			*patches = append(*patches, Patch{
+				Op:  PatchRemoveNode,
+				HID: prevChild.HID,
+			})
+		}
+	}
 
-	// Process next children in order
-	for nextIdx, nextChild := range next {
-		key := getKey(nextChild)
+	// Surviving children in their current DOM order, and the new index of
+	// each. Nodes on the LIS of those indexes keep their place.
+	nextIndex := make([]int, len(prev))
+	for i := range nextIndex {
+		nextIndex[i] = -1
+	}
+	for i, src := range sources {
+		if src >= 0 {
+			nextIndex[src] = i
+		}
+	}
+	// A Fenwick tree counts the surviving children by prev position.
+	var order []int
+	counts := newFenwick(len(prev) + 1)
+	for i := range prev {
+		if matched[i] {
+			order = append(order, nextIndex[i])
+			counts.add(i, 1)
+		}
+	}
+	stable := make(map[int]bool, len(order))
+	for _, idx := range longestIncreasingSubsequence(order) {
+		stable[order[idx]] = true
+	}
 
-		if key != "" {
-			if prevIdx, exists := prevKeyMap[key]; exists {
-				// Found matching key
-				matched[prevIdx] = true
-				prevChild := prev[prevIdx]
-
-				// Check if position changed
-				if prevIdx != nextIdx {
-					*patches = append(*patches, Patch{
-						Op:       PatchMoveNode,
-						HID:      prevChild.HID,
-						ParentID: parent.HID,
-						Index:    nextIdx,
-					})
-				}
-
-				// Diff the node itself - pass parent HID for text nodes
-				diff(prevChild, nextChild, parentHID, patches)
-			} else {
-				// New node with key
-				*patches = append(*patches, Patch{
-					Op:       PatchInsertNode,
-					ParentID: parent.HID,
-					Index:    nextIdx,
-					Node:     nextChild,
-				})
-			}
-		} else {
-			// Unkeyed node in keyed list - treat as insert
+	// Phase 2: walk right to left, placing each node before the anchor
+	// (the element placed just after it, or the end of the parent).
+	//
+	// A placed node is counted at the anchor's position, which is the prev
+	// position of the stable node it was placed before, or len(prev) for
+	// the end. Placed nodes precede everything already counted at that
+	// position, so the anchor's index is the count at lower positions.
+	anchor := len(prev)
+	for i := len(next) - 1; i >= 0; i-- {
+		nextChild := next[i]
+		src := sources[i]
+
+		if src < 0 {
 			*patches = append(*patches, Patch{
 				Op:       PatchInsertNode,
 				ParentID: parent.HID,
-				Index:    nextIdx,
+				Index:    counts.sum(anchor),
 				Node:     nextChild,
 			})
+			counts.add(anchor, 1)
+			continue
 		}
-	}
 
-	// Remove unmatched prev nodes
-	for i, prevChild := range prev {
-		if !matched[i] {
+		prevChild := prev[src]
+		if !stable[i] {
 			*patches = append(*patches, Patch{
-				Op:  PatchRemoveNode,
-				HID: prevChild.HID,
+				Op:       PatchMoveNode,
+				HID:      prevChild.HID,
+				ParentID: parent.HID,
+				Index:    counts.sum(anchor),
 			})
+			counts.add(src, -1)
+			counts.add(anchor, 1)
+			continue
+		}
+		anchor = src
+	}
+
+	// Phase 3: diff matched pairs - pass parent HID for text nodes.
+	for i, src := range sources {
+		if src >= 0 {
+			diff(prev[src], next[i], parentHID, patches)
+		}
+	}
+}
+
+// longestIncreasingSubsequence returns the positions in seq of one longest
+// strictly increasing subsequence, in ascending order. O(n log n).
+func longestIncreasingSubsequence(seq []int) []int {
+	if len(seq) == 0 {
+		return nil
+	}
+
+	// tails[k] is the position in seq of the smallest tail of an increasing
+	// subsequence of length k+1; links[i] is the predecessor of seq[i].
+	tails := make([]int, 0, len(seq))
+	links := make([]int, len(seq))
+	for i, v := range seq {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if seq[tails[mid]] < v {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		if lo > 0 {
+			links[i] = tails[lo-1]
+		} else {
+			links[i] = -1
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
 		}
 	}
+
+	result := make([]int, len(tails))
+	for k, i := len(tails)-1, tails[len(tails)-1]; k >= 0; k, i = k-1, links[i] {
+		result[k] = i
+	}
+	return result
+}
+
+// fenwick is a binary indexed tree of counts by position.
+type fenwick []int
+
+func newFenwick(n int) fenwick {
+	return make(fenwick, n+1)
+}
+
+// add adds delta to the count at position i.
+func (f fenwick) add(i, delta int) {
+	for i++; i < len(f); i += i & -i {
+		f[i] += delta
+	}
+}
+
+// sum returns the total count at positions below i.
+func (f fenwick) sum(i int) int {
+	total := 0
+	for ; i > 0; i -= i & -i {
+		total += f[i]
+	}
+	return total
 }
 
 // getKey extracts the key from a node's props.
@@ -1,6 +1,10 @@
 package vdom
 
-import "testing"
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
 
 // Helper to assign HIDs for testing
 func assignTestHIDs(node *VNode) {
@@ -339,6 +343,43 @@ func TestDiffKeyedAddition(t *testing.T) {
 	}
 }
 
+func TestDiffPortal(t *testing.T) {
+	t.Run("children", func(t *testing.T) {
+		prev := Div(Portal("body", Div(Text("A"))))
+		AssignHIDs(prev, NewHIDGenerator())
+		next := Div(Portal("body", Div(Text("B")), Span()))
+		AssignHIDs(next, NewHIDGenerator())
+
+		patches := Diff(prev, next)
+
+		portal := prev.Children[0]
+		if next.Children[0].HID != portal.HID {
+			t.Errorf("portal HID = %q, want %q", next.Children[0].HID, portal.HID)
+		}
+		if len(patches) != 2 {
+			t.Fatalf("Expected 2 patches, got %d: %+v", len(patches), patches)
+		}
+		if patches[0].Op != PatchSetText || patches[0].HID != portal.Children[0].HID {
+			t.Errorf("patch 0 = %+v, want SetText on the portal's child", patches[0])
+		}
+		if patches[1].Op != PatchInsertNode || patches[1].ParentID != portal.HID || patches[1].Index != 1 {
+			t.Errorf("patch 1 = %+v, want InsertNode into the portal", patches[1])
+		}
+	})
+
+	t.Run("target change", func(t *testing.T) {
+		prev := Div(Portal("body", Div()))
+		AssignHIDs(prev, NewHIDGenerator())
+		next := Div(Portal("#modals", Div()))
+
+		patches := Diff(prev, next)
+
+		if len(patches) != 1 || patches[0].Op != PatchReplaceNode || patches[0].HID != prev.Children[0].HID {
+			t.Errorf("patches = %+v, want the portal replaced", patches)
+		}
+	})
+}
+
 func TestDiffKeyedRemoval(t *testing.T) {
 	prev := Ul(
 		Li(Key("a"), Text("A")),
@@ -366,6 +407,307 @@ func TestDiffKeyedRemoval(t *testing.T) {
 	}
 }
 
+// applyChildPatches simulates the client applying structural patches to the
+// element children of a single parent. Nodes are identified by label.
+func applyChildPatches(t *testing.T, prev []*VNode, patches []Patch) []string {
+	t.Helper()
+	var dom []string
+	byHID := make(map[string]string)
+	for _, child := range prev {
+		if child.Kind == KindText {
+			continue
+		}
+		dom = append(dom, childLabel(child))
+		byHID[child.HID] = childLabel(child)
+	}
+	indexOf := func(label string) int {
+		i := indexOfLabel(dom, label)
+		if i < 0 {
+			t.Fatalf("node %q not in DOM %v", label, dom)
+		}
+		return i
+	}
+	insertBefore := func(label string, index int) {
+		// Mirrors parent.insertBefore(el, parent.children[index]).
+		var ref string
+		if index < len(dom) {
+			ref = dom[index]
+		}
+		if i := indexOfLabel(dom, label); i >= 0 {
+			dom = append(dom[:i], dom[i+1:]...)
+		}
+		at := len(dom)
+		if ref != "" {
+			at = indexOf(ref)
+		}
+		dom = append(dom, "")
+		copy(dom[at+1:], dom[at:])
+		dom[at] = label
+	}
+	for _, p := range patches {
+		switch p.Op {
+		case PatchRemoveNode:
+			label := byHID[p.HID]
+			dom = append(dom[:indexOf(label)], dom[indexOf(label)+1:]...)
+		case PatchMoveNode:
+			insertBefore(byHID[p.HID], p.Index)
+		case PatchInsertNode:
+			if p.Node.Kind != KindText {
+				insertBefore(childLabel(p.Node), p.Index)
+			}
+		}
+	}
+	return dom
+}
+
+func indexOfLabel(list []string, label string) int {
+	for i, l := range list {
+		if l == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func childLabel(node *VNode) string {
+	if key := getKey(node); key != "" {
+		return key
+	}
+	label := node.Tag
+	for _, child := range node.Children {
+		label += ":" + child.Text
+	}
+	return label
+}
+
+func keyedRows(keys ...string) *VNode {
+	items := make([]any, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, Li(Key(k), Text(k)))
+	}
+	return Ul(items...)
+}
+
+func countOps(patches []Patch) map[PatchOp]int {
+	counts := make(map[PatchOp]int)
+	for _, p := range patches {
+		counts[p.Op]++
+	}
+	return counts
+}
+
+func checkKeyedDiff(t *testing.T, prev, next *VNode) map[PatchOp]int {
+	t.Helper()
+	assignTestHIDs(prev)
+	prevChildren := append([]*VNode(nil), prev.Children...)
+	patches := Diff(prev, next)
+
+	got := applyChildPatches(t, prevChildren, patches)
+	var want []string
+	for _, child := range next.Children {
+		if child.Kind != KindText {
+			want = append(want, childLabel(child))
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("children = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("children = %v, want %v", got, want)
+		}
+	}
+	return countOps(patches)
+}
+
+func TestDiffKeyedPrependLongList(t *testing.T) {
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	prev := keyedRows(keys...)
+	next := keyedRows(append([]string{"new"}, keys...)...)
+
+	ops := checkKeyedDiff(t, prev, next)
+	if ops[PatchInsertNode] != 1 || ops[PatchMoveNode] != 0 || ops[PatchRemoveNode] != 0 {
+		t.Errorf("ops = %v, want 1 insert and no moves", ops)
+	}
+}
+
+func TestDiffKeyedReverseLongList(t *testing.T) {
+	keys := make([]string, 2000)
+	reversed := make([]string, len(keys))
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		reversed[len(keys)-1-i] = keys[i]
+	}
+
+	ops := checkKeyedDiff(t, keyedRows(keys...), keyedRows(reversed...))
+	if ops[PatchMoveNode] != len(keys)-1 {
+		t.Errorf("moves = %d, want %d", ops[PatchMoveNode], len(keys)-1)
+	}
+}
+
+func TestDiffKeyedMinimalMoves(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev      []string
+		next      []string
+		wantMoves int
+	}{
+		{"rotate left", []string{"a", "b", "c", "d"}, []string{"b", "c", "d", "a"}, 1},
+		{"rotate right", []string{"a", "b", "c", "d"}, []string{"d", "a", "b", "c"}, 1},
+		{"swap ends", []string{"a", "b", "c", "d", "e"}, []string{"e", "b", "c", "d", "a"}, 2},
+		{"reverse", []string{"a", "b", "c", "d", "e"}, []string{"e", "d", "c", "b", "a"}, 4},
+		{"remove and insert", []string{"a", "b", "c"}, []string{"x", "a", "c", "y"}, 0},
+		{"replace all", []string{"a", "b"}, []string{"c", "d"}, 0},
+		{"unchanged", []string{"a", "b", "c"}, []string{"a", "b", "c"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := checkKeyedDiff(t, keyedRows(tt.prev...), keyedRows(tt.next...))
+			if ops[PatchMoveNode] != tt.wantMoves {
+				t.Errorf("moves = %d, want %d (ops %v)", ops[PatchMoveNode], tt.wantMoves, ops)
+			}
+		})
+	}
+}
+
+func TestDiffKeyedShuffle(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		var prevKeys, nextKeys []string
+		for i := 0; i < 30; i++ {
+			k := strconv.Itoa(i)
+			if rng.Intn(4) != 0 {
+				prevKeys = append(prevKeys, k)
+			}
+			if rng.Intn(4) != 0 {
+				nextKeys = append(nextKeys, k)
+			}
+		}
+		rng.Shuffle(len(nextKeys), func(i, j int) {
+			nextKeys[i], nextKeys[j] = nextKeys[j], nextKeys[i]
+		})
+		checkKeyedDiff(t, keyedRows(prevKeys...), keyedRows(nextKeys...))
+	}
+}
+
+func TestDiffKeyedMixedUnkeyed(t *testing.T) {
+	prev := Ul(
+		Li(Text("header")),
+		Li(Key("a"), Text("A")),
+		Li(Key("b"), Text("B")),
+		Li(Text("footer")),
+	)
+	next := Ul(
+		Li(Text("header")),
+		Li(Key("b"), Text("B")),
+		Li(Key("a"), Text("A")),
+		Li(Text("footer")),
+	)
+
+	ops := checkKeyedDiff(t, prev, next)
+	if ops[PatchInsertNode] != 0 || ops[PatchRemoveNode] != 0 {
+		t.Errorf("unkeyed children should be reused, got ops %v", ops)
+	}
+	if ops[PatchMoveNode] != 1 {
+		t.Errorf("moves = %d, want 1", ops[PatchMoveNode])
+	}
+}
+
+func TestDiffKeyedMixedUnkeyedContentChange(t *testing.T) {
+	prev := Ul(
+		Li(Key("a"), Text("A")),
+		Li(Text("note")),
+	)
+	assignTestHIDs(prev)
+	next := Ul(
+		Li(Key("a"), Text("A")),
+		Li(Text("updated")),
+	)
+
+	patches := Diff(prev, next)
+	ops := countOps(patches)
+	if ops[PatchInsertNode] != 0 || ops[PatchRemoveNode] != 0 || ops[PatchMoveNode] != 0 {
+		t.Errorf("expected in-place update, got ops %v", ops)
+	}
+	if ops[PatchSetText] != 1 {
+		t.Errorf("SetText = %d, want 1", ops[PatchSetText])
+	}
+}
+
+func TestDiffKeyedMixedTextReorder(t *testing.T) {
+	prev := P(
+		Text("x "),
+		Span(Key("a"), Text("A")),
+		Text(" y "),
+		Span(Key("b"), Text("B")),
+	)
+	assignTestHIDs(prev)
+	next := P(
+		Text("x "),
+		Span(Key("b"), Text("B")),
+		Text(" y "),
+		Span(Key("a"), Text("A")),
+	)
+
+	// Text nodes cannot be moved, so the children are patched in place.
+	texts := make([]string, len(prev.Children))
+	slot := make(map[string]int)
+	for i, child := range prev.Children {
+		slot[child.HID] = i
+		if child.Kind == KindText {
+			texts[i] = child.Text
+			continue
+		}
+		texts[i] = child.Children[0].Text
+		slot[child.Children[0].HID] = i
+	}
+	for _, p := range Diff(prev, next) {
+		switch p.Op {
+		case PatchSetText:
+			texts[slot[p.HID]] = p.Value
+		case PatchInsertNode, PatchRemoveNode, PatchMoveNode, PatchReplaceNode:
+			t.Fatalf("unexpected structural patch %v", p)
+		}
+	}
+
+	want := []string{"x ", "B", " y ", "A"}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Fatalf("children = %q, want %q", texts, want)
+		}
+	}
+}
+
+func TestLongestIncreasingSubsequence(t *testing.T) {
+	tests := []struct {
+		seq  []int
+		want int
+	}{
+		{nil, 0},
+		{[]int{0, 1, 2}, 3},
+		{[]int{2, 1, 0}, 1},
+		{[]int{1, 2, 3, 0}, 3},
+		{[]int{3, 0, 1, 2}, 3},
+		{[]int{4, 1, 2, 3, 0}, 3},
+	}
+	for _, tt := range tests {
+		got := longestIncreasingSubsequence(tt.seq)
+		if len(got) != tt.want {
+			t.Errorf("LIS(%v) = %v, want length %d", tt.seq, got, tt.want)
+			continue
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i] <= got[i-1] || tt.seq[got[i]] <= tt.seq[got[i-1]] {
+				t.Errorf("LIS(%v) = %v is not increasing", tt.seq, got)
+			}
+		}
+	}
+}
+
 func TestDiffFragmentChildren(t *testing.T) {
 	prev := Fragment(Div(), Span())
 	assignTestHIDs(prev)
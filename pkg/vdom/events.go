@@ -89,7 +89,7 @@ func OnReset(handler any) EventHandler { return event("reset", handler) }
 // OnDragStart handles dragstart events.
 func OnDragStart(handler any) EventHandler { return event("dragstart", handler) }
 
-// OnDrag handles drag events.
+// OnDrag handles drag events, throttled to one every 50ms by default.
 func OnDrag(handler any) EventHandler { return event("drag", handler) }
 
 // OnDragEnd handles dragend events.
@@ -98,7 +98,7 @@ func OnDragEnd(handler any) EventHandler { return event("dragend", handler) }
 // OnDragEnter handles dragenter events.
 func OnDragEnter(handler any) EventHandler { return event("dragenter", handler) }
 
-// OnDragOver handles dragover events.
+// OnDragOver handles dragover events, throttled to one every 50ms by default.
 func OnDragOver(handler any) EventHandler { return event("dragover", handler) }
 
 // OnDragLeave handles dragleave events.
@@ -107,6 +107,13 @@ func OnDragLeave(handler any) EventHandler { return event("dragleave", handler)
 // OnDrop handles drop events.
 func OnDrop(handler any) EventHandler { return event("drop", handler) }
 
+// Upload events
+
+// OnUpload streams the files picked in or dropped on a file input over the
+// live session. The handler is an upload.Live naming the store and limits
+// and receiving progress.
+func OnUpload(handler any) EventHandler { return event("upload", handler) }
+
 // Touch events
 
 // OnTouchStart handles touchstart events.
@@ -130,6 +137,8 @@ func OnPointerDown(handler any) EventHandler { return event("pointerdown", handl
 func OnPointerUp(handler any) EventHandler { return event("pointerup", handler) }
 
 // OnPointerMove handles pointermove events.
+// The client throttles them to one every 50ms unless a Throttle modifier
+// sets another interval.
 func OnPointerMove(handler any) EventHandler { return event("pointermove", handler) }
 
 // OnPointerEnter handles pointerenter events.
@@ -161,6 +170,7 @@ func OnPause(handler any) EventHandler { return event("pause", handler) }
 func OnEnded(handler any) EventHandler { return event("ended", handler) }
 
 // OnTimeUpdate handles timeupdate events (playback position changed).
+// The client throttles them to one every 250ms by default.
 func OnTimeUpdate(handler any) EventHandler { return event("timeupdate", handler) }
 
 // OnLoadStart handles loadstart events (loading begins).
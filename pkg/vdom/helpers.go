@@ -82,6 +82,18 @@ func Fragment(children ...any) *VNode {
 	return node
 }
 
+// Portal renders children into the container matched by target (a CSS
+// selector such as "body" or "#modals") instead of in place. The portal is
+// still part of the declaring component's tree: its children are diffed,
+// receive HIDs and have their handlers collected like any other subtree,
+// and they are removed from the target when the portal unmounts.
+func Portal(target string, children ...any) *VNode {
+	node := Fragment(children...)
+	node.Kind = KindPortal
+	node.Tag = target
+	return node
+}
+
 // If returns the node if condition is true, nil otherwise.
 func If(condition bool, node *VNode) *VNode {
 	if condition {
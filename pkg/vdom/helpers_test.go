@@ -84,6 +84,22 @@ func TestFragment(t *testing.T) {
 	})
 }
 
+func TestPortal(t *testing.T) {
+	node := Portal("#modals", Div(), nil, "Hello")
+	if node.Kind != KindPortal {
+		t.Errorf("Kind = %v, want KindPortal", node.Kind)
+	}
+	if node.Tag != "#modals" {
+		t.Errorf("Tag = %q, want #modals", node.Tag)
+	}
+	if len(node.Children) != 2 {
+		t.Fatalf("Children len = %v, want 2", len(node.Children))
+	}
+	if node.Children[1].Kind != KindText {
+		t.Errorf("Child kind = %v, want KindText", node.Children[1].Kind)
+	}
+}
+
 func TestIf(t *testing.T) {
 	node := Div()
 
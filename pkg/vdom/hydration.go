@@ -48,7 +48,8 @@ func AssignHIDs(node *VNode, gen *HIDGenerator) {
 
 	// Assign HIDs to all elements to ensure they are addressable in the client.
 	// This supports dynamic updates (InsertNode, RemoveNode, etc.) anywhere in the tree.
-	if node.Kind == KindElement && node.HID == "" {
+	// Portals get one too: their HID addresses the container mounted in the target.
+	if (node.Kind == KindElement || node.Kind == KindPortal) && node.HID == "" {
 		node.HID = gen.Next()
 	}
 
@@ -78,8 +79,8 @@ func AssignAllHIDs(node *VNode, gen *HIDGenerator) {
 		return
 	}
 
-	// Assign HID to all elements
-	if node.Kind == KindElement {
+	// Assign HID to all elements and portals
+	if node.Kind == KindElement || node.Kind == KindPortal {
 		node.HID = gen.Next()
 	}
 
@@ -105,6 +105,20 @@ func TestAssignHIDs(t *testing.T) {
 			t.Errorf("Button HID = %v, want h4", form.Children[1].HID)
 		}
 	})
+	t.Run("portals", func(t *testing.T) {
+		tree := Div(Portal("#modals", Button(OnClick(func() {}))))
+
+		gen := NewHIDGenerator()
+		AssignHIDs(tree, gen)
+
+		portal := tree.Children[0]
+		if portal.HID != "h2" {
+			t.Errorf("Portal HID = %v, want h2", portal.HID)
+		}
+		if portal.Children[0].HID != "h3" {
+			t.Errorf("Button HID = %v, want h3", portal.Children[0].HID)
+		}
+	})
 }
 
 func TestAssignAllHIDs(t *testing.T) {
@@ -0,0 +1,34 @@
+package vdom
+
+import "time"
+
+// Transition marks an element as animated on the client. When the element,
+// or one of its element children, is inserted or removed by a patch, the
+// client applies CSS classes named after name:
+//
+//	name-enter-from, name-enter-active, name-enter-to  on insert
+//	name-leave-from, name-leave-active, name-leave-to  on removal
+//	name-move                                          on reorder
+//
+// A removed element stays in the DOM until its transition or animation ends
+// (or TransitionTimeout elapses), but it is detached from the tree at once:
+// its HIDs are released and later patches ignore it. Children that change
+// position are animated with FLIP using the name-move class.
+//
+// Example:
+//
+//	Ul(Transition("list"),
+//	    Range(items, func(item Item, i int) *VNode {
+//	        return Li(Key(item.ID), Text(item.Name))
+//	    }),
+//	)
+func Transition(name string) Attr {
+	return attr("data-transition", name)
+}
+
+// TransitionTimeout bounds how long the client waits for a transition to
+// end before finishing it. By default it uses the durations in the
+// element's computed style.
+func TransitionTimeout(d time.Duration) Attr {
+	return attr("data-transition-timeout", d.Milliseconds())
+}
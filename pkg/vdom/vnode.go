@@ -11,6 +11,7 @@ const (
 	KindFragment                   // Grouping without wrapper
 	KindComponent                  // Nested component
 	KindRaw                        // Raw HTML (dangerous)
+	KindPortal                     // Children mounted into another container
 )
 
 // String returns the string representation of the VKind.
@@ -26,6 +27,8 @@ func (k VKind) String() string {
 		return "Component"
 	case KindRaw:
 		return "Raw"
+	case KindPortal:
+		return "Portal"
 	default:
 		return "Unknown"
 	}
@@ -34,7 +37,7 @@ func (k VKind) String() string {
 // VNode is the virtual DOM node.
 type VNode struct {
 	Kind     VKind     // Node type
-	Tag      string    // Element tag name (e.g., "div")
+	Tag      string    // Element tag name (e.g., "div"), or the target selector for KindPortal
 	Props    Props     // Attributes and event handlers
 	Children []*VNode  // Child nodes
 	Key      string    // Reconciliation key
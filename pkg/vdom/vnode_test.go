@@ -12,6 +12,7 @@ func TestVKindString(t *testing.T) {
 		{KindFragment, "Fragment"},
 		{KindComponent, "Component"},
 		{KindRaw, "Raw"},
+		{KindPortal, "Portal"},
 		{VKind(255), "Unknown"},
 	}
 
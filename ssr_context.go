@@ -32,6 +32,8 @@ type ssrContext struct {
 	redirectURL  string
 	redirectCode int
 	redirected   bool
+
+	client *server.Client
 }
 
 func newSSRContext(w http.ResponseWriter, r *http.Request, params map[string]string, cfg Config, logger *slog.Logger, policy *server.CookiePolicy) *ssrContext {
@@ -222,6 +224,23 @@ func (c *ssrContext) StormBudget() vango.StormBudgetChecker { return nil }
 // Render mode (0 = ModeNormal for SSR)
 func (c *ssrContext) Mode() int { return 0 }
 
+// Client environment (from request headers for SSR)
+func (c *ssrContext) Client() *server.Client {
+	if c.client == nil {
+		c.client = server.NewClient(server.ClientEnvFromRequest(c.request))
+	}
+	return c.client
+}
+
+// Client hooks (no connection during SSR)
+func (c *ssrContext) CallHook(hid, method string, args map[string]any) (any, error) {
+	return nil, server.ErrNoConnection
+}
+
+func (c *ssrContext) CallHookAsync(hid, method string, args map[string]any, fn func(result any, err error)) {
+	fn(nil, server.ErrNoConnection)
+}
+
 // Asset resolution
 func (c *ssrContext) Asset(source string) string {
 	// For SSR, just prefix with static prefix
@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/vango-go/vango/pkg/server"
 )
 
 func newTestSSRContext(t *testing.T, cfg Config, req *http.Request) (*ssrContext, *httptest.ResponseRecorder) {
@@ -665,3 +667,27 @@ func TestSSRContext_Param_ReturnsRouteParam(t *testing.T) {
 		t.Errorf("Param(\"missing\") = %q, want empty", got)
 	}
 }
+
+func TestSSRContext_Client_FromHeaders(t *testing.T) {
+	cfg := DefaultConfig()
+	app := New(cfg)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
+	req.Header.Set("Sec-CH-Prefers-Color-Scheme", "dark")
+	rr := httptest.NewRecorder()
+	ctx := newSSRContext(rr, req, nil, cfg, slog.Default(), app.server.CookiePolicy())
+
+	client := ctx.Client()
+	if got := client.Locale(); got != "pt-BR" {
+		t.Errorf("Locale() = %q, want %q", got, "pt-BR")
+	}
+	if got := client.ColorScheme(); got != server.ColorSchemeDark {
+		t.Errorf("ColorScheme() = %q, want dark", got)
+	}
+	if got := client.Viewport(); got != server.DefaultViewport {
+		t.Errorf("Viewport() = %+v, want %+v", got, server.DefaultViewport)
+	}
+	if ctx.Client() != client {
+		t.Error("Client() should be cached per request")
+	}
+}
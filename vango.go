@@ -95,6 +95,43 @@ var WithNavigateParams = server.WithNavigateParams
 // WithoutScroll disables scrolling to top after navigation.
 var WithoutScroll = server.WithoutScroll
 
+// =============================================================================
+// Client hook calls (re-export from server)
+// =============================================================================
+
+// DOMRect is an element's bounding client rect; see ElementRect.
+type DOMRect = server.DOMRect
+
+// ScrollPosition is an element's scroll offset and size; see ElementScroll.
+type ScrollPosition = server.ScrollPosition
+
+// TextSelection is the selected text within an element; see ElementSelection.
+type TextSelection = server.TextSelection
+
+// HookCallError is returned by Ctx.CallHook when the client hook call fails.
+type HookCallError = server.HookCallError
+
+// ErrHookCallTimeout is returned by Ctx.CallHook when the client does not answer in time.
+var ErrHookCallTimeout = server.ErrHookCallTimeout
+
+// ElementRect returns the bounding client rect of the element at hid.
+// It blocks until the client answers; see Ctx.CallHook.
+func ElementRect(ctx Ctx, hid string) (DOMRect, error) {
+	return server.ElementRect(ctx, hid)
+}
+
+// ElementScroll returns the scroll position of the element at hid.
+// It blocks until the client answers; see Ctx.CallHook.
+func ElementScroll(ctx Ctx, hid string) (ScrollPosition, error) {
+	return server.ElementScroll(ctx, hid)
+}
+
+// ElementSelection returns the text selection within the element at hid.
+// It blocks until the client answers; see Ctx.CallHook.
+func ElementSelection(ctx Ctx, hid string) (TextSelection, error) {
+	return server.ElementSelection(ctx, hid)
+}
+
 // =============================================================================
 // Reactive primitives (re-export from pkg/vango)
 // =============================================================================
@@ -183,6 +220,7 @@ type SignalOption = corevango.SignalOption
 // Signal options
 var Transient = corevango.Transient
 var PersistKey = corevango.PersistKey
+var SyncKey = corevango.SyncKey
 
 // =============================================================================
 // Events (re-export from pkg/vango)
@@ -294,6 +332,18 @@ func KeyWithModifiers(key string, mods KeyMod, handler any) ModifiedHandler {
 	return corevango.KeyWithModifiers(key, mods, handler)
 }
 
+// OnWindow subscribes to a window event (resize, online, beforeunload, ...)
+// for the lifetime of the component.
+func OnWindow(event string, handler any, modifiers ...func(any) ModifiedHandler) {
+	corevango.OnWindow(event, handler, modifiers...)
+}
+
+// OnDocument subscribes to a document event (visibilitychange, keydown, ...)
+// for the lifetime of the component.
+func OnDocument(event string, handler any, modifiers ...func(any) ModifiedHandler) {
+	corevango.OnDocument(event, handler, modifiers...)
+}
+
 // =============================================================================
 // Hooks (re-export from pkg/features/hooks, spec-aligned types)
 // =============================================================================
@@ -360,6 +410,35 @@ func NewGlobalSignal[T any](initial T, opts ...SignalOption) *GlobalSignal[T] {
 // It embeds Signal[T] and provides direct access to all Signal methods.
 type GlobalSignal[T any] = corevango.GlobalSignal[T]
 
+// GlobalSignalBackend synchronizes keyed global signals across server instances.
+// Configure it with Config.GlobalSignalBackend.
+type GlobalSignalBackend = corevango.GlobalSignalBackend
+
+// NewMemoryGlobalSignalBackend creates an in-process GlobalSignalBackend.
+func NewMemoryGlobalSignalBackend() *corevango.MemoryGlobalSignalBackend {
+	return corevango.NewMemoryGlobalSignalBackend()
+}
+
+// =============================================================================
+// Broadcast (re-export from pkg/server)
+// =============================================================================
+
+// BroadcastTransport carries app.Server().Broadcast messages between instances.
+// Configure it with Config.BroadcastTransport.
+type BroadcastTransport = server.BroadcastTransport
+
+// Topic returns a stream of messages broadcast on topic to the current session.
+// Use it with Subscribe inside an Effect:
+//
+//	vango.CreateEffect(func() vango.Cleanup {
+//	    return vango.Subscribe(vango.Topic[Notice](ctx, "notices"), func(n Notice) {
+//	        notices.Append(n)
+//	    })
+//	})
+func Topic[T any](ctx Ctx, topic string) Stream[T] {
+	return server.Topic[T](ctx, topic)
+}
+
 // =============================================================================
 // Shared & Global Memos (re-export from pkg/vango)
 // =============================================================================
@@ -702,6 +781,15 @@ func Func(render func() *vdom.VNode) vdom.Component {
 	return vdom.Func(render)
 }
 
+// ErrorBoundary renders fallback in place of children when rendering them,
+// or running their effects, panics. reset mounts the children again.
+// A nil fallback renders the router's error page.
+//
+//	vango.ErrorBoundary(func(err error, reset func()) *vango.VNode {
+//	    return Button(OnClick(reset), Text("Try again"))
+//	}, Feed())
+var ErrorBoundary = corevango.ErrorBoundary
+
 // =============================================================================
 // Configuration (re-export from pkg/vango)
 // =============================================================================